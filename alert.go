@@ -0,0 +1,195 @@
+package influxdb
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrAlertAckNotFound is the error msg for a missing alert acknowledgement.
+const ErrAlertAckNotFound = "alert acknowledgement not found"
+
+// ops for alert errors.
+const (
+	OpFindAlerts       = "FindAlerts"
+	OpFindAlertAckByID = "FindAlertAckByID"
+	OpFindAlertAcks    = "FindAlertAcks"
+	OpCreateAlertAck   = "CreateAlertAck"
+	OpDeleteAlertAck   = "DeleteAlertAck"
+)
+
+// levels of the statuses written by the checks.
+const (
+	alertLevelOK      = "ok"
+	alertLevelCrit    = "crit"
+	alertLevelWarn    = "warn"
+	alertLevelInfo    = "info"
+	alertLevelUnknown = "unknown"
+)
+
+const alertAckNoteMaxSize = 1024
+
+// Alert is the latest status of a series of a check, as written into the
+// monitoring system bucket. An alert is firing while its level is not ok.
+type Alert struct {
+	OrgID     ID     `json:"orgID"`
+	CheckID   ID     `json:"checkID"`
+	CheckName string `json:"checkName"`
+	// Tags are the tags of the series of the status.
+	Tags    []Tag     `json:"tags"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+	// Ack is the acknowledgement of the alert, if it is acknowledged.
+	Ack *AlertAck `json:"ack,omitempty"`
+}
+
+// Firing returns whether the level of the alert is not ok.
+func (a *Alert) Firing() bool {
+	return a.Level != alertLevelOK
+}
+
+// AlertFilter represents a set of filters that restrict the returned alerts.
+type AlertFilter struct {
+	OrgID        ID
+	CheckID      *ID
+	Acknowledged *bool
+}
+
+// QueryParams converts AlertFilter fields to url query params.
+func (f AlertFilter) QueryParams() map[string][]string {
+	qp := url.Values{}
+	qp.Add("orgID", f.OrgID.String())
+
+	if f.CheckID != nil {
+		qp.Add("checkID", f.CheckID.String())
+	}
+
+	if f.Acknowledged != nil {
+		if *f.Acknowledged {
+			qp.Add("acknowledged", "true")
+		} else {
+			qp.Add("acknowledged", "false")
+		}
+	}
+
+	return qp
+}
+
+// AlertAck is the acknowledgement of an alert by a user. The notification
+// rules skip the statuses of the series of the alert at its level until the
+// acknowledgement is deleted; a change of level of the alert is notified.
+type AlertAck struct {
+	ID      ID `json:"id,omitempty"`
+	OrgID   ID `json:"orgID"`
+	CheckID ID `json:"checkID"`
+	UserID  ID `json:"userID,omitempty"`
+	// Tags are the tags of the series of the alert.
+	Tags  []Tag  `json:"tags"`
+	Level string `json:"level"`
+	Note  string `json:"note,omitempty"`
+	CRUDLog
+}
+
+// Valid returns an error if the alert acknowledgement is invalid.
+func (a *AlertAck) Valid() error {
+	if !a.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "alert acknowledgement orgID is invalid",
+		}
+	}
+	if !a.CheckID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "alert acknowledgement checkID is invalid",
+		}
+	}
+	switch a.Level {
+	case alertLevelCrit, alertLevelWarn, alertLevelInfo, alertLevelUnknown:
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "alert acknowledgement level must be one of crit, warn, info or unknown",
+		}
+	}
+	for _, t := range a.Tags {
+		if err := t.Valid(); err != nil {
+			return err
+		}
+	}
+	if len(a.Note) > alertAckNoteMaxSize {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "alert acknowledgement note is too long",
+		}
+	}
+	return nil
+}
+
+// Acknowledges returns whether the acknowledgement is the one of the alert.
+func (a *AlertAck) Acknowledges(alert *Alert) bool {
+	return a.CheckID == alert.CheckID && a.Level == alert.Level && SeriesKey(a.Tags) == SeriesKey(alert.Tags)
+}
+
+// SeriesKey returns a key identifying the series with the tags, whatever their order.
+func SeriesKey(tags []Tag) string {
+	ts := make([]string, len(tags))
+	for i, t := range tags {
+		ts[i] = t.Key + "=" + t.Value
+	}
+	sort.Strings(ts)
+	return strings.Join(ts, ",")
+}
+
+// AlertAckFilter represents a set of filters that restrict the returned alert acknowledgements.
+type AlertAckFilter struct {
+	ID      *ID
+	OrgID   *ID
+	CheckID *ID
+}
+
+// QueryParams converts AlertAckFilter fields to url query params.
+func (f AlertAckFilter) QueryParams() map[string][]string {
+	qp := url.Values{}
+	if f.ID != nil {
+		qp.Add("id", f.ID.String())
+	}
+
+	if f.OrgID != nil {
+		qp.Add("orgID", f.OrgID.String())
+	}
+
+	if f.CheckID != nil {
+		qp.Add("checkID", f.CheckID.String())
+	}
+
+	return qp
+}
+
+// AlertAckService manages the acknowledgements of the alerts.
+type AlertAckService interface {
+	// FindAlertAckByID returns a single alert acknowledgement by ID.
+	FindAlertAckByID(ctx context.Context, id ID) (*AlertAck, error)
+
+	// FindAlertAcks returns a list of alert acknowledgements that match filter and the total count of matching acknowledgements.
+	// Additional options provide pagination & sorting.
+	FindAlertAcks(ctx context.Context, filter AlertAckFilter, opt ...FindOptions) ([]*AlertAck, int, error)
+
+	// CreateAlertAck acknowledges an alert and sets a.ID with the new identifier.
+	// The notification rules of the organization are updated to skip the statuses of the alert.
+	CreateAlertAck(ctx context.Context, a *AlertAck) error
+
+	// DeleteAlertAck removes an alert acknowledgement by ID.
+	DeleteAlertAck(ctx context.Context, id ID) error
+}
+
+// AlertService finds the alerts of the checks and manages their acknowledgements.
+type AlertService interface {
+	AlertAckService
+
+	// FindAlerts returns the firing alerts that match filter, with their acknowledgements.
+	FindAlerts(ctx context.Context, filter AlertFilter) ([]*Alert, error)
+}
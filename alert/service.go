@@ -0,0 +1,218 @@
+// Package alert finds the alerts of the checks in the statuses the checks
+// write into the monitoring system bucket.
+package alert
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+const (
+	timeColumn      = "_time"
+	valueColumn     = "_value"
+	levelColumn     = "_level"
+	checkIDColumn   = "_check_id"
+	checkNameColumn = "_check_name"
+)
+
+// nonSeriesColumns are the columns of the group key of the statuses that are
+// not tags of the series of the status.
+var nonSeriesColumns = map[string]bool{
+	"_start":        true,
+	"_stop":         true,
+	"_measurement":  true,
+	"_field":        true,
+	"_type":         true,
+	levelColumn:     true,
+	checkIDColumn:   true,
+	checkNameColumn: true,
+}
+
+var _ influxdb.AlertService = (*Service)(nil)
+
+// Service finds the alerts in the monitoring system bucket of the
+// organizations and manages their acknowledgements with the AlertAckService.
+type Service struct {
+	influxdb.AlertAckService
+
+	log           *zap.Logger
+	bucketService influxdb.BucketService
+	qs            query.QueryService
+}
+
+// NewService creates a new alert service.
+func NewService(log *zap.Logger, bs influxdb.BucketService, acks influxdb.AlertAckService, qs query.QueryService) *Service {
+	return &Service{
+		AlertAckService: acks,
+		log:             log,
+		bucketService:   bs,
+		qs:              qs,
+	}
+}
+
+// FindAlerts returns the firing alerts that match filter, with their acknowledgements.
+// The alert of a series is its latest status within the retention of the
+// monitoring system bucket.
+func (s *Service) FindAlerts(ctx context.Context, filter influxdb.AlertFilter) ([]*influxdb.Alert, error) {
+	sb, err := s.bucketService.FindBucketByName(ctx, filter.OrgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	filterPart := ""
+	if filter.CheckID != nil {
+		filterPart = fmt.Sprintf(` and r._check_id == %q`, filter.CheckID.String())
+	}
+
+	// the statuses will be stored for 7 days in the system bucket.
+	alertsScript := fmt.Sprintf(`from(bucketID: %q)
+	  |> range(start: -7d)
+	  |> filter(fn: (r) => r._measurement == "statuses" and r._field == "_message"%s)
+	  |> last()
+	  `, sb.ID.String(), filterPart)
+
+	// At this point we are behind authorization
+	// so we are faking a read only permission to the org's system bucket
+	monitoringSystemBucketID := sb.ID
+	auth := &influxdb.Authorization{
+		Status: influxdb.Active,
+		ID:     sb.ID,
+		OrgID:  filter.OrgID,
+		Permissions: []influxdb.Permission{
+			{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &filter.OrgID,
+					ID:    &monitoringSystemBucketID,
+				},
+			},
+		},
+	}
+	request := &query.Request{Authorization: auth, OrganizationID: filter.OrgID, Compiler: lang.FluxCompiler{Query: alertsScript}}
+
+	ittr, err := s.qs.Query(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer ittr.Release()
+
+	re := &alertReader{
+		orgID:  filter.OrgID,
+		alerts: make(map[string]*influxdb.Alert),
+		log:    s.log.With(zap.String("component", "alert-reader")),
+	}
+	for ittr.More() {
+		if err := ittr.Next().Tables().Do(re.readTable); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ittr.Err(); err != nil {
+		return nil, fmt.Errorf("unexpected internal error while decoding alert response: %v", err)
+	}
+
+	acks, _, err := s.FindAlertAcks(ctx, influxdb.AlertAckFilter{
+		OrgID:   &filter.OrgID,
+		CheckID: filter.CheckID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*influxdb.Alert, 0, len(re.alerts))
+	for _, a := range re.alerts {
+		if !a.Firing() {
+			continue
+		}
+		for _, ack := range acks {
+			if ack.Acknowledges(a) {
+				a.Ack = ack
+				break
+			}
+		}
+		if filter.Acknowledged != nil && *filter.Acknowledged != (a.Ack != nil) {
+			continue
+		}
+		alerts = append(alerts, a)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].CheckName != alerts[j].CheckName {
+			return alerts[i].CheckName < alerts[j].CheckName
+		}
+		return influxdb.SeriesKey(alerts[i].Tags) < influxdb.SeriesKey(alerts[j].Tags)
+	})
+
+	return alerts, nil
+}
+
+// alertReader keeps the latest status of each series of each check. The level
+// of a status is a tag, so the statuses of a series are read from a table per level.
+type alertReader struct {
+	orgID  influxdb.ID
+	alerts map[string]*influxdb.Alert
+	log    *zap.Logger
+}
+
+func (re *alertReader) readTable(tbl flux.Table) error {
+	return tbl.Do(re.readAlerts)
+}
+
+func (re *alertReader) readAlerts(cr flux.ColReader) error {
+	for i := 0; i < cr.Len(); i++ {
+		a := &influxdb.Alert{
+			OrgID: re.orgID,
+			Tags:  []influxdb.Tag{},
+		}
+		for j, col := range cr.Cols() {
+			switch col.Label {
+			case timeColumn:
+				a.Time = time.Unix(0, cr.Times(j).Value(i)).UTC()
+			case valueColumn:
+				a.Message = cr.Strings(j).ValueString(i)
+			case levelColumn:
+				a.Level = cr.Strings(j).ValueString(i)
+			case checkNameColumn:
+				a.CheckName = cr.Strings(j).ValueString(i)
+			case checkIDColumn:
+				id, err := influxdb.IDFromString(cr.Strings(j).ValueString(i))
+				if err != nil {
+					re.log.Info("Failed to parse checkID", zap.Error(err))
+					continue
+				}
+				a.CheckID = *id
+			default:
+				if !nonSeriesColumns[col.Label] && col.Type == flux.TString && cr.Key().HasCol(col.Label) {
+					a.Tags = append(a.Tags, influxdb.Tag{
+						Key:   col.Label,
+						Value: cr.Strings(j).ValueString(i),
+					})
+				}
+			}
+		}
+
+		// if we dont have a full enough data set we skip the status.
+		if !a.CheckID.Valid() || a.Level == "" {
+			continue
+		}
+
+		sort.Slice(a.Tags, func(i, j int) bool {
+			return a.Tags[i].Key < a.Tags[j].Key
+		})
+		key := a.CheckID.String() + "," + influxdb.SeriesKey(a.Tags)
+		if prev, ok := re.alerts[key]; !ok || prev.Time.Before(a.Time) {
+			re.alerts[key] = a
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package alert_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/alert"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	qmock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func statusTable(checkID, level, host string, t time.Time, msg string) *executetest.Table {
+	return &executetest.Table{
+		KeyCols: []string{"_check_id", "_check_name", "_field", "_level", "_measurement", "host"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_check_id", Type: flux.TString},
+			{Label: "_check_name", Type: flux.TString},
+			{Label: "_field", Type: flux.TString},
+			{Label: "_level", Type: flux.TString},
+			{Label: "_measurement", Type: flux.TString},
+			{Label: "host", Type: flux.TString},
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{checkID, "cpu check", "_message", level, "statuses", host, values.ConvertTime(t), msg},
+		},
+	}
+}
+
+func TestService_FindAlerts(t *testing.T) {
+	now := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	checkID := "020f755c3c082000"
+	id := influxdb.ID(0x020f755c3c082000)
+
+	tests := []struct {
+		name   string
+		filter influxdb.AlertFilter
+		acks   []*influxdb.AlertAck
+		tables []*executetest.Table
+		want   []*influxdb.Alert
+		query  string
+	}{
+		{
+			name:   "latest status of each series",
+			filter: influxdb.AlertFilter{OrgID: 1},
+			tables: []*executetest.Table{
+				statusTable(checkID, "crit", "db1", now.Add(-time.Minute), "db1 is down"),
+				statusTable(checkID, "ok", "db1", now, "db1 is up"),
+				statusTable(checkID, "ok", "db2", now.Add(-time.Minute), "db2 is up"),
+				statusTable(checkID, "warn", "db2", now, "db2 is slow"),
+			},
+			want: []*influxdb.Alert{
+				{
+					OrgID:     1,
+					CheckID:   id,
+					CheckName: "cpu check",
+					Tags:      []influxdb.Tag{{Key: "host", Value: "db2"}},
+					Level:     "warn",
+					Message:   "db2 is slow",
+					Time:      now,
+				},
+			},
+			query: `r._measurement == "statuses" and r._field == "_message")`,
+		},
+		{
+			name: "unacknowledged alerts of a check",
+			filter: influxdb.AlertFilter{
+				OrgID:        1,
+				CheckID:      &id,
+				Acknowledged: boolPtr(false),
+			},
+			acks: []*influxdb.AlertAck{
+				{
+					ID:      3,
+					CheckID: id,
+					Tags:    []influxdb.Tag{{Key: "host", Value: "db1"}},
+					Level:   "crit",
+				},
+			},
+			tables: []*executetest.Table{
+				statusTable(checkID, "crit", "db1", now, "db1 is down"),
+				statusTable(checkID, "crit", "db2", now, "db2 is down"),
+			},
+			want: []*influxdb.Alert{
+				{
+					OrgID:     1,
+					CheckID:   id,
+					CheckName: "cpu check",
+					Tags:      []influxdb.Tag{{Key: "host", Value: "db2"}},
+					Level:     "crit",
+					Message:   "db2 is down",
+					Time:      now,
+				},
+			},
+			query: `r._check_id == "020f755c3c082000")`,
+		},
+		{
+			name:   "acknowledged alerts",
+			filter: influxdb.AlertFilter{OrgID: 1, Acknowledged: boolPtr(true)},
+			acks: []*influxdb.AlertAck{
+				{
+					ID:      3,
+					CheckID: id,
+					Tags:    []influxdb.Tag{{Key: "host", Value: "db1"}},
+					Level:   "warn",
+				},
+			},
+			tables: []*executetest.Table{
+				statusTable(checkID, "crit", "db1", now, "db1 is down"),
+			},
+			want: []*influxdb.Alert{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := mock.NewBucketService()
+			bs.FindBucketByNameFn = func(_ context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error) {
+				if name != influxdb.MonitoringSystemBucketName {
+					t.Fatalf("unexpected bucket %q", name)
+				}
+				return &influxdb.Bucket{ID: 2, OrgID: orgID, Name: name}, nil
+			}
+
+			as := mock.NewAlertService()
+			as.FindAlertAcksFn = func(context.Context, influxdb.AlertAckFilter, ...influxdb.FindOptions) ([]*influxdb.AlertAck, int, error) {
+				return tt.acks, len(tt.acks), nil
+			}
+
+			qs := &qmock.QueryService{
+				QueryF: func(_ context.Context, req *query.Request) (flux.ResultIterator, error) {
+					q := req.Compiler.(lang.FluxCompiler).Query
+					if !strings.Contains(q, tt.query) {
+						t.Errorf("expected query to contain %q, got:\n%s", tt.query, q)
+					}
+					if req.Authorization.Permissions[0].Action != influxdb.ReadAction {
+						t.Errorf("expected a read only authorization, got %v", req.Authorization.Permissions)
+					}
+					return flux.NewSliceResultIterator([]flux.Result{executetest.NewResult(tt.tables)}), nil
+				},
+			}
+
+			svc := alert.NewService(zaptest.NewLogger(t), bs, as, qs)
+			got, err := svc.FindAlerts(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected alerts -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -3,11 +3,15 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
 const AuthorizationKind = "authorization"
 
+// ErrAuthorizationExpired is the error message for expired authorizations.
+const ErrAuthorizationExpired = "authorization has expired"
+
 // ErrUnableToCreateToken sanitized error message for all errors when a user cannot create a token
 var ErrUnableToCreateToken = &Error{
 	Msg:  "unable to create token",
@@ -23,6 +27,8 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
+	// ExpiresAt is the time the token expires at; the token never expires if nil.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 	CRUDLog
 }
 
@@ -32,6 +38,16 @@ type AuthorizationUpdate struct {
 	Description *string `json:"description,omitempty"`
 }
 
+// AuthorizationRotation is the rotation request of an authorization.
+type AuthorizationRotation struct {
+	// GracePeriod is how long the token of the rotated authorization remains valid.
+	GracePeriod time.Duration
+	// ExpiresAt is the time the new token expires at. If nil, the new token
+	// is valid for as long as the rotated one was, or never expires if the
+	// rotated one did not.
+	ExpiresAt *time.Time
+}
+
 // Valid ensures that the authorization is valid.
 func (a *Authorization) Valid() error {
 	for _, p := range a.Permissions {
@@ -46,16 +62,86 @@ func (a *Authorization) Valid() error {
 	return nil
 }
 
-// Allowed returns true if the authorization is active and request permission
-// exists in the authorization's list of permissions.
+// Expired returns an error if the authorization is expired.
+func (a *Authorization) Expired() error {
+	return a.expiredAt(time.Now())
+}
+
+func (a *Authorization) expiredAt(now time.Time) error {
+	if a.ExpiresAt != nil && !now.Before(*a.ExpiresAt) {
+		return &Error{
+			Code: EForbidden,
+			Msg:  ErrAuthorizationExpired,
+		}
+	}
+	return nil
+}
+
+// Rotate returns the authorization replacing a, with its permissions and the
+// expiry of the rotation, and sets the expiry of a to the end of the grace
+// period of the rotation, unless a expires before. The token and ID of the new
+// authorization are left to be set by the service storing it.
+func (a *Authorization) Rotate(r AuthorizationRotation, now time.Time) (*Authorization, error) {
+	if err := a.expiredAt(now); err != nil {
+		return nil, err
+	}
+	if r.GracePeriod < 0 {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  "rotation grace period must not be negative",
+		}
+	}
+
+	expiresAt := r.ExpiresAt
+	if expiresAt == nil && a.ExpiresAt != nil {
+		// the lifetime is unknown without a creation time; the expiry is kept.
+		t := *a.ExpiresAt
+		if !a.CreatedAt.IsZero() {
+			t = now.Add(a.ExpiresAt.Sub(a.CreatedAt))
+		}
+		expiresAt = &t
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  "rotated authorization must expire in the future",
+		}
+	}
+
+	rotated := &Authorization{
+		Status:      a.Status,
+		Description: a.Description,
+		OrgID:       a.OrgID,
+		UserID:      a.UserID,
+		Permissions: append([]Permission(nil), a.Permissions...),
+		ExpiresAt:   expiresAt,
+	}
+
+	graceEnd := now.Add(r.GracePeriod)
+	if a.ExpiresAt == nil || graceEnd.Before(*a.ExpiresAt) {
+		a.ExpiresAt = &graceEnd
+	}
+	return rotated, nil
+}
+
+// Allowed returns true if the authorization is active, unexpired and request
+// permission exists in the authorization's list of permissions.
 func (a *Authorization) Allowed(p Permission) bool {
 	if !a.IsActive() {
 		return false
 	}
+	if err := a.Expired(); err != nil {
+		return false
+	}
 
 	return PermissionAllowed(p, a.Permissions)
 }
 
+// PermissionSet returns the permissions of the authorization.
+func (a *Authorization) PermissionSet() []Permission {
+	return a.Permissions
+}
+
 // IsActive is a stub for idpe.
 func IsActive(a *Authorization) bool {
 	return a.IsActive()
@@ -85,6 +171,7 @@ const (
 	OpCreateAuthorization      = "CreateAuthorization"
 	OpUpdateAuthorization      = "UpdateAuthorization"
 	OpDeleteAuthorization      = "DeleteAuthorization"
+	OpRotateAuthorization      = "RotateAuthorization"
 )
 
 // AuthorizationService represents a service for managing authorization data.
@@ -107,6 +194,11 @@ type AuthorizationService interface {
 
 	// Removes a authorization by token.
 	DeleteAuthorization(ctx context.Context, id ID) error
+
+	// RotateAuthorization atomically creates an authorization with the
+	// permissions of the authorization and a new token, and expires the
+	// authorization at the end of the grace period of the rotation.
+	RotateAuthorization(ctx context.Context, id ID, r AuthorizationRotation) (*Authorization, error)
 }
 
 // AuthorizationFilter represents a set of filter that restrict the returned results.
@@ -0,0 +1,34 @@
+package influxdb
+
+import "context"
+
+// ErrAuthenticationFailed is returned when none of the authenticators recognizes the credentials.
+var ErrAuthenticationFailed = &Error{
+	Code: EUnauthorized,
+	Msg:  "your username or password is incorrect",
+}
+
+// Authenticator authenticates users by their username and password.
+type Authenticator interface {
+	// Authenticate returns the user with the username if the password is its.
+	// Credentials that do not match return errors.
+	Authenticate(ctx context.Context, username, password string) (*User, error)
+}
+
+// AuthenticatorChain is an Authenticator trying each of its authenticators
+// in turn. The user of the first authenticator recognizing the credentials
+// is returned; the failure of one authenticator, for instance an unavailable
+// directory, does not prevent the next ones from being tried.
+type AuthenticatorChain []Authenticator
+
+var _ Authenticator = AuthenticatorChain(nil)
+
+// Authenticate returns the user of the first authenticator recognizing the credentials.
+func (c AuthenticatorChain) Authenticate(ctx context.Context, username, password string) (*User, error) {
+	for _, a := range c {
+		if u, err := a.Authenticate(ctx, username, password); err == nil {
+			return u, nil
+		}
+	}
+	return nil, ErrAuthenticationFailed
+}
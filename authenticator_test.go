@@ -0,0 +1,56 @@
+package influxdb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+type authenticatorFunc func(ctx context.Context, username, password string) (*influxdb.User, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, username, password string) (*influxdb.User, error) {
+	return f(ctx, username, password)
+}
+
+func TestAuthenticatorChain(t *testing.T) {
+	authenticator := func(username, password string, err error) influxdb.Authenticator {
+		return authenticatorFunc(func(_ context.Context, u, p string) (*influxdb.User, error) {
+			if err != nil {
+				return nil, err
+			}
+			if u != username || p != password {
+				return nil, &influxdb.Error{Code: influxdb.EForbidden}
+			}
+			return &influxdb.User{Name: username}, nil
+		})
+	}
+	chain := influxdb.AuthenticatorChain{
+		authenticator("local", "secret1", nil),
+		authenticator("", "", &influxdb.Error{Code: influxdb.EUnavailable}),
+		authenticator("directory", "secret2", nil),
+	}
+
+	tests := []struct {
+		username string
+		password string
+		wantErr  bool
+	}{
+		{username: "local", password: "secret1"},
+		{username: "directory", password: "secret2"},
+		{username: "directory", password: "secret1", wantErr: true},
+		{username: "unknown", password: "secret1", wantErr: true},
+	}
+	for _, tt := range tests {
+		u, err := chain.Authenticate(context.Background(), tt.username, tt.password)
+		if tt.wantErr {
+			if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("%s: expected unauthorized error, got %v", tt.username, err)
+			}
+			continue
+		}
+		if err != nil || u.Name != tt.username {
+			t.Errorf("%s: unexpected user %v, error %v", tt.username, u, err)
+		}
+	}
+}
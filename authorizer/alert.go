@@ -0,0 +1,116 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// AlertService wraps a influxdb.AlertService and authorizes actions
+// against it appropriately.
+// Alerts are the statuses of the checks of an organization, so they are
+// authorized against its checks. Acknowledgements mute the notification rules
+// of an organization, so they are authorized against its notification rules.
+type AlertService struct {
+	s influxdb.AlertService
+}
+
+// NewAlertService constructs an instance of an authorizing alert service.
+func NewAlertService(s influxdb.AlertService) *AlertService {
+	return &AlertService{
+		s: s,
+	}
+}
+
+func authorizeAlertAck(ctx context.Context, a influxdb.Action, orgID influxdb.ID) error {
+	p, err := influxdb.NewPermission(a, influxdb.NotificationRuleResourceType, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAlerts checks to see if the authorizer on context has read access to the checks of the organization.
+func (s *AlertService) FindAlerts(ctx context.Context, filter influxdb.AlertFilter) ([]*influxdb.Alert, error) {
+	p, err := influxdb.NewPermission(influxdb.ReadAction, influxdb.ChecksResourceType, filter.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindAlerts(ctx, filter)
+}
+
+// FindAlertAckByID checks to see if the authorizer on context has read access to the alert acknowledgement provided.
+func (s *AlertService) FindAlertAckByID(ctx context.Context, id influxdb.ID) (*influxdb.AlertAck, error) {
+	a, err := s.s.FindAlertAckByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeAlertAck(ctx, influxdb.ReadAction, a.OrgID); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// FindAlertAcks retrieves all alert acknowledgements that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *AlertService) FindAlertAcks(ctx context.Context, filter influxdb.AlertAckFilter, opt ...influxdb.FindOptions) ([]*influxdb.AlertAck, int, error) {
+	// TODO: we'll likely want to push this operation into the database since fetching the whole list of data will likely be expensive.
+	as, _, err := s.s.FindAlertAcks(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	acks := as[:0]
+	for _, a := range as {
+		err := authorizeAlertAck(ctx, influxdb.ReadAction, a.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		acks = append(acks, a)
+	}
+
+	return acks, len(acks), nil
+}
+
+// CreateAlertAck checks to see if the authorizer on context has write access to the notification rules of the organization.
+func (s *AlertService) CreateAlertAck(ctx context.Context, a *influxdb.AlertAck) error {
+	if err := authorizeAlertAck(ctx, influxdb.WriteAction, a.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.CreateAlertAck(ctx, a)
+}
+
+// DeleteAlertAck checks to see if the authorizer on context has write access to the alert acknowledgement provided.
+func (s *AlertService) DeleteAlertAck(ctx context.Context, id influxdb.ID) error {
+	a, err := s.s.FindAlertAckByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeAlertAck(ctx, influxdb.WriteAction, a.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteAlertAck(ctx, id)
+}
@@ -131,7 +131,7 @@ func (s *AuthorizationService) CreateAuthorization(ctx context.Context, a *influ
 // VerifyPermission ensures that an authorization is allowed all of the appropriate permissions.
 func VerifyPermissions(ctx context.Context, ps []influxdb.Permission) error {
 	for _, p := range ps {
-		if err := IsAllowed(ctx, p); err != nil {
+		if err := IsAllowedOnSeries(ctx, p); err != nil {
 			return &influxdb.Error{
 				Err:  err,
 				Msg:  fmt.Sprintf("permission %s is not allowed", p),
@@ -157,6 +157,21 @@ func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id influ
 	return s.s.UpdateAuthorization(ctx, id, upd)
 }
 
+// RotateAuthorization checks to see if the authorizer on context has write access to the authorization provided.
+// The new authorization has the permissions of the rotated one, which are not checked again.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id influxdb.ID, r influxdb.AuthorizationRotation) (*influxdb.Authorization, error) {
+	a, err := s.s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteAuthorization(ctx, a.UserID); err != nil {
+		return nil, err
+	}
+
+	return s.s.RotateAuthorization(ctx, id, r)
+}
+
 // DeleteAuthorization checks to see if the authorizer on context has write access to the authorization provided.
 func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id influxdb.ID) error {
 	a, err := s.s.FindAuthorizationByID(ctx, id)
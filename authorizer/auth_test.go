@@ -270,3 +270,57 @@ func TestAuthorizationService_WriteAuthorization(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyPermissions(t *testing.T) {
+	bucket := influxdb.Permission{
+		Action: influxdb.ReadAction,
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+			ID:    influxdbtesting.IDPtr(2),
+		},
+	}
+	scoped := func(p influxdb.Permission, measurements ...string) influxdb.Permission {
+		p.Series = &influxdb.SeriesScope{Measurements: measurements}
+		return p
+	}
+
+	tests := []struct {
+		name        string
+		authorizer  []influxdb.Permission
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name:        "unscoped authorizer is allowed scoped permissions",
+			authorizer:  []influxdb.Permission{bucket},
+			permissions: []influxdb.Permission{scoped(bucket, "cpu")},
+		},
+		{
+			name:        "scoped authorizer is allowed narrower scoped permissions",
+			authorizer:  []influxdb.Permission{scoped(bucket, "cpu", "mem")},
+			permissions: []influxdb.Permission{scoped(bucket, "cpu")},
+		},
+		{
+			name:        "scoped authorizer is not allowed wider scoped permissions",
+			authorizer:  []influxdb.Permission{scoped(bucket, "cpu")},
+			permissions: []influxdb.Permission{scoped(bucket, "cpu", "mem")},
+			wantErr:     true,
+		},
+		{
+			name:        "scoped authorizer is not allowed unscoped permissions",
+			authorizer:  []influxdb.Permission{scoped(bucket, "cpu")},
+			permissions: []influxdb.Permission{bucket},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.authorizer})
+			if err := authorizer.VerifyPermissions(ctx, tt.permissions); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyPermissions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
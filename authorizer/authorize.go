@@ -25,3 +25,27 @@ func IsAllowed(ctx context.Context, p influxdb.Permission) error {
 
 	return nil
 }
+
+// IsAllowedOnSeries checks to see if an action is authorized on the series of
+// the permission, all the series of the buckets if the permission has no series
+// scope, rather than only on the buckets.
+func IsAllowedOnSeries(ctx context.Context, p influxdb.Permission) error {
+	if err := IsAllowed(ctx, p); err != nil {
+		return err
+	}
+	if p.Series != nil || p.Resource.Type != influxdb.BucketsResourceType {
+		return nil
+	}
+
+	a, err := influxdbcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	if len(influxdb.SeriesScopes(a, p)) > 0 {
+		return &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  fmt.Sprintf("%s is unauthorized on all the series", p),
+		}
+	}
+	return nil
+}
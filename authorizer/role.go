@@ -0,0 +1,205 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// RoleService wraps a influxdb.RoleService and authorizes actions
+// against it appropriately.
+// Roles are authorized against their organization: its members can read
+// them, and those allowed to write it can manage and assign them, provided
+// they are allowed the permissions of the roles themselves.
+type RoleService struct {
+	s influxdb.RoleService
+}
+
+// NewRoleService constructs an instance of an authorizing role service.
+func NewRoleService(s influxdb.RoleService) *RoleService {
+	return &RoleService{
+		s: s,
+	}
+}
+
+// FindRoleByID checks to see if the authorizer on context has read access to the organization of the role.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadOrg(ctx, r.OrgID); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindRoles retrieves all roles that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	// TODO: we'll likely want to push this operation into the database since fetching the whole list of data will likely be expensive.
+	rs, _, err := s.s.FindRoles(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	roles := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadOrg(ctx, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		roles = append(roles, r)
+	}
+
+	return roles, len(roles), nil
+}
+
+// CreateRole checks to see if the authorizer on context has write access to the organization of the role and is allowed its permissions.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	if err := authorizeWriteOrg(ctx, r.OrgID); err != nil {
+		return err
+	}
+
+	if err := VerifyPermissions(ctx, r.Permissions); err != nil {
+		return err
+	}
+
+	return s.s.CreateRole(ctx, r)
+}
+
+// UpdateRole checks to see if the authorizer on context has write access to the organization of the role and is allowed its new permissions.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteOrg(ctx, r.OrgID); err != nil {
+		return nil, err
+	}
+
+	if err := VerifyPermissions(ctx, upd.Permissions); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateRole(ctx, id, upd)
+}
+
+// DeleteRole checks to see if the authorizer on context has write access to the organization of the role.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteOrg(ctx, r.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteRole(ctx, id)
+}
+
+// FindRoleAssignments retrieves all role assignments that match the provided filter and then filters the list down to the ones of the roles that are authorized.
+func (s *RoleService) FindRoleAssignments(ctx context.Context, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+	as, _, err := s.s.FindRoleAssignments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orgs := make(map[influxdb.ID]influxdb.ID)
+	assignments := as[:0]
+	for _, a := range as {
+		orgID, ok := orgs[a.RoleID]
+		if !ok {
+			r, err := s.s.FindRoleByID(ctx, a.RoleID)
+			if err != nil {
+				return nil, 0, err
+			}
+			orgID = r.OrgID
+			orgs[a.RoleID] = orgID
+		}
+
+		err := authorizeReadOrg(ctx, orgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		assignments = append(assignments, a)
+	}
+
+	return assignments, len(assignments), nil
+}
+
+// CreateRoleAssignment checks to see if the authorizer on context has write access to the organization of the role and is allowed its permissions.
+func (s *RoleService) CreateRoleAssignment(ctx context.Context, a *influxdb.RoleAssignment) error {
+	r, err := s.s.FindRoleByID(ctx, a.RoleID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteOrg(ctx, r.OrgID); err != nil {
+		return err
+	}
+
+	if err := VerifyPermissions(ctx, r.Permissions); err != nil {
+		return err
+	}
+
+	return s.s.CreateRoleAssignment(ctx, a)
+}
+
+// DeleteRoleAssignment checks to see if the authorizer on context has write access to the organization of the role.
+func (s *RoleService) DeleteRoleAssignment(ctx context.Context, roleID, userID influxdb.ID) error {
+	r, err := s.s.FindRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteOrg(ctx, r.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteRoleAssignment(ctx, roleID, userID)
+}
+
+// WithRolePermissions returns a copy of the session a also allowed the
+// permissions of the roles assigned to its user. Other authorizers, such as
+// authorizations, are returned as is: a token is only allowed the permissions
+// it was created with, so that it keeps the least privilege and the series
+// scopes of its permissions.
+func WithRolePermissions(ctx context.Context, rs influxdb.RoleService, a influxdb.Authorizer) (influxdb.Authorizer, error) {
+	sess, ok := a.(*influxdb.Session)
+	if !ok || !sess.UserID.Valid() {
+		return a, nil
+	}
+
+	roles, _, err := rs.FindRoles(ctx, influxdb.RoleFilter{UserID: &sess.UserID})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return a, nil
+	}
+
+	cp := *sess
+	cp.Permissions = append([]influxdb.Permission(nil), sess.Permissions...)
+	for _, r := range roles {
+		cp.Permissions = append(cp.Permissions, r.Permissions...)
+	}
+	return &cp, nil
+}
@@ -0,0 +1,173 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRoleService_CreateRoleAssignment(t *testing.T) {
+	type args struct {
+		permissions []influxdb.Permission
+	}
+	type wants struct {
+		err error
+	}
+
+	writeOrg := influxdb.Permission{
+		Action: "write",
+		Resource: influxdb.Resource{
+			Type: influxdb.OrgsResourceType,
+			ID:   influxdbtesting.IDPtr(1),
+		},
+	}
+	writeDashboards := influxdb.Permission{
+		Action: "write",
+		Resource: influxdb.Resource{
+			Type:  influxdb.DashboardsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+		},
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to assign role",
+			args: args{
+				permissions: []influxdb.Permission{writeOrg, writeDashboards},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to assign role without write access to its organization",
+			args: args{
+				permissions: []influxdb.Permission{writeDashboards},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/0000000000000001 is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+		{
+			name: "forbidden to assign role with permissions it is not allowed",
+			args: args{
+				permissions: []influxdb.Permission{writeOrg},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "permission write:orgs/0000000000000001/dashboards is not allowed",
+					Code: influxdb.EForbidden,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewRoleService()
+			m.FindRoleByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+				return &influxdb.Role{
+					ID:          id,
+					OrgID:       1,
+					Name:        "dashboard-editor",
+					Permissions: []influxdb.Permission{writeDashboards},
+				}, nil
+			}
+			s := authorizer.NewRoleService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
+
+			err := s.CreateRoleAssignment(ctx, &influxdb.RoleAssignment{RoleID: 2, UserID: 3})
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestWithRolePermissions(t *testing.T) {
+	writeDashboards := influxdb.Permission{
+		Action: "write",
+		Resource: influxdb.Resource{
+			Type:  influxdb.DashboardsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+		},
+	}
+
+	m := mock.NewRoleService()
+	m.FindRolesFn = func(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+		if filter.UserID == nil || *filter.UserID != 2 {
+			return nil, 0, nil
+		}
+		return []*influxdb.Role{{ID: 3, OrgID: 1, Name: "dashboard-editor", Permissions: []influxdb.Permission{writeDashboards}}}, 1, nil
+	}
+
+	sess := &influxdb.Session{UserID: 2, ExpiresAt: time.Now().Add(time.Hour)}
+	a, err := authorizer.WithRolePermissions(context.Background(), m, sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Allowed(writeDashboards) {
+		t.Error("expected session to be allowed the permissions of the roles of its user")
+	}
+	if sess.Allowed(writeDashboards) {
+		t.Error("expected the session to be copied rather than modified")
+	}
+
+	other := &influxdb.Session{UserID: 4, ExpiresAt: time.Now().Add(time.Hour)}
+	if a, err = authorizer.WithRolePermissions(context.Background(), m, other); err != nil {
+		t.Fatal(err)
+	}
+	if a.Allowed(writeDashboards) {
+		t.Error("expected session not to be allowed the permissions of roles not assigned to its user")
+	}
+}
+
+func TestWithRolePermissions_Authorization(t *testing.T) {
+	writeBuckets := influxdb.Permission{
+		Action: "write",
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(1),
+		},
+	}
+	writeBucket := influxdb.Permission{
+		Action: "write",
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			ID:    influxdbtesting.IDPtr(5),
+			OrgID: influxdbtesting.IDPtr(1),
+		},
+	}
+	scoped := writeBucket
+	scoped.Series = &influxdb.SeriesScope{Measurements: []string{"cpu"}}
+
+	m := mock.NewRoleService()
+	m.FindRolesFn = func(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+		return []*influxdb.Role{{ID: 3, OrgID: 1, Name: "bucket-writer", Permissions: []influxdb.Permission{writeBuckets}}}, 1, nil
+	}
+
+	auth := &influxdb.Authorization{ID: 4, OrgID: 1, UserID: 2, Status: influxdb.Active, Permissions: []influxdb.Permission{scoped}}
+	a, err := authorizer.WithRolePermissions(context.Background(), m, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Allowed(writeBuckets) {
+		t.Error("expected token not to be allowed the permissions of the roles of its user")
+	}
+	if scopes := influxdb.SeriesScopes(a, writeBucket); len(scopes) != 1 {
+		t.Errorf("expected token to stay scoped to the series of its permission, got %v", scopes)
+	}
+}
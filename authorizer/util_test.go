@@ -22,3 +22,7 @@ func (a *Authorizer) GetUserID() influxdb.ID {
 func (a *Authorizer) Kind() string {
 	return "mock"
 }
+
+func (a *Authorizer) PermissionSet() []influxdb.Permission {
+	return a.Permissions
+}
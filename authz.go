@@ -205,6 +205,10 @@ func (t ResourceType) Valid() (err error) {
 type Permission struct {
 	Action   Action   `json:"action"`
 	Resource Resource `json:"resource"`
+	// Series restricts the action on the data of the buckets to the series in
+	// scope; the buckets themselves remain accessible. All the series are
+	// in scope if nil.
+	Series *SeriesScope `json:"series,omitempty"`
 }
 
 // Matches returns whether or not one permission matches the other.
@@ -217,6 +221,12 @@ func (p Permission) Matches(perm Permission) bool {
 		return false
 	}
 
+	// a permission on some series only matches the permissions on fewer
+	// series, or the permission on the bucket.
+	if p.Series != nil && perm.Series != nil && !p.Series.covers(perm.Series) {
+		return false
+	}
+
 	if p.Resource.OrgID == nil && p.Resource.ID == nil {
 		return true
 	}
@@ -245,6 +255,9 @@ func (p Permission) Matches(perm Permission) bool {
 }
 
 func (p Permission) String() string {
+	if p.Series != nil {
+		return fmt.Sprintf("%s:%s[%s]", p.Action, p.Resource, p.Series)
+	}
 	return fmt.Sprintf("%s:%s", p.Action, p.Resource)
 }
 
@@ -282,6 +295,18 @@ func (p *Permission) Valid() error {
 		}
 	}
 
+	if p.Series != nil {
+		if p.Resource.Type != BucketsResourceType {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "series scope is only valid for bucket permissions",
+			}
+		}
+		if err := p.Series.Valid(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -229,6 +229,90 @@ func TestAuthorizer_PermissionAllowed(t *testing.T) {
 			},
 			allowed: false,
 		},
+		{
+			name: "series scope covering the requested series",
+			permission: platform.Permission{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+				Series: &platform.SeriesScope{
+					Measurements: []string{"cpu"},
+					Tags: []platform.TagRule{
+						{Tag: platform.Tag{Key: "host", Value: "^web"}, Operator: platform.RegexEqual},
+					},
+				},
+			},
+			permissions: []platform.Permission{
+				{
+					Action: platform.ReadAction,
+					Resource: platform.Resource{
+						Type:  platform.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(1),
+						ID:    influxdbtesting.IDPtr(1),
+					},
+					Series: &platform.SeriesScope{
+						Measurements: []string{"cpu", "mem"},
+					},
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "series scope not covering the requested series",
+			permission: platform.Permission{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+				Series: &platform.SeriesScope{
+					Measurements: []string{"disk"},
+				},
+			},
+			permissions: []platform.Permission{
+				{
+					Action: platform.ReadAction,
+					Resource: platform.Resource{
+						Type:  platform.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(1),
+						ID:    influxdbtesting.IDPtr(1),
+					},
+					Series: &platform.SeriesScope{
+						Measurements: []string{"cpu", "mem"},
+					},
+				},
+			},
+			allowed: false,
+		},
+		{
+			name: "series scope allows the bucket",
+			permission: platform.Permission{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+			},
+			permissions: []platform.Permission{
+				{
+					Action: platform.ReadAction,
+					Resource: platform.Resource{
+						Type:  platform.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(1),
+						ID:    influxdbtesting.IDPtr(1),
+					},
+					Series: &platform.SeriesScope{
+						Measurements: []string{"cpu"},
+					},
+				},
+			},
+			allowed: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -245,6 +329,7 @@ func TestPermission_Valid(t *testing.T) {
 	type fields struct {
 		Action   platform.Action
 		Resource platform.Resource
+		Series   *platform.SeriesScope
 	}
 	tests := []struct {
 		name    string
@@ -302,12 +387,71 @@ func TestPermission_Valid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid bucket permission with a series scope",
+			fields: fields{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Series: &platform.SeriesScope{
+					Measurements: []string{"cpu"},
+					Tags: []platform.TagRule{
+						{Tag: platform.Tag{Key: "host", Value: "^web"}, Operator: platform.RegexEqual},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid series scope restricting nothing",
+			fields: fields{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Series: &platform.SeriesScope{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid series scope with an invalid regular expression",
+			fields: fields{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Series: &platform.SeriesScope{
+					Tags: []platform.TagRule{
+						{Tag: platform.Tag{Key: "host", Value: "(web"}, Operator: platform.RegexEqual},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid series scope on a task permission",
+			fields: fields{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.TasksResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Series: &platform.SeriesScope{
+					Measurements: []string{"cpu"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &platform.Permission{
 				Action:   tt.fields.Action,
 				Resource: tt.fields.Resource,
+				Series:   tt.fields.Series,
 			}
 			if err := p.Valid(); (err != nil) != tt.wantErr {
 				t.Errorf("Permission.Valid() error = %v, wantErr %v", err, tt.wantErr)
@@ -367,6 +511,7 @@ func TestPermission_String(t *testing.T) {
 		Action   platform.Action
 		Resource platform.Resource
 		Name     *string
+		Series   *platform.SeriesScope
 	}
 	tests := []struct {
 		name   string
@@ -417,12 +562,30 @@ func TestPermission_String(t *testing.T) {
 			},
 			want: `write:buckets/0000000000000001`,
 		},
+		{
+			name: "valid permission with a series scope",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Series: &platform.SeriesScope{
+					Measurements: []string{"cpu", "mem"},
+					Tags: []platform.TagRule{
+						{Tag: platform.Tag{Key: "host", Value: "^web"}, Operator: platform.RegexEqual},
+					},
+				},
+			},
+			want: `read:orgs/0000000000000001/buckets[measurements=cpu,mem host=~/^web/]`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := platform.Permission{
 				Action:   tt.fields.Action,
 				Resource: tt.fields.Resource,
+				Series:   tt.fields.Series,
 			}
 			if got := p.String(); got != tt.want {
 				t.Errorf("Permission.String() = %v, want %v", got, tt.want)
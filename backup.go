@@ -0,0 +1,92 @@
+package influxdb
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BackupService represents the data backup functions of InfluxDB.
+type BackupService interface {
+	// CreateBackup creates a point-in-time snapshot of the storage engine
+	// files. TSM and index files are hard linked so the snapshot is cheap to
+	// create and does not block writes for longer than it takes to flush the
+	// cache. The returned manifest lists every file in the snapshot.
+	CreateBackup(ctx context.Context, opts BackupOptions) (*BackupManifest, error)
+
+	// FetchBackupFile writes the contents of a single file of a snapshot to w.
+	FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error
+
+	// DeleteBackup removes a snapshot once all of its files have been fetched.
+	DeleteBackup(ctx context.Context, backupID int) error
+}
+
+// BackupOptions changes how a backup is created.
+type BackupOptions struct {
+	// Incremental skips flushing the cache before the snapshot is taken.
+	// The closed WAL segments are included in the snapshot instead, so that
+	// the only TSM files it contains that were not part of the previous
+	// backup are those written by compactions since.
+	Incremental bool
+}
+
+// KVBackupService represents the metadata backup functions of InfluxDB.
+type KVBackupService interface {
+	// Backup writes a consistent copy of the metadata store to w.
+	Backup(ctx context.Context, w io.Writer) error
+}
+
+// BackupFileType describes what a file in a backup contains.
+type BackupFileType string
+
+// Types of file that can be part of a backup.
+const (
+	BackupFileTypeKV     BackupFileType = "kv"     // Copy of the metadata store.
+	BackupFileTypeTSM    BackupFileType = "tsm"    // TSM data or tombstone file.
+	BackupFileTypeIndex  BackupFileType = "index"  // TSI index file.
+	BackupFileTypeSeries BackupFileType = "series" // Series file segment or index.
+	BackupFileTypeWAL    BackupFileType = "wal"    // WAL segment.
+)
+
+// BackupFile is a single file within a backup.
+type BackupFile struct {
+	Type BackupFileType `json:"type"`
+	// Path is slash separated and relative to the root of the backup.
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// Inherited is set when the file was not copied into an incremental
+	// backup because it is identical to a file of the parent backup.
+	Inherited bool `json:"inherited,omitempty"`
+}
+
+// BackupManifest describes the contents of a backup.
+type BackupManifest struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Parent is the location of the backup that an incremental backup is
+	// based on, relative to the incremental backup. It is empty for a full
+	// backup.
+	Parent string       `json:"parent,omitempty"`
+	Files  []BackupFile `json:"files"`
+}
+
+// FilesOfType returns the files in the manifest with the provided type.
+func (m *BackupManifest) FilesOfType(typ BackupFileType) []BackupFile {
+	var files []BackupFile
+	for _, f := range m.Files {
+		if f.Type == typ {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// File returns the file in the manifest with the provided path.
+func (m *BackupManifest) File(path string) (BackupFile, bool) {
+	for _, f := range m.Files {
+		if f.Path == path {
+			return f, true
+		}
+	}
+	return BackupFile{}, false
+}
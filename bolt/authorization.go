@@ -232,41 +232,45 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 	}
 
 	return c.db.Update(func(tx *bolt.Tx) error {
-		_, pErr := c.findUserByID(ctx, tx, a.UserID)
-		if pErr != nil {
-			return platform.ErrUnableToCreateToken
-		}
+		return c.createAuthorization(ctx, tx, a, op)
+	})
+}
 
-		_, pErr = c.findOrganizationByID(ctx, tx, a.OrgID)
-		if pErr != nil {
-			return platform.ErrUnableToCreateToken
-		}
+func (c *Client) createAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.Authorization, op string) error {
+	_, pErr := c.findUserByID(ctx, tx, a.UserID)
+	if pErr != nil {
+		return platform.ErrUnableToCreateToken
+	}
 
-		if unique := c.uniqueAuthorizationToken(ctx, tx, a); !unique {
-			return platform.ErrUnableToCreateToken
-		}
+	_, pErr = c.findOrganizationByID(ctx, tx, a.OrgID)
+	if pErr != nil {
+		return platform.ErrUnableToCreateToken
+	}
 
-		if a.Token == "" {
-			token, err := c.TokenGenerator.Token()
-			if err != nil {
-				return &platform.Error{
-					Err: err,
-					Op:  op,
-				}
+	if unique := c.uniqueAuthorizationToken(ctx, tx, a); !unique {
+		return platform.ErrUnableToCreateToken
+	}
+
+	if a.Token == "" {
+		token, err := c.TokenGenerator.Token()
+		if err != nil {
+			return &platform.Error{
+				Err: err,
+				Op:  op,
 			}
-			a.Token = token
 		}
+		a.Token = token
+	}
 
-		a.ID = c.IDGenerator.ID()
+	a.ID = c.IDGenerator.ID()
 
-		pe := c.putAuthorization(ctx, tx, a)
-		if pe != nil {
-			pe.Op = op
-			return pe
-		}
+	pe := c.putAuthorization(ctx, tx, a)
+	if pe != nil {
+		pe.Op = op
+		return pe
+	}
 
-		return nil
-	})
+	return nil
 }
 
 // PutAuthorization will put a authorization without setting an ID.
@@ -453,3 +457,39 @@ func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 	}
 	return a, nil
 }
+
+// RotateAuthorization atomically creates an authorization with the permissions
+// of the authorization and a new token, and expires the authorization at the
+// end of the grace period of the rotation.
+func (c *Client) RotateAuthorization(ctx context.Context, id platform.ID, r platform.AuthorizationRotation) (*platform.Authorization, error) {
+	op := getOp(platform.OpRotateAuthorization)
+	var a *platform.Authorization
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		old, pe := c.findAuthorizationByID(ctx, tx, id)
+		if pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		var err error
+		if a, err = old.Rotate(r, c.Now()); err != nil {
+			return &platform.Error{
+				Err: err,
+				Op:  op,
+			}
+		}
+		if err := c.createAuthorization(ctx, tx, a, op); err != nil {
+			return err
+		}
+
+		if pe := c.putAuthorization(ctx, tx, old); pe != nil {
+			pe.Op = op
+			return pe
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
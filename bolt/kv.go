@@ -3,11 +3,13 @@ package bolt
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
 	bolt "github.com/coreos/bbolt"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
 	"go.uber.org/zap"
@@ -16,6 +18,9 @@ import (
 // check that *KVStore implement kv.Store interface.
 var _ (kv.Store) = (*KVStore)(nil)
 
+// check that *KVStore implement platform.KVBackupService interface.
+var _ (platform.KVBackupService) = (*KVStore)(nil)
+
 // KVStore is a kv.Store backed by boltdb.
 type KVStore struct {
 	path string
@@ -97,6 +102,17 @@ func (s *KVStore) WithDB(db *bolt.DB) {
 	s.db = db
 }
 
+// Backup writes a consistent copy of the underlying bolt database to w.
+func (s *KVStore) Backup(ctx context.Context, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
 // View opens up a view transaction against the store.
 func (s *KVStore) View(ctx context.Context, fn func(tx kv.Tx) error) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
@@ -33,13 +33,15 @@ const InfiniteRetention = 0
 
 // Bucket is a bucket. 🎉
 type Bucket struct {
-	ID                  ID            `json:"id,omitempty"`
-	OrgID               ID            `json:"orgID,omitempty"`
-	Type                BucketType    `json:"type"`
-	Name                string        `json:"name"`
-	Description         string        `json:"description"`
-	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
-	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	ID                  ID                `json:"id,omitempty"`
+	OrgID               ID                `json:"orgID,omitempty"`
+	Type                BucketType        `json:"type"`
+	Name                string            `json:"name"`
+	Description         string            `json:"description"`
+	RetentionPolicyName string            `json:"rp,omitempty"` // This to support v1 sources
+	RetentionPeriod     time.Duration     `json:"retentionPeriod"`
+	ColdTierPeriod      time.Duration     `json:"coldTierPeriod,omitempty"`
+	DownsamplePolicy    *DownsamplePolicy `json:"downsamplePolicy,omitempty"`
 	CRUDLog
 }
 
@@ -97,11 +99,14 @@ type BucketService interface {
 }
 
 // BucketUpdate represents updates to a bucket.
-// Only fields which are set are updated.
+// Only fields which are set are updated. An empty DownsamplePolicy removes
+// the policy of the bucket.
 type BucketUpdate struct {
-	Name            *string        `json:"name,omitempty"`
-	Description     *string        `json:"description,omitempty"`
-	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	Name             *string           `json:"name,omitempty"`
+	Description      *string           `json:"description,omitempty"`
+	RetentionPeriod  *time.Duration    `json:"retentionPeriod,omitempty"`
+	ColdTierPeriod   *time.Duration    `json:"coldTierPeriod,omitempty"`
+	DownsamplePolicy *DownsamplePolicy `json:"downsamplePolicy,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
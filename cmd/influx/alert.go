@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/predicate"
+	"github.com/spf13/cobra"
+)
+
+// Alert Command
+var alertCmd = &cobra.Command{
+	Use:   "alert",
+	Short: "Alert management commands",
+	Long:  "Alerts are the latest non ok statuses of the series of the checks. Acknowledged alerts are skipped by the notification rules.",
+	Run:   alertF,
+}
+
+func alertF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newAlertService(f Flags) (influxdb.AlertService, error) {
+	if f.local {
+		return nil, fmt.Errorf("local flag not supported for alert command")
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.AlertService{
+		Client: client,
+	}, nil
+}
+
+func writeAlerts(headers bool, alerts ...*influxdb.Alert) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.HideHeaders(!headers)
+	w.WriteHeaders(
+		"CheckID",
+		"Check",
+		"Tags",
+		"Level",
+		"Message",
+		"Time",
+		"AckID",
+		"Note",
+	)
+	for _, a := range alerts {
+		var ackID, note string
+		if a.Ack != nil {
+			ackID = a.Ack.ID.String()
+			note = a.Ack.Note
+		}
+		w.Write(map[string]interface{}{
+			"CheckID": a.CheckID.String(),
+			"Check":   a.CheckName,
+			"Tags":    alertTags(a.Tags),
+			"Level":   a.Level,
+			"Message": a.Message,
+			"Time":    a.Time.Format(time.RFC3339),
+			"AckID":   ackID,
+			"Note":    note,
+		})
+	}
+	w.Flush()
+}
+
+func writeAlertAcks(headers bool, acks ...*influxdb.AlertAck) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.HideHeaders(!headers)
+	w.WriteHeaders(
+		"ID",
+		"CheckID",
+		"Tags",
+		"Level",
+		"UserID",
+		"Note",
+		"Created",
+	)
+	for _, a := range acks {
+		var userID string
+		if a.UserID.Valid() {
+			userID = a.UserID.String()
+		}
+		w.Write(map[string]interface{}{
+			"ID":      a.ID.String(),
+			"CheckID": a.CheckID.String(),
+			"Tags":    alertTags(a.Tags),
+			"Level":   a.Level,
+			"UserID":  userID,
+			"Note":    a.Note,
+			"Created": a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+}
+
+func alertTags(tags []influxdb.Tag) []string {
+	ts := make([]string, 0, len(tags))
+	for _, t := range tags {
+		ts = append(ts, fmt.Sprintf("%s=%q", t.Key, t.Value))
+	}
+	return ts
+}
+
+// AlertListFlags define the List Command
+type AlertListFlags struct {
+	checkID        string
+	acknowledged   bool
+	unacknowledged bool
+	headers        bool
+	organization
+}
+
+var alertListFlags AlertListFlags
+
+func init() {
+	alertListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the firing alerts of an organization",
+		RunE:  wrapCheckSetup(alertListF),
+	}
+
+	alertListCmd.Flags().StringVarP(&alertListFlags.checkID, "check-id", "", "", "The ID of the check of the alerts")
+	alertListCmd.Flags().BoolVar(&alertListFlags.acknowledged, "acknowledged", false, "Only list the acknowledged alerts")
+	alertListCmd.Flags().BoolVar(&alertListFlags.unacknowledged, "unacknowledged", false, "Only list the unacknowledged alerts")
+	alertListCmd.Flags().BoolVar(&alertListFlags.headers, "headers", true, "To print the table headers; defaults true")
+	alertListFlags.organization.register(alertListCmd)
+
+	alertCmd.AddCommand(alertListCmd)
+}
+
+func alertListF(cmd *cobra.Command, args []string) error {
+	if err := alertListFlags.organization.validOrgFlags(); err != nil {
+		return err
+	}
+
+	if alertListFlags.acknowledged && alertListFlags.unacknowledged {
+		return fmt.Errorf("must specify at most one of acknowledged or unacknowledged")
+	}
+
+	s, err := newAlertService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize alert service client: %v", err)
+	}
+
+	orgSvc, err := newOrganizationService()
+	if err != nil {
+		return err
+	}
+
+	filter := influxdb.AlertFilter{}
+	filter.OrgID, err = alertListFlags.organization.getID(orgSvc)
+	if err != nil {
+		return err
+	}
+
+	if alertListFlags.checkID != "" {
+		id, err := influxdb.IDFromString(alertListFlags.checkID)
+		if err != nil {
+			return fmt.Errorf("failed to decode check id %q: %v", alertListFlags.checkID, err)
+		}
+		filter.CheckID = id
+	}
+
+	switch {
+	case alertListFlags.acknowledged:
+		filter.Acknowledged = &alertListFlags.acknowledged
+	case alertListFlags.unacknowledged:
+		acknowledged := false
+		filter.Acknowledged = &acknowledged
+	}
+
+	alerts, err := s.FindAlerts(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve alerts: %s", err)
+	}
+
+	writeAlerts(alertListFlags.headers, alerts...)
+
+	return nil
+}
+
+// AlertAckFlags define the Ack Command
+type AlertAckFlags struct {
+	checkID   string
+	predicate string
+	note      string
+	organization
+}
+
+var alertAckFlags AlertAckFlags
+
+func init() {
+	alertAckCmd := &cobra.Command{
+		Use:   "ack",
+		Short: "Acknowledge the firing alerts of a check",
+		RunE:  wrapCheckSetup(alertAckF),
+	}
+
+	alertAckCmd.Flags().StringVarP(&alertAckFlags.checkID, "check-id", "", "", "The ID of the check of the alerts (required)")
+	alertAckCmd.Flags().StringVarP(&alertAckFlags.predicate, "predicate", "p", "", `The tags of the acknowledged alerts, e.g. 'host="db1" and env="prod"'; defaults to all the alerts of the check`)
+	alertAckCmd.Flags().StringVarP(&alertAckFlags.note, "note", "", "", "A note about the acknowledgement")
+	alertAckCmd.MarkFlagRequired("check-id")
+	alertAckFlags.organization.register(alertAckCmd)
+
+	alertCmd.AddCommand(alertAckCmd)
+}
+
+func alertAckF(cmd *cobra.Command, args []string) error {
+	if err := alertAckFlags.organization.validOrgFlags(); err != nil {
+		return err
+	}
+
+	s, err := newAlertService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize alert service client: %v", err)
+	}
+
+	checkID, err := influxdb.IDFromString(alertAckFlags.checkID)
+	if err != nil {
+		return fmt.Errorf("failed to decode check id %q: %v", alertAckFlags.checkID, err)
+	}
+
+	var tagRules []influxdb.TagRule
+	if alertAckFlags.predicate != "" {
+		node, err := predicate.Parse(alertAckFlags.predicate)
+		if err != nil {
+			return fmt.Errorf("failed to parse predicate %q: %v", alertAckFlags.predicate, err)
+		}
+		if tagRules, err = predicate.TagRules(node); err != nil {
+			return fmt.Errorf("failed to parse predicate %q: %v", alertAckFlags.predicate, err)
+		}
+	}
+
+	orgSvc, err := newOrganizationService()
+	if err != nil {
+		return err
+	}
+
+	orgID, err := alertAckFlags.organization.getID(orgSvc)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	acknowledged := false
+	alerts, err := s.FindAlerts(ctx, influxdb.AlertFilter{
+		OrgID:        orgID,
+		CheckID:      checkID,
+		Acknowledged: &acknowledged,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve alerts: %s", err)
+	}
+
+	acks := make([]*influxdb.AlertAck, 0, len(alerts))
+	for _, a := range alerts {
+		if !hasTags(a.Tags, tagRules) {
+			continue
+		}
+		ack := &influxdb.AlertAck{
+			OrgID:   a.OrgID,
+			CheckID: a.CheckID,
+			Tags:    a.Tags,
+			Level:   a.Level,
+			Note:    alertAckFlags.note,
+		}
+		if err := s.CreateAlertAck(ctx, ack); err != nil {
+			return fmt.Errorf("failed to acknowledge alert: %v", err)
+		}
+		acks = append(acks, ack)
+	}
+
+	if len(acks) == 0 {
+		return fmt.Errorf("no unacknowledged alert of check %q matches", checkID)
+	}
+
+	writeAlertAcks(true, acks...)
+
+	return nil
+}
+
+// hasTags returns whether the tags of an alert contain all the tag rules.
+func hasTags(tags []influxdb.Tag, trs []influxdb.TagRule) bool {
+	for _, tr := range trs {
+		found := false
+		for _, t := range tags {
+			if t.Key == tr.Key && t.Value == tr.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// AlertUnackFlags define the Unack command
+type AlertUnackFlags struct {
+	id string
+}
+
+var alertUnackFlags AlertUnackFlags
+
+func init() {
+	alertUnackCmd := &cobra.Command{
+		Use:   "unack",
+		Short: "Delete the acknowledgement of an alert",
+		RunE:  wrapCheckSetup(alertUnackF),
+	}
+
+	alertUnackCmd.Flags().StringVarP(&alertUnackFlags.id, "id", "i", "", "The alert acknowledgement ID (required)")
+	alertUnackCmd.MarkFlagRequired("id")
+
+	alertCmd.AddCommand(alertUnackCmd)
+}
+
+func alertUnackF(cmd *cobra.Command, args []string) error {
+	s, err := newAlertService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize alert service client: %v", err)
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(alertUnackFlags.id); err != nil {
+		return fmt.Errorf("failed to decode alert acknowledgement id %q: %v", alertUnackFlags.id, err)
+	}
+
+	ctx := context.Background()
+	ack, err := s.FindAlertAckByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find alert acknowledgement with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteAlertAck(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete alert acknowledgement with id %q: %v", id, err)
+	}
+
+	writeAlertAcks(true, ack)
+
+	return nil
+}
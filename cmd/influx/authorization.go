@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
@@ -13,8 +15,9 @@ import (
 
 // AuthorizationCreateFlags are command line args used when creating a authorization
 type AuthorizationCreateFlags struct {
-	user string
-	org  string
+	user      string
+	org       string
+	expiresIn time.Duration
 
 	writeUserPermission bool
 	readUserPermission  bool
@@ -25,6 +28,9 @@ type AuthorizationCreateFlags struct {
 	writeBucketPermissions []string
 	readBucketPermissions  []string
 
+	measurements []string
+	tags         []string
+
 	writeTasksPermission bool
 	readTasksPermission  bool
 
@@ -64,6 +70,7 @@ func authCmd() *cobra.Command {
 		authDeleteCmd(),
 		authFindCmd(),
 		authInactiveCmd(),
+		authRotateCmd(),
 	)
 
 	return cmd
@@ -84,6 +91,7 @@ func authCreateCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&authCreateFlags.user, "user", "u", "", "The user name")
+	cmd.Flags().DurationVarP(&authCreateFlags.expiresIn, "expires-in", "", 0, "The duration after which the authorization expires, it never expires if 0")
 
 	cmd.Flags().BoolVarP(&authCreateFlags.writeUserPermission, "write-user", "", false, "Grants the permission to perform mutative actions against organization users")
 	cmd.Flags().BoolVarP(&authCreateFlags.readUserPermission, "read-user", "", false, "Grants the permission to perform read actions against organization users")
@@ -94,6 +102,9 @@ func authCreateCmd() *cobra.Command {
 	cmd.Flags().StringArrayVarP(&authCreateFlags.writeBucketPermissions, "write-bucket", "", []string{}, "The bucket id")
 	cmd.Flags().StringArrayVarP(&authCreateFlags.readBucketPermissions, "read-bucket", "", []string{}, "The bucket id")
 
+	cmd.Flags().StringArrayVarP(&authCreateFlags.measurements, "measurement", "", []string{}, "Restricts the bucket permissions to the measurement")
+	cmd.Flags().StringArrayVarP(&authCreateFlags.tags, "tag", "", []string{}, "Restricts the bucket permissions to the series with the tag, of the form key=value, key!=value, key=~/regex/ or key!~/regex/")
+
 	cmd.Flags().BoolVarP(&authCreateFlags.writeTasksPermission, "write-tasks", "", false, "Grants the permission to create tasks")
 	cmd.Flags().BoolVarP(&authCreateFlags.readTasksPermission, "read-tasks", "", false, "Grants the permission to read tasks")
 
@@ -226,11 +237,29 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	series, err := authorizationSeriesScope(authCreateFlags.measurements, authCreateFlags.tags)
+	if err != nil {
+		return err
+	}
+	for i := range permissions {
+		if permissions[i].Resource.Type == platform.BucketsResourceType {
+			permissions[i].Series = series
+		}
+	}
+
 	authorization := &platform.Authorization{
 		Permissions: permissions,
 		OrgID:       o.ID,
 	}
 
+	if authCreateFlags.expiresIn < 0 {
+		return fmt.Errorf("expires-in must not be negative")
+	}
+	if authCreateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authCreateFlags.expiresIn)
+		authorization.ExpiresAt = &expiresAt
+	}
+
 	if userName := authCreateFlags.user; userName != "" {
 		userSvc, err := newUserService()
 		if err != nil {
@@ -260,6 +289,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		"Token",
 		"Status",
 		"UserID",
+		"ExpiresAt",
 		"Permissions",
 	)
 
@@ -273,6 +303,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		"Token":       authorization.Token,
 		"Status":      authorization.Status,
 		"UserID":      authorization.UserID.String(),
+		"ExpiresAt":   formatExpiresAt(authorization.ExpiresAt),
 		"Permissions": ps,
 	})
 
@@ -379,6 +410,7 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"User",
 		"UserID",
+		"ExpiresAt",
 		"Permissions",
 	)
 
@@ -393,6 +425,7 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 			"Token":       a.Token,
 			"Status":      a.Status,
 			"UserID":      a.UserID.String(),
+			"ExpiresAt":   formatExpiresAt(a.ExpiresAt),
 			"Permissions": permissions,
 		})
 	}
@@ -612,3 +645,112 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// AuthorizationRotateFlags are command line args used when rotating an authorization
+type AuthorizationRotateFlags struct {
+	id          string
+	gracePeriod time.Duration
+	expiresIn   time.Duration
+}
+
+var authorizationRotateFlags AuthorizationRotateFlags
+
+func authRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Rotate authorization token",
+		Long:  "Creates an authorization with a new token and the same permissions; the token of the rotated authorization expires after the grace period",
+		RunE:  wrapCheckSetup(authorizationRotateF),
+	}
+
+	cmd.Flags().StringVarP(&authorizationRotateFlags.id, "id", "i", "", "The authorization ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().DurationVarP(&authorizationRotateFlags.gracePeriod, "grace-period", "", time.Hour, "The duration the token of the rotated authorization remains valid")
+	cmd.Flags().DurationVarP(&authorizationRotateFlags.expiresIn, "expires-in", "", 0, "The duration after which the new authorization expires, the lifetime of the rotated authorization if 0")
+
+	return cmd
+}
+
+func authorizationRotateF(cmd *cobra.Command, args []string) error {
+	s, err := newAuthorizationService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(authorizationRotateFlags.id); err != nil {
+		return err
+	}
+
+	if authorizationRotateFlags.expiresIn < 0 {
+		return fmt.Errorf("expires-in must not be negative")
+	}
+	r := platform.AuthorizationRotation{
+		GracePeriod: authorizationRotateFlags.gracePeriod,
+	}
+	if authorizationRotateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authorizationRotateFlags.expiresIn)
+		r.ExpiresAt = &expiresAt
+	}
+
+	a, err := s.RotateAuthorization(context.Background(), id, r)
+	if err != nil {
+		return err
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Token",
+		"Status",
+		"UserID",
+		"ExpiresAt",
+		"Permissions",
+	)
+
+	ps := []string{}
+	for _, p := range a.Permissions {
+		ps = append(ps, p.String())
+	}
+
+	w.Write(map[string]interface{}{
+		"ID":          a.ID.String(),
+		"Token":       a.Token,
+		"Status":      a.Status,
+		"UserID":      a.UserID.String(),
+		"ExpiresAt":   formatExpiresAt(a.ExpiresAt),
+		"Permissions": ps,
+	})
+
+	w.Flush()
+
+	return nil
+}
+
+// authorizationSeriesScope returns the series scope of the measurements and
+// tag rules, or nil if there are none.
+func authorizationSeriesScope(measurements, tags []string) (*platform.SeriesScope, error) {
+	if len(measurements) == 0 && len(tags) == 0 {
+		return nil, nil
+	}
+
+	s := &platform.SeriesScope{Measurements: measurements}
+	for _, t := range tags {
+		tr, err := platform.ParseTagRule(t)
+		if err != nil {
+			return nil, err
+		}
+		s.Tags = append(s.Tags, tr)
+	}
+	if err := s.Valid(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func formatExpiresAt(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -87,6 +87,7 @@ func influxCmd() *cobra.Command {
 	viper.SetEnvPrefix("INFLUX")
 
 	cmd.AddCommand(
+		alertCmd,
 		authCmd(),
 		bucketCmd,
 		deleteCmd,
@@ -96,7 +97,9 @@ func influxCmd() *cobra.Command {
 		queryCmd,
 		transpileCmd,
 		replCmd,
+		roleCmd,
 		setupCmd,
+		silenceCmd,
 		taskCmd,
 		userCmd(),
 		writeCmd,
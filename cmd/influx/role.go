@@ -0,0 +1,543 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Role Command
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Role management commands",
+	Long:  "Roles are named sets of permissions on the resources of an organization, e.g. dashboard-editor, assigned to its users.",
+	Run:   roleF,
+}
+
+func roleF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newRoleService(f Flags) (influxdb.RoleService, error) {
+	if f.local {
+		return newLocalKVService()
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.RoleService{
+		Client: client,
+	}, nil
+}
+
+func writeRoles(headers bool, roles ...*influxdb.Role) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.HideHeaders(!headers)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"Description",
+		"Permissions",
+		"OrganizationID",
+	)
+	for _, r := range roles {
+		ps := make([]string, 0, len(r.Permissions))
+		for _, p := range r.Permissions {
+			ps = append(ps, p.String())
+		}
+		w.Write(map[string]interface{}{
+			"ID":             r.ID.String(),
+			"Name":           r.Name,
+			"Description":    r.Description,
+			"Permissions":    ps,
+			"OrganizationID": r.OrgID.String(),
+		})
+	}
+	w.Flush()
+}
+
+// parseRolePermissions parses the permissions of the form action:type or
+// action:type/id on the resources of the organization, e.g. write:dashboards
+// or read:buckets/0000000000000001. The permission on the organization itself
+// is read:orgs or write:orgs.
+func parseRolePermissions(orgID influxdb.ID, ss []string) ([]influxdb.Permission, error) {
+	ps := make([]influxdb.Permission, 0, len(ss))
+	for _, s := range ss {
+		parts := strings.SplitN(s, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("permission %q must be of the form action:type or action:type/id", s)
+		}
+
+		resource := strings.SplitN(parts[1], "/", 2)
+		p := influxdb.Permission{
+			Action: influxdb.Action(parts[0]),
+			Resource: influxdb.Resource{
+				Type:  influxdb.ResourceType(resource[0]),
+				OrgID: &orgID,
+			},
+		}
+		if len(resource) == 2 {
+			id, err := influxdb.IDFromString(resource[1])
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode id of permission %q: %v", s, err)
+			}
+			p.Resource.ID = id
+		}
+		if p.Resource.Type == influxdb.OrgsResourceType {
+			p.Resource.OrgID = nil
+			p.Resource.ID = &orgID
+		}
+
+		if err := p.Valid(); err != nil {
+			return nil, fmt.Errorf("invalid permission %q: %v", s, err)
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// RoleCreateFlags define the Create Command
+type RoleCreateFlags struct {
+	name        string
+	description string
+	permissions []string
+	organization
+}
+
+var roleCreateFlags RoleCreateFlags
+
+func init() {
+	roleCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create role",
+		RunE:  wrapCheckSetup(roleCreateF),
+	}
+
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.name, "name", "n", "", "Name of role that will be created")
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.description, "description", "d", "", "Description of the role")
+	roleCreateCmd.Flags().StringArrayVarP(&roleCreateFlags.permissions, "permission", "p", []string{}, "Permission of the role, e.g. write:dashboards or read:buckets/<id>")
+	roleCreateCmd.MarkFlagRequired("name")
+	roleCreateCmd.MarkFlagRequired("permission")
+	roleCreateFlags.organization.register(roleCreateCmd)
+
+	roleCmd.AddCommand(roleCreateCmd)
+}
+
+func roleCreateF(cmd *cobra.Command, args []string) error {
+	if err := roleCreateFlags.organization.validOrgFlags(); err != nil {
+		return err
+	}
+
+	s, err := newRoleService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize role service client: %v", err)
+	}
+
+	orgSvc, err := newOrganizationService()
+	if err != nil {
+		return err
+	}
+
+	orgID, err := roleCreateFlags.organization.getID(orgSvc)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := parseRolePermissions(orgID, roleCreateFlags.permissions)
+	if err != nil {
+		return err
+	}
+
+	role := &influxdb.Role{
+		OrgID:       orgID,
+		Name:        roleCreateFlags.name,
+		Description: roleCreateFlags.description,
+		Permissions: permissions,
+	}
+
+	if err := s.CreateRole(context.Background(), role); err != nil {
+		return fmt.Errorf("failed to create role: %v", err)
+	}
+
+	writeRoles(true, role)
+
+	return nil
+}
+
+// RoleFindFlags define the Find Command
+type RoleFindFlags struct {
+	id      string
+	name    string
+	userID  string
+	headers bool
+	organization
+}
+
+var roleFindFlags RoleFindFlags
+
+func init() {
+	roleFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find roles",
+		RunE:  wrapCheckSetup(roleFindF),
+	}
+
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.id, "id", "i", "", "The role ID")
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.name, "name", "n", "", "The role name")
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.userID, "user-id", "", "", "The ID of a user the roles are assigned to")
+	roleFindCmd.Flags().BoolVar(&roleFindFlags.headers, "headers", true, "To print the table headers; defaults true")
+	roleFindFlags.organization.register(roleFindCmd)
+
+	roleCmd.AddCommand(roleFindCmd)
+}
+
+func roleFindF(cmd *cobra.Command, args []string) error {
+	s, err := newRoleService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize role service client: %v", err)
+	}
+
+	filter := influxdb.RoleFilter{}
+	if roleFindFlags.id != "" {
+		id, err := influxdb.IDFromString(roleFindFlags.id)
+		if err != nil {
+			return fmt.Errorf("failed to decode role id %q: %v", roleFindFlags.id, err)
+		}
+		filter.ID = id
+	}
+
+	if roleFindFlags.name != "" {
+		filter.Name = &roleFindFlags.name
+	}
+
+	if roleFindFlags.userID != "" {
+		id, err := influxdb.IDFromString(roleFindFlags.userID)
+		if err != nil {
+			return fmt.Errorf("failed to decode user id %q: %v", roleFindFlags.userID, err)
+		}
+		filter.UserID = id
+	}
+
+	if roleFindFlags.organization.id != "" || roleFindFlags.organization.name != "" {
+		if err := roleFindFlags.organization.validOrgFlags(); err != nil {
+			return err
+		}
+
+		orgSvc, err := newOrganizationService()
+		if err != nil {
+			return err
+		}
+
+		orgID, err := roleFindFlags.organization.getID(orgSvc)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+
+	roles, _, err := s.FindRoles(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve roles: %s", err)
+	}
+
+	writeRoles(roleFindFlags.headers, roles...)
+
+	return nil
+}
+
+// RoleUpdateFlags define the Update Command
+type RoleUpdateFlags struct {
+	id          string
+	name        string
+	description string
+	permissions []string
+}
+
+var roleUpdateFlags RoleUpdateFlags
+
+func init() {
+	roleUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update role",
+		RunE:  wrapCheckSetup(roleUpdateF),
+	}
+
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.id, "id", "i", "", "The role ID (required)")
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.name, "name", "n", "", "New role name")
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.description, "description", "d", "", "New role description")
+	roleUpdateCmd.Flags().StringArrayVarP(&roleUpdateFlags.permissions, "permission", "p", []string{}, "New permissions of the role, replacing its current ones")
+	roleUpdateCmd.MarkFlagRequired("id")
+
+	roleCmd.AddCommand(roleUpdateCmd)
+}
+
+func roleUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newRoleService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize role service client: %v", err)
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(roleUpdateFlags.id); err != nil {
+		return fmt.Errorf("failed to decode role id %q: %v", roleUpdateFlags.id, err)
+	}
+
+	ctx := context.Background()
+	update := influxdb.RoleUpdate{}
+	if roleUpdateFlags.name != "" {
+		update.Name = &roleUpdateFlags.name
+	}
+	if roleUpdateFlags.description != "" {
+		update.Description = &roleUpdateFlags.description
+	}
+	if len(roleUpdateFlags.permissions) > 0 {
+		role, err := s.FindRoleByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find role with id %q: %v", id, err)
+		}
+		if update.Permissions, err = parseRolePermissions(role.OrgID, roleUpdateFlags.permissions); err != nil {
+			return err
+		}
+	}
+
+	role, err := s.UpdateRole(ctx, id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %v", err)
+	}
+
+	writeRoles(true, role)
+
+	return nil
+}
+
+// RoleDeleteFlags define the Delete command
+type RoleDeleteFlags struct {
+	id string
+}
+
+var roleDeleteFlags RoleDeleteFlags
+
+func init() {
+	roleDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete role",
+		RunE:  wrapCheckSetup(roleDeleteF),
+	}
+
+	roleDeleteCmd.Flags().StringVarP(&roleDeleteFlags.id, "id", "i", "", "The role ID (required)")
+	roleDeleteCmd.MarkFlagRequired("id")
+
+	roleCmd.AddCommand(roleDeleteCmd)
+}
+
+func roleDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newRoleService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize role service client: %v", err)
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(roleDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode role id %q: %v", roleDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	role, err := s.FindRoleByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find role with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteRole(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete role with id %q: %v", id, err)
+	}
+
+	writeRoles(true, role)
+
+	return nil
+}
+
+// RoleMemberFlags define the commands managing the users a role is assigned to.
+type RoleMemberFlags struct {
+	id     string
+	userID string
+	user   string
+}
+
+// roleUserID returns the ID of the user of the flags, looked up by name if
+// the ID is not set.
+func (f RoleMemberFlags) roleUserID(ctx context.Context) (influxdb.ID, error) {
+	if f.userID != "" {
+		id, err := influxdb.IDFromString(f.userID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode user id %q: %v", f.userID, err)
+		}
+		return *id, nil
+	}
+	if f.user == "" {
+		return 0, fmt.Errorf("must specify user-id, or user name")
+	}
+
+	userSvc, err := newUserService()
+	if err != nil {
+		return 0, err
+	}
+	u, err := userSvc.FindUser(ctx, influxdb.UserFilter{Name: &f.user})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find user %q: %v", f.user, err)
+	}
+	return u.ID, nil
+}
+
+var roleAssignFlags RoleMemberFlags
+
+func init() {
+	roleAssignCmd := &cobra.Command{
+		Use:   "assign",
+		Short: "Assign role to a user",
+		RunE:  wrapCheckSetup(roleAssignF),
+	}
+
+	roleAssignCmd.Flags().StringVarP(&roleAssignFlags.id, "id", "i", "", "The role ID (required)")
+	roleAssignCmd.Flags().StringVarP(&roleAssignFlags.userID, "user-id", "", "", "The ID of the user")
+	roleAssignCmd.Flags().StringVarP(&roleAssignFlags.user, "user", "u", "", "The name of the user")
+	roleAssignCmd.MarkFlagRequired("id")
+
+	roleCmd.AddCommand(roleAssignCmd)
+}
+
+func roleAssignF(cmd *cobra.Command, args []string) error {
+	s, err := newRoleService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize role service client: %v", err)
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(roleAssignFlags.id); err != nil {
+		return fmt.Errorf("failed to decode role id %q: %v", roleAssignFlags.id, err)
+	}
+
+	ctx := context.Background()
+	userID, err := roleAssignFlags.roleUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CreateRoleAssignment(ctx, &influxdb.RoleAssignment{RoleID: id, UserID: userID}); err != nil {
+		return fmt.Errorf("failed to assign role: %v", err)
+	}
+
+	writeRoleMembers(true, &influxdb.RoleAssignment{RoleID: id, UserID: userID})
+
+	return nil
+}
+
+var roleUnassignFlags RoleMemberFlags
+
+func init() {
+	roleUnassignCmd := &cobra.Command{
+		Use:   "unassign",
+		Short: "Unassign role from a user",
+		RunE:  wrapCheckSetup(roleUnassignF),
+	}
+
+	roleUnassignCmd.Flags().StringVarP(&roleUnassignFlags.id, "id", "i", "", "The role ID (required)")
+	roleUnassignCmd.Flags().StringVarP(&roleUnassignFlags.userID, "user-id", "", "", "The ID of the user")
+	roleUnassignCmd.Flags().StringVarP(&roleUnassignFlags.user, "user", "u", "", "The name of the user")
+	roleUnassignCmd.MarkFlagRequired("id")
+
+	roleCmd.AddCommand(roleUnassignCmd)
+}
+
+func roleUnassignF(cmd *cobra.Command, args []string) error {
+	s, err := newRoleService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize role service client: %v", err)
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(roleUnassignFlags.id); err != nil {
+		return fmt.Errorf("failed to decode role id %q: %v", roleUnassignFlags.id, err)
+	}
+
+	ctx := context.Background()
+	userID, err := roleUnassignFlags.roleUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DeleteRoleAssignment(ctx, id, userID); err != nil {
+		return fmt.Errorf("failed to unassign role: %v", err)
+	}
+
+	writeRoleMembers(true, &influxdb.RoleAssignment{RoleID: id, UserID: userID})
+
+	return nil
+}
+
+// RoleMembersFlags define the Members command
+type RoleMembersFlags struct {
+	id      string
+	headers bool
+}
+
+var roleMembersFlags RoleMembersFlags
+
+func init() {
+	roleMembersCmd := &cobra.Command{
+		Use:   "members",
+		Short: "List the users a role is assigned to",
+		RunE:  wrapCheckSetup(roleMembersF),
+	}
+
+	roleMembersCmd.Flags().StringVarP(&roleMembersFlags.id, "id", "i", "", "The role ID (required)")
+	roleMembersCmd.Flags().BoolVar(&roleMembersFlags.headers, "headers", true, "To print the table headers; defaults true")
+	roleMembersCmd.MarkFlagRequired("id")
+
+	roleCmd.AddCommand(roleMembersCmd)
+}
+
+func roleMembersF(cmd *cobra.Command, args []string) error {
+	s, err := newRoleService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize role service client: %v", err)
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(roleMembersFlags.id); err != nil {
+		return fmt.Errorf("failed to decode role id %q: %v", roleMembersFlags.id, err)
+	}
+
+	as, _, err := s.FindRoleAssignments(context.Background(), influxdb.RoleAssignmentFilter{RoleID: &id})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve role members: %v", err)
+	}
+
+	writeRoleMembers(roleMembersFlags.headers, as...)
+
+	return nil
+}
+
+func writeRoleMembers(headers bool, as ...*influxdb.RoleAssignment) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.HideHeaders(!headers)
+	w.WriteHeaders(
+		"RoleID",
+		"UserID",
+	)
+	for _, a := range as {
+		w.Write(map[string]interface{}{
+			"RoleID": a.RoleID.String(),
+			"UserID": a.UserID.String(),
+		})
+	}
+	w.Flush()
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/predicate"
+	"github.com/spf13/cobra"
+)
+
+// Silence Command
+var silenceCmd = &cobra.Command{
+	Use:   "silence",
+	Short: "Silence management commands",
+	Long:  "Silences mute the notifications of the statuses they match, e.g. during a planned maintenance.",
+	Run:   silenceF,
+}
+
+func silenceF(cmd *cobra.Command, args []string) {
+	cmd.Usage()
+}
+
+func newSilenceService(f Flags) (influxdb.SilenceService, error) {
+	if f.local {
+		return newLocalKVService()
+	}
+
+	client, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.SilenceService{
+		Client: client,
+	}, nil
+}
+
+func writeSilences(headers bool, silences ...*influxdb.Silence) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.HideHeaders(!headers)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"CheckID",
+		"RuleID",
+		"Tags",
+		"Start",
+		"End",
+		"OrganizationID",
+	)
+	for _, s := range silences {
+		var checkID, ruleID string
+		if s.CheckID.Valid() {
+			checkID = s.CheckID.String()
+		}
+		if s.RuleID.Valid() {
+			ruleID = s.RuleID.String()
+		}
+		tags := make([]string, 0, len(s.TagRules))
+		for _, tr := range s.TagRules {
+			tags = append(tags, fmt.Sprintf("%s=%q", tr.Key, tr.Value))
+		}
+		w.Write(map[string]interface{}{
+			"ID":             s.ID.String(),
+			"Name":           s.Name,
+			"CheckID":        checkID,
+			"RuleID":         ruleID,
+			"Tags":           tags,
+			"Start":          s.StartTime.Format(time.RFC3339),
+			"End":            s.EndTime.Format(time.RFC3339),
+			"OrganizationID": s.OrgID.String(),
+		})
+	}
+	w.Flush()
+}
+
+// SilenceCreateFlags define the Create Command
+type SilenceCreateFlags struct {
+	name        string
+	description string
+	checkID     string
+	ruleID      string
+	predicate   string
+	start       string
+	stop        string
+	duration    time.Duration
+	organization
+}
+
+var silenceCreateFlags SilenceCreateFlags
+
+func init() {
+	silenceCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create silence",
+		RunE:  wrapCheckSetup(silenceCreateF),
+	}
+
+	silenceCreateCmd.Flags().StringVarP(&silenceCreateFlags.name, "name", "n", "", "Name of silence that will be created")
+	silenceCreateCmd.Flags().StringVarP(&silenceCreateFlags.description, "description", "d", "", "Description of the silence")
+	silenceCreateCmd.Flags().StringVarP(&silenceCreateFlags.checkID, "check-id", "", "", "The ID of the check whose statuses are silenced")
+	silenceCreateCmd.Flags().StringVarP(&silenceCreateFlags.ruleID, "rule-id", "", "", "The ID of the notification rule that is silenced")
+	silenceCreateCmd.Flags().StringVarP(&silenceCreateFlags.predicate, "predicate", "p", "", `The tags of the silenced statuses, e.g. 'host="db1" and env="prod"'`)
+	silenceCreateCmd.Flags().StringVarP(&silenceCreateFlags.start, "start", "", "", "start time of the silence, RFC3339; defaults to now")
+	silenceCreateCmd.Flags().StringVarP(&silenceCreateFlags.stop, "stop", "", "", "stop time of the silence, RFC3339")
+	silenceCreateCmd.Flags().DurationVarP(&silenceCreateFlags.duration, "duration", "", 0, "duration of the silence, if stop is not set")
+	silenceCreateCmd.MarkFlagRequired("name")
+	silenceCreateFlags.organization.register(silenceCreateCmd)
+
+	silenceCmd.AddCommand(silenceCreateCmd)
+}
+
+func silenceCreateF(cmd *cobra.Command, args []string) error {
+	if err := silenceCreateFlags.organization.validOrgFlags(); err != nil {
+		return err
+	}
+
+	s, err := newSilenceService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize silence service client: %v", err)
+	}
+
+	silence := &influxdb.Silence{
+		Name:        silenceCreateFlags.name,
+		Description: silenceCreateFlags.description,
+		StartTime:   time.Now().UTC(),
+	}
+
+	if silenceCreateFlags.checkID != "" {
+		if err := silence.CheckID.DecodeFromString(silenceCreateFlags.checkID); err != nil {
+			return fmt.Errorf("failed to decode check id %q: %v", silenceCreateFlags.checkID, err)
+		}
+	}
+
+	if silenceCreateFlags.ruleID != "" {
+		if err := silence.RuleID.DecodeFromString(silenceCreateFlags.ruleID); err != nil {
+			return fmt.Errorf("failed to decode notification rule id %q: %v", silenceCreateFlags.ruleID, err)
+		}
+	}
+
+	if silenceCreateFlags.predicate != "" {
+		node, err := predicate.Parse(silenceCreateFlags.predicate)
+		if err != nil {
+			return fmt.Errorf("failed to parse predicate %q: %v", silenceCreateFlags.predicate, err)
+		}
+		if silence.TagRules, err = predicate.TagRules(node); err != nil {
+			return fmt.Errorf("failed to parse predicate %q: %v", silenceCreateFlags.predicate, err)
+		}
+	}
+
+	if silenceCreateFlags.start != "" {
+		if silence.StartTime, err = time.Parse(time.RFC3339, silenceCreateFlags.start); err != nil {
+			return fmt.Errorf("failed to parse start time %q: %v", silenceCreateFlags.start, err)
+		}
+	}
+
+	switch {
+	case silenceCreateFlags.stop != "":
+		if silence.EndTime, err = time.Parse(time.RFC3339, silenceCreateFlags.stop); err != nil {
+			return fmt.Errorf("failed to parse stop time %q: %v", silenceCreateFlags.stop, err)
+		}
+	case silenceCreateFlags.duration > 0:
+		silence.EndTime = silence.StartTime.Add(silenceCreateFlags.duration)
+	default:
+		return fmt.Errorf("must specify stop or duration")
+	}
+
+	orgSvc, err := newOrganizationService()
+	if err != nil {
+		return err
+	}
+
+	silence.OrgID, err = silenceCreateFlags.organization.getID(orgSvc)
+	if err != nil {
+		return err
+	}
+
+	if err := s.CreateSilence(context.Background(), silence); err != nil {
+		return fmt.Errorf("failed to create silence: %v", err)
+	}
+
+	writeSilences(true, silence)
+
+	return nil
+}
+
+// SilenceFindFlags define the Find Command
+type SilenceFindFlags struct {
+	id      string
+	checkID string
+	ruleID  string
+	headers bool
+	organization
+}
+
+var silenceFindFlags SilenceFindFlags
+
+func init() {
+	silenceFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find silences",
+		RunE:  wrapCheckSetup(silenceFindF),
+	}
+
+	silenceFindCmd.Flags().StringVarP(&silenceFindFlags.id, "id", "i", "", "The silence ID")
+	silenceFindCmd.Flags().StringVarP(&silenceFindFlags.checkID, "check-id", "", "", "The ID of the silenced check")
+	silenceFindCmd.Flags().StringVarP(&silenceFindFlags.ruleID, "rule-id", "", "", "The ID of a notification rule the silences apply to")
+	silenceFindCmd.Flags().BoolVar(&silenceFindFlags.headers, "headers", true, "To print the table headers; defaults true")
+	silenceFindFlags.organization.register(silenceFindCmd)
+
+	silenceCmd.AddCommand(silenceFindCmd)
+}
+
+func silenceFindF(cmd *cobra.Command, args []string) error {
+	s, err := newSilenceService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize silence service client: %v", err)
+	}
+
+	filter := influxdb.SilenceFilter{}
+	if silenceFindFlags.id != "" {
+		id, err := influxdb.IDFromString(silenceFindFlags.id)
+		if err != nil {
+			return fmt.Errorf("failed to decode silence id %q: %v", silenceFindFlags.id, err)
+		}
+		filter.ID = id
+	}
+
+	if silenceFindFlags.checkID != "" {
+		id, err := influxdb.IDFromString(silenceFindFlags.checkID)
+		if err != nil {
+			return fmt.Errorf("failed to decode check id %q: %v", silenceFindFlags.checkID, err)
+		}
+		filter.CheckID = id
+	}
+
+	if silenceFindFlags.ruleID != "" {
+		id, err := influxdb.IDFromString(silenceFindFlags.ruleID)
+		if err != nil {
+			return fmt.Errorf("failed to decode notification rule id %q: %v", silenceFindFlags.ruleID, err)
+		}
+		filter.RuleID = id
+	}
+
+	if silenceFindFlags.organization.id != "" || silenceFindFlags.organization.name != "" {
+		if err := silenceFindFlags.organization.validOrgFlags(); err != nil {
+			return err
+		}
+
+		orgSvc, err := newOrganizationService()
+		if err != nil {
+			return err
+		}
+
+		orgID, err := silenceFindFlags.organization.getID(orgSvc)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+
+	silences, _, err := s.FindSilences(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve silences: %s", err)
+	}
+
+	writeSilences(silenceFindFlags.headers, silences...)
+
+	return nil
+}
+
+// SilenceUpdateFlags define the Update Command
+type SilenceUpdateFlags struct {
+	id          string
+	name        string
+	description string
+	start       string
+	stop        string
+}
+
+var silenceUpdateFlags SilenceUpdateFlags
+
+func init() {
+	silenceUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update silence",
+		RunE:  wrapCheckSetup(silenceUpdateF),
+	}
+
+	silenceUpdateCmd.Flags().StringVarP(&silenceUpdateFlags.id, "id", "i", "", "The silence ID (required)")
+	silenceUpdateCmd.Flags().StringVarP(&silenceUpdateFlags.name, "name", "n", "", "New silence name")
+	silenceUpdateCmd.Flags().StringVarP(&silenceUpdateFlags.description, "description", "d", "", "New silence description")
+	silenceUpdateCmd.Flags().StringVarP(&silenceUpdateFlags.start, "start", "", "", "New start time of the silence, RFC3339")
+	silenceUpdateCmd.Flags().StringVarP(&silenceUpdateFlags.stop, "stop", "", "", "New stop time of the silence, RFC3339; use now to end the silence")
+	silenceUpdateCmd.MarkFlagRequired("id")
+
+	silenceCmd.AddCommand(silenceUpdateCmd)
+}
+
+func silenceUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newSilenceService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize silence service client: %v", err)
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(silenceUpdateFlags.id); err != nil {
+		return fmt.Errorf("failed to decode silence id %q: %v", silenceUpdateFlags.id, err)
+	}
+
+	update := influxdb.SilenceUpdate{}
+	if silenceUpdateFlags.name != "" {
+		update.Name = &silenceUpdateFlags.name
+	}
+	if silenceUpdateFlags.description != "" {
+		update.Description = &silenceUpdateFlags.description
+	}
+	if silenceUpdateFlags.start != "" {
+		start, err := time.Parse(time.RFC3339, silenceUpdateFlags.start)
+		if err != nil {
+			return fmt.Errorf("failed to parse start time %q: %v", silenceUpdateFlags.start, err)
+		}
+		update.StartTime = &start
+	}
+	if silenceUpdateFlags.stop != "" {
+		stop := time.Now().UTC()
+		if silenceUpdateFlags.stop != "now" {
+			if stop, err = time.Parse(time.RFC3339, silenceUpdateFlags.stop); err != nil {
+				return fmt.Errorf("failed to parse stop time %q: %v", silenceUpdateFlags.stop, err)
+			}
+		}
+		update.EndTime = &stop
+	}
+
+	silence, err := s.UpdateSilence(context.Background(), id, update)
+	if err != nil {
+		return fmt.Errorf("failed to update silence: %v", err)
+	}
+
+	writeSilences(true, silence)
+
+	return nil
+}
+
+// SilenceDeleteFlags define the Delete command
+type SilenceDeleteFlags struct {
+	id string
+}
+
+var silenceDeleteFlags SilenceDeleteFlags
+
+func init() {
+	silenceDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete silence",
+		RunE:  wrapCheckSetup(silenceDeleteF),
+	}
+
+	silenceDeleteCmd.Flags().StringVarP(&silenceDeleteFlags.id, "id", "i", "", "The silence ID (required)")
+	silenceDeleteCmd.MarkFlagRequired("id")
+
+	silenceCmd.AddCommand(silenceDeleteCmd)
+}
+
+func silenceDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newSilenceService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize silence service client: %v", err)
+	}
+
+	var id influxdb.ID
+	if err := id.DecodeFromString(silenceDeleteFlags.id); err != nil {
+		return fmt.Errorf("failed to decode silence id %q: %v", silenceDeleteFlags.id, err)
+	}
+
+	ctx := context.Background()
+	silence, err := s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find silence with id %q: %v", id, err)
+	}
+
+	if err := s.DeleteSilence(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete silence with id %q: %v", id, err)
+	}
+
+	writeSilences(true, silence)
+
+	return nil
+}
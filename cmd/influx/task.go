@@ -45,9 +45,18 @@ var runCmd = &cobra.Command{
 	},
 }
 
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Backfill related commands",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Usage()
+	},
+}
+
 func init() {
 	taskCmd.AddCommand(runCmd)
 	taskCmd.AddCommand(logCmd)
+	taskCmd.AddCommand(backfillCmd)
 }
 
 var taskCreateFlags struct {
@@ -581,3 +590,149 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+var backfillCreateFlags struct {
+	taskID      string
+	start       string
+	stop        string
+	concurrency int
+	dryRun      bool
+}
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "run a task for every time of its schedule in a time range",
+		RunE:  wrapCheckSetup(backfillCreateF),
+	}
+
+	cmd.Flags().StringVarP(&backfillCreateFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&backfillCreateFlags.start, "start", "", "", "start time of the range, RFC3339 (required)")
+	cmd.Flags().StringVarP(&backfillCreateFlags.stop, "stop", "", "", "stop time of the range, RFC3339 (required)")
+	cmd.Flags().IntVarP(&backfillCreateFlags.concurrency, "concurrency", "", 1, "maximum number of runs executed at once")
+	cmd.Flags().BoolVarP(&backfillCreateFlags.dryRun, "dry-run", "", false, "list the times runs would be scheduled for, without scheduling them")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("start")
+	cmd.MarkFlagRequired("stop")
+
+	backfillCmd.AddCommand(cmd)
+}
+
+func backfillCreateF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(backfillCreateFlags.taskID); err != nil {
+		return err
+	}
+
+	start, err := time.Parse(time.RFC3339, backfillCreateFlags.start)
+	if err != nil {
+		return err
+	}
+	stop, err := time.Parse(time.RFC3339, backfillCreateFlags.stop)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.BackfillTask(context.Background(), taskID, platform.BackfillRequest{
+		Start:       start,
+		Stop:        stop,
+		Concurrency: backfillCreateFlags.concurrency,
+		DryRun:      backfillCreateFlags.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if backfillCreateFlags.dryRun {
+		w := internal.NewTabWriter(os.Stdout)
+		w.WriteHeaders("ScheduledFor")
+		for _, t := range b.ScheduledFor {
+			w.Write(map[string]interface{}{
+				"ScheduledFor": t.Format(time.RFC3339),
+			})
+		}
+		w.Flush()
+		return nil
+	}
+
+	fmt.Printf("Backfill %s of task %s scheduled %d runs, %d at once.\n", b.ID, taskID, len(b.ScheduledFor), b.Concurrency)
+
+	return nil
+}
+
+var backfillStatusFlags struct {
+	taskID string
+}
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "show the progress of the backfills of a task",
+		RunE:  wrapCheckSetup(backfillStatusF),
+	}
+
+	cmd.Flags().StringVarP(&backfillStatusFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.MarkFlagRequired("task-id")
+
+	backfillCmd.AddCommand(cmd)
+}
+
+func backfillStatusF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(backfillStatusFlags.taskID); err != nil {
+		return err
+	}
+
+	backfills, err := s.FindBackfills(context.Background(), taskID)
+	if err != nil {
+		return err
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Status",
+		"Start",
+		"Stop",
+		"Runs",
+		"Succeeded",
+		"Failed",
+		"Skipped",
+		"CreatedAt",
+		"FinishedAt",
+	)
+	for _, b := range backfills {
+		var finishedAt string
+		if !b.FinishedAt.IsZero() {
+			finishedAt = b.FinishedAt.Format(time.RFC3339Nano)
+		}
+
+		w.Write(map[string]interface{}{
+			"ID":         b.ID,
+			"Status":     b.Status,
+			"Start":      b.Start.Format(time.RFC3339),
+			"Stop":       b.Stop.Format(time.RFC3339),
+			"Runs":       len(b.ScheduledFor),
+			"Succeeded":  b.Succeeded,
+			"Failed":     b.Failed,
+			"Skipped":    b.Skipped,
+			"CreatedAt":  b.CreatedAt.Format(time.RFC3339Nano),
+			"FinishedAt": finishedAt,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/internal/fs"
+	pfs "github.com/influxdata/influxdb/pkg/fs"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ManifestFileName is the name of the manifest describing the contents
+	// of a backup directory.
+	ManifestFileName = "manifest.json"
+
+	// KVFileName is the name of the copy of the bolt metadata store within
+	// a backup directory.
+	KVFileName = "influxd.bolt"
+)
+
+// clientFlags are the flags shared by commands that connect to a running server.
+type clientFlags struct {
+	host       string
+	token      string
+	skipVerify bool
+}
+
+func (f *clientFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.host, "host", "http://localhost:9999", "HTTP address of the influxd server")
+	cmd.Flags().StringVarP(&f.token, "token", "t", "", "Operator token; defaults to the token saved by influx setup")
+	cmd.Flags().BoolVar(&f.skipVerify, "skip-verify", false, "Skip verification of the server's TLS certificate")
+}
+
+func (f *clientFlags) authToken() string {
+	if f.token != "" {
+		return f.token
+	}
+	if tok := os.Getenv("INFLUX_TOKEN"); tok != "" {
+		return tok
+	}
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		return ""
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "credentials"))
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+var backupFlags = struct {
+	clientFlags
+	parent string
+}{}
+
+// NewBackupCommand creates the backup command.
+func NewBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup <path>",
+		Short: "Creates a backup of a running influxd server",
+		Long: `
+This command creates a consistent backup of the metadata store and of the
+time series data of a running influxd server, without stopping it. The backup
+is written to a new directory at the provided path and can be restored with
+"influxd restore".
+
+With --incremental-from, only the TSM files that are not part of the
+provided previous backup are copied, along with the WAL segments holding
+writes that have not been compacted yet. Restoring an incremental backup
+requires every backup in its chain of parents.
+
+An operator token is required.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: backupF,
+	}
+
+	backupFlags.addFlags(cmd)
+	cmd.Flags().StringVar(&backupFlags.parent, "incremental-from", "", "Path to a previous backup to create an incremental backup from")
+
+	return cmd
+}
+
+func backupF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	path := args[0]
+
+	if _, err := os.Stat(filepath.Join(path, ManifestFileName)); err == nil {
+		return fmt.Errorf("a backup already exists at %q", path)
+	}
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return err
+	}
+
+	var parent *influxdb.BackupManifest
+	if backupFlags.parent != "" {
+		m, err := readManifest(backupFlags.parent)
+		if err != nil {
+			return fmt.Errorf("failed to read previous backup: %v", err)
+		}
+		parent = m
+	}
+
+	client, err := http.NewHTTPClient(backupFlags.host, backupFlags.authToken(), backupFlags.skipVerify)
+	if err != nil {
+		return err
+	}
+	svc := &http.BackupService{Client: client}
+
+	// The engine snapshot is created before the metadata store is copied so
+	// that every bucket with data in the backup is present in the copy.
+	manifest, err := svc.CreateBackup(ctx, influxdb.BackupOptions{Incremental: parent != nil})
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %v", err)
+	}
+	defer func() {
+		if err := svc.DeleteBackup(ctx, manifest.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to remove backup %d from the server: %v\n", manifest.ID, err)
+		}
+	}()
+
+	kvFile, err := downloadFile(path, KVFileName, func(f *os.File) error {
+		return svc.Backup(ctx, f)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to back up metadata: %v", err)
+	}
+	kvFile.Type = influxdb.BackupFileTypeKV
+
+	var inherited int
+	for i, file := range manifest.Files {
+		if parent != nil && isInherited(file, parent) {
+			manifest.Files[i].Inherited = true
+			inherited++
+			continue
+		}
+
+		if _, err := downloadFile(path, file.Path, func(f *os.File) error {
+			return svc.FetchBackupFile(ctx, manifest.ID, file.Path, f)
+		}); err != nil {
+			return fmt.Errorf("failed to back up %q: %v", file.Path, err)
+		}
+	}
+	manifest.Files = append([]influxdb.BackupFile{kvFile}, manifest.Files...)
+
+	if parent != nil {
+		if manifest.Parent, err = relativePath(path, backupFlags.parent); err != nil {
+			return err
+		}
+	}
+
+	if err := writeManifest(path, manifest); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup %d written to %s (%d files, %d inherited)\n", manifest.ID, path, len(manifest.Files), inherited)
+	return nil
+}
+
+// isInherited returns true if file does not need to be copied into an
+// incremental backup of parent. TSM files are never modified once written,
+// so a TSM file with the same name as one in the parent backup has the same
+// contents. Their tombstones are modified in place and are always copied.
+func isInherited(file influxdb.BackupFile, parent *influxdb.BackupManifest) bool {
+	if file.Type != influxdb.BackupFileTypeTSM || path.Ext(file.Path) != "."+tsm1.TSMFileExtension {
+		return false
+	}
+	pf, ok := parent.File(file.Path)
+	return ok && pf.Size == file.Size
+}
+
+// relativePath returns the slash separated location of target relative to dir.
+func relativePath(dir, target string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absDir, absTarget)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// downloadFile creates the file name within dir and fills it using fn.
+func downloadFile(dir, name string, fn func(f *os.File) error) (influxdb.BackupFile, error) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return influxdb.BackupFile{}, err
+	}
+
+	f, err := pfs.CreateFile(path)
+	if err != nil {
+		return influxdb.BackupFile{}, err
+	}
+	defer f.Close()
+
+	if err := fn(f); err != nil {
+		return influxdb.BackupFile{}, err
+	}
+	if err := f.Sync(); err != nil {
+		return influxdb.BackupFile{}, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return influxdb.BackupFile{}, err
+	}
+	return influxdb.BackupFile{Path: name, Size: fi.Size()}, f.Close()
+}
+
+func writeManifest(dir string, m *influxdb.BackupManifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, ManifestFileName), b, 0666)
+}
+
+// backupChain is a backup followed by all of the backups that it is
+// incrementally based on, in order.
+type backupChain []chainedBackup
+
+type chainedBackup struct {
+	dir      string
+	manifest *influxdb.BackupManifest
+}
+
+// readBackupChain reads the manifests of the backup at dir and its parents.
+func readBackupChain(dir string) (backupChain, error) {
+	var chain backupChain
+	seen := make(map[string]bool)
+	for {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		} else if seen[abs] {
+			return nil, fmt.Errorf("backup %q is its own parent", dir)
+		}
+		seen[abs] = true
+
+		m, err := readManifest(dir)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, chainedBackup{dir: dir, manifest: m})
+
+		if m.Parent == "" {
+			return chain, nil
+		}
+		dir = filepath.Join(dir, filepath.FromSlash(m.Parent))
+	}
+}
+
+// Manifest returns the manifest of the most recent backup in the chain.
+func (c backupChain) Manifest() *influxdb.BackupManifest {
+	return c[0].manifest
+}
+
+// Locate returns the location on disk of a file of the most recent backup,
+// following the chain of parents for inherited files.
+func (c backupChain) Locate(file influxdb.BackupFile) (string, error) {
+	for i := range c {
+		f, ok := c[i].manifest.File(file.Path)
+		if !ok {
+			break
+		} else if !f.Inherited {
+			return filepath.Join(c[i].dir, filepath.FromSlash(f.Path)), nil
+		}
+	}
+	return "", fmt.Errorf("file %q is missing from the chain of backups", file.Path)
+}
+
+func readManifest(dir string) (*influxdb.BackupManifest, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var m influxdb.BackupManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %v", err)
+	}
+	return &m, nil
+}
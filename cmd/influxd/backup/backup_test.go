@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestBackupChain_Locate(t *testing.T) {
+	root, err := ioutil.TempDir("", "influxd_backup_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	full := &influxdb.BackupManifest{
+		ID: 1,
+		Files: []influxdb.BackupFile{
+			{Type: influxdb.BackupFileTypeTSM, Path: "data/000000001-000000001.tsm", Size: 10},
+			{Type: influxdb.BackupFileTypeTSM, Path: "data/000000002-000000001.tsm", Size: 10},
+		},
+	}
+	incremental := &influxdb.BackupManifest{
+		ID: 2,
+		Files: []influxdb.BackupFile{
+			{Type: influxdb.BackupFileTypeTSM, Path: "data/000000001-000000001.tsm", Size: 10},
+			{Type: influxdb.BackupFileTypeTSM, Path: "data/000000001-000000001.tombstone", Size: 5},
+			{Type: influxdb.BackupFileTypeTSM, Path: "data/000000002-000000001.tsm", Size: 20},
+			{Type: influxdb.BackupFileTypeTSM, Path: "data/000000003-000000001.tsm", Size: 10},
+		},
+	}
+
+	// Only unchanged TSM files are inherited from the parent.
+	for i, exp := range []bool{true, false, false, false} {
+		if got := isInherited(incremental.Files[i], full); got != exp {
+			t.Errorf("isInherited(%q) = %v, expected %v", incremental.Files[i].Path, got, exp)
+		}
+		incremental.Files[i].Inherited = exp
+	}
+
+	fullDir, incDir := filepath.Join(root, "full"), filepath.Join(root, "incremental")
+	for _, dir := range []string{fullDir, incDir} {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if incremental.Parent, err = relativePath(incDir, fullDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeManifest(fullDir, full); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeManifest(incDir, incremental); err != nil {
+		t.Fatal(err)
+	}
+
+	chain, err := readBackupChain(incDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := len(chain), 2; got != exp {
+		t.Fatalf("got chain of %d backups, expected %d", got, exp)
+	}
+	if got, exp := chain.Manifest().ID, 2; got != exp {
+		t.Fatalf("got manifest %d, expected %d", got, exp)
+	}
+
+	for i, exp := range []string{
+		filepath.Join(fullDir, "data", "000000001-000000001.tsm"),
+		filepath.Join(incDir, "data", "000000001-000000001.tombstone"),
+		filepath.Join(incDir, "data", "000000002-000000001.tsm"),
+		filepath.Join(incDir, "data", "000000003-000000001.tsm"),
+	} {
+		got, err := chain.Locate(incremental.Files[i])
+		if err != nil {
+			t.Fatal(err)
+		} else if got != exp {
+			t.Errorf("got location %q, expected %q", got, exp)
+		}
+	}
+
+	// A file inherited from a parent that does not have it cannot be located.
+	if _, err := chain.Locate(influxdb.BackupFile{Path: "data/000000004-000000001.tsm", Inherited: true}); err == nil {
+		t.Fatal("expected error locating missing file")
+	}
+}
@@ -0,0 +1,493 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/models"
+	pfs "github.com/influxdata/influxdb/pkg/fs"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// restoreBatchSize is the number of points sent in each write when restoring
+// a single bucket.
+const restoreBatchSize = 5000
+
+var restoreFlags = struct {
+	clientFlags
+
+	full       bool
+	force      bool
+	boltPath   string
+	enginePath string
+
+	org       string
+	bucket    string
+	newOrg    string
+	newBucket string
+}{}
+
+// NewRestoreCommand creates the restore command.
+func NewRestoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <path>",
+		Short: "Restores a backup created by influxd backup",
+		Long: `
+This command restores a backup created by "influxd backup". An incremental
+backup is restored along with the chain of backups that it is based on.
+
+With --full, the metadata store and all time series data are restored in
+place of an existing instance. influxd must not be running; existing data is
+only replaced when --force is provided.
+
+With --bucket, the data of a single bucket is written into a new bucket of a
+running influxd server. The bucket is created with the retention period of the
+bucket in the backup. An operator token is required.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: restoreF,
+	}
+
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to determine influx directory: %v", err))
+	}
+
+	restoreFlags.addFlags(cmd)
+	cmd.Flags().BoolVar(&restoreFlags.full, "full", false, "Restore the metadata store and all data of an instance; influxd must be stopped")
+	cmd.Flags().BoolVar(&restoreFlags.force, "force", false, "Replace existing data during a full restore")
+	cmd.Flags().StringVar(&restoreFlags.boltPath, "bolt-path", filepath.Join(dir, "influxd.bolt"), "Path to the boltdb database to restore")
+	cmd.Flags().StringVar(&restoreFlags.enginePath, "engine-path", filepath.Join(dir, "engine"), "Path to the persistent engine files to restore")
+	cmd.Flags().StringVar(&restoreFlags.org, "org", "", "Name of the organization of the bucket in the backup")
+	cmd.Flags().StringVar(&restoreFlags.bucket, "bucket", "", "Name of the bucket in the backup to restore")
+	cmd.Flags().StringVar(&restoreFlags.newOrg, "new-org", "", "Name of the organization to restore the bucket into; defaults to --org")
+	cmd.Flags().StringVar(&restoreFlags.newBucket, "new-bucket", "", "Name of the bucket to restore into; defaults to --bucket")
+
+	return cmd
+}
+
+func restoreF(cmd *cobra.Command, args []string) error {
+	chain, err := readBackupChain(args[0])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case restoreFlags.full && restoreFlags.bucket != "":
+		return errors.New("--full and --bucket are mutually exclusive")
+	case restoreFlags.full:
+		return restoreFull(chain)
+	case restoreFlags.bucket != "":
+		if restoreFlags.org == "" {
+			return errors.New("--org is required to restore a bucket")
+		}
+		return restoreBucket(context.Background(), chain)
+	default:
+		return errors.New("one of --full or --bucket is required")
+	}
+}
+
+// restoreFull copies all of the files of the backup into the bolt and engine
+// paths of a stopped instance. The WAL segments of an incremental backup are
+// replayed by influxd when it is next started.
+func restoreFull(chain backupChain) error {
+	manifest := chain.Manifest()
+
+	for _, p := range []string{restoreFlags.boltPath, restoreFlags.enginePath} {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if !restoreFlags.force {
+			return fmt.Errorf("%q already exists; stop influxd and use --force to replace it", p)
+		}
+		if err := os.RemoveAll(p); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range manifest.Files {
+		src, err := chain.Locate(file)
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(restoreFlags.enginePath, filepath.FromSlash(file.Path))
+		if file.Type == influxdb.BackupFileTypeKV {
+			dst = restoreFlags.boltPath
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			return err
+		}
+		if err := pfs.CopyFile(src, dst); err != nil {
+			return fmt.Errorf("failed to restore %q: %v", file.Path, err)
+		}
+	}
+
+	fmt.Printf("Restored backup %d to %s and %s\n", manifest.ID, restoreFlags.boltPath, restoreFlags.enginePath)
+	return nil
+}
+
+// restoreBucket writes the data of a single bucket of the backup into a new
+// bucket of a running server.
+func restoreBucket(ctx context.Context, chain backupChain) error {
+	src, err := findBackupBucket(ctx, chain)
+	if err != nil {
+		return err
+	}
+
+	newOrg, newBucket := restoreFlags.newOrg, restoreFlags.newBucket
+	if newOrg == "" {
+		newOrg = restoreFlags.org
+	}
+	if newBucket == "" {
+		newBucket = restoreFlags.bucket
+	}
+
+	client, err := http.NewHTTPClient(restoreFlags.host, restoreFlags.authToken(), restoreFlags.skipVerify)
+	if err != nil {
+		return err
+	}
+
+	orgSvc := &http.OrganizationService{Client: client}
+	org, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &newOrg})
+	if err != nil {
+		return fmt.Errorf("failed to find organization %q: %v", newOrg, err)
+	}
+
+	bucketSvc := &http.BucketService{Client: client}
+	if _, err := bucketSvc.FindBucketByName(ctx, org.ID, newBucket); err == nil {
+		return fmt.Errorf("bucket %q already exists; use --new-bucket to restore into a new bucket", newBucket)
+	} else if influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+
+	dst := &influxdb.Bucket{
+		OrgID:           org.ID,
+		Name:            newBucket,
+		Description:     src.Description,
+		RetentionPeriod: src.RetentionPeriod,
+	}
+	if err := bucketSvc.CreateBucket(ctx, dst); err != nil {
+		return fmt.Errorf("failed to create bucket %q: %v", newBucket, err)
+	}
+
+	w := &bucketWriter{
+		ctx:    ctx,
+		orgID:  dst.OrgID,
+		bucket: dst.ID,
+		svc: &http.WriteService{
+			Addr:               restoreFlags.host,
+			Token:              restoreFlags.authToken(),
+			InsecureSkipVerify: restoreFlags.skipVerify,
+		},
+	}
+
+	// TSM files inherited from a parent backup are staged alongside their
+	// current tombstones so that deleted values are not restored.
+	dir, err := ioutil.TempDir("", "influxd-restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	tsmPaths, err := stageFiles(chain, influxdb.BackupFileTypeTSM, dir)
+	if err != nil {
+		return err
+	}
+	walPaths, err := stageFiles(chain, influxdb.BackupFileTypeWAL, dir)
+	if err != nil {
+		return err
+	}
+
+	deletes, err := readWALDeletes(walPaths, src.OrgID, src.ID)
+	if err != nil {
+		return err
+	}
+
+	prefix := tsdb.EncodeName(src.OrgID, src.ID)
+	for _, path := range tsmPaths {
+		if filepath.Ext(path) != "."+tsm1.TSMFileExtension {
+			continue // Tombstones are loaded alongside their TSM file.
+		}
+		if err := restoreTSMFile(path, prefix[:], deletes, w); err != nil {
+			return fmt.Errorf("failed to restore %q: %v", filepath.Base(path), err)
+		}
+	}
+
+	if err := restoreWAL(walPaths, prefix[:], deletes, w); err != nil {
+		return fmt.Errorf("failed to restore wal: %v", err)
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored %d points of bucket %q into bucket %q\n", w.points, restoreFlags.bucket, newBucket)
+	return nil
+}
+
+// findBackupBucket looks up the bucket to restore in the metadata store of the
+// backup.
+func findBackupBucket(ctx context.Context, chain backupChain) (*influxdb.Bucket, error) {
+	files := chain.Manifest().FilesOfType(influxdb.BackupFileTypeKV)
+	if len(files) == 0 {
+		return nil, errors.New("backup does not contain a metadata store")
+	}
+
+	// Work on a copy so that opening the store leaves the backup untouched.
+	tmp, err := ioutil.TempDir("", "influxd-restore")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	src, err := chain.Locate(files[0])
+	if err != nil {
+		return nil, err
+	}
+	boltPath := filepath.Join(tmp, KVFileName)
+	if err := pfs.CopyFile(src, boltPath); err != nil {
+		return nil, err
+	}
+
+	store := bolt.NewKVStore(zap.NewNop(), boltPath)
+	if err := store.Open(ctx); err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	svc := kv.NewService(zap.NewNop(), store)
+	org, err := svc.FindOrganizationByName(ctx, restoreFlags.org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organization %q in backup: %v", restoreFlags.org, err)
+	}
+	b, err := svc.FindBucketByName(ctx, org.ID, restoreFlags.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bucket %q in backup: %v", restoreFlags.bucket, err)
+	}
+	return b, nil
+}
+
+// stageFiles links or copies the files of type typ of the backup into dir,
+// returning their new locations.
+func stageFiles(chain backupChain, typ influxdb.BackupFileType, dir string) ([]string, error) {
+	var paths []string
+	for _, file := range chain.Manifest().FilesOfType(typ) {
+		src, err := chain.Locate(file)
+		if err != nil {
+			return nil, err
+		}
+
+		dst := filepath.Join(dir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			return nil, err
+		}
+		if err := os.Link(src, dst); err != nil {
+			if err := pfs.CopyFile(src, dst); err != nil {
+				return nil, err
+			}
+		}
+		paths = append(paths, dst)
+	}
+	return paths, nil
+}
+
+// restoreTSMFile writes all values in the TSM file at path with keys starting
+// with prefix to w.
+func restoreTSMFile(path string, prefix []byte, deletes walDeletes, w *bucketWriter) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	defer r.Close()
+
+	var tombstones []tsm1.TimeRange
+	iter := r.Iterator(prefix)
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+
+		values, err := r.ReadAll(key)
+		if err != nil {
+			return err
+		}
+
+		// Every delete in the WAL happened after the values in the TSM
+		// files were written.
+		tombstones = r.TombstoneRange(key, tombstones[:0])
+		if err := w.writeValues(key, values, func(ts int64) bool {
+			return inTimeRanges(ts, tombstones) || deletes.deleted(key, ts, 0)
+		}); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// restoreWAL writes all values in the WAL segments at paths with keys starting
+// with prefix to w.
+func restoreWAL(paths []string, prefix []byte, deletes walDeletes, w *bucketWriter) error {
+	var seq int
+	return wal.NewWALReader(paths).Read(func(entry wal.WALEntry) error {
+		seq++
+		en, ok := entry.(*wal.WriteWALEntry)
+		if !ok {
+			return nil
+		}
+
+		for k, values := range en.Values {
+			key := []byte(k)
+			if !bytes.HasPrefix(key, prefix) {
+				continue
+			}
+			if err := w.writeValues(key, values, func(ts int64) bool {
+				return deletes.deleted(key, ts, seq)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// walDelete is a delete of a time range of a bucket, recorded in the WAL.
+type walDelete struct {
+	seq      int // Position of the delete within the WAL.
+	min, max int64
+	pred     tsm1.Predicate
+}
+
+type walDeletes []walDelete
+
+// readWALDeletes returns the deletes of the bucket recorded in the WAL
+// segments at paths.
+func readWALDeletes(paths []string, orgID, bucketID influxdb.ID) (walDeletes, error) {
+	var deletes walDeletes
+	var seq int
+	err := wal.NewWALReader(paths).Read(func(entry wal.WALEntry) error {
+		seq++
+		en, ok := entry.(*wal.DeleteBucketRangeWALEntry)
+		if !ok || en.OrgID != orgID || en.BucketID != bucketID {
+			return nil
+		}
+
+		d := walDelete{seq: seq, min: en.Min, max: en.Max}
+		if len(en.Predicate) > 0 {
+			pred, err := tsm1.UnmarshalPredicate(en.Predicate)
+			if err != nil {
+				return err
+			}
+			d.pred = pred
+		}
+		deletes = append(deletes, d)
+		return nil
+	})
+	return deletes, err
+}
+
+// deleted returns true if the value of key at ts, written at position seq of
+// the WAL, is removed by a later delete.
+func (a walDeletes) deleted(key []byte, ts int64, seq int) bool {
+	for _, d := range a {
+		if d.seq > seq && ts >= d.min && ts <= d.max && (d.pred == nil || d.pred.Matches(key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// bucketWriter converts TSM values to line protocol and writes them to a
+// bucket in batches.
+type bucketWriter struct {
+	ctx    context.Context
+	svc    *http.WriteService
+	orgID  influxdb.ID
+	bucket influxdb.ID
+
+	buf    bytes.Buffer
+	n      int
+	points int
+}
+
+// writeValues writes the values of the series and field in key for which
+// deleted returns false.
+func (w *bucketWriter) writeValues(key []byte, values []tsm1.Value, deleted func(ts int64) bool) error {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	_, tags := models.ParseKeyBytes(seriesKey)
+
+	// The measurement and field are stored as tags of the series key and
+	// must be removed before the points can be written again.
+	measurement := string(tags.Get(models.MeasurementTagKeyBytes))
+	tags.Delete(models.MeasurementTagKeyBytes)
+	tags.Delete(models.FieldKeyTagKeyBytes)
+
+	for _, v := range values {
+		if deleted(v.UnixNano()) {
+			continue
+		}
+
+		pt, err := models.NewPoint(measurement, tags, models.Fields{string(field): v.Value()}, time.Unix(0, v.UnixNano()))
+		if err != nil {
+			return err
+		}
+		w.buf.WriteString(pt.String())
+		w.buf.WriteByte('\n')
+
+		w.n++
+		if w.n >= restoreBatchSize {
+			if err := w.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *bucketWriter) flush() error {
+	if w.n == 0 {
+		return nil
+	}
+	if err := w.svc.Write(w.ctx, w.orgID, w.bucket, &w.buf); err != nil {
+		return err
+	}
+	w.points += w.n
+	w.n = 0
+	w.buf.Reset()
+	return nil
+}
+
+// inTimeRanges returns true if ts is within any of the time ranges.
+func inTimeRanges(ts int64, tombstones []tsm1.TimeRange) bool {
+	for _, tr := range tombstones {
+		if ts >= tr.Min && ts <= tr.Max {
+			return true
+		}
+	}
+	return false
+}
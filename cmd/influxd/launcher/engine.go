@@ -2,6 +2,7 @@ package launcher
 
 import (
 	"context"
+	"io"
 	"io/ioutil"
 	"os"
 	"sync"
@@ -25,6 +26,7 @@ var _ Engine = (*storage.Engine)(nil)
 // to facilitate testing.
 type Engine interface {
 	influxdb.DeleteService
+	influxdb.BackupService
 	readservice.Viewer
 	storage.PointsWriter
 	storage.BucketDeleter
@@ -124,6 +126,21 @@ func (t *TemporaryEngine) DeleteBucket(ctx context.Context, orgID, bucketID infl
 	return t.engine.DeleteBucket(ctx, orgID, bucketID)
 }
 
+// CreateBackup creates a snapshot of the underlying engine.
+func (t *TemporaryEngine) CreateBackup(ctx context.Context, opts influxdb.BackupOptions) (*influxdb.BackupManifest, error) {
+	return t.engine.CreateBackup(ctx, opts)
+}
+
+// FetchBackupFile writes a file from a snapshot of the underlying engine to w.
+func (t *TemporaryEngine) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	return t.engine.FetchBackupFile(ctx, backupID, backupFile, w)
+}
+
+// DeleteBackup removes a snapshot of the underlying engine.
+func (t *TemporaryEngine) DeleteBackup(ctx context.Context, backupID int) error {
+	return t.engine.DeleteBackup(ctx, backupID)
+}
+
 // WithLogger sets the logger on the engine. It must be called before Open.
 func (t *TemporaryEngine) WithLogger(log *zap.Logger) {
 	t.log = log.With(zap.String("service", "temporary_engine"))
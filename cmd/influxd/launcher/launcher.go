@@ -17,6 +17,7 @@ import (
 
 	"github.com/influxdata/flux"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/alert"
 	"github.com/influxdata/influxdb/authorizer"
 	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/chronograf/server"
@@ -31,8 +32,10 @@ import (
 	"github.com/influxdata/influxdb/kit/signals"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/ldap"
 	influxlogger "github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/nats"
+	"github.com/influxdata/influxdb/oidc"
 	"github.com/influxdata/influxdb/pkger"
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/query"
@@ -50,7 +53,7 @@ import (
 	"github.com/influxdata/influxdb/task/backend/scheduler"
 	"github.com/influxdata/influxdb/telemetry"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1" // needed for tsi1
-	_ "github.com/influxdata/influxdb/tsdb/tsm1" // needed for tsm1
+	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/influxdata/influxdb/vault"
 	pzap "github.com/influxdata/influxdb/zap"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -173,6 +176,37 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: filepath.Join(dir, "engine"),
 			Desc:    "path to persistent engine files",
 		},
+		{
+			DestP: &l.StorageConfig.Engine.ColdTier.Path,
+			Flag:  "storage-cold-tier-path",
+			Desc:  "directory, or s3://bucket/prefix URL, that TSM files older than the cold tier period of their bucket are moved to",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.ColdTier.S3.Endpoint,
+			Flag:  "storage-cold-tier-s3-endpoint",
+			Desc:  "URL of the S3 compatible object store holding the cold tier, for example: http://localhost:9000",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.ColdTier.S3.Region,
+			Flag:  "storage-cold-tier-s3-region",
+			Desc:  "region of the bucket holding the cold tier. The default value is us-east-1.",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.ColdTier.S3.AccessKeyID,
+			Flag:  "storage-cold-tier-s3-access-key-id",
+			Desc:  "access key id of the S3 compatible object store holding the cold tier",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.ColdTier.S3.SecretAccessKey,
+			Flag:  "storage-cold-tier-s3-secret-access-key",
+			Desc:  "secret access key of the S3 compatible object store holding the cold tier",
+		},
+		{
+			DestP:   &l.StorageConfig.Engine.BlockStats,
+			Flag:    "storage-tsm-block-stats",
+			Default: false,
+			Desc:    "store the count, sum, min and max of the values of TSM blocks in the index of the files to answer window aggregates without decoding the blocks",
+		},
 		{
 			DestP:   &l.secretStore,
 			Flag:    "secret-store",
@@ -197,6 +231,129 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP: &l.oidcConfig.Issuer,
+			Flag:  "oidc-issuer",
+			Desc:  "issuer URL of the OpenID Connect provider; enables the single sign-on when set",
+		},
+		{
+			DestP: &l.oidcConfig.ClientID,
+			Flag:  "oidc-client-id",
+			Desc:  "client ID of influxd at the OpenID Connect provider",
+		},
+		{
+			DestP: &l.oidcConfig.ClientSecret,
+			Flag:  "oidc-client-secret",
+			Desc:  "client secret of influxd at the OpenID Connect provider",
+		},
+		{
+			DestP: &l.oidcConfig.RedirectURL,
+			Flag:  "oidc-redirect-url",
+			Desc:  "URL of the /api/v2/signin/oidc/callback route the OpenID Connect provider redirects to, for example: https://influxdb.example.com/api/v2/signin/oidc/callback",
+		},
+		{
+			DestP: &l.oidcConfig.Scopes,
+			Flag:  "oidc-scopes",
+			Desc:  "scopes requested from the OpenID Connect provider (default openid, email and profile)",
+		},
+		{
+			DestP:   &l.oidcConfig.UsernameClaim,
+			Flag:    "oidc-username-claim",
+			Default: oidc.DefaultUsernameClaim,
+			Desc:    "claim of the ID token holding the name of the user; the email claim requires email_verified",
+		},
+		{
+			DestP: &l.oidcConfig.OrgsClaim,
+			Flag:  "oidc-orgs-claim",
+			Desc:  "claim of the ID token holding the names of the organizations the user is added to as a member",
+		},
+		{
+			DestP:   &l.oidcConfig.AutoProvision,
+			Flag:    "oidc-auto-provision",
+			Default: false,
+			Desc:    "creates the users signing in through the OpenID Connect provider who do not exist yet",
+		},
+		{
+			DestP: &l.oidcConfig.Audience,
+			Flag:  "oidc-audience",
+			Desc:  "audience of the provider-issued tokens accepted by the API (default the client ID)",
+		},
+		{
+			DestP: &l.ldapConfig.URL,
+			Flag:  "ldap-url",
+			Desc:  "ldap:// or ldaps:// URL of the LDAP server authenticating the users; enables the LDAP authentication when set",
+		},
+		{
+			DestP:   &l.ldapConfig.StartTLS,
+			Flag:    "ldap-start-tls",
+			Default: false,
+			Desc:    "upgrades the ldap:// connections to TLS with StartTLS before binding",
+		},
+		{
+			DestP: &l.ldapConfig.CACert,
+			Flag:  "ldap-ca-cert",
+			Desc:  "path to the PEM-encoded certificates verifying the certificate of the LDAP server; the system ones are used if empty",
+		},
+		{
+			DestP:   &l.ldapConfig.InsecureSkipVerify,
+			Flag:    "ldap-insecure-skip-verify",
+			Default: false,
+			Desc:    "do not verify the certificate of the LDAP server. Setting this variable is not recommended.",
+		},
+		{
+			DestP:   &l.ldapConfig.Timeout,
+			Flag:    "ldap-timeout",
+			Default: ldap.DefaultTimeout,
+			Desc:    "timeout of the operations with the LDAP server",
+		},
+		{
+			DestP: &l.ldapConfig.BindDN,
+			Flag:  "ldap-bind-dn",
+			Desc:  "DN searching the users and groups; the search is anonymous if empty",
+		},
+		{
+			DestP: &l.ldapConfig.BindPassword,
+			Flag:  "ldap-bind-password",
+			Desc:  "password of the ldap-bind-dn",
+		},
+		{
+			DestP: &l.ldapConfig.UserSearchBase,
+			Flag:  "ldap-user-search-base",
+			Desc:  "base DN of the LDAP users, for example: ou=people,dc=example,dc=com",
+		},
+		{
+			DestP:   &l.ldapConfig.UserFilter,
+			Flag:    "ldap-user-filter",
+			Default: ldap.DefaultUserFilter,
+			Desc:    "filter finding the LDAP user with the username, which replaces its %s",
+		},
+		{
+			DestP: &l.ldapConfig.GroupSearchBase,
+			Flag:  "ldap-group-search-base",
+			Desc:  "base DN of the LDAP groups, for example: ou=groups,dc=example,dc=com",
+		},
+		{
+			DestP:   &l.ldapConfig.GroupFilter,
+			Flag:    "ldap-group-filter",
+			Default: ldap.DefaultGroupFilter,
+			Desc:    "filter finding the LDAP groups of the user with the DN, which replaces its %s",
+		},
+		{
+			DestP:   &l.ldapConfig.GroupNameAttribute,
+			Flag:    "ldap-group-name-attribute",
+			Default: ldap.DefaultGroupNameAttribute,
+			Desc:    "attribute naming the LDAP groups in the group mappings",
+		},
+		{
+			DestP: &l.ldapGroupMappings,
+			Flag:  "ldap-group-mappings",
+			Desc:  "roles of the members of the LDAP groups on the organizations, synced on each sign in, as group=org:role with role owner or member",
+		},
+		{
+			DestP: &l.ldapConfig.LinkedUsers,
+			Flag:  "ldap-linked-users",
+			Desc:  "names of the existing users not created through LDAP that the LDAP users of the same name sign in as",
+		},
 		{
 			DestP: &vaultConfig.Address,
 			Flag:  "vault-addr",
@@ -265,6 +422,18 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "feature flag that enables using the new treescheduler",
 		},
+		{
+			DestP:   &l.taskLeases,
+			Flag:    "task-leases",
+			Default: false,
+			Desc:    "split the tasks with the other influxd processes sharing the metadata store, using leases. Requires the new treescheduler",
+		},
+		{
+			DestP:   &l.taskLeaseTTL,
+			Flag:    "task-lease-ttl",
+			Default: coordinator.DefaultLeaseTTL,
+			Desc:    "duration after which the tasks of an influxd process that stopped renewing their leases are taken over by the other processes",
+		},
 	}
 
 	cli.BindOptions(cmd, opts)
@@ -283,6 +452,9 @@ type Launcher struct {
 	testing              bool
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
+	oidcConfig           oidc.Config
+	ldapConfig           ldap.Config
+	ldapGroupMappings    []string
 
 	logLevel          string
 	tracingType       string
@@ -293,10 +465,12 @@ type Launcher struct {
 	enginePath      string
 	secretStore     string
 
-	boltClient    *bolt.Client
-	kvService     *kv.Service
-	engine        Engine
-	StorageConfig storage.Config
+	boltClient      *bolt.Client
+	kvStore         kv.Store
+	kvService       *kv.Service
+	kvBackupService platform.KVBackupService
+	engine          Engine
+	StorageConfig   storage.Config
 
 	queryController *control.Controller
 
@@ -309,6 +483,8 @@ type Launcher struct {
 	natsPort   int
 
 	EnableNewScheduler bool
+	taskLeases         bool
+	taskLeaseTTL       time.Duration
 	scheduler          *taskbackend.TickScheduler
 	treeScheduler      *scheduler.TreeScheduler
 	taskControlService taskbackend.TaskControlService
@@ -496,12 +672,15 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	case BoltStore:
 		store := bolt.NewKVStore(m.log.With(zap.String("service", "kvstore-bolt")), m.boltPath)
 		store.WithDB(m.boltClient.DB())
+		m.kvStore = store
 		m.kvService = kv.NewService(m.log.With(zap.String("store", "kv")), store, serviceConfig)
+		m.kvBackupService = store
 		if m.testing {
 			flushers = append(flushers, store)
 		}
 	case MemoryStore:
 		store := inmem.NewKVStore()
+		m.kvStore = store
 		m.kvService = kv.NewService(m.log.With(zap.String("store", "kv")), store, serviceConfig)
 		if m.testing {
 			flushers = append(flushers, store)
@@ -546,6 +725,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		secretSvc                 platform.SecretService                   = m.kvService
 		lookupSvc                 platform.LookupService                   = m.kvService
 		notificationEndpointStore platform.NotificationEndpointService     = m.kvService
+		dbrpMappingSvc            platform.DBRPMappingService              = m.kvService
 	)
 
 	switch m.secretStore {
@@ -572,13 +752,24 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	coldStore, err := tsm1.NewColdStore(m.StorageConfig.Engine.ColdTier)
+	if err != nil {
+		m.log.Error("Failed creating cold tier store", zap.Error(err))
+		return err
+	}
+	var engineOpts []storage.Option
+	if coldStore != nil {
+		engineOpts = append(engineOpts, storage.WithColdStore(coldStore))
+	}
+	engineOpts = append(engineOpts, storage.WithRetentionEnforcer(bucketSvc))
+
 	if m.testing {
 		// the testing engine will write/read into a temporary directory
-		engine := NewTemporaryEngine(m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		engine := NewTemporaryEngine(m.StorageConfig, engineOpts...)
 		flushers = append(flushers, engine)
 		m.engine = engine
 	} else {
-		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, engineOpts...)
 	}
 	m.engine.WithLogger(m.log)
 	if err := m.engine.Open(ctx); err != nil {
@@ -629,7 +820,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.reg.MustRegister(m.queryController.PrometheusCollectors()...)
 
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
-	var taskSvc platform.TaskService
+	var (
+		taskSvc platform.TaskService
+		// coordinatedTaskSvc is the task service without the authorization
+		// checks, for the services managing their own tasks.
+		coordinatedTaskSvc platform.TaskService
+	)
 	{
 		// create the task stack:
 		// validation(coordinator(analyticalstore(kv.Service)))
@@ -645,9 +841,21 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			m.reg.MustRegister(executorMetrics.PrometheusCollectors()...)
 			schLogger := m.log.With(zap.String("service", "task-scheduler"))
 
+			var (
+				schExecutor  scheduler.Executor           = executor
+				checkpointer scheduler.SchedulableService = taskbackend.NewSchedulableTaskService(m.kvService)
+				leases       *coordinator.Leases
+			)
+			if m.taskLeases {
+				leases = coordinator.NewLeases(m.kvStore, snowflake.NewIDGenerator().ID(), coordinator.WithLeaseTTL(m.taskLeaseTTL))
+				schExecutor = leases.Executor(schExecutor)
+				checkpointer = leases.SchedulableService(checkpointer)
+				executor.SetHeldFunc(leases.Held)
+			}
+
 			sch, sm, err := scheduler.NewScheduler(
-				executor,
-				taskbackend.NewSchedulableTaskService(m.kvService),
+				schExecutor,
+				checkpointer,
 				scheduler.WithOnErrorFn(func(ctx context.Context, taskID scheduler.ID, scheduledFor time.Time, err error) {
 					schLogger.Info(
 						"error in scheduler run",
@@ -661,13 +869,22 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			}
 			m.treeScheduler = sch
 			m.reg.MustRegister(sm.PrometheusCollectors()...)
+			// trigger the runs of the tasks depending on the task of a completed run.
+			executor.SetCompletedFunc(sch.Completed)
 			coordLogger := m.log.With(zap.String("service", "task-coordinator"))
+			var taskSch scheduler.Scheduler = sch
+			if leases != nil {
+				leaseSch := coordinator.NewLeaseScheduler(m.log.With(zap.String("service", "task-leases")), leases, sch, combinedTaskService)
+				go leaseSch.Run(ctx)
+				taskSch = leaseSch
+			}
 			taskCoord := coordinator.NewCoordinator(
 				coordLogger,
-				sch,
+				taskSch,
 				executor)
 
 			taskSvc = middleware.New(combinedTaskService, taskCoord)
+			coordinatedTaskSvc = taskSvc
 			m.taskControlService = combinedTaskService
 			if err := taskbackend.TaskNotifyCoordinatorOfExisting(
 				ctx,
@@ -675,6 +892,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 				combinedTaskService,
 				taskCoord,
 				func(ctx context.Context, taskID platform.ID, runID platform.ID) error {
+					if leases != nil {
+						// the runs of the tasks leased to other processes are resumed by them.
+						if held, err := leases.Held(ctx, taskID); err != nil || !held {
+							return err
+						}
+					}
 					_, err := executor.ResumeCurrentRun(ctx, taskID, runID)
 					return err
 				},
@@ -700,6 +923,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			}
 
 			taskSvc = middleware.New(combinedTaskService, coordinator)
+			coordinatedTaskSvc = taskSvc
 			taskSvc = authorizer.NewTaskService(m.log.With(zap.String("service", "task-authz-validator")), taskSvc)
 			m.taskControlService = combinedTaskService
 		}
@@ -718,6 +942,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		notificationRuleSvc = middleware.NewNotificationRuleStore(m.kvService, m.kvService, coordinator)
 	}
 
+	{
+		coordinator := coordinator.New(m.log, m.scheduler)
+		bucketSvc = middleware.NewBucketService(bucketSvc, m.kvService, coordinator)
+		deleteService = middleware.NewDeleteService(deleteService, bucketSvc, coordinatedTaskSvc)
+	}
+
 	// NATS streaming server
 	natsOpts := nats.NewDefaultServerOptions()
 	nextPort := int64(4222)
@@ -788,6 +1018,27 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		Addr: m.httpBindAddress,
 	}
 
+	var oidcSvc *oidc.Service
+	if m.oidcConfig.Issuer != "" {
+		oidcSvc, err = oidc.NewService(ctx, m.log.With(zap.String("service", "oidc")), m.oidcConfig, userSvc, orgSvc, userResourceSvc, authSvc)
+		if err != nil {
+			m.log.Error("Failed to initialize OpenID Connect single sign-on", zap.Error(err))
+			return err
+		}
+	}
+
+	var authenticator platform.Authenticator
+	if m.ldapConfig.URL != "" {
+		ldapSvc, err := m.newLDAPService(userSvc, orgSvc, userResourceSvc)
+		if err != nil {
+			m.log.Error("Failed to initialize LDAP authentication", zap.Error(err))
+			return err
+		}
+		// the local users are authenticated first, the ones of the
+		// directory have no local password.
+		authenticator = platform.AuthenticatorChain{m.kvService, ldapSvc}
+	}
+
 	m.apibackend = &http.APIBackend{
 		AssetsPath:           m.assetsPath,
 		HTTPErrorHandler:     http.ErrorHandler(0),
@@ -796,11 +1047,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
+		ReadStore:            readservice.NewStore(m.engine),
 		DeleteService:        deleteService,
 		AuthorizationService: authSvc,
+		BackupService:        m.engine,
+		KVBackupService:      m.kvBackupService,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
 		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		DBRPMappingService:              dbrpMappingSvc,
 		SessionService:                  sessionSvc,
+		OIDCService:                     oidcSvc,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
 		UserResourceMappingService:      userResourceSvc,
@@ -813,6 +1069,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		SourceService:                   sourceSvc,
 		VariableService:                 variableSvc,
 		PasswordsService:                passwdsSvc,
+		Authenticator:                   authenticator,
 		OnboardingService:               onboardingSvc,
 		InfluxQLService:                 nil, // No InfluxQL support
 		FluxService:                     storageQueryService,
@@ -820,6 +1077,9 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
 		NotificationEndpointService:     endpoints.NewService(notificationEndpointStore, secretSvc, userResourceSvc, orgSvc),
+		SilenceService:                  m.kvService,
+		RoleService:                     m.kvService,
+		AlertService:                    alert.NewService(m.log.With(zap.String("service", "alert")), m.kvService, m.kvService, query.QueryServiceBridge{AsyncQueryService: m.queryController}),
 		CheckService:                    checkSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
@@ -925,6 +1185,20 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	return nil
 }
 
+// newLDAPService returns the LDAP authentication service of the ldap flags.
+func (m *Launcher) newLDAPService(us platform.UserService, os platform.OrganizationService, urms platform.UserResourceMappingService) (*ldap.Service, error) {
+	cfg := m.ldapConfig
+	cfg.GroupMappings = nil
+	for _, s := range m.ldapGroupMappings {
+		gm, err := ldap.ParseGroupMapping(s)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GroupMappings = append(cfg.GroupMappings, gm)
+	}
+	return ldap.NewService(m.log.With(zap.String("service", "ldap")), cfg, us, os, urms)
+}
+
 // OrganizationService returns the internal organization service.
 func (m *Launcher) OrganizationService() platform.OrganizationService {
 	return m.apibackend.OrganizationService
@@ -105,6 +105,52 @@ func TestPipeline_WriteV2_Query(t *testing.T) {
 	res.HasTableCount(t, 1)
 }
 
+// This test initializes a default launcher with a bucket named after a database and
+// retention policy, and checks that the data written with the 1.x /write endpoint
+// is returned by the 1.x /query endpoint.
+func TestPipeline_V1_Write_Query(t *testing.T) {
+	be := launcher.RunTestLauncherOrFail(t, ctx)
+	defer be.ShutdownOrFail(t, ctx)
+	results := be.OnBoardOrFail(t, &influxdb.OnboardingRequest{
+		User:     "USER",
+		Password: "PASSWORD",
+		Org:      "ORG",
+		Bucket:   "db0/autogen",
+	})
+
+	do := func(method, rawurl, body string) string {
+		t.Helper()
+		req, err := nethttp.NewRequest(method, be.URL()+rawurl, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.SetBasicAuth("USER", results.Auth.Token)
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		buf := new(bytes.Buffer)
+		if _, err := io.Copy(buf, resp.Body); err != nil {
+			t.Fatalf("Could not read body: %s", err)
+		}
+		if resp.StatusCode/100 != 2 {
+			t.Fatalf("unexpected status %d, body: %s", resp.StatusCode, buf.String())
+		}
+		return buf.String()
+	}
+
+	now := time.Now().Truncate(time.Second)
+	do("POST", "/write?db=db0&precision=s", fmt.Sprintf("cpu,host=a value=1 %d\ncpu,host=a value=2 %d", now.Unix()-1, now.Unix()))
+
+	got := do("GET", "/query?db=db0&epoch=s&q=SELECT+value+FROM+cpu+WHERE+time+>+now()+-+1m", "")
+	want := fmt.Sprintf(`{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[[%d,1],[%d,2]]}]}]}`+"\n", now.Unix()-1, now.Unix())
+	if got != want {
+		t.Errorf("unexpected response:\ngot  %s\nwant %s", got, want)
+	}
+}
+
 // This test initializes a default launcher; writes some data; queries the data (success);
 // sets memory limits to the same read query; checks that the query fails because limits are exceeded.
 func TestPipeline_QueryMemoryLimits(t *testing.T) {
@@ -4,14 +4,18 @@ import (
 	"fmt"
 	"io/ioutil"
 	nethttp "net/http"
+	"net/url"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influxd/launcher"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/prometheus/prompb"
 	"github.com/influxdata/influxdb/toml"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 )
@@ -91,6 +95,203 @@ func TestLauncher_WriteAndQuery(t *testing.T) {
 	}
 }
 
+func TestLauncher_PromWriteAndRead(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	post := func(path string, m interface{ Marshal() ([]byte, error) }, code int) []byte {
+		t.Helper()
+		data, err := m.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := l.MustNewHTTPRequest("POST", fmt.Sprintf("%s?org=%s&bucket=%s", path, l.Org.ID, l.Bucket.ID), string(snappy.Encode(nil, data)))
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := resp.Body.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != code {
+			t.Fatalf("unexpected status code: %d, body: %s, headers: %v", resp.StatusCode, body, resp.Header)
+		}
+		return body
+	}
+
+	post("/api/v1/prom/write", &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: 946684800000}, {Value: 0, Timestamp: 946684860000}},
+			},
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "web"}},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: 946684800000}},
+			},
+		},
+	}, nethttp.StatusNoContent)
+
+	// Query server to ensure the samples are stored in the value field of the metric.
+	qs := `from(bucket:"BUCKET") |> range(start:2000-01-01T00:00:00Z,stop:2000-01-02T00:00:00Z) |> filter(fn: (r) => r.job == "web")`
+	exp := `,result,table,_start,_stop,_time,_value,_field,_measurement,job` + "\r\n" +
+		`,_result,0,2000-01-01T00:00:00Z,2000-01-02T00:00:00Z,2000-01-01T00:00:00Z,1,value,up,web` + "\r\n\r\n"
+	buf, err := http.SimpleQuery(l.URL(), qs, l.Org.Name, l.Auth.Token)
+	if err != nil {
+		t.Fatalf("unexpected error querying server: %v", err)
+	}
+	if diff := cmp.Diff(string(buf), exp); diff != "" {
+		t.Fatal(diff)
+	}
+
+	body := post("/api/v1/prom/read", &prompb.ReadRequest{
+		Queries: []*prompb.Query{
+			{
+				StartTimestampMs: 946684800000,
+				EndTimestampMs:   946684860000,
+				Matchers: []*prompb.LabelMatcher{
+					{Type: prompb.LabelMatcher_EQ, Name: "__name__", Value: "up"},
+					{Type: prompb.LabelMatcher_RE, Name: "job", Value: "a.*"},
+				},
+			},
+		},
+	}, nethttp.StatusOK)
+
+	data, err := snappy.Decode(nil, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got prompb.ReadResponse
+	if err := got.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	want := prompb.ReadResponse{
+		Results: []*prompb.QueryResult{
+			{
+				Timeseries: []*prompb.TimeSeries{
+					{
+						Labels:  []prompb.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}},
+						Samples: []prompb.Sample{{Value: 1, Timestamp: 946684800000}, {Value: 0, Timestamp: 946684860000}},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("unexpected read response -got/+want\n%s", diff)
+	}
+}
+
+func TestLauncher_PromQL(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	// The samples of up are 1 for api and 0 for web every minute from
+	// 2000-01-01T00:00:00Z, and web stops reporting after two minutes.
+	l.WritePointsOrFail(t, `up,job=api value=1 946684800000000000
+up,job=api value=1 946684860000000000
+up,job=api value=1 946684920000000000
+up,job=api value=1 946684980000000000
+up,job=web value=0 946684800000000000
+up,job=web value=0 946684860000000000
+up,job=web value=0 946684920000000000
+other,job=api value=7 946684800000000000`)
+
+	for _, tt := range []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "instant vector",
+			path: "/api/v1/query?query=up&time=946684890",
+			want: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"up","job":"api"},"value":[946684890,"1"]},{"metric":{"__name__":"up","job":"web"},"value":[946684890,"0"]}]}}`,
+		},
+		{
+			name: "label matchers",
+			path: `/api/v1/query?query=up{job=~"a.*"}&time=946684890`,
+			want: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"up","job":"api"},"value":[946684890,"1"]}]}}`,
+		},
+		{
+			name: "range vector",
+			path: "/api/v1/query?query=up{job=\"web\"}[2m]&time=946684920",
+			want: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up","job":"web"},"values":[[946684860,"0"],[946684920,"0"]]}]}}`,
+		},
+		{
+			name: "range query",
+			path: "/api/v1/query_range?query=up&start=946684800&end=946685400&step=300",
+			want: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up","job":"api"},"values":[[946684800,"1"],[946685100,"1"]]},{"metric":{"__name__":"up","job":"web"},"values":[[946684800,"0"],[946685100,"0"]]}]}}`,
+		},
+		{
+			name: "offset",
+			path: "/api/v1/query_range?query=up offset 1m&start=946684860&end=946684920&step=60",
+			want: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up","job":"api"},"values":[[946684860,"1"],[946684920,"1"]]},{"metric":{"__name__":"up","job":"web"},"values":[[946684860,"0"],[946684920,"0"]]}]}}`,
+		},
+		{
+			name: "aggregate",
+			path: "/api/v1/query_range?query=count(up)&start=946684800&end=946684980&step=60",
+			want: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[946684800,"2"],[946684860,"2"],[946684920,"2"],[946684980,"2"]]}]}}`,
+		},
+		{
+			name: "aggregate by",
+			path: "/api/v1/query?query=sum(up) by (job)&time=946684800",
+			want: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[946684800,"1"]},{"metric":{"job":"web"},"value":[946684800,"0"]}]}}`,
+		},
+		{
+			name: "aggregate without",
+			path: "/api/v1/query?query=sum without (job) (up)&time=946684800",
+			want: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[946684800,"1"]}]}}`,
+		},
+		{
+			name: "stdvar",
+			path: "/api/v1/query?query=stdvar(up)&time=946684800",
+			want: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[946684800,"0.25"]}]}}`,
+		},
+		{
+			name: "topk",
+			path: "/api/v1/query?query=topk(1, up)&time=946684800",
+			want: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"up","job":"api"},"value":[946684800,"1"]}]}}`,
+		},
+		{
+			name: "invalid query",
+			path: "/api/v1/query?query=up{&time=946684800",
+			want: `{"status":"error","errorType":"bad_data","error":"1:4 (3): no match found, expected: [\\pL_]"}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			params := u.Query()
+			params.Set("org", l.Org.ID.String())
+			params.Set("bucket", l.Bucket.ID.String())
+			u.RawQuery = params.Encode()
+
+			resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("GET", u.String(), ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := resp.Body.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := strings.TrimSpace(string(body)), tt.want; got != want {
+				t.Errorf("unexpected response -got/+want\n%s", cmp.Diff(got, want))
+			}
+		})
+	}
+}
+
 func TestLauncher_BucketDelete(t *testing.T) {
 	l := launcher.RunTestLauncherOrFail(t, ctx)
 	l.SetupOrFail(t)
@@ -0,0 +1,212 @@
+package influxdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// TaskTypeDownsample is the type of the tasks that materialize the
+// downsample policies of buckets.
+const TaskTypeDownsample = "downsample"
+
+// DownsampleFluxPackage is the Flux package providing the functions used by
+// the downsample tasks.
+const DownsampleFluxPackage = "influxdata/influxdb/downsample"
+
+// Field types that aggregates can be configured for.
+const (
+	DownsampleFieldTypeFloat    = "float"
+	DownsampleFieldTypeInteger  = "integer"
+	DownsampleFieldTypeUnsigned = "unsigned"
+	DownsampleFieldTypeBoolean  = "boolean"
+	DownsampleFieldTypeString   = "string"
+)
+
+var (
+	numericDownsampleAggregates = []string{"count", "first", "last", "max", "mean", "median", "min", "spread", "sum"}
+	otherDownsampleAggregates   = []string{"count", "first", "last"}
+)
+
+// DownsamplePolicy turns the bucket it is attached to into a continuous
+// aggregate of a source bucket. The data of the source bucket is aggregated
+// into windows of a fixed duration by a task that is managed along with the
+// bucket, using the aggregate configured for the type of each field.
+type DownsamplePolicy struct {
+	SourceBucketID ID                   `json:"sourceBucketID,omitempty"`
+	Every          Duration             `json:"every"`
+	Lookback       Duration             `json:"lookback,omitempty"`
+	Offset         Duration             `json:"offset,omitempty"`
+	Aggregates     DownsampleAggregates `json:"aggregates"`
+	// TaskID is the task that materializes the policy. It is managed by
+	// the bucket service and cannot be set.
+	TaskID ID `json:"taskID,omitempty"`
+}
+
+// DownsampleAggregates are the names of the aggregate functions used for the
+// fields of each type. Fields of a type without an aggregate are not
+// copied to the downsampled bucket.
+type DownsampleAggregates struct {
+	Float    string `json:"float,omitempty"`
+	Integer  string `json:"integer,omitempty"`
+	Unsigned string `json:"unsigned,omitempty"`
+	Boolean  string `json:"boolean,omitempty"`
+	String   string `json:"string,omitempty"`
+}
+
+type downsampleAggregate struct {
+	fieldType string
+	fn        string
+	allowed   []string
+}
+
+func (a DownsampleAggregates) list() []downsampleAggregate {
+	return []downsampleAggregate{
+		{fieldType: DownsampleFieldTypeFloat, fn: a.Float, allowed: numericDownsampleAggregates},
+		{fieldType: DownsampleFieldTypeInteger, fn: a.Integer, allowed: numericDownsampleAggregates},
+		{fieldType: DownsampleFieldTypeUnsigned, fn: a.Unsigned, allowed: numericDownsampleAggregates},
+		{fieldType: DownsampleFieldTypeBoolean, fn: a.Boolean, allowed: otherDownsampleAggregates},
+		{fieldType: DownsampleFieldTypeString, fn: a.String, allowed: otherDownsampleAggregates},
+	}
+}
+
+// IsZero returns true if the policy is empty. An empty policy is used to
+// remove the policy of a bucket.
+func (p DownsamplePolicy) IsZero() bool {
+	return p == DownsamplePolicy{}
+}
+
+// EffectiveLookback returns how far back each run of the task recomputes
+// windows. It defaults to a single window.
+func (p DownsamplePolicy) EffectiveLookback() time.Duration {
+	if p.Lookback.Duration == 0 {
+		return p.Every.Duration
+	}
+	return p.Lookback.Duration
+}
+
+// Valid returns an error if the policy is invalid.
+func (p DownsamplePolicy) Valid() error {
+	if !p.SourceBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample policy requires a source bucket",
+		}
+	}
+	if p.Every.Duration < time.Second || p.Every.Duration%time.Second != 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample every must be a whole number of seconds",
+		}
+	}
+	if lb := p.Lookback.Duration; lb < 0 || lb%p.Every.Duration != 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample lookback must be a multiple of every",
+		}
+	}
+	if off := p.Offset.Duration; off < 0 || off%time.Second != 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample offset must be a whole number of seconds",
+		}
+	}
+
+	var n int
+	for _, agg := range p.Aggregates.list() {
+		if agg.fn == "" {
+			continue
+		}
+		if !containsString(agg.allowed, agg.fn) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid aggregate %q for %s fields; must be one of %v", agg.fn, agg.fieldType, agg.allowed),
+			}
+		}
+		n++
+	}
+	if n == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample policy requires at least one aggregate",
+		}
+	}
+	return nil
+}
+
+// GenerateFlux returns the script of the task that materializes the policy of
+// the bucket b. Every run aggregates the windows within the lookback that
+// ends at the time it is scheduled for, so that windows that received late
+// writes are computed again.
+func (p DownsamplePolicy) GenerateFlux(b *Bucket) (string, error) {
+	if err := p.Valid(); err != nil {
+		return "", err
+	}
+
+	taskOpts := []*ast.Property{
+		flux.Property("name", flux.String(fmt.Sprintf("Downsample %s", b.Name))),
+		flux.Property("every", durationLiteral(p.Every.Duration)),
+	}
+	if p.Offset.Duration > 0 {
+		taskOpts = append(taskOpts, flux.Property("offset", durationLiteral(p.Offset.Duration)))
+	}
+
+	body := []ast.Statement{
+		flux.DefineTaskOption(flux.Object(taskOpts...)),
+		flux.DefineVariable("data", flux.Pipe(
+			flux.Call(flux.Identifier("from"), flux.Object(
+				flux.Property("bucketID", flux.String(p.SourceBucketID.String())),
+			)),
+			flux.Call(flux.Identifier("range"), flux.Object(
+				flux.Property("start", flux.Negative(durationLiteral(p.EffectiveLookback()))),
+			)),
+		)),
+	}
+
+	for _, agg := range p.Aggregates.list() {
+		if agg.fn == "" {
+			continue
+		}
+		body = append(body, flux.ExpressionStatement(flux.Pipe(
+			flux.Identifier("data"),
+			flux.Call(flux.Member("downsample", "fieldType"), flux.Object(
+				flux.Property("type", flux.String(agg.fieldType)),
+			)),
+			flux.Call(flux.Identifier("aggregateWindow"), flux.Object(
+				flux.Property("every", durationLiteral(p.Every.Duration)),
+				flux.Property("fn", flux.Identifier(agg.fn)),
+				flux.Property("createEmpty", flux.Bool(false)),
+			)),
+			flux.Call(flux.Identifier("to"), flux.Object(
+				flux.Property("bucketID", flux.String(b.ID.String())),
+				flux.Property("orgID", flux.String(b.OrgID.String())),
+			)),
+		)))
+	}
+
+	return ast.Format(flux.File("", flux.Imports(DownsampleFluxPackage), body)), nil
+}
+
+// durationLiteral returns a Flux duration literal for d, using the largest
+// unit that d is a multiple of.
+func durationLiteral(d time.Duration) *ast.DurationLiteral {
+	switch {
+	case d%time.Hour == 0:
+		return flux.Duration(int64(d/time.Hour), "h")
+	case d%time.Minute == 0:
+		return flux.Duration(int64(d/time.Minute), "m")
+	default:
+		return flux.Duration(int64(d/time.Second), "s")
+	}
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
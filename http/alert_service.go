@@ -0,0 +1,469 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixAlerts    = "/api/v2/alerts"
+	prefixAlertAcks = "/api/v2/alerts/acks"
+)
+
+// AlertBackend is all services and associated parameters required to construct
+// the AlertHandler.
+type AlertBackend struct {
+	influxdb.HTTPErrorHandler
+	log          *zap.Logger
+	AlertService influxdb.AlertService
+}
+
+// NewAlertBackend creates a backend used by the alert handler.
+func NewAlertBackend(log *zap.Logger, b *APIBackend) *AlertBackend {
+	return &AlertBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		AlertService:     b.AlertService,
+	}
+}
+
+// AlertHandler is the handler for the alert service
+type AlertHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	AlertService influxdb.AlertService
+}
+
+// NewAlertHandler creates a new AlertHandler
+func NewAlertHandler(log *zap.Logger, b *AlertBackend) *AlertHandler {
+	h := &AlertHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		AlertService: b.AlertService,
+	}
+
+	ackPath := fmt.Sprintf("%s/:id", prefixAlertAcks)
+
+	h.HandlerFunc("GET", prefixAlerts, h.handleGetAlerts)
+	h.HandlerFunc("GET", prefixAlertAcks, h.handleGetAlertAcks)
+	h.HandlerFunc("POST", prefixAlertAcks, h.handlePostAlertAck)
+	h.HandlerFunc("GET", ackPath, h.handleGetAlertAck)
+	h.HandlerFunc("DELETE", ackPath, h.handleDeleteAlertAck)
+
+	return h
+}
+
+type alertLinks struct {
+	Check string `json:"check"`
+	Org   string `json:"org"`
+}
+
+type alertResponse struct {
+	*influxdb.Alert
+	Links alertLinks `json:"links"`
+}
+
+func newAlertResponse(a *influxdb.Alert) alertResponse {
+	return alertResponse{
+		Alert: a,
+		Links: alertLinks{
+			Check: fmt.Sprintf("/api/v2/checks/%s", a.CheckID),
+			Org:   fmt.Sprintf("/api/v2/orgs/%s", a.OrgID),
+		},
+	}
+}
+
+type getAlertsResponse struct {
+	Alerts []alertResponse   `json:"alerts"`
+	Links  map[string]string `json:"links"`
+}
+
+func (r getAlertsResponse) toInfluxDB() []*influxdb.Alert {
+	alerts := make([]*influxdb.Alert, len(r.Alerts))
+	for i := range r.Alerts {
+		alerts[i] = r.Alerts[i].Alert
+	}
+	return alerts
+}
+
+func newGetAlertsResponse(alerts []*influxdb.Alert) getAlertsResponse {
+	resp := getAlertsResponse{
+		Alerts: make([]alertResponse, 0, len(alerts)),
+		Links: map[string]string{
+			"self": prefixAlerts,
+		},
+	}
+
+	for _, a := range alerts {
+		resp.Alerts = append(resp.Alerts, newAlertResponse(a))
+	}
+
+	return resp
+}
+
+func decodeGetAlertsRequest(r *http.Request) (*influxdb.AlertFilter, error) {
+	qp := r.URL.Query()
+	orgID := qp.Get("orgID")
+	if orgID == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+
+	id, err := influxdb.IDFromString(orgID)
+	if err != nil {
+		return nil, err
+	}
+	filter := &influxdb.AlertFilter{OrgID: *id}
+
+	if checkID := qp.Get("checkID"); checkID != "" {
+		id, err := influxdb.IDFromString(checkID)
+		if err != nil {
+			return nil, err
+		}
+		filter.CheckID = id
+	}
+
+	if acknowledged := qp.Get("acknowledged"); acknowledged != "" {
+		ack, err := strconv.ParseBool(acknowledged)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "acknowledged must be true or false",
+			}
+		}
+		filter.Acknowledged = &ack
+	}
+
+	return filter, nil
+}
+
+func (h *AlertHandler) handleGetAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := decodeGetAlertsRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	alerts, err := h.AlertService.FindAlerts(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alerts retrieved", zap.String("alerts", fmt.Sprint(alerts)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetAlertsResponse(alerts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type alertAckLinks struct {
+	Self  string `json:"self"`
+	Check string `json:"check"`
+	Org   string `json:"org"`
+}
+
+type alertAckResponse struct {
+	*influxdb.AlertAck
+	Links alertAckLinks `json:"links"`
+}
+
+func newAlertAckResponse(a *influxdb.AlertAck) alertAckResponse {
+	return alertAckResponse{
+		AlertAck: a,
+		Links: alertAckLinks{
+			Self:  fmt.Sprintf("%s/%s", prefixAlertAcks, a.ID),
+			Check: fmt.Sprintf("/api/v2/checks/%s", a.CheckID),
+			Org:   fmt.Sprintf("/api/v2/orgs/%s", a.OrgID),
+		},
+	}
+}
+
+type getAlertAcksResponse struct {
+	Acks  []alertAckResponse    `json:"acks"`
+	Links *influxdb.PagingLinks `json:"links"`
+}
+
+func (r getAlertAcksResponse) toInfluxDB() []*influxdb.AlertAck {
+	acks := make([]*influxdb.AlertAck, len(r.Acks))
+	for i := range r.Acks {
+		acks[i] = r.Acks[i].AlertAck
+	}
+	return acks
+}
+
+func newGetAlertAcksResponse(acks []*influxdb.AlertAck, f influxdb.AlertAckFilter, opts influxdb.FindOptions) getAlertAcksResponse {
+	num := len(acks)
+	resp := getAlertAcksResponse{
+		Acks:  make([]alertAckResponse, 0, num),
+		Links: newPagingLinks(prefixAlertAcks, opts, f, num),
+	}
+
+	for _, a := range acks {
+		resp.Acks = append(resp.Acks, newAlertAckResponse(a))
+	}
+
+	return resp
+}
+
+type getAlertAcksRequest struct {
+	filter influxdb.AlertAckFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetAlertAcksRequest(ctx context.Context, r *http.Request) (*getAlertAcksRequest, error) {
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getAlertAcksRequest{
+		opts: *opts,
+	}
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = id
+	}
+
+	if checkID := qp.Get("checkID"); checkID != "" {
+		id, err := influxdb.IDFromString(checkID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.CheckID = id
+	}
+
+	return req, nil
+}
+
+func (h *AlertHandler) handleGetAlertAcks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetAlertAcksRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	acks, _, err := h.AlertService.FindAlertAcks(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alert acknowledgements retrieved", zap.String("acks", fmt.Sprint(acks)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetAlertAcksResponse(acks, req.filter, req.opts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func requestAlertAckID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *AlertHandler) handleGetAlertAck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestAlertAckID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ack, err := h.AlertService.FindAlertAckByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alert acknowledgement retrieved", zap.String("ack", fmt.Sprint(ack)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newAlertAckResponse(ack)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodePostAlertAckRequest(r *http.Request) (*influxdb.AlertAck, error) {
+	ack := &influxdb.AlertAck{}
+	if err := json.NewDecoder(r.Body).Decode(ack); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}
+	}
+
+	if err := ack.Valid(); err != nil {
+		return nil, err
+	}
+
+	return ack, nil
+}
+
+func (h *AlertHandler) handlePostAlertAck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ack, err := decodePostAlertAckRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	ack.UserID = auth.GetUserID()
+
+	if err := h.AlertService.CreateAlertAck(ctx, ack); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alert acknowledgement created", zap.String("ack", fmt.Sprint(ack)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newAlertAckResponse(ack)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *AlertHandler) handleDeleteAlertAck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestAlertAckID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.AlertService.DeleteAlertAck(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alert acknowledgement deleted", zap.String("ackID", fmt.Sprint(id)))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AlertService is an alert service over HTTP to the influxdb server
+type AlertService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// FindAlerts returns the firing alerts that match filter.
+func (s *AlertService) FindAlerts(ctx context.Context, filter influxdb.AlertFilter) ([]*influxdb.Alert, error) {
+	params := [][2]string{{"orgID", filter.OrgID.String()}}
+	if filter.CheckID != nil {
+		params = append(params, [2]string{"checkID", filter.CheckID.String()})
+	}
+	if filter.Acknowledged != nil {
+		params = append(params, [2]string{"acknowledged", strconv.FormatBool(*filter.Acknowledged)})
+	}
+
+	var resp getAlertsResponse
+	err := s.Client.
+		Get(prefixAlerts).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.toInfluxDB(), nil
+}
+
+// FindAlertAckByID finds a single alert acknowledgement from the store by its ID
+func (s *AlertService) FindAlertAckByID(ctx context.Context, id influxdb.ID) (*influxdb.AlertAck, error) {
+	var resp alertAckResponse
+	err := s.Client.
+		Get(prefixAlertAcks, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.AlertAck, nil
+}
+
+// FindAlertAcks returns a list of alert acknowledgements that match filter.
+// Additional options provide pagination & sorting.
+func (s *AlertService) FindAlertAcks(ctx context.Context, filter influxdb.AlertAckFilter, opts ...influxdb.FindOptions) ([]*influxdb.AlertAck, int, error) {
+	if filter.ID != nil {
+		ack, err := s.FindAlertAckByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*influxdb.AlertAck{ack}, 1, nil
+	}
+
+	params := findOptionParams(opts...)
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.CheckID != nil {
+		params = append(params, [2]string{"checkID", filter.CheckID.String()})
+	}
+
+	var resp getAlertAcksResponse
+	err := s.Client.
+		Get(prefixAlertAcks).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	acks := resp.toInfluxDB()
+	return acks, len(acks), nil
+}
+
+// CreateAlertAck acknowledges an alert and assigns the acknowledgement an influxdb.ID
+func (s *AlertService) CreateAlertAck(ctx context.Context, ack *influxdb.AlertAck) error {
+	if err := ack.Valid(); err != nil {
+		return err
+	}
+
+	return s.Client.
+		PostJSON(ack, prefixAlertAcks).
+		DecodeJSON(ack).
+		Do(ctx)
+}
+
+// DeleteAlertAck removes an alert acknowledgement from the store
+func (s *AlertService) DeleteAlertAck(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(prefixAlertAcks, id.String()).
+		Do(ctx)
+}
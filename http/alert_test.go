@@ -0,0 +1,208 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// NewMockAlertBackend returns an AlertBackend with mock services.
+func NewMockAlertBackend(t *testing.T) *AlertBackend {
+	return &AlertBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		log:              zaptest.NewLogger(t),
+		AlertService:     mock.NewAlertService(),
+	}
+}
+
+func TestAlertService_handleGetAlerts(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		wants wants
+	}{
+		{
+			name:  "get the unacknowledged alerts of an organization",
+			query: "?orgID=0000000000000001&acknowledged=false",
+			wants: wants{
+				statusCode: 200,
+				body: `
+{
+  "alerts": [
+    {
+      "orgID": "0000000000000001",
+      "checkID": "0000000000000002",
+      "checkName": "cpu",
+      "tags": [{"key": "host", "value": "db1"}],
+      "level": "crit",
+      "message": "db1 is down",
+      "time": "2006-05-04T01:02:03Z",
+      "links": {
+        "check": "/api/v2/checks/0000000000000002",
+        "org": "/api/v2/orgs/0000000000000001"
+      }
+    }
+  ],
+  "links": {
+    "self": "/api/v2/alerts"
+  }
+}
+`,
+			},
+		},
+		{
+			name:  "get alerts without organization",
+			query: "?checkID=0000000000000002",
+			wants: wants{
+				statusCode: 400,
+				body:       `{"code":"invalid","message":"orgID is required"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alertBackend := NewMockAlertBackend(t)
+			alertBackend.AlertService = &mock.AlertService{
+				FindAlertsFn: func(ctx context.Context, filter influxdb.AlertFilter) ([]*influxdb.Alert, error) {
+					if filter.Acknowledged == nil || *filter.Acknowledged {
+						t.Errorf("expected a filter of unacknowledged alerts, got %v", filter.Acknowledged)
+					}
+					return []*influxdb.Alert{
+						{
+							OrgID:     filter.OrgID,
+							CheckID:   2,
+							CheckName: "cpu",
+							Tags:      []influxdb.Tag{{Key: "host", Value: "db1"}},
+							Level:     "crit",
+							Message:   "db1 is down",
+							Time:      faketime,
+						},
+					}, nil
+				},
+			}
+			h := NewAlertHandler(zaptest.NewLogger(t), alertBackend)
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/alerts"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("got = %v, want %v: %s", res.StatusCode, tt.wants.statusCode, body)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+				t.Errorf("%q, error unmarshaling json %v", tt.name, err)
+			} else if !eq {
+				t.Errorf("%q. ***%s***", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestAlertService_handlePostAlertAck(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		ack   string
+		wants wants
+	}{
+		{
+			name: "acknowledge an alert",
+			ack: `
+{
+  "orgID": "0000000000000001",
+  "checkID": "0000000000000002",
+  "tags": [{"key": "host", "value": "db1"}],
+  "level": "crit",
+  "note": "looking into it"
+}
+`,
+			wants: wants{
+				statusCode: 201,
+				body: `
+{
+  "id": "0000000000000003",
+  "orgID": "0000000000000001",
+  "checkID": "0000000000000002",
+  "userID": "0000000000000006",
+  "tags": [{"key": "host", "value": "db1"}],
+  "level": "crit",
+  "note": "looking into it",
+  "createdAt": "2006-05-04T01:02:03Z",
+  "updatedAt": "2006-05-04T01:02:03Z",
+  "links": {
+    "self": "/api/v2/alerts/acks/0000000000000003",
+    "check": "/api/v2/checks/0000000000000002",
+    "org": "/api/v2/orgs/0000000000000001"
+  }
+}
+`,
+			},
+		},
+		{
+			name: "acknowledge an ok alert",
+			ack: `
+{
+  "orgID": "0000000000000001",
+  "checkID": "0000000000000002",
+  "level": "ok"
+}
+`,
+			wants: wants{
+				statusCode: 400,
+				body:       `{"code":"invalid","message":"alert acknowledgement level must be one of crit, warn, info or unknown"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alertBackend := NewMockAlertBackend(t)
+			alertBackend.AlertService = &mock.AlertService{
+				CreateAlertAckFn: func(ctx context.Context, a *influxdb.AlertAck) error {
+					a.ID = 3
+					a.CreatedAt = faketime
+					a.UpdatedAt = faketime
+					return nil
+				},
+			}
+			h := NewAlertHandler(zaptest.NewLogger(t), alertBackend)
+			r := httptest.NewRequest("POST", "http://any.url", bytes.NewReader([]byte(tt.ack)))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Session{UserID: 6}))
+			w := httptest.NewRecorder()
+
+			h.handlePostAlertAck(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("got = %v, want %v: %s", res.StatusCode, tt.wants.statusCode, body)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+				t.Errorf("%q, error unmarshaling json %v", tt.name, err)
+			} else if !eq {
+				t.Errorf("%q. ***%s***", tt.name, diff)
+			}
+		})
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/http/metric"
 	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/influxdata/influxdb/oidc"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -35,10 +37,15 @@ type APIBackend struct {
 	QueryEventRecorder metric.EventRecorder
 
 	PointsWriter                    storage.PointsWriter
+	ReadStore                       reads.Store
 	DeleteService                   influxdb.DeleteService
 	AuthorizationService            influxdb.AuthorizationService
+	BackupService                   influxdb.BackupService
+	KVBackupService                 influxdb.KVBackupService
 	BucketService                   influxdb.BucketService
+	DBRPMappingService              influxdb.DBRPMappingService
 	SessionService                  influxdb.SessionService
+	OIDCService                     *oidc.Service // nil if the single sign-on is disabled
 	UserService                     influxdb.UserService
 	OrganizationService             influxdb.OrganizationService
 	UserResourceMappingService      influxdb.UserResourceMappingService
@@ -51,6 +58,7 @@ type APIBackend struct {
 	SourceService                   influxdb.SourceService
 	VariableService                 influxdb.VariableService
 	PasswordsService                influxdb.PasswordsService
+	Authenticator                   influxdb.Authenticator // nil if the passwords are only compared with the PasswordsService
 	OnboardingService               influxdb.OnboardingService
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
@@ -65,6 +73,9 @@ type APIBackend struct {
 	DocumentService                 influxdb.DocumentService
 	NotificationRuleStore           influxdb.NotificationRuleStore
 	NotificationEndpointService     influxdb.NotificationEndpointService
+	SilenceService                  influxdb.SilenceService
+	AlertService                    influxdb.AlertService
+	RoleService                     influxdb.RoleService
 }
 
 // PrometheusCollectors exposes the prometheus collectors associated with an APIBackend.
@@ -112,10 +123,17 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 
 	h.Mount("/api/v2", serveLinksHandler(b.HTTPErrorHandler))
 
+	alertBackend := NewAlertBackend(b.Logger.With(zap.String("handler", "alert")), b)
+	alertBackend.AlertService = authorizer.NewAlertService(b.AlertService)
+	h.Mount(prefixAlerts, NewAlertHandler(b.Logger, alertBackend))
+
 	authorizationBackend := NewAuthorizationBackend(b.Logger.With(zap.String("handler", "authorization")), b)
 	authorizationBackend.AuthorizationService = authorizer.NewAuthorizationService(b.AuthorizationService)
 	h.Mount(prefixAuthorization, NewAuthorizationHandler(b.Logger, authorizationBackend))
 
+	backupBackend := NewBackupBackend(b)
+	h.Mount(prefixBackup, NewBackupHandler(backupBackend))
+
 	bucketBackend := NewBucketBackend(b.Logger.With(zap.String("handler", "bucket")), b)
 	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService)
 	h.Mount(prefixBuckets, NewBucketHandler(b.Logger, bucketBackend))
@@ -156,12 +174,24 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	orgBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
 	h.Mount(prefixOrganizations, NewOrgHandler(b.Logger, orgBackend))
 
+	promBackend := NewPromBackend(b.Logger.With(zap.String("handler", "prom")), b)
+	h.Mount(prefixProm, NewPromHandler(b.Logger, promBackend))
+
+	promQLBackend := NewPromQLBackend(b.Logger.With(zap.String("handler", "promql")), b)
+	promQLHandler := NewPromQLHandler(b.Logger, promQLBackend)
+	h.Mount(prefixPromQLQuery, promQLHandler)
+	h.Mount(prefixPromQLQueryRange, promQLHandler)
+
 	scraperBackend := NewScraperBackend(b.Logger.With(zap.String("handler", "scraper")), b)
 	scraperBackend.ScraperStorageService = authorizer.NewScraperTargetStoreService(b.ScraperTargetStoreService,
 		b.UserResourceMappingService,
 		b.OrganizationService)
 	h.Mount(prefixTargets, NewScraperHandler(b.Logger, scraperBackend))
 
+	roleBackend := NewRoleBackend(b.Logger.With(zap.String("handler", "role")), b)
+	roleBackend.RoleService = authorizer.NewRoleService(b.RoleService)
+	h.Mount(prefixRoles, NewRoleHandler(b.Logger, roleBackend))
+
 	sessionBackend := newSessionBackend(b.Logger.With(zap.String("handler", "session")), b)
 	sessionHandler := NewSessionHandler(b.Logger, sessionBackend)
 	h.Mount(prefixSignIn, sessionHandler)
@@ -170,6 +200,10 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	setupBackend := NewSetupBackend(b.Logger.With(zap.String("handler", "setup")), b)
 	h.Mount(prefixSetup, NewSetupHandler(b.Logger, setupBackend))
 
+	silenceBackend := NewSilenceBackend(b.Logger.With(zap.String("handler", "silence")), b)
+	silenceBackend.SilenceService = authorizer.NewSilenceService(b.SilenceService)
+	h.Mount(prefixSilences, NewSilenceHandler(b.Logger, silenceBackend))
+
 	sourceBackend := NewSourceBackend(b.Logger.With(zap.String("handler", "source")), b)
 	sourceBackend.SourceService = authorizer.NewSourceService(b.SourceService)
 	sourceBackend.BucketService = authorizer.NewBucketService(b.BucketService)
@@ -194,6 +228,12 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixMe, userHandler)
 	h.Mount(prefixUsers, userHandler)
 
+	v1Backend := NewV1Backend(b.Logger.With(zap.String("handler", "v1")), b)
+	v1Handler := NewV1Handler(b.Logger, v1Backend)
+	h.Mount(v1QueryPath, v1Handler)
+	h.Mount(v1WritePath, v1Handler)
+	h.Mount(v1PingPath, v1Handler)
+
 	variableBackend := NewVariableBackend(b.Logger.With(zap.String("handler", "variable")), b)
 	variableBackend.VariableService = authorizer.NewVariableService(b.VariableService)
 	h.Mount(prefixVariables, NewVariableHandler(b.Logger, variableBackend))
@@ -210,7 +250,9 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
+	"alerts":         "/api/v2/alerts",
 	"authorizations": "/api/v2/authorizations",
+	"backup":         "/api/v2/backup",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
 	"external": map[string]string{
@@ -228,9 +270,11 @@ var apiLinks = map[string]interface{}{
 		"analyze":     "/api/v2/query/analyze",
 		"suggestions": "/api/v2/query/suggestions",
 	},
+	"roles":    "/api/v2/roles",
 	"setup":    "/api/v2/setup",
 	"signin":   "/api/v2/signin",
 	"signout":  "/api/v2/signout",
+	"silences": "/api/v2/silences",
 	"sources":  "/api/v2/sources",
 	"scrapers": "/api/v2/scrapers",
 	"swagger":  "/api/v2/swagger.json",
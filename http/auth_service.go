@@ -72,6 +72,7 @@ func NewAuthorizationHandler(log *zap.Logger, b *AuthorizationBackend) *Authoriz
 	h.HandlerFunc("GET", "/api/v2/authorizations/:id", h.handleGetAuthorization)
 	h.HandlerFunc("PATCH", "/api/v2/authorizations/:id", h.handleUpdateAuthorization)
 	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id", h.handleDeleteAuthorization)
+	h.HandlerFunc("POST", "/api/v2/authorizations/:id/rotate", h.handleRotateAuthorization)
 	return h
 }
 
@@ -86,6 +87,7 @@ type authResponse struct {
 	User        string               `json:"user"`
 	Permissions []permissionResponse `json:"permissions"`
 	Links       map[string]string    `json:"links"`
+	ExpiresAt   *time.Time           `json:"expiresAt,omitempty"`
 	CreatedAt   time.Time            `json:"createdAt"`
 	UpdatedAt   time.Time            `json:"updatedAt"`
 }
@@ -105,6 +107,7 @@ func newAuthResponse(a *platform.Authorization, org *platform.Organization, user
 			"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID),
 			"user": fmt.Sprintf("/api/v2/users/%s", a.UserID),
 		},
+		ExpiresAt: a.ExpiresAt,
 		CreatedAt: a.CreatedAt,
 		UpdatedAt: a.UpdatedAt,
 	}
@@ -119,20 +122,22 @@ func (a *authResponse) toPlatform() *platform.Authorization {
 		Description: a.Description,
 		OrgID:       a.OrgID,
 		UserID:      a.UserID,
+		ExpiresAt:   a.ExpiresAt,
 		CRUDLog: platform.CRUDLog{
 			CreatedAt: a.CreatedAt,
 			UpdatedAt: a.UpdatedAt,
 		},
 	}
 	for _, p := range a.Permissions {
-		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource})
+		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource, Series: p.Series})
 	}
 	return res
 }
 
 type permissionResponse struct {
-	Action   platform.Action  `json:"action"`
-	Resource resourceResponse `json:"resource"`
+	Action   platform.Action       `json:"action"`
+	Resource resourceResponse      `json:"resource"`
+	Series   *platform.SeriesScope `json:"series,omitempty"`
 }
 
 type resourceResponse struct {
@@ -149,6 +154,7 @@ func newPermissionsResponse(ctx context.Context, ps []platform.Permission, svc p
 			Resource: resourceResponse{
 				Resource: p.Resource,
 			},
+			Series: p.Series,
 		}
 
 		if p.Resource.ID != nil {
@@ -245,6 +251,7 @@ type postAuthorizationRequest struct {
 	UserID      *platform.ID          `json:"userID,omitempty"`
 	Description string                `json:"description"`
 	Permissions []platform.Permission `json:"permissions"`
+	ExpiresAt   *time.Time            `json:"expiresAt,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Authorization {
@@ -254,6 +261,7 @@ func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Auth
 		Description: p.Description,
 		Permissions: p.Permissions,
 		UserID:      userID,
+		ExpiresAt:   p.ExpiresAt,
 	}
 }
 
@@ -263,6 +271,7 @@ func newPostAuthorizationRequest(a *platform.Authorization) (*postAuthorizationR
 		Description: a.Description,
 		Permissions: a.Permissions,
 		Status:      a.Status,
+		ExpiresAt:   a.ExpiresAt,
 	}
 
 	if a.UserID.Valid() {
@@ -304,6 +313,13 @@ func (p *postAuthorizationRequest) Validate() error {
 		}
 	}
 
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "authorization must expire in the future",
+		}
+	}
+
 	if p.Status == "" {
 		p.Status = platform.Active
 	}
@@ -618,6 +634,99 @@ func decodeDeleteAuthorizationRequest(ctx context.Context, r *http.Request) (*de
 	}, nil
 }
 
+// handleRotateAuthorization is the HTTP handler for the POST /api/v2/authorizations/:id/rotate route.
+// It responds with the authorization replacing the rotated one.
+func (h *AuthorizationHandler) handleRotateAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeRotateAuthorizationRequest(ctx, r)
+	if err != nil {
+		h.log.Info("Failed to decode request", zap.String("handler", "rotateAuthorization"), zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AuthorizationService.RotateAuthorization(ctx, req.ID, req.rotation())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, a.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	u, err := h.UserService.FindUserByID(ctx, a.UserID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ps, err := newPermissionsResponse(ctx, a.Permissions, h.LookupService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Auth rotated", zap.String("authID", req.ID.String()), zap.String("newAuthID", a.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newAuthResponse(a, o, u, ps)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type rotateAuthorizationRequest struct {
+	ID                 platform.ID `json:"-"`
+	GracePeriodSeconds int64       `json:"gracePeriodSeconds"`
+	ExpiresAt          *time.Time  `json:"expiresAt,omitempty"`
+}
+
+func newRotateAuthorizationRequest(r platform.AuthorizationRotation) *rotateAuthorizationRequest {
+	return &rotateAuthorizationRequest{
+		GracePeriodSeconds: int64(r.GracePeriod / time.Second),
+		ExpiresAt:          r.ExpiresAt,
+	}
+}
+
+func (r *rotateAuthorizationRequest) rotation() platform.AuthorizationRotation {
+	return platform.AuthorizationRotation{
+		GracePeriod: time.Duration(r.GracePeriodSeconds) * time.Second,
+		ExpiresAt:   r.ExpiresAt,
+	}
+}
+
+func decodeRotateAuthorizationRequest(ctx context.Context, r *http.Request) (*rotateAuthorizationRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	req := &rotateAuthorizationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+	if err := req.ID.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	if req.GracePeriodSeconds < 0 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "gracePeriodSeconds must not be negative",
+		}
+	}
+	return req, nil
+}
+
 func getAuthorizedUser(r *http.Request, svc platform.UserService) (*platform.User, error) {
 	ctx := r.Context()
 
@@ -725,3 +834,18 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 		Delete(prefixAuthorization, id.String()).
 		Do(ctx)
 }
+
+// RotateAuthorization creates an authorization replacing the authorization
+// with a new token, which remains valid for the grace period of the rotation.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, r platform.AuthorizationRotation) (*platform.Authorization, error) {
+	var res authResponse
+	err := s.Client.
+		PostJSON(newRotateAuthorizationRequest(r), prefixAuthorization, id.String(), "rotate").
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.toPlatform(), nil
+}
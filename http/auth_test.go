@@ -8,7 +8,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
@@ -854,6 +856,163 @@ func TestService_handleDeleteAuthorization(t *testing.T) {
 	}
 }
 
+func TestService_handleRotateAuthorization(t *testing.T) {
+	expiresAt := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		AuthorizationService platform.AuthorizationService
+	}
+	type args struct {
+		id   string
+		body string
+	}
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "rotate an authorization",
+			fields: fields{
+				&mock.AuthorizationService{
+					RotateAuthorizationFn: func(ctx context.Context, id platform.ID, r platform.AuthorizationRotation) (*platform.Authorization, error) {
+						if id != platformtesting.MustIDBase16("020f755c3c082000") {
+							return nil, fmt.Errorf("wrong id")
+						}
+						if r.GracePeriod != time.Hour || r.ExpiresAt == nil || !r.ExpiresAt.Equal(expiresAt) {
+							return nil, fmt.Errorf("wrong rotation %v", r)
+						}
+						return &platform.Authorization{
+							ID:          platformtesting.MustIDBase16("020f755c3c082001"),
+							Token:       "rotated-token",
+							Status:      platform.Active,
+							OrgID:       platformtesting.MustIDBase16("020f755c3c083000"),
+							UserID:      platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa"),
+							Description: "telegraf",
+							ExpiresAt:   r.ExpiresAt,
+						}, nil
+					},
+				},
+			},
+			args: args{
+				id:   "020f755c3c082000",
+				body: `{"gracePeriodSeconds": 3600, "expiresAt": "2030-01-01T00:00:00Z"}`,
+			},
+			wants: wants{
+				statusCode: http.StatusCreated,
+				body: `
+{
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z",
+  "expiresAt": "2030-01-01T00:00:00Z",
+  "description": "telegraf",
+  "id": "020f755c3c082001",
+  "links": {
+    "self": "/api/v2/authorizations/020f755c3c082001",
+    "user": "/api/v2/users/aaaaaaaaaaaaaaaa"
+  },
+  "org": "o1",
+  "orgID": "020f755c3c083000",
+  "permissions": [],
+  "status": "active",
+  "token": "rotated-token",
+  "user": "u1",
+  "userID": "aaaaaaaaaaaaaaaa"
+}
+`,
+			},
+		},
+		{
+			name: "negative grace period",
+			fields: fields{
+				&mock.AuthorizationService{},
+			},
+			args: args{
+				id:   "020f755c3c082000",
+				body: `{"gracePeriodSeconds": -1}`,
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+				body:       `{"code":"invalid","message":"gracePeriodSeconds must not be negative"}`,
+			},
+		},
+		{
+			name: "expired authorization",
+			fields: fields{
+				&mock.AuthorizationService{
+					RotateAuthorizationFn: func(ctx context.Context, id platform.ID, r platform.AuthorizationRotation) (*platform.Authorization, error) {
+						return nil, &platform.Error{
+							Code: platform.EForbidden,
+							Msg:  platform.ErrAuthorizationExpired,
+						}
+					},
+				},
+			},
+			args: args{
+				id:   "020f755c3c082000",
+				body: `{"gracePeriodSeconds": 3600}`,
+			},
+			wants: wants{
+				statusCode: http.StatusForbidden,
+				body:       `{"code":"forbidden","message":"authorization has expired"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorizationBackend := NewMockAuthorizationBackend(t)
+			authorizationBackend.HTTPErrorHandler = ErrorHandler(0)
+			authorizationBackend.AuthorizationService = tt.fields.AuthorizationService
+			authorizationBackend.UserService = &mock.UserService{
+				FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+					return &platform.User{ID: id, Name: "u1"}, nil
+				},
+			}
+			authorizationBackend.OrganizationService = &mock.OrganizationService{
+				FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id, Name: "o1"}, nil
+				},
+			}
+			h := NewAuthorizationHandler(zaptest.NewLogger(t), authorizationBackend)
+
+			r := httptest.NewRequest("POST", "http://any.url", strings.NewReader(tt.args.body))
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: tt.args.id,
+					},
+				}))
+
+			w := httptest.NewRecorder()
+
+			h.handleRotateAuthorization(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Logf("body: %s", body)
+				t.Errorf("%q. handleRotateAuthorization() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+				t.Errorf("%q, handleRotateAuthorization(). error unmarshaling json %v", tt.name, err)
+			} else if !eq {
+				t.Errorf("%q. handleRotateAuthorization() = ***%s***", tt.name, diff)
+			}
+		})
+	}
+}
+
 func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.T) (platform.AuthorizationService, string, func()) {
 	t.Helper()
 	if t.Name() == "TestAuthorizationService_FindAuthorizations/find_authorization_by_token" {
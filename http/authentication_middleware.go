@@ -9,8 +9,10 @@ import (
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	platcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/jsonweb"
+	"github.com/influxdata/influxdb/oidc"
 	"go.uber.org/zap"
 )
 
@@ -22,8 +24,14 @@ type AuthenticationHandler struct {
 	AuthorizationService platform.AuthorizationService
 	SessionService       platform.SessionService
 	UserService          platform.UserService
+	// RoleService resolves the permissions of the roles assigned to the users
+	// of the sessions, if set.
+	RoleService          platform.RoleService
 	TokenParser          *jsonweb.TokenParser
 	SessionRenewDisabled bool
+	// OIDCService authenticates the tokens of the OpenID Connect provider, if
+	// the single sign-on is enabled.
+	OIDCService *oidc.Service
 
 	// This is only really used for it's lookup method the specific http
 	// handler used to register routes does not matter.
@@ -56,7 +64,7 @@ const (
 
 // ProbeAuthScheme probes the http request for the requests for token or cookie session.
 func ProbeAuthScheme(r *http.Request) (string, error) {
-	_, tokenErr := GetToken(r)
+	_, tokenErr := getRequestToken(r)
 	_, sessErr := decodeCookieSession(r.Context(), r)
 
 	if tokenErr != nil && sessErr != nil {
@@ -115,6 +123,13 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	if h.RoleService != nil {
+		if auth, err = authorizer.WithRolePermissions(ctx, h.RoleService, auth); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	ctx = platcontext.SetAuthorizer(ctx, auth)
 
 	h.Handler.ServeHTTP(w, r.WithContext(ctx))
@@ -134,11 +149,21 @@ func (h *AuthenticationHandler) isUserActive(ctx context.Context, auth platform.
 }
 
 func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *http.Request) (platform.Authorizer, error) {
-	t, err := GetToken(r)
+	t, err := getRequestToken(r)
 	if err != nil {
 		return nil, err
 	}
 
+	if h.OIDCService != nil {
+		s, err := h.OIDCService.Authorize(ctx, t)
+		if err == nil {
+			return s, nil
+		}
+		if err != oidc.ErrUnknownIssuer {
+			return nil, err
+		}
+	}
+
 	token, err := h.TokenParser.Parse(t)
 	if err == nil {
 		return token, nil
@@ -152,7 +177,23 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return nil, err
 	}
 
-	return h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	a, err := h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.Expired(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// getRequestToken returns the token of the request, which can also be given
+// in the ways of InfluxDB 1.x for the endpoints compatible with it.
+func getRequestToken(r *http.Request) (string, error) {
+	if isV1Path(r.URL.Path) {
+		return getV1Token(r)
+	}
+	return GetToken(r)
 }
 
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (*platform.Session, error) {
@@ -11,6 +11,7 @@ import (
 
 	influxdb "github.com/influxdata/influxdb"
 	platform "github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
 	platformhttp "github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/jsonweb"
 	"github.com/influxdata/influxdb/mock"
@@ -113,6 +114,42 @@ func TestAuthenticationHandler(t *testing.T) {
 				code: http.StatusUnauthorized,
 			},
 		},
+		{
+			name: "token is expired",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						expiresAt := time.Now().Add(-time.Minute)
+						return &platform.Authorization{ExpiresAt: &expiresAt}, nil
+					},
+				},
+				SessionService: mock.NewSessionService(),
+			},
+			args: args{
+				token: "abc123",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name: "token is not expired yet",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						expiresAt := time.Now().Add(time.Hour)
+						return &platform.Authorization{ExpiresAt: &expiresAt}, nil
+					},
+				},
+				SessionService: mock.NewSessionService(),
+			},
+			args: args{
+				token: "abc123",
+			},
+			wants: wants{
+				code: http.StatusOK,
+			},
+		},
 		{
 			name: "associated user is inactive",
 			fields: fields{
@@ -248,6 +285,57 @@ func TestAuthenticationHandler(t *testing.T) {
 	}
 }
 
+func TestAuthenticationHandler_RolePermissions(t *testing.T) {
+	dashboards := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &one},
+	}
+
+	var allowed bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, err := platcontext.GetAuthorizer(r.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		allowed = a.Allowed(dashboards)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	h := platformhttp.NewAuthenticationHandler(zaptest.NewLogger(t), platformhttp.ErrorHandler(0))
+	h.AuthorizationService = &mock.AuthorizationService{
+		FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+			return &platform.Authorization{UserID: 2, OrgID: one, Status: platform.Active}, nil
+		},
+	}
+	h.SessionService = mock.NewSessionService()
+	h.UserService = &mock.UserService{
+		FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+			return &platform.User{ID: id}, nil
+		},
+	}
+	var found int
+	rs := mock.NewRoleService()
+	rs.FindRolesFn = func(ctx context.Context, filter platform.RoleFilter, opt ...platform.FindOptions) ([]*platform.Role, int, error) {
+		found++
+		return []*platform.Role{{ID: 3, OrgID: one, Name: "dashboard-editor", Permissions: []platform.Permission{dashboards}}}, 1, nil
+	}
+	h.RoleService = rs
+	h.Handler = handler
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "http://any.url", nil)
+	platformhttp.SetToken("abc123", r)
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code to be %d got %d", http.StatusOK, w.Code)
+	}
+	// a token keeps the permissions it was created with.
+	if allowed || found != 0 {
+		t.Error("expected token not to be allowed the permissions of the roles of its user")
+	}
+}
+
 func TestProbeAuthScheme(t *testing.T) {
 	type args struct {
 		token   string
@@ -0,0 +1,325 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+// BackupBackend is all services and associated parameters required to construct
+// the BackupHandler.
+type BackupBackend struct {
+	Logger *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	BackupService   influxdb.BackupService
+	KVBackupService influxdb.KVBackupService
+}
+
+// NewBackupBackend returns a new instance of BackupBackend.
+func NewBackupBackend(b *APIBackend) *BackupBackend {
+	return &BackupBackend{
+		Logger: b.Logger.With(zap.String("handler", "backup")),
+
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		BackupService:    b.BackupService,
+		KVBackupService:  b.KVBackupService,
+	}
+}
+
+// BackupHandler is http handler for backup service.
+type BackupHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	BackupService   influxdb.BackupService
+	KVBackupService influxdb.KVBackupService
+}
+
+const (
+	prefixBackup        = "/api/v2/backup"
+	backupKVPath        = prefixBackup + "/kv"
+	backupSnapshotsPath = prefixBackup + "/snapshots"
+	backupSnapshotPath  = backupSnapshotsPath + "/:backup_id"
+	backupFilePath      = backupSnapshotPath + "/files/*backup_file"
+)
+
+// NewBackupHandler creates a new handler at /api/v2/backup to receive backup requests.
+func NewBackupHandler(b *BackupBackend) *BackupHandler {
+	h := &BackupHandler{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Router:           NewRouter(b.HTTPErrorHandler),
+		Logger:           b.Logger,
+		BackupService:    b.BackupService,
+		KVBackupService:  b.KVBackupService,
+	}
+
+	h.HandlerFunc(http.MethodGet, backupKVPath, h.handleBackupKV)
+	h.HandlerFunc(http.MethodPost, backupSnapshotsPath, h.handleCreateBackup)
+	h.HandlerFunc(http.MethodGet, backupFilePath, h.handleFetchBackupFile)
+	h.HandlerFunc(http.MethodDelete, backupSnapshotPath, h.handleDeleteBackup)
+
+	return h
+}
+
+// authorizeBackup ensures the request was made with an operator token. A
+// backup contains every resource in the instance, so nothing less suffices.
+func (h *BackupHandler) authorizeBackup(ctx context.Context) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range influxdb.OperPermissions() {
+		if !a.Allowed(p) {
+			return &influxdb.Error{
+				Code: influxdb.EForbidden,
+				Op:   "http/authorizeBackup",
+				Msg:  "backups require an operator token",
+			}
+		}
+	}
+	return nil
+}
+
+func (h *BackupHandler) handleBackupKV(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleBackupKV")
+	defer span.Finish()
+
+	ctx := r.Context()
+	if err := h.authorizeBackup(ctx); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.KVBackupService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EMethodNotAllowed,
+			Op:   "http/handleBackupKV",
+			Msg:  "the configured kv store does not support backups",
+		}, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	if err := h.KVBackupService.Backup(ctx, w); err != nil {
+		// The status code has already been sent, so the error can only be logged.
+		h.Logger.Error("Failed to write kv backup", zap.Error(err))
+	}
+}
+
+func (h *BackupHandler) handleCreateBackup(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleCreateBackup")
+	defer span.Finish()
+
+	ctx := r.Context()
+	if err := h.authorizeBackup(ctx); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	opts, err := decodeBackupOptions(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	manifest, err := h.BackupService.CreateBackup(ctx, opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("backup created", zap.Int("backupID", manifest.ID))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, manifest); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *BackupHandler) handleFetchBackupFile(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleFetchBackupFile")
+	defer span.Finish()
+
+	ctx := r.Context()
+	if err := h.authorizeBackup(ctx); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	backupID, err := decodeBackupID(params.ByName("backup_id"))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	// The catch-all parameter includes the leading slash.
+	backupFile := params.ByName("backup_file")
+	if len(backupFile) > 0 && backupFile[0] == '/' {
+		backupFile = backupFile[1:]
+	}
+
+	// The response header is deferred until the file is first written so
+	// that a missing file is still reported with the appropriate status code.
+	bw := &backupFileWriter{w: w}
+	if err := h.BackupService.FetchBackupFile(ctx, backupID, backupFile, bw); err != nil {
+		if bw.written {
+			h.Logger.Error("Failed to write backup file", zap.String("file", backupFile), zap.Error(err))
+			return
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if !bw.written {
+		bw.writeHeader()
+	}
+}
+
+func (h *BackupHandler) handleDeleteBackup(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleDeleteBackup")
+	defer span.Finish()
+
+	ctx := r.Context()
+	if err := h.authorizeBackup(ctx); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	backupID, err := decodeBackupID(httprouter.ParamsFromContext(ctx).ByName("backup_id"))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.BackupService.DeleteBackup(ctx, backupID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("backup deleted", zap.Int("backupID", backupID))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeBackupOptions(r *http.Request) (influxdb.BackupOptions, error) {
+	var opts influxdb.BackupOptions
+	if s := r.URL.Query().Get("incremental"); s != "" {
+		incremental, err := strconv.ParseBool(s)
+		if err != nil {
+			return opts, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid incremental parameter %q", s),
+			}
+		}
+		opts.Incremental = incremental
+	}
+	return opts, nil
+}
+
+func decodeBackupID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid backup id %q", s),
+		}
+	}
+	return id, nil
+}
+
+// backupFileWriter defers writing the response header until the first write
+// of the file contents.
+type backupFileWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (bw *backupFileWriter) writeHeader() {
+	bw.w.Header().Set("Content-Type", "application/octet-stream")
+	bw.w.WriteHeader(http.StatusOK)
+	bw.written = true
+}
+
+func (bw *backupFileWriter) Write(p []byte) (int, error) {
+	if !bw.written {
+		bw.writeHeader()
+	}
+	return bw.w.Write(p)
+}
+
+// BackupService connects to Influx via HTTP using tokens to create and
+// download backups.
+type BackupService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.BackupService = (*BackupService)(nil)
+var _ influxdb.KVBackupService = (*BackupService)(nil)
+
+// Backup writes a copy of the server's kv store to w.
+func (s *BackupService) Backup(ctx context.Context, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Get(backupKVPath).
+		Accept("application/octet-stream").
+		Decode(func(resp *http.Response) error {
+			_, err := io.Copy(w, resp.Body)
+			return err
+		}).
+		Do(ctx)
+}
+
+// CreateBackup creates a backup on the server and returns its manifest.
+func (s *BackupService) CreateBackup(ctx context.Context, opts influxdb.BackupOptions) (*influxdb.BackupManifest, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var manifest influxdb.BackupManifest
+	err := s.Client.
+		Post(httpc.BodyEmpty, backupSnapshotsPath).
+		QueryParams([2]string{"incremental", strconv.FormatBool(opts.Incremental)}).
+		DecodeJSON(&manifest).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// FetchBackupFile downloads a single file of a backup and writes it to w.
+func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Get(backupSnapshotsPath, strconv.Itoa(backupID), "files", path.Clean(backupFile)).
+		Accept("application/octet-stream").
+		Decode(func(resp *http.Response) error {
+			_, err := io.Copy(w, resp.Body)
+			return err
+		}).
+		Do(ctx)
+}
+
+// DeleteBackup removes a backup from the server.
+func (s *BackupService) DeleteBackup(ctx context.Context, backupID int) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.Client.
+		Delete(backupSnapshotsPath, strconv.Itoa(backupID)).
+		Do(ctx)
+}
@@ -132,13 +132,14 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 
 // bucket is used for serialization/deserialization with duration string syntax.
 type bucket struct {
-	ID                  influxdb.ID     `json:"id,omitempty"`
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Type                string          `json:"type"`
-	Description         string          `json:"description,omitempty"`
-	Name                string          `json:"name"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	ID                  influxdb.ID                `json:"id,omitempty"`
+	OrgID               influxdb.ID                `json:"orgID,omitempty"`
+	Type                string                     `json:"type"`
+	Description         string                     `json:"description,omitempty"`
+	Name                string                     `json:"name"`
+	RetentionPolicyName string                     `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules      []retentionRule            `json:"retentionRules"`
+	DownsamplePolicy    *influxdb.DownsamplePolicy `json:"downsamplePolicy,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -160,22 +161,59 @@ func (rr *retentionRule) RetentionPeriod() (time.Duration, error) {
 	return t, nil
 }
 
+// retentionRuleTypeCold is the type of the retention rule moving the data of a
+// bucket to the cold tier of the storage engine.
+const retentionRuleTypeCold = "cold"
+
+// retentionPeriods returns the retention period and the cold tier period set by
+// rules. Only a single rule of each type is supported for the moment.
+func retentionPeriods(rules []retentionRule) (retention, cold time.Duration, err error) {
+	var hasRetention, hasCold bool
+	for i := range rules {
+		d, err := rules[i].RetentionPeriod()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if rules[i].Type == retentionRuleTypeCold {
+			if !hasCold {
+				cold, hasCold = d, true
+			}
+			continue
+		}
+		if !hasRetention {
+			retention, hasRetention = d, true
+		}
+	}
+	return retention, cold, nil
+}
+
+func newRetentionRules(retention, cold time.Duration) []retentionRule {
+	rules := []retentionRule{}
+	if rp := int64(retention.Round(time.Second) / time.Second); rp > 0 {
+		rules = append(rules, retentionRule{
+			Type:         "expire",
+			EverySeconds: rp,
+		})
+	}
+	if cp := int64(cold.Round(time.Second) / time.Second); cp > 0 {
+		rules = append(rules, retentionRule{
+			Type:         retentionRuleTypeCold,
+			EverySeconds: cp,
+		})
+	}
+	return rules
+}
+
 func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 	if b == nil {
 		return nil, nil
 	}
 
-	var d time.Duration // zero value implies infinite retention policy
-
-	// Only support a single retention period for the moment
-	if len(b.RetentionRules) > 0 {
-		d = time.Duration(b.RetentionRules[0].EverySeconds) * time.Second
-		if d < time.Second {
-			return nil, &influxdb.Error{
-				Code: influxdb.EUnprocessableEntity,
-				Msg:  "expiration seconds must be greater than or equal to one second",
-			}
-		}
+	// zero value implies infinite retention policy
+	d, cold, err := retentionPeriods(b.RetentionRules)
+	if err != nil {
+		return nil, err
 	}
 
 	return &influxdb.Bucket{
@@ -186,6 +224,8 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		ColdTierPeriod:      cold,
+		DownsamplePolicy:    b.DownsamplePolicy,
 		CRUDLog:             b.CRUDLog,
 	}, nil
 }
@@ -195,15 +235,6 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		return nil
 	}
 
-	rules := []retentionRule{}
-	rp := int64(pb.RetentionPeriod.Round(time.Second) / time.Second)
-	if rp > 0 {
-		rules = append(rules, retentionRule{
-			Type:         "expire",
-			EverySeconds: rp,
-		})
-	}
-
 	return &bucket{
 		ID:                  pb.ID,
 		OrgID:               pb.OrgID,
@@ -211,16 +242,18 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Name:                pb.Name,
 		Description:         pb.Description,
 		RetentionPolicyName: pb.RetentionPolicyName,
-		RetentionRules:      rules,
+		RetentionRules:      newRetentionRules(pb.RetentionPeriod, pb.ColdTierPeriod),
+		DownsamplePolicy:    pb.DownsamplePolicy,
 		CRUDLog:             pb.CRUDLog,
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
-	Name           *string         `json:"name,omitempty"`
-	Description    *string         `json:"description,omitempty"`
-	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	Name             *string                    `json:"name,omitempty"`
+	Description      *string                    `json:"description,omitempty"`
+	RetentionRules   []retentionRule            `json:"retentionRules,omitempty"`
+	DownsamplePolicy *influxdb.DownsamplePolicy `json:"downsamplePolicy,omitempty"`
 }
 
 func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
@@ -228,21 +261,17 @@ func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
 		return nil, nil
 	}
 
-	// For now, only use a single retention rule.
-	var d time.Duration
-	var err error
-
-	if len(b.RetentionRules) > 0 {
-		d, err = b.RetentionRules[0].RetentionPeriod()
-		if err != nil {
-			return nil, err
-		}
+	d, cold, err := retentionPeriods(b.RetentionRules)
+	if err != nil {
+		return nil, err
 	}
 
 	return &influxdb.BucketUpdate{
-		Name:            b.Name,
-		Description:     b.Description,
-		RetentionPeriod: &d,
+		Name:             b.Name,
+		Description:      b.Description,
+		RetentionPeriod:  &d,
+		ColdTierPeriod:   &cold,
+		DownsamplePolicy: b.DownsamplePolicy,
 	}, nil
 }
 
@@ -252,9 +281,10 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 	}
 
 	up := &bucketUpdate{
-		Name:           pb.Name,
-		Description:    pb.Description,
-		RetentionRules: []retentionRule{},
+		Name:             pb.Name,
+		Description:      pb.Description,
+		RetentionRules:   []retentionRule{},
+		DownsamplePolicy: pb.DownsamplePolicy,
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -264,6 +294,13 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 			EverySeconds: d,
 		})
 	}
+	if pb.ColdTierPeriod != nil && *pb.ColdTierPeriod > 0 {
+		d := int64((*pb.ColdTierPeriod).Round(time.Second) / time.Second)
+		up.RetentionRules = append(up.RetentionRules, retentionRule{
+			Type:         retentionRuleTypeCold,
+			EverySeconds: d,
+		})
+	}
 	return up
 }
 
@@ -346,11 +383,12 @@ func (h *BucketHandler) handlePostBucket(w http.ResponseWriter, r *http.Request)
 }
 
 type postBucketRequest struct {
-	OrgID               influxdb.ID     `json:"orgID,omitempty"`
-	Name                string          `json:"name"`
-	Description         string          `json:"description"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	OrgID               influxdb.ID                `json:"orgID,omitempty"`
+	Name                string                     `json:"name"`
+	Description         string                     `json:"description"`
+	RetentionPolicyName string                     `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules      []retentionRule            `json:"retentionRules"`
+	DownsamplePolicy    *influxdb.DownsamplePolicy `json:"downsamplePolicy,omitempty"`
 }
 
 func (b postBucketRequest) Validate() error {
@@ -365,15 +403,10 @@ func (b postBucketRequest) Validate() error {
 }
 
 func (b postBucketRequest) toInfluxDB() (*influxdb.Bucket, error) {
-	var dur time.Duration // zero value implies infinite retention policy
-	var err error
-
-	// Only support a single retention period for the moment
-	if len(b.RetentionRules) > 0 {
-		dur, err = b.RetentionRules[0].RetentionPeriod()
-		if err != nil {
-			return nil, err
-		}
+	// zero value implies infinite retention policy
+	dur, cold, err := retentionPeriods(b.RetentionRules)
+	if err != nil {
+		return nil, err
 	}
 
 	return &influxdb.Bucket{
@@ -383,6 +416,8 @@ func (b postBucketRequest) toInfluxDB() (*influxdb.Bucket, error) {
 		Type:                influxdb.BucketTypeUser,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     dur,
+		ColdTierPeriod:      cold,
+		DownsamplePolicy:    b.DownsamplePolicy,
 	}, err
 }
 
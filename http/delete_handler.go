@@ -105,7 +105,9 @@ func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !a.Allowed(*p) {
+	// the predicate of the delete is not restricted to the series of the
+	// authorizer, so it must be allowed to write all the series.
+	if !a.Allowed(*p) || len(influxdb.SeriesScopes(a, *p)) > 0 {
 		h.HandleHTTPError(ctx, &influxdb.Error{
 			Code: influxdb.EForbidden,
 			Op:   "http/handleDelete",
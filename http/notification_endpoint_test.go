@@ -481,6 +481,58 @@ func TestService_handlePostNotificationEndpoint(t *testing.T) {
   "description": "desc1",
   "labels": []
 }
+`,
+			},
+		},
+		{
+			name: "create a new opsgenie notification endpoint",
+			fields: fields{
+				Secrets: map[string]string{},
+				NotificationEndpointService: &mock.NotificationEndpointService{
+					CreateNotificationEndpointF: func(ctx context.Context, edp influxdb.NotificationEndpoint, userID influxdb.ID) error {
+						edp.SetID(influxTesting.MustIDBase16("020f755c3c082000"))
+						edp.BackfillSecretKeys()
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+						return &influxdb.Organization{ID: influxTesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+			},
+			args: args{
+				endpoint: map[string]interface{}{
+					"name":        "hello",
+					"type":        "opsgenie",
+					"orgID":       "6f626f7274697320",
+					"description": "desc1",
+					"status":      "active",
+					"apiKey":      "key1",
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "self": "/api/v2/notificationEndpoints/020f755c3c082000",
+    "labels": "/api/v2/notificationEndpoints/020f755c3c082000/labels",
+    "members": "/api/v2/notificationEndpoints/020f755c3c082000/members",
+    "owners": "/api/v2/notificationEndpoints/020f755c3c082000/owners"
+  },
+  "status": "active",
+  "apiKey": "secret: 020f755c3c082000-api-key",
+  "type": "opsgenie",
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z",
+  "id": "020f755c3c082000",
+  "orgID": "6f626f7274697320",
+  "name": "hello",
+  "description": "desc1",
+  "labels": []
+}
 `,
 			},
 		},
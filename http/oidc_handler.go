@@ -0,0 +1,123 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/oidc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixSignInOIDC         = "/api/v2/signin/oidc"
+	prefixSignInOIDCCallback = "/api/v2/signin/oidc/callback"
+
+	cookieOIDCLoginName = "oidc_login"
+	// oidcLoginMaxAge is the time in seconds the user has to sign in at the provider.
+	oidcLoginMaxAge = 600
+)
+
+// handleSigninOIDC is the HTTP handler for the GET /signin/oidc route.
+// It redirects the user agent to the OpenID Connect provider to sign in.
+func (h *SessionHandler) handleSigninOIDC(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	l, err := oidc.NewLogin()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	encodeCookieOIDCLogin(w, r, l)
+	http.Redirect(w, r, h.OIDCService.AuthCodeURL(l), http.StatusFound)
+}
+
+// handleSigninOIDCCallback is the HTTP handler for the GET /signin/oidc/callback route.
+// The OpenID Connect provider redirects the user agent to it after the user
+// signed in, and a session is created for the user of the ID token.
+func (h *SessionHandler) handleSigninOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	l, err := decodeCookieOIDCLogin(r)
+	if err != nil {
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+	// the sign in can only be completed once.
+	expireCookieOIDCLogin(w)
+
+	qp := r.URL.Query()
+	if e := qp.Get("error"); e != "" {
+		h.log.Info("OpenID Connect sign in failed", zap.String("error", e), zap.String("description", qp.Get("error_description")))
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	if qp.Get("state") != l.State {
+		h.log.Info("OpenID Connect sign in state mismatch")
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	u, err := h.OIDCService.SignIn(ctx, l, qp.Get("code"))
+	if err != nil {
+		h.log.Info("OpenID Connect sign in failed", zap.Error(err))
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	s, err := h.SessionService.CreateSession(ctx, u.Name)
+	if err != nil {
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	encodeCookieSession(w, s)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func encodeCookieOIDCLogin(w http.ResponseWriter, r *http.Request, l *oidc.Login) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieOIDCLoginName,
+		Value:    strings.Join([]string{l.State, l.Nonce, l.Verifier}, "."),
+		Path:     prefixSignInOIDC,
+		MaxAge:   oidcLoginMaxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func expireCookieOIDCLogin(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieOIDCLoginName,
+		Path:     prefixSignInOIDC,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+func decodeCookieOIDCLogin(r *http.Request) (*oidc.Login, error) {
+	c, err := r.Cookie(cookieOIDCLoginName)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	vs := strings.Split(c.Value, ".")
+	if len(vs) != 3 || vs[0] == "" || vs[1] == "" || vs[2] == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid OpenID Connect sign in cookie",
+		}
+	}
+
+	return &oidc.Login{
+		State:    vs[0],
+		Nonce:    vs[1],
+		Verifier: vs[2],
+	}, nil
+}
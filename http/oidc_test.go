@@ -0,0 +1,226 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/oidc"
+	"github.com/influxdata/influxdb/oidc/oidctest"
+	"go.uber.org/zap/zaptest"
+)
+
+func newOIDCTestService(t *testing.T) (*oidc.Service, *oidctest.Provider, *kv.Service) {
+	t.Helper()
+
+	p, err := oidctest.NewProvider("influxdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		p.Close()
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+
+	s, err := oidc.NewService(ctx, zaptest.NewLogger(t), oidc.Config{
+		Issuer:        p.Issuer(),
+		ClientID:      "influxdb",
+		ClientSecret:  "secret",
+		RedirectURL:   "http://localhost:9999/api/v2/signin/oidc/callback",
+		AutoProvision: true,
+	}, svc, svc, svc, svc)
+	if err != nil {
+		p.Close()
+		t.Fatal(err)
+	}
+	return s, p, svc
+}
+
+func TestSessionHandler_handleSigninOIDC(t *testing.T) {
+	s, p, svc := newOIDCTestService(t)
+	defer p.Close()
+
+	h := NewSessionHandler(zaptest.NewLogger(t), &SessionBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		log:              zaptest.NewLogger(t),
+		PasswordsService: svc,
+		SessionService:   svc,
+		UserService:      svc,
+		OIDCService:      s,
+	})
+
+	signin := func(t *testing.T) (*http.Cookie, url.Values) {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oidc", nil))
+		res := w.Result()
+		if res.StatusCode != http.StatusFound {
+			t.Fatalf("got status code %d, want %d", res.StatusCode, http.StatusFound)
+		}
+		loc, err := res.Location()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cookies := res.Cookies()
+		if len(cookies) != 1 || cookies[0].Name != cookieOIDCLoginName {
+			t.Fatalf("got cookies %v, want the %s cookie", cookies, cookieOIDCLoginName)
+		}
+		return cookies[0], loc.Query()
+	}
+
+	t.Run("creates a session for the user of the ID token", func(t *testing.T) {
+		c, q := signin(t)
+		p.Authorize("code", q.Get("code_challenge"), jwt.MapClaims{
+			"sub":            "1234",
+			"email":          "alice@example.com",
+			"email_verified": true,
+			"nonce":          q.Get("nonce"),
+		})
+
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oidc/callback?code=code&state="+q.Get("state"), nil)
+		r.AddCookie(c)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		res := w.Result()
+		if res.StatusCode != http.StatusSeeOther {
+			t.Fatalf("got status code %d, want %d", res.StatusCode, http.StatusSeeOther)
+		}
+
+		var key string
+		for _, c := range res.Cookies() {
+			if c.Name == cookieSessionName {
+				key = c.Value
+			}
+		}
+		if key == "" {
+			t.Fatal("expected a session cookie")
+		}
+		session, err := svc.FindSession(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := svc.FindUserByID(context.Background(), session.UserID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Name != "alice@example.com" {
+			t.Fatalf("got session of user %q, want alice@example.com", u.Name)
+		}
+	})
+
+	t.Run("state mismatch is unauthorized", func(t *testing.T) {
+		c, q := signin(t)
+		p.Authorize("code", q.Get("code_challenge"), jwt.MapClaims{
+			"sub":            "1234",
+			"email":          "alice@example.com",
+			"email_verified": true,
+			"nonce":          q.Get("nonce"),
+		})
+
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oidc/callback?code=code&state=forged", nil)
+		r.AddCookie(c)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("got status code %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("missing login cookie is unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oidc/callback?code=code&state=state", nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("got status code %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("provider error is unauthorized", func(t *testing.T) {
+		c, q := signin(t)
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/signin/oidc/callback?error=access_denied&state="+q.Get("state"), nil)
+		r.AddCookie(c)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("got status code %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestAuthenticationHandler_OIDC(t *testing.T) {
+	s, p, svc := newOIDCTestService(t)
+	defer p.Close()
+
+	var authorizer platform.Authorizer
+	h := NewAuthenticationHandler(zaptest.NewLogger(t), ErrorHandler(0))
+	h.AuthorizationService = svc
+	h.SessionService = svc
+	h.UserService = svc
+	h.OIDCService = s
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizer, _ = pcontext.GetAuthorizer(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("provider token authenticates its user", func(t *testing.T) {
+		authorizer = nil
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/buckets", nil)
+		SetToken(p.Token(jwt.MapClaims{"sub": "1234", "email": "alice@example.com", "email_verified": true}), r)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status code %d, want %d", w.Code, http.StatusOK)
+		}
+		name := "alice@example.com"
+		u, err := svc.FindUser(context.Background(), platform.UserFilter{Name: &name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if authorizer == nil || authorizer.GetUserID() != u.ID {
+			t.Fatalf("got authorizer %v, want one of user %s", authorizer, u.ID)
+		}
+	})
+
+	t.Run("provider token of another audience is unauthorized", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/buckets", nil)
+		SetToken(p.Token(jwt.MapClaims{"sub": "1234", "email": "alice@example.com", "email_verified": true, "aud": "other"}), r)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("got status code %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("authorization tokens are still accepted", func(t *testing.T) {
+		ctx := context.Background()
+		u := &platform.User{Name: "bob"}
+		if err := svc.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		o := &platform.Organization{Name: "org"}
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+		a := &platform.Authorization{OrgID: o.ID, UserID: u.ID, Status: platform.Active}
+		if err := svc.CreateAuthorization(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/buckets", nil)
+		SetToken(a.Token, r)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status code %d, want %d", w.Code, http.StatusOK)
+		}
+	})
+}
@@ -0,0 +1,473 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/snappy"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/prometheus/prompb"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"go.uber.org/zap"
+)
+
+// PromBackend is all services and associated parameters required to construct
+// the PromHandler.
+type PromBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	PointsWriter        storage.PointsWriter
+	ReadStore           reads.Store
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewPromBackend returns a new instance of PromBackend.
+func NewPromBackend(log *zap.Logger, b *APIBackend) *PromBackend {
+	return &PromBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		PointsWriter:        b.PointsWriter,
+		ReadStore:           b.ReadStore,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// PromHandler serves the remote write and read endpoints of Prometheus.
+//
+// The samples of a metric are stored in the value field of the measurement
+// named after the metric, and the labels of the metric are its tags.
+type PromHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	PointsWriter        storage.PointsWriter
+	ReadStore           reads.Store
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	prefixProm     = "/api/v1/prom"
+	promWritePath  = "/api/v1/prom/write"
+	promReadPath   = "/api/v1/prom/read"
+	promMetricName = "__name__"
+	promFieldKey   = "value"
+)
+
+// Prefix provides the route prefix.
+func (*PromHandler) Prefix() string {
+	return prefixProm
+}
+
+// NewPromHandler creates a new handler at /api/v1/prom to receive the remote
+// writes and serve the remote reads of Prometheus.
+func NewPromHandler(log *zap.Logger, b *PromBackend) *PromHandler {
+	h := &PromHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		PointsWriter:        b.PointsWriter,
+		ReadStore:           b.ReadStore,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", promWritePath, h.handlePostWrite)
+	h.HandlerFunc("POST", promReadPath, h.handlePostRead)
+	return h
+}
+
+func (h *PromHandler) handlePostWrite(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	org, bucket, err := findPromBucket(ctx, r, h.OrganizationService, h.BucketService, influxdb.WriteAction)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req prompb.WriteRequest
+	if err := decodePromRequest(r, &req); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	points, err := promWritePoints(org.ID, bucket.ID, &req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	points, dropped, err := promFilterPoints(ctx, bucket, points)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if len(points) > 0 {
+		if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+			h.log.Error("Error writing points", zap.Error(err))
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   "http/handlePostPromWrite",
+				Msg:  "unexpected error writing points to database",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
+	if dropped > 0 {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   "http/handlePostPromWrite",
+			Msg:  fmt.Sprintf("partial write: %d points outside of the series scope of the authorization were dropped", dropped),
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PromHandler) handlePostRead(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	org, bucket, err := findPromBucket(ctx, r, h.OrganizationService, h.BucketService, influxdb.ReadAction)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req prompb.ReadRequest
+	if err := decodePromRequest(r, &req); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	resp := &prompb.ReadResponse{
+		Results: make([]*prompb.QueryResult, len(req.Queries)),
+	}
+	for i, q := range req.Queries {
+		res, err := h.readQuery(ctx, org.ID, bucket.ID, q)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		resp.Results[i] = res
+	}
+
+	data, err := resp.Marshal()
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   "http/handlePostPromRead",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Header().Set("Content-Encoding", "snappy")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(snappy.Encode(nil, data)); err != nil {
+		h.log.Info("Failed to write remote read response", zap.Error(err))
+	}
+}
+
+// findPromBucket returns the organization and bucket of the request if the
+// authorizer of the request is allowed action on the bucket.
+func findPromBucket(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService, bucketSvc influxdb.BucketService, action influxdb.Action) (*influxdb.Organization, *influxdb.Bucket, error) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	org, err := queryOrganization(ctx, r, orgSvc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bucket, err := queryBucket(ctx, r, bucketSvc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bucket.OrgID != org.ID {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  "bucket not found",
+		}
+	}
+
+	p, err := influxdb.NewPermissionAtID(bucket.ID, action, influxdb.BucketsResourceType, org.ID)
+	if err != nil {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		}
+	}
+	if !a.Allowed(*p) {
+		return nil, nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  fmt.Sprintf("insufficient permissions for %s", action),
+		}
+	}
+	return org, bucket, nil
+}
+
+// promFilterPoints returns the points of the series of the bucket the
+// authorizer of the context may write and the number of points dropped.
+func promFilterPoints(ctx context.Context, bucket *influxdb.Bucket, points []models.Point) ([]models.Point, int, error) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	p, err := influxdb.NewPermissionAtID(bucket.ID, influxdb.WriteAction, influxdb.BucketsResourceType, bucket.OrgID)
+	if err != nil {
+		return nil, 0, err
+	}
+	scopes := influxdb.SeriesScopes(a, *p)
+	if len(scopes) == 0 {
+		return points, 0, nil
+	}
+	return filterPoints(points, scopes)
+}
+
+type promMessage interface {
+	Unmarshal([]byte) error
+}
+
+// decodePromRequest decodes the snappy compressed protobuf message of the
+// body of r into m.
+func decodePromRequest(r *http.Request, m promMessage) error {
+	compressed, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("unable to read data: %v", err),
+			Err:  err,
+		}
+	}
+
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "request body is not snappy compressed",
+			Err:  err,
+		}
+	}
+
+	if err := m.Unmarshal(data); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid protobuf message",
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// promWritePoints returns the points of the samples of req to write to the
+// bucket. Samples which are not finite, such as the staleness markers of
+// Prometheus, are dropped.
+func promWritePoints(orgID, bucketID influxdb.ID, req *prompb.WriteRequest) ([]models.Point, error) {
+	name := tsdb.EncodeName(orgID, bucketID)
+
+	var points []models.Point
+	for _, ts := range req.Timeseries {
+		tags := make(models.Tags, 0, len(ts.Labels)+2)
+		var metric string
+		for _, l := range ts.Labels {
+			switch {
+			case l.Name == promMetricName:
+				metric = l.Value
+			case l.Value != "":
+				tags = append(tags, models.NewTag([]byte(l.Name), []byte(l.Value)))
+			}
+		}
+		if metric == "" {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "time series is missing the metric name label " + promMetricName,
+			}
+		}
+		tags = append(tags,
+			models.NewTag(models.MeasurementTagKeyBytes, []byte(metric)),
+			models.NewTag(models.FieldKeyTagKeyBytes, []byte(promFieldKey)))
+		sort.Sort(tags)
+
+		for _, s := range ts.Samples {
+			if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+				continue
+			}
+			pt, err := models.NewPoint(string(name[:]), tags, models.Fields{promFieldKey: s.Value}, time.Unix(0, s.Timestamp*int64(time.Millisecond)))
+			if err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("invalid sample of %s: %v", metric, err),
+					Err:  err,
+				}
+			}
+			points = append(points, pt)
+		}
+	}
+	return points, nil
+}
+
+// readQuery returns the time series of the bucket matching q.
+func (h *PromHandler) readQuery(ctx context.Context, orgID, bucketID influxdb.ID, q *prompb.Query) (*prompb.QueryResult, error) {
+	pred, err := promMatchersPredicate(q.Matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := types.MarshalAny(h.ReadStore.GetSource(uint64(orgID), uint64(bucketID)))
+	if err != nil {
+		return nil, err
+	}
+
+	var req datatypes.ReadFilterRequest
+	req.ReadSource = src
+	req.Predicate = pred
+	req.Range.Start = q.StartTimestampMs * int64(time.Millisecond)
+	// The end of the range of a read request is exclusive.
+	req.Range.End = q.EndTimestampMs*int64(time.Millisecond) + 1
+
+	res := &prompb.QueryResult{}
+	rs, err := h.ReadStore.ReadFilter(ctx, &req)
+	if err != nil {
+		return nil, err
+	} else if rs == nil {
+		return res, nil
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		ts := &prompb.TimeSeries{Labels: promLabels(rs.Tags())}
+		cur := rs.Cursor()
+		switch cur := cur.(type) {
+		case cursors.FloatArrayCursor:
+			for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+				for i, t := range a.Timestamps {
+					ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: t / int64(time.Millisecond), Value: a.Values[i]})
+				}
+			}
+		case cursors.IntegerArrayCursor:
+			for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+				for i, t := range a.Timestamps {
+					ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: t / int64(time.Millisecond), Value: float64(a.Values[i])})
+				}
+			}
+		case cursors.UnsignedArrayCursor:
+			for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+				for i, t := range a.Timestamps {
+					ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: t / int64(time.Millisecond), Value: float64(a.Values[i])})
+				}
+			}
+		}
+		cur.Close()
+
+		if len(ts.Samples) > 0 {
+			res.Timeseries = append(res.Timeseries, ts)
+		}
+	}
+	return res, rs.Err()
+}
+
+// promLabels returns the labels of the series with tags. The store names the
+// measurement and field tags of a series _measurement and _field.
+func promLabels(tags models.Tags) []prompb.Label {
+	labels := make([]prompb.Label, 0, len(tags))
+	for _, t := range tags {
+		switch string(t.Key) {
+		case "_measurement":
+			labels = append(labels, prompb.Label{Name: promMetricName, Value: string(t.Value)})
+		case "_field":
+		default:
+			labels = append(labels, prompb.Label{Name: string(t.Key), Value: string(t.Value)})
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
+
+// promMatchersPredicate returns the predicate of the series of the value
+// field whose tags match matchers.
+func promMatchersPredicate(matchers []*prompb.LabelMatcher) (*datatypes.Predicate, error) {
+	root := promTagComparison(models.FieldKeyTagKey, datatypes.ComparisonEqual, &datatypes.Node{NodeType: datatypes.NodeTypeLiteral, Value: &datatypes.Node_StringValue{StringValue: promFieldKey}})
+	for _, m := range matchers {
+		key := m.Name
+		if key == promMetricName {
+			key = models.MeasurementTagKey
+		}
+
+		var n *datatypes.Node
+		switch m.Type {
+		case prompb.LabelMatcher_EQ:
+			n = promTagComparison(key, datatypes.ComparisonEqual, &datatypes.Node{NodeType: datatypes.NodeTypeLiteral, Value: &datatypes.Node_StringValue{StringValue: m.Value}})
+		case prompb.LabelMatcher_NEQ:
+			n = promTagComparison(key, datatypes.ComparisonNotEqual, &datatypes.Node{NodeType: datatypes.NodeTypeLiteral, Value: &datatypes.Node_StringValue{StringValue: m.Value}})
+		case prompb.LabelMatcher_RE:
+			// The regular expressions of Prometheus are anchored.
+			n = promTagComparison(key, datatypes.ComparisonRegex, &datatypes.Node{NodeType: datatypes.NodeTypeLiteral, Value: &datatypes.Node_RegexValue{RegexValue: "^(?:" + m.Value + ")$"}})
+		case prompb.LabelMatcher_NRE:
+			n = promTagComparison(key, datatypes.ComparisonNotRegex, &datatypes.Node{NodeType: datatypes.NodeTypeLiteral, Value: &datatypes.Node_RegexValue{RegexValue: "^(?:" + m.Value + ")$"}})
+		default:
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("unsupported label matcher type %d", m.Type),
+			}
+		}
+
+		root = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLogicalExpression,
+			Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalAnd},
+			Children: []*datatypes.Node{root, n},
+		}
+	}
+	return &datatypes.Predicate{Root: root}, nil
+}
+
+func promTagComparison(key string, cmp datatypes.Node_Comparison, literal *datatypes.Node) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: cmp},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			literal,
+		},
+	}
+}
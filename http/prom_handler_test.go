@@ -0,0 +1,172 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/prometheus/prompb"
+	"github.com/influxdata/influxdb/storage/reads"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPromHandler_handlePostWrite(t *testing.T) {
+	series := prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: "http_requests_total"},
+			{Name: "job", Value: "api"},
+			{Name: "instance", Value: ""},
+		},
+		Samples: []prompb.Sample{
+			{Value: 1, Timestamp: 946684800000},
+			{Value: 2, Timestamp: 946684801000},
+		},
+	}
+	scoped := prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: "m1"},
+			{Name: "host", Value: "web1"},
+		},
+		Samples: []prompb.Sample{{Value: 1, Timestamp: 946684800000}},
+	}
+	unnamed := prompb.TimeSeries{
+		Labels:  []prompb.Label{{Name: "job", Value: "api"}},
+		Samples: []prompb.Sample{{Value: 1, Timestamp: 946684800000}},
+	}
+
+	type point struct {
+		tags  string
+		value float64
+		time  int64
+	}
+
+	tests := []struct {
+		name   string
+		auth   influxdb.Authorizer
+		body   []byte
+		code   int
+		resp   string
+		points []point
+	}{
+		{
+			name: "samples are written as points",
+			auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body: mustEncodePromWrite(t, series),
+			code: 204,
+			points: []point{
+				{tags: "\x00=http_requests_total,job=api,\xff=value", value: 1, time: 946684800000000000},
+				{tags: "\x00=http_requests_total,job=api,\xff=value", value: 2, time: 946684801000000000},
+			},
+		},
+		{
+			name: "time series without a metric name returns 400",
+			auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body: mustEncodePromWrite(t, unnamed),
+			code: 400,
+			resp: `{"code":"invalid","message":"time series is missing the metric name label __name__"}`,
+		},
+		{
+			name: "uncompressed body returns 400",
+			auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body: []byte("m1,t1=v1 f1=1"),
+			code: 400,
+			resp: `{"code":"invalid","message":"request body is not snappy compressed: snappy: corrupt input"}`,
+		},
+		{
+			name: "forbidden to write with insufficient permission",
+			auth: bucketWritePermission("043e0780ee2b1000", "000000000000000a"),
+			body: mustEncodePromWrite(t, series),
+			code: 403,
+			resp: `{"code":"forbidden","message":"insufficient permissions for write"}`,
+		}, {
+			name: "samples outside of the series scope of the authorization are dropped",
+			auth: bucketWriteScopedPermission("043e0780ee2b1000", "04504b356e23b000"),
+			body: mustEncodePromWrite(t, series, scoped),
+			code: 403,
+			resp: `{"code":"forbidden","message":"partial write: 2 points outside of the series scope of the authorization were dropped"}`,
+			points: []point{
+				{tags: "\x00=m1,host=web1,\xff=value", value: 1, time: 946684800000000000},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationF = func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return testOrg("043e0780ee2b1000"), nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(context.Context, influxdb.BucketFilter) (*influxdb.Bucket, error) {
+				return testBucket("043e0780ee2b1000", "04504b356e23b000"), nil
+			}
+			pw := &mock.PointsWriter{}
+
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: orgs,
+				BucketService:       buckets,
+				PointsWriter:        pw,
+			}
+			promHandler := NewPromHandler(zaptest.NewLogger(t), NewPromBackend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(promHandler, tt.auth)
+
+			r := httptest.NewRequest("POST", "http://localhost:9999/api/v1/prom/write?org=043e0780ee2b1000&bucket=04504b356e23b000", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := w.Body.String(), tt.resp; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+
+			if got, want := len(pw.Points), len(tt.points); got != want {
+				t.Fatalf("unexpected number of points: got %d want %d", got, want)
+			}
+			for i, p := range pw.Points {
+				fields, err := p.Fields()
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := point{tags: string(p.Tags().HashKey()[1:]), value: fields["value"].(float64), time: p.UnixNano()}
+				if want := tt.points[i]; got != want {
+					t.Errorf("unexpected point %d: got %+v want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPromMatchersPredicate(t *testing.T) {
+	pred, err := promMatchersPredicate([]*prompb.LabelMatcher{
+		{Type: prompb.LabelMatcher_EQ, Name: "__name__", Value: "up"},
+		{Type: prompb.LabelMatcher_NRE, Name: "job", Value: "api|web"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "'\xff' = \"value\" AND '\x00' = \"up\" AND 'job' !~ /^(?:api|web)$/"
+	if got := reads.PredicateToExprString(pred); got != want {
+		t.Errorf("unexpected predicate: got %s want %s", got, want)
+	}
+
+	if _, err := promMatchersPredicate([]*prompb.LabelMatcher{{Type: 10, Name: "job"}}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected invalid error for unknown matcher type, got %v", err)
+	}
+}
+
+func mustEncodePromWrite(t *testing.T, series ...prompb.TimeSeries) []byte {
+	t.Helper()
+	data, err := (&prompb.WriteRequest{Timeseries: series}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snappy.Encode(nil, data)
+}
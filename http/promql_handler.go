@@ -0,0 +1,271 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/promql"
+	"go.uber.org/zap"
+)
+
+// PromQLBackend is all services and associated parameters required to
+// construct the PromQLHandler.
+type PromQLBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	ProxyQueryService   query.ProxyQueryService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewPromQLBackend returns a new instance of PromQLBackend.
+func NewPromQLBackend(log *zap.Logger, b *APIBackend) *PromQLBackend {
+	return &PromQLBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		ProxyQueryService:   b.FluxService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// PromQLHandler serves the query API of Prometheus, evaluating PromQL
+// queries of the metrics of a bucket.
+type PromQLHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	ProxyQueryService   query.ProxyQueryService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	prefixPromQLQuery      = "/api/v1/query"
+	prefixPromQLQueryRange = "/api/v1/query_range"
+
+	// promQLMaxPoints is the maximum number of evaluation times of a range
+	// query, as in Prometheus.
+	promQLMaxPoints = 11000
+)
+
+// NewPromQLHandler creates a new handler at /api/v1/query and
+// /api/v1/query_range to evaluate the instant and range queries of PromQL.
+func NewPromQLHandler(log *zap.Logger, b *PromQLBackend) *PromQLHandler {
+	h := &PromQLHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		ProxyQueryService:   b.ProxyQueryService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", prefixPromQLQuery, h.handleQuery)
+	h.HandlerFunc("POST", prefixPromQLQuery, h.handleQuery)
+	h.HandlerFunc("GET", prefixPromQLQueryRange, h.handleQueryRange)
+	h.HandlerFunc("POST", prefixPromQLQueryRange, h.handleQueryRange)
+	return h
+}
+
+func (h *PromQLHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromQLHandler")
+	defer span.Finish()
+
+	if err := r.ParseForm(); err != nil {
+		promQLError(w, influxdb.EInvalid, err)
+		return
+	}
+
+	ts := time.Now()
+	if v := r.Form.Get("time"); v != "" {
+		t, err := parsePromQLTime(v)
+		if err != nil {
+			promQLError(w, influxdb.EInvalid, fmt.Errorf("invalid parameter 'time': %v", err))
+			return
+		}
+		ts = t
+	}
+
+	h.query(w, r, &promql.Compiler{
+		Query: r.Form.Get("query"),
+		Start: ts,
+		End:   ts,
+	})
+}
+
+func (h *PromQLHandler) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromQLHandler")
+	defer span.Finish()
+
+	if err := r.ParseForm(); err != nil {
+		promQLError(w, influxdb.EInvalid, err)
+		return
+	}
+
+	start, err := parsePromQLTime(r.Form.Get("start"))
+	if err != nil {
+		promQLError(w, influxdb.EInvalid, fmt.Errorf("invalid parameter 'start': %v", err))
+		return
+	}
+	end, err := parsePromQLTime(r.Form.Get("end"))
+	if err != nil {
+		promQLError(w, influxdb.EInvalid, fmt.Errorf("invalid parameter 'end': %v", err))
+		return
+	}
+	if end.Before(start) {
+		promQLError(w, influxdb.EInvalid, fmt.Errorf("end timestamp must not be before start time"))
+		return
+	}
+	step, err := parsePromQLDuration(r.Form.Get("step"))
+	if err != nil {
+		promQLError(w, influxdb.EInvalid, fmt.Errorf("invalid parameter 'step': %v", err))
+		return
+	}
+	if step <= 0 {
+		promQLError(w, influxdb.EInvalid, fmt.Errorf("zero or negative query resolution step widths are not accepted. Try a positive integer"))
+		return
+	}
+	if end.Sub(start)/step > promQLMaxPoints {
+		promQLError(w, influxdb.EInvalid, fmt.Errorf("exceeded maximum resolution of %d points per timeseries. Try decreasing the query resolution (?step=XX)", promQLMaxPoints))
+		return
+	}
+
+	h.query(w, r, &promql.Compiler{
+		Query: r.Form.Get("query"),
+		Start: start,
+		End:   end,
+		Step:  step,
+	})
+}
+
+// query evaluates the query of compiler in the bucket of the request.
+func (h *PromQLHandler) query(w http.ResponseWriter, r *http.Request, compiler *promql.Compiler) {
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		promQLError(w, influxdb.EUnauthorized, err)
+		return
+	}
+
+	org, bucket, err := findPromBucket(ctx, r, h.OrganizationService, h.BucketService, influxdb.ReadAction)
+	if err != nil {
+		promQLError(w, influxdb.ErrorCode(err), err)
+		return
+	}
+	compiler.BucketID = bucket.ID
+
+	// Parse errors are the errors of the request.
+	parsed, err := promql.ParsePromQL(compiler.Query)
+	if err != nil {
+		promQLError(w, influxdb.EInvalid, err)
+		return
+	}
+	if _, err := compiler.Transpile(); err != nil {
+		promQLError(w, influxdb.EInvalid, err)
+		return
+	}
+
+	token, err := queryAuthorization(a, org.ID)
+	if err != nil {
+		promQLError(w, influxdb.EUnauthorized, err)
+		return
+	}
+	ctx = pcontext.SetAuthorizer(ctx, token)
+
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  token,
+			OrganizationID: org.ID,
+			Compiler:       compiler,
+		},
+		Dialect: &promql.Dialect{ResultType: promql.Config{Start: compiler.Start, End: compiler.End}.ResultType(parsed)},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error IFF nothing has been written to w.
+			promQLError(w, influxdb.EUnprocessableEntity, err)
+			return
+		}
+		h.log.Info("Error writing response to client",
+			zap.String("handler", "promql"),
+			zap.Error(err),
+		)
+	}
+}
+
+// promQLErrorTypes are the types of the errors of the query API of
+// Prometheus of the codes of errors.
+var promQLErrorTypes = map[string]string{
+	influxdb.EInvalid:             "bad_data",
+	influxdb.ENotFound:            "not_found",
+	influxdb.EUnprocessableEntity: "execution",
+	influxdb.EUnavailable:         "unavailable",
+	influxdb.ETooManyRequests:     "unavailable",
+}
+
+// promQLError writes err as an error of the query API of Prometheus.
+func promQLError(w http.ResponseWriter, code string, err error) {
+	errorType, ok := promQLErrorTypes[code]
+	if !ok {
+		errorType = "internal"
+	}
+	status, ok := statusCodePlatformError[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(promql.Response{
+		Status:    "error",
+		ErrorType: errorType,
+		Error:     err.Error(),
+	})
+}
+
+// parsePromQLTime parses a time in seconds since the epoch or in RFC 3339.
+func parsePromQLTime(s string) (time.Time, error) {
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		s, ns := math.Modf(t)
+		ns = math.Round(ns*1000) / 1000
+		return time.Unix(int64(s), int64(ns*float64(time.Second))), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+// parsePromQLDuration parses a duration in seconds or as a Go duration.
+func parsePromQLDuration(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil {
+		ts := d * float64(time.Second)
+		if ts > float64(math.MaxInt64) || ts < float64(math.MinInt64) {
+			return 0, fmt.Errorf("cannot parse %q to a valid duration. It overflows int64", s)
+		}
+		return time.Duration(ts), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
@@ -0,0 +1,571 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixRoles = "/api/v2/roles"
+)
+
+// RoleBackend is all services and associated parameters required to construct
+// the RoleHandler.
+type RoleBackend struct {
+	influxdb.HTTPErrorHandler
+	log         *zap.Logger
+	RoleService influxdb.RoleService
+}
+
+// NewRoleBackend creates a backend used by the role handler.
+func NewRoleBackend(log *zap.Logger, b *APIBackend) *RoleBackend {
+	return &RoleBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		RoleService:      b.RoleService,
+	}
+}
+
+// RoleHandler is the handler for the role service
+type RoleHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RoleService influxdb.RoleService
+}
+
+// NewRoleHandler creates a new RoleHandler
+func NewRoleHandler(log *zap.Logger, b *RoleBackend) *RoleHandler {
+	h := &RoleHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		RoleService: b.RoleService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixRoles)
+	membersPath := fmt.Sprintf("%s/:id/members", prefixRoles)
+
+	h.HandlerFunc("GET", prefixRoles, h.handleGetRoles)
+	h.HandlerFunc("POST", prefixRoles, h.handlePostRole)
+	h.HandlerFunc("GET", entityPath, h.handleGetRole)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchRole)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteRole)
+
+	h.HandlerFunc("GET", membersPath, h.handleGetRoleMembers)
+	h.HandlerFunc("POST", membersPath, h.handlePostRoleMember)
+	h.HandlerFunc("DELETE", membersPath+"/:userID", h.handleDeleteRoleMember)
+
+	return h
+}
+
+type roleLinks struct {
+	Self    string `json:"self"`
+	Members string `json:"members"`
+	Org     string `json:"org"`
+}
+
+type roleResponse struct {
+	*influxdb.Role
+	Links roleLinks `json:"links"`
+}
+
+func newRoleResponse(r *influxdb.Role) roleResponse {
+	return roleResponse{
+		Role: r,
+		Links: roleLinks{
+			Self:    fmt.Sprintf("%s/%s", prefixRoles, r.ID),
+			Members: fmt.Sprintf("%s/%s/members", prefixRoles, r.ID),
+			Org:     fmt.Sprintf("/api/v2/orgs/%s", r.OrgID),
+		},
+	}
+}
+
+type getRolesResponse struct {
+	Roles []roleResponse        `json:"roles"`
+	Links *influxdb.PagingLinks `json:"links"`
+}
+
+func (r getRolesResponse) toInfluxDB() []*influxdb.Role {
+	roles := make([]*influxdb.Role, len(r.Roles))
+	for i := range r.Roles {
+		roles[i] = r.Roles[i].Role
+	}
+	return roles
+}
+
+func newGetRolesResponse(roles []*influxdb.Role, f influxdb.RoleFilter, opts influxdb.FindOptions) getRolesResponse {
+	num := len(roles)
+	resp := getRolesResponse{
+		Roles: make([]roleResponse, 0, num),
+		Links: newPagingLinks(prefixRoles, opts, f, num),
+	}
+
+	for _, r := range roles {
+		resp.Roles = append(resp.Roles, newRoleResponse(r))
+	}
+
+	return resp
+}
+
+type roleMemberLinks struct {
+	Self string `json:"self"`
+	User string `json:"user"`
+}
+
+type roleMemberResponse struct {
+	*influxdb.RoleAssignment
+	Links roleMemberLinks `json:"links"`
+}
+
+func newRoleMemberResponse(a *influxdb.RoleAssignment) roleMemberResponse {
+	return roleMemberResponse{
+		RoleAssignment: a,
+		Links: roleMemberLinks{
+			Self: fmt.Sprintf("%s/%s/members/%s", prefixRoles, a.RoleID, a.UserID),
+			User: fmt.Sprintf("%s/%s", prefixUsers, a.UserID),
+		},
+	}
+}
+
+type getRoleMembersResponse struct {
+	Members []roleMemberResponse `json:"members"`
+	Links   map[string]string    `json:"links"`
+}
+
+func newGetRoleMembersResponse(roleID influxdb.ID, as []*influxdb.RoleAssignment) getRoleMembersResponse {
+	resp := getRoleMembersResponse{
+		Members: make([]roleMemberResponse, 0, len(as)),
+		Links: map[string]string{
+			"self": fmt.Sprintf("%s/%s/members", prefixRoles, roleID),
+		},
+	}
+
+	for _, a := range as {
+		resp.Members = append(resp.Members, newRoleMemberResponse(a))
+	}
+
+	return resp
+}
+
+type getRolesRequest struct {
+	filter influxdb.RoleFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetRolesRequest(ctx context.Context, r *http.Request) (*getRolesRequest, error) {
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getRolesRequest{
+		opts: *opts,
+	}
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = id
+	}
+
+	if name := qp.Get("name"); name != "" {
+		req.filter.Name = &name
+	}
+
+	if userID := qp.Get("userID"); userID != "" {
+		id, err := influxdb.IDFromString(userID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.UserID = id
+	}
+
+	return req, nil
+}
+
+func (h *RoleHandler) handleGetRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetRolesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	roles, _, err := h.RoleService.FindRoles(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Roles retrieved", zap.String("roles", fmt.Sprint(roles)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetRolesResponse(roles, req.filter, req.opts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func requestRoleID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *RoleHandler) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	role, err := h.RoleService.FindRoleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role retrieved", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodePostRoleRequest(r *http.Request) (*influxdb.Role, error) {
+	role := &influxdb.Role{}
+	if err := json.NewDecoder(r.Body).Decode(role); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}
+	}
+
+	if err := role.Valid(); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (h *RoleHandler) handlePostRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	role, err := decodePostRoleRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.CreateRole(ctx, role); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role created", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *RoleHandler) handlePatchRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.RoleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+
+	if err := upd.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	role, err := h.RoleService.UpdateRole(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role updated", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *RoleHandler) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.DeleteRole(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role deleted", zap.String("roleID", fmt.Sprint(id)))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RoleHandler) handleGetRoleMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// the role is looked up so that an unknown role is not found, rather
+	// than without members.
+	if _, err := h.RoleService.FindRoleByID(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	as, _, err := h.RoleService.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{RoleID: &id})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role members retrieved", zap.String("members", fmt.Sprint(as)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetRoleMembersResponse(id, as)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *RoleHandler) handlePostRoleMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a := &influxdb.RoleAssignment{}
+	if err := json.NewDecoder(r.Body).Decode(a); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+	a.RoleID = id
+
+	if err := a.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.CreateRoleAssignment(ctx, a); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role assigned", zap.String("roleID", a.RoleID.String()), zap.String("userID", a.UserID.String()))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRoleMemberResponse(a)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *RoleHandler) handleDeleteRoleMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	userID, err := influxdb.IDFromString(httprouter.ParamsFromContext(ctx).ByName("userID"))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.DeleteRoleAssignment(ctx, id, *userID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role unassigned", zap.String("roleID", id.String()), zap.String("userID", userID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RoleService is a role service over HTTP to the influxdb server
+type RoleService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// FindRoleByID finds a single role from the store by its ID
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	var resp roleResponse
+	err := s.Client.
+		Get(prefixRoles, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Role, nil
+}
+
+// FindRoles returns a list of roles that match filter.
+// Additional options provide pagination & sorting.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opts ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	if filter.ID != nil {
+		role, err := s.FindRoleByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*influxdb.Role{role}, 1, nil
+	}
+
+	params := findOptionParams(opts...)
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.Name != nil {
+		params = append(params, [2]string{"name", *filter.Name})
+	}
+	if filter.UserID != nil {
+		params = append(params, [2]string{"userID", filter.UserID.String()})
+	}
+
+	var resp getRolesResponse
+	err := s.Client.
+		Get(prefixRoles).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	roles := resp.toInfluxDB()
+	return roles, len(roles), nil
+}
+
+// CreateRole creates a new role and assigns it an influxdb.ID
+func (s *RoleService) CreateRole(ctx context.Context, role *influxdb.Role) error {
+	if err := role.Valid(); err != nil {
+		return err
+	}
+
+	return s.Client.
+		PostJSON(role, prefixRoles).
+		DecodeJSON(role).
+		Do(ctx)
+}
+
+// UpdateRole updates a single role with a changeset
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	var resp roleResponse
+	err := s.Client.
+		PatchJSON(upd, prefixRoles, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Role, nil
+}
+
+// DeleteRole removes a role from the store
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(prefixRoles, id.String()).
+		Do(ctx)
+}
+
+// FindRoleAssignments returns the assignments of the role of the filter, or
+// of the roles assigned to the user of the filter if it has no role.
+func (s *RoleService) FindRoleAssignments(ctx context.Context, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+	if filter.RoleID == nil {
+		if filter.UserID == nil {
+			return nil, 0, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "role assignments must be filtered by role or user",
+			}
+		}
+		roles, _, err := s.FindRoles(ctx, influxdb.RoleFilter{UserID: filter.UserID})
+		if err != nil {
+			return nil, 0, err
+		}
+		as := make([]*influxdb.RoleAssignment, 0, len(roles))
+		for _, r := range roles {
+			as = append(as, &influxdb.RoleAssignment{RoleID: r.ID, UserID: *filter.UserID})
+		}
+		return as, len(as), nil
+	}
+
+	var resp getRoleMembersResponse
+	err := s.Client.
+		Get(prefixRoles, filter.RoleID.String(), "members").
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	as := make([]*influxdb.RoleAssignment, 0, len(resp.Members))
+	for _, m := range resp.Members {
+		if filter.UserID != nil && m.UserID != *filter.UserID {
+			continue
+		}
+		as = append(as, m.RoleAssignment)
+	}
+	return as, len(as), nil
+}
+
+// CreateRoleAssignment assigns a role to a user
+func (s *RoleService) CreateRoleAssignment(ctx context.Context, a *influxdb.RoleAssignment) error {
+	if err := a.Valid(); err != nil {
+		return err
+	}
+
+	return s.Client.
+		PostJSON(a, prefixRoles, a.RoleID.String(), "members").
+		Do(ctx)
+}
+
+// DeleteRoleAssignment unassigns a role from a user
+func (s *RoleService) DeleteRoleAssignment(ctx context.Context, roleID, userID influxdb.ID) error {
+	return s.Client.
+		Delete(prefixRoles, roleID.String(), "members", userID.String()).
+		Do(ctx)
+}
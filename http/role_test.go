@@ -0,0 +1,184 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// NewMockRoleBackend returns a RoleBackend with mock services.
+func NewMockRoleBackend(t *testing.T) *RoleBackend {
+	return &RoleBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		log:              zaptest.NewLogger(t),
+		RoleService:      mock.NewRoleService(),
+	}
+}
+
+func TestRoleService_handlePostRole(t *testing.T) {
+	type args struct {
+		role string
+	}
+	type wants struct {
+		statusCode  int
+		contentType string
+		body        string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "create a role",
+			args: args{
+				role: `
+{
+  "orgID": "0000000000000001",
+  "name": "dashboard-editor",
+  "permissions": [
+    {"action": "write", "resource": {"type": "dashboards", "orgID": "0000000000000001"}}
+  ]
+}
+`,
+			},
+			wants: wants{
+				statusCode:  201,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "name": "dashboard-editor",
+  "permissions": [
+    {"action": "write", "resource": {"type": "dashboards", "orgID": "0000000000000001"}}
+  ],
+  "createdAt": "2006-05-04T01:02:03Z",
+  "updatedAt": "2006-05-04T01:02:03Z",
+  "links": {
+    "self": "/api/v2/roles/0000000000000002",
+    "members": "/api/v2/roles/0000000000000002/members",
+    "org": "/api/v2/orgs/0000000000000001"
+  }
+}
+`,
+			},
+		},
+		{
+			name: "create a role with a permission outside of its organization",
+			args: args{
+				role: `
+{
+  "orgID": "0000000000000001",
+  "name": "dashboard-editor",
+  "permissions": [
+    {"action": "write", "resource": {"type": "dashboards", "orgID": "0000000000000003"}}
+  ]
+}
+`,
+			},
+			wants: wants{
+				statusCode:  400,
+				contentType: "application/json; charset=utf-8",
+				body:        `{"code":"invalid","message":"role permission write:orgs/0000000000000003/dashboards is not on the resources of organization 0000000000000001"}`,
+			},
+		},
+		{
+			name: "create a role without permissions",
+			args: args{
+				role: `{"orgID": "0000000000000001", "name": "dashboard-editor"}`,
+			},
+			wants: wants{
+				statusCode:  400,
+				contentType: "application/json; charset=utf-8",
+				body:        `{"code":"invalid","message":"role must have at least one permission"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roleBackend := NewMockRoleBackend(t)
+			roleBackend.RoleService = &mock.RoleService{
+				CreateRoleFn: func(ctx context.Context, r *influxdb.Role) error {
+					r.ID = 2
+					r.CreatedAt = faketime
+					r.UpdatedAt = faketime
+					return nil
+				},
+			}
+			h := NewRoleHandler(zaptest.NewLogger(t), roleBackend)
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/roles", bytes.NewReader([]byte(tt.args.role)))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			contentType := res.Header.Get("Content-Type")
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("got = %v, want %v: %s", res.StatusCode, tt.wants.statusCode, body)
+			}
+			if contentType != tt.wants.contentType {
+				t.Errorf("got = %v, want %v", contentType, tt.wants.contentType)
+			}
+			if tt.wants.body == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+				t.Errorf("%q, error unmarshaling json %v", tt.name, err)
+			} else if !eq {
+				t.Errorf("%q. ***%s***", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestRoleService_handlePostRoleMember(t *testing.T) {
+	var assigned *influxdb.RoleAssignment
+	roleBackend := NewMockRoleBackend(t)
+	roleBackend.RoleService = &mock.RoleService{
+		CreateRoleAssignmentFn: func(ctx context.Context, a *influxdb.RoleAssignment) error {
+			assigned = a
+			return nil
+		},
+	}
+	h := NewRoleHandler(zaptest.NewLogger(t), roleBackend)
+	r := httptest.NewRequest("POST", "http://any.url/api/v2/roles/0000000000000002/members", bytes.NewReader([]byte(`{"userID": "0000000000000004"}`)))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != 201 {
+		t.Fatalf("got = %v, want %v: %s", res.StatusCode, 201, body)
+	}
+	if assigned == nil || assigned.RoleID != 2 || assigned.UserID != 4 {
+		t.Fatalf("unexpected role assignment %v", assigned)
+	}
+
+	want := `
+{
+  "roleID": "0000000000000002",
+  "userID": "0000000000000004",
+  "links": {
+    "self": "/api/v2/roles/0000000000000002/members/0000000000000004",
+    "user": "/api/v2/users/0000000000000004"
+  }
+}
+`
+	if eq, diff, err := jsonEqual(string(body), want); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+}
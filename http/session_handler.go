@@ -6,6 +6,7 @@ import (
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/oidc"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +24,10 @@ type SessionBackend struct {
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
 	UserService      platform.UserService
+	OIDCService      *oidc.Service
+	// Authenticator checks the credentials of the sign in; the password of
+	// the user is compared with the PasswordsService if nil.
+	Authenticator platform.Authenticator
 }
 
 // newSessionBackend creates a new SessionBackend with associated logger.
@@ -34,6 +39,8 @@ func newSessionBackend(log *zap.Logger, b *APIBackend) *SessionBackend {
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
 		UserService:      b.UserService,
+		OIDCService:      b.OIDCService,
+		Authenticator:    b.Authenticator,
 	}
 }
 
@@ -46,6 +53,8 @@ type SessionHandler struct {
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
 	UserService      platform.UserService
+	OIDCService      *oidc.Service
+	Authenticator    platform.Authenticator
 }
 
 // NewSessionHandler returns a new instance of SessionHandler.
@@ -58,10 +67,16 @@ func NewSessionHandler(log *zap.Logger, b *SessionBackend) *SessionHandler {
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
 		UserService:      b.UserService,
+		OIDCService:      b.OIDCService,
+		Authenticator:    b.Authenticator,
 	}
 
 	h.HandlerFunc("POST", prefixSignIn, h.handleSignin)
 	h.HandlerFunc("POST", prefixSignOut, h.handleSignout)
+	if h.OIDCService != nil {
+		h.HandlerFunc("GET", prefixSignInOIDC, h.handleSigninOIDC)
+		h.HandlerFunc("GET", prefixSignInOIDCCallback, h.handleSigninOIDCCallback)
+	}
 	return h
 }
 
@@ -75,15 +90,7 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	u, err := h.UserService.FindUser(ctx, platform.UserFilter{
-		Name: &req.Username,
-	})
-	if err != nil {
-		UnauthorizedError(ctx, h, w)
-		return
-	}
-
-	if err := h.PasswordsService.ComparePassword(ctx, u.ID, req.Password); err != nil {
+	if err := h.authenticate(ctx, req); err != nil {
 		// Don't log here, it should already be handled by the service
 		UnauthorizedError(ctx, h, w)
 		return
@@ -99,6 +106,21 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *SessionHandler) authenticate(ctx context.Context, req *signinRequest) error {
+	if h.Authenticator != nil {
+		_, err := h.Authenticator.Authenticate(ctx, req.Username, req.Password)
+		return err
+	}
+
+	u, err := h.UserService.FindUser(ctx, platform.UserFilter{
+		Name: &req.Username,
+	})
+	if err != nil {
+		return err
+	}
+	return h.PasswordsService.ComparePassword(ctx, u.ID, req.Password)
+}
+
 type signinRequest struct {
 	Username string
 	Password string
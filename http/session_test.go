@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,7 +20,8 @@ func NewMockSessionBackend(t *testing.T) *SessionBackend {
 		return &platform.User{ID: 1}, nil
 	}
 	return &SessionBackend{
-		log: zaptest.NewLogger(t),
+		log:              zaptest.NewLogger(t),
+		HTTPErrorHandler: ErrorHandler(0),
 
 		SessionService:   mock.NewSessionService(),
 		PasswordsService: mock.NewPasswordsService(),
@@ -31,6 +33,7 @@ func TestSessionHandler_handleSignin(t *testing.T) {
 	type fields struct {
 		PasswordsService platform.PasswordsService
 		SessionService   platform.SessionService
+		Authenticator    platform.Authenticator
 	}
 	type args struct {
 		user     string
@@ -76,6 +79,62 @@ func TestSessionHandler_handleSignin(t *testing.T) {
 				code:   http.StatusNoContent,
 			},
 		},
+		{
+			name: "successful authenticator",
+			fields: fields{
+				SessionService: &mock.SessionService{
+					CreateSessionFn: func(_ context.Context, user string) (*platform.Session, error) {
+						if user != "user1" {
+							return nil, fmt.Errorf("unexpected session user %q", user)
+						}
+						return &platform.Session{
+							ID:        platform.ID(0),
+							Key:       "abc123xyz",
+							CreatedAt: time.Date(2018, 9, 26, 0, 0, 0, 0, time.UTC),
+							ExpiresAt: time.Date(2030, 9, 26, 0, 0, 0, 0, time.UTC),
+							UserID:    platform.ID(1),
+						}, nil
+					},
+				},
+				PasswordsService: mock.NewPasswordsService(),
+				Authenticator: &mock.Authenticator{
+					AuthenticateFn: func(_ context.Context, username, password string) (*platform.User, error) {
+						return &platform.User{ID: 1, Name: username}, nil
+					},
+				},
+			},
+			args: args{
+				user:     "user1",
+				password: "supersecret",
+			},
+			wants: wants{
+				cookie: "session=abc123xyz",
+				code:   http.StatusNoContent,
+			},
+		},
+		{
+			name: "failed authenticator",
+			fields: fields{
+				SessionService: mock.NewSessionService(),
+				PasswordsService: &mock.PasswordsService{
+					ComparePasswordFn: func(context.Context, platform.ID, string) error {
+						return nil
+					},
+				},
+				Authenticator: &mock.Authenticator{
+					AuthenticateFn: func(context.Context, string, string) (*platform.User, error) {
+						return nil, platform.ErrAuthenticationFailed
+					},
+				},
+			},
+			args: args{
+				user:     "user1",
+				password: "supersecret",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -83,6 +142,7 @@ func TestSessionHandler_handleSignin(t *testing.T) {
 			b := NewMockSessionBackend(t)
 			b.PasswordsService = tt.fields.PasswordsService
 			b.SessionService = tt.fields.SessionService
+			b.Authenticator = tt.fields.Authenticator
 			h := NewSessionHandler(zaptest.NewLogger(t), b)
 
 			w := httptest.NewRecorder()
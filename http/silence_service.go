@@ -0,0 +1,407 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"github.com/influxdata/influxdb/predicate"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixSilences = "/api/v2/silences"
+)
+
+// SilenceBackend is all services and associated parameters required to construct
+// the SilenceHandler.
+type SilenceBackend struct {
+	influxdb.HTTPErrorHandler
+	log            *zap.Logger
+	SilenceService influxdb.SilenceService
+}
+
+// NewSilenceBackend creates a backend used by the silence handler.
+func NewSilenceBackend(log *zap.Logger, b *APIBackend) *SilenceBackend {
+	return &SilenceBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		SilenceService:   b.SilenceService,
+	}
+}
+
+// SilenceHandler is the handler for the silence service
+type SilenceHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	SilenceService influxdb.SilenceService
+}
+
+// NewSilenceHandler creates a new SilenceHandler
+func NewSilenceHandler(log *zap.Logger, b *SilenceBackend) *SilenceHandler {
+	h := &SilenceHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		SilenceService: b.SilenceService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixSilences)
+
+	h.HandlerFunc("GET", prefixSilences, h.handleGetSilences)
+	h.HandlerFunc("POST", prefixSilences, h.handlePostSilence)
+	h.HandlerFunc("GET", entityPath, h.handleGetSilence)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchSilence)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteSilence)
+
+	return h
+}
+
+type silenceLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+}
+
+type silenceResponse struct {
+	*influxdb.Silence
+	Links silenceLinks `json:"links"`
+}
+
+func newSilenceResponse(s *influxdb.Silence) silenceResponse {
+	return silenceResponse{
+		Silence: s,
+		Links: silenceLinks{
+			Self: fmt.Sprintf("%s/%s", prefixSilences, s.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", s.OrgID),
+		},
+	}
+}
+
+type getSilencesResponse struct {
+	Silences []silenceResponse     `json:"silences"`
+	Links    *influxdb.PagingLinks `json:"links"`
+}
+
+func (r getSilencesResponse) toInfluxDB() []*influxdb.Silence {
+	silences := make([]*influxdb.Silence, len(r.Silences))
+	for i := range r.Silences {
+		silences[i] = r.Silences[i].Silence
+	}
+	return silences
+}
+
+func newGetSilencesResponse(silences []*influxdb.Silence, f influxdb.SilenceFilter, opts influxdb.FindOptions) getSilencesResponse {
+	num := len(silences)
+	resp := getSilencesResponse{
+		Silences: make([]silenceResponse, 0, num),
+		Links:    newPagingLinks(prefixSilences, opts, f, num),
+	}
+
+	for _, s := range silences {
+		resp.Silences = append(resp.Silences, newSilenceResponse(s))
+	}
+
+	return resp
+}
+
+type getSilencesRequest struct {
+	filter influxdb.SilenceFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetSilencesRequest(ctx context.Context, r *http.Request) (*getSilencesRequest, error) {
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getSilencesRequest{
+		opts: *opts,
+	}
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = id
+	}
+
+	if checkID := qp.Get("checkID"); checkID != "" {
+		id, err := influxdb.IDFromString(checkID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.CheckID = id
+	}
+
+	if ruleID := qp.Get("ruleID"); ruleID != "" {
+		id, err := influxdb.IDFromString(ruleID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.RuleID = id
+	}
+
+	return req, nil
+}
+
+func (h *SilenceHandler) handleGetSilences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetSilencesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	silences, _, err := h.SilenceService.FindSilences(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silences retrieved", zap.String("silences", fmt.Sprint(silences)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetSilencesResponse(silences, req.filter, req.opts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func requestSilenceID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	urlID := params.ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+
+	return *id, nil
+}
+
+func (h *SilenceHandler) handleGetSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	silence, err := h.SilenceService.FindSilenceByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence retrieved", zap.String("silence", fmt.Sprint(silence)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilenceResponse(silence)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// silenceRequest is the body of a silence creation. Its tag rules can be
+// given as a delete predicate, e.g. `host="db1" and env="prod"`.
+type silenceRequest struct {
+	*influxdb.Silence
+	Predicate string `json:"predicate,omitempty"`
+}
+
+func decodePostSilenceRequest(r *http.Request) (*influxdb.Silence, error) {
+	req := &silenceRequest{Silence: &influxdb.Silence{}}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}
+	}
+
+	if req.Predicate != "" {
+		node, err := predicate.Parse(req.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		trs, err := predicate.TagRules(node)
+		if err != nil {
+			return nil, err
+		}
+		req.TagRules = append(req.TagRules, trs...)
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	return req.Silence, nil
+}
+
+func (h *SilenceHandler) handlePostSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	silence, err := decodePostSilenceRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.SilenceService.CreateSilence(ctx, silence); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence created", zap.String("silence", fmt.Sprint(silence)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newSilenceResponse(silence)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *SilenceHandler) handlePatchSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.SilenceUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+
+	if err := upd.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	silence, err := h.SilenceService.UpdateSilence(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence updated", zap.String("silence", fmt.Sprint(silence)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilenceResponse(silence)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *SilenceHandler) handleDeleteSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.SilenceService.DeleteSilence(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence deleted", zap.String("silenceID", fmt.Sprint(id)))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SilenceService is a silence service over HTTP to the influxdb server
+type SilenceService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// FindSilenceByID finds a single silence from the store by its ID
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var resp silenceResponse
+	err := s.Client.
+		Get(prefixSilences, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Silence, nil
+}
+
+// FindSilences returns a list of silences that match filter.
+// Additional options provide pagination & sorting.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opts ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	if filter.ID != nil {
+		silence, err := s.FindSilenceByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*influxdb.Silence{silence}, 1, nil
+	}
+
+	params := findOptionParams(opts...)
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.CheckID != nil {
+		params = append(params, [2]string{"checkID", filter.CheckID.String()})
+	}
+	if filter.RuleID != nil {
+		params = append(params, [2]string{"ruleID", filter.RuleID.String()})
+	}
+
+	var resp getSilencesResponse
+	err := s.Client.
+		Get(prefixSilences).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	silences := resp.toInfluxDB()
+	return silences, len(silences), nil
+}
+
+// CreateSilence creates a new silence and assigns it an influxdb.ID
+func (s *SilenceService) CreateSilence(ctx context.Context, silence *influxdb.Silence) error {
+	if err := silence.Valid(); err != nil {
+		return err
+	}
+
+	return s.Client.
+		PostJSON(silence, prefixSilences).
+		DecodeJSON(silence).
+		Do(ctx)
+}
+
+// UpdateSilence updates a single silence with a changeset
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	var resp silenceResponse
+	err := s.Client.
+		PatchJSON(upd, prefixSilences, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Silence, nil
+}
+
+// DeleteSilence removes a silence from the store
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(prefixSilences, id.String()).
+		Do(ctx)
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+// NewMockSilenceBackend returns a SilenceBackend with mock services.
+func NewMockSilenceBackend(t *testing.T) *SilenceBackend {
+	return &SilenceBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		log:              zaptest.NewLogger(t),
+		SilenceService:   mock.NewSilenceService(),
+	}
+}
+
+func TestSilenceService_handlePostSilence(t *testing.T) {
+	type args struct {
+		silence string
+	}
+	type wants struct {
+		statusCode  int
+		contentType string
+		body        string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "create a silence with a predicate",
+			args: args{
+				silence: `
+{
+  "orgID": "0000000000000001",
+  "name": "maintenance",
+  "checkID": "0000000000000002",
+  "predicate": "host=\"db1\" and env=\"prod\"",
+  "startTime": "2006-05-04T01:02:03Z",
+  "endTime": "2006-05-04T03:02:03Z"
+}
+`,
+			},
+			wants: wants{
+				statusCode:  201,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "id": "0000000000000003",
+  "orgID": "0000000000000001",
+  "name": "maintenance",
+  "checkID": "0000000000000002",
+  "tagRules": [
+    {"key": "host", "value": "db1", "operator": "equal"},
+    {"key": "env", "value": "prod", "operator": "equal"}
+  ],
+  "startTime": "2006-05-04T01:02:03Z",
+  "endTime": "2006-05-04T03:02:03Z",
+  "createdAt": "2006-05-04T01:02:03Z",
+  "updatedAt": "2006-05-04T01:02:03Z",
+  "links": {
+    "self": "/api/v2/silences/0000000000000003",
+    "org": "/api/v2/orgs/0000000000000001"
+  }
+}
+`,
+			},
+		},
+		{
+			name: "create a silence with an invalid predicate",
+			args: args{
+				silence: `
+{
+  "orgID": "0000000000000001",
+  "name": "maintenance",
+  "predicate": "host=\"db1\" or env=\"prod\"",
+  "startTime": "2006-05-04T01:02:03Z",
+  "endTime": "2006-05-04T03:02:03Z"
+}
+`,
+			},
+			wants: wants{
+				statusCode:  400,
+				contentType: "application/json; charset=utf-8",
+			},
+		},
+		{
+			name: "create a silence without matchers",
+			args: args{
+				silence: `
+{
+  "orgID": "0000000000000001",
+  "name": "maintenance",
+  "startTime": "2006-05-04T01:02:03Z",
+  "endTime": "2006-05-04T03:02:03Z"
+}
+`,
+			},
+			wants: wants{
+				statusCode:  400,
+				contentType: "application/json; charset=utf-8",
+				body:        `{"code":"invalid","message":"silence must match a check, a notification rule or tags"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			silenceBackend := NewMockSilenceBackend(t)
+			silenceBackend.SilenceService = &mock.SilenceService{
+				CreateSilenceFn: func(ctx context.Context, s *influxdb.Silence) error {
+					s.ID = 3
+					s.CreatedAt = faketime
+					s.UpdatedAt = faketime
+					return nil
+				},
+			}
+			h := NewSilenceHandler(zaptest.NewLogger(t), silenceBackend)
+			r := httptest.NewRequest("POST", "http://any.url", bytes.NewReader([]byte(tt.args.silence)))
+			w := httptest.NewRecorder()
+
+			h.handlePostSilence(w, r)
+
+			res := w.Result()
+			contentType := res.Header.Get("Content-Type")
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("got = %v, want %v: %s", res.StatusCode, tt.wants.statusCode, body)
+			}
+			if contentType != tt.wants.contentType {
+				t.Errorf("got = %v, want %v", contentType, tt.wants.contentType)
+			}
+			if tt.wants.body == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+				t.Errorf("%q, error unmarshaling json %v", tt.name, err)
+			} else if !eq {
+				t.Errorf("%q. ***%s***", tt.name, diff)
+			}
+		})
+	}
+}
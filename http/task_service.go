@@ -79,6 +79,7 @@ const (
 	tasksIDRunsIDPath      = "/api/v2/tasks/:id/runs/:rid"
 	tasksIDRunsIDLogsPath  = "/api/v2/tasks/:id/runs/:rid/logs"
 	tasksIDRunsIDRetryPath = "/api/v2/tasks/:id/runs/:rid/retry"
+	tasksIDBackfillPath    = "/api/v2/tasks/:id/backfill"
 	tasksIDLabelsPath      = "/api/v2/tasks/:id/labels"
 	tasksIDLabelsIDPath    = "/api/v2/tasks/:id/labels/:lid"
 )
@@ -139,6 +140,9 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
+	h.HandlerFunc("GET", tasksIDBackfillPath, h.handleGetBackfills)
+	h.HandlerFunc("POST", tasksIDBackfillPath, h.handlePostBackfill)
+
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		log:              b.log.With(zap.String("handler", "label")),
@@ -341,6 +345,7 @@ type httpRun struct {
 	StartedAt    *time.Time     `json:"startedAt,omitempty"`
 	FinishedAt   *time.Time     `json:"finishedAt,omitempty"`
 	RequestedAt  *time.Time     `json:"requestedAt,omitempty"`
+	Attempt      int            `json:"attempt,omitempty"`
 	Log          []influxdb.Log `json:"log,omitempty"`
 }
 
@@ -349,6 +354,7 @@ func newRunResponse(r influxdb.Run) runResponse {
 		ID:           r.ID,
 		TaskID:       r.TaskID,
 		Status:       r.Status,
+		Attempt:      r.Attempt,
 		Log:          r.Log,
 		ScheduledFor: &r.ScheduledFor,
 	}
@@ -376,10 +382,11 @@ func newRunResponse(r influxdb.Run) runResponse {
 
 func convertRun(r httpRun) *influxdb.Run {
 	run := &influxdb.Run{
-		ID:     r.ID,
-		TaskID: r.TaskID,
-		Status: r.Status,
-		Log:    r.Log,
+		ID:      r.ID,
+		TaskID:  r.TaskID,
+		Status:  r.Status,
+		Attempt: r.Attempt,
+		Log:     r.Log,
 	}
 
 	if r.StartedAt != nil {
@@ -1106,6 +1113,158 @@ func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunReques
 	}, nil
 }
 
+type backfillResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Backfill
+	// FinishedAt is omitted until the backfill finished.
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+func newBackfillResponse(b influxdb.Backfill) backfillResponse {
+	r := backfillResponse{
+		Links: map[string]string{
+			"task": fmt.Sprintf("/api/v2/tasks/%s", b.TaskID),
+			"runs": fmt.Sprintf("/api/v2/tasks/%s/runs", b.TaskID),
+		},
+		Backfill: b,
+	}
+	if !b.FinishedAt.IsZero() {
+		r.FinishedAt = &b.FinishedAt
+	}
+	return r
+}
+
+func convertBackfill(r backfillResponse) *influxdb.Backfill {
+	b := r.Backfill
+	if r.FinishedAt != nil {
+		b.FinishedAt = *r.FinishedAt
+	}
+	return &b
+}
+
+type backfillsResponse struct {
+	Links     map[string]string   `json:"links"`
+	Backfills []*backfillResponse `json:"backfills"`
+}
+
+func newBackfillsResponse(bs []*influxdb.Backfill, taskID influxdb.ID) backfillsResponse {
+	r := backfillsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/tasks/%s/backfill", taskID),
+			"task": fmt.Sprintf("/api/v2/tasks/%s", taskID),
+		},
+		Backfills: make([]*backfillResponse, len(bs)),
+	}
+
+	for i := range bs {
+		b := newBackfillResponse(*bs[i])
+		r.Backfills[i] = &b
+	}
+	return r
+}
+
+func (h *TaskHandler) handlePostBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostBackfillRequest(ctx, r)
+	if err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.TaskService.BackfillTask(ctx, req.TaskID, req.BackfillRequest)
+	if err != nil {
+		err := &influxdb.Error{
+			Err: err,
+			Msg: "failed to backfill task",
+		}
+		if err.Err == influxdb.ErrTaskNotFound {
+			err.Code = influxdb.ENotFound
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	code := http.StatusCreated
+	if req.DryRun {
+		code = http.StatusOK
+	}
+	if err := encodeResponse(ctx, w, code, newBackfillResponse(*b)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type postBackfillRequest struct {
+	TaskID influxdb.ID
+	influxdb.BackfillRequest
+}
+
+func decodePostBackfillRequest(ctx context.Context, r *http.Request) (*postBackfillRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "you must provide a task ID",
+		}
+	}
+
+	var req postBackfillRequest
+	if err := req.TaskID.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req.BackfillRequest); err != nil {
+		return nil, err
+	}
+
+	if err := req.BackfillRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (h *TaskHandler) handleGetBackfills(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params := httprouter.ParamsFromContext(ctx)
+	var taskID influxdb.ID
+	if err := taskID.DecodeFromString(params.ByName("id")); err != nil {
+		err = &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	backfills, err := h.TaskService.FindBackfills(ctx, taskID)
+	if err != nil {
+		err := &influxdb.Error{
+			Err: err,
+			Msg: "failed to find backfills",
+		}
+		if err.Err == influxdb.ErrTaskNotFound {
+			err.Code = influxdb.ENotFound
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillsResponse(backfills, taskID)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
 func (h *TaskHandler) handleGetRun(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -1912,6 +2071,89 @@ func (t TaskService) CancelRun(ctx context.Context, taskID, runID influxdb.ID) e
 	return nil
 }
 
+// BackfillTask creates a run of the task for every time of its schedule in the range of the request.
+func (t TaskService) BackfillTask(ctx context.Context, taskID influxdb.ID, req influxdb.BackfillRequest) (*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, r)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var br backfillResponse
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		return nil, err
+	}
+	return convertBackfill(br), nil
+}
+
+// FindBackfills returns the backfills of the task.
+func (t TaskService) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var bs backfillsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bs); err != nil {
+		return nil, err
+	}
+
+	backfills := make([]*influxdb.Backfill, len(bs.Backfills))
+	for i := range bs.Backfills {
+		backfills[i] = convertBackfill(*bs.Backfills[i])
+	}
+	return backfills, nil
+}
+
 func taskIDPath(id influxdb.ID) string {
 	return path.Join(prefixTasks, id.String())
 }
@@ -1920,6 +2162,10 @@ func taskIDRunsPath(id influxdb.ID) string {
 	return path.Join(prefixTasks, id.String(), "runs")
 }
 
+func taskIDBackfillPath(id influxdb.ID) string {
+	return path.Join(prefixTasks, id.String(), "backfill")
+}
+
 func taskIDRunIDPath(taskID, runID influxdb.ID) string {
 	return path.Join(prefixTasks, taskID.String(), "runs", runID.String())
 }
@@ -817,6 +817,133 @@ func TestTaskHandler_handleGetRuns(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_handlePostBackfill(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		wants wants
+	}{
+		{
+			name: "dry run of a backfill",
+			body: `{"start": "2019-01-01T00:30:00Z", "stop": "2019-01-01T02:00:00Z", "dryRun": true}`,
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "links": {
+    "task": "/api/v2/tasks/0000000000000001",
+    "runs": "/api/v2/tasks/0000000000000001/runs"
+  },
+  "taskID": "0000000000000001",
+  "start": "2019-01-01T00:30:00Z",
+  "stop": "2019-01-01T02:00:00Z",
+  "concurrency": 1,
+  "scheduledFor": ["2019-01-01T01:00:00Z", "2019-01-01T02:00:00Z"],
+  "succeeded": 0,
+  "failed": 0,
+  "skipped": 0,
+  "createdAt": "2019-01-01T03:00:00Z"
+}`,
+			},
+		},
+		{
+			name: "backfill",
+			body: `{"start": "2019-01-01T00:30:00Z", "stop": "2019-01-01T02:00:00Z", "concurrency": 2}`,
+			wants: wants{
+				statusCode: http.StatusCreated,
+				body: `
+{
+  "links": {
+    "task": "/api/v2/tasks/0000000000000001",
+    "runs": "/api/v2/tasks/0000000000000001/runs"
+  },
+  "id": "0000000000000002",
+  "taskID": "0000000000000001",
+  "status": "running",
+  "start": "2019-01-01T00:30:00Z",
+  "stop": "2019-01-01T02:00:00Z",
+  "concurrency": 2,
+  "scheduledFor": ["2019-01-01T01:00:00Z", "2019-01-01T02:00:00Z"],
+  "runs": ["0000000000000003", "0000000000000004"],
+  "succeeded": 0,
+  "failed": 0,
+  "skipped": 0,
+  "createdAt": "2019-01-01T03:00:00Z"
+}`,
+			},
+		},
+		{
+			name: "start after stop",
+			body: `{"start": "2019-01-01T02:00:00Z", "stop": "2019-01-01T00:30:00Z"}`,
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskService := &mock.TaskService{
+				BackfillTaskFn: func(ctx context.Context, taskID platform.ID, req platform.BackfillRequest) (*platform.Backfill, error) {
+					b := &platform.Backfill{
+						TaskID:       taskID,
+						Start:        req.Start,
+						Stop:         req.Stop,
+						Concurrency:  req.Concurrency,
+						ScheduledFor: []time.Time{req.Start.Add(30 * time.Minute), req.Stop},
+						CreatedAt:    req.Stop.Add(time.Hour),
+					}
+					if b.Concurrency == 0 {
+						b.Concurrency = 1
+					}
+					if !req.DryRun {
+						b.ID = 2
+						b.Status = platform.BackfillStatusRunning
+						b.Runs = []platform.ID{3, 4}
+					}
+					return b, nil
+				},
+			}
+
+			r := httptest.NewRequest("POST", "http://any.url", strings.NewReader(tt.body))
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: platform.ID(1).String(),
+					},
+				}))
+			w := httptest.NewRecorder()
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.HTTPErrorHandler = ErrorHandler(0)
+			taskBackend.TaskService = taskService
+			h := NewTaskHandler(zaptest.NewLogger(t), taskBackend)
+			h.handlePostBackfill(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePostBackfill() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handlePostBackfill(). error unmarshaling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handlePostBackfill() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
 func TestTaskHandler_NotFoundStatus(t *testing.T) {
 	// Ensure that the HTTP handlers return 404s for missing resources, and OKs for matching.
 
@@ -0,0 +1,617 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	iql "github.com/influxdata/influxql"
+	"go.uber.org/zap"
+)
+
+// V1Backend is all services and associated parameters required to construct
+// the V1Handler.
+type V1Backend struct {
+	influxdb.HTTPErrorHandler
+	log                *zap.Logger
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+
+	PointsWriter        storage.PointsWriter
+	ProxyQueryService   query.ProxyQueryService
+	DBRPMappingService  influxdb.DBRPMappingService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewV1Backend returns a new instance of V1Backend.
+func NewV1Backend(log *zap.Logger, b *APIBackend) *V1Backend {
+	return &V1Backend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		log:                log,
+		WriteEventRecorder: b.WriteEventRecorder,
+		QueryEventRecorder: b.QueryEventRecorder,
+
+		PointsWriter:        b.PointsWriter,
+		ProxyQueryService:   b.FluxService,
+		DBRPMappingService:  b.DBRPMappingService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// V1Handler serves the /query and /write endpoints of InfluxDB 1.x.
+//
+// The database and retention policy of a request are resolved to a bucket
+// with the dbrp mappings. When there is no mapping, the bucket named
+// `db/rp` in the organization of the request is used, where the retention
+// policy defaults to autogen.
+type V1Handler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	PointsWriter        storage.PointsWriter
+	ProxyQueryService   query.ProxyQueryService
+	DBRPMappingService  influxdb.DBRPMappingService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+
+	WriteEventRecorder metric.EventRecorder
+	QueryEventRecorder metric.EventRecorder
+}
+
+const (
+	v1QueryPath = "/query"
+	v1WritePath = "/write"
+	v1PingPath  = "/ping"
+
+	// v1DefaultChunkSize is the number of values of a series in a chunk
+	// of a chunked response when the chunk size is not specified.
+	v1DefaultChunkSize = 10000
+
+	errInvalidV1Precision = "invalid precision; valid precision units are h, m, s, ms, u, and n"
+)
+
+// isV1Path reports whether path is served by the V1Handler.
+func isV1Path(path string) bool {
+	return path == v1QueryPath || path == v1WritePath || path == v1PingPath
+}
+
+// NewV1Handler creates a new handler at /query, /write and /ping that is
+// compatible with the http API of InfluxDB 1.x.
+func NewV1Handler(log *zap.Logger, b *V1Backend) *V1Handler {
+	h := &V1Handler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		PointsWriter:        b.PointsWriter,
+		ProxyQueryService:   b.ProxyQueryService,
+		DBRPMappingService:  b.DBRPMappingService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+		WriteEventRecorder:  b.WriteEventRecorder,
+		QueryEventRecorder:  b.QueryEventRecorder,
+	}
+
+	h.HandlerFunc("GET", v1QueryPath, h.handleQuery)
+	h.HandlerFunc("POST", v1QueryPath, h.handleQuery)
+	h.HandlerFunc("POST", v1WritePath, h.handleWrite)
+	h.HandlerFunc("GET", v1PingPath, h.handlePing)
+	h.HandlerFunc("HEAD", v1PingPath, h.handlePing)
+	return h
+}
+
+func (h *V1Handler) handlePing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Influxdb-Build", "OSS")
+	w.Header().Set("X-Influxdb-Version", influxdb.GetBuildInfo().Version)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *V1Handler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "V1Handler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var orgID influxdb.ID
+	var requestBytes int
+	sw := newStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.WriteEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  requestBytes,
+			ResponseBytes: sw.responseBytes,
+			Status:        sw.code(),
+		})
+	}()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		v1Error(w, err)
+		return
+	}
+
+	qp := r.URL.Query()
+	db, rp := qp.Get("db"), qp.Get("rp")
+	if db == "" {
+		v1Error(w, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "database is required",
+		})
+		return
+	}
+
+	precision, err := v1Precision(qp.Get("precision"))
+	if err != nil {
+		v1Error(w, err)
+		return
+	}
+
+	bucket, err := h.findBucket(ctx, r, a, db, rp)
+	if err != nil {
+		v1Error(w, err)
+		return
+	}
+	orgID = bucket.OrgID
+	span.LogKV("org_id", bucket.OrgID, "bucket_id", bucket.ID)
+
+	p, err := influxdb.NewPermissionAtID(bucket.ID, influxdb.WriteAction, influxdb.BucketsResourceType, bucket.OrgID)
+	if err != nil {
+		v1Error(w, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   "http/handleV1Write",
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		})
+		return
+	}
+	if !a.Allowed(*p) {
+		v1Error(w, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   "http/handleV1Write",
+			Msg:  "insufficient permissions for write",
+		})
+		return
+	}
+
+	in := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		in, err = gzip.NewReader(r.Body)
+		if err != nil {
+			v1Error(w, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   "http/handleV1Write",
+				Msg:  errInvalidGzipHeader,
+				Err:  err,
+			})
+			return
+		}
+		defer in.Close()
+	}
+
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		v1Error(w, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   "http/handleV1Write",
+			Msg:  fmt.Sprintf("unable to read data: %v", err),
+			Err:  err,
+		})
+		return
+	}
+	requestBytes = len(data)
+
+	encoded := tsdb.EncodeName(bucket.OrgID, bucket.ID)
+	mm := models.EscapeMeasurement(encoded[:])
+	points, parseErr := parseV1Points(data, mm, time.Now(), precision)
+
+	var dropped int
+	if scopes := influxdb.SeriesScopes(a, *p); len(scopes) > 0 {
+		points, dropped, err = filterPoints(points, scopes)
+		if err != nil {
+			v1Error(w, err)
+			return
+		}
+	}
+
+	if len(points) > 0 {
+		if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+			h.log.Error("Error writing points", zap.Error(err))
+			v1Error(w, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   "http/handleV1Write",
+				Msg:  "unexpected error writing points to database",
+				Err:  err,
+			})
+			return
+		}
+	}
+	if parseErr != nil {
+		// The points that could be parsed have been written, as with
+		// the partial writes of InfluxDB 1.x.
+		v1Error(w, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("partial write: %v", parseErr),
+		})
+		return
+	}
+	if dropped > 0 {
+		v1Error(w, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   "http/handleV1Write",
+			Msg:  fmt.Sprintf("partial write: %d points outside of the series scope of the authorization were dropped", dropped),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *V1Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "V1Handler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	var orgID influxdb.ID
+	sw := newStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.QueryEventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			ResponseBytes: sw.responseBytes,
+			Status:        sw.code(),
+		})
+	}()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		v1Error(w, err)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		v1Error(w, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		})
+		return
+	}
+
+	dialect, err := decodeV1Dialect(r)
+	if err != nil {
+		v1Error(w, err)
+		return
+	}
+	enc := dialect.Encoder().(*influxql.MultiResultEncoder)
+
+	q := r.Form.Get("q")
+	if q == "" {
+		v1Error(w, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  `missing required parameter "q"`,
+		})
+		return
+	}
+	if _, err := iql.ParseQuery(q); err != nil {
+		dialect.SetHeaders(w)
+		w.WriteHeader(http.StatusBadRequest)
+		_ = enc.EncodeError(w, fmt.Errorf("error parsing query: %v", err))
+		return
+	}
+
+	db, rp := r.Form.Get("db"), r.Form.Get("rp")
+	org, err := h.findQueryOrganization(ctx, r, a, db, rp)
+	if err != nil {
+		v1Error(w, err)
+		return
+	}
+	orgID = org
+
+	token, err := queryAuthorization(a, orgID)
+	if err != nil {
+		v1Error(w, err)
+		return
+	}
+	ctx = pcontext.SetAuthorizer(ctx, token)
+
+	compiler := influxql.NewCompiler(h.DBRPMappingService)
+	compiler.DB = db
+	compiler.RP = rp
+	compiler.Query = q
+	compiler.FallbackToDBRP = true
+
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  token,
+			OrganizationID: orgID,
+			Compiler:       compiler,
+		},
+		Dialect: dialect,
+	}
+
+	dialect.SetHeaders(w)
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error IFF nothing has been written to w.
+			status := http.StatusBadRequest
+			if e, ok := err.(*influxdb.Error); ok {
+				if code, ok := statusCodePlatformError[e.Code]; ok {
+					status = code
+				}
+			}
+			w.WriteHeader(status)
+			_ = enc.EncodeError(w, err)
+			return
+		}
+		_ = tracing.LogError(span, err)
+		h.log.Info("Error writing response to client",
+			zap.String("handler", "v1"),
+			zap.Error(err),
+		)
+	}
+}
+
+// findBucket returns the bucket of the database and retention policy.
+func (h *V1Handler) findBucket(ctx context.Context, r *http.Request, a influxdb.Authorizer, db, rp string) (*influxdb.Bucket, error) {
+	m, err := findV1Mapping(ctx, h.DBRPMappingService, db, rp)
+	if err != nil {
+		return nil, err
+	}
+	if m != nil {
+		return h.BucketService.FindBucketByID(ctx, m.BucketID)
+	}
+
+	orgID, err := v1Organization(ctx, r, a, h.OrganizationService)
+	if err != nil {
+		return nil, err
+	}
+	if rp == "" {
+		rp = influxql.DefaultRetentionPolicy
+	}
+	name := db + "/" + rp
+	b, err := h.BucketService.FindBucket(ctx, influxdb.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &name,
+	})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  fmt.Sprintf("database not found: %q", db),
+			Err:  err,
+		}
+	}
+	return b, err
+}
+
+// findQueryOrganization returns the organization of a query, which is the
+// organization of the mapping of the default database of the query, if there
+// is one, or else the organization of the request.
+func (h *V1Handler) findQueryOrganization(ctx context.Context, r *http.Request, a influxdb.Authorizer, db, rp string) (influxdb.ID, error) {
+	if db != "" {
+		m, err := findV1Mapping(ctx, h.DBRPMappingService, db, rp)
+		if err != nil {
+			return 0, err
+		}
+		if m != nil {
+			return m.OrganizationID, nil
+		}
+	}
+	return v1Organization(ctx, r, a, h.OrganizationService)
+}
+
+// findV1Mapping returns the mapping of the database and retention policy, or
+// the default mapping of the database if the retention policy is empty. It
+// returns nil if there is no mapping.
+func findV1Mapping(ctx context.Context, svc influxdb.DBRPMappingService, db, rp string) (*influxdb.DBRPMapping, error) {
+	filter := influxdb.DBRPMappingFilter{
+		Database: &db,
+	}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	} else {
+		defaultRP := true
+		filter.Default = &defaultRP
+	}
+
+	m, err := svc.Find(ctx, filter)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, nil
+	}
+	return m, err
+}
+
+// v1Organization returns the organization of the org or orgID parameters of
+// the request, or the organization of the authorization of the request.
+func v1Organization(ctx context.Context, r *http.Request, a influxdb.Authorizer, svc influxdb.OrganizationService) (influxdb.ID, error) {
+	qp := r.URL.Query()
+	if qp.Get(Org) != "" || qp.Get(OrgID) != "" {
+		o, err := queryOrganization(ctx, r, svc)
+		if err != nil {
+			return 0, err
+		}
+		return o.ID, nil
+	}
+
+	if auth, ok := a.(*influxdb.Authorization); ok {
+		return auth.OrgID, nil
+	}
+	return 0, &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "organization is required",
+	}
+}
+
+// v1Precision returns the precision of the points for a precision of
+// InfluxDB 1.x.
+func v1Precision(p string) (string, error) {
+	switch p {
+	case "", "n", "ns":
+		return "ns", nil
+	case "u", "µ", "us":
+		return "us", nil
+	case "ms":
+		return "ms", nil
+	case "s":
+		return "s", nil
+	case "m":
+		return "m", nil
+	case "h":
+		return "h", nil
+	default:
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  errInvalidV1Precision,
+		}
+	}
+}
+
+// parseV1Points parses the points of data. When some lines cannot be parsed,
+// it returns the points of the others with the error of those lines, so that
+// they are written as with the partial writes of InfluxDB 1.x.
+func parseV1Points(data, mm []byte, now time.Time, precision string) ([]models.Point, error) {
+	points, err := models.ParsePointsWithPrecision(data, mm, now, precision)
+	if err == nil {
+		return points, nil
+	}
+
+	// The points parsed before an invalid line can be dropped with it, so
+	// the lines are parsed one at a time. A string field value spanning
+	// several lines is then invalid.
+	points = nil
+	var failed []string
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		pts, err := models.ParsePointsWithPrecision(line, mm, now, precision)
+		if err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		points = append(points, pts...)
+	}
+	return points, errors.New(strings.Join(failed, "\n"))
+}
+
+// decodeV1Dialect returns the dialect of the epoch, chunked, chunk_size and
+// pretty parameters and of the Accept header of the request.
+func decodeV1Dialect(r *http.Request) (*influxql.Dialect, error) {
+	d := &influxql.Dialect{}
+
+	switch r.Header.Get("Accept") {
+	case "application/csv", "text/csv":
+		d.Encoding = influxql.CSV
+		// The times are written as nanoseconds in CSV by default.
+		d.TimeFormat = influxql.Nanosecond
+	default:
+		if r.Form.Get("pretty") == "true" {
+			d.Encoding = influxql.JSONPretty
+		}
+	}
+
+	switch epoch := r.Form.Get("epoch"); epoch {
+	case "":
+	case "h":
+		d.TimeFormat = influxql.Hour
+	case "m":
+		d.TimeFormat = influxql.Minute
+	case "s":
+		d.TimeFormat = influxql.Second
+	case "ms":
+		d.TimeFormat = influxql.Millisecond
+	case "u", "µ", "us":
+		d.TimeFormat = influxql.Microsecond
+	case "n", "ns":
+		d.TimeFormat = influxql.Nanosecond
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid epoch: %q", epoch),
+		}
+	}
+
+	if r.Form.Get("chunked") == "true" {
+		d.ChunkSize = v1DefaultChunkSize
+		if s := r.Form.Get("chunk_size"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("invalid chunk_size: %q", s),
+				}
+			}
+			d.ChunkSize = n
+		}
+	}
+	return d, nil
+}
+
+// getV1Token returns the token of a request to the V1Handler. As well as in
+// the Authorization header, the token can be given as the password of the
+// u and p parameters, in the URL or the form of a query, or of the basic
+// authentication of the request, as the clients of InfluxDB 1.x do.
+func getV1Token(r *http.Request) (string, error) {
+	t, err := GetToken(r)
+	if err == nil {
+		return t, nil
+	}
+	if p := r.URL.Query().Get("p"); p != "" {
+		return p, nil
+	}
+	// The parameters of a query can be sent as a form, unlike the ones of a
+	// write of which the body is line protocol.
+	if r.Method == http.MethodPost && r.URL.Path == v1QueryPath {
+		if p := r.PostFormValue("p"); p != "" {
+			return p, nil
+		}
+	}
+	if _, p, ok := r.BasicAuth(); ok && p != "" {
+		return p, nil
+	}
+	return "", err
+}
+
+// v1Error writes err in the format of the errors of InfluxDB 1.x.
+func v1Error(w http.ResponseWriter, err error) {
+	code := influxdb.ErrorCode(err)
+	status, ok := statusCodePlatformError[code]
+	if !ok {
+		status = http.StatusBadRequest
+	}
+
+	msg := err.Error()
+	if e, ok := err.(*influxdb.Error); ok && e.Msg != "" {
+		msg = e.Msg
+	}
+
+	w.Header().Set(PlatformErrorCodeHeader, code)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Err string `json:"error"`
+	}{Err: msg})
+}
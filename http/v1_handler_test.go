@@ -0,0 +1,355 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb"
+	httpmock "github.com/influxdata/influxdb/http/mock"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	influxtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestV1Handler_handleWrite(t *testing.T) {
+	mapping := &influxdb.DBRPMapping{
+		Cluster:         "default",
+		Database:        "telegraf",
+		RetentionPolicy: "autogen",
+		Default:         true,
+		OrganizationID:  influxtesting.MustIDBase16("043e0780ee2b1000"),
+		BucketID:        influxtesting.MustIDBase16("04504b356e23b000"),
+	}
+
+	tests := []struct {
+		name   string
+		url    string
+		auth   influxdb.Authorizer
+		body   string
+		code   int
+		resp   string
+		bucket string
+		points int
+		at     time.Time
+	}{
+		{
+			name:   "the default mapping of the database is used",
+			url:    "/write?db=telegraf",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "cpu,host=a value=1 1000000000",
+			code:   204,
+			points: 1,
+		},
+		{
+			name:   "bucket named after the database without a mapping",
+			url:    "/write?db=db0&rp=rp0&precision=s",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "cpu,host=a value=1 1",
+			code:   204,
+			bucket: "db0/rp0",
+			points: 1,
+		},
+		{
+			name:   "retention policy defaults to autogen without a mapping",
+			url:    "/write?db=db0",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "cpu,host=a value=1 1",
+			code:   204,
+			bucket: "db0/autogen",
+			points: 1,
+		},
+		{
+			name: "database is required",
+			url:  "/write",
+			auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body: "cpu,host=a value=1 1",
+			code: 400,
+			resp: `{"error":"database is required"}` + "\n",
+		},
+		{
+			name: "invalid precision",
+			url:  "/write?db=telegraf&precision=d",
+			auth: bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body: "cpu,host=a value=1 1",
+			code: 400,
+			resp: `{"error":"invalid precision; valid precision units are h, m, s, ms, u, and n"}` + "\n",
+		},
+		{
+			name:   "hour precision",
+			url:    "/write?db=telegraf&precision=h",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "cpu,host=a value=1 2",
+			code:   204,
+			points: 1,
+			at:     time.Unix(2*60*60, 0),
+		},
+		{
+			name:   "minute precision",
+			url:    "/write?db=telegraf&precision=m",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "cpu,host=a value=1 2",
+			code:   204,
+			points: 1,
+			at:     time.Unix(2*60, 0),
+		},
+		{
+			name:   "points that can be parsed are written",
+			url:    "/write?db=telegraf",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "cpu,host=a value=1 1\ncpu,host=b",
+			code:   400,
+			resp:   `{"error":"partial write: unable to parse 'cpu,host=b': missing fields"}` + "\n",
+			points: 1,
+		},
+		{
+			name:   "points after a line that cannot be parsed are written",
+			url:    "/write?db=telegraf",
+			auth:   bucketWritePermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "cpu,host=a value=1 1\ncpu,host=b\ncpu,host=c value=1 1",
+			code:   400,
+			resp:   `{"error":"partial write: unable to parse 'cpu,host=b': missing fields"}` + "\n",
+			points: 2,
+		},
+		{
+			name: "forbidden to write with insufficient permission",
+			url:  "/write?db=telegraf",
+			auth: bucketWritePermission("043e0780ee2b1000", "000000000000000a"),
+			body: "cpu,host=a value=1 1",
+			code: 403,
+			resp: `{"error":"insufficient permissions for write"}` + "\n",
+		},
+		{
+			name:   "points in the series scope of the authorization are written",
+			url:    "/write?db=telegraf",
+			auth:   bucketWriteScopedPermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "m1,host=web1 value=1 1\nm1,host=web2 value=1 1",
+			code:   204,
+			points: 2,
+		},
+		{
+			name:   "points outside of the series scope of the authorization are dropped",
+			url:    "/write?db=telegraf",
+			auth:   bucketWriteScopedPermission("043e0780ee2b1000", "04504b356e23b000"),
+			body:   "m1,host=web1 value=1 1\ncpu,host=web1 value=1 1\nm1,host=db1 value=1 1",
+			code:   403,
+			resp:   `{"error":"partial write: 2 points outside of the series scope of the authorization were dropped"}` + "\n",
+			points: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbrps := mock.NewDBRPMappingService()
+			dbrps.FindFn = func(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+				if *filter.Database == mapping.Database && filter.Default != nil && *filter.Default {
+					return mapping, nil
+				}
+				return nil, &influxdb.Error{Code: influxdb.ENotFound}
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+				if id != mapping.BucketID {
+					t.Errorf("unexpected bucket id: %s", id)
+				}
+				return testBucket("043e0780ee2b1000", "04504b356e23b000"), nil
+			}
+			buckets.FindBucketFn = func(ctx context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
+				if got, want := *filter.Name, tt.bucket; got != want {
+					t.Errorf("unexpected bucket name: got %s want %s", got, want)
+				}
+				return testBucket("043e0780ee2b1000", "04504b356e23b000"), nil
+			}
+			pw := &mock.PointsWriter{}
+
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: mock.NewOrganizationService(),
+				BucketService:       buckets,
+				DBRPMappingService:  dbrps,
+				PointsWriter:        pw,
+				WriteEventRecorder:  noopEventRecorder{},
+				QueryEventRecorder:  noopEventRecorder{},
+			}
+			v1Handler := NewV1Handler(zaptest.NewLogger(t), NewV1Backend(zaptest.NewLogger(t), b))
+			handler := httpmock.NewAuthMiddlewareHandler(v1Handler, tt.auth)
+
+			r := httptest.NewRequest("POST", "http://localhost:9999"+tt.url, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := w.Body.String(), tt.resp; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+			if got, want := len(pw.Points), tt.points; got != want {
+				t.Errorf("unexpected number of points: got %d want %d", got, want)
+			}
+			if !tt.at.IsZero() && len(pw.Points) > 0 && !pw.Points[0].Time().Equal(tt.at) {
+				t.Errorf("unexpected time of point: got %v want %v", pw.Points[0].Time(), tt.at)
+			}
+		})
+	}
+}
+
+func TestV1Handler_handleQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		accept  string
+		code    int
+		resp    string
+		dialect *influxql.Dialect
+		db, rp  string
+	}{
+		{
+			name:    "query of the database",
+			url:     "/query?db=db0&rp=rp0&q=SELECT+value+FROM+cpu",
+			code:    200,
+			dialect: &influxql.Dialect{},
+			db:      "db0",
+			rp:      "rp0",
+		},
+		{
+			name:    "epoch and chunked",
+			url:     "/query?q=SELECT+value+FROM+db0..cpu&epoch=ms&chunked=true&chunk_size=100",
+			code:    200,
+			dialect: &influxql.Dialect{TimeFormat: influxql.Millisecond, ChunkSize: 100},
+		},
+		{
+			name:    "csv",
+			url:     "/query?q=SELECT+value+FROM+db0..cpu",
+			accept:  "application/csv",
+			code:    200,
+			dialect: &influxql.Dialect{TimeFormat: influxql.Nanosecond, Encoding: influxql.CSV},
+		},
+		{
+			name: "query is required",
+			url:  "/query?db=db0",
+			code: 400,
+			resp: `{"error":"missing required parameter \"q\""}` + "\n",
+		},
+		{
+			name: "invalid epoch",
+			url:  "/query?q=SELECT+value+FROM+db0..cpu&epoch=d",
+			code: 400,
+			resp: `{"error":"invalid epoch: \"d\""}` + "\n",
+		},
+		{
+			name: "parse error",
+			url:  "/query?q=SELECT",
+			code: 400,
+			resp: `{"error":"error parsing query: found EOF, expected identifier, string, number, bool at line 1, char 8"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req *query.ProxyRequest
+			b := &APIBackend{
+				HTTPErrorHandler:    DefaultErrorHandler,
+				Logger:              zaptest.NewLogger(t),
+				OrganizationService: mock.NewOrganizationService(),
+				DBRPMappingService: &mock.DBRPMappingService{
+					FindFn: func(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+						return nil, &influxdb.Error{Code: influxdb.ENotFound}
+					},
+				},
+				FluxService: &querymock.ProxyQueryService{
+					QueryF: func(ctx context.Context, w io.Writer, r *query.ProxyRequest) (flux.Statistics, error) {
+						req = r
+						return flux.Statistics{}, nil
+					},
+				},
+				WriteEventRecorder: noopEventRecorder{},
+				QueryEventRecorder: noopEventRecorder{},
+			}
+			v1Handler := NewV1Handler(zaptest.NewLogger(t), NewV1Backend(zaptest.NewLogger(t), b))
+			auth := bucketWritePermission("043e0780ee2b1000", "04504b356e23b000")
+			handler := httpmock.NewAuthMiddlewareHandler(v1Handler, auth)
+
+			r := httptest.NewRequest("GET", "http://localhost:9999"+tt.url, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if got, want := w.Code, tt.code; got != want {
+				t.Errorf("unexpected status code: got %d want %d", got, want)
+			}
+			if got, want := w.Body.String(), tt.resp; got != want {
+				t.Errorf("unexpected body: got %s want %s", got, want)
+			}
+
+			if tt.dialect == nil {
+				if req != nil {
+					t.Fatal("unexpected query")
+				}
+				return
+			}
+			if req == nil {
+				t.Fatal("expected query")
+			}
+			if got, want := *req.Dialect.(*influxql.Dialect), *tt.dialect; got != want {
+				t.Errorf("unexpected dialect: got %+v want %+v", got, want)
+			}
+			if got, want := req.Request.OrganizationID, auth.OrgID; got != want {
+				t.Errorf("unexpected organization: got %s want %s", got, want)
+			}
+			c := req.Request.Compiler.(*influxql.Compiler)
+			if c.DB != tt.db || c.RP != tt.rp || !c.FallbackToDBRP {
+				t.Errorf("unexpected compiler: %+v", c)
+			}
+		})
+	}
+}
+
+func TestGetV1Token(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		url   string
+		basic bool
+		form  string
+		token string
+	}{
+		{name: "password parameter", url: "/query?u=user&p=secret", token: "secret"},
+		{name: "basic authentication", url: "/query", basic: true, token: "secret"},
+		{name: "password in the form of a query", url: "/query", form: "u=user&p=secret&q=SHOW+DATABASES", token: "secret"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://localhost:9999"+tt.url, nil)
+			if tt.form != "" {
+				r = httptest.NewRequest("POST", "http://localhost:9999"+tt.url, strings.NewReader(tt.form))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			if tt.basic {
+				r.SetBasicAuth("user", "secret")
+			}
+			token, err := getRequestToken(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token != tt.token {
+				t.Errorf("unexpected token: got %s want %s", token, tt.token)
+			}
+		})
+	}
+
+	r := httptest.NewRequest("GET", "http://localhost:9999/api/v2/buckets?p=secret", nil)
+	if _, err := getRequestToken(r); err == nil {
+		t.Error("expected the password parameter to only be a token for the 1.x endpoints")
+	}
+
+	r = httptest.NewRequest("POST", "http://localhost:9999/write?db=telegraf", strings.NewReader("p=secret value=1"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if _, err := getRequestToken(r); err == nil {
+		t.Error("expected the line protocol body of a write not to be read as a form")
+	}
+}
@@ -244,13 +244,33 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
-		log.Error("Error writing points", zap.Error(err))
+	var dropped int
+	if scopes := influxdb.SeriesScopes(a, *p); len(scopes) > 0 {
+		points, dropped, err = filterPoints(points, scopes)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	if len(points) > 0 {
+		if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+			log.Error("Error writing points", zap.Error(err))
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   "http/handleWrite",
+				Msg:  "unexpected error writing points to database",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
+	if dropped > 0 {
 		h.HandleHTTPError(ctx, &influxdb.Error{
-			Code: influxdb.EInternal,
+			Code: influxdb.EForbidden,
 			Op:   "http/handleWrite",
-			Msg:  "unexpected error writing points to database",
-			Err:  err,
+			Msg:  fmt.Sprintf("partial write: %d points outside of the series scope of the authorization were dropped", dropped),
 		}, w)
 		return
 	}
@@ -258,6 +278,25 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// filterPoints returns the points of the series in any of the scopes and the
+// number of points dropped.
+func filterPoints(points []models.Point, scopes []influxdb.SeriesScope) ([]models.Point, int, error) {
+	m, err := influxdb.NewSeriesMatcher(scopes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filtered := points[:0]
+	for _, pt := range points {
+		tags := pt.Tags()
+		measurement := string(tags.Get(models.MeasurementTagKeyBytes))
+		if m.Match(measurement, tags.GetString) {
+			filtered = append(filtered, pt)
+		}
+	}
+	return filtered, len(points) - len(filtered), nil
+}
+
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
 	qp := r.URL.Query()
 	p := qp.Get("precision")
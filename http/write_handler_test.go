@@ -239,6 +239,39 @@ func TestWriteHandler_handleWrite(t *testing.T) {
 				body: `{"code":"forbidden","message":"insufficient permissions for write"}`,
 			},
 		},
+		{
+			name: "points in the series scope are accepted",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,host=web1 f1=1\nm1,host=web2 f1=1",
+				auth:   bucketWriteScopedPermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 204,
+			},
+		},
+		{
+			name: "points outside of the series scope are dropped",
+			request: request{
+				org:    "043e0780ee2b1000",
+				bucket: "04504b356e23b000",
+				body:   "m1,host=web1 f1=1\nm1,host=db1 f1=1\nm2,host=web1 f1=1",
+				auth:   bucketWriteScopedPermission("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			state: state{
+				org:    testOrg("043e0780ee2b1000"),
+				bucket: testBucket("043e0780ee2b1000", "04504b356e23b000"),
+			},
+			wants: wants{
+				code: 403,
+				body: `{"code":"forbidden","message":"partial write: 2 points outside of the series scope of the authorization were dropped"}`,
+			},
+		},
 		{
 			// authorization extraction happens in a different middleware.
 			name: "no authorizer is an internal error",
@@ -323,6 +356,19 @@ func bucketWritePermission(org, bucket string) *influxdb.Authorization {
 	}
 }
 
+// bucketWriteScopedPermission is allowed to write the series of m1 with
+// host tags starting with web.
+func bucketWriteScopedPermission(org, bucket string) *influxdb.Authorization {
+	a := bucketWritePermission(org, bucket)
+	a.Permissions[0].Series = &influxdb.SeriesScope{
+		Measurements: []string{"m1"},
+		Tags: []influxdb.TagRule{
+			{Tag: influxdb.Tag{Key: "host", Value: "^web"}, Operator: influxdb.RegexEqual},
+		},
+	}
+	return a
+}
+
 func testOrg(org string) *influxdb.Organization {
 	oid := influxtesting.MustIDBase16(org)
 	return &influxdb.Organization{
@@ -248,3 +248,38 @@ func (s *Service) UpdateAuthorization(ctx context.Context, id platform.ID, upd *
 
 	return a, s.PutAuthorization(ctx, a)
 }
+
+// RotateAuthorization creates an authorization with the permissions of the
+// authorization and a new token, and expires the authorization at the end of
+// the grace period of the rotation.
+func (s *Service) RotateAuthorization(ctx context.Context, id platform.ID, r platform.AuthorizationRotation) (*platform.Authorization, error) {
+	op := OpPrefix + platform.OpRotateAuthorization
+	old, err := s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+
+	a, err := old.Rotate(r, s.Now())
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+	// CreateAuthorization activates the authorization, the status of the
+	// rotated one is kept.
+	status := a.Status
+	if err := s.CreateAuthorization(ctx, a); err != nil {
+		return nil, err
+	}
+	if a.Status != status {
+		a.Status = status
+		if err := s.PutAuthorization(ctx, a); err != nil {
+			return nil, err
+		}
+	}
+	return a, s.PutAuthorization(ctx, old)
+}
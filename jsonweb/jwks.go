@@ -0,0 +1,136 @@
+package jsonweb
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultJWKSRefreshInterval is the minimum interval between two fetches of a
+// JSON Web Key Set by a JWKSKeyStore.
+const DefaultJWKSRefreshInterval = time.Minute
+
+// JWKSKeyStore is a KeyStore of the RSA signing keys of a JSON Web Key Set,
+// e.g. the keys an OpenID Connect provider publishes at its jwks_uri, so that
+// the tokens issued by the provider can be verified.
+// Its keys are PEM encoded public keys, to be parsed with jwt.ParseRSAPublicKeyFromPEM.
+// The key set is fetched again when a key is not found, at most once per
+// refresh interval, so that the keys rotated by the provider are found.
+type JWKSKeyStore struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string][]byte
+	fetchedAt time.Time
+}
+
+// NewJWKSKeyStore returns a KeyStore of the key set at url.
+func NewJWKSKeyStore(url string, client *http.Client) *JWKSKeyStore {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &JWKSKeyStore{
+		url:             url,
+		client:          client,
+		refreshInterval: DefaultJWKSRefreshInterval,
+		now:             time.Now,
+	}
+}
+
+// Key returns the PEM encoded public key with the key ID kid.
+func (s *JWKSKeyStore) Key(kid string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+
+	if !s.fetchedAt.IsZero() && s.now().Sub(s.fetchedAt) < s.refreshInterval {
+		return nil, ErrKeyNotFound
+	}
+
+	keys, err := s.fetch()
+	if err != nil {
+		return nil, err
+	}
+	s.keys = keys
+	s.fetchedAt = s.now()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// jwk is a JSON Web Key, as defined in RFC 7517.
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+func (s *JWKSKeyStore) fetch() (map[string][]byte, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch key set: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch key set: unexpected status %s", resp.Status)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode key set: %v", err)
+	}
+
+	keys := make(map[string][]byte, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.KeyType != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		key, err := k.rsaPublicKeyPEM()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %v", k.KeyID, err)
+		}
+		keys[k.KeyID] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) rsaPublicKeyPEM() ([]byte, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
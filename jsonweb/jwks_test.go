@@ -0,0 +1,72 @@
+package jsonweb
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func Test_JWKSKeyStore(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kid, fetches := "key-1", 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": kid,
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+				{"kty": "EC", "kid": "ec-key"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	now := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	ks := NewJWKSKeyStore(srv.URL, nil)
+	ks.now = func() time.Time { return now }
+
+	pem, err := ks.Key("key-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.N.Cmp(key.N) != 0 || pub.E != key.E {
+		t.Fatal("expected the public key of the key set")
+	}
+
+	if _, err := ks.Key("ec-key"); err != ErrKeyNotFound {
+		t.Fatalf("expected key not found error for a non RSA key, got %v", err)
+	}
+	if fetches != 1 {
+		t.Fatalf("expected the key set not to be fetched again within the refresh interval, got %d fetches", fetches)
+	}
+
+	kid = "key-2"
+	now = now.Add(DefaultJWKSRefreshInterval)
+	if _, err := ks.Key("key-2"); err != nil {
+		t.Fatalf("expected the rotated key to be found, got %v", err)
+	}
+	if fetches != 2 {
+		t.Fatalf("expected the key set to be fetched again, got %d fetches", fetches)
+	}
+}
@@ -0,0 +1,328 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	alertAckBucket = []byte("alertacksv1")
+
+	// ErrAlertAckNotFound is used when the alert acknowledgement is not found.
+	ErrAlertAckNotFound = &influxdb.Error{
+		Msg:  influxdb.ErrAlertAckNotFound,
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidAlertAckID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidAlertAckID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided alert acknowledgement ID has invalid format",
+	}
+)
+
+var _ influxdb.AlertAckService = (*Service)(nil)
+
+func (s *Service) initializeAlertAcks(ctx context.Context, tx Tx) error {
+	if _, err := s.alertAckBucket(tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnavailableAlertAckStoreError is used if we aren't able to interact with the
+// store, it means the store is not available at the moment (e.g. network).
+func UnavailableAlertAckStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unable to connect to alert acknowledgement store service. Please try again; Err: %v", err),
+		Op:   "kv/alertAck",
+	}
+}
+
+// InternalAlertAckStoreError is used when the error comes from an
+// internal system.
+func InternalAlertAckStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unknown internal alert acknowledgement data error; Err: %v", err),
+		Op:   "kv/alertAck",
+	}
+}
+
+func (s *Service) alertAckBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(alertAckBucket)
+	if err != nil {
+		return nil, UnavailableAlertAckStoreError(err)
+	}
+	return b, nil
+}
+
+// FindAlertAckByID returns a single alert acknowledgement by ID.
+func (s *Service) FindAlertAckByID(ctx context.Context, id influxdb.ID) (*influxdb.AlertAck, error) {
+	var (
+		a   *influxdb.AlertAck
+		err error
+	)
+
+	err = s.kv.View(ctx, func(tx Tx) error {
+		a, err = s.findAlertAckByID(ctx, tx, id)
+		return err
+	})
+
+	return a, err
+}
+
+func (s *Service) findAlertAckByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.AlertAck, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidAlertAckID
+	}
+
+	bucket, err := s.alertAckBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := bucket.Get(encID)
+	if IsNotFound(err) {
+		return nil, ErrAlertAckNotFound
+	}
+	if err != nil {
+		return nil, InternalAlertAckStoreError(err)
+	}
+
+	a := &influxdb.AlertAck{}
+	if err := json.Unmarshal(v, a); err != nil {
+		return nil, InternalAlertAckStoreError(err)
+	}
+	return a, nil
+}
+
+// FindAlertAcks returns a list of alert acknowledgements that match filter and the total count of matching acknowledgements.
+// Additional options provide pagination & sorting.
+func (s *Service) FindAlertAcks(ctx context.Context, filter influxdb.AlertAckFilter, opt ...influxdb.FindOptions) (as []*influxdb.AlertAck, n int, err error) {
+	err = s.kv.View(ctx, func(tx Tx) error {
+		as, err = s.findAlertAcks(ctx, tx, filter, opt...)
+		return err
+	})
+	return as, len(as), err
+}
+
+func (s *Service) findAlertAcks(ctx context.Context, tx Tx, filter influxdb.AlertAckFilter, opt ...influxdb.FindOptions) ([]*influxdb.AlertAck, error) {
+	as := make([]*influxdb.AlertAck, 0)
+
+	if filter.ID != nil {
+		a, err := s.findAlertAckByID(ctx, tx, *filter.ID)
+		if err != nil {
+			return nil, err
+		}
+		if filterAlertAcksFn(filter)(a) {
+			as = append(as, a)
+		}
+		return as, nil
+	}
+
+	var offset, limit, count int
+	var descending bool
+	if len(opt) > 0 {
+		offset = opt[0].Offset
+		limit = opt[0].Limit
+		descending = opt[0].Descending
+	}
+	filterFn := filterAlertAcksFn(filter)
+	err := s.forEachAlertAck(ctx, tx, descending, func(a *influxdb.AlertAck) bool {
+		if filterFn(a) {
+			if count >= offset {
+				as = append(as, a)
+			}
+			count++
+		}
+
+		if limit > 0 && len(as) >= limit {
+			return false
+		}
+
+		return true
+	})
+
+	return as, err
+}
+
+// forEachAlertAck will iterate through all alert acknowledgements while fn returns true.
+func (s *Service) forEachAlertAck(ctx context.Context, tx Tx, descending bool, fn func(*influxdb.AlertAck) bool) error {
+	bkt, err := s.alertAckBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	cur, err := bkt.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var k, v []byte
+	if descending {
+		k, v = cur.Last()
+	} else {
+		k, v = cur.First()
+	}
+
+	for k != nil {
+		a := &influxdb.AlertAck{}
+		if err := json.Unmarshal(v, a); err != nil {
+			return err
+		}
+		if !fn(a) {
+			break
+		}
+
+		if descending {
+			k, v = cur.Prev()
+		} else {
+			k, v = cur.Next()
+		}
+	}
+
+	return nil
+}
+
+func filterAlertAcksFn(filter influxdb.AlertAckFilter) func(a *influxdb.AlertAck) bool {
+	return func(a *influxdb.AlertAck) bool {
+		if filter.ID != nil && a.ID != *filter.ID {
+			return false
+		}
+		if filter.OrgID != nil && a.OrgID != *filter.OrgID {
+			return false
+		}
+		if filter.CheckID != nil && a.CheckID != *filter.CheckID {
+			return false
+		}
+		return true
+	}
+}
+
+// CreateAlertAck acknowledges an alert and sets a.ID with the new identifier.
+func (s *Service) CreateAlertAck(ctx context.Context, a *influxdb.AlertAck) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.createAlertAck(ctx, tx, a)
+	})
+}
+
+func (s *Service) createAlertAck(ctx context.Context, tx Tx, a *influxdb.AlertAck) error {
+	if err := a.Valid(); err != nil {
+		return err
+	}
+
+	if _, err := s.findOrganizationByID(ctx, tx, a.OrgID); err != nil {
+		return err
+	}
+
+	if _, err := s.findCheckByID(ctx, tx, a.CheckID); err != nil {
+		return err
+	}
+
+	a.ID = s.IDGenerator.ID()
+	now := s.TimeGenerator.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	if err := s.putAlertAck(ctx, tx, a); err != nil {
+		return err
+	}
+
+	return s.updateAcknowledgedNotificationTasks(ctx, tx, a.OrgID)
+}
+
+func (s *Service) putAlertAck(ctx context.Context, tx Tx, a *influxdb.AlertAck) error {
+	encodedID, err := a.ID.Encode()
+	if err != nil {
+		return ErrInvalidAlertAckID
+	}
+
+	v, err := json.Marshal(a)
+	if err != nil {
+		return InternalAlertAckStoreError(err)
+	}
+
+	bucket, err := s.alertAckBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(encodedID, v); err != nil {
+		return UnavailableAlertAckStoreError(err)
+	}
+	return nil
+}
+
+// DeleteAlertAck removes an alert acknowledgement by ID.
+func (s *Service) DeleteAlertAck(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteAlertAck(ctx, tx, id)
+	})
+}
+
+func (s *Service) deleteAlertAck(ctx context.Context, tx Tx, id influxdb.ID) error {
+	a, err := s.findAlertAckByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return ErrInvalidAlertAckID
+	}
+
+	bucket, err := s.alertAckBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Delete(encodedID); err != nil {
+		return InternalAlertAckStoreError(err)
+	}
+
+	return s.updateAcknowledgedNotificationTasks(ctx, tx, a.OrgID)
+}
+
+// setNotificationRuleAlertAcks sets the alert acknowledgements of the
+// organization of the rule on the rule, so that they are part of its generated flux.
+func (s *Service) setNotificationRuleAlertAcks(ctx context.Context, tx Tx, r influxdb.NotificationRule) error {
+	orgID := r.GetOrgID()
+	as, err := s.findAlertAcks(ctx, tx, influxdb.AlertAckFilter{
+		OrgID: &orgID,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.SetAlertAcks(as)
+	return nil
+}
+
+// updateAcknowledgedNotificationTasks regenerates the tasks of the notification
+// rules of the organization.
+func (s *Service) updateAcknowledgedNotificationTasks(ctx context.Context, tx Tx, orgID influxdb.ID) error {
+	var rules []influxdb.NotificationRule
+	err := s.forEachNotificationRule(ctx, tx, false, func(nr influxdb.NotificationRule) bool {
+		if nr.GetOrgID() == orgID {
+			rules = append(rules, nr)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, nr := range rules {
+		if _, err := s.updateNotificationTask(ctx, tx, nr, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestInmemAlertAckService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	now := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	svc.IDGenerator = mock.NewMockIDGenerator()
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	every := &notification.Duration{Values: []ast.Duration{{Magnitude: 1, Unit: "m"}}}
+	chk := &check.Deadman{
+		Base: check.Base{
+			Name:  "check",
+			OrgID: org.ID,
+			Query: influxdb.DashboardQuery{
+				Text: `data = from(bucket: "telegraf") |> range(start: -1m) |> filter(fn: (r) => r._field == "usage_user")`,
+			},
+			Every:                 every,
+			StatusMessageTemplate: "msg",
+		},
+		TimeSince: every,
+		StaleTime: every,
+		Level:     notification.Critical,
+	}
+	if err := svc.CreateCheck(ctx, influxdb.CheckCreate{Check: chk, Status: influxdb.Active}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	ep := &endpoint.Slack{
+		URL: "http://localhost:7777",
+		Base: endpoint.Base{
+			OrgID:  &org.ID,
+			Name:   "endpoint",
+			Status: influxdb.Active,
+		},
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, ep, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	nr := &rule.Slack{
+		Channel:         "ops",
+		MessageTemplate: "msg",
+		Base: rule.Base{
+			OrgID:      org.ID,
+			OwnerID:    1,
+			Name:       "rule",
+			EndpointID: *ep.ID,
+			Every:      &notification.Duration{Values: []ast.Duration{{Magnitude: 1, Unit: "h"}}},
+			StatusRules: []notification.StatusRule{
+				{CurrentLevel: notification.Critical},
+			},
+		},
+	}
+	nrc := influxdb.NotificationRuleCreate{NotificationRule: nr, Status: influxdb.Active}
+	if err := svc.CreateNotificationRule(ctx, nrc, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	taskFlux := func() string {
+		t.Helper()
+		task, err := svc.FindTaskByID(ctx, nr.TaskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task.Flux
+	}
+	if strings.Contains(taskFlux(), "acknowledged") {
+		t.Fatalf("expected the rule task not to skip acknowledged alerts:\n%s", taskFlux())
+	}
+
+	if err := svc.CreateAlertAck(ctx, &influxdb.AlertAck{
+		OrgID:   org.ID,
+		CheckID: 99,
+		Level:   "crit",
+	}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected check not found error, got %v", err)
+	}
+
+	a := &influxdb.AlertAck{
+		OrgID:   org.ID,
+		CheckID: chk.ID,
+		UserID:  1,
+		Tags:    []influxdb.Tag{{Key: "host", Value: "db1"}},
+		Level:   "crit",
+		Note:    "looking into it",
+	}
+	if err := svc.CreateAlertAck(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if want := `r._check_id == "` + chk.ID.String() + `" and r._level == "crit" and r.host == "db1"`; !strings.Contains(taskFlux(), want) {
+		t.Fatalf("expected the rule task to skip the acknowledged alert:\n%s", taskFlux())
+	}
+
+	as, n, err := svc.FindAlertAcks(ctx, influxdb.AlertAckFilter{CheckID: &chk.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || as[0].ID != a.ID || !as[0].CreatedAt.Equal(now) {
+		t.Fatalf("expected the alert acknowledgement of the check, got %v", as)
+	}
+
+	if err := svc.DeleteAlertAck(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(taskFlux(), "acknowledged") {
+		t.Fatalf("expected the rule task not to skip alerts after the acknowledgement is deleted:\n%s", taskFlux())
+	}
+	if _, err := svc.FindAlertAckByID(ctx, a.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected alert acknowledgement not found error, got %v", err)
+	}
+}
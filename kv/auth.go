@@ -564,3 +564,42 @@ func (s *Service) uniqueAuthToken(ctx context.Context, tx Tx, a *influxdb.Author
 	// should provide some debugging information.
 	return err
 }
+
+// RotateAuthorization atomically creates an authorization with the permissions
+// of the authorization and a new token, and expires the authorization at the
+// end of the grace period of the rotation.
+func (s *Service) RotateAuthorization(ctx context.Context, id influxdb.ID, r influxdb.AuthorizationRotation) (*influxdb.Authorization, error) {
+	var a *influxdb.Authorization
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		var err error
+		a, err = s.rotateAuthorization(ctx, tx, id, r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *Service) rotateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, r influxdb.AuthorizationRotation) (*influxdb.Authorization, error) {
+	old, err := s.findAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.TimeGenerator.Now()
+	a, err := old.Rotate(r, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.createAuthorization(ctx, tx, a); err != nil {
+		return nil, err
+	}
+
+	old.SetUpdatedAt(now)
+	if err := s.putAuthorization(ctx, tx, old); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -464,6 +464,10 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) (
 		return err
 	}
 
+	if err := validBucketColdTierPeriod(b); err != nil {
+		return err
+	}
+
 	if b.ID, err = s.generateBucketID(ctx, tx); err != nil {
 		return err
 	}
@@ -471,6 +475,12 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) (
 	b.CreatedAt = s.Now()
 	b.UpdatedAt = s.Now()
 
+	if b.DownsamplePolicy != nil {
+		if err := s.createDownsampleTask(ctx, tx, b); err != nil {
+			return err
+		}
+	}
+
 	if err := s.appendBucketEventToLog(ctx, tx, b.ID, bucketCreatedEvent); err != nil {
 		return &influxdb.Error{
 			Err: err,
@@ -650,6 +660,24 @@ func (s *Service) validBucketName(ctx context.Context, tx Tx, b *influxdb.Bucket
 	return err
 }
 
+// validBucketColdTierPeriod returns an error if the data of b would expire
+// before it is moved to the cold tier.
+func validBucketColdTierPeriod(b *influxdb.Bucket) error {
+	if b.ColdTierPeriod < 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "cold tier period must not be negative",
+		}
+	}
+	if b.ColdTierPeriod > 0 && b.RetentionPeriod > 0 && b.ColdTierPeriod >= b.RetentionPeriod {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "cold tier period must be shorter than the retention period",
+		}
+	}
+	return nil
+}
+
 // UpdateBucket updates a bucket according the parameters set on upd.
 func (s *Service) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
@@ -690,6 +718,14 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ColdTierPeriod != nil {
+		b.ColdTierPeriod = *upd.ColdTierPeriod
+	}
+
+	if err := validBucketColdTierPeriod(b); err != nil {
+		return nil, err
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
@@ -717,6 +753,13 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.Name = *upd.Name
 	}
 
+	// The name of the bucket is part of the name of its downsample task.
+	if upd.DownsamplePolicy != nil || (upd.Name != nil && b.DownsamplePolicy != nil) {
+		if err := s.updateDownsamplePolicy(ctx, tx, b, upd.DownsamplePolicy); err != nil {
+			return nil, err
+		}
+	}
+
 	b.UpdatedAt = s.Now()
 
 	if err := s.appendBucketEventToLog(ctx, tx, b.ID, bucketUpdatedEvent); err != nil {
@@ -747,6 +790,10 @@ func (s *Service) DeleteBucket(ctx context.Context, id influxdb.ID) error {
 			}
 		}
 
+		if err := s.validDownsampleSourceDelete(ctx, tx, id); err != nil {
+			return err
+		}
+
 		if pe := s.deleteBucket(ctx, tx, id); pe != nil {
 			err = pe
 		}
@@ -760,6 +807,12 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return pe
 	}
 
+	if b.DownsamplePolicy != nil && b.DownsamplePolicy.TaskID.Valid() {
+		if err := s.deleteTask(ctx, tx, b.DownsamplePolicy.TaskID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+	}
+
 	key, pe := bucketIndexKey(b)
 	if pe != nil {
 		return pe
@@ -805,6 +858,147 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 	return nil
 }
 
+// createDownsampleTask creates the task materializing the downsample policy
+// of b and records its ID in the policy.
+func (s *Service) createDownsampleTask(ctx context.Context, tx Tx, b *influxdb.Bucket) error {
+	script, err := s.downsampleFlux(ctx, tx, b)
+	if err != nil {
+		return err
+	}
+
+	ownerID, err := s.downsampleTaskOwner(ctx, tx, b.OrgID)
+	if err != nil {
+		return err
+	}
+
+	t, err := s.createTask(ctx, tx, influxdb.TaskCreate{
+		Type:           influxdb.TaskTypeDownsample,
+		Flux:           script,
+		OrganizationID: b.OrgID,
+		OwnerID:        ownerID,
+	})
+	if err != nil {
+		return err
+	}
+	b.DownsamplePolicy.TaskID = t.ID
+	return nil
+}
+
+// downsampleTaskOwner returns the user owning a new downsample task, which
+// is the user creating it or otherwise an owner of the organization.
+func (s *Service) downsampleTaskOwner(ctx context.Context, tx Tx, orgID influxdb.ID) (influxdb.ID, error) {
+	if a, err := icontext.GetAuthorizer(ctx); err == nil {
+		return a.GetUserID(), nil
+	}
+
+	owners, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   orgID,
+		ResourceType: influxdb.OrgsResourceType,
+		UserType:     influxdb.Owner,
+	})
+	if err != nil || len(owners) == 0 {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "downsample task requires an owner",
+			Err:  err,
+		}
+	}
+	return owners[0].UserID, nil
+}
+
+// updateDownsamplePolicy replaces the downsample policy of b with p and
+// updates its task. A nil p keeps the current policy, and an empty p removes
+// it along with its task.
+func (s *Service) updateDownsamplePolicy(ctx context.Context, tx Tx, b *influxdb.Bucket, p *influxdb.DownsamplePolicy) error {
+	var taskID influxdb.ID
+	if b.DownsamplePolicy != nil {
+		taskID = b.DownsamplePolicy.TaskID
+	}
+
+	if p != nil && p.IsZero() {
+		if taskID.Valid() {
+			if err := s.deleteTask(ctx, tx, taskID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+				return err
+			}
+		}
+		b.DownsamplePolicy = nil
+		return nil
+	}
+
+	if p != nil {
+		policy := *p
+		policy.TaskID = taskID
+		b.DownsamplePolicy = &policy
+	}
+
+	if !taskID.Valid() {
+		return s.createDownsampleTask(ctx, tx, b)
+	}
+
+	script, err := s.downsampleFlux(ctx, tx, b)
+	if err != nil {
+		return err
+	}
+	_, err = s.updateTask(ctx, tx, taskID, influxdb.TaskUpdate{Flux: &script})
+	return err
+}
+
+// downsampleFlux validates the downsample policy of b and returns the script
+// of its task.
+func (s *Service) downsampleFlux(ctx context.Context, tx Tx, b *influxdb.Bucket) (string, error) {
+	p := b.DownsamplePolicy
+	if err := p.Valid(); err != nil {
+		return "", err
+	}
+
+	if p.SourceBucketID == b.ID {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "a bucket cannot be downsampled from itself",
+		}
+	}
+	src, err := s.findBucketByID(ctx, tx, p.SourceBucketID)
+	if err != nil {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "downsample source bucket not found",
+			Err:  err,
+		}
+	}
+	if src.OrgID != b.OrgID {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "downsample source bucket must belong to the same organization",
+		}
+	}
+
+	return p.GenerateFlux(b)
+}
+
+// validDownsampleSourceDelete returns an error if the bucket with the
+// provided id is the source of a downsampled bucket.
+func (s *Service) validDownsampleSourceDelete(ctx context.Context, tx Tx, id influxdb.ID) error {
+	var target *influxdb.Bucket
+	err := s.forEachBucket(ctx, tx, false, func(b *influxdb.Bucket) bool {
+		if b.DownsamplePolicy != nil && b.DownsamplePolicy.SourceBucketID == id {
+			target = b
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if target != nil {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("bucket is the downsample source of bucket %q", target.Name),
+		}
+	}
+	return nil
+}
+
 const bucketOperationLogKeyPrefix = "bucket"
 
 func encodeBucketOperationLogKey(id influxdb.ID) ([]byte, error) {
@@ -3,8 +3,10 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kv"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
 	"go.uber.org/zap/zaptest"
@@ -80,3 +82,140 @@ func initBucketService(s kv.Store, f influxdbtesting.BucketFields, t *testing.T)
 		}
 	}
 }
+
+func TestService_DownsamplePolicy(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	if err := svc.Initialize(context.Background()); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{UserID: user.ID})
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	src := &influxdb.Bucket{OrgID: org.ID, Name: "src"}
+	if err := svc.CreateBucket(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+
+	// A policy must be valid and reference a bucket of the same organization.
+	for _, p := range []influxdb.DownsamplePolicy{
+		{SourceBucketID: src.ID, Every: influxdb.Duration{Duration: time.Hour}},
+		{SourceBucketID: src.ID, Every: influxdb.Duration{Duration: time.Hour}, Aggregates: influxdb.DownsampleAggregates{String: "mean"}},
+		{SourceBucketID: influxdb.ID(1), Every: influxdb.Duration{Duration: time.Hour}, Aggregates: influxdb.DownsampleAggregates{Float: "mean"}},
+	} {
+		p := p
+		b := &influxdb.Bucket{OrgID: org.ID, Name: "invalid", DownsamplePolicy: &p}
+		if err := svc.CreateBucket(ctx, b); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Fatalf("got error %v creating bucket with policy %+v, expected invalid", err, p)
+		}
+	}
+
+	dst := &influxdb.Bucket{
+		OrgID: org.ID,
+		Name:  "dst",
+		DownsamplePolicy: &influxdb.DownsamplePolicy{
+			SourceBucketID: src.ID,
+			Every:          influxdb.Duration{Duration: time.Hour},
+			Aggregates:     influxdb.DownsampleAggregates{Float: "mean", Boolean: "last"},
+		},
+	}
+	if err := svc.CreateBucket(ctx, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	taskID := dst.DownsamplePolicy.TaskID
+	task, err := svc.FindTaskByID(ctx, taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Type != influxdb.TaskTypeDownsample || task.Name != "Downsample dst" || task.Every != "1h" {
+		t.Fatalf("unexpected downsample task %+v", task)
+	}
+
+	// Renaming the bucket updates its task.
+	name := "dst_1h"
+	if _, err := svc.UpdateBucket(ctx, dst.ID, influxdb.BucketUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	if task, err = svc.FindTaskByID(ctx, taskID); err != nil {
+		t.Fatal(err)
+	} else if task.Name != "Downsample dst_1h" {
+		t.Fatalf("got task name %q after renaming bucket", task.Name)
+	}
+
+	if err := svc.DeleteBucket(ctx, src.ID); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("got error %v deleting source bucket, expected conflict", err)
+	}
+
+	// Removing the policy deletes the task.
+	b, err := svc.UpdateBucket(ctx, dst.ID, influxdb.BucketUpdate{DownsamplePolicy: &influxdb.DownsamplePolicy{}})
+	if err != nil {
+		t.Fatal(err)
+	} else if b.DownsamplePolicy != nil {
+		t.Fatalf("got policy %+v after removing it", b.DownsamplePolicy)
+	}
+	if _, err := svc.FindTaskByID(ctx, taskID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("got error %v finding removed task, expected not found", err)
+	}
+
+	if err := svc.DeleteBucket(ctx, src.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_ColdTierPeriod(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	if err := svc.Initialize(context.Background()); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+	ctx := context.Background()
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &influxdb.Bucket{OrgID: org.ID, Name: "b", RetentionPeriod: 24 * time.Hour, ColdTierPeriod: 24 * time.Hour}
+	if err := svc.CreateBucket(ctx, b); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("got error %v creating bucket expiring data before the cold tier, expected invalid", err)
+	}
+
+	b.ColdTierPeriod = time.Hour
+	if err := svc.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	cold := 48 * time.Hour
+	if _, err := svc.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{ColdTierPeriod: &cold}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("got error %v updating bucket expiring data before the cold tier, expected invalid", err)
+	}
+
+	// Data of buckets with infinite retention can be moved to the cold tier at any age.
+	var infinite time.Duration
+	updated, err := svc.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{RetentionPeriod: &infinite, ColdTierPeriod: &cold})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ColdTierPeriod != cold {
+		t.Fatalf("got cold tier period %s, expected %s", updated.ColdTierPeriod, cold)
+	}
+}
@@ -114,6 +114,14 @@ func (s *Service) createNotificationTask(ctx context.Context, tx Tx, r influxdb.
 		return nil, err
 	}
 
+	if err := s.setNotificationRuleSilences(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
+	if err := s.setNotificationRuleAlertAcks(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
 	script, err := r.GenerateFlux(ep)
 	if err != nil {
 		return nil, err
@@ -143,6 +151,14 @@ func (s *Service) updateNotificationTask(ctx context.Context, tx Tx, r influxdb.
 		return nil, err
 	}
 
+	if err := s.setNotificationRuleSilences(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
+	if err := s.setNotificationRuleAlertAcks(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
 	script, err := r.GenerateFlux(ep)
 	if err != nil {
 		return nil, err
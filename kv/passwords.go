@@ -204,3 +204,22 @@ func (b *Bcrypt) GenerateFromPassword(password []byte, cost int) ([]byte, error)
 	}
 	return bcrypt.GenerateFromPassword(password, cost)
 }
+
+var _ influxdb.Authenticator = (*Service)(nil)
+
+// Authenticate returns the user with the username if the password matches
+// the password recorded.
+func (s *Service) Authenticate(ctx context.Context, username, password string) (*influxdb.User, error) {
+	var u *influxdb.User
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		if u, err = s.findUserByName(ctx, tx, username); err != nil {
+			return EIncorrectPassword
+		}
+		return s.comparePassword(ctx, tx, u.ID, password)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
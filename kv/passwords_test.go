@@ -507,3 +507,56 @@ func TestService_ComparePassword(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Authenticate(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new inmem kv store: %v", err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+	u := &influxdb.User{Name: "user1"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetPassword(ctx, u.ID, "howdydoody"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUser(ctx, &influxdb.User{Name: "user2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		wantErr  bool
+	}{
+		{name: "matching password", username: "user1", password: "howdydoody"},
+		{name: "wrong password", username: "user1", password: "howdydoodi", wantErr: true},
+		{name: "user without password", username: "user2", password: "howdydoody", wantErr: true},
+		{name: "unknown user", username: "user3", password: "howdydoody", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Authenticate(ctx, tt.username, tt.password)
+			if tt.wantErr {
+				if err != kv.EIncorrectPassword {
+					t.Fatalf("expected %v, got %v", kv.EIncorrectPassword, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != u.ID {
+				t.Fatalf("got user %v, want %v", got, u)
+			}
+		})
+	}
+}
@@ -0,0 +1,562 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	roleBucket           = []byte("rolesv1")
+	roleAssignmentBucket = []byte("roleassignmentsv1")
+
+	// ErrRoleNotFound is used when the role is not found.
+	ErrRoleNotFound = &influxdb.Error{
+		Msg:  influxdb.ErrRoleNotFound,
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidRoleID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidRoleID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided role ID has invalid format",
+	}
+
+	// ErrRoleAssignmentNotFound is used when the role is not assigned to the user.
+	ErrRoleAssignmentNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "role is not assigned to the user",
+	}
+
+	// ErrRoleAssignmentExists is used when the role is already assigned to the user.
+	ErrRoleAssignmentExists = &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  "role is already assigned to the user",
+	}
+
+	// ErrRoleAssigneeNotMember is used when the user is not a member of the
+	// organization of the role.
+	ErrRoleAssigneeNotMember = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "user is not a member of the organization of the role",
+	}
+)
+
+var _ influxdb.RoleService = (*Service)(nil)
+
+func (s *Service) initializeRoles(ctx context.Context, tx Tx) error {
+	if _, err := s.roleBucket(tx); err != nil {
+		return err
+	}
+	if _, err := s.roleAssignmentBucket(tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnavailableRoleStoreError is used if we aren't able to interact with the
+// store, it means the store is not available at the moment (e.g. network).
+func UnavailableRoleStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unable to connect to role store service. Please try again; Err: %v", err),
+		Op:   "kv/role",
+	}
+}
+
+// InternalRoleStoreError is used when the error comes from an
+// internal system.
+func InternalRoleStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unknown internal role data error; Err: %v", err),
+		Op:   "kv/role",
+	}
+}
+
+func (s *Service) roleBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(roleBucket)
+	if err != nil {
+		return nil, UnavailableRoleStoreError(err)
+	}
+	return b, nil
+}
+
+func (s *Service) roleAssignmentBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(roleAssignmentBucket)
+	if err != nil {
+		return nil, UnavailableRoleStoreError(err)
+	}
+	return b, nil
+}
+
+// FindRoleByID returns a single role by ID.
+func (s *Service) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	var (
+		r   *influxdb.Role
+		err error
+	)
+
+	err = s.kv.View(ctx, func(tx Tx) error {
+		r, err = s.findRoleByID(ctx, tx, id)
+		return err
+	})
+
+	return r, err
+}
+
+func (s *Service) findRoleByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Role, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidRoleID
+	}
+
+	bucket, err := s.roleBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := bucket.Get(encID)
+	if IsNotFound(err) {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, InternalRoleStoreError(err)
+	}
+
+	r := &influxdb.Role{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, InternalRoleStoreError(err)
+	}
+	return r, nil
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+// Additional options provide pagination & sorting.
+func (s *Service) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) (rs []*influxdb.Role, n int, err error) {
+	err = s.kv.View(ctx, func(tx Tx) error {
+		rs, err = s.findRoles(ctx, tx, filter, opt...)
+		return err
+	})
+	return rs, len(rs), err
+}
+
+func (s *Service) findRoles(ctx context.Context, tx Tx, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, error) {
+	rs := make([]*influxdb.Role, 0)
+
+	var assigned map[influxdb.ID]bool
+	if filter.UserID != nil {
+		as, err := s.findRoleAssignments(ctx, tx, influxdb.RoleAssignmentFilter{UserID: filter.UserID})
+		if err != nil {
+			return nil, err
+		}
+		assigned = make(map[influxdb.ID]bool, len(as))
+		for _, a := range as {
+			assigned[a.RoleID] = true
+		}
+	}
+
+	if filter.ID != nil {
+		r, err := s.findRoleByID(ctx, tx, *filter.ID)
+		if err != nil {
+			return nil, err
+		}
+		if filterRolesFn(filter, assigned)(r) {
+			rs = append(rs, r)
+		}
+		return rs, nil
+	}
+
+	var offset, limit, count int
+	var descending bool
+	if len(opt) > 0 {
+		offset = opt[0].Offset
+		limit = opt[0].Limit
+		descending = opt[0].Descending
+	}
+	filterFn := filterRolesFn(filter, assigned)
+	err := s.forEachRole(ctx, tx, descending, func(r *influxdb.Role) bool {
+		if filterFn(r) {
+			if count >= offset {
+				rs = append(rs, r)
+			}
+			count++
+		}
+
+		if limit > 0 && len(rs) >= limit {
+			return false
+		}
+
+		return true
+	})
+
+	return rs, err
+}
+
+// forEachRole will iterate through all roles while fn returns true.
+func (s *Service) forEachRole(ctx context.Context, tx Tx, descending bool, fn func(*influxdb.Role) bool) error {
+	bkt, err := s.roleBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	cur, err := bkt.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var k, v []byte
+	if descending {
+		k, v = cur.Last()
+	} else {
+		k, v = cur.First()
+	}
+
+	for k != nil {
+		r := &influxdb.Role{}
+		if err := json.Unmarshal(v, r); err != nil {
+			return err
+		}
+		if !fn(r) {
+			break
+		}
+
+		if descending {
+			k, v = cur.Prev()
+		} else {
+			k, v = cur.Next()
+		}
+	}
+
+	return nil
+}
+
+// filterRolesFn filters the roles by the filter. The roles are restricted
+// to the ones assigned if filter.UserID is set.
+func filterRolesFn(filter influxdb.RoleFilter, assigned map[influxdb.ID]bool) func(r *influxdb.Role) bool {
+	return func(r *influxdb.Role) bool {
+		if filter.ID != nil && r.ID != *filter.ID {
+			return false
+		}
+		if filter.OrgID != nil && r.OrgID != *filter.OrgID {
+			return false
+		}
+		if filter.Name != nil && r.Name != *filter.Name {
+			return false
+		}
+		if filter.UserID != nil && !assigned[r.ID] {
+			return false
+		}
+		return true
+	}
+}
+
+// uniqueRoleName returns an error if another role of the organization has the name.
+func (s *Service) uniqueRoleName(ctx context.Context, tx Tx, r *influxdb.Role) error {
+	rs, err := s.findRoles(ctx, tx, influxdb.RoleFilter{OrgID: &r.OrgID, Name: &r.Name})
+	if err != nil {
+		return err
+	}
+	for _, o := range rs {
+		if o.ID != r.ID {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("role with name %s already exists", r.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// CreateRole creates a new role and sets r.ID with the new identifier.
+func (s *Service) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.createRole(ctx, tx, r)
+	})
+}
+
+func (s *Service) createRole(ctx context.Context, tx Tx, r *influxdb.Role) error {
+	if err := r.Valid(); err != nil {
+		return err
+	}
+
+	if _, err := s.findOrganizationByID(ctx, tx, r.OrgID); err != nil {
+		return err
+	}
+
+	if err := s.uniqueRoleName(ctx, tx, r); err != nil {
+		return err
+	}
+
+	r.ID = s.IDGenerator.ID()
+	now := s.TimeGenerator.Now()
+	r.CreatedAt = now
+	r.UpdatedAt = now
+
+	return s.putRole(ctx, tx, r)
+}
+
+// UpdateRole updates a single role with changeset.
+// Returns the new role state after update.
+func (s *Service) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	var r *influxdb.Role
+	err := s.kv.Update(ctx, func(tx Tx) (err error) {
+		r, err = s.updateRole(ctx, tx, id, upd)
+		return err
+	})
+	return r, err
+}
+
+func (s *Service) updateRole(ctx context.Context, tx Tx, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	if err := upd.Valid(); err != nil {
+		return nil, err
+	}
+
+	r, err := s.findRoleByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	upd.Apply(r)
+	r.UpdatedAt = s.TimeGenerator.Now()
+
+	if err := r.Valid(); err != nil {
+		return nil, err
+	}
+
+	if upd.Name != nil {
+		if err := s.uniqueRoleName(ctx, tx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.putRole(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *Service) putRole(ctx context.Context, tx Tx, r *influxdb.Role) error {
+	encodedID, err := r.ID.Encode()
+	if err != nil {
+		return ErrInvalidRoleID
+	}
+
+	v, err := json.Marshal(r)
+	if err != nil {
+		return InternalRoleStoreError(err)
+	}
+
+	bucket, err := s.roleBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(encodedID, v); err != nil {
+		return UnavailableRoleStoreError(err)
+	}
+	return nil
+}
+
+// DeleteRole removes a role by ID, along with its assignments.
+func (s *Service) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteRole(ctx, tx, id)
+	})
+}
+
+func (s *Service) deleteRole(ctx context.Context, tx Tx, id influxdb.ID) error {
+	if _, err := s.findRoleByID(ctx, tx, id); err != nil {
+		return err
+	}
+
+	as, err := s.findRoleAssignments(ctx, tx, influxdb.RoleAssignmentFilter{RoleID: &id})
+	if err != nil {
+		return err
+	}
+	for _, a := range as {
+		if err := s.deleteRoleAssignment(ctx, tx, a.RoleID, a.UserID); err != nil {
+			return err
+		}
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return ErrInvalidRoleID
+	}
+
+	bucket, err := s.roleBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Delete(encodedID); err != nil {
+		return InternalRoleStoreError(err)
+	}
+	return nil
+}
+
+// roleAssignmentKey is the key of an assignment, prefixed by its user so
+// that the roles of a user are stored together.
+func roleAssignmentKey(roleID, userID influxdb.ID) ([]byte, error) {
+	encodedUserID, err := userID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	encodedRoleID, err := roleID.Encode()
+	if err != nil {
+		return nil, ErrInvalidRoleID
+	}
+
+	return append(encodedUserID, encodedRoleID...), nil
+}
+
+// FindRoleAssignments returns a list of role assignments that match filter and the total count of matching assignments.
+func (s *Service) FindRoleAssignments(ctx context.Context, filter influxdb.RoleAssignmentFilter) (as []*influxdb.RoleAssignment, n int, err error) {
+	err = s.kv.View(ctx, func(tx Tx) error {
+		as, err = s.findRoleAssignments(ctx, tx, filter)
+		return err
+	})
+	return as, len(as), err
+}
+
+func (s *Service) findRoleAssignments(ctx context.Context, tx Tx, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, error) {
+	bkt, err := s.roleAssignmentBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := bkt.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	as := make([]*influxdb.RoleAssignment, 0)
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		a := &influxdb.RoleAssignment{}
+		if err := json.Unmarshal(v, a); err != nil {
+			return nil, InternalRoleStoreError(err)
+		}
+		if filter.RoleID != nil && a.RoleID != *filter.RoleID {
+			continue
+		}
+		if filter.UserID != nil && a.UserID != *filter.UserID {
+			continue
+		}
+		as = append(as, a)
+	}
+	return as, nil
+}
+
+// CreateRoleAssignment assigns a role to a user.
+func (s *Service) CreateRoleAssignment(ctx context.Context, a *influxdb.RoleAssignment) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.createRoleAssignment(ctx, tx, a)
+	})
+}
+
+func (s *Service) createRoleAssignment(ctx context.Context, tx Tx, a *influxdb.RoleAssignment) error {
+	if err := a.Valid(); err != nil {
+		return err
+	}
+
+	r, err := s.findRoleByID(ctx, tx, a.RoleID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.findUserByID(ctx, tx, a.UserID); err != nil {
+		return err
+	}
+
+	ms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   r.OrgID,
+		UserID:       a.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(ms) == 0 {
+		return ErrRoleAssigneeNotMember
+	}
+
+	key, err := roleAssignmentKey(a.RoleID, a.UserID)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := s.roleAssignmentBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := bucket.Get(key); err == nil {
+		return ErrRoleAssignmentExists
+	} else if !IsNotFound(err) {
+		return InternalRoleStoreError(err)
+	}
+
+	v, err := json.Marshal(a)
+	if err != nil {
+		return InternalRoleStoreError(err)
+	}
+
+	if err := bucket.Put(key, v); err != nil {
+		return UnavailableRoleStoreError(err)
+	}
+	return nil
+}
+
+// DeleteRoleAssignment unassigns a role from a user.
+func (s *Service) DeleteRoleAssignment(ctx context.Context, roleID, userID influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteRoleAssignment(ctx, tx, roleID, userID)
+	})
+}
+
+func (s *Service) deleteRoleAssignment(ctx context.Context, tx Tx, roleID, userID influxdb.ID) error {
+	key, err := roleAssignmentKey(roleID, userID)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := s.roleAssignmentBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := bucket.Get(key); IsNotFound(err) {
+		return ErrRoleAssignmentNotFound
+	} else if err != nil {
+		return InternalRoleStoreError(err)
+	}
+
+	if err := bucket.Delete(key); err != nil {
+		return InternalRoleStoreError(err)
+	}
+	return nil
+}
+
+// deleteOrgRoleAssignments unassigns the roles of the organization orgID from
+// the user, as the user is no longer a member of it.
+func (s *Service) deleteOrgRoleAssignments(ctx context.Context, tx Tx, orgID, userID influxdb.ID) error {
+	rs, err := s.findRoles(ctx, tx, influxdb.RoleFilter{OrgID: &orgID, UserID: &userID})
+	if err != nil {
+		return err
+	}
+	for _, r := range rs {
+		if err := s.deleteRoleAssignment(ctx, tx, r.ID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
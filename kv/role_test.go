@@ -0,0 +1,149 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestInmemRoleService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	svc.IDGenerator = mock.NewMockIDGenerator()
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	dashboards, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.DashboardsResourceType, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	role := &influxdb.Role{
+		OrgID:       org.ID,
+		Name:        "dashboard-editor",
+		Permissions: []influxdb.Permission{*dashboards},
+	}
+	if err := svc.CreateRole(ctx, role); err != nil {
+		t.Fatal(err)
+	}
+
+	dup := &influxdb.Role{
+		OrgID:       org.ID,
+		Name:        "dashboard-editor",
+		Permissions: []influxdb.Permission{*dashboards},
+	}
+	if err := svc.CreateRole(ctx, dup); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict creating role with a duplicate name, got %v", err)
+	}
+
+	other := influxdb.ID(100)
+	outside, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.DashboardsResourceType, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := &influxdb.Role{
+		OrgID:       org.ID,
+		Name:        "outside",
+		Permissions: []influxdb.Permission{*outside},
+	}
+	if err := svc.CreateRole(ctx, invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected role with a permission outside of its org to be invalid, got %v", err)
+	}
+
+	a := &influxdb.RoleAssignment{RoleID: role.ID, UserID: user.ID}
+	if err := svc.CreateRoleAssignment(ctx, a); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected assigning the role to a user outside of its org to be invalid, got %v", err)
+	}
+
+	member := &influxdb.UserResourceMapping{
+		ResourceID:   org.ID,
+		ResourceType: influxdb.OrgsResourceType,
+		UserID:       user.ID,
+		UserType:     influxdb.Member,
+	}
+	if err := svc.CreateUserResourceMapping(ctx, member); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateRoleAssignment(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateRoleAssignment(ctx, a); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected conflict assigning the role twice, got %v", err)
+	}
+
+	rs, _, err := svc.FindRoles(ctx, influxdb.RoleFilter{UserID: &user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || rs[0].ID != role.ID {
+		t.Fatalf("expected the role assigned to the user, got %v", rs)
+	}
+
+	tasks, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.TasksResourceType, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := "task-operator"
+	updated, err := svc.UpdateRole(ctx, role.ID, influxdb.RoleUpdate{
+		Name:        &name,
+		Permissions: []influxdb.Permission{*tasks},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != name || len(updated.Permissions) != 1 || updated.Permissions[0].String() != tasks.String() {
+		t.Fatalf("unexpected updated role %v", updated)
+	}
+
+	if err := svc.DeleteUserResourceMapping(ctx, org.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	as, _, err := svc.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{UserID: &user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as) != 0 {
+		t.Fatalf("expected assignments of the roles of the org to be deleted with the membership, got %v", as)
+	}
+
+	if err := svc.CreateUserResourceMapping(ctx, member); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateRoleAssignment(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.DeleteRole(ctx, role.ID); err != nil {
+		t.Fatal(err)
+	}
+	as, _, err = svc.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{UserID: &user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as) != 0 {
+		t.Fatalf("expected assignments of the role to be deleted with it, got %v", as)
+	}
+	if _, err := svc.FindRoleByID(ctx, role.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected deleted role not to be found, got %v", err)
+	}
+}
@@ -78,6 +78,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeDBRPMappings(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeDocuments(ctx, tx); err != nil {
 			return err
 		}
@@ -154,6 +158,18 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeSilences(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeAlertAcks(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeRoles(ctx, tx); err != nil {
+			return err
+		}
+
 		return s.initializeUsers(ctx, tx)
 	})
 }
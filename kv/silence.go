@@ -0,0 +1,380 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	silenceBucket = []byte("silencesv1")
+
+	// ErrSilenceNotFound is used when the silence is not found.
+	ErrSilenceNotFound = &influxdb.Error{
+		Msg:  influxdb.ErrSilenceNotFound,
+		Code: influxdb.ENotFound,
+	}
+
+	// ErrInvalidSilenceID is used when the service was provided
+	// an invalid ID format.
+	ErrInvalidSilenceID = &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  "provided silence ID has invalid format",
+	}
+)
+
+var _ influxdb.SilenceService = (*Service)(nil)
+
+func (s *Service) initializeSilences(ctx context.Context, tx Tx) error {
+	if _, err := s.silenceBucket(tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnavailableSilenceStoreError is used if we aren't able to interact with the
+// store, it means the store is not available at the moment (e.g. network).
+func UnavailableSilenceStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unable to connect to silence store service. Please try again; Err: %v", err),
+		Op:   "kv/silence",
+	}
+}
+
+// InternalSilenceStoreError is used when the error comes from an
+// internal system.
+func InternalSilenceStoreError(err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Msg:  fmt.Sprintf("Unknown internal silence data error; Err: %v", err),
+		Op:   "kv/silence",
+	}
+}
+
+func (s *Service) silenceBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return nil, UnavailableSilenceStoreError(err)
+	}
+	return b, nil
+}
+
+// FindSilenceByID returns a single silence by ID.
+func (s *Service) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var (
+		sl  *influxdb.Silence
+		err error
+	)
+
+	err = s.kv.View(ctx, func(tx Tx) error {
+		sl, err = s.findSilenceByID(ctx, tx, id)
+		return err
+	})
+
+	return sl, err
+}
+
+func (s *Service) findSilenceByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Silence, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, ErrInvalidSilenceID
+	}
+
+	bucket, err := s.silenceBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := bucket.Get(encID)
+	if IsNotFound(err) {
+		return nil, ErrSilenceNotFound
+	}
+	if err != nil {
+		return nil, InternalSilenceStoreError(err)
+	}
+
+	sl := &influxdb.Silence{}
+	if err := json.Unmarshal(v, sl); err != nil {
+		return nil, InternalSilenceStoreError(err)
+	}
+	return sl, nil
+}
+
+// FindSilences returns a list of silences that match filter and the total count of matching silences.
+// Additional options provide pagination & sorting.
+func (s *Service) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) (sls []*influxdb.Silence, n int, err error) {
+	err = s.kv.View(ctx, func(tx Tx) error {
+		sls, err = s.findSilences(ctx, tx, filter, opt...)
+		return err
+	})
+	return sls, len(sls), err
+}
+
+func (s *Service) findSilences(ctx context.Context, tx Tx, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, error) {
+	sls := make([]*influxdb.Silence, 0)
+
+	if filter.ID != nil {
+		sl, err := s.findSilenceByID(ctx, tx, *filter.ID)
+		if err != nil {
+			return nil, err
+		}
+		if filterSilencesFn(filter)(sl) {
+			sls = append(sls, sl)
+		}
+		return sls, nil
+	}
+
+	var offset, limit, count int
+	var descending bool
+	if len(opt) > 0 {
+		offset = opt[0].Offset
+		limit = opt[0].Limit
+		descending = opt[0].Descending
+	}
+	filterFn := filterSilencesFn(filter)
+	err := s.forEachSilence(ctx, tx, descending, func(sl *influxdb.Silence) bool {
+		if filterFn(sl) {
+			if count >= offset {
+				sls = append(sls, sl)
+			}
+			count++
+		}
+
+		if limit > 0 && len(sls) >= limit {
+			return false
+		}
+
+		return true
+	})
+
+	return sls, err
+}
+
+// forEachSilence will iterate through all silences while fn returns true.
+func (s *Service) forEachSilence(ctx context.Context, tx Tx, descending bool, fn func(*influxdb.Silence) bool) error {
+	bkt, err := s.silenceBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	cur, err := bkt.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var k, v []byte
+	if descending {
+		k, v = cur.Last()
+	} else {
+		k, v = cur.First()
+	}
+
+	for k != nil {
+		sl := &influxdb.Silence{}
+		if err := json.Unmarshal(v, sl); err != nil {
+			return err
+		}
+		if !fn(sl) {
+			break
+		}
+
+		if descending {
+			k, v = cur.Prev()
+		} else {
+			k, v = cur.Next()
+		}
+	}
+
+	return nil
+}
+
+func filterSilencesFn(filter influxdb.SilenceFilter) func(sl *influxdb.Silence) bool {
+	return func(sl *influxdb.Silence) bool {
+		if filter.ID != nil && sl.ID != *filter.ID {
+			return false
+		}
+		if filter.OrgID != nil && sl.OrgID != *filter.OrgID {
+			return false
+		}
+		if filter.CheckID != nil && sl.CheckID != *filter.CheckID {
+			return false
+		}
+		if filter.RuleID != nil && !sl.AppliesTo(*filter.RuleID) {
+			return false
+		}
+		return true
+	}
+}
+
+// CreateSilence creates a new silence and sets s.ID with the new identifier.
+func (s *Service) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.createSilence(ctx, tx, sl)
+	})
+}
+
+func (s *Service) createSilence(ctx context.Context, tx Tx, sl *influxdb.Silence) error {
+	if err := sl.Valid(); err != nil {
+		return err
+	}
+
+	if _, err := s.findOrganizationByID(ctx, tx, sl.OrgID); err != nil {
+		return err
+	}
+
+	if sl.RuleID.Valid() {
+		if _, err := s.findNotificationRuleByID(ctx, tx, sl.RuleID); err != nil {
+			return err
+		}
+	}
+
+	sl.ID = s.IDGenerator.ID()
+	now := s.TimeGenerator.Now()
+	sl.CreatedAt = now
+	sl.UpdatedAt = now
+
+	if err := s.putSilence(ctx, tx, sl); err != nil {
+		return err
+	}
+
+	return s.updateSilencedNotificationTasks(ctx, tx, sl)
+}
+
+// UpdateSilence updates a single silence with changeset.
+// Returns the new silence state after update.
+func (s *Service) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.Update(ctx, func(tx Tx) (err error) {
+		sl, err = s.updateSilence(ctx, tx, id, upd)
+		return err
+	})
+	return sl, err
+}
+
+func (s *Service) updateSilence(ctx context.Context, tx Tx, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	if err := upd.Valid(); err != nil {
+		return nil, err
+	}
+
+	sl, err := s.findSilenceByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	upd.Apply(sl)
+	sl.UpdatedAt = s.TimeGenerator.Now()
+
+	if err := sl.Valid(); err != nil {
+		return nil, err
+	}
+
+	if err := s.putSilence(ctx, tx, sl); err != nil {
+		return nil, err
+	}
+
+	if err := s.updateSilencedNotificationTasks(ctx, tx, sl); err != nil {
+		return nil, err
+	}
+
+	return sl, nil
+}
+
+func (s *Service) putSilence(ctx context.Context, tx Tx, sl *influxdb.Silence) error {
+	encodedID, err := sl.ID.Encode()
+	if err != nil {
+		return ErrInvalidSilenceID
+	}
+
+	v, err := json.Marshal(sl)
+	if err != nil {
+		return InternalSilenceStoreError(err)
+	}
+
+	bucket, err := s.silenceBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(encodedID, v); err != nil {
+		return UnavailableSilenceStoreError(err)
+	}
+	return nil
+}
+
+// DeleteSilence removes a silence by ID.
+func (s *Service) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteSilence(ctx, tx, id)
+	})
+}
+
+func (s *Service) deleteSilence(ctx context.Context, tx Tx, id influxdb.ID) error {
+	sl, err := s.findSilenceByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return ErrInvalidSilenceID
+	}
+
+	bucket, err := s.silenceBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Delete(encodedID); err != nil {
+		return InternalSilenceStoreError(err)
+	}
+
+	return s.updateSilencedNotificationTasks(ctx, tx, sl)
+}
+
+// setNotificationRuleSilences sets the unexpired silences of the organization
+// of the rule on the rule, so that they are part of its generated flux.
+func (s *Service) setNotificationRuleSilences(ctx context.Context, tx Tx, r influxdb.NotificationRule) error {
+	orgID, ruleID := r.GetOrgID(), r.GetID()
+	sls, err := s.findSilences(ctx, tx, influxdb.SilenceFilter{
+		OrgID:  &orgID,
+		RuleID: &ruleID,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := s.TimeGenerator.Now()
+	active := make([]*influxdb.Silence, 0, len(sls))
+	for _, sl := range sls {
+		if !sl.Expired(now) {
+			active = append(active, sl)
+		}
+	}
+	r.SetSilences(active)
+	return nil
+}
+
+// updateSilencedNotificationTasks regenerates the tasks of the notification
+// rules the silence applies to.
+func (s *Service) updateSilencedNotificationTasks(ctx context.Context, tx Tx, sl *influxdb.Silence) error {
+	var rules []influxdb.NotificationRule
+	err := s.forEachNotificationRule(ctx, tx, false, func(nr influxdb.NotificationRule) bool {
+		if nr.GetOrgID() == sl.OrgID && sl.AppliesTo(nr.GetID()) {
+			rules = append(rules, nr)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, nr := range rules {
+		if _, err := s.updateNotificationTask(ctx, tx, nr, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
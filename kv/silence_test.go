@@ -0,0 +1,130 @@
+package kv_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestInmemSilenceService(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	now := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	svc.IDGenerator = mock.NewMockIDGenerator()
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing service: %v", err)
+	}
+
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	ep := &endpoint.Slack{
+		URL: "http://localhost:7777",
+		Base: endpoint.Base{
+			OrgID:  &org.ID,
+			Name:   "endpoint",
+			Status: influxdb.Active,
+		},
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, ep, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	nr := &rule.Slack{
+		Channel:         "ops",
+		MessageTemplate: "msg",
+		Base: rule.Base{
+			OrgID:      org.ID,
+			OwnerID:    1,
+			Name:       "rule",
+			EndpointID: *ep.ID,
+			Every:      &notification.Duration{Values: []ast.Duration{{Magnitude: 1, Unit: "h"}}},
+			StatusRules: []notification.StatusRule{
+				{CurrentLevel: notification.Critical},
+			},
+		},
+	}
+	nrc := influxdb.NotificationRuleCreate{NotificationRule: nr, Status: influxdb.Active}
+	if err := svc.CreateNotificationRule(ctx, nrc, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	taskFlux := func() string {
+		t.Helper()
+		task, err := svc.FindTaskByID(ctx, nr.TaskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task.Flux
+	}
+	if strings.Contains(taskFlux(), "silenced") {
+		t.Fatalf("expected the rule task not to be silenced:\n%s", taskFlux())
+	}
+
+	sl := &influxdb.Silence{
+		OrgID:     org.ID,
+		Name:      "maintenance",
+		CheckID:   3,
+		StartTime: now,
+		EndTime:   now.Add(2 * time.Hour),
+	}
+	if err := svc.CreateSilence(ctx, sl); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(taskFlux(), `r._check_id == "0000000000000003"`) {
+		t.Fatalf("expected the rule task to be silenced:\n%s", taskFlux())
+	}
+
+	otherRuleID := influxdb.ID(99)
+	sls, _, err := svc.FindSilences(ctx, influxdb.SilenceFilter{RuleID: &otherRuleID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sls) != 1 || sls[0].ID != sl.ID {
+		t.Fatalf("expected the silence without rule to apply to any rule, got %v", sls)
+	}
+
+	end := now.Add(-time.Hour)
+	if _, err := svc.UpdateSilence(ctx, sl.ID, influxdb.SilenceUpdate{EndTime: &end}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an invalid silence update error, got %v", err)
+	}
+
+	name := "planned maintenance"
+	updated, err := svc.UpdateSilence(ctx, sl.ID, influxdb.SilenceUpdate{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != name {
+		t.Fatalf("expected silence name %q, got %q", name, updated.Name)
+	}
+
+	if err := svc.DeleteSilence(ctx, sl.ID); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(taskFlux(), "silenced") {
+		t.Fatalf("expected the rule task not to be silenced after the silence is deleted:\n%s", taskFlux())
+	}
+	if _, err := svc.FindSilenceByID(ctx, sl.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected silence not found error, got %v", err)
+	}
+}
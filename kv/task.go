@@ -23,13 +23,16 @@ import (
 //   <taskID>/latestCompleted: run data for the latest completed run of a task
 // taskIndexBucket
 //   <orgID>/<taskID>: index for tasks by org
+// taskBackfillBucket
+//   <taskID>/<backfillID>: backfill data storage
 
 // We may want to add a <taskName>/<taskID> index to allow us to look up tasks by task name.
 
 var (
-	taskBucket      = []byte("tasksv1")
-	taskRunBucket   = []byte("taskRunsv1")
-	taskIndexBucket = []byte("taskIndexsv1")
+	taskBucket         = []byte("tasksv1")
+	taskRunBucket      = []byte("taskRunsv1")
+	taskIndexBucket    = []byte("taskIndexsv1")
+	taskBackfillBucket = []byte("taskBackfillsv1")
 )
 
 var _ influxdb.TaskService = (*Service)(nil)
@@ -50,6 +53,7 @@ type kvTask struct {
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
 	Offset          influxdb.Duration      `json:"offset,omitempty"`
+	DependsOn       []influxdb.ID          `json:"dependsOn,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
@@ -73,6 +77,7 @@ func kvToInfluxTask(k *kvTask) *influxdb.Task {
 		LastRunStatus:   k.LastRunStatus,
 		LastRunError:    k.LastRunError,
 		Offset:          k.Offset.Duration,
+		DependsOn:       k.DependsOn,
 		LatestCompleted: k.LatestCompleted,
 		LatestScheduled: k.LatestScheduled,
 		CreatedAt:       k.CreatedAt,
@@ -91,6 +96,9 @@ func (s *Service) initializeTasks(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(taskIndexBucket); err != nil {
 		return err
 	}
+	if _, err := tx.Bucket(taskBackfillBucket); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -606,6 +614,10 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 
 	}
 
+	if task.DependsOn, err = s.taskDependencies(ctx, tx, task, opt.DependsOn); err != nil {
+		return nil, err
+	}
+
 	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -673,6 +685,65 @@ func (s *Service) createTaskURM(ctx context.Context, tx Tx, t *influxdb.Task) er
 	})
 }
 
+// taskDependencies returns the IDs of the tasks dependsOn of task.
+// The tasks must be other tasks of the organization of task, and must not
+// depend on task themselves, directly or not.
+func (s *Service) taskDependencies(ctx context.Context, tx Tx, task *influxdb.Task, dependsOn []string) ([]influxdb.ID, error) {
+	if len(dependsOn) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]influxdb.ID, 0, len(dependsOn))
+	for _, v := range dependsOn {
+		var id influxdb.ID
+		if err := id.DecodeFromString(v); err != nil {
+			return nil, influxdb.ErrTaskOptionParse(err)
+		}
+		if id == task.ID {
+			return nil, influxdb.ErrTaskDependencyCycle
+		}
+		upstream, err := s.findTaskByID(ctx, tx, id)
+		if err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				return nil, influxdb.ErrTaskDependencyNotFound(id)
+			}
+			return nil, err
+		}
+		if upstream.OrganizationID != task.OrganizationID {
+			return nil, influxdb.ErrTaskDependencyNotFound(id)
+		}
+		ids = append(ids, id)
+	}
+
+	// walk the tasks upstream of task to make sure none of them depends on it.
+	visited := map[influxdb.ID]bool{}
+	next := append([]influxdb.ID(nil), ids...)
+	for len(next) > 0 {
+		id := next[len(next)-1]
+		next = next[:len(next)-1]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		upstream, err := s.findTaskByID(ctx, tx, id)
+		if err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				// a deleted task can't be part of a cycle.
+				continue
+			}
+			return nil, err
+		}
+		for _, uid := range upstream.DependsOn {
+			if uid == task.ID {
+				return nil, influxdb.ErrTaskDependencyCycle
+			}
+			next = append(next, uid)
+		}
+	}
+	return ids, nil
+}
+
 // UpdateTask updates a single task with changeset.
 func (s *Service) UpdateTask(ctx context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
 	var t *influxdb.Task
@@ -723,6 +794,10 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 			}
 		}
 		task.Offset = off
+
+		if task.DependsOn, err = s.taskDependencies(ctx, tx, task, options.DependsOn); err != nil {
+			return nil, err
+		}
 		task.UpdatedAt = updatedAt
 	}
 
@@ -866,6 +941,12 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 			return influxdb.ErrUnexpectedTaskBucketErr(err)
 		}
 	}
+
+	// remove the backfills
+	if err := s.deleteBackfills(ctx, tx, task.ID); err != nil {
+		return err
+	}
+
 	// remove the task
 	key, err := taskKey(task.ID)
 	if err != nil {
@@ -1099,7 +1180,8 @@ func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID
 	r.Status = backend.RunScheduled.String()
 	r.StartedAt = time.Time{}
 	r.FinishedAt = time.Time{}
-	r.RequestedAt = time.Time{}
+	// a retry is requested like a forced run, it does not trigger the runs of the dependent tasks.
+	r.RequestedAt = time.Now().UTC()
 
 	// add a clean copy of the run to the manual runs
 	bucket, err := tx.Bucket(taskRunBucket)
@@ -1159,18 +1241,16 @@ func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor
 }
 
 func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
-	// create a run
-	t := time.Unix(scheduledFor, 0).UTC()
-	r := &influxdb.Run{
-		ID:           s.IDGenerator.ID(),
-		TaskID:       taskID,
-		Status:       backend.RunScheduled.String(),
-		RequestedAt:  time.Now().UTC(),
-		ScheduledFor: t,
-		Log:          []influxdb.Log{},
+	runs, err := s.queueManualRuns(ctx, tx, taskID, []time.Time{time.Unix(scheduledFor, 0).UTC()})
+	if err != nil {
+		return nil, err
 	}
+	return runs[0], nil
+}
 
-	// add a clean copy of the run to the manual runs
+// queueManualRuns adds a run for every time of scheduledFor to the manual runs of the task.
+func (s *Service) queueManualRuns(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor []time.Time) ([]*influxdb.Run, error) {
+	// add a clean copy of the runs to the manual runs
 	bucket, err := tx.Bucket(taskRunBucket)
 	if err != nil {
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -1181,13 +1261,31 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, sched
 		return nil, err
 	}
 
-	// check to see if this run is already queued
+	queued := make(map[time.Time]bool, len(runs))
 	for _, run := range runs {
-		if run.ScheduledFor == r.ScheduledFor {
+		queued[run.ScheduledFor] = true
+	}
+
+	created := make([]*influxdb.Run, 0, len(scheduledFor))
+	for _, t := range scheduledFor {
+		// check to see if this run is already queued
+		if queued[t] {
 			return nil, influxdb.ErrTaskRunAlreadyQueued
 		}
+		queued[t] = true
+
+		// create a run
+		r := &influxdb.Run{
+			ID:           s.IDGenerator.ID(),
+			TaskID:       taskID,
+			Status:       backend.RunScheduled.String(),
+			RequestedAt:  time.Now().UTC(),
+			ScheduledFor: t,
+			Log:          []influxdb.Log{},
+		}
+		runs = append(runs, r)
+		created = append(created, r)
 	}
-	runs = append(runs, r)
 
 	// save manual runs
 	runsBytes, err := json.Marshal(runs)
@@ -1204,7 +1302,267 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, sched
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
-	return r, nil
+	return created, nil
+}
+
+// BackfillTask creates a backfill of the task for every time of its schedule after the start
+// of the request, up to and including its stop, and queues its first runs. The next runs are
+// queued as the previous ones finish.
+func (s *Service) BackfillTask(ctx context.Context, taskID influxdb.ID, req influxdb.BackfillRequest) (*influxdb.Backfill, error) {
+	if err := req.Validate(); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	var b *influxdb.Backfill
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		backfill, err := s.backfillTask(ctx, tx, taskID, req)
+		if err != nil {
+			return err
+		}
+		b = backfill
+		return nil
+	})
+	return b, err
+}
+
+func (s *Service) backfillTask(ctx context.Context, tx Tx, taskID influxdb.ID, req influxdb.BackfillRequest) (*influxdb.Backfill, error) {
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledFor, err := backfillSchedule(task, req.Start, req.Stop)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &influxdb.Backfill{
+		TaskID:       taskID,
+		Start:        req.Start.UTC(),
+		Stop:         req.Stop.UTC(),
+		Concurrency:  req.Concurrency,
+		ScheduledFor: scheduledFor,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if b.Concurrency == 0 {
+		b.Concurrency = 1
+	}
+
+	if req.DryRun {
+		return b, nil
+	}
+
+	b.ID = s.IDGenerator.ID()
+	b.Status = influxdb.BackfillStatusRunning
+	b.Runs = []influxdb.ID{}
+	if err := s.queueBackfillRuns(ctx, tx, b); err != nil {
+		return nil, err
+	}
+
+	if err := s.putBackfill(ctx, tx, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// backfillSchedule returns the times of the schedule of the task after start, up to and including stop.
+func backfillSchedule(task *influxdb.Task, start, stop time.Time) ([]time.Time, error) {
+	if task.EffectiveCron() == "" {
+		return nil, influxdb.ErrTaskNotScheduled
+	}
+
+	sch, err := cron.Parse(task.EffectiveCron())
+	if err != nil {
+		return nil, influxdb.ErrTaskTimeParse(err)
+	}
+
+	// align the start to the every of the task, as its scheduled runs are
+	t := start.UTC().Truncate(time.Second)
+	if task.Every != "" {
+		every := options.Duration{}
+		if err := every.Parse(task.Every); err != nil {
+			return nil, influxdb.ErrTaskTimeParse(err)
+		}
+		everyDur, err := every.DurationFrom(t)
+		if err != nil {
+			return nil, influxdb.ErrTaskTimeParse(err)
+		}
+		t = t.Truncate(everyDur)
+	}
+
+	var scheduledFor []time.Time
+	// Next returns the zero time if no time of the schedule can be found.
+	for t = sch.Next(t); !t.IsZero() && !t.After(stop); t = sch.Next(t) {
+		if len(scheduledFor) == influxdb.MaxBackfillRuns {
+			return nil, influxdb.ErrBackfillTooManyRuns
+		}
+		scheduledFor = append(scheduledFor, t.UTC())
+	}
+	return scheduledFor, nil
+}
+
+// FindBackfills returns the backfills of a task, the oldest first.
+func (s *Service) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	var backfills []*influxdb.Backfill
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findTaskByID(ctx, tx, taskID); err != nil {
+			return err
+		}
+
+		bs, err := s.findBackfills(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		backfills = bs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backfills, nil
+}
+
+func (s *Service) findBackfills(ctx context.Context, tx Tx, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	bucket, err := tx.Bucket(taskBackfillBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	prefix, err := taskKey(taskID)
+	if err != nil {
+		return nil, err
+	}
+	prefix = append(prefix, '/')
+
+	c, err := bucket.Cursor(WithCursorHintPrefix(string(prefix)))
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	backfills := []*influxdb.Backfill{}
+	for k, v := c.Seek(prefix); k != nil && strings.HasPrefix(string(k), string(prefix)); k, v = c.Next() {
+		b := &influxdb.Backfill{}
+		if err := json.Unmarshal(v, b); err != nil {
+			return nil, influxdb.ErrInternalTaskServiceError(err)
+		}
+		backfills = append(backfills, b)
+	}
+	return backfills, nil
+}
+
+func (s *Service) putBackfill(ctx context.Context, tx Tx, b *influxdb.Backfill) error {
+	bucket, err := tx.Bucket(taskBackfillBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskBackfillKey(b.TaskID, b.ID)
+	if err != nil {
+		return err
+	}
+
+	v, err := json.Marshal(b)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	if err := bucket.Put(key, v); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
+// finishBackfillRun counts the finished run in the running backfill of its task it belongs to, if any.
+func (s *Service) finishBackfillRun(ctx context.Context, tx Tx, r *influxdb.Run) error {
+	backfills, err := s.findBackfills(ctx, tx, r.TaskID)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range backfills {
+		if b.Status != influxdb.BackfillStatusRunning || !containsID(b.Runs, r.ID) {
+			continue
+		}
+
+		if r.Status == backend.RunSuccess.String() {
+			b.Succeeded++
+		} else {
+			b.Failed++
+		}
+
+		// the next runs are only queued once the previous ones finished, so that
+		// the concurrency of the backfill holds whatever executes the runs.
+		if err := s.queueBackfillRuns(ctx, tx, b); err != nil {
+			return err
+		}
+
+		return s.putBackfill(ctx, tx, b)
+	}
+	return nil
+}
+
+// queueBackfillRuns queues the next runs of the backfill, until as many runs
+// as its concurrency are queued and unfinished, and finishes the backfill once
+// all of its runs finished. The times a manual run is already queued for are
+// skipped.
+func (s *Service) queueBackfillRuns(ctx context.Context, tx Tx, b *influxdb.Backfill) error {
+	for b.Running() < b.Concurrency && len(b.Runs)+b.Skipped < len(b.ScheduledFor) {
+		runs, err := s.queueManualRuns(ctx, tx, b.TaskID, []time.Time{b.ScheduledFor[len(b.Runs)+b.Skipped]})
+		if err == influxdb.ErrTaskRunAlreadyQueued {
+			b.Skipped++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		b.Runs = append(b.Runs, runs[0].ID)
+	}
+
+	if b.Finished() == len(b.ScheduledFor) {
+		b.FinishedAt = time.Now().UTC()
+		b.Status = influxdb.BackfillStatusSuccess
+		if b.Failed > 0 {
+			b.Status = influxdb.BackfillStatusFailed
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteBackfills(ctx context.Context, tx Tx, taskID influxdb.ID) error {
+	backfills, err := s.findBackfills(ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := tx.Bucket(taskBackfillBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	for _, b := range backfills {
+		key, err := taskBackfillKey(taskID, b.ID)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Delete(key); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+	}
+	return nil
+}
+
+func containsID(ids []influxdb.ID, id influxdb.ID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
 }
 
 // CreateNextRun creates the earliest needed run scheduled no later than the given Unix timestamp now.
@@ -1578,6 +1936,78 @@ func (s *Service) startManualRun(ctx context.Context, tx Tx, taskID, runID influ
 	return run, nil
 }
 
+// QueueRetry adds the next attempt of the currently running run runID to the manual runs of the task,
+// to be started at runAt.
+func (s *Service) QueueRetry(ctx context.Context, taskID, runID influxdb.ID, runAt time.Time) (*influxdb.Run, error) {
+	var r *influxdb.Run
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		run, err := s.queueRetry(ctx, tx, taskID, runID, runAt)
+		if err != nil {
+			return err
+		}
+		r = run
+		return nil
+	})
+	return r, err
+}
+
+func (s *Service) queueRetry(ctx context.Context, tx Tx, taskID, runID influxdb.ID, runAt time.Time) (*influxdb.Run, error) {
+	run, err := s.findRunByID(ctx, tx, taskID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.manualRuns(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range runs {
+		if r.ScheduledFor.Equal(run.ScheduledFor) {
+			return nil, influxdb.ErrTaskRunAlreadyQueued
+		}
+	}
+
+	// runs created before they were retried are first attempts.
+	attempt := run.Attempt
+	if attempt < 1 {
+		attempt = 1
+	}
+	r := &influxdb.Run{
+		ID:           s.IDGenerator.ID(),
+		TaskID:       taskID,
+		Status:       backend.RunScheduled.String(),
+		ScheduledFor: run.ScheduledFor,
+		RunAt:        runAt.UTC(),
+		RequestedAt:  run.RequestedAt,
+		Attempt:      attempt + 1,
+		Log:          []influxdb.Log{},
+	}
+	runs = append(runs, r)
+
+	// save manual runs
+	runsBytes, err := json.Marshal(runs)
+	if err != nil {
+		return nil, influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	key, err := taskManualRunKey(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, err := tx.Bucket(taskRunBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	if err := bucket.Put(key, runsBytes); err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	return r, nil
+}
+
 // FinishRun removes runID from the list of running tasks and if its `now` is later then last completed update it.
 func (s *Service) FinishRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
 	var run *influxdb.Run
@@ -1621,6 +2051,10 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 		return nil, err
 	}
 
+	if err := s.finishBackfillRun(ctx, tx, r); err != nil {
+		return nil, err
+	}
+
 	// remove run
 	bucket, err := tx.Bucket(taskRunBucket)
 	if err != nil {
@@ -1873,6 +2307,18 @@ func taskManualRunKey(taskID influxdb.ID) ([]byte, error) {
 	return []byte(string(encodedID) + "/manualRuns"), nil
 }
 
+func taskBackfillKey(taskID, backfillID influxdb.ID) ([]byte, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, influxdb.ErrInvalidTaskID
+	}
+	encodedBackfillID, err := backfillID.Encode()
+	if err != nil {
+		return nil, influxdb.ErrInvalidTaskID
+	}
+	return []byte(string(encodedID) + "/" + string(encodedBackfillID)), nil
+}
+
 func taskOrgKey(orgID, taskID influxdb.ID) ([]byte, error) {
 	encodedOrgID, err := orgID.Encode()
 	if err != nil {
@@ -440,3 +440,133 @@ func TestTaskRunCancellation(t *testing.T) {
 		t.Fatalf("expected task run to be cancelled")
 	}
 }
+
+func TestService_BackfillTask(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := influxdb.BackfillRequest{
+		Start:  time.Date(2019, 1, 1, 0, 30, 0, 0, time.UTC),
+		Stop:   time.Date(2019, 1, 1, 4, 0, 0, 0, time.UTC),
+		DryRun: true,
+	}
+	expScheduledFor := []time.Time{
+		time.Date(2019, 1, 1, 1, 0, 0, 0, time.UTC),
+		time.Date(2019, 1, 1, 2, 0, 0, 0, time.UTC),
+		time.Date(2019, 1, 1, 3, 0, 0, 0, time.UTC),
+		time.Date(2019, 1, 1, 4, 0, 0, 0, time.UTC),
+	}
+
+	b, err := ts.Service.BackfillTask(ctx, task.ID, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expScheduledFor, b.ScheduledFor); diff != "" {
+		t.Fatalf("unexpected scheduled times -want/+got:\n%s", diff)
+	}
+	if mRuns, err := ts.Service.ManualRuns(ctx, task.ID); err != nil || len(mRuns) != 0 {
+		t.Fatalf("expected no manual runs from a dry run, got %d, %v", len(mRuns), err)
+	}
+
+	// a run forced for a time of the backfill is not queued twice.
+	forced, err := ts.Service.ForceRun(ctx, task.ID, expScheduledFor[3].Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.DryRun = false
+	req.Concurrency = 2
+	b, err = ts.Service.BackfillTask(ctx, task.ID, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Status != influxdb.BackfillStatusRunning || b.Concurrency != 2 || len(b.Runs) != 2 {
+		t.Fatalf("unexpected backfill: %+v", b)
+	}
+
+	// only as many runs as the concurrency of the backfill are queued at once.
+	mRuns, err := ts.Service.ManualRuns(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mRuns) != 3 {
+		t.Fatalf("expected the forced run and 2 runs of the backfill queued, got %d", len(mRuns))
+	}
+
+	for i, state := range []backend.RunStatus{backend.RunSuccess, backend.RunFail, backend.RunSuccess} {
+		backfills, err := ts.Service.FindBackfills(ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = backfills[0]
+		if b.Running() > b.Concurrency {
+			t.Fatalf("expected at most %d runs of the backfill queued, got %d", b.Concurrency, b.Running())
+		}
+
+		r, err := ts.Service.StartManualRun(ctx, task.ID, b.Runs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.ScheduledFor.Equal(expScheduledFor[i]) {
+			t.Fatalf("expected run %d scheduled for %s, got %s", i, expScheduledFor[i], r.ScheduledFor)
+		}
+		if err := ts.Service.UpdateRunState(ctx, task.ID, r.ID, time.Now(), state); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ts.Service.FinishRun(ctx, task.ID, r.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backfills, err := ts.Service.FindBackfills(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backfills) != 1 {
+		t.Fatalf("expected 1 backfill, got %d", len(backfills))
+	}
+	b = backfills[0]
+	if b.Status != influxdb.BackfillStatusFailed || len(b.Runs) != 3 || b.Succeeded != 2 || b.Failed != 1 || b.Skipped != 1 || b.FinishedAt.IsZero() {
+		t.Fatalf("unexpected finished backfill: %+v", b)
+	}
+	if containsRun(b.Runs, forced.ID) {
+		t.Fatalf("expected the forced run not to be a run of the backfill: %+v", b)
+	}
+
+	_, err = ts.Service.BackfillTask(ctx, task.ID, influxdb.BackfillRequest{
+		Start: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		Stop:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != influxdb.ErrBackfillTooManyRuns {
+		t.Fatalf("expected too many runs error, got %v", err)
+	}
+
+	if err := ts.Service.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.Service.FindBackfills(ctx, task.ID); err != influxdb.ErrTaskNotFound {
+		t.Fatalf("expected task not found error, got %v", err)
+	}
+}
+
+func containsRun(ids []influxdb.ID, id influxdb.ID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -399,7 +399,7 @@ func (s *Service) deleteOrgDependentMappings(ctx context.Context, tx Tx, m *infl
 		// TODO(desa): add support for all other resource types.
 	}
 
-	return nil
+	return s.deleteOrgRoleAssignments(ctx, tx, m.ResourceID, m.UserID)
 }
 
 func (s *Service) addResourceOwner(ctx context.Context, tx Tx, rt influxdb.ResourceType, id influxdb.ID) error {
@@ -0,0 +1,142 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// defaults of the configuration.
+const (
+	DefaultUserFilter         = "(uid=%s)"
+	DefaultGroupFilter        = "(member=%s)"
+	DefaultGroupNameAttribute = "cn"
+	DefaultTimeout            = 10 * time.Second
+)
+
+// Config is the configuration of the LDAP authentication.
+type Config struct {
+	// URL is the ldap:// or ldaps:// URL of the server.
+	URL string
+	// StartTLS upgrades the ldap:// connections to TLS before binding.
+	StartTLS bool
+	// CACert is the path of the PEM encoded certificates of the authorities
+	// verifying the server certificate; the system ones if empty.
+	CACert             string
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+
+	// BindDN and BindPassword are the credentials searching the users and
+	// groups; the search is anonymous if BindDN is empty.
+	BindDN       string
+	BindPassword string
+
+	// UserSearchBase is the base DN of the users; UserFilter finds the user
+	// with the username, which replaces its %s.
+	UserSearchBase string
+	UserFilter     string
+
+	// GroupSearchBase is the base DN of the groups; GroupFilter finds the
+	// groups of the user with the DN, which replaces its %s. The groups are
+	// named by their GroupNameAttribute.
+	GroupSearchBase    string
+	GroupFilter        string
+	GroupNameAttribute string
+
+	// GroupMappings map the groups to the roles of their members on the organizations.
+	GroupMappings []GroupMapping
+
+	// LinkedUsers are the names of the existing users which are not LDAP
+	// users, that the LDAP users of the same name sign in as, their roles
+	// being synced with their groups.
+	LinkedUsers []string
+}
+
+// Valid returns an error if the configuration is invalid.
+func (c Config) Valid() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+		return fmt.Errorf("LDAP URL %q must be an ldap:// or ldaps:// URL", c.URL)
+	}
+	if c.StartTLS && u.Scheme == "ldaps" {
+		return fmt.Errorf("LDAP StartTLS cannot be used with an ldaps:// URL")
+	}
+	if c.UserSearchBase == "" {
+		return fmt.Errorf("LDAP user search base is required")
+	}
+	if len(c.GroupMappings) > 0 && c.GroupSearchBase == "" {
+		return fmt.Errorf("LDAP group search base is required to map groups")
+	}
+	return nil
+}
+
+func (c Config) withDefaults() Config {
+	if c.UserFilter == "" {
+		c.UserFilter = DefaultUserFilter
+	}
+	if c.GroupFilter == "" {
+		c.GroupFilter = DefaultGroupFilter
+	}
+	if c.GroupNameAttribute == "" {
+		c.GroupNameAttribute = DefaultGroupNameAttribute
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	return c
+}
+
+func (c Config) tlsConfig() (*tls.Config, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CACert != "" {
+		pem, err := ioutil.ReadFile(c.CACert)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in LDAP CA certificate %s", c.CACert)
+		}
+	}
+	return cfg, nil
+}
+
+// GroupMapping gives the members of an LDAP group a role on an organization.
+type GroupMapping struct {
+	Group string
+	Org   string
+	Role  influxdb.UserType
+}
+
+// ParseGroupMapping parses a group mapping of the form group=org:role, the
+// role being owner or member.
+func ParseGroupMapping(s string) (GroupMapping, error) {
+	eq := strings.Index(s, "=")
+	colon := strings.LastIndex(s, ":")
+	if eq <= 0 || colon <= eq+1 {
+		return GroupMapping{}, fmt.Errorf("LDAP group mapping %q must be of the form group=org:role", s)
+	}
+
+	m := GroupMapping{
+		Group: s[:eq],
+		Org:   s[eq+1 : colon],
+		Role:  influxdb.UserType(s[colon+1:]),
+	}
+	if m.Role != influxdb.Owner && m.Role != influxdb.Member {
+		return GroupMapping{}, fmt.Errorf("LDAP group mapping %q role must be owner or member", s)
+	}
+	return m, nil
+}
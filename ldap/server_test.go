@@ -0,0 +1,302 @@
+package ldap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+const oidStartTLS = "1.3.6.1.4.1.1466.20037"
+
+// testEntry is an entry of the directory of the test server.
+type testEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// testServer is an in-process LDAP server supporting the operations of the
+// client: simple binds, searches, StartTLS and unbinds.
+type testServer struct {
+	ln        net.Listener
+	tlsConfig *tls.Config
+	// caCert is the path of the PEM encoded certificate of the server.
+	caCert string
+	ldaps  bool
+	// requireTLS rejects the operations of the connections not using TLS.
+	requireTLS bool
+
+	mu      sync.Mutex
+	entries map[string]*testEntry
+}
+
+// newTestServer starts an LDAP server listening on 127.0.0.1, with TLS from
+// the start if ldaps is set.
+func newTestServer(t *testing.T, ldaps bool) *testServer {
+	t.Helper()
+
+	cert, certPEM := newTestCertificate(t)
+	f, err := ioutil.TempFile("", "ldap-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(certPEM); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s := &testServer{
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		caCert:    f.Name(),
+		ldaps:     ldaps,
+		entries:   make(map[string]*testEntry),
+	}
+	if ldaps {
+		s.ln, err = tls.Listen("tcp", "127.0.0.1:0", s.tlsConfig)
+	} else {
+		s.ln, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.serve()
+	return s
+}
+
+// URL returns the URL of the server.
+func (s *testServer) URL() string {
+	if s.ldaps {
+		return "ldaps://" + s.ln.Addr().String()
+	}
+	return "ldap://" + s.ln.Addr().String()
+}
+
+// Close stops the server.
+func (s *testServer) Close() {
+	s.ln.Close()
+	os.Remove(s.caCert)
+}
+
+// add adds or replaces an entry of the directory.
+func (s *testServer) add(dn, password string, attributes map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[strings.ToLower(dn)] = &testEntry{dn: dn, password: password, attributes: attributes}
+}
+
+// setMembers sets the members of the group with the DN.
+func (s *testServer) setMembers(dn string, members ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[strings.ToLower(dn)].attributes["member"] = members
+}
+
+func (s *testServer) serve() {
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(c)
+	}
+}
+
+func (s *testServer) handle(c net.Conn) {
+	defer c.Close()
+
+	_, secure := c.(*tls.Conn)
+	for {
+		msg, err := ber.ReadPacket(c)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id, op := msg.Children[0].Value, msg.Children[1]
+
+		respond := func(ops ...*ber.Packet) bool {
+			for _, op := range ops {
+				m := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+				m.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+				m.AppendChild(op)
+				if _, err := c.Write(m.Bytes()); err != nil {
+					return false
+				}
+			}
+			return true
+		}
+
+		switch {
+		case isOp(op, goldap.ApplicationUnbindRequest):
+			return
+		case isOp(op, goldap.ApplicationExtendedRequest):
+			if op.Children[0].Data.String() != oidStartTLS || secure {
+				respond(testResult(goldap.ApplicationExtendedResponse, goldap.LDAPResultProtocolError, "unsupported extended operation"))
+				continue
+			}
+			if !respond(testResult(goldap.ApplicationExtendedResponse, goldap.LDAPResultSuccess, "")) {
+				return
+			}
+			tc := tls.Server(c, s.tlsConfig)
+			if err := tc.Handshake(); err != nil {
+				return
+			}
+			c, secure = tc, true
+		case s.requireTLS && !secure:
+			tag := ber.Tag(goldap.ApplicationBindResponse)
+			if isOp(op, goldap.ApplicationSearchRequest) {
+				tag = goldap.ApplicationSearchResultDone
+			}
+			respond(testResult(tag, goldap.LDAPResultConfidentialityRequired, "TLS is required"))
+		case isOp(op, goldap.ApplicationBindRequest):
+			respond(s.bind(op.Children[1].Data.String(), op.Children[2].Data.String()))
+		case isOp(op, goldap.ApplicationSearchRequest):
+			respond(s.search(op)...)
+		default:
+			return
+		}
+	}
+}
+
+func isOp(op *ber.Packet, tag ber.Tag) bool {
+	return op.ClassType == ber.ClassApplication && op.Tag == tag
+}
+
+func (s *testServer) bind(dn, password string) *ber.Packet {
+	if dn == "" && password == "" {
+		return testResult(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[strings.ToLower(dn)]
+	if !ok || password == "" || e.password != password {
+		return testResult(goldap.ApplicationBindResponse, goldap.LDAPResultInvalidCredentials, "invalid credentials")
+	}
+	return testResult(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+}
+
+func (s *testServer) search(op *ber.Packet) []*ber.Packet {
+	base := strings.ToLower(op.Children[0].Data.String())
+	filter := op.Children[6]
+	var attrs []string
+	for _, a := range op.Children[7].Children {
+		attrs = append(attrs, strings.ToLower(a.Data.String()))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*ber.Packet
+	for dn, e := range s.entries {
+		if !strings.HasSuffix(dn, base) || !matchFilter(filter, e) {
+			continue
+		}
+		as := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for name, vs := range e.attributes {
+			if !containsString(attrs, strings.ToLower(name)) {
+				continue
+			}
+			a := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			a.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, v := range vs {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+			}
+			a.AppendChild(set)
+			as.AppendChild(a)
+		}
+		entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "DN"))
+		entry.AppendChild(as)
+		res = append(res, entry)
+	}
+	return append(res, testResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess, ""))
+}
+
+func matchFilter(f *ber.Packet, e *testEntry) bool {
+	switch f.Tag {
+	case goldap.FilterAnd:
+		for _, c := range f.Children {
+			if !matchFilter(c, e) {
+				return false
+			}
+		}
+		return true
+	case goldap.FilterOr:
+		for _, c := range f.Children {
+			if matchFilter(c, e) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterNot:
+		return !matchFilter(f.Children[0], e)
+	case goldap.FilterEqualityMatch:
+		for _, v := range testValues(e, f.Children[0].Data.String()) {
+			if strings.EqualFold(v, f.Children[1].Data.String()) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterPresent:
+		return len(testValues(e, f.Data.String())) > 0
+	}
+	return false
+}
+
+func testValues(e *testEntry, attr string) []string {
+	for name, vs := range e.attributes {
+		if strings.EqualFold(name, attr) {
+			return vs
+		}
+	}
+	return nil
+}
+
+func testResult(tag ber.Tag, code uint16, msg string) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, msg, "Diagnostic Message"))
+	return res
+}
+
+// newTestCertificate returns a self-signed certificate of 127.0.0.1 and its PEM encoding.
+func newTestCertificate(t *testing.T) (tls.Certificate, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
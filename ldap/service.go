@@ -0,0 +1,299 @@
+// Package ldap authenticates the users against an LDAP directory and syncs
+// the roles of the users on the organizations with their LDAP groups.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// noAttributes requests the entries of a search without their attributes, see RFC 4511.
+const noAttributes = "1.1"
+
+var _ influxdb.Authenticator = (*Service)(nil)
+
+// Service authenticates the users with an LDAP bind. The users are created
+// on their first sign in, and their roles on the organizations of the group
+// mappings are synced with their groups on each sign in.
+type Service struct {
+	log       *zap.Logger
+	config    Config
+	tlsConfig *tls.Config
+
+	UserService                influxdb.UserService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+}
+
+// NewService returns a new LDAP authentication service.
+func NewService(log *zap.Logger, config Config, us influxdb.UserService, os influxdb.OrganizationService, urms influxdb.UserResourceMappingService) (*Service, error) {
+	if err := config.Valid(); err != nil {
+		return nil, err
+	}
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		log:                        log,
+		config:                     config.withDefaults(),
+		tlsConfig:                  tlsConfig,
+		UserService:                us,
+		OrganizationService:        os,
+		UserResourceMappingService: urms,
+	}, nil
+}
+
+// Authenticate binds as the LDAP user with the username and returns its
+// user, after syncing its roles on the organizations with its groups.
+func (s *Service) Authenticate(ctx context.Context, username, password string) (*influxdb.User, error) {
+	// an empty password would be an unauthenticated bind, which succeeds.
+	if username == "" || password == "" {
+		return nil, influxdb.ErrAuthenticationFailed
+	}
+
+	c, err := s.dial()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "unable to connect to the LDAP server",
+			Err:  err,
+		}
+	}
+	defer c.Close()
+
+	dn, err := s.findUserDN(c, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Bind(dn, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, influxdb.ErrAuthenticationFailed
+		}
+		return nil, ldapError(err)
+	}
+
+	groups, err := s.findGroups(c, dn)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.provision(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.syncRoles(ctx, u, groups); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *Service) dial() (*goldap.Conn, error) {
+	c, err := goldap.DialURL(s.config.URL,
+		goldap.DialWithDialer(&net.Dialer{Timeout: s.config.Timeout}),
+		goldap.DialWithTLSConfig(s.tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	c.SetTimeout(s.config.Timeout)
+	if s.config.StartTLS {
+		if err := c.StartTLS(s.tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// bindService binds with the search credentials, if any.
+func (s *Service) bindService(c *goldap.Conn) error {
+	if s.config.BindDN == "" {
+		return nil
+	}
+	if err := c.Bind(s.config.BindDN, s.config.BindPassword); err != nil {
+		return ldapError(err)
+	}
+	return nil
+}
+
+// search returns the entries under base matching filter, with the attributes requested.
+func (s *Service) search(c *goldap.Conn, base, filter string, attrs ...string) ([]*goldap.Entry, error) {
+	res, err := c.Search(goldap.NewSearchRequest(
+		base, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases, 0, int(s.config.Timeout.Seconds()), false,
+		filter, attrs, nil,
+	))
+	if err != nil {
+		return nil, ldapError(err)
+	}
+	return res.Entries, nil
+}
+
+func (s *Service) findUserDN(c *goldap.Conn, username string) (string, error) {
+	if err := s.bindService(c); err != nil {
+		return "", err
+	}
+
+	es, err := s.search(c, s.config.UserSearchBase, fmt.Sprintf(s.config.UserFilter, goldap.EscapeFilter(username)), noAttributes)
+	if err != nil {
+		return "", err
+	}
+	if len(es) != 1 {
+		s.log.Debug("LDAP user not found or not unique", zap.String("user", username), zap.Int("entries", len(es)))
+		return "", influxdb.ErrAuthenticationFailed
+	}
+	return es[0].DN, nil
+}
+
+// findGroups returns the names of the groups of the user with the DN.
+func (s *Service) findGroups(c *goldap.Conn, dn string) ([]string, error) {
+	if len(s.config.GroupMappings) == 0 {
+		return nil, nil
+	}
+	// the groups are searched with the search credentials, not the ones of the user.
+	if err := s.bindService(c); err != nil {
+		return nil, err
+	}
+
+	es, err := s.search(c, s.config.GroupSearchBase, fmt.Sprintf(s.config.GroupFilter, goldap.EscapeFilter(dn)), s.config.GroupNameAttribute)
+	if err != nil {
+		return nil, err
+	}
+	var groups []string
+	for _, e := range es {
+		groups = append(groups, e.GetEqualFoldAttributeValues(s.config.GroupNameAttribute)...)
+	}
+	return groups, nil
+}
+
+// provision returns the user with the username, creating it on its first sign
+// in. The users created are marked as LDAP users by their OAuth ID; an existing
+// user which is not is refused, unless it is one of the linked users of the
+// configuration, so that an LDAP entry never takes over a local user.
+func (s *Service) provision(ctx context.Context, username string) (*influxdb.User, error) {
+	u, err := s.UserService.FindUser(ctx, influxdb.UserFilter{Name: &username})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		u = &influxdb.User{Name: username, OAuthID: identity(username), Status: influxdb.Active}
+		if err := s.UserService.CreateUser(ctx, u); err != nil {
+			return nil, err
+		}
+		s.log.Info("Created user signed in through LDAP", zap.String("user", username))
+		return u, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if u.OAuthID != identity(username) && !containsString(s.config.LinkedUsers, username) {
+		s.log.Warn("Refused LDAP sign in of a user not provisioned through LDAP", zap.String("user", username))
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  fmt.Sprintf("user %q is not an LDAP user", username),
+		}
+	}
+	if u.Status == influxdb.Inactive {
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "user is inactive",
+		}
+	}
+	return u, nil
+}
+
+// identity returns the OAuth ID marking the user of the username as an LDAP user.
+func identity(username string) string {
+	return "ldap:" + username
+}
+
+// syncRoles sets the role of the user on each organization of the group
+// mappings to the highest role of its groups; the user is removed from the
+// organizations none of its groups maps to. The other organizations of the
+// user are left untouched.
+func (s *Service) syncRoles(ctx context.Context, u *influxdb.User, groups []string) error {
+	var (
+		orgs  []string
+		roles = make(map[string]influxdb.UserType)
+	)
+	for _, m := range s.config.GroupMappings {
+		if _, ok := roles[m.Org]; !ok {
+			orgs = append(orgs, m.Org)
+			roles[m.Org] = ""
+		}
+		if containsString(groups, m.Group) && roles[m.Org] != influxdb.Owner {
+			roles[m.Org] = m.Role
+		}
+	}
+
+	for _, name := range orgs {
+		name := name
+		org, err := s.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &name})
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			s.log.Debug("Skipping unknown organization of LDAP group mapping", zap.String("org", name))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.syncRole(ctx, u, org, roles[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) syncRole(ctx context.Context, u *influxdb.User, org *influxdb.Organization, role influxdb.UserType) error {
+	ms, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceID:   org.ID,
+		ResourceType: influxdb.OrgsResourceType,
+		UserID:       u.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(ms) > 0 {
+		if ms[0].UserType == role {
+			return nil
+		}
+		if err := s.UserResourceMappingService.DeleteUserResourceMapping(ctx, org.ID, u.ID); err != nil {
+			return err
+		}
+	}
+	if role == "" {
+		s.log.Info("Removed LDAP user from organization", zap.String("user", u.Name), zap.String("org", org.Name))
+		return nil
+	}
+
+	if err := s.UserResourceMappingService.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		ResourceID:   org.ID,
+		ResourceType: influxdb.OrgsResourceType,
+		UserID:       u.ID,
+		UserType:     role,
+	}); err != nil {
+		return err
+	}
+	s.log.Info("Set role of LDAP user on organization", zap.String("user", u.Name), zap.String("org", org.Name), zap.String("role", string(role)))
+	return nil
+}
+
+// ldapError wraps an error of the LDAP server.
+func ldapError(err error) error {
+	return &influxdb.Error{
+		Code: influxdb.EUnavailable,
+		Msg:  "LDAP operation failed",
+		Err:  err,
+	}
+}
+
+func containsString(vs []string, v string) bool {
+	for _, s := range vs {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+package ldap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	bindDN    = "cn=influxdb,ou=services,dc=example,dc=com"
+	aliceDN   = "uid=alice,ou=people,dc=example,dc=com"
+	bobDN     = "uid=bob,ou=people,dc=example,dc=com"
+	adminsDN  = "cn=admins,ou=groups,dc=example,dc=com"
+	devsDN    = "cn=devs,ou=groups,dc=example,dc=com"
+	orgOps    = "ops"
+	orgDev    = "dev"
+	orgOthers = "others"
+)
+
+func newTestDirectory(t *testing.T, ldaps bool) *testServer {
+	t.Helper()
+
+	s := newTestServer(t, ldaps)
+	s.add(bindDN, "influxdb-secret", map[string][]string{"cn": {"influxdb"}})
+	s.add(aliceDN, "alice-secret", map[string][]string{"uid": {"alice"}, "objectClass": {"person"}})
+	s.add(bobDN, "bob-secret", map[string][]string{"uid": {"bob"}, "objectClass": {"person"}})
+	s.add(adminsDN, "", map[string][]string{"cn": {"admins"}, "member": {aliceDN}})
+	s.add(devsDN, "", map[string][]string{"cn": {"devs"}, "member": {aliceDN, bobDN}})
+	return s
+}
+
+func newTestService(t *testing.T, s *testServer, startTLS bool) (*Service, *kv.Service) {
+	t.Helper()
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+	for _, name := range []string{orgOps, orgDev, orgOthers} {
+		if err := svc.CreateOrganization(ctx, &influxdb.Organization{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ls, err := NewService(zaptest.NewLogger(t), Config{
+		URL:             s.URL(),
+		StartTLS:        startTLS,
+		CACert:          s.caCert,
+		BindDN:          bindDN,
+		BindPassword:    "influxdb-secret",
+		UserSearchBase:  "ou=people,dc=example,dc=com",
+		UserFilter:      "(&(objectClass=person)(uid=%s))",
+		GroupSearchBase: "ou=groups,dc=example,dc=com",
+		GroupMappings: []GroupMapping{
+			{Group: "admins", Org: orgOps, Role: influxdb.Owner},
+			{Group: "devs", Org: orgOps, Role: influxdb.Member},
+			{Group: "devs", Org: orgDev, Role: influxdb.Member},
+		},
+	}, svc, svc, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ls, svc
+}
+
+// roles returns the roles of the user on the organizations, by organization name.
+func roles(t *testing.T, svc *kv.Service, userID influxdb.ID) map[string]influxdb.UserType {
+	t.Helper()
+
+	ctx := context.Background()
+	ms, _, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		UserID:       userID,
+		ResourceType: influxdb.OrgsResourceType,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := make(map[string]influxdb.UserType)
+	for _, m := range ms {
+		o, err := svc.FindOrganizationByID(ctx, m.ResourceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs[o.Name] = m.UserType
+	}
+	return rs
+}
+
+func TestService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("StartTLS bind creates the user with the roles of its groups", func(t *testing.T) {
+		s := newTestDirectory(t, false)
+		defer s.Close()
+		s.requireTLS = true
+		ls, svc := newTestService(t, s, true)
+
+		u, err := ls.Authenticate(ctx, "alice", "alice-secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Name != "alice" || u.Status != influxdb.Active || u.OAuthID != "ldap:alice" {
+			t.Fatalf("unexpected user %v", u)
+		}
+		rs := roles(t, svc, u.ID)
+		if len(rs) != 2 || rs[orgOps] != influxdb.Owner || rs[orgDev] != influxdb.Member {
+			t.Fatalf("unexpected roles %v", rs)
+		}
+	})
+
+	t.Run("ldaps bind", func(t *testing.T) {
+		s := newTestDirectory(t, true)
+		defer s.Close()
+		ls, svc := newTestService(t, s, false)
+
+		u, err := ls.Authenticate(ctx, "bob", "bob-secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs := roles(t, svc, u.ID)
+		if len(rs) != 2 || rs[orgOps] != influxdb.Member || rs[orgDev] != influxdb.Member {
+			t.Fatalf("unexpected roles %v", rs)
+		}
+	})
+
+	t.Run("roles are synced on each sign in", func(t *testing.T) {
+		s := newTestDirectory(t, false)
+		defer s.Close()
+		ls, svc := newTestService(t, s, false)
+
+		u, err := ls.Authenticate(ctx, "alice", "alice-secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the organizations of no group mapping are not synced.
+		others, err := svc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: strPtr(orgOthers)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+			ResourceID:   others.ID,
+			ResourceType: influxdb.OrgsResourceType,
+			UserID:       u.ID,
+			UserType:     influxdb.Owner,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		s.setMembers(adminsDN)
+		if _, err := ls.Authenticate(ctx, "alice", "alice-secret"); err != nil {
+			t.Fatal(err)
+		}
+		rs := roles(t, svc, u.ID)
+		if len(rs) != 3 || rs[orgOps] != influxdb.Member || rs[orgDev] != influxdb.Member || rs[orgOthers] != influxdb.Owner {
+			t.Fatalf("unexpected roles %v", rs)
+		}
+
+		s.setMembers(devsDN, bobDN)
+		if _, err := ls.Authenticate(ctx, "alice", "alice-secret"); err != nil {
+			t.Fatal(err)
+		}
+		rs = roles(t, svc, u.ID)
+		if len(rs) != 1 || rs[orgOthers] != influxdb.Owner {
+			t.Fatalf("unexpected roles %v", rs)
+		}
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		s := newTestDirectory(t, false)
+		defer s.Close()
+		ls, svc := newTestService(t, s, false)
+
+		for _, c := range []struct {
+			username, password string
+		}{
+			{username: "alice", password: "wrong"},
+			{username: "alice", password: ""},
+			{username: "carol", password: "alice-secret"},
+			{username: "*", password: "alice-secret"},
+			{username: "alice)(uid=*", password: "alice-secret"},
+		} {
+			if _, err := ls.Authenticate(ctx, c.username, c.password); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Errorf("%s: expected unauthorized error, got %v", c.username, err)
+			}
+		}
+		if _, n, err := svc.FindUsers(ctx, influxdb.UserFilter{}); err != nil || n != 0 {
+			t.Fatalf("expected no user to be created, got %d users: %v", n, err)
+		}
+	})
+
+	t.Run("inactive user", func(t *testing.T) {
+		s := newTestDirectory(t, false)
+		defer s.Close()
+		ls, svc := newTestService(t, s, false)
+
+		u := &influxdb.User{Name: "alice", OAuthID: "ldap:alice"}
+		if err := svc.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		u.Status = influxdb.Inactive
+		if err := svc.PutUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ls.Authenticate(ctx, "alice", "alice-secret"); influxdb.ErrorCode(err) != influxdb.EForbidden {
+			t.Fatalf("expected forbidden error, got %v", err)
+		}
+	})
+
+	t.Run("local user of the same name", func(t *testing.T) {
+		s := newTestDirectory(t, false)
+		defer s.Close()
+		ls, svc := newTestService(t, s, false)
+
+		u := &influxdb.User{Name: "alice", Status: influxdb.Active}
+		if err := svc.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ls.Authenticate(ctx, "alice", "alice-secret"); influxdb.ErrorCode(err) != influxdb.EForbidden {
+			t.Fatalf("expected forbidden error, got %v", err)
+		}
+		if rs := roles(t, svc, u.ID); len(rs) != 0 {
+			t.Fatalf("expected the roles of the local user not to be synced, got %v", rs)
+		}
+
+		ls.config.LinkedUsers = []string{"alice"}
+		got, err := ls.Authenticate(ctx, "alice", "alice-secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != u.ID {
+			t.Fatalf("got user %v, want the linked user %v", got, u)
+		}
+		if rs := roles(t, svc, u.ID); len(rs) != 2 {
+			t.Fatalf("expected the roles of the linked user to be synced, got %v", rs)
+		}
+	})
+
+	t.Run("unavailable server", func(t *testing.T) {
+		s := newTestDirectory(t, false)
+		ls, _ := newTestService(t, s, false)
+		s.Close()
+
+		if _, err := ls.Authenticate(ctx, "alice", "alice-secret"); influxdb.ErrorCode(err) != influxdb.EUnavailable {
+			t.Fatalf("expected unavailable error, got %v", err)
+		}
+	})
+}
+
+func TestParseGroupMapping(t *testing.T) {
+	tests := []struct {
+		mapping string
+		want    GroupMapping
+		wantErr bool
+	}{
+		{mapping: "admins=ops:owner", want: GroupMapping{Group: "admins", Org: "ops", Role: influxdb.Owner}},
+		{mapping: "devs=my:org:member", want: GroupMapping{Group: "devs", Org: "my:org", Role: influxdb.Member}},
+		{mapping: "devs=ops:reader", wantErr: true},
+		{mapping: "devs:member", wantErr: true},
+		{mapping: "=ops:owner", wantErr: true},
+		{mapping: "devs=:owner", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseGroupMapping(tt.mapping)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.mapping, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.mapping, got, tt.want)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// AlertService is a mock implementation of an influxdb.AlertService.
+type AlertService struct {
+	FindAlertsFn          func(context.Context, influxdb.AlertFilter) ([]*influxdb.Alert, error)
+	FindAlertsCalls       SafeCount
+	FindAlertAckByIDFn    func(context.Context, influxdb.ID) (*influxdb.AlertAck, error)
+	FindAlertAckByIDCalls SafeCount
+	FindAlertAcksFn       func(context.Context, influxdb.AlertAckFilter, ...influxdb.FindOptions) ([]*influxdb.AlertAck, int, error)
+	FindAlertAcksCalls    SafeCount
+	CreateAlertAckFn      func(context.Context, *influxdb.AlertAck) error
+	CreateAlertAckCalls   SafeCount
+	DeleteAlertAckFn      func(context.Context, influxdb.ID) error
+	DeleteAlertAckCalls   SafeCount
+}
+
+// NewAlertService returns a mock AlertService where its methods will return
+// zero values.
+func NewAlertService() *AlertService {
+	return &AlertService{
+		FindAlertsFn:       func(context.Context, influxdb.AlertFilter) ([]*influxdb.Alert, error) { return nil, nil },
+		FindAlertAckByIDFn: func(context.Context, influxdb.ID) (*influxdb.AlertAck, error) { return nil, nil },
+		FindAlertAcksFn: func(context.Context, influxdb.AlertAckFilter, ...influxdb.FindOptions) ([]*influxdb.AlertAck, int, error) {
+			return nil, 0, nil
+		},
+		CreateAlertAckFn: func(context.Context, *influxdb.AlertAck) error { return nil },
+		DeleteAlertAckFn: func(context.Context, influxdb.ID) error { return nil },
+	}
+}
+
+// FindAlerts returns the firing alerts that match filter.
+func (s *AlertService) FindAlerts(ctx context.Context, filter influxdb.AlertFilter) ([]*influxdb.Alert, error) {
+	defer s.FindAlertsCalls.IncrFn()()
+	return s.FindAlertsFn(ctx, filter)
+}
+
+// FindAlertAckByID returns a single alert acknowledgement by ID.
+func (s *AlertService) FindAlertAckByID(ctx context.Context, id influxdb.ID) (*influxdb.AlertAck, error) {
+	defer s.FindAlertAckByIDCalls.IncrFn()()
+	return s.FindAlertAckByIDFn(ctx, id)
+}
+
+// FindAlertAcks returns a list of alert acknowledgements that match filter and the total count of matching acknowledgements.
+func (s *AlertService) FindAlertAcks(ctx context.Context, filter influxdb.AlertAckFilter, opts ...influxdb.FindOptions) ([]*influxdb.AlertAck, int, error) {
+	defer s.FindAlertAcksCalls.IncrFn()()
+	return s.FindAlertAcksFn(ctx, filter, opts...)
+}
+
+// CreateAlertAck acknowledges an alert.
+func (s *AlertService) CreateAlertAck(ctx context.Context, a *influxdb.AlertAck) error {
+	defer s.CreateAlertAckCalls.IncrFn()()
+	return s.CreateAlertAckFn(ctx, a)
+}
+
+// DeleteAlertAck removes an alert acknowledgement by ID.
+func (s *AlertService) DeleteAlertAck(ctx context.Context, id influxdb.ID) error {
+	defer s.DeleteAlertAckCalls.IncrFn()()
+	return s.DeleteAlertAckFn(ctx, id)
+}
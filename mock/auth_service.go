@@ -20,6 +20,7 @@ type AuthorizationService struct {
 	CreateAuthorizationFn      func(context.Context, *platform.Authorization) error
 	DeleteAuthorizationFn      func(context.Context, platform.ID) error
 	UpdateAuthorizationFn      func(context.Context, platform.ID, *platform.AuthorizationUpdate) (*platform.Authorization, error)
+	RotateAuthorizationFn      func(context.Context, platform.ID, platform.AuthorizationRotation) (*platform.Authorization, error)
 }
 
 // NewAuthorizationService returns a mock AuthorizationService where its methods will return
@@ -36,6 +37,9 @@ func NewAuthorizationService() *AuthorizationService {
 		UpdateAuthorizationFn: func(context.Context, platform.ID, *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 			return nil, nil
 		},
+		RotateAuthorizationFn: func(context.Context, platform.ID, platform.AuthorizationRotation) (*platform.Authorization, error) {
+			return nil, nil
+		},
 	}
 }
 
@@ -67,3 +71,8 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id platform.ID, upd *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 	return s.UpdateAuthorizationFn(ctx, id, upd)
 }
+
+// RotateAuthorization creates an authorization replacing the authorization with a new token.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, r platform.AuthorizationRotation) (*platform.Authorization, error) {
+	return s.RotateAuthorizationFn(ctx, id, r)
+}
@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.Authenticator = (*Authenticator)(nil)
+
+// Authenticator is a mock implementation of influxdb.Authenticator.
+type Authenticator struct {
+	AuthenticateFn func(ctx context.Context, username, password string) (*influxdb.User, error)
+}
+
+// Authenticate returns the user with the username if the password is its.
+func (a *Authenticator) Authenticate(ctx context.Context, username, password string) (*influxdb.User, error) {
+	return a.AuthenticateFn(ctx, username, password)
+}
@@ -0,0 +1,96 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// RoleService is a mock implementation of an influxdb.RoleService.
+type RoleService struct {
+	FindRoleByIDFn            func(context.Context, influxdb.ID) (*influxdb.Role, error)
+	FindRoleByIDCalls         SafeCount
+	FindRolesFn               func(context.Context, influxdb.RoleFilter, ...influxdb.FindOptions) ([]*influxdb.Role, int, error)
+	FindRolesCalls            SafeCount
+	CreateRoleFn              func(context.Context, *influxdb.Role) error
+	CreateRoleCalls           SafeCount
+	UpdateRoleFn              func(context.Context, influxdb.ID, influxdb.RoleUpdate) (*influxdb.Role, error)
+	UpdateRoleCalls           SafeCount
+	DeleteRoleFn              func(context.Context, influxdb.ID) error
+	DeleteRoleCalls           SafeCount
+	FindRoleAssignmentsFn     func(context.Context, influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error)
+	FindRoleAssignmentsCalls  SafeCount
+	CreateRoleAssignmentFn    func(context.Context, *influxdb.RoleAssignment) error
+	CreateRoleAssignmentCalls SafeCount
+	DeleteRoleAssignmentFn    func(context.Context, influxdb.ID, influxdb.ID) error
+	DeleteRoleAssignmentCalls SafeCount
+}
+
+// NewRoleService returns a mock RoleService where its methods will return
+// zero values.
+func NewRoleService() *RoleService {
+	return &RoleService{
+		FindRoleByIDFn: func(context.Context, influxdb.ID) (*influxdb.Role, error) { return nil, nil },
+		FindRolesFn: func(context.Context, influxdb.RoleFilter, ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+			return nil, 0, nil
+		},
+		CreateRoleFn: func(context.Context, *influxdb.Role) error { return nil },
+		UpdateRoleFn: func(context.Context, influxdb.ID, influxdb.RoleUpdate) (*influxdb.Role, error) { return nil, nil },
+		DeleteRoleFn: func(context.Context, influxdb.ID) error { return nil },
+		FindRoleAssignmentsFn: func(context.Context, influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+			return nil, 0, nil
+		},
+		CreateRoleAssignmentFn: func(context.Context, *influxdb.RoleAssignment) error { return nil },
+		DeleteRoleAssignmentFn: func(context.Context, influxdb.ID, influxdb.ID) error { return nil },
+	}
+}
+
+// FindRoleByID returns a single role by ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	defer s.FindRoleByIDCalls.IncrFn()()
+	return s.FindRoleByIDFn(ctx, id)
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opts ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	defer s.FindRolesCalls.IncrFn()()
+	return s.FindRolesFn(ctx, filter, opts...)
+}
+
+// CreateRole creates a new role.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	defer s.CreateRoleCalls.IncrFn()()
+	return s.CreateRoleFn(ctx, r)
+}
+
+// UpdateRole updates a single role with changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	defer s.UpdateRoleCalls.IncrFn()()
+	return s.UpdateRoleFn(ctx, id, upd)
+}
+
+// DeleteRole removes a role by ID.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	defer s.DeleteRoleCalls.IncrFn()()
+	return s.DeleteRoleFn(ctx, id)
+}
+
+// FindRoleAssignments returns a list of role assignments that match filter.
+func (s *RoleService) FindRoleAssignments(ctx context.Context, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+	defer s.FindRoleAssignmentsCalls.IncrFn()()
+	return s.FindRoleAssignmentsFn(ctx, filter)
+}
+
+// CreateRoleAssignment assigns a role to a user.
+func (s *RoleService) CreateRoleAssignment(ctx context.Context, a *influxdb.RoleAssignment) error {
+	defer s.CreateRoleAssignmentCalls.IncrFn()()
+	return s.CreateRoleAssignmentFn(ctx, a)
+}
+
+// DeleteRoleAssignment unassigns a role from a user.
+func (s *RoleService) DeleteRoleAssignment(ctx context.Context, roleID, userID influxdb.ID) error {
+	defer s.DeleteRoleAssignmentCalls.IncrFn()()
+	return s.DeleteRoleAssignmentFn(ctx, roleID, userID)
+}
@@ -12,28 +12,32 @@ var _ influxdb.TaskService = (*TaskService)(nil)
 var _ backend.TaskControlService = (*TaskControlService)(nil)
 
 type TaskService struct {
-	FindTaskByIDFn    func(context.Context, influxdb.ID) (*influxdb.Task, error)
-	FindTaskByIDCalls SafeCount
-	FindTasksFn       func(context.Context, influxdb.TaskFilter) ([]*influxdb.Task, int, error)
-	FindTasksCalls    SafeCount
-	CreateTaskFn      func(context.Context, influxdb.TaskCreate) (*influxdb.Task, error)
-	CreateTaskCalls   SafeCount
-	UpdateTaskFn      func(context.Context, influxdb.ID, influxdb.TaskUpdate) (*influxdb.Task, error)
-	UpdateTaskCalls   SafeCount
-	DeleteTaskFn      func(context.Context, influxdb.ID) error
-	DeleteTaskCalls   SafeCount
-	FindLogsFn        func(context.Context, influxdb.LogFilter) ([]*influxdb.Log, int, error)
-	FindLogsCalls     SafeCount
-	FindRunsFn        func(context.Context, influxdb.RunFilter) ([]*influxdb.Run, int, error)
-	FindRunsCalls     SafeCount
-	FindRunByIDFn     func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Run, error)
-	FindRunByIDCalls  SafeCount
-	CancelRunFn       func(context.Context, influxdb.ID, influxdb.ID) error
-	CancelRunCalls    SafeCount
-	RetryRunFn        func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Run, error)
-	RetryRunCalls     SafeCount
-	ForceRunFn        func(context.Context, influxdb.ID, int64) (*influxdb.Run, error)
-	ForceRunCalls     SafeCount
+	FindTaskByIDFn     func(context.Context, influxdb.ID) (*influxdb.Task, error)
+	FindTaskByIDCalls  SafeCount
+	FindTasksFn        func(context.Context, influxdb.TaskFilter) ([]*influxdb.Task, int, error)
+	FindTasksCalls     SafeCount
+	CreateTaskFn       func(context.Context, influxdb.TaskCreate) (*influxdb.Task, error)
+	CreateTaskCalls    SafeCount
+	UpdateTaskFn       func(context.Context, influxdb.ID, influxdb.TaskUpdate) (*influxdb.Task, error)
+	UpdateTaskCalls    SafeCount
+	DeleteTaskFn       func(context.Context, influxdb.ID) error
+	DeleteTaskCalls    SafeCount
+	FindLogsFn         func(context.Context, influxdb.LogFilter) ([]*influxdb.Log, int, error)
+	FindLogsCalls      SafeCount
+	FindRunsFn         func(context.Context, influxdb.RunFilter) ([]*influxdb.Run, int, error)
+	FindRunsCalls      SafeCount
+	FindRunByIDFn      func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Run, error)
+	FindRunByIDCalls   SafeCount
+	CancelRunFn        func(context.Context, influxdb.ID, influxdb.ID) error
+	CancelRunCalls     SafeCount
+	RetryRunFn         func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Run, error)
+	RetryRunCalls      SafeCount
+	ForceRunFn         func(context.Context, influxdb.ID, int64) (*influxdb.Run, error)
+	ForceRunCalls      SafeCount
+	BackfillTaskFn     func(context.Context, influxdb.ID, influxdb.BackfillRequest) (*influxdb.Backfill, error)
+	BackfillTaskCalls  SafeCount
+	FindBackfillsFn    func(context.Context, influxdb.ID) ([]*influxdb.Backfill, error)
+	FindBackfillsCalls SafeCount
 }
 
 func NewTaskService() *TaskService {
@@ -71,6 +75,12 @@ func NewTaskService() *TaskService {
 		ForceRunFn: func(ctx context.Context, id influxdb.ID, i int64) (*influxdb.Run, error) {
 			return nil, nil
 		},
+		BackfillTaskFn: func(ctx context.Context, id influxdb.ID, req influxdb.BackfillRequest) (*influxdb.Backfill, error) {
+			return nil, nil
+		},
+		FindBackfillsFn: func(ctx context.Context, id influxdb.ID) ([]*influxdb.Backfill, error) {
+			return nil, nil
+		},
 	}
 }
 
@@ -129,6 +139,16 @@ func (s *TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, schedule
 	return s.ForceRunFn(ctx, taskID, scheduledFor)
 }
 
+func (s *TaskService) BackfillTask(ctx context.Context, taskID influxdb.ID, req influxdb.BackfillRequest) (*influxdb.Backfill, error) {
+	defer s.BackfillTaskCalls.IncrFn()()
+	return s.BackfillTaskFn(ctx, taskID, req)
+}
+
+func (s *TaskService) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	defer s.FindBackfillsCalls.IncrFn()()
+	return s.FindBackfillsFn(ctx, taskID)
+}
+
 type TaskControlService struct {
 	CreateNextRunFn    func(ctx context.Context, taskID influxdb.ID, now int64) (backend.RunCreation, error)
 	NextDueRunFn       func(ctx context.Context, taskID influxdb.ID) (int64, error)
@@ -136,6 +156,7 @@ type TaskControlService struct {
 	CurrentlyRunningFn func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error)
 	ManualRunsFn       func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error)
 	StartManualRunFn   func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error)
+	QueueRetryFn       func(ctx context.Context, taskID, runID influxdb.ID, runAt time.Time) (*influxdb.Run, error)
 	FinishRunFn        func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error)
 	UpdateRunStateFn   func(ctx context.Context, taskID, runID influxdb.ID, when time.Time, state backend.RunStatus) error
 	AddRunLogFn        func(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error
@@ -159,6 +180,9 @@ func (tcs *TaskControlService) ManualRuns(ctx context.Context, taskID influxdb.I
 func (tcs *TaskControlService) StartManualRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
 	return tcs.StartManualRunFn(ctx, taskID, runID)
 }
+func (tcs *TaskControlService) QueueRetry(ctx context.Context, taskID, runID influxdb.ID, runAt time.Time) (*influxdb.Run, error) {
+	return tcs.QueueRetryFn(ctx, taskID, runID, runAt)
+}
 func (tcs *TaskControlService) FinishRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
 	return tcs.FinishRunFn(ctx, taskID, runID)
 }
@@ -2008,6 +2008,18 @@ func TestParsePointsWithPrecision(t *testing.T) {
 			precision: "s",
 			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946730096000000000",
 		},
+		{
+			name:      "minute",
+			line:      `cpu,host=serverA,region=us-east value=1.0 15778834`,
+			precision: "m",
+			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946730040000000000",
+		},
+		{
+			name:      "hour",
+			line:      `cpu,host=serverA,region=us-east value=1.0 262980`,
+			precision: "h",
+			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946728000000000000",
+		},
 	}
 	for _, test := range tests {
 		pts, err := models.ParsePointsWithPrecision([]byte(test.line), []byte("mm"), time.Now().UTC(), test.precision)
@@ -2059,6 +2071,16 @@ func TestParsePointsWithPrecisionNoTime(t *testing.T) {
 			precision: "s",
 			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946730096000000000",
 		},
+		{
+			name:      "minute precision",
+			precision: "m",
+			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946730040000000000",
+		},
+		{
+			name:      "hour precision",
+			precision: "h",
+			exp:       "mm,\x00=cpu,host=serverA,region=us-east,\xff=value value=1.0 946728000000000000",
+		},
 	}
 
 	for _, test := range tests {
@@ -30,6 +30,10 @@ type NotificationRule interface {
 	GetEndpointID() ID
 	GetLimit() *Limit
 	GenerateFlux(NotificationEndpoint) (string, error)
+	// SetSilences sets the silences muting the statuses of the generated flux.
+	SetSilences(silences []*Silence)
+	// SetAlertAcks sets the alert acknowledgements skipped by the generated flux.
+	SetAlertAcks(acks []*AlertAck)
 	HasTag(key, value string) bool
 }
 
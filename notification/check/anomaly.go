@@ -0,0 +1,447 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+var _ influxdb.Check = (*Anomaly)(nil)
+
+// AnomalyMethod is how the anomaly check compares the latest value of a field
+// with its past values.
+type AnomalyMethod string
+
+// anomaly methods
+const (
+	// AnomalyChange is the percentage of change of the value over the window.
+	AnomalyChange AnomalyMethod = "change"
+	// AnomalyDeviation is the number of standard deviations between the value
+	// and the mean of the values of the window preceding it.
+	AnomalyDeviation AnomalyMethod = "deviation"
+	// AnomalyLastWeek is the percentage of difference between the value and
+	// the value at the same time last week.
+	AnomalyLastWeek AnomalyMethod = "lastWeek"
+)
+
+// Anomaly is the anomaly check. It computes the _anomaly of the latest value
+// of the field queried, with its method, and reaches the levels the absolute
+// _anomaly is greater than or equal to.
+type Anomaly struct {
+	Base
+	Method AnomalyMethod `json:"method"`
+	// Window is the period of the past values of the change and deviation methods.
+	Window *notification.Duration `json:"window,omitempty"`
+	Levels []AnomalyLevel         `json:"levels"`
+}
+
+// AnomalyLevel is a level of an anomaly check.
+type AnomalyLevel struct {
+	Level notification.CheckLevel `json:"level"`
+	// Value is the percentage, or the number of standard deviations, from which the level is reached.
+	Value float64 `json:"value"`
+}
+
+// Type returns the type of the check.
+func (a Anomaly) Type() string {
+	return "anomaly"
+}
+
+// Valid returns error if something is invalid.
+func (a Anomaly) Valid() error {
+	if err := a.Base.Valid(); err != nil {
+		return err
+	}
+	if a.Every == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Anomaly check every is required",
+		}
+	}
+	switch a.Method {
+	case AnomalyChange, AnomalyDeviation:
+		if a.Window == nil || a.Window.TimeDuration() <= a.Every.TimeDuration() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Anomaly check window must be larger than the interval",
+			}
+		}
+	case AnomalyLastWeek:
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid anomaly method %q", a.Method),
+		}
+	}
+	if len(a.Levels) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Anomaly check must have at least one level",
+		}
+	}
+	levels := make(map[notification.CheckLevel]bool)
+	for _, l := range a.Levels {
+		if l.Level < notification.Ok || l.Level > notification.Critical {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Anomaly level is invalid",
+			}
+		}
+		if levels[l.Level] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("Anomaly level %s is duplicated", l.Level),
+			}
+		}
+		levels[l.Level] = true
+		if l.Value <= 0 {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Anomaly level value must be larger than 0",
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateFlux returns a flux script for the anomaly check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (a Anomaly) GenerateFlux() (string, error) {
+	p, err := a.GenerateFluxAST()
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the anomaly check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (a Anomaly) GenerateFluxAST() (*ast.Package, error) {
+	p := parser.ParseSource(a.Query.Text)
+	replaceDurationsWithEvery(p, a.Every)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+	if a.Method != AnomalyLastWeek {
+		// the window preceding the latest value is queried with it.
+		replaceRangeStart(p, flux.Negative(addDurations(a.Window, a.Every)), nil)
+	}
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	fields := getFields(p)
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("expected a single field but got: %s", fields)
+	}
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "influxdata/influxdb/v1")...)
+	if a.Method == AnomalyDeviation {
+		f.Imports = append(f.Imports, flux.ImportDeclaration("math"))
+	}
+	if a.Method == AnomalyLastWeek {
+		lastWeek, err := a.generateLastWeekData()
+		if err != nil {
+			return nil, err
+		}
+		f.Body = append(f.Body, lastWeek)
+	}
+	f.Body = append(f.Body, a.generateFluxASTBody(fields[0])...)
+
+	return p, nil
+}
+
+// generateLastWeekData defines last_week, the data queried a week before the
+// data, shifted to the time of the data.
+func (a Anomaly) generateLastWeekData() (ast.Statement, error) {
+	week := flux.Duration(1, "w")
+
+	p := parser.ParseSource(a.Query.Text)
+	replaceDurationsWithEvery(p, a.Every)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+	replaceRangeStart(p, flux.Negative(addDurations((*notification.Duration)(week), a.Every)), flux.Negative(week))
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	f := p.Files[0]
+	if err := assignPipelineToData(f); err != nil {
+		return nil, err
+	}
+	data := f.Body[0].(*ast.VariableAssignment).Init
+
+	return flux.DefineVariable("last_week", flux.Pipe(
+		data,
+		flux.Call(flux.Identifier("timeShift"), flux.Object(flux.Property("duration", week))),
+	)), nil
+}
+
+// replaceRangeStart replaces the start of the range of the query, and sets its
+// stop if it is provided.
+func replaceRangeStart(pkg *ast.Package, start, stop ast.Expression) {
+	ast.Visit(pkg, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok {
+			if id, ok := call.Callee.(*ast.Identifier); ok && id.Name == "range" {
+				for _, args := range call.Arguments {
+					if obj, ok := args.(*ast.ObjectExpression); ok {
+						for _, prop := range obj.Properties {
+							if prop.Key.Key() == "start" {
+								prop.Value = start
+							}
+						}
+						if stop != nil {
+							obj.Properties = append(obj.Properties, flux.Property("stop", stop))
+						}
+					}
+				}
+			}
+		}
+	})
+}
+
+func addDurations(ds ...*notification.Duration) *ast.DurationLiteral {
+	sum := &ast.DurationLiteral{}
+	for _, d := range ds {
+		sum.Values = append(sum.Values, d.Values...)
+	}
+	return sum
+}
+
+func (a Anomaly) generateFluxASTBody(field string) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, a.generateTaskOption())
+	statements = append(statements, a.generateFluxASTCheckDefinition("anomaly"))
+	statements = append(statements, a.generateFluxASTLevelFunctions()...)
+	statements = append(statements, a.generateFluxASTMessageFunction())
+	statements = append(statements, a.generateFluxASTChecksFunction(field))
+	return statements
+}
+
+func (a Anomaly) generateFluxASTLevelFunctions() []ast.Statement {
+	statements := make([]ast.Statement, len(a.Levels))
+	for i, l := range a.Levels {
+		anomaly := flux.Member("r", "_anomaly")
+		fn := flux.Function(flux.FunctionParams("r"), flux.Or(
+			flux.GreaterThanEqual(anomaly, flux.Float(l.Value)),
+			flux.LessThanEqual(anomaly, flux.Float(-l.Value)),
+		))
+		statements[i] = flux.DefineVariable(strings.ToLower(l.Level.String()), fn)
+	}
+	return statements
+}
+
+func (a Anomaly) generateFluxASTChecksFunction(field string) ast.Statement {
+	var (
+		data  ast.Expression
+		calls []*ast.CallExpression
+		value = flux.Member("r", field)
+	)
+	switch a.Method {
+	case AnomalyChange:
+		data = flux.Pipe(
+			flux.Identifier("data"),
+			flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
+		)
+		calls = []*ast.CallExpression{
+			reduce(
+				[]*ast.Property{
+					flux.Property("_count", flux.Integer(0)),
+					flux.Property("_first", flux.Float(0)),
+					flux.Property("_last", flux.Float(0)),
+				},
+				flux.Property("_count", flux.Add(accumulator("_count"), flux.Integer(1))),
+				flux.Property("_first", flux.If(
+					flux.Equal(accumulator("_count"), flux.Integer(0)),
+					value,
+					accumulator("_first"),
+				)),
+				flux.Property("_last", value),
+			),
+			filter(flux.GreaterThan(flux.Member("r", "_count"), flux.Integer(1))),
+			mapAnomaly(field, percentChange(flux.Member("r", "_first"))),
+			dropColumns("_count", "_first", "_last"),
+		}
+	case AnomalyDeviation:
+		data = flux.Pipe(
+			flux.Identifier("data"),
+			flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
+		)
+		// the mean and standard deviation are those of the values preceding the latest value.
+		n := flux.Call(flux.Identifier("float"), flux.Object(
+			flux.Property("v", flux.Subtract(flux.Member("r", "_count"), flux.Integer(1))),
+		))
+		last := flux.Member("r", "_last")
+		mean := flux.Member("r", "_mean")
+		variance := flux.Divide(
+			flux.Subtract(
+				flux.Subtract(flux.Member("r", "_sum_sq"), flux.Multiply(last, last)),
+				flux.Multiply(flux.Multiply(n, mean), mean),
+			),
+			flux.Call(flux.Identifier("float"), flux.Object(
+				flux.Property("v", flux.Subtract(flux.Member("r", "_count"), flux.Integer(2))),
+			)),
+		)
+		stddev := flux.Call(flux.Member("math", "sqrt"), flux.Object(flux.Property("x", variance)))
+		calls = []*ast.CallExpression{
+			reduce(
+				[]*ast.Property{
+					flux.Property("_count", flux.Integer(0)),
+					flux.Property("_sum", flux.Float(0)),
+					flux.Property("_sum_sq", flux.Float(0)),
+					flux.Property("_last", flux.Float(0)),
+				},
+				flux.Property("_count", flux.Add(accumulator("_count"), flux.Integer(1))),
+				flux.Property("_sum", flux.Add(accumulator("_sum"), value)),
+				flux.Property("_sum_sq", flux.Add(accumulator("_sum_sq"), flux.Multiply(value, value))),
+				flux.Property("_last", value),
+			),
+			filter(flux.GreaterThan(flux.Member("r", "_count"), flux.Integer(2))),
+			mapWith(flux.Property("_mean", flux.Divide(flux.Subtract(flux.Member("r", "_sum"), last), n))),
+			mapAnomaly(field, flux.Divide(flux.Subtract(last, mean), stddev)),
+			dropColumns("_count", "_sum", "_sum_sq", "_last", "_mean"),
+		}
+	case AnomalyLastWeek:
+		markLastWeek := func(table string, lastWeek bool) ast.Expression {
+			return flux.Pipe(
+				flux.Identifier(table),
+				flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
+				mapWith(flux.Property("_last_week", flux.Bool(lastWeek))),
+			)
+		}
+		data = flux.Call(flux.Identifier("union"), flux.Object(
+			flux.Property("tables", flux.Array(markLastWeek("last_week", true), markLastWeek("data", false))),
+		))
+		lastWeek := flux.Member("r", "_last_week")
+		calls = []*ast.CallExpression{
+			flux.Call(flux.Identifier("sort"), flux.Object(
+				flux.Property("columns", flux.Array(flux.String("_time"))),
+			)),
+			reduce(
+				[]*ast.Property{
+					flux.Property("_count", flux.Integer(0)),
+					flux.Property("_last", flux.Float(0)),
+					flux.Property("_last_week_count", flux.Integer(0)),
+					flux.Property("_last_week_value", flux.Float(0)),
+				},
+				flux.Property("_count", flux.If(lastWeek,
+					accumulator("_count"),
+					flux.Add(accumulator("_count"), flux.Integer(1)),
+				)),
+				flux.Property("_last", flux.If(lastWeek, accumulator("_last"), value)),
+				flux.Property("_last_week_count", flux.If(lastWeek,
+					flux.Add(accumulator("_last_week_count"), flux.Integer(1)),
+					accumulator("_last_week_count"),
+				)),
+				flux.Property("_last_week_value", flux.If(lastWeek, value, accumulator("_last_week_value"))),
+			),
+			filter(flux.And(
+				flux.GreaterThan(flux.Member("r", "_count"), flux.Integer(0)),
+				flux.GreaterThan(flux.Member("r", "_last_week_count"), flux.Integer(0)),
+			)),
+			mapAnomaly(field, percentChange(flux.Member("r", "_last_week_value"))),
+			dropColumns("_count", "_last", "_last_week_count", "_last_week_value"),
+		}
+	}
+
+	calls = append(calls, a.generateFluxASTChecksCall())
+	return flux.ExpressionStatement(flux.Pipe(data, calls...))
+}
+
+func (a Anomaly) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	for _, l := range a.Levels {
+		lvl := strings.ToLower(l.Level.String())
+		objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+	}
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+func accumulator(column string) *ast.MemberExpression {
+	return flux.Member("accumulator", column)
+}
+
+func reduce(identity []*ast.Property, fn ...*ast.Property) *ast.CallExpression {
+	return flux.Call(flux.Identifier("reduce"), flux.Object(
+		flux.Property("identity", flux.Object(identity...)),
+		flux.Property("fn", flux.Function(flux.FunctionParams("r", "accumulator"), flux.Object(fn...))),
+	))
+}
+
+func filter(fn ast.Expression) *ast.CallExpression {
+	return flux.Call(flux.Identifier("filter"), flux.Object(
+		flux.Property("fn", flux.Function(flux.FunctionParams("r"), fn)),
+	))
+}
+
+func mapWith(ps ...*ast.Property) *ast.CallExpression {
+	return flux.Call(flux.Identifier("map"), flux.Object(
+		flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.ObjectWith("r", ps...))),
+	))
+}
+
+// mapAnomaly sets the _anomaly of the latest value of the field, as of the
+// time the check runs.
+func mapAnomaly(field string, anomaly ast.Expression) *ast.CallExpression {
+	return mapWith(
+		flux.Property(field, flux.Member("r", "_last")),
+		flux.Property("_anomaly", anomaly),
+		flux.Property("_time", flux.Member("r", "_stop")),
+	)
+}
+
+// percentChange is the percentage of change of the latest value from the value.
+func percentChange(from ast.Expression) ast.Expression {
+	return flux.Multiply(
+		flux.Divide(flux.Subtract(flux.Member("r", "_last"), from), from),
+		flux.Float(100),
+	)
+}
+
+func dropColumns(columns ...string) *ast.CallExpression {
+	cols := make([]ast.Expression, len(columns))
+	for i, c := range columns {
+		cols[i] = flux.String(c)
+	}
+	return flux.Call(flux.Identifier("drop"), flux.Object(flux.Property("columns", flux.Array(cols...))))
+}
+
+type anomalyAlias Anomaly
+
+// MarshalJSON implement json.Marshaler interface.
+func (a Anomaly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			anomalyAlias
+			Type string `json:"type"`
+		}{
+			anomalyAlias: anomalyAlias(a),
+			Type:         a.Type(),
+		})
+}
@@ -0,0 +1,276 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestAnomaly_GenerateFlux(t *testing.T) {
+	type args struct {
+		anomaly check.Anomaly
+	}
+	type wants struct {
+		script string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "percentage of change over the window",
+			args: args{
+				anomaly: check.Anomaly{
+					Base: check.Base{
+						ID:   10,
+						Name: "moo",
+						Tags: []influxdb.Tag{
+							{Key: "aaa", Value: "vaaa"},
+							{Key: "bbb", Value: "vbbb"},
+						},
+						Every:                 mustDuration("5m"),
+						StatusMessageTemplate: "whoa! {r.usage_user}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Method: check.AnomalyChange,
+					Window: mustDuration("1h"),
+					Levels: []check.AnomalyLevel{
+						{Level: notification.Critical, Value: 50},
+						{Level: notification.Warn, Value: 20},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -1h5m)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 5m, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 5m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa", bbb: "vbbb"},
+}
+crit = (r) =>
+	(r._anomaly >= 50.0 or r._anomaly <= -50.0)
+warn = (r) =>
+	(r._anomaly >= 20.0 or r._anomaly <= -20.0)
+messageFn = (r) =>
+	("whoa! {r.usage_user}")
+
+data
+	|> v1.fieldsAsCols()
+	|> reduce(identity: {_count: 0, _first: 0.0, _last: 0.0}, fn: (r, accumulator) =>
+		({_count: accumulator._count + 1, _first: if accumulator._count == 0 then r.usage_user else accumulator._first, _last: r.usage_user}))
+	|> filter(fn: (r) =>
+		(r._count > 1))
+	|> map(fn: (r) =>
+		({r with usage_user: r._last, _anomaly: (r._last - r._first) / r._first * 100.0, _time: r._stop}))
+	|> drop(columns: ["_count", "_first", "_last"])
+	|> monitor.check(
+		data: check,
+		messageFn: messageFn,
+		crit: crit,
+		warn: warn,
+	)`,
+			},
+		},
+		{
+			name: "standard deviations from the window",
+			args: args{
+				anomaly: check.Anomaly{
+					Base: check.Base{
+						ID:   10,
+						Name: "moo",
+						Tags: []influxdb.Tag{
+							{Key: "aaa", Value: "vaaa"},
+							{Key: "bbb", Value: "vbbb"},
+						},
+						Every:                 mustDuration("5m"),
+						StatusMessageTemplate: "whoa! {r.usage_user}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Method: check.AnomalyDeviation,
+					Window: mustDuration("1h"),
+					Levels: []check.AnomalyLevel{
+						{Level: notification.Critical, Value: 3},
+						{Level: notification.Info, Value: 2},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/v1"
+import "math"
+
+data = from(bucket: "foo")
+	|> range(start: -1h5m)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 5m, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 5m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa", bbb: "vbbb"},
+}
+crit = (r) =>
+	(r._anomaly >= 3.0 or r._anomaly <= -3.0)
+info = (r) =>
+	(r._anomaly >= 2.0 or r._anomaly <= -2.0)
+messageFn = (r) =>
+	("whoa! {r.usage_user}")
+
+data
+	|> v1.fieldsAsCols()
+	|> reduce(identity: {
+		_count: 0,
+		_sum: 0.0,
+		_sum_sq: 0.0,
+		_last: 0.0,
+	}, fn: (r, accumulator) =>
+		({
+			_count: accumulator._count + 1,
+			_sum: accumulator._sum + r.usage_user,
+			_sum_sq: accumulator._sum_sq + r.usage_user * r.usage_user,
+			_last: r.usage_user,
+		}))
+	|> filter(fn: (r) =>
+		(r._count > 2))
+	|> map(fn: (r) =>
+		({r with _mean: (r._sum - r._last) / float(v: r._count - 1)}))
+	|> map(fn: (r) =>
+		({r with usage_user: r._last, _anomaly: (r._last - r._mean) / math.sqrt(x: (r._sum_sq - r._last * r._last - float(v: r._count - 1) * r._mean * r._mean) / float(v: r._count - 2)), _time: r._stop}))
+	|> drop(columns: ["_count", "_sum", "_sum_sq", "_last", "_mean"])
+	|> monitor.check(
+		data: check,
+		messageFn: messageFn,
+		crit: crit,
+		info: info,
+	)`,
+			},
+		},
+		{
+			name: "difference from last week",
+			args: args{
+				anomaly: check.Anomaly{
+					Base: check.Base{
+						ID:   10,
+						Name: "moo",
+						Tags: []influxdb.Tag{
+							{Key: "aaa", Value: "vaaa"},
+							{Key: "bbb", Value: "vbbb"},
+						},
+						Every:                 mustDuration("5m"),
+						StatusMessageTemplate: "whoa! {r.usage_user}",
+						Query: influxdb.DashboardQuery{
+							Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+						},
+					},
+					Method: check.AnomalyLastWeek,
+					Levels: []check.AnomalyLevel{
+						{Level: notification.Critical, Value: 50},
+						{Level: notification.Warn, Value: 20},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -5m)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 5m, fn: mean, createEmpty: false)
+last_week = from(bucket: "foo")
+	|> range(start: -1w5m, stop: -1w)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 5m, fn: mean, createEmpty: false)
+	|> timeShift(duration: 1w)
+
+option task = {name: "moo", every: 5m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa", bbb: "vbbb"},
+}
+crit = (r) =>
+	(r._anomaly >= 50.0 or r._anomaly <= -50.0)
+warn = (r) =>
+	(r._anomaly >= 20.0 or r._anomaly <= -20.0)
+messageFn = (r) =>
+	("whoa! {r.usage_user}")
+
+union(tables: [last_week
+	|> v1.fieldsAsCols()
+	|> map(fn: (r) =>
+		({r with _last_week: true})), data
+	|> v1.fieldsAsCols()
+	|> map(fn: (r) =>
+		({r with _last_week: false}))])
+	|> sort(columns: ["_time"])
+	|> reduce(identity: {
+		_count: 0,
+		_last: 0.0,
+		_last_week_count: 0,
+		_last_week_value: 0.0,
+	}, fn: (r, accumulator) =>
+		({
+			_count: if r._last_week then accumulator._count else accumulator._count + 1,
+			_last: if r._last_week then accumulator._last else r.usage_user,
+			_last_week_count: if r._last_week then accumulator._last_week_count + 1 else accumulator._last_week_count,
+			_last_week_value: if r._last_week then r.usage_user else accumulator._last_week_value,
+		}))
+	|> filter(fn: (r) =>
+		(r._count > 0 and r._last_week_count > 0))
+	|> map(fn: (r) =>
+		({r with usage_user: r._last, _anomaly: (r._last - r._last_week_value) / r._last_week_value * 100.0, _time: r._stop}))
+	|> drop(columns: ["_count", "_last", "_last_week_count", "_last_week_value"])
+	|> monitor.check(
+		data: check,
+		messageFn: messageFn,
+		crit: crit,
+		warn: warn,
+	)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.args.anomaly.GenerateFlux()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if exp, got := tt.wants.script, s; exp != got {
+				t.Errorf("expected:\n%v\n\ngot:\n%v\n", exp, got)
+			}
+		})
+	}
+}
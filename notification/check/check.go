@@ -184,6 +184,8 @@ func (b *Base) SetDescription(description string) {
 var typeToCheck = map[string](func() influxdb.Check){
 	"deadman":   func() influxdb.Check { return &Deadman{} },
 	"threshold": func() influxdb.Check { return &Threshold{} },
+	"anomaly":   func() influxdb.Check { return &Anomaly{} },
+	"custom":    func() influxdb.Check { return &Custom{} },
 }
 
 // UnmarshalJSON will convert
@@ -135,6 +135,150 @@ func TestValidCheck(t *testing.T) {
 				Msg:  "range threshold min can't be larger than max",
 			},
 		},
+		{
+			name: "anomaly window not larger than interval",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:                    influxTesting.MustIDBase16(id1),
+					Name:                  "name1",
+					OwnerID:               influxTesting.MustIDBase16(id2),
+					OrgID:                 influxTesting.MustIDBase16(id3),
+					StatusMessageTemplate: "temp1",
+					Every:                 mustDuration("1h"),
+				},
+				Method: check.AnomalyChange,
+				Window: mustDuration("1h"),
+				Levels: []check.AnomalyLevel{
+					{Level: notification.Critical, Value: 50},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Anomaly check window must be larger than the interval",
+			},
+		},
+		{
+			name: "bad anomaly method",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:                    influxTesting.MustIDBase16(id1),
+					Name:                  "name1",
+					OwnerID:               influxTesting.MustIDBase16(id2),
+					OrgID:                 influxTesting.MustIDBase16(id3),
+					StatusMessageTemplate: "temp1",
+					Every:                 mustDuration("1h"),
+				},
+				Method: "median",
+				Levels: []check.AnomalyLevel{
+					{Level: notification.Critical, Value: 50},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `invalid anomaly method "median"`,
+			},
+		},
+		{
+			name: "duplicated anomaly level",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:                    influxTesting.MustIDBase16(id1),
+					Name:                  "name1",
+					OwnerID:               influxTesting.MustIDBase16(id2),
+					OrgID:                 influxTesting.MustIDBase16(id3),
+					StatusMessageTemplate: "temp1",
+					Every:                 mustDuration("1h"),
+				},
+				Method: check.AnomalyLastWeek,
+				Levels: []check.AnomalyLevel{
+					{Level: notification.Critical, Value: 50},
+					{Level: notification.Critical, Value: 20},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Anomaly level CRIT is duplicated",
+			},
+		},
+		{
+			name: "anomaly level value not positive",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:                    influxTesting.MustIDBase16(id1),
+					Name:                  "name1",
+					OwnerID:               influxTesting.MustIDBase16(id2),
+					OrgID:                 influxTesting.MustIDBase16(id3),
+					StatusMessageTemplate: "temp1",
+					Every:                 mustDuration("1h"),
+				},
+				Method: check.AnomalyDeviation,
+				Window: mustDuration("1d"),
+				Levels: []check.AnomalyLevel{
+					{Level: notification.Warn, Value: -2},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Anomaly level value must be larger than 0",
+			},
+		},
+		{
+			name: "custom check without monitor.check",
+			src: &check.Custom{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					Query: influxdb.DashboardQuery{
+						Text: `from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r._value > 90.0)`,
+					},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Custom check query must send its statuses to monitor.check",
+			},
+		},
+		{
+			name: "custom check without messageFn",
+			src: &check.Custom{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					Query: influxdb.DashboardQuery{
+						Text: `import "influxdata/influxdb/monitor"\nfrom(bucket: "foo") |> range(start: -1h) |> monitor.check(data: check, crit: (r) => r._value > 90.0)`,
+					},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "monitor.check of custom check query requires data and messageFn",
+			},
+		},
+		{
+			name: "custom check without levels",
+			src: &check.Custom{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					Query: influxdb.DashboardQuery{
+						Text: `import "influxdata/influxdb/monitor"\nfrom(bucket: "foo") |> range(start: -1h) |> monitor.check(data: check, messageFn: (r) => "cpu")`,
+					},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "monitor.check of custom check query requires at least one of [crit warn info ok]",
+			},
+		},
 	}
 	for _, c := range cases {
 		got := c.src.Valid()
@@ -243,6 +387,72 @@ func TestJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "simple anomaly",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					Query: influxdb.DashboardQuery{
+						BuilderConfig: influxdb.BuilderConfig{
+							Buckets: []string{},
+							Tags: []struct {
+								Key    string   `json:"key"`
+								Values []string `json:"values"`
+							}{},
+							Functions: []struct {
+								Name string `json:"name"`
+							}{},
+						},
+					},
+					Tags: []influxdb.Tag{},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Method: check.AnomalyDeviation,
+				Window: mustDuration("1d"),
+				Levels: []check.AnomalyLevel{
+					{Level: notification.Critical, Value: 3},
+					{Level: notification.Warn, Value: 2.5},
+				},
+			},
+		},
+		{
+			name: "simple custom",
+			src: &check.Custom{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1h"),
+					Query: influxdb.DashboardQuery{
+						Text: `import "influxdata/influxdb/monitor"
+from(bucket: "foo") |> range(start: -1h) |> monitor.check(data: check, messageFn: (r) => "cpu", crit: (r) => r._value > 90.0)`,
+						BuilderConfig: influxdb.BuilderConfig{
+							Buckets: []string{},
+							Tags: []struct {
+								Key    string   `json:"key"`
+								Values []string `json:"values"`
+							}{},
+							Functions: []struct {
+								Name string `json:"name"`
+							}{},
+						},
+					},
+					Tags: []influxdb.Tag{},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		fn := func(t *testing.T) {
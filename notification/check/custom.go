@@ -0,0 +1,206 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+var _ influxdb.Check = (*Custom)(nil)
+
+// statusLevels are the level predicates monitor.check accepts.
+var statusLevels = []string{"crit", "warn", "info", "ok"}
+
+// Custom is the custom check. Its query is a flux script with the user's own
+// status logic, which must end by sending the statuses to monitor.check.
+// The task option and the check definition of the script are generated from the check.
+type Custom struct {
+	Base
+}
+
+// Type returns the type of the check.
+func (c Custom) Type() string {
+	return "custom"
+}
+
+// Valid returns error if something is invalid.
+func (c Custom) Valid() error {
+	if err := c.Base.Valid(); err != nil {
+		return err
+	}
+	if c.Every == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Custom check every is required",
+		}
+	}
+	p := parser.ParseSource(c.Query.Text)
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Custom check query is invalid",
+			Err:  multiError(errs),
+		}
+	}
+	return validStatusSchema(p)
+}
+
+// validStatusSchema returns an error if the statuses of the script are not
+// written by monitor.check, which sets their _level and _message.
+func validStatusSchema(pkg *ast.Package) error {
+	var (
+		imported bool
+		calls    []*ast.CallExpression
+	)
+	for _, f := range pkg.Files {
+		for _, imp := range f.Imports {
+			if imp.Path.Value == "influxdata/influxdb/monitor" && imp.As == nil {
+				imported = true
+			}
+		}
+	}
+	ast.Visit(pkg, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok && isMonitorCheck(call) {
+			calls = append(calls, call)
+		}
+	})
+	if !imported || len(calls) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Custom check query must send its statuses to monitor.check",
+		}
+	}
+
+	for _, call := range calls {
+		var props map[string]bool
+		if len(call.Arguments) == 1 {
+			if obj, ok := call.Arguments[0].(*ast.ObjectExpression); ok {
+				props = make(map[string]bool, len(obj.Properties))
+				for _, prop := range obj.Properties {
+					props[prop.Key.Key()] = true
+				}
+			}
+		}
+		if !props["data"] || !props["messageFn"] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "monitor.check of custom check query requires data and messageFn",
+			}
+		}
+		var levels int
+		for _, lvl := range statusLevels {
+			if props[lvl] {
+				levels++
+			}
+		}
+		if levels == 0 {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("monitor.check of custom check query requires at least one of %v", statusLevels),
+			}
+		}
+	}
+	return nil
+}
+
+func isMonitorCheck(call *ast.CallExpression) bool {
+	m, ok := call.Callee.(*ast.MemberExpression)
+	if !ok {
+		return false
+	}
+	obj, ok := m.Object.(*ast.Identifier)
+	return ok && obj.Name == "monitor" && m.Property.Key() == "check"
+}
+
+// GenerateFlux returns a flux script for the custom check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Custom) GenerateFlux() (string, error) {
+	p, err := c.GenerateFluxAST()
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the custom check provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+// The task option and the check definition of the script are replaced by
+// those of the check, and the check definition is passed as the data of
+// every monitor.check.
+func (c Custom) GenerateFluxAST() (*ast.Package, error) {
+	p := parser.ParseSource(c.Query.Text)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	if err := validStatusSchema(p); err != nil {
+		return nil, err
+	}
+
+	f := p.Files[0]
+	body := []ast.Statement{
+		c.generateTaskOption(),
+		c.generateFluxASTCheckDefinition("custom"),
+	}
+	for _, stmt := range f.Body {
+		if isTaskOption(stmt) || isCheckDefinition(stmt) {
+			continue
+		}
+		body = append(body, stmt)
+	}
+	f.Body = body
+
+	ast.Visit(p, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok && isMonitorCheck(call) {
+			for _, prop := range call.Arguments[0].(*ast.ObjectExpression).Properties {
+				if prop.Key.Key() == "data" {
+					prop.Value = flux.Identifier("check")
+				}
+			}
+		}
+	})
+
+	return p, nil
+}
+
+func isTaskOption(stmt ast.Statement) bool {
+	opt, ok := stmt.(*ast.OptionStatement)
+	if !ok {
+		return false
+	}
+	v, ok := opt.Assignment.(*ast.VariableAssignment)
+	return ok && v.ID.Name == "task"
+}
+
+func isCheckDefinition(stmt ast.Statement) bool {
+	v, ok := stmt.(*ast.VariableAssignment)
+	return ok && v.ID.Name == "check"
+}
+
+type customAlias Custom
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Custom) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			customAlias
+			Type string `json:"type"`
+		}{
+			customAlias: customAlias(c),
+			Type:        c.Type(),
+		})
+}
@@ -0,0 +1,145 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestCustom_GenerateFlux(t *testing.T) {
+	type args struct {
+		custom check.Custom
+	}
+	type wants struct {
+		script string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "join across measurements",
+			args: args{
+				custom: check.Custom{
+					Base: check.Base{
+						ID:   10,
+						Name: "moo",
+						Tags: []influxdb.Tag{
+							{Key: "aaa", Value: "vaaa"},
+							{Key: "bbb", Value: "vbbb"},
+						},
+						Every: mustDuration("1h"),
+						Query: influxdb.DashboardQuery{
+							Text: `import "influxdata/influxdb/monitor"
+
+cpu = from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "cpu" and r._field == "usage_user")
+mem = from(bucket: "foo") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "mem" and r._field == "used_percent")
+
+join(tables: {cpu: cpu, mem: mem}, on: ["_time", "host"])
+	|> monitor.check(
+		data: check,
+		messageFn: (r) => "cpu {r._value_cpu} mem {r._value_mem}",
+		crit: (r) => r._value_cpu > 90.0 and r._value_mem > 90.0,
+	)`,
+						},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+
+option task = {name: "moo", every: 1h}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "custom",
+	tags: {aaa: "vaaa", bbb: "vbbb"},
+}
+cpu = from(bucket: "foo")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu" and r._field == "usage_user"))
+mem = from(bucket: "foo")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._measurement == "mem" and r._field == "used_percent"))
+
+join(tables: {cpu: cpu, mem: mem}, on: ["_time", "host"])
+	|> monitor.check(data: check, messageFn: (r) =>
+		("cpu {r._value_cpu} mem {r._value_mem}"), crit: (r) =>
+		(r._value_cpu > 90.0 and r._value_mem > 90.0))`,
+			},
+		},
+		{
+			name: "replaces task option and check definition",
+			args: args{
+				custom: check.Custom{
+					Base: check.Base{
+						ID:     10,
+						Name:   "moo",
+						Every:  mustDuration("5m"),
+						Offset: mustDuration("10s"),
+						Query: influxdb.DashboardQuery{
+							Text: `import "influxdata/influxdb/monitor"
+
+option task = {name: "other", every: 1m}
+
+check = {_check_id: "0000000000000001", _check_name: "other", _type: "threshold", tags: {}}
+other = {_check_id: "0000000000000001", _check_name: "other", _type: "threshold", tags: {}}
+
+from(bucket: "foo")
+	|> range(start: -5m)
+	|> filter(fn: (r) => r._measurement == "disk")
+	|> monitor.check(data: other, messageFn: (r) => "disk", warn: (r) => r._value > 80.0)`,
+						},
+					},
+				},
+			},
+			wants: wants{
+				script: `package main
+import "influxdata/influxdb/monitor"
+
+option task = {name: "moo", every: 5m, offset: 10s}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "custom",
+	tags: {},
+}
+other = {
+	_check_id: "0000000000000001",
+	_check_name: "other",
+	_type: "threshold",
+	tags: {},
+}
+
+from(bucket: "foo")
+	|> range(start: -5m)
+	|> filter(fn: (r) =>
+		(r._measurement == "disk"))
+	|> monitor.check(data: check, messageFn: (r) =>
+		("disk"), warn: (r) =>
+		(r._value > 80.0))`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.args.custom.GenerateFlux()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if exp, got := tt.wants.script, s; exp != got {
+				t.Errorf("expected:\n%v\n\ngot:\n%v\n", exp, got)
+			}
+		})
+	}
+}
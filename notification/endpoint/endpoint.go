@@ -12,12 +12,20 @@ const (
 	SlackType     = "slack"
 	PagerDutyType = "pagerduty"
 	HTTPType      = "http"
+	SMTPType      = "smtp"
+	TeamsType     = "teams"
+	OpsgenieType  = "opsgenie"
+	TelegramType  = "telegram"
 )
 
 var typeToEndpoint = map[string](func() influxdb.NotificationEndpoint){
 	SlackType:     func() influxdb.NotificationEndpoint { return &Slack{} },
 	PagerDutyType: func() influxdb.NotificationEndpoint { return &PagerDuty{} },
 	HTTPType:      func() influxdb.NotificationEndpoint { return &HTTP{} },
+	SMTPType:      func() influxdb.NotificationEndpoint { return &SMTP{} },
+	TeamsType:     func() influxdb.NotificationEndpoint { return &Teams{} },
+	OpsgenieType:  func() influxdb.NotificationEndpoint { return &Opsgenie{} },
+	TelegramType:  func() influxdb.NotificationEndpoint { return &Telegram{} },
 }
 
 // UnmarshalJSON will convert the bytes to notification endpoint.
@@ -0,0 +1,88 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.NotificationEndpoint = &Opsgenie{}
+
+const opsgenieAPIKeySuffix = "-api-key"
+
+// DefaultOpsgenieURL is the URL of the Opsgenie alert API used if the endpoint has no URL.
+const DefaultOpsgenieURL = "https://api.opsgenie.com/v2/alerts"
+
+// Opsgenie is the notification endpoint config of opsgenie.
+type Opsgenie struct {
+	Base
+	// URL is the URL of the Opsgenie alert API, DefaultOpsgenieURL if not set
+	// example: https://api.eu.opsgenie.com/v2/alerts
+	URL string `json:"url,omitempty"`
+	// APIKey is the key of an API integration of Opsgenie
+	APIKey influxdb.SecretField `json:"apiKey"`
+}
+
+// BackfillSecretKeys fill back fill the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *Opsgenie) BackfillSecretKeys() {
+	if s.APIKey.Key == "" && s.APIKey.Value != nil {
+		s.APIKey.Key = s.idStr() + opsgenieAPIKeySuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s Opsgenie) SecretFields() []influxdb.SecretField {
+	return []influxdb.SecretField{
+		s.APIKey,
+	}
+}
+
+// Valid returns error if some configuration is invalid
+func (s Opsgenie) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if _, err := url.Parse(s.URL); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("opsgenie endpoint URL is invalid: %s", err.Error()),
+		}
+	}
+	if s.APIKey.Key == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "opsgenie API key is invalid",
+		}
+	}
+	return nil
+}
+
+// GetURL returns the URL of the Opsgenie alert API.
+func (s Opsgenie) GetURL() string {
+	if s.URL == "" {
+		return DefaultOpsgenieURL
+	}
+	return s.URL
+}
+
+type opsgenieAlias Opsgenie
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Opsgenie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			opsgenieAlias
+			Type string `json:"type"`
+		}{
+			opsgenieAlias: opsgenieAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s Opsgenie) Type() string {
+	return OpsgenieType
+}
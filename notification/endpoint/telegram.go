@@ -0,0 +1,87 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.NotificationEndpoint = &Telegram{}
+
+const telegramTokenSuffix = "-token"
+
+// DefaultTelegramURL is the URL of the Telegram bot API used if the endpoint has no URL.
+const DefaultTelegramURL = "https://api.telegram.org/bot"
+
+// Telegram is the notification endpoint config of a telegram bot.
+type Telegram struct {
+	Base
+	// URL is the URL of the Telegram bot API the token is appended to, DefaultTelegramURL if not set
+	URL string `json:"url,omitempty"`
+	// Token is the token of the bot
+	Token influxdb.SecretField `json:"token"`
+}
+
+// BackfillSecretKeys fill back fill the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *Telegram) BackfillSecretKeys() {
+	if s.Token.Key == "" && s.Token.Value != nil {
+		s.Token.Key = s.idStr() + telegramTokenSuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s Telegram) SecretFields() []influxdb.SecretField {
+	return []influxdb.SecretField{
+		s.Token,
+	}
+}
+
+// Valid returns error if some configuration is invalid
+func (s Telegram) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if _, err := url.Parse(s.URL); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("telegram endpoint URL is invalid: %s", err.Error()),
+		}
+	}
+	if s.Token.Key == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "telegram bot token is invalid",
+		}
+	}
+	return nil
+}
+
+// GetURL returns the URL of the Telegram bot API.
+func (s Telegram) GetURL() string {
+	if s.URL == "" {
+		return DefaultTelegramURL
+	}
+	return s.URL
+}
+
+type telegramAlias Telegram
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Telegram) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			telegramAlias
+			Type string `json:"type"`
+		}{
+			telegramAlias: telegramAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s Telegram) Type() string {
+	return TelegramType
+}
@@ -0,0 +1,204 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// Opsgenie is the notification rule config of opsgenie.
+type Opsgenie struct {
+	Base
+	MessageTemplate string   `json:"messageTemplate"`
+	Tags            []string `json:"tags,omitempty"`
+}
+
+// GenerateFlux generates a flux script for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	opsgenieEndpoint, ok := e.(*endpoint.Opsgenie)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not an Opsgenie endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(opsgenieEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the opsgenie notification rule.
+func (s *Opsgenie) GenerateFluxAST(e *endpoint.Opsgenie) (*ast.Package, error) {
+	f := flux.File(
+		s.Name,
+		flux.Imports("influxdata/influxdb/monitor", "http", "json", "influxdata/influxdb/secrets", "experimental"),
+		s.generateFluxASTBody(e),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Opsgenie) generateFluxASTBody(e *endpoint.Opsgenie) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTSecrets(e))
+	statements = append(statements, s.generateHeaders())
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateAllStateChanges()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+
+	return statements
+}
+
+func (s *Opsgenie) generateFluxASTSecrets(e *endpoint.Opsgenie) ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.APIKey.Key))))
+
+	return flux.DefineVariable("opsgenie_secret", call)
+}
+
+func (s *Opsgenie) generateHeaders() ast.Statement {
+	props := []*ast.Property{
+		flux.Dictionary("Content-Type", flux.String("application/json")),
+		flux.Dictionary("Authorization", flux.Add(flux.String("GenieKey "), flux.Identifier("opsgenie_secret"))),
+	}
+
+	return flux.DefineVariable("headers", flux.Object(props...))
+}
+
+// generateFluxASTEndpoint generates the endpoint posting the alerts to opsgenie.
+// http.endpoint is not used because opsgenie accepts the alerts with a 202 status.
+func (s *Opsgenie) generateFluxASTEndpoint(e *endpoint.Opsgenie) ast.Statement {
+	post := flux.Call(
+		flux.Member("http", "post"),
+		flux.Object(
+			flux.Property("url", flux.String(e.GetURL())),
+			flux.Property("headers", flux.Member("obj", "headers")),
+			flux.Property("data", flux.Member("obj", "data")),
+		),
+	)
+	sent := flux.Call(
+		flux.Identifier("string"),
+		flux.Object(flux.Property("v", flux.Equal(
+			flux.Integer(2),
+			&ast.BinaryExpression{Operator: ast.DivisionOperator, Left: post, Right: flux.Integer(100)},
+		))),
+	)
+	mapFn := flux.FuncBlock(flux.FunctionParams("r"),
+		flux.DefineVariable("obj", flux.Call(
+			flux.Identifier("mapFn"),
+			flux.Object(flux.Property("r", flux.Identifier("r"))),
+		)),
+		&ast.ReturnStatement{
+			Argument: flux.ObjectWith("r", flux.Property("_sent", sent)),
+		},
+	)
+	tablesFn := flux.Function(
+		[]*ast.Property{{Key: flux.Identifier("tables"), Value: &ast.PipeLiteral{}}},
+		flux.Pipe(
+			flux.Identifier("tables"),
+			flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", mapFn))),
+		),
+	)
+
+	return flux.DefineVariable("opsgenie_endpoint", flux.Function(flux.FunctionParams("mapFn"), tablesFn))
+}
+
+func (s *Opsgenie) generateFluxASTNotifyPipe() ast.Statement {
+	endpointBody := flux.Call(
+		flux.Member("json", "encode"),
+		flux.Object(flux.Property("v", flux.Identifier("body"))),
+	)
+
+	endpointProps := []*ast.Property{
+		flux.Property("headers", flux.Identifier("headers")),
+		flux.Property("data", endpointBody),
+	}
+	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
+		s.generateBody(),
+		&ast.ReturnStatement{
+			Argument: flux.Object(endpointProps...),
+		},
+	)
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("opsgenie_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+// generateBody generates the alert created in opsgenie. The alerts of a check are
+// deduplicated by opsgenie through their alias while they are open.
+func (s *Opsgenie) generateBody() ast.Statement {
+	tags := make([]ast.Expression, 0, len(s.Tags))
+	for _, tag := range s.Tags {
+		tags = append(tags, flux.String(tag))
+	}
+
+	props := []*ast.Property{
+		flux.Property("message", flux.String(s.MessageTemplate)),
+		flux.Property("alias", flux.Add(
+			flux.Add(flux.Member("r", "_notification_rule_id"), flux.String("-")),
+			flux.Member("r", "_check_id"),
+		)),
+		flux.Property("entity", flux.Member("r", "_check_name")),
+		flux.Property("source", flux.String("InfluxDB")),
+		flux.Property("priority", s.generateOpsgeniePriorities()),
+		flux.Property("tags", flux.Array(tags...)),
+	}
+
+	return flux.DefineVariable("body", flux.Object(props...))
+}
+
+func (s *Opsgenie) generateOpsgeniePriorities() ast.Expression {
+	level := flux.Member("r", "_level")
+	return flux.If(
+		flux.Equal(level, flux.String("crit")),
+		flux.String("P1"),
+		flux.If(
+			flux.Equal(level, flux.String("warn")),
+			flux.String("P3"),
+			flux.String("P5"),
+		),
+	)
+}
+
+type opsgenieAlias Opsgenie
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Opsgenie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			opsgenieAlias
+			Type string `json:"type"`
+		}{
+			opsgenieAlias: opsgenieAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s Opsgenie) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.MessageTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "opsgenie msg template is empty",
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s Opsgenie) Type() string {
+	return "opsgenie"
+}
@@ -0,0 +1,96 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestOpsgenie_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+opsgenie_secret = secrets.get(key: "0000000000000002-api-key")
+headers = {"Content-Type": "application/json", "Authorization": "GenieKey " + opsgenie_secret}
+opsgenie_endpoint = (mapFn) =>
+	((tables=<-) =>
+		(tables
+			|> map(fn: (r) => {
+				obj = mapFn(r: r)
+
+				return {r with _sent: string(v: 2 == http.post(url: "http://localhost:7777/v2/alerts", headers: obj.headers, data: obj.data) / 100)}
+			})))
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: opsgenie_endpoint(mapFn: (r) => {
+		body = {
+			message: "${r._message}",
+			alias: r._notification_rule_id + "-" + r._check_id,
+			entity: r._check_name,
+			source: "InfluxDB",
+			priority: if r._level == "crit" then "P1" else if r._level == "warn" then "P3" else "P5",
+			tags: ["influxdb", "cpu"],
+		}
+
+		return {headers: headers, data: json.encode(v: body)}
+	}))`
+
+	s := &rule.Opsgenie{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			TagRules:   []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		MessageTemplate: "${r._message}",
+		Tags:            []string{"influxdb", "cpu"},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.Opsgenie{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL:    "http://localhost:7777/v2/alerts",
+		APIKey: influxdb.SecretField{Key: "0000000000000002-api-key"},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
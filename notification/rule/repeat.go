@@ -0,0 +1,427 @@
+package rule
+
+import (
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// logColumns are the columns of the notification logs that are not part of the
+// statuses they were sent for.
+var logColumns = []string{
+	"_start",
+	"_stop",
+	"_measurement",
+	"_level",
+	"_sent",
+	"_notification_rule_id",
+	"_notification_rule_name",
+	"_notification_endpoint_id",
+	"_notification_endpoint_name",
+}
+
+// notifiesRepeatedly returns whether the series are notified on a schedule
+// rather than only on their state changes.
+func (b Base) notifiesRepeatedly() bool {
+	return b.Renotify != nil || b.EscalateAfter != nil
+}
+
+func (b Base) validRepeat() error {
+	if !b.notifiesRepeatedly() {
+		return nil
+	}
+	if b.Every == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Notification Rule every is required to renotify or escalate",
+		}
+	}
+	if b.Renotify != nil && b.Renotify.TimeDuration() < b.Every.TimeDuration() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "Renotify should not be less than the interval",
+		}
+	}
+	if b.EscalateAfter != nil && b.EscalateAfter.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "EscalateAfter must be larger than 0",
+		}
+	}
+	return nil
+}
+
+// statusesLookback is how far back the statuses are queried. Series notified
+// repeatedly need the statuses of the last escalation delay and of the current
+// and previous runs to know for how long they have matched the status rules.
+func (b *Base) statusesLookback() *ast.DurationLiteral {
+	if !b.notifiesRepeatedly() {
+		return increaseDur((*ast.DurationLiteral)(b.Every))
+	}
+	return durationLiteral(b.escalationDelay() + 2*b.Every.TimeDuration())
+}
+
+func (b *Base) escalationDelay() time.Duration {
+	if b.EscalateAfter == nil {
+		return 0
+	}
+	return b.EscalateAfter.TimeDuration()
+}
+
+// generateRepeatedNotifications generates the statuses notified on a schedule.
+// The statuses of every series are merged regardless of their level so that
+// the time a series has matched the status rules can be tracked with stateDuration,
+// and the last time a series was notified is read from the notification logs.
+// The resulting tables are unioned with the stateChanges if it is not nil.
+func (b *Base) generateRepeatedNotifications(stateChanges *ast.Identifier) []ast.Statement {
+	stmts := []ast.Statement{b.generateSeriesStatuses()}
+	tables := []ast.Expression{}
+	if stateChanges != nil {
+		tables = append(tables, stateChanges)
+	}
+	if b.EscalateAfter != nil {
+		stmts = append(stmts, b.generateEscalations())
+		tables = append(tables, flux.Identifier("escalations"))
+	}
+	if b.Renotify != nil {
+		stmts = append(stmts, b.generateLastNotifications(), b.generateLatestStatuses(), b.generateRenotifications())
+		tables = append(tables, flux.Identifier("renotifications"))
+	}
+
+	var all ast.Expression = tables[0]
+	if len(tables) > 1 {
+		all = flux.Call(
+			flux.Identifier("union"),
+			flux.Object(
+				flux.Property("tables", flux.Array(tables...)),
+			),
+		)
+	}
+
+	return append(stmts, b.generateAllStatuses(all)...)
+}
+
+func (b *Base) generateSeriesStatuses() ast.Statement {
+	pipe := flux.Pipe(
+		flux.Identifier("statuses"),
+		flux.Call(
+			flux.Identifier("drop"),
+			flux.Object(
+				flux.Property("columns", flux.Array(flux.String("_start"), flux.String("_stop"))),
+			),
+		),
+	)
+	pipe = flux.Pipe(pipe, ungroupColumn("_level", "l2")...)
+	pipe = flux.Pipe(pipe, ungroupColumn("_measurement", "m2")...)
+	pipe = flux.Pipe(
+		pipe,
+		flux.Call(
+			flux.Identifier("sort"),
+			flux.Object(
+				flux.Property("columns", flux.Array(flux.String("_time"))),
+			),
+		),
+		flux.Call(
+			flux.Identifier("stateDuration"),
+			flux.Object(
+				flux.Property("fn", flux.Function(flux.FunctionParams("r"), b.generateLevelsMatch())),
+				flux.Property("column", flux.String("_state_duration")),
+			),
+		),
+	)
+
+	return flux.DefineVariable("series_statuses", pipe)
+}
+
+// ungroupColumn removes a column from the group key while keeping its values.
+func ungroupColumn(column, tmp string) []*ast.CallExpression {
+	return []*ast.CallExpression{
+		flux.Call(
+			flux.Identifier("duplicate"),
+			flux.Object(
+				flux.Property("column", flux.String(column)),
+				flux.Property("as", flux.String(tmp)),
+			),
+		),
+		flux.Call(
+			flux.Identifier("drop"),
+			flux.Object(
+				flux.Property("columns", flux.Array(flux.String(column))),
+			),
+		),
+		flux.Call(
+			flux.Identifier("rename"),
+			flux.Object(
+				flux.Property("columns", flux.Object(flux.Dictionary(tmp, flux.String(column)))),
+			),
+		),
+	}
+}
+
+// generateLevelsMatch returns whether the current level of a status matches one of the status rules.
+func (b *Base) generateLevelsMatch() ast.Expression {
+	var match ast.Expression
+	seen := map[notification.CheckLevel]bool{}
+	for _, r := range b.StatusRules {
+		if r.CurrentLevel == notification.Any {
+			return flux.Bool(true)
+		}
+		if seen[r.CurrentLevel] {
+			continue
+		}
+		seen[r.CurrentLevel] = true
+
+		eq := flux.Equal(
+			flux.Member("r", "_level"),
+			flux.String(strings.ToLower(r.CurrentLevel.String())),
+		)
+		if match == nil {
+			match = eq
+			continue
+		}
+		match = flux.Or(match, eq)
+	}
+	if match == nil {
+		return flux.Bool(false)
+	}
+	return match
+}
+
+// generateEscalations generates the statuses at which the series reached the
+// escalation delay during the current run.
+func (b *Base) generateEscalations() ast.Statement {
+	escalated := flux.If(
+		flux.GreaterThanEqual(flux.Member("r", "_state_duration"), flux.Integer(int64(b.escalationDelay()/time.Second))),
+		flux.Integer(1),
+		flux.Integer(0),
+	)
+
+	pipe := flux.Pipe(
+		flux.Identifier("series_statuses"),
+		flux.Call(
+			flux.Identifier("map"),
+			flux.Object(
+				flux.Property("fn", flux.Function(
+					flux.FunctionParams("r"),
+					flux.ObjectWith("r", flux.Property("_escalated", escalated)),
+				)),
+			),
+		),
+		flux.Call(
+			flux.Identifier("difference"),
+			flux.Object(
+				flux.Property("columns", flux.Array(flux.String("_escalated"))),
+			),
+		),
+		flux.Call(
+			flux.Identifier("filter"),
+			flux.Object(
+				flux.Property("fn", flux.Function(
+					flux.FunctionParams("r"),
+					flux.And(
+						flux.GreaterThan(flux.Member("r", "_escalated"), flux.Integer(0)),
+						b.generateInCurrentRun(),
+					),
+				)),
+			),
+		),
+		dropColumns("_escalated", "_state_duration"),
+		regroupStatuses(),
+	)
+
+	return flux.DefineVariable("escalations", pipe)
+}
+
+// generateLastNotifications generates the last notification of every series
+// sent by the rule within the renotify interval.
+func (b *Base) generateLastNotifications() ast.Statement {
+	sent := flux.And(
+		flux.Equal(flux.Member("r", "_notification_rule_id"), flux.String(b.ID.String())),
+		flux.Equal(flux.Member("r", "_sent"), flux.String("true")),
+	)
+
+	pipe := flux.Pipe(
+		flux.Call(
+			flux.Member("monitor", "logs"),
+			flux.Object(
+				flux.Property("start", flux.Negative(increaseDur((*ast.DurationLiteral)(b.Renotify)))),
+				flux.Property("fn", flux.Function(flux.FunctionParams("r"), sent)),
+			),
+		),
+		dropColumns(append(logColumns, "_status_timestamp")...),
+		flux.Call(
+			flux.Identifier("last"),
+			flux.Object(
+				flux.Property("column", flux.String("_time")),
+			),
+		),
+		flux.Call(
+			flux.Identifier("filter"),
+			flux.Object(
+				flux.Property("fn", flux.Function(
+					flux.FunctionParams("r"),
+					flux.GreaterThan(
+						flux.Member("r", "_time"),
+						flux.Call(
+							flux.Member("experimental", "subDuration"),
+							flux.Object(
+								flux.Property("from", flux.Call(flux.Identifier("now"), flux.Object())),
+								flux.Property("d", (*ast.DurationLiteral)(b.Renotify)),
+							),
+						),
+					),
+				)),
+			),
+		),
+		setNotified(1),
+	)
+
+	return flux.DefineVariable("last_notifications", pipe)
+}
+
+// generateLatestStatuses generates the latest status of the series that have
+// matched the status rules since before the current run, and past the escalation delay.
+func (b *Base) generateLatestStatuses() ast.Statement {
+	after := b.escalationDelay() + b.Every.TimeDuration()
+
+	pipe := flux.Pipe(
+		flux.Identifier("series_statuses"),
+		flux.Call(
+			flux.Identifier("last"),
+			flux.Object(
+				flux.Property("column", flux.String("_time")),
+			),
+		),
+		flux.Call(
+			flux.Identifier("filter"),
+			flux.Object(
+				flux.Property("fn", flux.Function(
+					flux.FunctionParams("r"),
+					flux.GreaterThanEqual(flux.Member("r", "_state_duration"), flux.Integer(int64(after/time.Second))),
+				)),
+			),
+		),
+		setNotified(0),
+	)
+
+	return flux.DefineVariable("latest_statuses", pipe)
+}
+
+// generateRenotifications generates the latest statuses of the series that
+// were not notified within the renotify interval. The latest status of a series
+// is sorted after its last notification, so that it is the only one to have
+// a cumulative sum of _notified of 0 if the series is due.
+func (b *Base) generateRenotifications() ast.Statement {
+	pipe := flux.Pipe(
+		flux.Call(
+			flux.Identifier("union"),
+			flux.Object(
+				flux.Property("tables", flux.Array(
+					flux.Identifier("last_notifications"),
+					flux.Identifier("latest_statuses"),
+				)),
+			),
+		),
+		flux.Call(
+			flux.Identifier("sort"),
+			flux.Object(
+				flux.Property("columns", flux.Array(flux.String("_notified"))),
+				flux.Property("desc", flux.Bool(true)),
+			),
+		),
+		flux.Call(
+			flux.Identifier("cumulativeSum"),
+			flux.Object(
+				flux.Property("columns", flux.Array(flux.String("_notified"))),
+			),
+		),
+		flux.Call(
+			flux.Identifier("filter"),
+			flux.Object(
+				flux.Property("fn", flux.Function(
+					flux.FunctionParams("r"),
+					flux.Equal(flux.Member("r", "_notified"), flux.Integer(0)),
+				)),
+			),
+		),
+		dropColumns("_notified", "_state_duration"),
+		regroupStatuses(),
+	)
+
+	return flux.DefineVariable("renotifications", pipe)
+}
+
+func setNotified(n int64) *ast.CallExpression {
+	return flux.Call(
+		flux.Identifier("map"),
+		flux.Object(
+			flux.Property("fn", flux.Function(
+				flux.FunctionParams("r"),
+				flux.ObjectWith("r", flux.Property("_notified", flux.Integer(n))),
+			)),
+		),
+	)
+}
+
+func (b *Base) generateInCurrentRun() ast.Expression {
+	return flux.GreaterThan(
+		flux.Member("r", "_time"),
+		flux.Call(
+			flux.Member("experimental", "subDuration"),
+			flux.Object(
+				flux.Property("from", flux.Call(flux.Identifier("now"), flux.Object())),
+				flux.Property("d", (*ast.DurationLiteral)(b.Every)),
+			),
+		),
+	)
+}
+
+func dropColumns(columns ...string) *ast.CallExpression {
+	cols := make([]ast.Expression, 0, len(columns))
+	for _, c := range columns {
+		cols = append(cols, flux.String(c))
+	}
+	return flux.Call(
+		flux.Identifier("drop"),
+		flux.Object(
+			flux.Property("columns", flux.Array(cols...)),
+		),
+	)
+}
+
+// regroupStatuses restores the group key of the statuses written by the checks
+// so that the notifications are logged with it.
+func regroupStatuses() *ast.CallExpression {
+	return flux.Call(
+		flux.Member("experimental", "group"),
+		flux.Object(
+			flux.Property("mode", flux.String("extend")),
+			flux.Property("columns", flux.Array(flux.String("_level"), flux.String("_measurement"))),
+		),
+	)
+}
+
+// durationLiteral converts a time.Duration to a duration literal in hours,
+// minutes and seconds.
+func durationLiteral(d time.Duration) *ast.DurationLiteral {
+	units := []struct {
+		unit string
+		dur  time.Duration
+	}{
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+	lit := &ast.DurationLiteral{}
+	for _, u := range units {
+		if m := d / u.dur; m > 0 {
+			lit.Values = append(lit.Values, ast.Duration{Magnitude: int64(m), Unit: u.unit})
+			d -= m * u.dur
+		}
+	}
+	return lit
+}
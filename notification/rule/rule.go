@@ -16,6 +16,10 @@ var typeToRule = map[string](func() influxdb.NotificationRule){
 	"slack":     func() influxdb.NotificationRule { return &Slack{} },
 	"pagerduty": func() influxdb.NotificationRule { return &PagerDuty{} },
 	"http":      func() influxdb.NotificationRule { return &HTTP{} },
+	"smtp":      func() influxdb.NotificationRule { return &SMTP{} },
+	"teams":     func() influxdb.NotificationRule { return &Teams{} },
+	"opsgenie":  func() influxdb.NotificationRule { return &Opsgenie{} },
+	"telegram":  func() influxdb.NotificationRule { return &Telegram{} },
 }
 
 // UnmarshalJSON will convert
@@ -57,8 +61,21 @@ type Base struct {
 	RunbookLink string                    `json:"runbookLink"`
 	TagRules    []notification.TagRule    `json:"tagRules,omitempty"`
 	StatusRules []notification.StatusRule `json:"statusRules,omitempty"`
+	// Renotify is the interval at which a series is notified again while its
+	// latest status still matches the status rules.
+	Renotify *notification.Duration `json:"renotify,omitempty"`
+	// EscalateAfter delays the notification of a series until its statuses have
+	// matched the status rules for that long. Escalation steps are rules on
+	// different endpoints with increasing delays.
+	EscalateAfter *notification.Duration `json:"escalateAfter,omitempty"`
 	*influxdb.Limit
 	influxdb.CRUDLog
+	// Silences are the silences muting the statuses of the generated flux.
+	// They are stored on their own and set before the flux is generated.
+	Silences []*influxdb.Silence `json:"-"`
+	// AlertAcks are the acknowledged alerts whose statuses the generated flux skips.
+	// They are stored on their own and set before the flux is generated.
+	AlertAcks []*influxdb.AlertAck `json:"-"`
 }
 
 func (b Base) valid() error {
@@ -98,6 +115,9 @@ func (b Base) valid() error {
 			Msg:  "Offset should not be equal or greater than the interval",
 		}
 	}
+	if err := b.validRepeat(); err != nil {
+		return err
+	}
 	for _, tagRule := range b.TagRules {
 		if err := tagRule.Valid(); err != nil {
 			return err
@@ -124,6 +144,11 @@ func (b *Base) generateFluxASTNotificationDefinition(e influxdb.NotificationEndp
 }
 
 func (b *Base) generateAllStateChanges() []ast.Statement {
+	if b.EscalateAfter != nil {
+		// escalated rules are not notified of the state changes as they happen.
+		return b.generateRepeatedNotifications(nil)
+	}
+
 	stmts := []ast.Statement{}
 	tables := []ast.Expression{}
 	for _, r := range b.StatusRules {
@@ -181,9 +206,12 @@ func (b *Base) generateAllStateChanges() []ast.Statement {
 		)
 	}
 
-	stmts = append(stmts, flux.DefineVariable("all_statuses", pipe))
+	if b.Renotify != nil {
+		stmts = append(stmts, flux.DefineVariable("state_changes", pipe))
+		return append(stmts, b.generateRepeatedNotifications(flux.Identifier("state_changes"))...)
+	}
 
-	return stmts
+	return append(stmts, b.generateAllStatuses(pipe)...)
 }
 
 func (b *Base) generateStateChanges(r notification.StatusRule) (ast.Statement, *ast.Identifier) {
@@ -288,8 +316,7 @@ func (b *Base) generateTaskOption() ast.Statement {
 func (b *Base) generateFluxASTStatuses() ast.Statement {
 	props := []*ast.Property{}
 
-	dur := (*ast.DurationLiteral)(b.Every)
-	props = append(props, flux.Property("start", flux.Negative(increaseDur(dur))))
+	props = append(props, flux.Property("start", flux.Negative(b.statusesLookback())))
 
 	if len(b.TagRules) > 0 {
 		r := b.TagRules[0]
@@ -120,6 +120,41 @@ func TestValidRule(t *testing.T) {
 				Msg:  "Offset should not be equal or greater than the interval",
 			},
 		},
+		{
+			name: "renotify less than interval",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					Name:       "name1",
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Every:      mustDuration("5m"),
+					Renotify:   mustDuration("1m"),
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Renotify should not be less than the interval",
+			},
+		},
+		{
+			name: "escalation without interval",
+			src: &rule.Slack{
+				Base: rule.Base{
+					ID:            influxTesting.MustIDBase16(id1),
+					Name:          "name1",
+					OwnerID:       influxTesting.MustIDBase16(id2),
+					OrgID:         influxTesting.MustIDBase16(id3),
+					EndpointID:    1,
+					EscalateAfter: mustDuration("15m"),
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "Notification Rule every is required to renotify or escalate",
+			},
+		},
 		{
 			name: "empty slack message",
 			src: &rule.Slack{
@@ -141,6 +176,31 @@ func TestValidRule(t *testing.T) {
 				Msg:  "pagerduty invalid message template",
 			},
 		},
+		{
+			name: "empty smtp to",
+			src: &rule.SMTP{
+				Base:            goodBase,
+				SubjectTemplate: "subject1",
+				BodyTemplate:    "msg1",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp to addresses are empty",
+			},
+		},
+		{
+			name: "bad telegram parse mode",
+			src: &rule.Telegram{
+				Base:            goodBase,
+				Channel:         "channel1",
+				MessageTemplate: "msg1",
+				ParseMode:       "markdown",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `invalid telegram parse mode "markdown"`,
+			},
+		},
 		{
 			name: "bad tag rule",
 			src: &rule.PagerDuty{
@@ -252,7 +312,7 @@ func TestJSON(t *testing.T) {
 		},
 		{
 			name: "simple smtp",
-			src: &rule.PagerDuty{
+			src: &rule.SMTP{
 				Base: rule.Base{
 					ID:          influxTesting.MustIDBase16(id1),
 					Name:        "name1",
@@ -282,7 +342,9 @@ func TestJSON(t *testing.T) {
 						UpdatedAt: timeGen2.Now(),
 					},
 				},
-				MessageTemplate: "msg1",
+				To:              []string{"oncall@example.com"},
+				SubjectTemplate: "subject1",
+				BodyTemplate:    "msg1",
 			},
 		},
 		{
@@ -0,0 +1,158 @@
+package rule
+
+import (
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// SetSilences sets the silences that apply to the rule.
+func (b *Base) SetSilences(silences []*influxdb.Silence) {
+	b.Silences = nil
+	for _, s := range silences {
+		if s.AppliesTo(b.ID) {
+			b.Silences = append(b.Silences, s)
+		}
+	}
+}
+
+// SetAlertAcks sets the alert acknowledgements of the organization of the rule.
+func (b *Base) SetAlertAcks(acks []*influxdb.AlertAck) {
+	b.AlertAcks = acks
+}
+
+// generateAllStatuses defines all_statuses, the statuses notified by the rule.
+// If the rule is silenced, the silenced statuses are removed from them and
+// logged as not sent. The statuses of the acknowledged alerts are removed from them.
+func (b *Base) generateAllStatuses(statuses ast.Expression) []ast.Statement {
+	var statements []ast.Statement
+	if len(b.Silences) != 0 {
+		statements, statuses = b.generateSilencedStatuses(statuses)
+	}
+	if len(b.AlertAcks) != 0 {
+		acknowledged := flux.Call(flux.Identifier("acknowledged"), flux.Object(flux.Property("r", flux.Identifier("r"))))
+		statements = append(statements,
+			flux.DefineVariable("acknowledged", flux.Function(flux.FunctionParams("r"), b.generateAlertAcksMatch())),
+		)
+		statuses = flux.Pipe(
+			statuses,
+			flux.Call(
+				flux.Identifier("filter"),
+				flux.Object(
+					flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.Not(acknowledged))),
+				),
+			),
+		)
+	}
+	return append(statements, flux.DefineVariable("all_statuses", statuses))
+}
+
+// generateSilencedStatuses logs the silenced statuses as not sent and returns
+// the statuses that are not silenced.
+func (b *Base) generateSilencedStatuses(statuses ast.Expression) ([]ast.Statement, ast.Expression) {
+	silenced := flux.Call(flux.Identifier("silenced"), flux.Object(flux.Property("r", flux.Identifier("r"))))
+
+	// silenced statuses go through monitor.notify so that they are logged.
+	silencedEndpoint := flux.Function(
+		[]*ast.Property{{Key: flux.Identifier("tables"), Value: &ast.PipeLiteral{}}},
+		flux.Pipe(
+			flux.Identifier("tables"),
+			flux.Call(
+				flux.Identifier("map"),
+				flux.Object(
+					flux.Property("fn", flux.Function(
+						flux.FunctionParams("r"),
+						flux.ObjectWith("r",
+							flux.Property("_sent", flux.String("false")),
+							flux.Property("_silenced", flux.String("true")),
+						),
+					)),
+				),
+			),
+		),
+	)
+
+	return []ast.Statement{
+		flux.DefineVariable("matched_statuses", statuses),
+		flux.DefineVariable("silenced", flux.Function(flux.FunctionParams("r"), b.generateSilencesMatch())),
+		flux.ExpressionStatement(flux.Pipe(
+			flux.Identifier("matched_statuses"),
+			flux.Call(
+				flux.Identifier("filter"),
+				flux.Object(
+					flux.Property("fn", flux.Function(flux.FunctionParams("r"), silenced)),
+				),
+			),
+			flux.Call(
+				flux.Member("monitor", "notify"),
+				flux.Object(
+					flux.Property("data", flux.Identifier("notification")),
+					flux.Property("endpoint", silencedEndpoint),
+				),
+			),
+		)),
+	}, flux.Pipe(
+		flux.Identifier("matched_statuses"),
+		flux.Call(
+			flux.Identifier("filter"),
+			flux.Object(
+				flux.Property("fn", flux.Function(flux.FunctionParams("r"), flux.Not(silenced))),
+			),
+		),
+	)
+}
+
+// generateSilencesMatch returns whether a status is matched by one of the silences of the rule.
+func (b *Base) generateSilencesMatch() ast.Expression {
+	var match ast.Expression
+	for _, s := range b.Silences {
+		expr := generateSilenceMatch(s)
+		if match == nil {
+			match = expr
+			continue
+		}
+		match = flux.Or(match, expr)
+	}
+	return match
+}
+
+func generateSilenceMatch(s *influxdb.Silence) ast.Expression {
+	time := flux.Member("r", "_time")
+	match := flux.And(
+		flux.GreaterThanEqual(time, &ast.DateTimeLiteral{Value: s.StartTime.UTC()}),
+		flux.LessThan(time, &ast.DateTimeLiteral{Value: s.EndTime.UTC()}),
+	)
+	if s.CheckID.Valid() {
+		match = flux.And(match, flux.Equal(flux.Member("r", "_check_id"), flux.String(s.CheckID.String())))
+	}
+	for _, tr := range s.TagRules {
+		match = flux.And(match, notification.TagRule(tr).GenerateFluxAST())
+	}
+	return match
+}
+
+// generateAlertAcksMatch returns whether a status is one of an acknowledged alert.
+func (b *Base) generateAlertAcksMatch() ast.Expression {
+	var match ast.Expression
+	for _, a := range b.AlertAcks {
+		expr := generateAlertAckMatch(a)
+		if match == nil {
+			match = expr
+			continue
+		}
+		match = flux.Or(match, expr)
+	}
+	return match
+}
+
+func generateAlertAckMatch(a *influxdb.AlertAck) ast.Expression {
+	var match ast.Expression = flux.And(
+		flux.Equal(flux.Member("r", "_check_id"), flux.String(a.CheckID.String())),
+		flux.Equal(flux.Member("r", "_level"), flux.String(a.Level)),
+	)
+	for _, t := range a.Tags {
+		match = flux.And(match, flux.Equal(flux.Member("r", t.Key), flux.String(t.Value)))
+	}
+	return match
+}
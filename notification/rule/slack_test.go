@@ -2,6 +2,7 @@ package rule_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/influxdb"
@@ -339,6 +340,409 @@ all_statuses
 				},
 			},
 		},
+		{
+			name: "with renotify",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h, fn: (r) =>
+	(r.foo == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+info_to_warn = statuses
+	|> monitor.stateChanges(fromLevel: "info", toLevel: "warn")
+state_changes = union(tables: [crit, info_to_warn])
+	|> sort(columns: ["_time"])
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+series_statuses = statuses
+	|> drop(columns: ["_start", "_stop"])
+	|> duplicate(column: "_level", as: "l2")
+	|> drop(columns: ["_level"])
+	|> rename(columns: {"l2": "_level"})
+	|> duplicate(column: "_measurement", as: "m2")
+	|> drop(columns: ["_measurement"])
+	|> rename(columns: {"m2": "_measurement"})
+	|> sort(columns: ["_time"])
+	|> stateDuration(fn: (r) =>
+		(r._level == "crit" or r._level == "warn"), column: "_state_duration")
+last_notifications = monitor.logs(start: -5h, fn: (r) =>
+	(r._notification_rule_id == "0000000000000001" and r._sent == "true"))
+	|> drop(columns: ["_start", "_stop", "_measurement", "_level", "_sent", "_notification_rule_id", "_notification_rule_name", "_notification_endpoint_id", "_notification_endpoint_name", "_status_timestamp"])
+	|> last(column: "_time")
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 4h)))
+	|> map(fn: (r) =>
+		({r with _notified: 1}))
+latest_statuses = series_statuses
+	|> last(column: "_time")
+	|> filter(fn: (r) =>
+		(r._state_duration >= 3600))
+	|> map(fn: (r) =>
+		({r with _notified: 0}))
+renotifications = union(tables: [last_notifications, latest_statuses])
+	|> sort(columns: ["_notified"], desc: true)
+	|> cumulativeSum(columns: ["_notified"])
+	|> filter(fn: (r) =>
+		(r._notified == 0))
+	|> drop(columns: ["_notified", "_state_duration"])
+	|> experimental.group(mode: "extend", columns: ["_level", "_measurement"])
+all_statuses = union(tables: [state_changes, renotifications])
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r._level == "crit" then "danger" else if r._level == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel:         "bar",
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					Renotify:   mustDuration("4h"),
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+						{
+							CurrentLevel:  notification.Warn,
+							PreviousLevel: statusRulePtr(notification.Info),
+						},
+					},
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
+		{
+			name: "with escalation and renotify",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h30m, fn: (r) =>
+	(r.foo == "bar"))
+series_statuses = statuses
+	|> drop(columns: ["_start", "_stop"])
+	|> duplicate(column: "_level", as: "l2")
+	|> drop(columns: ["_level"])
+	|> rename(columns: {"l2": "_level"})
+	|> duplicate(column: "_measurement", as: "m2")
+	|> drop(columns: ["_measurement"])
+	|> rename(columns: {"m2": "_measurement"})
+	|> sort(columns: ["_time"])
+	|> stateDuration(fn: (r) =>
+		(r._level == "crit" or r._level == "warn"), column: "_state_duration")
+escalations = series_statuses
+	|> map(fn: (r) =>
+		({r with _escalated: if r._state_duration >= 1800 then 1 else 0}))
+	|> difference(columns: ["_escalated"])
+	|> filter(fn: (r) =>
+		(r._escalated > 0 and r._time > experimental.subDuration(from: now(), d: 1h)))
+	|> drop(columns: ["_escalated", "_state_duration"])
+	|> experimental.group(mode: "extend", columns: ["_level", "_measurement"])
+last_notifications = monitor.logs(start: -5h, fn: (r) =>
+	(r._notification_rule_id == "0000000000000001" and r._sent == "true"))
+	|> drop(columns: ["_start", "_stop", "_measurement", "_level", "_sent", "_notification_rule_id", "_notification_rule_name", "_notification_endpoint_id", "_notification_endpoint_name", "_status_timestamp"])
+	|> last(column: "_time")
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 4h)))
+	|> map(fn: (r) =>
+		({r with _notified: 1}))
+latest_statuses = series_statuses
+	|> last(column: "_time")
+	|> filter(fn: (r) =>
+		(r._state_duration >= 5400))
+	|> map(fn: (r) =>
+		({r with _notified: 0}))
+renotifications = union(tables: [last_notifications, latest_statuses])
+	|> sort(columns: ["_notified"], desc: true)
+	|> cumulativeSum(columns: ["_notified"])
+	|> filter(fn: (r) =>
+		(r._notified == 0))
+	|> drop(columns: ["_notified", "_state_duration"])
+	|> experimental.group(mode: "extend", columns: ["_level", "_measurement"])
+all_statuses = union(tables: [escalations, renotifications])
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r._level == "crit" then "danger" else if r._level == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel:         "bar",
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:            1,
+					EndpointID:    2,
+					Name:          "foo",
+					Every:         mustDuration("1h"),
+					Renotify:      mustDuration("4h"),
+					EscalateAfter: mustDuration("30m"),
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+						{
+							CurrentLevel:  notification.Warn,
+							PreviousLevel: statusRulePtr(notification.Info),
+						},
+					},
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
+		{
+			name: "with silences",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h, fn: (r) =>
+	(r.foo == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+matched_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+silenced = (r) =>
+	(r._time >= 2019-12-01T00:00:00Z and r._time < 2019-12-01T02:00:00Z and r._check_id == "0000000000000003" or r._time >= 2019-12-02T00:00:00Z and r._time < 2019-12-02T06:30:00Z and r.host == "db1")
+
+matched_statuses
+	|> filter(fn: (r) =>
+		(silenced(r: r)))
+	|> monitor.notify(data: notification, endpoint: (tables=<-) =>
+		(tables
+			|> map(fn: (r) =>
+				({r with _sent: "false", _silenced: "true"}))))
+
+all_statuses = matched_statuses
+	|> filter(fn: (r) =>
+		(not silenced(r: r)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r._level == "crit" then "danger" else if r._level == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel:         "bar",
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+					Silences: []*influxdb.Silence{
+						{
+							CheckID:   3,
+							StartTime: time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC),
+							EndTime:   time.Date(2019, 12, 1, 2, 0, 0, 0, time.UTC),
+						},
+						{
+							TagRules: []influxdb.TagRule{
+								{
+									Tag: influxdb.Tag{
+										Key:   "host",
+										Value: "db1",
+									},
+									Operator: influxdb.Equal,
+								},
+							},
+							StartTime: time.Date(2019, 12, 2, 0, 0, 0, 0, time.UTC),
+							EndTime:   time.Date(2019, 12, 2, 6, 30, 0, 0, time.UTC),
+						},
+					},
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
+		{
+			name: "with silences and acknowledged alerts",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "slack"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+slack_endpoint = slack.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h, fn: (r) =>
+	(r.foo == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+matched_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+silenced = (r) =>
+	(r._time >= 2019-12-01T00:00:00Z and r._time < 2019-12-01T02:00:00Z and r._check_id == "0000000000000003")
+
+matched_statuses
+	|> filter(fn: (r) =>
+		(silenced(r: r)))
+	|> monitor.notify(data: notification, endpoint: (tables=<-) =>
+		(tables
+			|> map(fn: (r) =>
+				({r with _sent: "false", _silenced: "true"}))))
+
+acknowledged = (r) =>
+	(r._check_id == "0000000000000004" and r._level == "crit" and r.host == "db1" and r._source_measurement == "cpu" or r._check_id == "0000000000000005" and r._level == "crit")
+all_statuses = matched_statuses
+	|> filter(fn: (r) =>
+		(not silenced(r: r)))
+	|> filter(fn: (r) =>
+		(not acknowledged(r: r)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) =>
+		({channel: "bar", text: "blah", color: if r._level == "crit" then "danger" else if r._level == "warn" then "warning" else "good"})))`,
+			rule: &rule.Slack{
+				Channel:         "bar",
+				MessageTemplate: "blah",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+					Silences: []*influxdb.Silence{
+						{
+							CheckID:   3,
+							StartTime: time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC),
+							EndTime:   time.Date(2019, 12, 1, 2, 0, 0, 0, time.UTC),
+						},
+					},
+					AlertAcks: []*influxdb.AlertAck{
+						{
+							CheckID: 4,
+							Level:   "crit",
+							Tags: []influxdb.Tag{
+								{Key: "host", Value: "db1"},
+								{Key: "_source_measurement", Value: "cpu"},
+							},
+						},
+						{
+							CheckID: 5,
+							Level:   "crit",
+						},
+					},
+				},
+			},
+			endpoint: &endpoint.Slack{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				URL: "http://localhost:7777",
+			},
+		},
 	}
 
 	for _, tt := range tests {
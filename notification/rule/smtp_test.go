@@ -0,0 +1,88 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestSMTP_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/smtp"
+import "experimental"
+import "influxdata/influxdb/secrets"
+
+option task = {name: "foo", every: 1h}
+
+smtp_username = secrets.get(key: "0000000000000002-username")
+smtp_password = secrets.get(key: "0000000000000002-password")
+smtp_endpoint = smtp.endpoint(
+	host: "localhost",
+	port: 2525,
+	username: smtp_username,
+	password: smtp_password,
+	from: "InfluxDB <alerts@example.com>",
+)
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: smtp_endpoint(mapFn: (r) =>
+		({to: ["oncall@example.com", "ops@example.com"], subject: "${r._check_name} is ${r._level}", body: "${r._message}"})))`
+
+	s := &rule.SMTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			TagRules:   []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		To:              []string{"oncall@example.com", "ops@example.com"},
+		SubjectTemplate: "${r._check_name} is ${r._level}",
+		BodyTemplate:    "${r._message}",
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.SMTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		Host:     "localhost",
+		Port:     2525,
+		From:     "InfluxDB <alerts@example.com>",
+		Username: influxdb.SecretField{Key: "0000000000000002-username"},
+		Password: influxdb.SecretField{Key: "0000000000000002-password"},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
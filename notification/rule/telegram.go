@@ -0,0 +1,167 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// Telegram is the notification rule config of a telegram bot.
+type Telegram struct {
+	Base
+	// Channel is the id of the chat or the username of the channel the messages are sent to.
+	Channel         string `json:"channel"`
+	MessageTemplate string `json:"messageTemplate"`
+	// ParseMode is the formatting of the message: "Markdown", "MarkdownV2" or "HTML". It is plain text if empty.
+	ParseMode             string `json:"parseMode,omitempty"`
+	DisableWebPagePreview bool   `json:"disableWebPagePreview,omitempty"`
+}
+
+var goodTelegramParseMode = map[string]bool{
+	"":           true,
+	"Markdown":   true,
+	"MarkdownV2": true,
+	"HTML":       true,
+}
+
+// GenerateFlux generates a flux script for the telegram notification rule.
+func (s *Telegram) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
+	telegramEndpoint, ok := e.(*endpoint.Telegram)
+	if !ok {
+		return "", fmt.Errorf("endpoint provided is a %s, not a Telegram endpoint", e.Type())
+	}
+	p, err := s.GenerateFluxAST(telegramEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST generates a flux AST for the telegram notification rule.
+func (s *Telegram) GenerateFluxAST(e *endpoint.Telegram) (*ast.Package, error) {
+	f := flux.File(
+		s.Name,
+		flux.Imports("influxdata/influxdb/monitor", "http", "json", "influxdata/influxdb/secrets", "experimental"),
+		s.generateFluxASTBody(e),
+	)
+	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
+}
+
+func (s *Telegram) generateFluxASTBody(e *endpoint.Telegram) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, s.generateTaskOption())
+	statements = append(statements, s.generateFluxASTSecrets(e))
+	statements = append(statements, s.generateFluxASTEndpoint(e))
+	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
+	statements = append(statements, s.generateFluxASTStatuses())
+	statements = append(statements, s.generateAllStateChanges()...)
+	statements = append(statements, s.generateFluxASTNotifyPipe())
+
+	return statements
+}
+
+func (s *Telegram) generateFluxASTSecrets(e *endpoint.Telegram) ast.Statement {
+	call := flux.Call(flux.Member("secrets", "get"), flux.Object(flux.Property("key", flux.String(e.Token.Key))))
+
+	return flux.DefineVariable("telegram_secret", call)
+}
+
+func (s *Telegram) generateFluxASTEndpoint(e *endpoint.Telegram) ast.Statement {
+	// the token of the bot is part of the URL of the bot API.
+	url := flux.Add(
+		flux.Add(flux.String(e.GetURL()), flux.Identifier("telegram_secret")),
+		flux.String("/sendMessage"),
+	)
+	call := flux.Call(flux.Member("http", "endpoint"), flux.Object(flux.Property("url", url)))
+
+	return flux.DefineVariable("telegram_endpoint", call)
+}
+
+func (s *Telegram) generateFluxASTNotifyPipe() ast.Statement {
+	endpointBody := flux.Call(
+		flux.Member("json", "encode"),
+		flux.Object(flux.Property("v", flux.Identifier("body"))),
+	)
+
+	endpointProps := []*ast.Property{
+		flux.Property("headers", flux.Object(flux.Dictionary("Content-Type", flux.String("application/json")))),
+		flux.Property("data", endpointBody),
+	}
+	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
+		s.generateBody(),
+		&ast.ReturnStatement{
+			Argument: flux.Object(endpointProps...),
+		},
+	)
+
+	props := []*ast.Property{}
+	props = append(props, flux.Property("data", flux.Identifier("notification")))
+	props = append(props, flux.Property("endpoint",
+		flux.Call(flux.Identifier("telegram_endpoint"), flux.Object(flux.Property("mapFn", endpointFn)))))
+
+	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
+
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+}
+
+func (s *Telegram) generateBody() ast.Statement {
+	props := []*ast.Property{
+		flux.Property("chat_id", flux.String(s.Channel)),
+		flux.Property("text", flux.String(s.MessageTemplate)),
+	}
+	if s.ParseMode != "" {
+		props = append(props, flux.Property("parse_mode", flux.String(s.ParseMode)))
+	}
+	props = append(props, flux.Property("disable_web_page_preview", flux.Bool(s.DisableWebPagePreview)))
+
+	return flux.DefineVariable("body", flux.Object(props...))
+}
+
+type telegramAlias Telegram
+
+// MarshalJSON implement json.Marshaler interface.
+func (s Telegram) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			telegramAlias
+			Type string `json:"type"`
+		}{
+			telegramAlias: telegramAlias(s),
+			Type:          s.Type(),
+		})
+}
+
+// Valid returns where the config is valid.
+func (s Telegram) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.Channel == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "telegram channel is empty",
+		}
+	}
+	if s.MessageTemplate == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "telegram msg template is empty",
+		}
+	}
+	if !goodTelegramParseMode[s.ParseMode] {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid telegram parse mode %q", s.ParseMode),
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the rule config.
+func (s Telegram) Type() string {
+	return "telegram"
+}
@@ -0,0 +1,87 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestTelegram_GenerateFlux(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+telegram_secret = secrets.get(key: "0000000000000002-token")
+telegram_endpoint = http.endpoint(url: "https://api.telegram.org/bot" + telegram_secret + "/sendMessage")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: telegram_endpoint(mapFn: (r) => {
+		body = {
+			chat_id: "-12345",
+			text: "${r._message}",
+			parse_mode: "Markdown",
+			disable_web_page_preview: true,
+		}
+
+		return {headers: {"Content-Type": "application/json"}, data: json.encode(v: body)}
+	}))`
+
+	s := &rule.Telegram{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			TagRules:   []notification.TagRule{},
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		Channel:               "-12345",
+		MessageTemplate:       "${r._message}",
+		ParseMode:             "Markdown",
+		DisableWebPagePreview: true,
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.Telegram{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		Token: influxdb.SecretField{Key: "0000000000000002-token"},
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+}
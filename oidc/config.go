@@ -0,0 +1,79 @@
+package oidc
+
+import (
+	"github.com/influxdata/influxdb"
+)
+
+// DefaultUsernameClaim is the claim of the tokens of the provider that holds
+// the name of the user, when it is not configured.
+const DefaultUsernameClaim = "email"
+
+// DefaultScopes are the scopes requested to the provider on sign in, when they
+// are not configured.
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// Config is the configuration of the single sign-on through an OpenID Connect provider.
+type Config struct {
+	// Issuer is the URL of the provider, where its discovery document is
+	// published under /.well-known/openid-configuration.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the URL of the sign in callback of influxdb,
+	// e.g. https://influxdb.example.com/api/v2/signin/oidc/callback.
+	RedirectURL string
+	Scopes      []string
+
+	// UsernameClaim is the claim of the tokens holding the name of the user.
+	// The email claim is only accepted if the email_verified claim is true.
+	UsernameClaim string
+	// OrgsClaim is the claim of the tokens holding the names of the
+	// organizations the user is a member of, e.g. groups. The memberships are
+	// added when the user signs in.
+	OrgsClaim string
+	// AutoProvision creates the users that sign in for the first time,
+	// otherwise only the existing users can sign in. In both cases, an
+	// existing user only signs in if its OAuth ID is the issuer and subject
+	// of the token, as oidc:<issuer>#<subject>.
+	AutoProvision bool
+
+	// Audience is the audience of the tokens of the provider that API clients
+	// present, the client ID if not set.
+	Audience string
+}
+
+// Valid returns an error if the configuration is incomplete.
+func (c Config) Valid() error {
+	if c.Issuer == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "OpenID Connect issuer is required",
+		}
+	}
+	if c.ClientID == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "OpenID Connect client ID is required",
+		}
+	}
+	if c.RedirectURL == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "OpenID Connect redirect URL is required",
+		}
+	}
+	return nil
+}
+
+func (c Config) withDefaults() Config {
+	if len(c.Scopes) == 0 {
+		c.Scopes = DefaultScopes
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = DefaultUsernameClaim
+	}
+	if c.Audience == "" {
+		c.Audience = c.ClientID
+	}
+	return c
+}
@@ -0,0 +1,462 @@
+// Package oidc implements the single sign-on of the users through an OpenID
+// Connect provider, with the authorization code flow and PKCE, and the
+// authentication of the API clients presenting the tokens of the provider.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/jsonweb"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+// ErrUnknownIssuer is returned when authorizing a token which is not a token of the provider.
+var ErrUnknownIssuer = &influxdb.Error{
+	Code: influxdb.EUnauthorized,
+	Msg:  "token is not issued by the OpenID Connect provider",
+}
+
+// sessionCacheTTL is the longest the session of a token presented by an API
+// client is reused, before its user and permissions are looked up again.
+const sessionCacheTTL = time.Minute
+
+// Service signs in the users through an OpenID Connect provider, creating
+// them and their organization memberships from the claims of their tokens.
+// The users are bound to the issuer and subject of their tokens.
+type Service struct {
+	log      *zap.Logger
+	config   Config
+	provider *Provider
+	oauth2   *oauth2.Config
+	client   *http.Client
+	keys     jsonweb.KeyStore
+	parser   *jwt.Parser
+
+	mu       sync.Mutex
+	sessions map[[sha256.Size]byte]*influxdb.Session
+
+	UserService                influxdb.UserService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	AuthorizationService       influxdb.AuthorizationService
+}
+
+// NewService discovers the provider of the configuration and returns a service signing in through it.
+func NewService(ctx context.Context, log *zap.Logger, config Config, us influxdb.UserService, os influxdb.OrganizationService, urms influxdb.UserResourceMappingService, as influxdb.AuthorizationService) (*Service, error) {
+	if err := config.Valid(); err != nil {
+		return nil, err
+	}
+	config = config.withDefaults()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	p, err := Discover(ctx, client, config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		log:      log,
+		config:   config,
+		provider: p,
+		oauth2: &oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  config.RedirectURL,
+			Scopes:       config.Scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  p.AuthorizationEndpoint,
+				TokenURL: p.TokenEndpoint,
+			},
+		},
+		client: client,
+		keys:   jsonweb.NewJWKSKeyStore(p.JWKSURI, client),
+		parser: &jwt.Parser{
+			ValidMethods: []string{jwt.SigningMethodRS256.Alg()},
+		},
+		sessions: make(map[[sha256.Size]byte]*influxdb.Session),
+
+		UserService:                us,
+		OrganizationService:        os,
+		UserResourceMappingService: urms,
+		AuthorizationService:       as,
+	}, nil
+}
+
+// Login is the state of a sign in, kept by the user agent between its
+// redirection to the provider and the callback of the provider.
+type Login struct {
+	// State binds the callback to the user agent which started the sign in.
+	State string
+	// Nonce binds the ID token to the sign in.
+	Nonce string
+	// Verifier is the PKCE code verifier of the authorization code.
+	Verifier string
+}
+
+// NewLogin returns the random state of a new sign in.
+func NewLogin() (*Login, error) {
+	var vs [3]string
+	for i := range vs {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		vs[i] = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return &Login{
+		State:    vs[0],
+		Nonce:    vs[1],
+		Verifier: vs[2],
+	}, nil
+}
+
+// AuthCodeURL returns the URL of the provider the user agent is redirected to, to sign in.
+func (s *Service) AuthCodeURL(l *Login) string {
+	challenge := sha256.Sum256([]byte(l.Verifier))
+	return s.oauth2.AuthCodeURL(l.State,
+		oauth2.SetAuthURLParam("nonce", l.Nonce),
+		oauth2.SetAuthURLParam("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:])),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+}
+
+// SignIn exchanges the authorization code of the callback of the provider
+// for the ID token of the user, and returns the user, provisioned from the
+// claims of the token.
+func (s *Service) SignIn(ctx context.Context, l *Login, code string) (*influxdb.User, error) {
+	tok, err := s.oauth2.Exchange(context.WithValue(ctx, oauth2.HTTPClient, s.client), code,
+		oauth2.SetAuthURLParam("code_verifier", l.Verifier),
+	)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "failed to exchange the authorization code",
+			Err:  err,
+		}
+	}
+
+	raw, ok := tok.Extra("id_token").(string)
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "provider did not return an ID token",
+		}
+	}
+
+	claims, err := s.verify(raw, s.config.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if nonce, _ := claims["nonce"].(string); nonce != l.Nonce {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "ID token nonce does not match the sign in",
+		}
+	}
+
+	return s.provision(ctx, claims)
+}
+
+// Authorize returns the authorizer of a token of the provider presented by an
+// API client, a session of the user of the token with its permissions which
+// expires with the token. The session is reused for the same token for up to
+// a minute. ErrUnknownIssuer is returned if the token is not a token of the
+// provider.
+func (s *Service) Authorize(ctx context.Context, raw string) (*influxdb.Session, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := s.parser.ParseUnverified(raw, claims); err != nil || !claims.VerifyIssuer(s.provider.Issuer, true) {
+		return nil, ErrUnknownIssuer
+	}
+
+	key := sha256.Sum256([]byte(raw))
+	if sess := s.cachedSession(key); sess != nil {
+		return sess, nil
+	}
+
+	claims, err := s.verify(raw, s.config.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "token has no expiration",
+		}
+	}
+
+	u, err := s.provision(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := s.userPermissions(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := &influxdb.Session{
+		ID:          u.ID,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Unix(int64(exp), 0),
+		UserID:      u.ID,
+		Permissions: ps,
+	}
+	s.cacheSession(key, sess)
+	return sess, nil
+}
+
+// cachedSession returns a copy of the session of a token, if it is cached and
+// neither the session nor its cache entry expired.
+func (s *Service) cachedSession(key [sha256.Size]byte) *influxdb.Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[key]
+	if !ok {
+		return nil
+	}
+	if time.Since(sess.CreatedAt) > sessionCacheTTL || sess.Expired() != nil {
+		delete(s.sessions, key)
+		return nil
+	}
+	cp := *sess
+	return &cp
+}
+
+// cacheSession caches the session of a token, dropping the expired entries.
+func (s *Service) cacheSession(key [sha256.Size]byte, sess *influxdb.Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, v := range s.sessions {
+		if time.Since(v.CreatedAt) > sessionCacheTTL || v.Expired() != nil {
+			delete(s.sessions, k)
+		}
+	}
+	cp := *sess
+	s.sessions[key] = &cp
+}
+
+// verify returns the claims of a token of the provider, if its signature,
+// issuer, audience and times are valid.
+func (s *Service) verify(raw, audience string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := s.parser.ParseWithClaims(raw, claims, s.keyFunc); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "invalid token",
+			Err:  err,
+		}
+	}
+
+	if !claims.VerifyIssuer(s.provider.Issuer, true) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "token is not issued by the OpenID Connect provider",
+		}
+	}
+
+	if !containsString(claimStrings(claims, "aud"), audience) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  fmt.Sprintf("token audience is not %q", audience),
+		}
+	}
+
+	return claims, nil
+}
+
+// keyFunc returns the public key of the provider which signed the token.
+func (s *Service) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, err := s.keys.Key(kid)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(key)
+}
+
+// provision returns the user of the claims, creating the user if it does not
+// exist and auto provisioning is enabled, and adds the user to the
+// organizations of the claims. The user is bound to the issuer and subject of
+// the claims when created, and an existing user of the same name which is not
+// bound to them is refused, so that a local user is never taken over.
+func (s *Service) provision(ctx context.Context, claims jwt.MapClaims) (*influxdb.User, error) {
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "token has no subject",
+		}
+	}
+	id := identity(s.provider.Issuer, sub)
+
+	name, _ := claims[s.config.UsernameClaim].(string)
+	if name == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  fmt.Sprintf("token has no %q claim", s.config.UsernameClaim),
+		}
+	}
+	if s.config.UsernameClaim == "email" && !claimTrue(claims, "email_verified") {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  fmt.Sprintf("email %q of the token is not verified", name),
+		}
+	}
+
+	u, err := s.UserService.FindUser(ctx, influxdb.UserFilter{Name: &name})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound && s.config.AutoProvision {
+		u = &influxdb.User{Name: name, OAuthID: id, Status: influxdb.Active}
+		if err := s.UserService.CreateUser(ctx, u); err != nil {
+			return nil, err
+		}
+		s.log.Info("Created user signed in through OpenID Connect", zap.String("user", name))
+	} else if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  fmt.Sprintf("user %q does not exist", name),
+		}
+	} else if err != nil {
+		return nil, err
+	} else if u.OAuthID != id {
+		s.log.Warn("Refused OpenID Connect sign in of a user bound to another identity", zap.String("user", name), zap.String("subject", sub))
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  fmt.Sprintf("user %q is not bound to the OpenID Connect identity of the token", name),
+		}
+	}
+
+	if s.config.OrgsClaim == "" {
+		return u, nil
+	}
+
+	for _, orgName := range claimStrings(claims, s.config.OrgsClaim) {
+		orgName := orgName
+		org, err := s.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &orgName})
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			s.log.Debug("Skipping unknown organization of OpenID Connect user", zap.String("user", name), zap.String("org", orgName))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		_, n, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+			ResourceID:   org.ID,
+			ResourceType: influxdb.OrgsResourceType,
+			UserID:       u.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			continue
+		}
+
+		if err := s.UserResourceMappingService.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+			ResourceID:   org.ID,
+			ResourceType: influxdb.OrgsResourceType,
+			UserID:       u.ID,
+			UserType:     influxdb.Member,
+		}); err != nil {
+			return nil, err
+		}
+		s.log.Info("Added OpenID Connect user to organization", zap.String("user", name), zap.String("org", orgName))
+	}
+
+	return u, nil
+}
+
+// userPermissions returns the maximal permissions of the user, as the ones of
+// the sessions of the user.
+func (s *Service) userPermissions(ctx context.Context, userID influxdb.ID) ([]influxdb.Permission, error) {
+	mappings, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	ps := make([]influxdb.Permission, 0, len(mappings))
+	for _, m := range mappings {
+		p, err := m.ToPermissions()
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p...)
+	}
+	ps = append(ps, influxdb.MePermissions(userID)...)
+
+	as, _, err := s.AuthorizationService.FindAuthorizations(ctx, influxdb.AuthorizationFilter{UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range as {
+		ps = append(ps, a.Permissions...)
+	}
+
+	return ps, nil
+}
+
+// claimStrings returns the values of a claim which is either a string, a comma
+// separated list or an array of strings.
+func claimStrings(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		var vs []string
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				vs = append(vs, s)
+			}
+		}
+		return vs
+	case []interface{}:
+		vs := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				vs = append(vs, s)
+			}
+		}
+		return vs
+	}
+	return nil
+}
+
+// claimTrue returns whether a boolean claim is true. Some providers encode the
+// booleans as strings.
+func claimTrue(claims jwt.MapClaims, name string) bool {
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+// identity returns the OAuth ID of the user of a subject of the issuer.
+func identity(issuer, sub string) string {
+	return "oidc:" + issuer + "#" + sub
+}
+
+func containsString(vs []string, v string) bool {
+	for _, s := range vs {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
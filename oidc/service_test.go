@@ -0,0 +1,262 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/oidc"
+	"github.com/influxdata/influxdb/oidc/oidctest"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestService(t *testing.T, autoProvision bool) (*oidc.Service, *oidctest.Provider, *kv.Service, func()) {
+	t.Helper()
+
+	p, err := oidctest.NewProvider("influxdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+
+	cfg := oidc.Config{
+		Issuer:        p.Issuer(),
+		ClientID:      "influxdb",
+		ClientSecret:  "secret",
+		RedirectURL:   "http://localhost:9999/api/v2/signin/oidc/callback",
+		OrgsClaim:     "groups",
+		AutoProvision: autoProvision,
+	}
+	s, err := oidc.NewService(ctx, zaptest.NewLogger(t), cfg, svc, svc, svc, svc)
+	if err != nil {
+		p.Close()
+		t.Fatal(err)
+	}
+	return s, p, svc, p.Close
+}
+
+// authorize signs in at the provider with the claims and returns the authorization code.
+func authorize(t *testing.T, s *oidc.Service, p *oidctest.Provider, l *oidc.Login, claims jwt.MapClaims) string {
+	t.Helper()
+
+	u, err := url.Parse(s.AuthCodeURL(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("state") != l.State || q.Get("nonce") != l.Nonce || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization URL %s", u)
+	}
+	p.Authorize("code", q.Get("code_challenge"), claims)
+	return "code"
+}
+
+func TestService_SignIn(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("provisions the user and its organizations", func(t *testing.T) {
+		s, p, svc, closeProvider := newTestService(t, true)
+		defer closeProvider()
+		org := &influxdb.Organization{Name: "ops"}
+		if err := svc.CreateOrganization(ctx, org); err != nil {
+			t.Fatal(err)
+		}
+
+		l, err := oidc.NewLogin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		code := authorize(t, s, p, l, jwt.MapClaims{
+			"sub":            "1234",
+			"email":          "alice@example.com",
+			"email_verified": true,
+			"groups":         []interface{}{"ops", "unknown"},
+			"nonce":          l.Nonce,
+		})
+
+		u, err := s.SignIn(ctx, l, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Name != "alice@example.com" || u.Status != influxdb.Active || u.OAuthID != "oidc:"+p.Issuer()+"#1234" {
+			t.Fatalf("unexpected user %v", u)
+		}
+
+		_, n, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+			ResourceID: org.ID,
+			UserID:     u.ID,
+			UserType:   influxdb.Member,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Fatalf("expected the user to be a member of the organization of the claims, got %d mappings", n)
+		}
+	})
+
+	t.Run("rejects the code without its verifier", func(t *testing.T) {
+		s, p, _, closeProvider := newTestService(t, true)
+		defer closeProvider()
+
+		l, _ := oidc.NewLogin()
+		code := authorize(t, s, p, l, jwt.MapClaims{"email": "alice@example.com", "nonce": l.Nonce})
+		l.Verifier = "other"
+
+		if _, err := s.SignIn(ctx, l, code); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			t.Fatalf("expected unauthorized error, got %v", err)
+		}
+	})
+
+	t.Run("rejects the ID token of another sign in", func(t *testing.T) {
+		s, p, _, closeProvider := newTestService(t, true)
+		defer closeProvider()
+
+		l, _ := oidc.NewLogin()
+		code := authorize(t, s, p, l, jwt.MapClaims{"email": "alice@example.com", "nonce": "other"})
+
+		if _, err := s.SignIn(ctx, l, code); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			t.Fatalf("expected unauthorized error, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown users without auto provisioning", func(t *testing.T) {
+		s, p, _, closeProvider := newTestService(t, false)
+		defer closeProvider()
+
+		l, _ := oidc.NewLogin()
+		code := authorize(t, s, p, l, jwt.MapClaims{"sub": "1234", "email": "alice@example.com", "email_verified": true, "nonce": l.Nonce})
+
+		if _, err := s.SignIn(ctx, l, code); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			t.Fatalf("expected unauthorized error, got %v", err)
+		}
+	})
+
+	t.Run("rejects unverified emails", func(t *testing.T) {
+		s, p, _, closeProvider := newTestService(t, true)
+		defer closeProvider()
+
+		l, _ := oidc.NewLogin()
+		code := authorize(t, s, p, l, jwt.MapClaims{"sub": "1234", "email": "alice@example.com", "email_verified": false, "nonce": l.Nonce})
+
+		if _, err := s.SignIn(ctx, l, code); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			t.Fatalf("expected unauthorized error, got %v", err)
+		}
+	})
+
+	t.Run("refuses existing users not bound to the identity of the token", func(t *testing.T) {
+		s, p, svc, closeProvider := newTestService(t, true)
+		defer closeProvider()
+		local := &influxdb.User{Name: "alice@example.com", Status: influxdb.Active}
+		if err := svc.CreateUser(ctx, local); err != nil {
+			t.Fatal(err)
+		}
+
+		l, _ := oidc.NewLogin()
+		code := authorize(t, s, p, l, jwt.MapClaims{"sub": "1234", "email": "alice@example.com", "email_verified": true, "nonce": l.Nonce})
+
+		if _, err := s.SignIn(ctx, l, code); influxdb.ErrorCode(err) != influxdb.EForbidden {
+			t.Fatalf("expected forbidden error, got %v", err)
+		}
+	})
+
+	t.Run("signs in the users bound to the identity of the token", func(t *testing.T) {
+		s, p, svc, closeProvider := newTestService(t, false)
+		defer closeProvider()
+		bound := &influxdb.User{Name: "alice@example.com", OAuthID: "oidc:" + p.Issuer() + "#1234", Status: influxdb.Active}
+		if err := svc.CreateUser(ctx, bound); err != nil {
+			t.Fatal(err)
+		}
+
+		l, _ := oidc.NewLogin()
+		code := authorize(t, s, p, l, jwt.MapClaims{"sub": "1234", "email": "alice@example.com", "email_verified": true, "nonce": l.Nonce})
+		u, err := s.SignIn(ctx, l, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.ID != bound.ID {
+			t.Fatalf("got user %v, want %v", u, bound)
+		}
+
+		l, _ = oidc.NewLogin()
+		code = authorize(t, s, p, l, jwt.MapClaims{"sub": "5678", "email": "alice@example.com", "email_verified": true, "nonce": l.Nonce})
+		if _, err := s.SignIn(ctx, l, code); influxdb.ErrorCode(err) != influxdb.EForbidden {
+			t.Fatalf("expected forbidden error for another subject with the same email, got %v", err)
+		}
+	})
+}
+
+func TestService_Authorize(t *testing.T) {
+	ctx := context.Background()
+	s, p, svc, closeProvider := newTestService(t, true)
+	defer closeProvider()
+
+	org := &influxdb.Organization{Name: "ops"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	tok := p.Token(jwt.MapClaims{
+		"sub":            "1234",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"groups":         "ops",
+	})
+	sess, err := s.Authorize(ctx, tok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Expired(); err != nil {
+		t.Fatal(err)
+	}
+	p1, err := influxdb.NewPermissionAtID(org.ID, influxdb.ReadAction, influxdb.OrgsResourceType, org.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sess.Allowed(*p1) {
+		t.Fatalf("expected the user of the token to read its organization, got %v", sess.Permissions)
+	}
+
+	// The session of the token is reused rather than provisioned again.
+	if err := svc.DeleteUserResourceMapping(ctx, org.ID, sess.UserID); err != nil {
+		t.Fatal(err)
+	}
+	cached, err := s.Authorize(ctx, tok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.UserID != sess.UserID || !cached.Allowed(*p1) {
+		t.Fatalf("expected the cached session of the token, got %v", cached)
+	}
+	_, n, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{ResourceID: org.ID, UserID: sess.UserID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("expected the cached session not to provision the user again, got %d mappings", n)
+	}
+
+	if _, err := s.Authorize(ctx, p.Token(jwt.MapClaims{"sub": "1234", "email": "alice@example.com", "email_verified": true, "aud": "other"})); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Fatalf("expected unauthorized error for another audience, got %v", err)
+	}
+
+	foreign, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": "other", "kid": "some-key"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Authorize(ctx, foreign); err != oidc.ErrUnknownIssuer {
+		t.Fatalf("expected unknown issuer error, got %v", err)
+	}
+	if _, err := s.Authorize(ctx, "not-a-jwt"); err != oidc.ErrUnknownIssuer {
+		t.Fatalf("expected unknown issuer error, got %v", err)
+	}
+}
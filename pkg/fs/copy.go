@@ -0,0 +1,32 @@
+package fs
+
+import (
+	"io"
+	"os"
+)
+
+// CopyFile copies the contents of src to a new file at dst, returning an error
+// if dst already exists. The new file is synced before CopyFile returns.
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := CreateFile(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
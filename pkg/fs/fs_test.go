@@ -110,6 +110,27 @@ func TestCreateFileWithReplacement(t *testing.T) {
 
 }
 
+func TestCopyFile(t *testing.T) {
+	src := MustCreateTempFile(t, "sample data")
+	defer MustRemoveAll(src)
+
+	dst := src + ".copy"
+	defer MustRemoveAll(dst)
+
+	if err := fs.CopyFile(src, dst); err != nil {
+		t.Fatalf("CopyFile returned err: %v", err)
+	}
+
+	if got, exp := MustReadAllFile(dst), "sample data"; got != exp {
+		t.Fatalf("got contents %q, expected %q", got, exp)
+	}
+
+	// copying onto an existing file should fail.
+	if err := fs.CopyFile(src, dst); err == nil {
+		t.Fatalf("CopyFile did not return an error")
+	}
+}
+
 // CreateTempFileOrFail creates a temporary file returning the path to the file.
 func MustCreateTempFile(t testing.TB, data string) string {
 	t.Helper()
@@ -57,7 +57,7 @@ func uniqResourcesToClone(resources []ResourceToClone) []ResourceToClone {
 	return out
 }
 
-func bucketToResource(bkt influxdb.Bucket, name string) Resource {
+func bucketToResource(bkt influxdb.Bucket, sourceBucketName, name string) Resource {
 	if name == "" {
 		name = bkt.Name
 	}
@@ -69,6 +69,33 @@ func bucketToResource(bkt influxdb.Bucket, name string) Resource {
 	if bkt.RetentionPeriod != 0 {
 		r[fieldBucketRetentionRules] = retentionRules{newRetentionRule(bkt.RetentionPeriod)}
 	}
+	if p := bkt.DownsamplePolicy; p != nil && sourceBucketName != "" {
+		r[fieldBucketDownsamplePolicy] = downsamplePolicyToResource(*p, sourceBucketName)
+	}
+	return r
+}
+
+func downsamplePolicyToResource(p influxdb.DownsamplePolicy, sourceBucketName string) Resource {
+	r := Resource{
+		fieldDownsampleSourceBucket: sourceBucketName,
+		fieldEvery:                  p.Every.String(),
+	}
+	if p.Lookback.Duration > 0 {
+		r[fieldDownsampleLookback] = p.Lookback.String()
+	}
+	if p.Offset.Duration > 0 {
+		r[fieldOffset] = p.Offset.String()
+	}
+
+	aggs := make(Resource)
+	assignNonZeroStrings(aggs, map[string]string{
+		influxdb.DownsampleFieldTypeFloat:    p.Aggregates.Float,
+		influxdb.DownsampleFieldTypeInteger:  p.Aggregates.Integer,
+		influxdb.DownsampleFieldTypeUnsigned: p.Aggregates.Unsigned,
+		influxdb.DownsampleFieldTypeBoolean:  p.Aggregates.Boolean,
+		influxdb.DownsampleFieldTypeString:   p.Aggregates.String,
+	})
+	r[fieldDownsampleAggregates] = aggs
 	return r
 }
 
@@ -123,6 +150,26 @@ func checkToResource(ch influxdb.Check, name string) Resource {
 			thresholds = append(thresholds, convertThreshold(th))
 		}
 		r[fieldCheckThresholds] = thresholds
+	case *icheck.Anomaly:
+		r[fieldKind] = KindCheckAnomaly.title()
+		assignBase(cT.Base)
+		r[fieldCheckMethod] = string(cT.Method)
+		assignNonZeroFluxDurs(r, map[string]*notification.Duration{
+			fieldCheckWindow: cT.Window,
+		})
+		var levels []Resource
+		for _, l := range cT.Levels {
+			levels = append(levels, Resource{
+				fieldLevel: l.Level.String(),
+				fieldValue: l.Value,
+			})
+		}
+		r[fieldCheckLevels] = levels
+	case *icheck.Custom:
+		r[fieldKind] = KindCheckCustom.title()
+		assignBase(cT.Base)
+		// the status messages of a custom check are set by its query.
+		delete(r, fieldCheckStatusMessageTemplate)
 	}
 	return r
 }
@@ -435,6 +482,30 @@ func endpointToResource(e influxdb.NotificationEndpoint, name string) Resource {
 		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
 			fieldNotificationEndpointToken: actual.Token,
 		})
+	case *endpoint.Opsgenie:
+		r[fieldKind] = KindNotificationEndpointOpsgenie.title()
+		assignNonZeroStrings(r, map[string]string{fieldNotificationEndpointURL: actual.URL})
+		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
+			fieldNotificationEndpointAPIKey: actual.APIKey,
+		})
+	case *endpoint.SMTP:
+		r[fieldKind] = KindNotificationEndpointSMTP.title()
+		r[fieldNotificationEndpointHost] = actual.Host
+		r[fieldNotificationEndpointFrom] = actual.From
+		assignNonZeroInts(r, map[string]int{fieldNotificationEndpointPort: actual.Port})
+		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
+			fieldNotificationEndpointPassword: actual.Password,
+			fieldNotificationEndpointUsername: actual.Username,
+		})
+	case *endpoint.Teams:
+		r[fieldKind] = KindNotificationEndpointTeams.title()
+		r[fieldNotificationEndpointURL] = actual.URL
+	case *endpoint.Telegram:
+		r[fieldKind] = KindNotificationEndpointTelegram.title()
+		assignNonZeroStrings(r, map[string]string{fieldNotificationEndpointURL: actual.URL})
+		assignNonZeroSecrets(r, map[string]influxdb.SecretField{
+			fieldNotificationEndpointToken: actual.Token,
+		})
 	}
 
 	return r
@@ -455,8 +526,10 @@ func ruleToResource(iRule influxdb.NotificationRule, endpointName, name string)
 
 	assignBase := func(base rule.Base) {
 		assignNonZeroFluxDurs(r, map[string]*notification.Duration{
-			fieldEvery:  base.Every,
-			fieldOffset: base.Offset,
+			fieldEvery:                         base.Every,
+			fieldOffset:                        base.Offset,
+			fieldNotificationRuleRenotify:      base.Renotify,
+			fieldNotificationRuleEscalateAfter: base.EscalateAfter,
 		})
 
 		var tagRes []Resource
@@ -496,6 +569,27 @@ func ruleToResource(iRule influxdb.NotificationRule, endpointName, name string)
 		assignBase(t.Base)
 		r[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
 		assignNonZeroStrings(r, map[string]string{fieldNotificationRuleChannel: t.Channel})
+	case *rule.Opsgenie:
+		assignBase(t.Base)
+		r[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		if len(t.Tags) > 0 {
+			r[fieldNotificationRuleTags] = t.Tags
+		}
+	case *rule.SMTP:
+		assignBase(t.Base)
+		r[fieldNotificationRuleMessageTemplate] = t.BodyTemplate
+		r[fieldNotificationRuleSubjectTemplate] = t.SubjectTemplate
+		r[fieldNotificationRuleTo] = t.To
+	case *rule.Teams:
+		assignBase(t.Base)
+		r[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		assignNonZeroStrings(r, map[string]string{fieldNotificationRuleTitle: t.Title})
+	case *rule.Telegram:
+		assignBase(t.Base)
+		r[fieldNotificationRuleChannel] = t.Channel
+		r[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
+		assignNonZeroStrings(r, map[string]string{fieldNotificationRuleParseMode: t.ParseMode})
+		assignNonZeroBools(r, map[string]bool{fieldNotificationRuleDisableWebPagePreview: t.DisableWebPagePreview})
 	}
 
 	return r
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/mail"
 	"net/url"
 	"reflect"
 	"sort"
@@ -23,6 +24,8 @@ const (
 	KindUnknown                       Kind = ""
 	KindBucket                        Kind = "bucket"
 	KindCheck                         Kind = "check"
+	KindCheckAnomaly                  Kind = "check_anomaly"
+	KindCheckCustom                   Kind = "check_custom"
 	KindCheckDeadman                  Kind = "check_deadman"
 	KindCheckThreshold                Kind = "check_threshold"
 	KindDashboard                     Kind = "dashboard"
@@ -30,7 +33,11 @@ const (
 	KindNotificationEndpoint          Kind = "notification_endpoint"
 	KindNotificationEndpointPagerDuty Kind = "notification_endpoint_pager_duty"
 	KindNotificationEndpointHTTP      Kind = "notification_endpoint_http"
+	KindNotificationEndpointOpsgenie  Kind = "notification_endpoint_opsgenie"
 	KindNotificationEndpointSlack     Kind = "notification_endpoint_slack"
+	KindNotificationEndpointSMTP      Kind = "notification_endpoint_smtp"
+	KindNotificationEndpointTeams     Kind = "notification_endpoint_teams"
+	KindNotificationEndpointTelegram  Kind = "notification_endpoint_telegram"
 	KindNotificationRule              Kind = "notification_rule"
 	KindPackage                       Kind = "package"
 	KindTask                          Kind = "task"
@@ -41,14 +48,20 @@ const (
 var kinds = map[Kind]bool{
 	KindBucket:                        true,
 	KindCheck:                         true,
+	KindCheckAnomaly:                  true,
+	KindCheckCustom:                   true,
 	KindCheckDeadman:                  true,
 	KindCheckThreshold:                true,
 	KindDashboard:                     true,
 	KindLabel:                         true,
 	KindNotificationEndpoint:          true,
 	KindNotificationEndpointHTTP:      true,
+	KindNotificationEndpointOpsgenie:  true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointSMTP:      true,
+	KindNotificationEndpointTeams:     true,
+	KindNotificationEndpointTelegram:  true,
 	KindNotificationRule:              true,
 	KindPackage:                       true,
 	KindTask:                          true,
@@ -59,13 +72,19 @@ var kinds = map[Kind]bool{
 var kindsUniqByName = map[Kind]bool{
 	KindBucket:                        true,
 	KindCheck:                         true,
+	KindCheckAnomaly:                  true,
+	KindCheckCustom:                   true,
 	KindCheckDeadman:                  true,
 	KindCheckThreshold:                true,
 	KindLabel:                         true,
 	KindNotificationEndpoint:          true,
 	KindNotificationEndpointHTTP:      true,
+	KindNotificationEndpointOpsgenie:  true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointSMTP:      true,
+	KindNotificationEndpointTeams:     true,
+	KindNotificationEndpointTelegram:  true,
 	KindVariable:                      true,
 }
 
@@ -105,7 +124,7 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 	switch k {
 	case KindBucket:
 		return influxdb.BucketsResourceType
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAnomaly, KindCheckCustom, KindCheckDeadman, KindCheckThreshold:
 		return influxdb.ChecksResourceType
 	case KindDashboard:
 		return influxdb.DashboardsResourceType
@@ -113,8 +132,12 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 		return influxdb.LabelsResourceType
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
+		KindNotificationEndpointOpsgenie,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP,
+		KindNotificationEndpointTeams,
+		KindNotificationEndpointTelegram:
 		return influxdb.NotificationEndpointResourceType
 	case KindNotificationRule:
 		return influxdb.NotificationRuleResourceType
@@ -212,8 +235,9 @@ func (d Diff) HasConflicts() bool {
 
 // DiffBucketValues are the varying values for a bucket.
 type DiffBucketValues struct {
-	Description    string         `json:"description"`
-	RetentionRules retentionRules `json:"retentionRules"`
+	Description      string                   `json:"description"`
+	RetentionRules   retentionRules           `json:"retentionRules"`
+	DownsamplePolicy *SummaryDownsamplePolicy `json:"downsamplePolicy,omitempty"`
 }
 
 // DiffBucket is a diff of an individual bucket.
@@ -228,8 +252,9 @@ func newDiffBucket(b *bucket, i *influxdb.Bucket) DiffBucket {
 	diff := DiffBucket{
 		Name: b.Name(),
 		New: DiffBucketValues{
-			Description:    b.Description,
-			RetentionRules: b.RetentionRules,
+			Description:      b.Description,
+			RetentionRules:   b.RetentionRules,
+			DownsamplePolicy: b.DownsamplePolicy.summarize(),
 		},
 	}
 	if i != nil {
@@ -554,8 +579,18 @@ type SummaryBucket struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	// TODO: return retention rules?
-	RetentionPeriod   time.Duration  `json:"retentionPeriod"`
-	LabelAssociations []SummaryLabel `json:"labelAssociations"`
+	RetentionPeriod   time.Duration            `json:"retentionPeriod"`
+	DownsamplePolicy  *SummaryDownsamplePolicy `json:"downsamplePolicy,omitempty"`
+	LabelAssociations []SummaryLabel           `json:"labelAssociations"`
+}
+
+// SummaryDownsamplePolicy provides a summary of the downsample policy of a pkg bucket.
+type SummaryDownsamplePolicy struct {
+	SourceBucketName string                        `json:"sourceBucketName"`
+	Every            time.Duration                 `json:"every"`
+	Lookback         time.Duration                 `json:"lookback,omitempty"`
+	Offset           time.Duration                 `json:"offset,omitempty"`
+	Aggregates       influxdb.DownsampleAggregates `json:"aggregates"`
 }
 
 // SummaryCheck provides a summary of a pkg check.
@@ -712,9 +747,11 @@ type (
 		EndpointType string `json:"endpointType"`
 
 		Every             string              `json:"every"`
+		EscalateAfter     string              `json:"escalateAfter,omitempty"`
 		LabelAssociations []SummaryLabel      `json:"labelAssociations"`
 		Offset            string              `json:"offset"`
 		MessageTemplate   string              `json:"messageTemplate"`
+		Renotify          string              `json:"renotify,omitempty"`
 		Status            influxdb.Status     `json:"status"`
 		StatusRules       []SummaryStatusRule `json:"statusRules"`
 		TagRules          []SummaryTagRule    `json:"tagRules"`
@@ -806,16 +843,18 @@ const (
 )
 
 const (
-	fieldBucketRetentionRules = "retentionRules"
+	fieldBucketDownsamplePolicy = "downsamplePolicy"
+	fieldBucketRetentionRules   = "retentionRules"
 )
 
 type bucket struct {
-	id             influxdb.ID
-	OrgID          influxdb.ID
-	Description    string
-	name           string
-	RetentionRules retentionRules
-	labels         sortedLabels
+	id               influxdb.ID
+	OrgID            influxdb.ID
+	Description      string
+	name             string
+	RetentionRules   retentionRules
+	DownsamplePolicy *downsamplePolicy
+	labels           sortedLabels
 
 	// existing provides context for a resource that already
 	// exists in the platform. If a resource already exists
@@ -853,12 +892,22 @@ func (b *bucket) summarize() SummaryBucket {
 		Name:              b.Name(),
 		Description:       b.Description,
 		RetentionPeriod:   b.RetentionRules.RP(),
+		DownsamplePolicy:  b.DownsamplePolicy.summarize(),
 		LabelAssociations: toSummaryLabels(b.labels...),
 	}
 }
 
 func (b *bucket) valid() []validationErr {
-	return b.RetentionRules.valid()
+	failures := b.RetentionRules.valid()
+	if b.DownsamplePolicy != nil {
+		if ff := b.DownsamplePolicy.valid(b.Name()); len(ff) > 0 {
+			failures = append(failures, validationErr{
+				Field:  fieldBucketDownsamplePolicy,
+				Nested: ff,
+			})
+		}
+	}
+	return failures
 }
 
 func (b *bucket) shouldApply() bool {
@@ -868,6 +917,21 @@ func (b *bucket) shouldApply() bool {
 		b.RetentionRules.RP() != b.existing.RetentionPeriod
 }
 
+// shouldApplyDownsamplePolicy returns true if the downsample policy of the bucket,
+// reading from the bucket with the provided source ID, differs from the existing one.
+func (b *bucket) shouldApplyDownsamplePolicy(sourceID influxdb.ID) bool {
+	if b.DownsamplePolicy == nil {
+		return false
+	}
+	if b.existing == nil || b.existing.DownsamplePolicy == nil {
+		return true
+	}
+
+	existing := *b.existing.DownsamplePolicy
+	existing.TaskID = 0
+	return b.DownsamplePolicy.toInfluxPolicy(sourceID) != existing
+}
+
 type mapperBuckets []*bucket
 
 func (b mapperBuckets) Association(i int) labelAssociater {
@@ -916,6 +980,79 @@ const (
 	fieldRetentionRulesEverySeconds = "everySeconds"
 )
 
+const (
+	fieldDownsampleAggregates   = "aggregates"
+	fieldDownsampleLookback     = "lookback"
+	fieldDownsampleSourceBucket = "sourceBucket"
+)
+
+type downsamplePolicy struct {
+	sourceBucketName string
+	every            time.Duration
+	lookback         time.Duration
+	offset           time.Duration
+	aggregates       influxdb.DownsampleAggregates
+}
+
+func (p *downsamplePolicy) summarize() *SummaryDownsamplePolicy {
+	if p == nil {
+		return nil
+	}
+	return &SummaryDownsamplePolicy{
+		SourceBucketName: p.sourceBucketName,
+		Every:            p.every,
+		Lookback:         p.lookback,
+		Offset:           p.offset,
+		Aggregates:       p.aggregates,
+	}
+}
+
+func (p *downsamplePolicy) toInfluxPolicy(sourceID influxdb.ID) influxdb.DownsamplePolicy {
+	return influxdb.DownsamplePolicy{
+		SourceBucketID: sourceID,
+		Every:          influxdb.Duration{Duration: p.every},
+		Lookback:       influxdb.Duration{Duration: p.lookback},
+		Offset:         influxdb.Duration{Duration: p.offset},
+		Aggregates:     p.aggregates,
+	}
+}
+
+func (p *downsamplePolicy) valid(bucketName string) []validationErr {
+	var failures []validationErr
+	switch p.sourceBucketName {
+	case "":
+		failures = append(failures, validationErr{
+			Field: fieldDownsampleSourceBucket,
+			Msg:   "must provide the name of the source bucket",
+		})
+	case bucketName:
+		failures = append(failures, validationErr{
+			Field: fieldDownsampleSourceBucket,
+			Msg:   "a bucket cannot be downsampled from itself",
+		})
+	}
+
+	// the source bucket is resolved when the pkg is applied, the rest of the
+	// policy can be validated with a placeholder
+	if err := p.toInfluxPolicy(1).Valid(); err != nil {
+		failures = append(failures, validationErr{
+			Field: fieldBucketDownsamplePolicy,
+			Msg:   influxdb.ErrorMessage(err),
+		})
+	}
+	return failures
+}
+
+func newSummaryDownsamplePolicy(p influxdb.DownsamplePolicy, sourceBucketName string) *SummaryDownsamplePolicy {
+	return &SummaryDownsamplePolicy{
+		SourceBucketName: sourceBucketName,
+		Every:            p.Every.Duration,
+		Lookback:         p.Lookback.Duration,
+		Offset:           p.Offset.Duration,
+		Aggregates:       p.Aggregates,
+	}
+}
+
 type retentionRules []retentionRule
 
 func (r retentionRules) RP() time.Duration {
@@ -946,16 +1083,21 @@ type checkKind int
 const (
 	checkKindDeadman checkKind = iota + 1
 	checkKindThreshold
+	checkKindAnomaly
+	checkKindCustom
 )
 
 const (
 	fieldCheckAllValues             = "allValues"
+	fieldCheckLevels                = "levels"
+	fieldCheckMethod                = "method"
 	fieldCheckReportZero            = "reportZero"
 	fieldCheckStaleTime             = "staleTime"
 	fieldCheckStatusMessageTemplate = "statusMessageTemplate"
 	fieldCheckTags                  = "tags"
 	fieldCheckThresholds            = "thresholds"
 	fieldCheckTimeSince             = "timeSince"
+	fieldCheckWindow                = "window"
 )
 
 type check struct {
@@ -975,6 +1117,9 @@ type check struct {
 	tags          []struct{ k, v string }
 	timeSince     time.Duration
 	thresholds    []threshold
+	method        string
+	window        time.Duration
+	anomalyLevels []anomalyLevel
 
 	labels sortedLabels
 
@@ -1045,6 +1190,15 @@ func (c *check) summarize() SummaryCheck {
 			StaleTime:  toNotificationDuration(c.staleTime),
 			TimeSince:  toNotificationDuration(c.timeSince),
 		}
+	case checkKindAnomaly:
+		sum.Check = &icheck.Anomaly{
+			Base:   base,
+			Method: icheck.AnomalyMethod(c.method),
+			Window: toNotificationDuration(c.window),
+			Levels: toInfluxAnomalyLevels(c.anomalyLevels...),
+		}
+	case checkKindCustom:
+		sum.Check = &icheck.Custom{Base: base}
 	}
 	return sum
 }
@@ -1063,7 +1217,7 @@ func (c *check) valid() []validationErr {
 			Msg:   "must provide a non zero value",
 		})
 	}
-	if c.statusMessage == "" {
+	if c.statusMessage == "" && c.kind != checkKindCustom {
 		vErrs = append(vErrs, validationErr{
 			Field: fieldCheckStatusMessageTemplate,
 			Msg:   `must provide a template; ex. "Check: ${ r._check_name } is: ${ r._level }"`,
@@ -1090,6 +1244,34 @@ func (c *check) valid() []validationErr {
 				vErrs = append(vErrs, fail)
 			}
 		}
+	case checkKindAnomaly:
+		switch icheck.AnomalyMethod(c.method) {
+		case icheck.AnomalyChange, icheck.AnomalyDeviation:
+			if c.window <= c.every {
+				vErrs = append(vErrs, validationErr{
+					Field: fieldCheckWindow,
+					Msg:   "duration value must be provided that is > every",
+				})
+			}
+		case icheck.AnomalyLastWeek:
+		default:
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckMethod,
+				Msg:   fmt.Sprintf("must be 1 in [change, deviation, lastWeek]; got=%q", c.method),
+			})
+		}
+		if len(c.anomalyLevels) == 0 {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckLevels,
+				Msg:   "must provide at least 1 level entry",
+			})
+		}
+		for i, l := range c.anomalyLevels {
+			for _, fail := range l.valid() {
+				fail.Index = intPtr(i)
+				vErrs = append(vErrs, fail)
+			}
+		}
 	}
 	return vErrs
 }
@@ -1181,6 +1363,39 @@ func toInfluxThresholds(thresholds ...threshold) []icheck.ThresholdConfig {
 	return iThresh
 }
 
+type anomalyLevel struct {
+	level string
+	val   float64
+}
+
+func (a anomalyLevel) valid() []validationErr {
+	var vErrs []validationErr
+	if lvl := notification.ParseCheckLevel(a.level); lvl == notification.Unknown || lvl == notification.Any {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldLevel,
+			Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO, OK]; got=%q", a.level),
+		})
+	}
+	if a.val <= 0 {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldValue,
+			Msg:   "must be > 0",
+		})
+	}
+	return vErrs
+}
+
+func toInfluxAnomalyLevels(levels ...anomalyLevel) []icheck.AnomalyLevel {
+	var iLevels []icheck.AnomalyLevel
+	for _, l := range levels {
+		iLevels = append(iLevels, icheck.AnomalyLevel{
+			Level: notification.ParseCheckLevel(l.level),
+			Value: l.val,
+		})
+	}
+	return iLevels
+}
+
 type assocMapKey struct {
 	resType influxdb.ResourceType
 	name    string
@@ -1348,6 +1563,10 @@ const (
 	notificationKindHTTP notificationKind = iota + 1
 	notificationKindPagerDuty
 	notificationKindSlack
+	notificationKindOpsgenie
+	notificationKindSMTP
+	notificationKindTeams
+	notificationKindTelegram
 )
 
 const (
@@ -1357,8 +1576,12 @@ const (
 )
 
 const (
+	fieldNotificationEndpointAPIKey     = "apiKey"
+	fieldNotificationEndpointFrom       = "from"
+	fieldNotificationEndpointHost       = "host"
 	fieldNotificationEndpointHTTPMethod = "method"
 	fieldNotificationEndpointPassword   = "password"
+	fieldNotificationEndpointPort       = "port"
 	fieldNotificationEndpointRoutingKey = "routingKey"
 	fieldNotificationEndpointToken      = "token"
 	fieldNotificationEndpointURL        = "url"
@@ -1371,8 +1594,12 @@ type notificationEndpoint struct {
 	OrgID       influxdb.ID
 	name        string
 	description string
+	apiKey      references
+	from        string
+	host        string
 	method      string
 	password    references
+	port        int
 	routingKey  references
 	status      string
 	token       references
@@ -1463,6 +1690,32 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 			URL:   n.url,
 			Token: n.token.SecretField(),
 		}
+	case notificationKindOpsgenie:
+		sum.NotificationEndpoint = &endpoint.Opsgenie{
+			Base:   base,
+			URL:    n.url,
+			APIKey: n.apiKey.SecretField(),
+		}
+	case notificationKindSMTP:
+		sum.NotificationEndpoint = &endpoint.SMTP{
+			Base:     base,
+			Host:     n.host,
+			Port:     n.port,
+			From:     n.from,
+			Username: n.username.SecretField(),
+			Password: n.password.SecretField(),
+		}
+	case notificationKindTeams:
+		sum.NotificationEndpoint = &endpoint.Teams{
+			Base: base,
+			URL:  n.url,
+		}
+	case notificationKindTelegram:
+		sum.NotificationEndpoint = &endpoint.Telegram{
+			Base:  base,
+			URL:   n.url,
+			Token: n.token.SecretField(),
+		}
 	}
 	return sum
 }
@@ -1479,11 +1732,22 @@ var validEndpointHTTPMethods = map[string]bool{
 
 func (n *notificationEndpoint) valid() []validationErr {
 	var failures []validationErr
-	if _, err := url.Parse(n.url); err != nil || n.url == "" {
-		failures = append(failures, validationErr{
-			Field: fieldNotificationEndpointURL,
-			Msg:   "must be valid url",
-		})
+	switch n.kind {
+	case notificationKindOpsgenie, notificationKindSMTP, notificationKindTelegram:
+		// the url is optional, opsgenie and telegram default to their public API.
+		if _, err := url.Parse(n.url); err != nil {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointURL,
+				Msg:   "must be valid url",
+			})
+		}
+	default:
+		if _, err := url.Parse(n.url); err != nil || n.url == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointURL,
+				Msg:   "must be valid url",
+			})
+		}
 	}
 
 	status := influxdb.Status(n.status)
@@ -1495,6 +1759,39 @@ func (n *notificationEndpoint) valid() []validationErr {
 	}
 
 	switch n.kind {
+	case notificationKindOpsgenie:
+		if !n.apiKey.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointAPIKey,
+				Msg:   "must be provided",
+			})
+		}
+	case notificationKindSMTP:
+		if n.host == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointHost,
+				Msg:   "must be provided",
+			})
+		}
+		if n.port < 0 || n.port > 65535 {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPort,
+				Msg:   fmt.Sprintf("must be a valid port; got=%d", n.port),
+			})
+		}
+		if _, err := mail.ParseAddress(n.from); err != nil {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointFrom,
+				Msg:   "must be a valid email address",
+			})
+		}
+	case notificationKindTelegram:
+		if !n.token.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointToken,
+				Msg:   "must be provided",
+			})
+		}
 	case notificationKindPagerDuty:
 		if !n.routingKey.hasValue() {
 			failures = append(failures, validationErr{
@@ -1559,13 +1856,21 @@ func (n mapperNotificationEndpoints) Len() int {
 }
 
 const (
-	fieldNotificationRuleChannel         = "channel"
-	fieldNotificationRuleCurrentLevel    = "currentLevel"
-	fieldNotificationRuleEndpointName    = "endpointName"
-	fieldNotificationRuleMessageTemplate = "messageTemplate"
-	fieldNotificationRulePreviousLevel   = "previousLevel"
-	fieldNotificationRuleStatusRules     = "statusRules"
-	fieldNotificationRuleTagRules        = "tagRules"
+	fieldNotificationRuleChannel               = "channel"
+	fieldNotificationRuleCurrentLevel          = "currentLevel"
+	fieldNotificationRuleDisableWebPagePreview = "disableWebPagePreview"
+	fieldNotificationRuleEndpointName          = "endpointName"
+	fieldNotificationRuleEscalateAfter         = "escalateAfter"
+	fieldNotificationRuleMessageTemplate       = "messageTemplate"
+	fieldNotificationRuleParseMode             = "parseMode"
+	fieldNotificationRulePreviousLevel         = "previousLevel"
+	fieldNotificationRuleRenotify              = "renotify"
+	fieldNotificationRuleStatusRules           = "statusRules"
+	fieldNotificationRuleSubjectTemplate       = "subjectTemplate"
+	fieldNotificationRuleTagRules              = "tagRules"
+	fieldNotificationRuleTags                  = "tags"
+	fieldNotificationRuleTitle                 = "title"
+	fieldNotificationRuleTo                    = "to"
 )
 
 type notificationRule struct {
@@ -1573,14 +1878,22 @@ type notificationRule struct {
 	orgID influxdb.ID
 	name  string
 
-	channel     string
-	description string
-	every       time.Duration
-	msgTemplate string
-	offset      time.Duration
-	status      string
-	statusRules []struct{ curLvl, prevLvl string }
-	tagRules    []struct{ k, v, op string }
+	channel        string
+	description    string
+	disablePreview bool
+	escalateAfter  time.Duration
+	every          time.Duration
+	msgTemplate    string
+	offset         time.Duration
+	parseMode      string
+	renotify       time.Duration
+	status         string
+	statusRules    []struct{ curLvl, prevLvl string }
+	subjTemplate   string
+	tagRules       []struct{ k, v, op string }
+	tags           []string
+	title          string
+	to             []string
 
 	endpointID   influxdb.ID
 	endpointName string
@@ -1625,9 +1938,11 @@ func (r *notificationRule) summarize() SummaryNotificationRule {
 		EndpointType:      r.endpointType,
 		Description:       r.description,
 		Every:             r.every.String(),
+		EscalateAfter:     durToStr(r.escalateAfter),
 		LabelAssociations: toSummaryLabels(r.labels...),
 		Offset:            r.offset.String(),
 		MessageTemplate:   r.msgTemplate,
+		Renotify:          durToStr(r.renotify),
 		Status:            r.Status(),
 		StatusRules:       toSummaryStatusRules(r.statusRules),
 		TagRules:          toSummaryTagRules(r.tagRules),
@@ -1644,6 +1959,12 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 		Every:       toNotificationDuration(r.every),
 		Offset:      toNotificationDuration(r.offset),
 	}
+	if r.renotify > 0 {
+		base.Renotify = toNotificationDuration(r.renotify)
+	}
+	if r.escalateAfter > 0 {
+		base.EscalateAfter = toNotificationDuration(r.escalateAfter)
+	}
 	for _, sr := range r.statusRules {
 		var prevLvl *notification.CheckLevel
 		if lvl := notification.ParseCheckLevel(sr.prevLvl); lvl != notification.Unknown {
@@ -1679,6 +2000,33 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 			Channel:         r.channel,
 			MessageTemplate: r.msgTemplate,
 		}
+	case "opsgenie":
+		return &rule.Opsgenie{
+			Base:            base,
+			MessageTemplate: r.msgTemplate,
+			Tags:            r.tags,
+		}
+	case "smtp":
+		return &rule.SMTP{
+			Base:            base,
+			To:              r.to,
+			SubjectTemplate: r.subjTemplate,
+			BodyTemplate:    r.msgTemplate,
+		}
+	case "teams":
+		return &rule.Teams{
+			Base:            base,
+			Title:           r.title,
+			MessageTemplate: r.msgTemplate,
+		}
+	case "telegram":
+		return &rule.Telegram{
+			Base:                  base,
+			Channel:               r.channel,
+			MessageTemplate:       r.msgTemplate,
+			ParseMode:             r.parseMode,
+			DisableWebPagePreview: r.disablePreview,
+		}
 	}
 	return nil
 }
@@ -1697,6 +2045,12 @@ func (r *notificationRule) valid() []validationErr {
 			Msg:   "must be provided",
 		})
 	}
+	if r.renotify > 0 && r.renotify < r.every {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldNotificationRuleRenotify,
+			Msg:   "must not be less than every",
+		})
+	}
 	if status := r.Status(); status != influxdb.Active && status != influxdb.Inactive {
 		vErrs = append(vErrs, validationErr{
 			Field: fieldStatus,
@@ -519,6 +519,22 @@ func (p *Pkg) graphBuckets() *parseErr {
 				})
 			}
 		}
+		if dp, ok := ifaceToResource(r[fieldBucketDownsamplePolicy]); ok {
+			aggs := dp.mapStrStr(fieldDownsampleAggregates)
+			bkt.DownsamplePolicy = &downsamplePolicy{
+				sourceBucketName: strings.TrimSpace(dp.stringShort(fieldDownsampleSourceBucket)),
+				every:            dp.durationShort(fieldEvery),
+				lookback:         dp.durationShort(fieldDownsampleLookback),
+				offset:           dp.durationShort(fieldOffset),
+				aggregates: influxdb.DownsampleAggregates{
+					Float:    aggs[influxdb.DownsampleFieldTypeFloat],
+					Integer:  aggs[influxdb.DownsampleFieldTypeInteger],
+					Unsigned: aggs[influxdb.DownsampleFieldTypeUnsigned],
+					Boolean:  aggs[influxdb.DownsampleFieldTypeBoolean],
+					String:   aggs[influxdb.DownsampleFieldTypeString],
+				},
+			}
+		}
 
 		failures := p.parseNestedLabels(r, func(l *label) error {
 			bkt.labels = append(bkt.labels, l)
@@ -561,6 +577,8 @@ func (p *Pkg) graphChecks() *parseErr {
 	}{
 		{kind: KindCheckThreshold, checkKind: checkKindThreshold},
 		{kind: KindCheckDeadman, checkKind: checkKindDeadman},
+		{kind: KindCheckAnomaly, checkKind: checkKindAnomaly},
+		{kind: KindCheckCustom, checkKind: checkKindCustom},
 	}
 	var pErr parseErr
 	for _, k := range checkKinds {
@@ -578,6 +596,7 @@ func (p *Pkg) graphChecks() *parseErr {
 				description:   r.stringShort(fieldDescription),
 				every:         r.durationShort(fieldEvery),
 				level:         r.stringShort(fieldLevel),
+				method:        strings.TrimSpace(r.stringShort(fieldCheckMethod)),
 				offset:        r.durationShort(fieldOffset),
 				query:         strings.TrimSpace(r.stringShort(fieldQuery)),
 				reportZero:    r.boolShort(fieldCheckReportZero),
@@ -585,6 +604,7 @@ func (p *Pkg) graphChecks() *parseErr {
 				status:        normStr(r.stringShort(fieldStatus)),
 				statusMessage: r.stringShort(fieldCheckStatusMessageTemplate),
 				timeSince:     r.durationShort(fieldCheckTimeSince),
+				window:        r.durationShort(fieldCheckWindow),
 			}
 			for _, tagRes := range r.slcResource(fieldCheckTags) {
 				ch.tags = append(ch.tags, struct{ k, v string }{
@@ -602,6 +622,12 @@ func (p *Pkg) graphChecks() *parseErr {
 					val:        th.float64Short(fieldValue),
 				})
 			}
+			for _, lvl := range r.slcResource(fieldCheckLevels) {
+				ch.anomalyLevels = append(ch.anomalyLevels, anomalyLevel{
+					level: strings.TrimSpace(strings.ToUpper(lvl.stringShort(fieldLevel))),
+					val:   lvl.float64Short(fieldValue),
+				})
+			}
 
 			failures := p.parseNestedLabels(r, func(l *label) error {
 				ch.labels = append(ch.labels, l)
@@ -676,6 +702,22 @@ func (p *Pkg) graphNotificationEndpoints() *parseErr {
 			kind:             KindNotificationEndpointSlack,
 			notificationKind: notificationKindSlack,
 		},
+		{
+			kind:             KindNotificationEndpointOpsgenie,
+			notificationKind: notificationKindOpsgenie,
+		},
+		{
+			kind:             KindNotificationEndpointSMTP,
+			notificationKind: notificationKindSMTP,
+		},
+		{
+			kind:             KindNotificationEndpointTeams,
+			notificationKind: notificationKindTeams,
+		},
+		{
+			kind:             KindNotificationEndpointTelegram,
+			notificationKind: notificationKindTelegram,
+		},
 	}
 
 	var pErr parseErr
@@ -692,9 +734,13 @@ func (p *Pkg) graphNotificationEndpoints() *parseErr {
 				kind:        nk.notificationKind,
 				name:        r.Name(),
 				description: r.stringShort(fieldDescription),
+				apiKey:      r.references(fieldNotificationEndpointAPIKey),
+				from:        r.stringShort(fieldNotificationEndpointFrom),
+				host:        r.stringShort(fieldNotificationEndpointHost),
 				method:      strings.TrimSpace(strings.ToUpper(r.stringShort(fieldNotificationEndpointHTTPMethod))),
 				httpType:    normStr(r.stringShort(fieldType)),
 				password:    r.references(fieldNotificationEndpointPassword),
+				port:        r.intShort(fieldNotificationEndpointPort),
 				routingKey:  r.references(fieldNotificationEndpointRoutingKey),
 				status:      normStr(r.stringShort(fieldStatus)),
 				token:       r.references(fieldNotificationEndpointToken),
@@ -708,7 +754,7 @@ func (p *Pkg) graphNotificationEndpoints() *parseErr {
 			})
 			sort.Sort(endpoint.labels)
 
-			refs := []references{endpoint.password, endpoint.routingKey, endpoint.token, endpoint.username}
+			refs := []references{endpoint.apiKey, endpoint.password, endpoint.routingKey, endpoint.token, endpoint.username}
 			for _, ref := range refs {
 				if secret := ref.Secret; secret != "" {
 					p.mSecrets[secret] = false
@@ -732,14 +778,22 @@ func (p *Pkg) graphNotificationRules() *parseErr {
 	p.mNotificationRules = make([]*notificationRule, 0)
 	return p.eachResource(KindNotificationRule, 1, func(r Resource) []validationErr {
 		rule := &notificationRule{
-			name:         r.Name(),
-			endpointName: r.stringShort(fieldNotificationRuleEndpointName),
-			description:  r.stringShort(fieldDescription),
-			channel:      r.stringShort(fieldNotificationRuleChannel),
-			every:        r.durationShort(fieldEvery),
-			msgTemplate:  r.stringShort(fieldNotificationRuleMessageTemplate),
-			offset:       r.durationShort(fieldOffset),
-			status:       normStr(r.stringShort(fieldStatus)),
+			name:           r.Name(),
+			endpointName:   r.stringShort(fieldNotificationRuleEndpointName),
+			description:    r.stringShort(fieldDescription),
+			channel:        r.stringShort(fieldNotificationRuleChannel),
+			disablePreview: r.boolShort(fieldNotificationRuleDisableWebPagePreview),
+			escalateAfter:  r.durationShort(fieldNotificationRuleEscalateAfter),
+			every:          r.durationShort(fieldEvery),
+			msgTemplate:    r.stringShort(fieldNotificationRuleMessageTemplate),
+			offset:         r.durationShort(fieldOffset),
+			parseMode:      r.stringShort(fieldNotificationRuleParseMode),
+			renotify:       r.durationShort(fieldNotificationRuleRenotify),
+			status:         normStr(r.stringShort(fieldStatus)),
+			subjTemplate:   r.stringShort(fieldNotificationRuleSubjectTemplate),
+			tags:           r.slcStr(fieldNotificationRuleTags),
+			title:          r.stringShort(fieldNotificationRuleTitle),
+			to:             r.slcStr(fieldNotificationRuleTo),
 		}
 
 		for _, sRule := range r.slcResource(fieldNotificationRuleStatusRules) {
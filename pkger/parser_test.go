@@ -211,6 +211,97 @@ spec:
 				testPkgErrors(t, KindBucket, tt)
 			}
 		})
+
+		t.Run("with downsample policy should be valid", func(t *testing.T) {
+			testfileRunner(t, "testdata/bucket_downsample", func(t *testing.T, pkg *Pkg) {
+				buckets := pkg.Summary().Buckets
+				require.Len(t, buckets, 2)
+
+				assert.Nil(t, buckets[1].DownsamplePolicy)
+
+				actual := buckets[0]
+				expectedBucket := SummaryBucket{
+					Name: "rucket_hourly",
+					DownsamplePolicy: &SummaryDownsamplePolicy{
+						SourceBucketName: "rucket_raw",
+						Every:            time.Hour,
+						Lookback:         3 * time.Hour,
+						Offset:           5 * time.Minute,
+						Aggregates: influxdb.DownsampleAggregates{
+							Float:   "mean",
+							Integer: "sum",
+							Boolean: "last",
+						},
+					},
+				}
+				assert.Equal(t, expectedBucket, actual)
+			})
+		})
+
+		t.Run("handles bad downsample policy", func(t *testing.T) {
+			tests := []testPkgResourceError{
+				{
+					name:      "missing source bucket",
+					valFields: []string{fieldBucketDownsamplePolicy + "." + fieldDownsampleSourceBucket},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: buck_1
+      downsamplePolicy:
+        every: 1h
+        aggregates:
+          float: mean
+`,
+				},
+				{
+					name:      "downsampled from itself",
+					valFields: []string{fieldBucketDownsamplePolicy + "." + fieldDownsampleSourceBucket},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: buck_1
+      downsamplePolicy:
+        sourceBucket: buck_1
+        every: 1h
+        aggregates:
+          float: mean
+`,
+				},
+				{
+					name:      "invalid aggregate",
+					valFields: []string{fieldBucketDownsamplePolicy + "." + fieldBucketDownsamplePolicy},
+					pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      first_bucket_package
+  pkgVersion:   1
+spec:
+  resources:
+    - kind: Bucket
+      name: buck_1
+      downsamplePolicy:
+        sourceBucket: buck_0
+        every: 1h
+        aggregates:
+          string: mean
+`,
+				},
+			}
+
+			for _, tt := range tests {
+				testPkgErrors(t, KindBucket, tt)
+			}
+		})
 	})
 
 	t.Run("pkg with a label", func(t *testing.T) {
@@ -448,7 +539,7 @@ spec:
 	t.Run("pkg with checks", func(t *testing.T) {
 		testfileRunner(t, "testdata/checks", func(t *testing.T, pkg *Pkg) {
 			sum := pkg.Summary()
-			require.Len(t, sum.Checks, 2)
+			require.Len(t, sum.Checks, 4)
 
 			check1 := sum.Checks[0]
 			thresholdCheck, ok := check1.Check.(*icheck.Threshold)
@@ -519,6 +610,44 @@ spec:
 			assert.True(t, deadmanCheck.ReportZero)
 			assert.Len(t, check2.LabelAssociations, 1)
 
+			check3 := sum.Checks[2]
+			anomalyCheck, ok := check3.Check.(*icheck.Anomaly)
+			require.Truef(t, ok, "got: %#v", check3)
+
+			expectedBase = icheck.Base{
+				Name:                  "check_2",
+				Description:           "desc_2",
+				Every:                 mustDuration(t, 5*time.Minute),
+				Offset:                mustDuration(t, 30*time.Second),
+				StatusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }",
+			}
+			expectedBase.Query.Text = "from(bucket: \"rucket_1\")\n  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)\n  |> filter(fn: (r) => r._measurement == \"cpu\")\n  |> filter(fn: (r) => r._field == \"usage_idle\")\n  |> aggregateWindow(every: 1m, fn: mean)\n  |> yield(name: \"mean\")"
+			assert.Equal(t, expectedBase, anomalyCheck.Base)
+			assert.Equal(t, icheck.AnomalyDeviation, anomalyCheck.Method)
+			assert.Equal(t, mustDuration(t, time.Hour), anomalyCheck.Window)
+			expectedLevels := []icheck.AnomalyLevel{
+				{Level: notification.Critical, Value: 3.0},
+				{Level: notification.Warn, Value: 2.5},
+			}
+			assert.Equal(t, expectedLevels, anomalyCheck.Levels)
+			assert.Equal(t, influxdb.Active, check3.Status)
+			assert.Len(t, check3.LabelAssociations, 1)
+
+			check4 := sum.Checks[3]
+			customCheck, ok := check4.Check.(*icheck.Custom)
+			require.Truef(t, ok, "got: %#v", check4)
+
+			expectedBase = icheck.Base{
+				Name:        "check_3",
+				Description: "desc_3",
+				Every:       mustDuration(t, 5*time.Minute),
+				Offset:      mustDuration(t, 20*time.Second),
+			}
+			expectedBase.Query.Text = "import \"influxdata/influxdb/monitor\"\n\nfrom(bucket: \"rucket_1\")\n  |> range(start: -5m)\n  |> filter(fn: (r) => r._measurement == \"cpu\")\n  |> monitor.check(data: check, messageFn: (r) => \"cpu\", crit: (r) => r._value > 90.0)"
+			assert.Equal(t, expectedBase, customCheck.Base)
+			assert.Equal(t, influxdb.Active, check4.Status)
+			assert.Len(t, check4.LabelAssociations, 1)
+
 			containsLabelMappings(t, sum.LabelMappings,
 				labelMapping{
 					labelName: "label_1",
@@ -530,6 +659,16 @@ spec:
 					resName:   "check_1",
 					resType:   influxdb.ChecksResourceType,
 				},
+				labelMapping{
+					labelName: "label_1",
+					resName:   "check_2",
+					resType:   influxdb.ChecksResourceType,
+				},
+				labelMapping{
+					labelName: "label_1",
+					resName:   "check_3",
+					resType:   influxdb.ChecksResourceType,
+				},
 			)
 		})
 
@@ -893,6 +1032,113 @@ spec:
           name: label_1
         - kind: Label
           name: label_1
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testPkgResourceError{
+						name:           "invalid method",
+						validationErrs: 1,
+						valFields:      []string{fieldCheckMethod},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check_Anomaly
+      name: check_2
+      every: 5m
+      method: median
+      query:  >
+        from(bucket: "rucket_1") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+      statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+      levels:
+        - level: CRIT
+          value: 3.0
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testPkgResourceError{
+						name:           "window not larger than every",
+						validationErrs: 1,
+						valFields:      []string{fieldCheckWindow},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check_Anomaly
+      name: check_2
+      every: 5m
+      method: change
+      window: 5m
+      query:  >
+        from(bucket: "rucket_1") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+      statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+      levels:
+        - level: CRIT
+          value: 50.0
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testPkgResourceError{
+						name:           "no levels provided",
+						validationErrs: 1,
+						valFields:      []string{fieldCheckLevels},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check_Anomaly
+      name: check_2
+      every: 5m
+      method: lastWeek
+      query:  >
+        from(bucket: "rucket_1") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+      statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+      levels:
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testPkgResourceError{
+						name:           "invalid level value",
+						validationErrs: 1,
+						valFields:      []string{fieldValue},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Check_Anomaly
+      name: check_2
+      every: 5m
+      method: lastWeek
+      query:  >
+        from(bucket: "rucket_1") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+      statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
+      levels:
+        - level: WARN
+          value: -2.0
 `,
 					},
 				},
@@ -3249,6 +3495,59 @@ spec:
 			}
 		})
 
+		t.Run("with opsgenie, smtp, teams and telegram endpoints", func(t *testing.T) {
+			testfileRunner(t, "testdata/notification_endpoint_messaging.yml", func(t *testing.T, pkg *Pkg) {
+				expectedEndpoints := []influxdb.NotificationEndpoint{
+					&endpoint.Opsgenie{
+						Base: endpoint.Base{
+							Name:        "opsgenie_notification_endpoint",
+							Description: "opsgenie desc",
+							Status:      influxdb.TaskStatusActive,
+						},
+						URL:    "https://api.eu.opsgenie.com/v2/alerts",
+						APIKey: influxdb.SecretField{Value: strPtr("secret api-key")},
+					},
+					&endpoint.SMTP{
+						Base: endpoint.Base{
+							Name:        "smtp_notification_endpoint",
+							Description: "smtp desc",
+							Status:      influxdb.TaskStatusInactive,
+						},
+						Host:     "smtp.example.com",
+						Port:     587,
+						From:     "InfluxDB <alerts@example.com>",
+						Username: influxdb.SecretField{Value: strPtr("secret username")},
+						Password: influxdb.SecretField{Value: strPtr("secret password")},
+					},
+					&endpoint.Teams{
+						Base: endpoint.Base{
+							Name:        "teams_notification_endpoint",
+							Description: "teams desc",
+							Status:      influxdb.TaskStatusActive,
+						},
+						URL: "https://outlook.office.com/webhook/bip/piddy/boppidy",
+					},
+					&endpoint.Telegram{
+						Base: endpoint.Base{
+							Name:        "telegram_notification_endpoint",
+							Description: "telegram desc",
+							Status:      influxdb.TaskStatusActive,
+						},
+						Token: influxdb.SecretField{Value: strPtr("secret token")},
+					},
+				}
+
+				sum := pkg.Summary()
+				endpoints := sum.NotificationEndpoints
+				require.Len(t, endpoints, len(expectedEndpoints))
+				require.Len(t, sum.LabelMappings, len(expectedEndpoints))
+
+				for i := range expectedEndpoints {
+					assert.Equalf(t, expectedEndpoints[i], endpoints[i].NotificationEndpoint, "index=%d", i)
+				}
+			})
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			tests := []struct {
 				kind   Kind
@@ -3270,6 +3569,63 @@ spec:
   resources:
     - kind: Notification_Endpoint_Slack
       name: name1
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointOpsgenie,
+					resErr: testPkgResourceError{
+						name:           "missing opsgenie api key",
+						validationErrs: 1,
+						valFields:      []string{fieldNotificationEndpointAPIKey},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Notification_Endpoint_Opsgenie
+      name: name1
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointSMTP,
+					resErr: testPkgResourceError{
+						name:           "missing smtp host and from",
+						validationErrs: 1,
+						valFields:      []string{fieldNotificationEndpointHost, fieldNotificationEndpointFrom},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Notification_Endpoint_SMTP
+      name: name1
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointTelegram,
+					resErr: testPkgResourceError{
+						name:           "missing telegram token",
+						validationErrs: 1,
+						valFields:      []string{fieldNotificationEndpointToken},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Notification_Endpoint_Telegram
+      name: name1
 `,
 					},
 				},
@@ -3553,6 +3909,8 @@ spec:
 			assert.Equal(t, "desc_0", rule.Description)
 			assert.Equal(t, (10 * time.Minute).String(), rule.Every)
 			assert.Equal(t, (30 * time.Second).String(), rule.Offset)
+			assert.Equal(t, time.Hour.String(), rule.Renotify)
+			assert.Equal(t, (15 * time.Minute).String(), rule.EscalateAfter)
 			expectedMsgTempl := "Notification Rule: ${ r._notification_rule_name } triggered by check: ${ r._check_name }: ${ r._message }"
 			assert.Equal(t, expectedMsgTempl, rule.MessageTemplate)
 			assert.Equal(t, influxdb.Active, rule.Status)
@@ -3598,6 +3956,31 @@ spec:
       messageTemplate: "Notification Rule: ${ r._notification_rule_name } triggered by check: ${ r._check_name }: ${ r._message }"
       statusRules:
         - currentLevel: WARN
+`,
+					},
+				},
+				{
+					kind: KindNotificationRule,
+					resErr: testPkgResourceError{
+						name:           "renotify less than every",
+						validationErrs: 1,
+						valFields:      []string{fieldNotificationRuleRenotify},
+						pkgStr: `apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName:      pkg_name
+  pkgVersion:   1
+  description:  pack description
+spec:
+  resources:
+    - kind: Notification_Rule
+      name: rule_0
+      endpointName: endpoint_0
+      every: 10m
+      renotify: 5m
+      messageTemplate: "Notification Rule: ${ r._notification_rule_name } triggered by check: ${ r._check_name }: ${ r._message }"
+      statusRules:
+        - currentLevel: WARN
 `,
 					},
 				},
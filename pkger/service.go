@@ -270,15 +270,21 @@ func (s *Service) CreatePkg(ctx context.Context, setters ...CreatePkgSetFn) (*Pk
 	var kindPriorities = map[Kind]int{
 		KindLabel:                         1,
 		KindBucket:                        2,
-		KindCheckDeadman:                  3,
-		KindCheckThreshold:                4,
-		KindNotificationEndpointHTTP:      5,
-		KindNotificationEndpointPagerDuty: 6,
-		KindNotificationEndpointSlack:     7,
-		KindNotificationRule:              8,
-		KindVariable:                      9,
-		KindTelegraf:                      10,
-		KindDashboard:                     11,
+		KindCheckAnomaly:                  3,
+		KindCheckCustom:                   4,
+		KindCheckDeadman:                  5,
+		KindCheckThreshold:                6,
+		KindNotificationEndpointHTTP:      7,
+		KindNotificationEndpointOpsgenie:  8,
+		KindNotificationEndpointPagerDuty: 9,
+		KindNotificationEndpointSlack:     10,
+		KindNotificationEndpointSMTP:      11,
+		KindNotificationEndpointTeams:     12,
+		KindNotificationEndpointTelegram:  13,
+		KindNotificationRule:              14,
+		KindVariable:                      15,
+		KindTelegraf:                      16,
+		KindDashboard:                     17,
 	}
 
 	sort.Slice(pkg.Spec.Resources, func(i, j int) bool {
@@ -548,12 +554,17 @@ func (s *Service) resourceCloneToResource(ctx context.Context, r ResourceToClone
 	)
 	switch {
 	case r.Kind.is(KindBucket):
-		bkt, err := s.bucketSVC.FindBucketByID(ctx, r.ID)
+		bktRes, sourceRes, err := s.exportBucket(ctx, r)
 		if err != nil {
 			return nil, err
 		}
-		newResource = bucketToResource(*bkt, r.Name)
+		newResource = bktRes
+		if sourceRes != nil {
+			sidecarResources = append(sidecarResources, sourceRes)
+		}
 	case r.Kind.is(KindCheck),
+		r.Kind.is(KindCheckAnomaly),
+		r.Kind.is(KindCheckCustom),
 		r.Kind.is(KindCheckDeadman),
 		r.Kind.is(KindCheckThreshold):
 		ch, err := s.checkSVC.FindCheckByID(ctx, r.ID)
@@ -575,8 +586,12 @@ func (s *Service) resourceCloneToResource(ctx context.Context, r ResourceToClone
 		newResource = labelToResource(*l, r.Name)
 	case r.Kind.is(KindNotificationEndpoint),
 		r.Kind.is(KindNotificationEndpointHTTP),
+		r.Kind.is(KindNotificationEndpointOpsgenie),
 		r.Kind.is(KindNotificationEndpointPagerDuty),
-		r.Kind.is(KindNotificationEndpointSlack):
+		r.Kind.is(KindNotificationEndpointSlack),
+		r.Kind.is(KindNotificationEndpointSMTP),
+		r.Kind.is(KindNotificationEndpointTeams),
+		r.Kind.is(KindNotificationEndpointTelegram):
 		e, err := s.endpointSVC.FindNotificationEndpointByID(ctx, r.ID)
 		if err != nil {
 			return nil, err
@@ -621,6 +636,36 @@ func (s *Service) resourceCloneToResource(ctx context.Context, r ResourceToClone
 	return append(ass.newLableResources, append(sidecarResources, newResource)...), nil
 }
 
+// exportBucket returns the bucket along with the source bucket of its downsample
+// policy, if it has one.
+func (s *Service) exportBucket(ctx context.Context, r ResourceToClone) (Resource, Resource, error) {
+	bkt, err := s.bucketSVC.FindBucketByID(ctx, r.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bkt.DownsamplePolicy == nil {
+		return bucketToResource(*bkt, "", r.Name), nil, nil
+	}
+
+	source, err := s.bucketSVC.FindBucketByID(ctx, bkt.DownsamplePolicy.SourceBucketID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the source is exported the same way it is when it is cloned on its own,
+	// leaving out its own source bucket.
+	var sourceSourceName string
+	if source.DownsamplePolicy != nil {
+		sourceSource, err := s.bucketSVC.FindBucketByID(ctx, source.DownsamplePolicy.SourceBucketID)
+		if err != nil {
+			return nil, nil, err
+		}
+		sourceSourceName = sourceSource.Name
+	}
+
+	return bucketToResource(*bkt, source.Name, r.Name), bucketToResource(*source, sourceSourceName, ""), nil
+}
+
 func (s *Service) exportNotificationRule(ctx context.Context, r ResourceToClone) (Resource, Resource, error) {
 	rule, err := s.ruleSVC.FindNotificationRuleByID(ctx, r.ID)
 	if err != nil {
@@ -748,7 +793,16 @@ func (s *Service) dryRunBuckets(ctx context.Context, orgID influxdb.ID, pkg *Pkg
 		//  err isn't a not found (some other error)
 		case nil:
 			b.existing = existingBkt
-			mExistingBkts[b.Name()] = newDiffBucket(b, existingBkt)
+			diff := newDiffBucket(b, existingBkt)
+			// the existing policy is only of interest when the pkg manages it
+			if p := existingBkt.DownsamplePolicy; p != nil && b.DownsamplePolicy != nil {
+				sourceName := p.SourceBucketID.String()
+				if source, err := s.bucketSVC.FindBucketByID(ctx, p.SourceBucketID); err == nil {
+					sourceName = source.Name
+				}
+				diff.Old.DownsamplePolicy = newSummaryDownsamplePolicy(*p, sourceName)
+			}
+			mExistingBkts[b.Name()] = diff
 		default:
 			mExistingBkts[b.Name()] = newDiffBucket(b, nil)
 		}
@@ -1164,9 +1218,19 @@ func (s *Service) Apply(ctx context.Context, orgID, userID influxdb.ID, pkg *Pkg
 		}
 	}
 
+	// this has to be run after the above primary resources, because it relies on
+	// the source buckets already being applied.
+	app, err := s.applyDownsamplePoliciesGenerator(ctx, orgID, pkg.buckets())
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := coordinator.runTilEnd(ctx, orgID, userID, app); err != nil {
+		return Summary{}, err
+	}
+
 	// this has to be run after the above primary resources, because it relies on
 	// notification endpoints already being applied.
-	app, err := s.applyNotificationRulesGenerator(ctx, orgID, pkg.notificationRules())
+	app, err = s.applyNotificationRulesGenerator(ctx, orgID, pkg.notificationRules())
 	if err != nil {
 		return Summary{}, err
 	}
@@ -1286,6 +1350,112 @@ func (s *Service) applyBucket(ctx context.Context, b bucket) (influxdb.Bucket, e
 	return influxBucket, nil
 }
 
+func (s *Service) applyDownsamplePoliciesGenerator(ctx context.Context, orgID influxdb.ID, buckets []*bucket) (applier, error) {
+	var (
+		errs       applyErrs
+		sourceIDs  = make(map[string]influxdb.ID)
+		downsample []*bucket
+	)
+	for _, b := range buckets {
+		if b.DownsamplePolicy == nil {
+			continue
+		}
+
+		name := b.DownsamplePolicy.sourceBucketName
+		if _, ok := sourceIDs[name]; !ok {
+			source, err := s.bucketSVC.FindBucketByName(ctx, orgID, name)
+			if err != nil {
+				errs = append(errs, &applyErrBody{
+					name: b.Name(),
+					msg:  fmt.Sprintf("source bucket dependency does not exist; sourceBucket=%q", name),
+				})
+				continue
+			}
+			sourceIDs[name] = source.ID
+		}
+		downsample = append(downsample, b)
+	}
+
+	err := errs.toError("bucket", "failed to find dependency")
+	if err != nil {
+		return applier{}, err
+	}
+
+	return s.applyDownsamplePolicies(downsample, sourceIDs), nil
+}
+
+func (s *Service) applyDownsamplePolicies(buckets []*bucket, sourceIDs map[string]influxdb.ID) applier {
+	const resource = "bucket_downsample_policy"
+
+	mutex := new(doMutex)
+	rollbackBuckets := make([]*bucket, 0, len(buckets))
+
+	createFn := func(ctx context.Context, i int, orgID, userID influxdb.ID) *applyErrBody {
+		var b bucket
+		mutex.Do(func() {
+			b = *buckets[i]
+		})
+
+		sourceID := sourceIDs[b.DownsamplePolicy.sourceBucketName]
+		if !b.shouldApplyDownsamplePolicy(sourceID) {
+			return nil
+		}
+
+		policy := b.DownsamplePolicy.toInfluxPolicy(sourceID)
+		_, err := s.bucketSVC.UpdateBucket(ctx, b.ID(), influxdb.BucketUpdate{
+			DownsamplePolicy: &policy,
+		})
+		if err != nil {
+			return &applyErrBody{
+				name: b.Name(),
+				msg:  err.Error(),
+			}
+		}
+
+		mutex.Do(func() {
+			rollbackBuckets = append(rollbackBuckets, buckets[i])
+		})
+
+		return nil
+	}
+
+	return applier{
+		creater: creater{
+			entries: len(buckets),
+			fn:      createFn,
+		},
+		rollbacker: rollbacker{
+			resource: resource,
+			fn:       func(_ influxdb.ID) error { return s.rollbackDownsamplePolicies(rollbackBuckets) },
+		},
+	}
+}
+
+func (s *Service) rollbackDownsamplePolicies(buckets []*bucket) error {
+	var errs []string
+	for _, b := range buckets {
+		// new buckets are deleted when the buckets are rolled back, removing
+		// their policy first allows their source buckets to be deleted as well.
+		policy := &influxdb.DownsamplePolicy{}
+		if b.existing != nil && b.existing.DownsamplePolicy != nil {
+			policy = b.existing.DownsamplePolicy
+		}
+
+		_, err := s.bucketSVC.UpdateBucket(context.Background(), b.ID(), influxdb.BucketUpdate{
+			DownsamplePolicy: policy,
+		})
+		if err != nil {
+			errs = append(errs, b.ID().String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf(`bucket_ids=[%s] err="unable to restore downsample policy"`, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
 func (s *Service) applyChecks(checks []*check) applier {
 	const resource = "check"
 
@@ -1573,6 +1743,8 @@ func (s *Service) applyNotificationEndpoints(endpoints []*notificationEndpoint)
 			endpoints[i].id = influxEndpoint.GetID()
 			for _, secret := range influxEndpoint.SecretFields() {
 				switch {
+				case strings.HasSuffix(secret.Key, "-api-key"):
+					endpoints[i].apiKey.Secret = secret.Key
 				case strings.HasSuffix(secret.Key, "-routing-key"):
 					endpoints[i].routingKey.Secret = secret.Key
 				case strings.HasSuffix(secret.Key, "-token"):
@@ -2164,7 +2336,10 @@ func (r *rollbackCoordinator) rollback(l *zap.Logger, err *error, orgID influxdb
 		return
 	}
 
-	for _, r := range r.rollbacks {
+	// roll back in the reverse order the resources were applied, so that the
+	// resources depending on others are removed before their dependencies.
+	for i := len(r.rollbacks) - 1; i >= 0; i-- {
+		r := r.rollbacks[i]
 		if err := r.fn(orgID); err != nil {
 			l.Error("failed to delete "+r.resource, zap.Error(err))
 		}
@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"regexp"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -138,7 +139,7 @@ func TestService(t *testing.T) {
 				require.NoError(t, err)
 
 				checks := diff.Checks
-				require.Len(t, checks, 2)
+				require.Len(t, checks, 4)
 				check0 := checks[0]
 				assert.True(t, check0.IsNew())
 				assert.Equal(t, "check_0", check0.Name)
@@ -488,6 +489,74 @@ func TestService(t *testing.T) {
 				})
 			})
 
+			t.Run("applies downsample policy after its source bucket", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_downsample", func(t *testing.T, pkg *Pkg) {
+					var (
+						mu      sync.Mutex
+						created = make(map[string]*influxdb.Bucket)
+					)
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+						mu.Lock()
+						defer mu.Unlock()
+						b.ID = influxdb.ID(len(created) + 1)
+						created[b.Name] = b
+						return nil
+					}
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+						mu.Lock()
+						defer mu.Unlock()
+						if b, ok := created[name]; ok {
+							return b, nil
+						}
+						return nil, errors.New("not found")
+					}
+					var updates []influxdb.BucketUpdate
+					fakeBktSVC.UpdateBucketFn = func(_ context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+						mu.Lock()
+						defer mu.Unlock()
+						updates = append(updates, upd)
+						return &influxdb.Bucket{ID: id}, nil
+					}
+
+					svc := newTestService(WithBucketSVC(fakeBktSVC))
+
+					sum, err := svc.Apply(context.TODO(), influxdb.ID(9000), 0, pkg)
+					require.NoError(t, err)
+					require.Len(t, sum.Buckets, 2)
+
+					require.Len(t, updates, 1)
+					expected := &influxdb.DownsamplePolicy{
+						SourceBucketID: created["rucket_raw"].ID,
+						Every:          influxdb.Duration{Duration: time.Hour},
+						Lookback:       influxdb.Duration{Duration: 3 * time.Hour},
+						Offset:         influxdb.Duration{Duration: 5 * time.Minute},
+						Aggregates: influxdb.DownsampleAggregates{
+							Float:   "mean",
+							Integer: "sum",
+							Boolean: "last",
+						},
+					}
+					assert.Equal(t, expected, updates[0].DownsamplePolicy)
+				})
+			})
+
+			t.Run("errors when the downsample source bucket does not exist", func(t *testing.T) {
+				testfileRunner(t, "testdata/bucket_downsample", func(t *testing.T, pkg *Pkg) {
+					fakeBktSVC := mock.NewBucketService()
+					fakeBktSVC.FindBucketByNameFn = func(_ context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+						return nil, errors.New("not found")
+					}
+
+					svc := newTestService(WithBucketSVC(fakeBktSVC))
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), 0, pkg)
+					require.Error(t, err)
+
+					assert.Equal(t, 2, fakeBktSVC.DeleteBucketCalls.Count())
+				})
+			})
+
 			t.Run("rolls back all created buckets on an error", func(t *testing.T) {
 				testfileRunner(t, "testdata/bucket", func(t *testing.T, pkg *Pkg) {
 					fakeBktSVC := mock.NewBucketService()
@@ -533,7 +602,7 @@ func TestService(t *testing.T) {
 					sum, err := svc.Apply(context.TODO(), orgID, 0, pkg)
 					require.NoError(t, err)
 
-					require.Len(t, sum.Checks, 2)
+					require.Len(t, sum.Checks, 4)
 
 					containsWithID := func(t *testing.T, name string) {
 						for _, actualNotification := range sum.Checks {
@@ -548,7 +617,7 @@ func TestService(t *testing.T) {
 						assert.Fail(t, "did not find notification by name: "+name)
 					}
 
-					for _, expectedName := range []string{"check_0", "check_1"} {
+					for _, expectedName := range []string{"check_0", "check_1", "check_2", "check_3"} {
 						containsWithID(t, expectedName)
 					}
 				})
@@ -859,7 +928,7 @@ func TestService(t *testing.T) {
 				testLabelMappingFn(
 					t,
 					"testdata/checks.yml",
-					2, // 1 for each check
+					4, // 1 for each check
 					func() []ServiceSetterFn {
 						fakeCheckSVC := mock.NewCheckService()
 						fakeCheckSVC.CreateCheckFn = func(ctx context.Context, c influxdb.CheckCreate, id influxdb.ID) error {
@@ -1474,6 +1543,59 @@ func TestService(t *testing.T) {
 				}
 			})
 
+			t.Run("bucket with downsample policy", func(t *testing.T) {
+				source := &influxdb.Bucket{
+					ID:   3,
+					Name: "raw",
+				}
+				expected := &influxdb.Bucket{
+					ID:   4,
+					Name: "hourly",
+					DownsamplePolicy: &influxdb.DownsamplePolicy{
+						SourceBucketID: source.ID,
+						Every:          influxdb.Duration{Duration: time.Hour},
+						Lookback:       influxdb.Duration{Duration: 2 * time.Hour},
+						Aggregates:     influxdb.DownsampleAggregates{Float: "max"},
+						TaskID:         5,
+					},
+				}
+
+				bktSVC := mock.NewBucketService()
+				bktSVC.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+					switch id {
+					case source.ID:
+						return source, nil
+					case expected.ID:
+						return expected, nil
+					}
+					return nil, errors.New("uh ohhh, wrong id here: " + id.String())
+				}
+
+				svc := newTestService(WithBucketSVC(bktSVC), WithLabelSVC(mock.NewLabelService()))
+
+				resToClone := ResourceToClone{
+					Kind: KindBucket,
+					ID:   expected.ID,
+				}
+				pkg, err := svc.CreatePkg(context.TODO(), CreateWithExistingResources(resToClone))
+				require.NoError(t, err)
+
+				bkts := pkg.Summary().Buckets
+				require.Len(t, bkts, 2)
+
+				assert.Equal(t, "hourly", bkts[0].Name)
+				expectedPolicy := &SummaryDownsamplePolicy{
+					SourceBucketName: "raw",
+					Every:            time.Hour,
+					Lookback:         2 * time.Hour,
+					Aggregates:       influxdb.DownsampleAggregates{Float: "max"},
+				}
+				assert.Equal(t, expectedPolicy, bkts[0].DownsamplePolicy)
+
+				assert.Equal(t, "raw", bkts[1].Name)
+				assert.Nil(t, bkts[1].DownsamplePolicy)
+			})
+
 			t.Run("checks", func(t *testing.T) {
 				tests := []struct {
 					name     string
@@ -1531,6 +1653,24 @@ func TestService(t *testing.T) {
 							Level:      notification.Critical,
 						},
 					},
+					{
+						name: "anomaly",
+						expected: &icheck.Anomaly{
+							Base:   newThresholdBase(2),
+							Method: icheck.AnomalyChange,
+							Window: mustDuration(t, time.Hour),
+							Levels: []icheck.AnomalyLevel{
+								{Level: notification.Critical, Value: 50},
+								{Level: notification.Warn, Value: 20},
+							},
+						},
+					},
+					{
+						name: "custom",
+						expected: &icheck.Custom{
+							Base: newThresholdBase(3),
+						},
+					},
 				}
 
 				for _, tt := range tests {
@@ -166,3 +166,43 @@ func TestParseTagRule(t *testing.T) {
 		}
 	}
 }
+
+func TestTagRules(t *testing.T) {
+	cases := []struct {
+		str      string
+		tagRules []influxdb.TagRule
+	}{
+		{
+			str: ``,
+		},
+		{
+			str: `host=a`,
+			tagRules: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal},
+			},
+		},
+		{
+			str: `host=a and (region="us-west" and dc=1)`,
+			tagRules: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal},
+				{Tag: influxdb.Tag{Key: "region", Value: "us-west"}, Operator: influxdb.Equal},
+				{Tag: influxdb.Tag{Key: "dc", Value: "1"}, Operator: influxdb.Equal},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.str, func(t *testing.T) {
+			n, err := Parse(c.str)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tagRules, err := TagRules(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.tagRules, tagRules); diff != "" {
+				t.Errorf("tag rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
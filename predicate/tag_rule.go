@@ -82,3 +82,32 @@ func (n TagRuleNode) ToDataType() (*datatypes.Node, error) {
 		},
 	}, nil
 }
+
+// TagRules returns the tag rules of the predicate node n. The predicate is
+// satisfied if every tag rule is, as the only logical operator is AND.
+func TagRules(n Node) ([]influxdb.TagRule, error) {
+	switch n := n.(type) {
+	case nil:
+		return nil, nil
+	case TagRuleNode:
+		return []influxdb.TagRule{influxdb.TagRule(n)}, nil
+	case LogicalNode:
+		if _, err := n.Operator.Value(); err != nil {
+			return nil, err
+		}
+		var trs []influxdb.TagRule
+		for _, child := range n.Children {
+			childTRs, err := TagRules(child)
+			if err != nil {
+				return nil, err
+			}
+			trs = append(trs, childTRs...)
+		}
+		return trs, nil
+	default:
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("unsupported predicate node %T", n),
+		}
+	}
+}
@@ -43,6 +43,10 @@ func (a *authzSvc) UpdateAuthorization(context.Context, platform.ID, *platform.A
 	return nil, a.Err
 }
 
+func (a *authzSvc) RotateAuthorization(context.Context, platform.ID, platform.AuthorizationRotation) (*platform.Authorization, error) {
+	return nil, a.Err
+}
+
 func TestAuthorizationService_Metrics(t *testing.T) {
 	a := new(authzSvc)
 
@@ -0,0 +1,5 @@
+// Package prompb holds the messages of the remote write and read protocols of
+// Prometheus.
+package prompb
+
+//go:generate protoc -I ../../internal -I . --plugin ../../scripts/protoc-gen-gogofaster --gogofaster_out=. remote.proto
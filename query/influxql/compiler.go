@@ -27,6 +27,10 @@ type Compiler struct {
 	Query   string     `json:"query"`
 	Now     *time.Time `json:"now,omitempty"`
 
+	// FallbackToDBRP uses the bucket named `db/rp` when there is no mapping
+	// for the database and retention policy of a measurement.
+	FallbackToDBRP bool `json:"fallback_to_dbrp,omitempty"`
+
 	logicalPlannerOptions []plan.LogicalOption
 
 	dbrpMappingSvc platform.DBRPMappingService
@@ -55,6 +59,7 @@ func (c *Compiler) Compile(ctx context.Context) (flux.Program, error) {
 			DefaultDatabase:        c.DB,
 			DefaultRetentionPolicy: c.RP,
 			Now:                    now,
+			FallbackToDBRP:         c.FallbackToDBRP,
 		},
 	)
 	astPkg, err := transpiler.Transpile(ctx, c.Query)
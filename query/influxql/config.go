@@ -4,12 +4,18 @@ import (
 	"time"
 )
 
+// DefaultRetentionPolicy is the retention policy of the bucket named after a database
+// when the retention policy is not specified and there is no mapping for the database.
+const DefaultRetentionPolicy = "autogen"
+
 // Config modifies the behavior of the Transpiler.
 type Config struct {
 	DefaultDatabase        string
 	DefaultRetentionPolicy string
 	Now                    time.Time
-	Cluster                string
+	// Cluster restricts the dbrp mappings to the ones of the cluster.
+	// The mappings of any cluster are used if it is empty.
+	Cluster string
 	// FallbackToDBRP if true will use the naming convention of `db/rp`
 	// for a bucket name when an mapping is not found
 	FallbackToDBRP bool
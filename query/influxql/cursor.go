@@ -1,7 +1,11 @@
 package influxql
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -31,41 +35,259 @@ type varRefCursor struct {
 }
 
 // createVarRefCursor creates a new cursor from a variable reference using the sources
-// in the transpilerState.
+// in the transpilerState. The points of multiple sources are merged together.
 func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error) {
-	if len(t.stmt.Sources) != 1 {
-		// TODO(jsternberg): Support multiple sources.
-		return nil, errors.New("unimplemented: only one source is allowed")
+	exprs := make([]ast.Expression, 0, len(t.stmt.Sources))
+	for _, src := range t.stmt.Sources {
+		switch src := src.(type) {
+		case *influxql.Measurement:
+			expr, err := t.readFields(src, []*influxql.VarRef{ref})
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		case *influxql.SubQuery:
+			// A subquery without the column has no points for the variable.
+			expr, ok, err := t.subQuery(src, []*influxql.VarRef{ref})
+			if err != nil {
+				return nil, err
+			} else if ok {
+				exprs = append(exprs, readColumn(expr, ref.Val))
+			}
+		default:
+			return nil, fmt.Errorf("unimplemented: source must be a measurement or subquery, got %T", src)
+		}
+	}
+	if len(exprs) == 0 {
+		// InfluxDB 1.X returns no points for the variable instead.
+		return nil, fmt.Errorf("unimplemented: %s is not a column of any of the subqueries", ref)
 	}
+	return &varRefCursor{
+		expr: union(exprs),
+		ref:  ref,
+	}, nil
+}
 
-	// Only support a direct measurement. Subqueries are not supported yet.
-	mm, ok := t.stmt.Sources[0].(*influxql.Measurement)
-	if !ok {
-		return nil, errors.New("unimplemented: source must be a measurement")
+// readColumn reads the column of the results of a subquery as the default value column.
+func readColumn(in ast.Expression, column string) ast.Expression {
+	var property ast.PropertyKey
+	if strings.HasPrefix(column, "_") {
+		property = &ast.Identifier{Name: column}
+	} else {
+		property = &ast.StringLiteral{Value: column}
+	}
+	return &ast.PipeExpression{
+		Argument: in,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "map",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{
+							Name: "fn",
+						},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: &ast.Identifier{Name: "r"},
+							}},
+							Body: &ast.ObjectExpression{
+								With: &ast.Identifier{Name: "r"},
+								Properties: []*ast.Property{{
+									Key: &ast.Identifier{Name: execute.DefaultValueColLabel},
+									Value: &ast.MemberExpression{
+										Object:   &ast.Identifier{Name: "r"},
+										Property: property,
+									},
+								}},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// createFieldsCursor creates a new cursor that reads the variable references of a raw
+// query together. The fields are pivoted into columns named after the variables so
+// the tags of the series can still be accessed.
+func createFieldsCursor(t *transpilerState, refs []*influxql.VarRef) (cursor, error) {
+	exprs := make([]ast.Expression, 0, len(t.stmt.Sources))
+	for _, src := range t.stmt.Sources {
+		switch src := src.(type) {
+		case *influxql.Measurement:
+			expr, err := t.readFields(src, refs)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, &ast.PipeExpression{
+				Argument: expr,
+				Call: &ast.CallExpression{
+					Callee: &ast.Identifier{
+						Name: "pivot",
+					},
+					Arguments: []ast.Expression{
+						&ast.ObjectExpression{
+							Properties: []*ast.Property{
+								{
+									Key: &ast.Identifier{Name: "rowKey"},
+									Value: &ast.ArrayExpression{
+										Elements: []ast.Expression{
+											&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+										},
+									},
+								},
+								{
+									Key: &ast.Identifier{Name: "columnKey"},
+									Value: &ast.ArrayExpression{
+										Elements: []ast.Expression{
+											&ast.StringLiteral{Value: "_field"},
+										},
+									},
+								},
+								{
+									Key:   &ast.Identifier{Name: "valueColumn"},
+									Value: &ast.StringLiteral{Value: execute.DefaultValueColLabel},
+								},
+							},
+						},
+					},
+				},
+			})
+		case *influxql.SubQuery:
+			// The columns of the subquery are already named after the variables.
+			expr, _, err := t.subQuery(src, refs)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		default:
+			return nil, fmt.Errorf("unimplemented: source must be a measurement or subquery, got %T", src)
+		}
 	}
+	return &fieldsCursor{
+		expr: union(exprs),
+		refs: refs,
+	}, nil
+}
 
+// readFields reads the fields of the variable references from the measurement
+// within the time range of the current statement. Variables that are explicitly
+// tags are not read as fields.
+func (t *transpilerState) readFields(mm *influxql.Measurement, refs []*influxql.VarRef) (ast.Expression, error) {
 	// Create the from spec and add it to the list of operations.
 	from, err := t.from(mm)
 	if err != nil {
 		return nil, err
 	}
 
+	start, stop, err := t.timeRange()
+	if err != nil {
+		return nil, err
+	}
+	range_ := rangeCall(from, start, stop)
+
+	var fields ast.Expression
+	names := make(map[string]bool, len(refs))
+	for i := len(refs) - 1; i >= 0; i-- {
+		if ref := refs[i]; ref.Type != influxql.Tag && !names[ref.Val] {
+			names[ref.Val] = true
+			var expr ast.Expression = &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left: &ast.MemberExpression{
+					Object:   &ast.Identifier{Name: "r"},
+					Property: &ast.Identifier{Name: "_field"},
+				},
+				Right: &ast.StringLiteral{
+					Value: ref.Val,
+				},
+			}
+			if fields != nil {
+				expr = &ast.LogicalExpression{
+					Operator: ast.OrOperator,
+					Left:     expr,
+					Right:    fields,
+				}
+			}
+			fields = expr
+		}
+	}
+
+	var body ast.Expression = &ast.BinaryExpression{
+		Operator: ast.EqualOperator,
+		Left: &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: "_measurement"},
+		},
+		Right: &ast.StringLiteral{
+			Value: mm.Name,
+		},
+	}
+	if fields != nil {
+		body = &ast.LogicalExpression{
+			Operator: ast.AndOperator,
+			Left:     body,
+			Right:    fields,
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: range_,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "filter",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{
+							Key: &ast.Identifier{
+								Name: "fn",
+							},
+							Value: &ast.FunctionExpression{
+								Params: []*ast.Property{{
+									Key: &ast.Identifier{
+										Name: "r",
+									},
+								}},
+								Body: body,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// timeRange returns the bounds of the range that is read for the current
+// statement.
+func (t *transpilerState) timeRange() (start, stop time.Time, err error) {
 	valuer := influxql.NowValuer{Now: t.config.Now}
 	_, tr, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
 	if err != nil {
-		return nil, err
+		return time.Time{}, time.Time{}, err
 	}
 
 	// If the maximum is not set and we have a windowing function, then
-	// the end time will be set to now.
+	// the end time will be set to now. Otherwise, the maximum of the condition
+	// is inclusive while the stop of the range is exclusive.
+	stop = tr.MaxTime()
 	if tr.Max.IsZero() {
 		if window, err := t.stmt.GroupByInterval(); err == nil && window > 0 {
-			tr.Max = t.config.Now
+			stop = t.config.Now
 		}
+	} else {
+		stop = stop.Add(1)
 	}
+	return tr.MinTime(), stop, nil
+}
 
-	range_ := &ast.PipeExpression{
-		Argument: from,
+// rangeCall pipes the input into a range with the given bounds.
+func rangeCall(in ast.Expression, start, stop time.Time) ast.Expression {
+	return &ast.PipeExpression{
+		Argument: in,
 		Call: &ast.CallExpression{
 			Callee: &ast.Identifier{
 				Name: "range",
@@ -78,7 +300,7 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 								Name: "start",
 							},
 							Value: &ast.DateTimeLiteral{
-								Value: tr.MinTime().UTC(),
+								Value: start.UTC(),
 							},
 						},
 						{
@@ -86,7 +308,7 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 								Name: "stop",
 							},
 							Value: &ast.DateTimeLiteral{
-								Value: tr.MaxTime().UTC(),
+								Value: stop.UTC(),
 							},
 						},
 					},
@@ -94,60 +316,232 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 			},
 		},
 	}
+}
 
-	expr := &ast.PipeExpression{
-		Argument: range_,
+// subQuery transpiles the subquery with the time range of the current statement.
+// A wildcard in the subquery selects the variables that are read from it. This
+// also returns whether any of the variables is a column of the subquery.
+func (t *transpilerState) subQuery(sub *influxql.SubQuery, refs []*influxql.VarRef) (ast.Expression, bool, error) {
+	if len(sub.Statement.SortFields) > 0 && sub.Statement.TimeAscending() != t.stmt.TimeAscending() {
+		return nil, false, errors.New("subqueries must be ordered in the same direction as the query itself")
+	}
+
+	// Restrict the subquery to the time range of the outer query.
+	valuer := influxql.NowValuer{Now: t.config.Now}
+	_, tr, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
+	if err != nil {
+		return nil, false, err
+	}
+	stmt := sub.Statement.Clone()
+	if !tr.Min.IsZero() {
+		stmt.Condition = timeCondition(stmt.Condition, influxql.GTE, tr.Min)
+	}
+	if !tr.Max.IsZero() {
+		stmt.Condition = timeCondition(stmt.Condition, influxql.LTE, tr.Max)
+	}
+
+	fields := make(influxql.Fields, 0, len(stmt.Fields))
+	for _, f := range stmt.Fields {
+		if _, ok := f.Expr.(*influxql.Wildcard); !ok {
+			fields = append(fields, f)
+			continue
+		}
+		for _, ref := range refs {
+			fields = append(fields, &influxql.Field{
+				Expr: &influxql.VarRef{Val: ref.Val, Type: ref.Type},
+			})
+		}
+	}
+	stmt.Fields = fields
+
+	found := false
+	columns := make(map[string]bool)
+	for _, name := range stmt.ColumnNames() {
+		columns[name] = true
+	}
+	for _, ref := range refs {
+		found = found || columns[ref.Val]
+	}
+
+	// The subquery is grouped by the dimensions of the current statement too.
+	if !hasWildcard(stmt.Dimensions) {
+		dimensions := make(map[string]bool, len(stmt.Dimensions))
+		for _, d := range stmt.Dimensions {
+			if ref, ok := d.Expr.(*influxql.VarRef); ok {
+				dimensions[ref.Val] = true
+			}
+		}
+		for _, d := range t.stmt.Dimensions {
+			switch expr := d.Expr.(type) {
+			case *influxql.VarRef:
+				if !dimensions[expr.Val] {
+					stmt.Dimensions = append(stmt.Dimensions, &influxql.Dimension{
+						Expr: &influxql.VarRef{Val: expr.Val, Type: expr.Type},
+					})
+				}
+			case *influxql.Wildcard:
+				stmt.Dimensions = append(stmt.Dimensions, &influxql.Dimension{
+					Expr: &influxql.Wildcard{},
+				})
+			}
+		}
+	}
+
+	// The points of a raw query keep the tags of their series, so the tags
+	// the current statement filters by are selected too.
+	if stmt.IsRawQuery {
+		for _, ref := range t.tagRefs() {
+			if !columns[ref.Val] {
+				stmt.Fields = append(stmt.Fields, &influxql.Field{Expr: ref})
+				columns[ref.Val] = true
+			}
+		}
+	}
+
+	start, stop, err := t.timeRange()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Transpile the subquery with its own statement and restore the
+	// statement of the current query afterwards.
+	outer := t.stmt
+	defer func() { t.stmt = outer }()
+
+	cur, err := t.transpileSelect(context.TODO(), stmt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The points of the subquery are within the range of the current statement
+	// so the points of different subqueries are grouped together.
+	bound := func(label string, v time.Time) *ast.Property {
+		return &ast.Property{
+			Key:   &ast.Identifier{Name: label},
+			Value: &ast.DateTimeLiteral{Value: v.UTC()},
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: cur.Expr(),
 		Call: &ast.CallExpression{
 			Callee: &ast.Identifier{
-				Name: "filter",
+				Name: "map",
 			},
 			Arguments: []ast.Expression{
 				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "fn",
-							},
-							Value: &ast.FunctionExpression{
-								Params: []*ast.Property{{
-									Key: &ast.Identifier{
-										Name: "r",
-									},
-								}},
-								Body: &ast.LogicalExpression{
-									Operator: ast.AndOperator,
-									Left: &ast.BinaryExpression{
-										Operator: ast.EqualOperator,
-										Left: &ast.MemberExpression{
-											Object:   &ast.Identifier{Name: "r"},
-											Property: &ast.Identifier{Name: "_measurement"},
-										},
-										Right: &ast.StringLiteral{
-											Value: mm.Name,
-										},
-									},
-									Right: &ast.BinaryExpression{
-										Operator: ast.EqualOperator,
-										Left: &ast.MemberExpression{
-											Object:   &ast.Identifier{Name: "r"},
-											Property: &ast.Identifier{Name: "_field"},
-										},
-										Right: &ast.StringLiteral{
-											Value: ref.Val,
-										},
-									},
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{
+							Name: "fn",
+						},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: &ast.Identifier{Name: "r"},
+							}},
+							Body: &ast.ObjectExpression{
+								With: &ast.Identifier{Name: "r"},
+								Properties: []*ast.Property{
+									bound(execute.DefaultStartColLabel, start),
+									bound(execute.DefaultStopColLabel, stop),
 								},
 							},
 						},
-					},
+					}},
 				},
 			},
 		},
+	}, found, nil
+}
+
+// tagRefs returns the variable references of the condition of the current
+// statement, which may refer to tags.
+func (t *transpilerState) tagRefs() []*influxql.VarRef {
+	var refs []*influxql.VarRef
+	influxql.WalkFunc(t.stmt.Condition, func(n influxql.Node) {
+		if ref, ok := n.(*influxql.VarRef); ok && ref.Val != "time" {
+			refs = append(refs, &influxql.VarRef{Val: ref.Val, Type: ref.Type})
+		}
+	})
+	return refs
+}
+
+// valueColumns returns the names of the columns that may hold the values of
+// the points read for the current statement. These are the variables of the
+// statement and the columns of its subqueries.
+func (t *transpilerState) valueColumns() []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	influxql.WalkFunc(t.stmt.Fields, func(n influxql.Node) {
+		if ref, ok := n.(*influxql.VarRef); ok && ref.Val != "time" {
+			add(ref.Val)
+		}
+	})
+	for _, src := range t.stmt.Sources {
+		if sub, ok := src.(*influxql.SubQuery); ok {
+			for _, name := range sub.Statement.ColumnNames() {
+				if name != "time" {
+					add(name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// hasWildcard returns true if one of the dimensions is a wildcard.
+func hasWildcard(dimensions influxql.Dimensions) bool {
+	for _, d := range dimensions {
+		if _, ok := d.Expr.(*influxql.Wildcard); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// union merges the tables of the expressions if there is more than one.
+func union(exprs []ast.Expression) ast.Expression {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	return &ast.CallExpression{
+		Callee: &ast.Identifier{
+			Name: "union",
+		},
+		Arguments: []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: []*ast.Property{{
+					Key: &ast.Identifier{
+						Name: "tables",
+					},
+					Value: &ast.ArrayExpression{
+						Elements: exprs,
+					},
+				}},
+			},
+		},
+	}
+}
+
+// timeCondition adds a comparison of the time to the condition.
+func timeCondition(cond influxql.Expr, op influxql.Token, t time.Time) influxql.Expr {
+	expr := &influxql.BinaryExpr{
+		Op:  op,
+		LHS: &influxql.VarRef{Val: "time"},
+		RHS: &influxql.TimeLiteral{Val: t},
+	}
+	if cond == nil {
+		return expr
+	}
+	return &influxql.BinaryExpr{
+		Op:  influxql.AND,
+		LHS: &influxql.ParenExpr{Expr: cond},
+		RHS: expr,
 	}
-	return &varRefCursor{
-		expr: expr,
-		ref:  ref,
-	}, nil
 }
 
 func (c *varRefCursor) Expr() ast.Expression {
@@ -171,6 +565,38 @@ func (c *varRefCursor) Value(expr influxql.Expr) (string, bool) {
 	return "", false
 }
 
+// fieldsCursor contains a cursor for the variables of a raw query that are read
+// together. Each variable is read from the column with its name.
+type fieldsCursor struct {
+	expr ast.Expression
+	refs []*influxql.VarRef
+}
+
+func (c *fieldsCursor) Expr() ast.Expression {
+	return c.expr
+}
+
+func (c *fieldsCursor) Keys() []influxql.Expr {
+	keys := make([]influxql.Expr, 0, len(c.refs))
+	for _, ref := range c.refs {
+		keys = append(keys, ref)
+	}
+	return keys
+}
+
+func (c *fieldsCursor) Value(expr influxql.Expr) (string, bool) {
+	ref, ok := expr.(*influxql.VarRef)
+	if !ok {
+		return "", false
+	}
+	for _, r := range c.refs {
+		if r == ref || r.Val == ref.Val {
+			return ref.Val, true
+		}
+	}
+	return "", false
+}
+
 // pipeCursor wraps a cursor with a new expression while delegating all calls to the
 // wrapped cursor.
 type pipeCursor struct {
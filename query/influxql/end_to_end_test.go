@@ -4,12 +4,16 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
@@ -50,8 +54,6 @@ func init() {
 }
 
 var skipTests = map[string]string{
-	"hardcoded_literal_1":      "transpiler count query is off by 1 (https://github.com/influxdata/platform/issues/1278)",
-	"hardcoded_literal_3":      "transpiler count query is off by 1 (https://github.com/influxdata/platform/issues/1278)",
 	"fuzz_join_within_cursor":  "transpiler does not implement joining fields within a cursor (https://github.com/influxdata/platform/issues/1340)",
 	"derivative_count":         "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
 	"derivative_first":         "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
@@ -71,76 +73,12 @@ var skipTests = map[string]string{
 	"regex_measurement_3":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
 	"regex_measurement_4":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
 	"regex_measurement_5":      "Transpiler: regex on measurements not evaluated (https://github.com/influxdata/platform/issues/1592)",
-	"regex_tag_0":              "Transpiler: Returns results in wrong sort order for regex filter on tags (https://github.com/influxdata/platform/issues/1596)",
-	"regex_tag_1":              "Transpiler: Returns results in wrong sort order for regex filter on tags (https://github.com/influxdata/platform/issues/1596)",
-	"regex_tag_2":              "Transpiler: Returns results in wrong sort order for regex filter on tags (https://github.com/influxdata/platform/issues/1596)",
-	"regex_tag_3":              "Transpiler: Returns results in wrong sort order for regex filter on tags (https://github.com/influxdata/platform/issues/1596)",
-	"explicit_type_0":          "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"explicit_type_1":          "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"fills_0":                  "need fill/Interpolate function (https://github.com/influxdata/platform/issues/272)",
-	"random_math_0":            "transpiler does not implement joining fields within a cursor (https://github.com/influxdata/platform/issues/1340)",
-	"selector_0":               "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"selector_1":               "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"selector_2":               "Transpiler: first function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
-	"selector_6":               "Transpiler: first function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
-	"selector_7":               "Transpiler: first function uses different series than influxQL (https://github.com/influxdata/platform/issues/1605)",
 	"series_agg_0":             "Transpiler: Implement difference (https://github.com/influxdata/platform/issues/1609)",
 	"series_agg_1":             "Transpiler: Implement stddev (https://github.com/influxdata/platform/issues/1610)",
-	"series_agg_2":             "Transpiler: Implement spread (https://github.com/influxdata/platform/issues/1611)",
 	"series_agg_3":             "Transpiler: Implement elapsed (https://github.com/influxdata/platform/issues/1612)",
 	"series_agg_4":             "Transpiler: Implement cumulative_sum (https://github.com/influxdata/platform/issues/1613)",
 	"series_agg_5":             "add derivative support to the transpiler (https://github.com/influxdata/platform/issues/93)",
 	"series_agg_6":             "Transpiler: Implement non_negative_derivative (https://github.com/influxdata/platform/issues/1614)",
-	"series_agg_7":             "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"series_agg_8":             "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"series_agg_9":             "Transpiler should remove _start column (https://github.com/influxdata/platform/issues/1360)",
-	"Subquery_0":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_1":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_2":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_3":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_4":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"Subquery_5":               "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"NestedSubquery_0":         "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"NestedSubquery_1":         "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"NestedSubquery_2":         "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"NestedSubquery_3":         "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_0":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_1":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_2":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_3":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SimulatedHTTP_4":          "Implement subqueries in the transpiler (https://github.com/influxdata/platform/issues/194)",
-	"SelectorMath_0":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_1":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_2":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_3":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_4":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_5":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_6":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_7":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_8":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_9":           "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_10":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_11":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_12":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_13":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_14":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_15":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_16":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_17":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_18":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_19":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_20":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_21":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_22":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_23":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_24":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_25":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_26":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_27":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_28":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_29":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_30":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
-	"SelectorMath_31":          "Transpiler: unimplemented functions: top and bottom (https://github.com/influxdata/platform/issues/1601)",
 }
 
 var querier = fluxquerytest.NewQuerier()
@@ -190,6 +128,20 @@ func testGeneratedInfluxQL(t testing.TB, prefix, queryExt string) {
 	inFile := prefix + ".in.json"
 	outFile := prefix + ".out.json"
 
+	// A statement that fails in InfluxQL is expected to fail with the same error.
+	if msg, err := jsonToError(outFile); err != nil {
+		t.Fatalf("failed to read expected JSON results: %v", err)
+	} else if msg != "" {
+		res, err := resultsFromQuerier(querier, influxQLCompiler(string(q), inFile))
+		if err == nil {
+			res.Release()
+			t.Fatalf("expected error %q", msg)
+		} else if got := err.Error(); got != msg {
+			t.Fatalf("unexpected error -want/+got:\n\t- %q\n\t+ %q", msg, got)
+		}
+		return
+	}
+
 	out, err := jsonToResultIterator(outFile)
 	if err != nil {
 		t.Fatalf("failed to read expected JSON results: %v", err)
@@ -212,7 +164,7 @@ func testGeneratedInfluxQL(t testing.TB, prefix, queryExt string) {
 		got = append(got, res.Next())
 	}
 
-	if err := executetest.EqualResults(exp, got); err != nil {
+	if err := equalResults(exp, got); err != nil {
 		t.Errorf("result not as expected: %v", err)
 
 		expBuffer := new(bytes.Buffer)
@@ -234,6 +186,50 @@ func testGeneratedInfluxQL(t testing.TB, prefix, queryExt string) {
 	}
 }
 
+// equalResults compares the results like executetest.EqualResults does, but
+// allows for a small relative error in floats. InfluxDB 1.X merges the partial
+// aggregates of its shards, so its sums are not added up in the same order.
+func equalResults(want, got []flux.Result) error {
+	if len(want) != len(got) {
+		return fmt.Errorf("unexpected number of results - want %d results, got %d results", len(want), len(got))
+	}
+	for i := range want {
+		wt, err := convertTables(want[i])
+		if err != nil {
+			return err
+		}
+		gt, err := convertTables(got[i])
+		if err != nil {
+			return err
+		}
+		if len(wt) != len(gt) {
+			return fmt.Errorf("unexpected size for result %s - want %d tables, got %d tables", want[i].Name(), len(wt), len(gt))
+		}
+
+		opts := cmp.Options{cmpopts.EquateApprox(1e-12, 0), cmpopts.EquateNaNs()}
+		if !cmp.Equal(wt, gt, opts) {
+			return fmt.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(wt, gt, opts))
+		}
+	}
+	return nil
+}
+
+func convertTables(res flux.Result) ([]*executetest.Table, error) {
+	var tables []*executetest.Table
+	if err := res.Tables().Do(func(tbl flux.Table) error {
+		t, err := executetest.ConvertTable(tbl)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, t)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	executetest.NormalizeTables(tables)
+	return tables, nil
+}
+
 func resultsFromQuerier(querier *fluxquerytest.Querier, compiler flux.Compiler) (flux.ResultIterator, error) {
 	req := &query.ProxyRequest{
 		Request: query.Request{
@@ -267,6 +263,29 @@ func queryToJSON(querier *fluxquerytest.Querier, req *query.ProxyRequest) (io.Re
 	return ioutil.NopCloser(&buf), nil
 }
 
+// jsonToError returns the error of the first statement in the influxql json file
+// that failed, if there is one.
+func jsonToError(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	var resp influxql.Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", err
+	}
+	if resp.Err != "" {
+		return resp.Err, nil
+	}
+	for _, res := range resp.Results {
+		if res.Err != "" {
+			return res.Err, nil
+		}
+	}
+	return "", nil
+}
+
 func jsonToResultIterator(file string) (flux.ResultIterator, error) {
 	f, err := os.Open(file)
 	if err != nil {
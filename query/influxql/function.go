@@ -46,7 +46,7 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 		default:
 			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
 		}
-	case "min", "max", "sum", "first", "last", "mean", "median":
+	case "min", "max", "sum", "first", "last", "mean", "median", "spread":
 		if exp, got := 1, len(expr.Args); exp != got {
 			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
 		}
@@ -93,21 +93,91 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 			Ref:  functionRef,
 			call: expr,
 		}, nil
+	case "top", "bottom":
+		if exp, got := 2, len(expr.Args); got < exp {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least %d, got %d", expr.Name, exp, got)
+		}
+
+		ref, ok := expr.Args[0].(*influxql.VarRef)
+		if !ok {
+			return nil, fmt.Errorf("expected first argument to be a field in %s(), found %s", expr.Name, expr.Args[0])
+		}
+
+		limit, ok := expr.Args[len(expr.Args)-1].(*influxql.IntegerLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected integer as last argument in %s(), found %s", expr.Name, expr.Args[len(expr.Args)-1])
+		}
+
+		// The arguments between the field and the limit are the tags.
+		for _, arg := range expr.Args[1 : len(expr.Args)-1] {
+			if _, ok := arg.(*influxql.VarRef); !ok {
+				return nil, fmt.Errorf("only fields or tags are allowed in %s(), found %s", expr.Name, arg)
+			}
+		}
+
+		if limit.Val <= 0 {
+			return nil, fmt.Errorf("limit (%d) in %s function must be at least 1", limit.Val, expr.Name)
+		}
+		return &function{
+			Ref:  ref,
+			call: expr,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unimplemented function: %q", expr.Name)
 	}
 
 }
 
+// isTopOrBottom returns true if the call is to top() or bottom().
+func isTopOrBottom(call *influxql.Call) bool {
+	return call.Name == "top" || call.Name == "bottom"
+}
+
+// selectorTags returns the tags the top() or bottom() call selects
+// distinct points for.
+func selectorTags(call *influxql.Call) []*influxql.VarRef {
+	if !isTopOrBottom(call) || len(call.Args) < 3 {
+		return nil
+	}
+	tags := make([]*influxql.VarRef, 0, len(call.Args)-2)
+	for _, arg := range call.Args[1 : len(call.Args)-1] {
+		tags = append(tags, arg.(*influxql.VarRef))
+	}
+	return tags
+}
+
 // createFunctionCursor creates a new cursor that calls a function on one of the columns
-// and returns the result.
-func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, normalize bool) (cursor, error) {
+// and returns the result. The key is the list of columns in the group key of the input,
+// which is nil when grouping by a wildcard.
+func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, key []string, normalize bool) (cursor, error) {
 	cur := &functionCursor{
 		call:   call,
 		parent: in,
 	}
+	pipe := func(arg ast.Expression, name string, properties ...*ast.Property) ast.Expression {
+		call := &ast.CallExpression{
+			Callee: &ast.Identifier{Name: name},
+		}
+		if len(properties) > 0 {
+			call.Arguments = []ast.Expression{
+				&ast.ObjectExpression{Properties: properties},
+			}
+		}
+		return &ast.PipeExpression{Argument: arg, Call: call}
+	}
+	columns := func(name string, columns ...string) *ast.Property {
+		elements := make([]ast.Expression, 0, len(columns))
+		for _, c := range columns {
+			elements = append(elements, &ast.StringLiteral{Value: c})
+		}
+		return &ast.Property{
+			Key:   &ast.Identifier{Name: name},
+			Value: &ast.ArrayExpression{Elements: elements},
+		}
+	}
+
 	switch call.Name {
-	case "count", "min", "max", "sum", "first", "last", "mean":
+	case "count", "min", "max", "sum", "mean", "spread":
 		value, ok := in.Value(call.Args[0])
 		if !ok {
 			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
@@ -122,6 +192,62 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		}
 		cur.value = value
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "first", "last":
+		value, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		interval, err := t.stmt.GroupByInterval()
+		if err != nil {
+			return nil, err
+		}
+
+		// When the points of several series have the same time, InfluxDB 1.X
+		// selects the point with the largest value within a window and the
+		// point of the last series otherwise. The first point of the last
+		// series is found by numbering the rows before reversing them.
+		selector := "min"
+		if call.Name == "last" {
+			selector = "max"
+		}
+		expr := in.Expr()
+		if interval > 0 {
+			expr = pipe(expr, "sort", columns("columns", value), &ast.Property{
+				Key:   &ast.Identifier{Name: "desc"},
+				Value: &ast.BooleanLiteral{Value: true},
+			})
+			expr = pipe(expr, selector, &ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+			})
+		} else if call.Name == "first" {
+			expr = pipe(expr, "stateCount", &ast.Property{
+				Key: &ast.Identifier{Name: "fn"},
+				Value: &ast.FunctionExpression{
+					Params: []*ast.Property{{
+						Key: &ast.Identifier{Name: "r"},
+					}},
+					Body: &ast.BooleanLiteral{Value: true},
+				},
+			}, &ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: "_row"},
+			})
+			expr = pipe(expr, "sort", columns("columns", "_row"), &ast.Property{
+				Key:   &ast.Identifier{Name: "desc"},
+				Value: &ast.BooleanLiteral{Value: true},
+			})
+			expr = pipe(expr, selector, &ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+			})
+			expr = pipe(expr, "drop", columns("columns", "_row"))
+		} else {
+			expr = pipe(expr, "last")
+		}
+		cur.expr = expr
+		cur.value = value
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
 	case "median":
 		value, ok := in.Value(call.Args[0])
 		if !ok {
@@ -220,12 +346,102 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		}
 		cur.value = fieldName
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "top", "bottom":
+		fieldName, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+		limit := call.Args[len(call.Args)-1].(*influxql.IntegerLiteral)
+
+		// The tags are already in the group key when grouping by a wildcard.
+		expr := in.Expr()
+		if tags := selectorTags(call); len(tags) > 0 && key != nil {
+			// Select the point with the maximum (or minimum) value for each
+			// distinct set of tags before selecting the top (or bottom) points.
+			tagKey := append([]string(nil), key...)
+			for _, tag := range tags {
+				tagKey = append(tagKey, tag.Val)
+			}
+			selector := "max"
+			if call.Name == "bottom" {
+				selector = "min"
+			}
+			var args []*ast.Property
+			if fieldName != execute.DefaultValueColLabel {
+				args = append(args, &ast.Property{
+					Key:   &ast.Identifier{Name: "column"},
+					Value: &ast.StringLiteral{Value: fieldName},
+				})
+			}
+			expr = pipe(expr, "group", columns("columns", tagKey...), &ast.Property{
+				Key:   &ast.Identifier{Name: "mode"},
+				Value: &ast.StringLiteral{Value: "by"},
+			})
+			expr = pipe(expr, selector, args...)
+			expr = pipe(expr, "group", columns("columns", key...), &ast.Property{
+				Key:   &ast.Identifier{Name: "mode"},
+				Value: &ast.StringLiteral{Value: "by"},
+			})
+		}
+
+		// InfluxDB 1.X selects the earliest points when the values are equal.
+		// The points are sorted by their time too, which is negated for top()
+		// because it sorts all of the columns in descending order.
+		order := execute.DefaultTimeColLabel
+		if call.Name == "top" {
+			order = "_row"
+			expr = pipe(expr, "map", &ast.Property{
+				Key: &ast.Identifier{Name: "fn"},
+				Value: &ast.FunctionExpression{
+					Params: []*ast.Property{{
+						Key: &ast.Identifier{Name: "r"},
+					}},
+					Body: &ast.ObjectExpression{
+						With: &ast.Identifier{Name: "r"},
+						Properties: []*ast.Property{{
+							Key: &ast.Identifier{Name: order},
+							Value: &ast.UnaryExpression{
+								Operator: ast.SubtractionOperator,
+								Argument: &ast.CallExpression{
+									Callee: &ast.Identifier{Name: "int"},
+									Arguments: []ast.Expression{
+										&ast.ObjectExpression{
+											Properties: []*ast.Property{{
+												Key: &ast.Identifier{Name: "v"},
+												Value: &ast.MemberExpression{
+													Object:   &ast.Identifier{Name: "r"},
+													Property: &ast.Identifier{Name: execute.DefaultTimeColLabel},
+												},
+											}},
+										},
+									},
+								},
+							},
+						}},
+					},
+				},
+			})
+		}
+		expr = pipe(expr, call.Name, &ast.Property{
+			Key:   &ast.Identifier{Name: "n"},
+			Value: &ast.IntegerLiteral{Value: limit.Val},
+		}, columns("columns", fieldName, order))
+		if order != execute.DefaultTimeColLabel {
+			expr = pipe(expr, "drop", columns("columns", order))
+		}
+
+		// The points are returned in the order of their time rather than
+		// their value.
+		cur.expr = pipe(expr, "sort", columns("columns", execute.DefaultTimeColLabel))
+		cur.value = fieldName
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
 	default:
 		return nil, fmt.Errorf("unimplemented function: %q", call.Name)
 	}
 
 	// If we have been told to normalize the time, we do it here.
-	if normalize {
+	// The top and bottom selectors always return the time of the selected points.
+	if normalize && !isTopOrBottom(call) {
 		if influxql.IsSelector(call) {
 			cur.expr = &ast.PipeExpression{
 				Argument: cur.expr,
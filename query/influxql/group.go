@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 )
@@ -14,6 +15,10 @@ type groupInfo struct {
 	call     *influxql.Call
 	refs     []*influxql.VarRef
 	selector bool
+
+	// key is the list of columns in the group key once the group is created.
+	// The key is nil when grouping by a wildcard because the tags are not known.
+	key []string
 }
 
 type groupVisitor struct {
@@ -65,6 +70,21 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 		return nil, v.err
 	}
 
+	// The top and bottom selectors return multiple points so they cannot be
+	// combined with other functions.
+	for _, fn := range v.calls {
+		if !isTopOrBottom(fn.call) {
+			continue
+		} else if len(v.calls) > 1 {
+			return nil, fmt.Errorf("selector function %s() cannot be combined with other functions", fn.call.Name)
+		}
+
+		limit := fn.call.Args[len(fn.call.Args)-1].(*influxql.IntegerLiteral)
+		if stmt.Limit > 0 && int(limit.Val) > stmt.Limit {
+			return nil, fmt.Errorf("limit (%d) in %s function can not be larger than the LIMIT (%d) in the select statement", limit.Val, fn.call.Name, stmt.Limit)
+		}
+	}
+
 	// Attempt to take the calls and variables and put them into groups.
 	if len(v.refs) > 0 {
 		// If any of the calls are not selectors, we have an error message.
@@ -108,7 +128,10 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 	// Create all of the cursors for every variable reference.
 	// TODO(jsternberg): Determine which of these cursors are from fields and which are tags.
-	var cursors []cursor
+	var (
+		cursors []cursor
+		tags    map[influxql.VarRef]struct{}
+	)
 	if gr.call != nil {
 		ref, ok := gr.call.Args[0].(*influxql.VarRef)
 		if !ok {
@@ -122,7 +145,30 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 		cursors = append(cursors, cur)
 	}
 
+	if gr.call == nil && len(gr.refs) > 1 {
+		// The variables of a raw query are read together so the points of the
+		// fields that are written together are returned as a single row.
+		cur, err := createFieldsCursor(t, gr.refs)
+		if err != nil {
+			return nil, err
+		}
+		cursors = append(cursors, cur)
+	}
+
 	for _, ref := range gr.refs {
+		if len(cursors) > 0 && gr.call == nil {
+			break
+		}
+
+		// The auxiliary tags of a selector are read from the selected point.
+		if gr.call != nil && t.mapType(ref) == influxql.Tag {
+			if tags == nil {
+				tags = make(map[influxql.VarRef]struct{})
+			}
+			tags[*ref] = struct{}{}
+			continue
+		}
+
 		cur, err := createVarRefCursor(t, ref)
 		if err != nil {
 			return nil, err
@@ -132,17 +178,16 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 
 	// TODO(jsternberg): Establish which variables in the condition are tags and which are fields.
 	// We need to create the references to fields here so they can be joined.
-	var (
-		tags map[influxql.VarRef]struct{}
-		cond influxql.Expr
-	)
+	var cond influxql.Expr
 	valuer := influxql.NowValuer{Now: t.config.Now}
 	if t.stmt.Condition != nil {
 		var err error
 		if cond, _, err = influxql.ConditionExpr(t.stmt.Condition, &valuer); err != nil {
 			return nil, err
 		} else if cond != nil {
-			tags = make(map[influxql.VarRef]struct{})
+			if tags == nil {
+				tags = make(map[influxql.VarRef]struct{})
+			}
 
 			// Walk through the condition for every variable reference. There will be no function
 			// calls here.
@@ -242,7 +287,7 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 
 	// If a function call is present, evaluate the function call.
 	if gr.call != nil {
-		c, err := createFunctionCursor(t, gr.call, cur, !gr.selector || interval > 0)
+		c, err := createFunctionCursor(t, gr.call, cur, gr.key, !gr.selector || interval > 0)
 		if err != nil {
 			return nil, err
 		}
@@ -268,6 +313,13 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 				},
 				cursor: cur,
 			}
+
+			// Fill the windows without a value now that they are in the same table.
+			if c, err := gr.fill(t, cur); err != nil {
+				return nil, err
+			} else {
+				cur = c
+			}
 		}
 	} else {
 		// If we do not have a function, but we have a field option,
@@ -284,6 +336,36 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 		case influxql.LinearFill:
 			return nil, errors.New("fill(linear) must be used with a function")
 		}
+
+		// The points of all of the series in a group are returned in the order of their time.
+		properties := []*ast.Property{{
+			Key: &ast.Identifier{Name: "columns"},
+			Value: &ast.ArrayExpression{
+				Elements: []ast.Expression{
+					&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+				},
+			},
+		}}
+		if !t.stmt.TimeAscending() {
+			properties = append(properties, &ast.Property{
+				Key:   &ast.Identifier{Name: "desc"},
+				Value: &ast.BooleanLiteral{Value: true},
+			})
+		}
+		cur = &pipeCursor{
+			expr: &ast.PipeExpression{
+				Argument: cur.Expr(),
+				Call: &ast.CallExpression{
+					Callee: &ast.Identifier{Name: "sort"},
+					Arguments: []ast.Expression{
+						&ast.ObjectExpression{
+							Properties: properties,
+						},
+					},
+				},
+			},
+			cursor: cur,
+		}
 	}
 	return cur, nil
 }
@@ -291,6 +373,8 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 	var windowEvery time.Duration
 	var windowStart time.Time
+	var wildcard bool
+	gr.key = []string{"_measurement", "_start"}
 	tags := []ast.Expression{
 		&ast.StringLiteral{Value: "_measurement"},
 		&ast.StringLiteral{Value: "_start"},
@@ -314,6 +398,7 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 				tags = append(tags, &ast.StringLiteral{
 					Value: expr.Val,
 				})
+				gr.key = append(gr.key, expr.Val)
 				m[expr.Val] = struct{}{}
 			case *influxql.Call:
 				// Ensure the call is time() and it has one or two duration arguments.
@@ -367,7 +452,7 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 					}
 				}
 			case *influxql.Wildcard:
-				return nil, errors.New("unimplemented: dimension wildcards")
+				wildcard = true
 			case *influxql.RegexLiteral:
 				return nil, errors.New("unimplemented: dimension regex wildcards")
 			default:
@@ -377,8 +462,21 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 	}
 
 	// Perform the grouping by the tags we found. There is always a group by because
-	// there is always something to group in influxql.
-	// TODO(jsternberg): A wildcard will skip this step.
+	// there is always something to group in influxql. A wildcard groups by all of
+	// the columns except for the ones that hold the values of the points.
+	mode := "by"
+	if wildcard {
+		tags = []ast.Expression{
+			&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+			&ast.StringLiteral{Value: execute.DefaultStopColLabel},
+			&ast.StringLiteral{Value: "_field"},
+			&ast.StringLiteral{Value: execute.DefaultValueColLabel},
+		}
+		for _, name := range t.valueColumns() {
+			tags = append(tags, &ast.StringLiteral{Value: name})
+		}
+		mode = "except"
+	}
 	in = &pipeCursor{
 		expr: &ast.PipeExpression{
 			Argument: in.Expr(),
@@ -402,7 +500,7 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 									Name: "mode",
 								},
 								Value: &ast.StringLiteral{
-									Value: "by",
+									Value: mode,
 								},
 							},
 						},
@@ -432,6 +530,18 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 				},
 			})
 		}
+		// Windows without any points are only needed when they will be filled.
+		switch t.stmt.Fill {
+		case influxql.NumberFill, influxql.PreviousFill:
+			args = append(args, &ast.Property{
+				Key: &ast.Identifier{
+					Name: "createEmpty",
+				},
+				Value: &ast.BooleanLiteral{
+					Value: true,
+				},
+			})
+		}
 		in = &pipeCursor{
 			expr: &ast.PipeExpression{
 				Argument: in.Expr(),
@@ -448,10 +558,155 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 			},
 			cursor: in,
 		}
+		gr.key = append(gr.key, "_stop")
+	}
+	if wildcard {
+		gr.key = nil
 	}
 	return in, nil
 }
 
+// fill fills the null values of the windows of the function call
+// according to the fill option of the statement.
+func (gr *groupInfo) fill(t *transpilerState, in cursor) (cursor, error) {
+	switch t.stmt.Fill {
+	case influxql.NullFill, influxql.NoFill:
+		// Windows without any points are not created so there is nothing to fill.
+		// TODO(jsternberg): Create the empty windows for fill(null).
+		return in, nil
+	case influxql.LinearFill:
+		return nil, errors.New("unimplemented: fill(linear)")
+	case influxql.NumberFill, influxql.PreviousFill:
+	default:
+		return nil, fmt.Errorf("unknown fill option: %d", t.stmt.Fill)
+	}
+
+	column, ok := in.Value(gr.call)
+	if !ok {
+		return nil, fmt.Errorf("undefined variable: %s", gr.call)
+	}
+	var property ast.PropertyKey
+	if strings.HasPrefix(column, "_") {
+		property = &ast.Identifier{Name: column}
+	} else {
+		property = &ast.StringLiteral{Value: column}
+	}
+
+	var call *ast.CallExpression
+	switch {
+	case t.stmt.Fill == influxql.NumberFill && gr.call.Name == "count":
+		// The count of an empty window is zero rather than null so the
+		// zero counts are replaced with the fill value.
+		value, err := gr.fillValue(t.stmt.FillValue)
+		if err != nil {
+			return nil, err
+		}
+		ref := &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: property,
+		}
+		call = &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "map"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{Name: "fn"},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: &ast.Identifier{Name: "r"},
+							}},
+							Body: &ast.ObjectExpression{
+								With: &ast.Identifier{Name: "r"},
+								Properties: []*ast.Property{{
+									Key: &ast.Identifier{Name: column},
+									Value: &ast.ConditionalExpression{
+										Test: &ast.BinaryExpression{
+											Operator: ast.EqualOperator,
+											Left:     ref,
+											Right:    &ast.IntegerLiteral{Value: 0},
+										},
+										Consequent: value,
+										Alternate:  ref,
+									},
+								}},
+							},
+						},
+					}},
+				},
+			},
+		}
+	default:
+		var arg *ast.Property
+		if t.stmt.Fill == influxql.NumberFill {
+			value, err := gr.fillValue(t.stmt.FillValue)
+			if err != nil {
+				return nil, err
+			}
+			arg = &ast.Property{
+				Key:   &ast.Identifier{Name: "value"},
+				Value: value,
+			}
+		} else {
+			arg = &ast.Property{
+				Key:   &ast.Identifier{Name: "usePrevious"},
+				Value: &ast.BooleanLiteral{Value: true},
+			}
+		}
+		properties := []*ast.Property{arg}
+		if column != execute.DefaultValueColLabel {
+			properties = append(properties, &ast.Property{
+				Key:   &ast.Identifier{Name: "column"},
+				Value: &ast.StringLiteral{Value: column},
+			})
+		}
+		call = &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "fill"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: properties,
+				},
+			},
+		}
+	}
+	return &pipeCursor{
+		expr: &ast.PipeExpression{
+			Argument: in.Expr(),
+			Call:     call,
+		},
+		cursor: in,
+	}, nil
+}
+
+// fillValue returns the literal for the fill value with the type of the
+// values of the function call. The type is the type of the fill value
+// if the function returns the type of the field.
+func (gr *groupInfo) fillValue(v interface{}) (ast.Expression, error) {
+	switch gr.call.Name {
+	case "mean", "median":
+		switch v := v.(type) {
+		case int64:
+			return &ast.FloatLiteral{Value: float64(v)}, nil
+		case float64:
+			return &ast.FloatLiteral{Value: v}, nil
+		}
+	case "count":
+		switch v := v.(type) {
+		case int64:
+			return &ast.IntegerLiteral{Value: v}, nil
+		case float64:
+			return &ast.IntegerLiteral{Value: int64(v)}, nil
+		}
+	default:
+		switch v := v.(type) {
+		case int64:
+			return &ast.IntegerLiteral{Value: v}, nil
+		case float64:
+			return &ast.FloatLiteral{Value: v}, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid fill value: %v", v)
+}
+
 // tagsCursor is a pseudo-cursor that can be used to access tags within the cursor.
 type tagsCursor struct {
 	cursor
@@ -1,9 +1,11 @@
 package influxql
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/influxql"
 )
 
@@ -33,10 +35,10 @@ func Join(t *transpilerState, cursors []cursor, on []string) cursor {
 			},
 		})
 		for _, k := range cur.Keys() {
-			// Combine the table name with the name to access this attribute so we can know
-			// what it will be mapped to.
+			// Combine the name to access this attribute with the table name so we can know
+			// what it will be mapped to. The join suffixes the columns with the table name.
 			varName, _ := cur.Value(k)
-			name := fmt.Sprintf("%s_%s", tableName, varName)
+			name := fmt.Sprintf("%s_%s", varName, tableName)
 			exprs = append(exprs, k)
 			m[k] = name
 		}
@@ -80,6 +82,130 @@ func Join(t *transpilerState, cursors []cursor, on []string) cursor {
 	}
 }
 
+// pivotJoin joins the cursors when the columns of the group key are not known, which
+// is the case when grouping by a wildcard. The values of the cursors are merged and
+// pivoted into the columns that the join would have created.
+func pivotJoin(t *transpilerState, cursors []cursor) (cursor, error) {
+	if len(cursors) == 1 {
+		return cursors[0], nil
+	}
+
+	except := []ast.Expression{
+		&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+		&ast.StringLiteral{Value: execute.DefaultStopColLabel},
+		&ast.StringLiteral{Value: execute.DefaultValueColLabel},
+	}
+	for _, name := range t.valueColumns() {
+		except = append(except, &ast.StringLiteral{Value: name})
+	}
+
+	var exprs []influxql.Expr
+	m := make(map[influxql.Expr]string)
+	tables := make([]ast.Expression, 0, len(cursors))
+	for _, cur := range cursors {
+		keys := cur.Keys()
+		if len(keys) != 1 {
+			return nil, errors.New("unimplemented: joining multiple values of a cursor when grouping by a wildcard")
+		} else if varName, _ := cur.Value(keys[0]); varName != execute.DefaultValueColLabel {
+			return nil, fmt.Errorf("unimplemented: joining the %s column when grouping by a wildcard", varName)
+		}
+
+		// Name the values like Join does.
+		ident := t.assignment(cur.Expr())
+		name := fmt.Sprintf("%s_%s", execute.DefaultValueColLabel, ident.Name)
+		exprs = append(exprs, keys[0])
+		m[keys[0]] = name
+
+		set := &ast.PipeExpression{
+			Argument: &ast.Identifier{Name: ident.Name},
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{
+					Name: "set",
+				},
+				Arguments: []ast.Expression{
+					&ast.ObjectExpression{
+						Properties: []*ast.Property{
+							{
+								Key:   &ast.Identifier{Name: "key"},
+								Value: &ast.StringLiteral{Value: "_field"},
+							},
+							{
+								Key:   &ast.Identifier{Name: "value"},
+								Value: &ast.StringLiteral{Value: name},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		// The field is added to the group key so the values, which may be of
+		// different types, are not merged into the same table until the pivot.
+		tables = append(tables, &ast.PipeExpression{
+			Argument: set,
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{
+					Name: "group",
+				},
+				Arguments: []ast.Expression{
+					&ast.ObjectExpression{
+						Properties: []*ast.Property{
+							{
+								Key:   &ast.Identifier{Name: "columns"},
+								Value: &ast.ArrayExpression{Elements: except},
+							},
+							{
+								Key:   &ast.Identifier{Name: "mode"},
+								Value: &ast.StringLiteral{Value: "except"},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	expr := &ast.PipeExpression{
+		Argument: union(tables),
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "pivot",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{
+							Key: &ast.Identifier{Name: "rowKey"},
+							Value: &ast.ArrayExpression{
+								Elements: []ast.Expression{
+									&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+								},
+							},
+						},
+						{
+							Key: &ast.Identifier{Name: "columnKey"},
+							Value: &ast.ArrayExpression{
+								Elements: []ast.Expression{
+									&ast.StringLiteral{Value: "_field"},
+								},
+							},
+						},
+						{
+							Key:   &ast.Identifier{Name: "valueColumn"},
+							Value: &ast.StringLiteral{Value: execute.DefaultValueColLabel},
+						},
+					},
+				},
+			},
+		},
+	}
+	return &joinCursor{
+		expr:  expr,
+		m:     m,
+		exprs: exprs,
+	}, nil
+}
+
 func (c *joinCursor) Expr() ast.Expression {
 	return c.expr
 }
@@ -2,6 +2,8 @@ package influxql
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,15 +35,39 @@ func (c *mapCursor) Value(expr influxql.Expr) (string, bool) {
 // mapFields will take the list of symbols and maps each of the operations
 // using the column names.
 func (t *transpilerState) mapFields(in cursor) (cursor, error) {
+	// The tags of the top and bottom selectors are columns following the selector
+	// unless the results are written into a measurement, where they are tags.
+	var (
+		fields influxql.Fields
+		tags   map[influxql.VarRef]struct{}
+	)
+	for _, f := range t.stmt.Fields {
+		fields = append(fields, f)
+		if call, ok := f.Expr.(*influxql.Call); ok {
+			for _, tag := range selectorTags(call) {
+				if tags == nil {
+					tags = make(map[influxql.VarRef]struct{})
+				}
+				tags[*tag] = struct{}{}
+				if t.stmt.Target == nil {
+					fields = append(fields, &influxql.Field{Expr: tag})
+				}
+			}
+		}
+	}
+	if len(tags) > 0 {
+		in = &tagsCursor{cursor: in, tags: tags}
+	}
+
 	columns := t.stmt.ColumnNames()
-	if len(columns) != len(t.stmt.Fields) {
+	if len(columns) != len(fields) {
 		// TODO(jsternberg): This scenario should not be possible. Replace the use of ColumnNames with a more
 		// statically verifiable list of columns when we process the fields from the select statement instead
 		// of doing this in the future.
 		panic("number of columns does not match the number of fields")
 	}
 
-	properties := make([]*ast.Property, 0, len(t.stmt.Fields)+1)
+	properties := make([]*ast.Property, 0, len(fields)+len(tags)+1)
 	properties = append(properties, &ast.Property{
 		Key: &ast.Identifier{
 			Name: execute.DefaultTimeColLabel,
@@ -55,7 +81,8 @@ func (t *transpilerState) mapFields(in cursor) (cursor, error) {
 			},
 		},
 	})
-	for i, f := range t.stmt.Fields {
+	names := make([]string, 0, len(fields))
+	for i, f := range fields {
 		if ref, ok := f.Expr.(*influxql.VarRef); ok && ref.Val == "time" {
 			// Skip past any time columns.
 			continue
@@ -68,42 +95,116 @@ func (t *transpilerState) mapFields(in cursor) (cursor, error) {
 			Key:   &ast.Identifier{Name: columns[i]},
 			Value: value,
 		})
+		names = append(names, columns[i])
 	}
-	return &mapCursor{
-		expr: &ast.PipeExpression{
-			Argument: in.Expr(),
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "map",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{
-									Name: "fn",
-								},
-								Value: &ast.FunctionExpression{
-									Params: []*ast.Property{{
-										Key: &ast.Identifier{Name: "r"},
-									}},
-									Body: &ast.ObjectExpression{
-										Properties: properties,
-									},
-								},
+
+	// The map function adds the columns in sorted order, so columns that are not
+	// selected in sorted order are mapped to placeholders that sort in the order
+	// of the select statement and are renamed afterwards.
+	reorder := t.stmt.Target == nil && !sort.StringsAreSorted(names)
+	if reorder {
+		for i, p := range properties[1:] {
+			p.Key = &ast.Identifier{Name: placeholderColumn(i, len(names))}
+		}
+	}
+	if t.stmt.Target != nil {
+		for _, f := range t.stmt.Fields {
+			if call, ok := f.Expr.(*influxql.Call); ok {
+				for _, tag := range selectorTags(call) {
+					value, err := t.mapField(tag, in)
+					if err != nil {
+						return nil, err
+					}
+					properties = append(properties, &ast.Property{
+						Key:   &ast.Identifier{Name: tag.Val},
+						Value: value,
+					})
+				}
+			}
+		}
+	}
+	var expr ast.Expression = &ast.PipeExpression{
+		Argument: in.Expr(),
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "map",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{
+							Key: &ast.Identifier{
+								Name: "fn",
 							},
-							{
-								Key: &ast.Identifier{
-									Name: "mergeKey",
+							Value: &ast.FunctionExpression{
+								Params: []*ast.Property{{
+									Key: &ast.Identifier{Name: "r"},
+								}},
+								Body: &ast.ObjectExpression{
+									Properties: properties,
 								},
-								Value: &ast.BooleanLiteral{Value: true},
 							},
 						},
+						{
+							Key: &ast.Identifier{
+								Name: "mergeKey",
+							},
+							Value: &ast.BooleanLiteral{Value: true},
+						},
 					},
 				},
 			},
 		},
-	}, nil
+	}
+	if reorder {
+		expr = renameColumns(expr, names)
+	}
+	return &mapCursor{expr: expr}, nil
+}
+
+// renameColumns renames the placeholder columns of the map function to the
+// names of the columns. The placeholders are renamed with a function because
+// a column is not created if all of its values are null.
+func renameColumns(in ast.Expression, names []string) ast.Expression {
+	var body ast.Expression = &ast.Identifier{Name: "column"}
+	for i := len(names) - 1; i >= 0; i-- {
+		body = &ast.ConditionalExpression{
+			Test: &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left:     &ast.Identifier{Name: "column"},
+				Right:    &ast.StringLiteral{Value: placeholderColumn(i, len(names))},
+			},
+			Consequent: &ast.StringLiteral{Value: names[i]},
+			Alternate:  body,
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: in,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "rename",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{Name: "fn"},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: &ast.Identifier{Name: "column"},
+							}},
+							Body: body,
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// placeholderColumn returns the name of the placeholder for the ith of n columns.
+// The placeholders are padded so they sort in the order of the columns.
+func placeholderColumn(i, n int) string {
+	return fmt.Sprintf("_%0*d", len(strconv.Itoa(n-1)), i)
 }
 
 func (t *transpilerState) mapField(expr influxql.Expr, in cursor) (ast.Expression, error) {
@@ -178,6 +279,10 @@ func (t *transpilerState) evalBinaryExpr(expr *influxql.BinaryExpr, in cursor) (
 			return b.eval(ast.AdditionOperator)
 		case influxql.SUB:
 			return b.eval(ast.SubtractionOperator)
+		case influxql.MUL:
+			return b.eval(ast.MultiplicationOperator)
+		case influxql.DIV:
+			return b.div
 		case influxql.AND:
 			return b.logical(ast.AndOperator)
 		case influxql.OR:
@@ -218,6 +323,29 @@ func (evalBuilder) logical(op ast.LogicalOperatorKind) func(left, right ast.Expr
 	}
 }
 
+// div divides the values as floats because a division in InfluxQL always
+// returns a float.
+func (evalBuilder) div(left, right ast.Expression) ast.Expression {
+	float := func(v ast.Expression) ast.Expression {
+		return &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "float"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key:   &ast.Identifier{Name: "v"},
+						Value: v,
+					}},
+				},
+			},
+		}
+	}
+	return &ast.BinaryExpression{
+		Operator: ast.DivisionOperator,
+		Left:     float(left),
+		Right:    float(right),
+	}
+}
+
 func (evalBuilder) eval(op ast.OperatorKind) func(left, right ast.Expression) ast.Expression {
 	return func(left, right ast.Expression) ast.Expression {
 		return &ast.BinaryExpression{
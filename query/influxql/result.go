@@ -1,21 +1,30 @@
 package influxql
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/influxql"
 )
 
 // MultiResultEncoder encodes results as InfluxQL JSON format.
-type MultiResultEncoder struct{}
+type MultiResultEncoder struct {
+	TimeFormat TimeFormat     // TimeFormat is the format of the timestamp; defaults to RFC3339Nano.
+	Encoding   EncodingFormat // Encoding is the format of the results; defaults to JSON.
+	ChunkSize  int            // ChunkSize is the maximum number of values of a series in a chunk; defaults to 0 or no chunking.
+}
 
 // Encode writes a collection of results to the influxdb 1.X http response format.
+// The results are written as JSON, or as CSV if that is the encoding of the encoder.
 // Expectations/Assumptions:
 //  1.  Each result will be published as a 'statement' in the top-level list of results. The result name
 //      will be interpreted as an integer and used as the statement id.
@@ -28,9 +37,39 @@ type MultiResultEncoder struct{}
 //      TODO(jsternberg): This function currently requires the first column to be a time field, but this isn't
 //      a strict requirement and will be lifted when we begin to work on transpiling meta queries.
 func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
-	resp := Response{}
+	resp := e.response(results)
 	wc := &iocounter.Writer{Writer: w}
 
+	var err error
+	switch e.Encoding {
+	case CSV:
+		err = encodeCSV(wc, resp)
+	case JSONPretty:
+		enc := json.NewEncoder(wc)
+		enc.SetIndent("", "    ")
+		err = encodeJSON(enc, resp, e.ChunkSize)
+	default:
+		err = encodeJSON(json.NewEncoder(wc), resp, e.ChunkSize)
+	}
+	return wc.Count(), err
+}
+
+// EncodeError writes err as the error of the response in the encoding of the encoder.
+func (e *MultiResultEncoder) EncodeError(w io.Writer, err error) error {
+	resp := Response{}
+	resp.error(err)
+	switch e.Encoding {
+	case CSV:
+		return encodeCSV(w, resp)
+	default:
+		return json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// response reads the results into the response of the 1.X http API.
+func (e *MultiResultEncoder) response(results flux.ResultIterator) Response {
+	resp := Response{}
+
 	for results.More() {
 		res := results.Next()
 		name := res.Name()
@@ -154,7 +193,7 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 						vs := cr.Times(idx)
 						for i := 0; i < vs.Len(); i++ {
 							if vs.IsValid(i) {
-								values[i][j] = execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano)
+								values[i][j] = e.formatTime(execute.Time(vs.Value(i)))
 							}
 						}
 					default:
@@ -181,9 +220,179 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 	if err := results.Err(); err != nil && resp.Err == "" {
 		resp.error(err)
 	}
+	return resp
+}
 
-	err := json.NewEncoder(wc).Encode(resp)
-	return wc.Count(), err
+// formatTime formats the time of a value with the time format of the encoder.
+// The time of a function over a range without a start is the minimum time,
+// which is reported as the epoch like InfluxDB 1.X does.
+func (e *MultiResultEncoder) formatTime(t execute.Time) interface{} {
+	if t == execute.Time(influxql.MinTime) {
+		t = 0
+	}
+	switch e.TimeFormat {
+	case Hour:
+		return int64(t) / int64(time.Hour)
+	case Minute:
+		return int64(t) / int64(time.Minute)
+	case Second:
+		return int64(t) / int64(time.Second)
+	case Millisecond:
+		return int64(t) / int64(time.Millisecond)
+	case Microsecond:
+		return int64(t) / int64(time.Microsecond)
+	case Nanosecond:
+		return int64(t)
+	default:
+		return t.Time().Format(time.RFC3339Nano)
+	}
+}
+
+// encodeJSON writes the response as JSON. If chunkSize is greater than zero,
+// every series is split into chunks of at most chunkSize values and each
+// chunk is written as its own response, as when the results are chunked by
+// InfluxDB 1.X.
+func encodeJSON(enc *json.Encoder, resp Response, chunkSize int) error {
+	if chunkSize <= 0 || resp.Err != "" {
+		return enc.Encode(resp)
+	}
+
+	for _, result := range resp.Results {
+		if len(result.Series) == 0 {
+			if err := enc.Encode(Response{Results: []Result{result}}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for i, row := range result.Series {
+			values := row.Values
+			for {
+				n := len(values)
+				if n > chunkSize {
+					n = chunkSize
+				}
+				chunk := *row
+				chunk.Values = values[:n]
+				values = values[n:]
+				chunk.Partial = len(values) > 0
+
+				chunked := result
+				chunked.Series = []*Row{&chunk}
+				chunked.Partial = chunk.Partial || i < len(result.Series)-1
+				if err := enc.Encode(Response{Results: []Result{chunked}}); err != nil {
+					return err
+				}
+				if len(values) == 0 {
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// encodeCSV writes the response as CSV. Each series is written with the name of
+// the measurement and the tags of the series as the first two columns, and a
+// header is written whenever the columns change.
+func encodeCSV(w io.Writer, resp Response) error {
+	cw := csv.NewWriter(w)
+	if resp.Err != "" {
+		_ = cw.Write([]string{"error"})
+		_ = cw.Write([]string{resp.Err})
+		cw.Flush()
+		return cw.Error()
+	}
+
+	var columns []string
+	for _, result := range resp.Results {
+		if result.Err != "" {
+			_ = cw.Write([]string{"error"})
+			_ = cw.Write([]string{result.Err})
+			continue
+		}
+
+		for _, row := range result.Series {
+			if columns == nil || !stringsEqual(columns[2:], row.Columns) {
+				if columns != nil {
+					cw.Flush()
+					if _, err := w.Write([]byte("\n")); err != nil {
+						return err
+					}
+				}
+				columns = append([]string{"name", "tags"}, row.Columns...)
+				if err := cw.Write(columns); err != nil {
+					return err
+				}
+			}
+
+			record := make([]string, len(columns))
+			record[0] = row.Name
+			record[1] = formatTags(row.Tags)
+			for _, values := range row.Values {
+				for i, v := range values {
+					record[i+2] = formatValue(v)
+				}
+				if err := cw.Write(record); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatTags formats the tags of a series as key=value pairs sorted by key.
+func formatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(tags[k])
+	}
+	return sb.String()
+}
+
+// formatValue formats a value of a series for CSV.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 func NewMultiResultEncoder() *MultiResultEncoder {
 	return new(MultiResultEncoder)
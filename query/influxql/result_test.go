@@ -16,6 +16,7 @@ import (
 func TestMultiResultEncoder_Encode(t *testing.T) {
 	for _, tt := range []struct {
 		name string
+		enc  *influxql.MultiResultEncoder
 		in   flux.ResultIterator
 		out  string
 	}{
@@ -83,6 +84,95 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 			in:   &resultErrorIterator{Error: "expected"},
 			out:  `{"error":"expected"}`,
 		},
+		{
+			name: "Epoch",
+			enc:  &influxql.MultiResultEncoder{TimeFormat: influxql.Second},
+			in: func() flux.ResultIterator {
+				return flux.NewSliceResultIterator(
+					[]flux.Result{&executetest.Result{
+						Nm: "0",
+						Tbls: []*executetest.Table{{
+							KeyCols: []string{"_measurement", "host"},
+							ColMeta: []flux.ColMeta{
+								{Label: "_time", Type: flux.TTime},
+								{Label: "_measurement", Type: flux.TString},
+								{Label: "host", Type: flux.TString},
+								{Label: "value", Type: flux.TFloat},
+							},
+							Data: [][]interface{}{
+								{ts("2018-05-24T09:00:00Z"), "m0", "server01", float64(2)},
+								{ts("2018-05-24T09:00:10Z"), "m0", "server01", float64(3)},
+								{ts("2018-05-24T09:00:20Z"), "m0", "server01", float64(4)},
+							},
+						}},
+					}},
+				)
+			}(),
+			out: `{"results":[{"statement_id":0,"series":[{"name":"m0","tags":{"host":"server01"},"columns":["time","value"],"values":[[1527152400,2],[1527152410,3],[1527152420,4]]}]}]}`,
+		},
+		{
+			name: "Chunked",
+			enc:  &influxql.MultiResultEncoder{ChunkSize: 2},
+			in: func() flux.ResultIterator {
+				return flux.NewSliceResultIterator(
+					[]flux.Result{&executetest.Result{
+						Nm: "0",
+						Tbls: []*executetest.Table{{
+							KeyCols: []string{"_measurement", "host"},
+							ColMeta: []flux.ColMeta{
+								{Label: "_time", Type: flux.TTime},
+								{Label: "_measurement", Type: flux.TString},
+								{Label: "host", Type: flux.TString},
+								{Label: "value", Type: flux.TFloat},
+							},
+							Data: [][]interface{}{
+								{ts("2018-05-24T09:00:00Z"), "m0", "server01", float64(2)},
+								{ts("2018-05-24T09:00:10Z"), "m0", "server01", float64(3)},
+								{ts("2018-05-24T09:00:20Z"), "m0", "server01", float64(4)},
+							},
+						}},
+					}},
+				)
+			}(),
+			out: `{"results":[{"statement_id":0,"series":[{"name":"m0","tags":{"host":"server01"},"columns":["time","value"],"values":[["2018-05-24T09:00:00Z",2],["2018-05-24T09:00:10Z",3]],"partial":true}],"partial":true}]}
+{"results":[{"statement_id":0,"series":[{"name":"m0","tags":{"host":"server01"},"columns":["time","value"],"values":[["2018-05-24T09:00:20Z",4]]}]}]}`,
+		},
+		{
+			name: "CSV",
+			enc:  &influxql.MultiResultEncoder{TimeFormat: influxql.Nanosecond, Encoding: influxql.CSV},
+			in: func() flux.ResultIterator {
+				return flux.NewSliceResultIterator(
+					[]flux.Result{&executetest.Result{
+						Nm: "0",
+						Tbls: []*executetest.Table{{
+							KeyCols: []string{"_measurement", "host"},
+							ColMeta: []flux.ColMeta{
+								{Label: "_time", Type: flux.TTime},
+								{Label: "_measurement", Type: flux.TString},
+								{Label: "host", Type: flux.TString},
+								{Label: "value", Type: flux.TFloat},
+							},
+							Data: [][]interface{}{
+								{ts("2018-05-24T09:00:00Z"), "m0", "server01", float64(2)},
+								{ts("2018-05-24T09:00:10Z"), "m0", "server01", float64(3)},
+								{ts("2018-05-24T09:00:20Z"), "m0", "server01", float64(4)},
+							},
+						}},
+					}},
+				)
+			}(),
+			out: `name,tags,time,value
+m0,host=server01,1527152400000000000,2
+m0,host=server01,1527152410000000000,3
+m0,host=server01,1527152420000000000,4`,
+		},
+		{
+			name: "CSV Error",
+			enc:  &influxql.MultiResultEncoder{Encoding: influxql.CSV},
+			in:   &resultErrorIterator{Error: "expected"},
+			out: `error
+expected`,
+		},
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -90,7 +180,10 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 			tt.out += "\n"
 
 			var buf bytes.Buffer
-			enc := influxql.NewMultiResultEncoder()
+			enc := tt.enc
+			if enc == nil {
+				enc = influxql.NewMultiResultEncoder()
+			}
 			n, err := enc.Encode(&buf, tt.in)
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
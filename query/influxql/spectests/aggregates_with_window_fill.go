@@ -0,0 +1,49 @@
+package spectests
+
+import "fmt"
+
+func init() {
+	RegisterFixture(
+		AggregateTest(func(name string) (stmt, want string) {
+			fill := `fill(value: 0)`
+			switch name {
+			case "count":
+				fill = `map(fn: (r) => ({r with _value: if r._value == 0 then 0 else r._value}))`
+			case "mean":
+				fill = `fill(value: 0.0)`
+			}
+			return fmt.Sprintf(`SELECT %s(value) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m) fill(0)`, name),
+				`package main
+
+` + fmt.Sprintf(`from(bucketID: "%s"`, bucketID.String()) + `)
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m, createEmpty: true)
+	|> ` + name + `()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> ` + fill + `
+	|> map(fn: (r) => ({_time: r._time, ` + name + `: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`
+		}),
+		NewFixture(
+			`SELECT mean(value) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m) fill(previous)`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m, createEmpty: true)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> fill(usePrevious: true)
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,26 @@
+package spectests
+
+import "fmt"
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT mean(value) INTO db0..cpu_5m FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(5m), host`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start", "host"], mode: "by")
+	|> window(every: 5m)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+	|> set(key: "_measurement", value: "cpu_5m")
+	|> to(`+fmt.Sprintf(`bucketID: "%s", orgID: "%s"`, bucketID.String(), organizationID.String())+`, fieldFn: (r) => ({mean: r["mean"]}))
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -20,7 +20,8 @@ t1 = from(bucketID: "")
 	|> drop(columns: ["_time"])
 	|> duplicate(column: "_start", as: "_time")
 join(tables: {t0: t0, t1: t1}, on: ["_time", "_measurement"])
-	|> map(fn: (r) => ({_time: r._time, mean: r["t0__value"], max: r["t1__value"]}), mergeKey: true)
+	|> map(fn: (r) => ({_time: r._time, _0: r._value_t0, _1: r._value_t1}), mergeKey: true)
+	|> rename(fn: (column) => if column == "_0" then "mean" else if column == "_1" then "max" else column)
 	|> yield(name: "0")
 `,
 		),
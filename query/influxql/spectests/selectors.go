@@ -30,6 +30,13 @@ func SelectorTest(fn func(name string) (stmt, want string)) Fixture {
 func init() {
 	RegisterFixture(
 		SelectorTest(func(name string) (stmt, want string) {
+			selector := name + "()"
+			if name == "first" {
+				selector = `stateCount(fn: (r) => true, column: "_row")
+	|> sort(columns: ["_row"], desc: true)
+	|> min(column: "_time")
+	|> drop(columns: ["_row"])`
+			}
 			return fmt.Sprintf(`SELECT %s(value) FROM db0..cpu`, name),
 				`package main
 
@@ -37,7 +44,7 @@ func init() {
 	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
 	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
 	|> group(columns: ["_measurement", "_start"], mode: "by")
-	|> ` + name + `()
+	|> ` + selector + `
 	|> map(fn: (r) => ({_time: r._time, ` + name + `: r._value}), mergeKey: true)
 	|> yield(name: "0")
 `
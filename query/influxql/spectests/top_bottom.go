@@ -0,0 +1,43 @@
+package spectests
+
+import "fmt"
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT top(value, 2) FROM db0..cpu`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> map(fn: (r) => ({r with _row: -int(v: r._time)}))
+	|> top(n: 2, columns: ["_value", "_row"])
+	|> drop(columns: ["_row"])
+	|> sort(columns: ["_time"])
+	|> map(fn: (r) => ({_time: r._time, top: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT bottom(value, host, 2) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(5m)`,
+			`package main
+
+`+fmt.Sprintf(`from(bucketID: "%s")`, bucketID.String())+`
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 5m)
+	|> group(columns: ["_measurement", "_start", "_stop", "host"], mode: "by")
+	|> min()
+	|> group(columns: ["_measurement", "_start", "_stop"], mode: "by")
+	|> bottom(n: 2, columns: ["_value", "_time"])
+	|> sort(columns: ["_time"])
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, bottom: r._value, host: r["host"]}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -635,28 +635,209 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 		cursors = append(cursors, cur)
 	}
 
-	// Join the cursors together on the measurement name.
-	// TODO(jsternberg): This needs to join on all remaining group keys.
-	cur := Join(t, cursors, []string{"_time", "_measurement"})
+	// Join the cursors together on the measurement name and the tags of the group key.
+	var cur cursor
+	if groups[0].key == nil {
+		if cur, err = pivotJoin(t, cursors); err != nil {
+			return nil, err
+		}
+	} else {
+		on := []string{"_time", "_measurement"}
+		for _, key := range groups[0].key {
+			switch key {
+			case "_measurement", "_start", "_stop":
+			default:
+				on = append(on, key)
+			}
+		}
+		cur = Join(t, cursors, on)
+	}
 
 	// Map each of the fields into another cursor. This evaluates any lingering expressions.
 	cur, err = t.mapFields(cur)
 	if err != nil {
 		return nil, err
 	}
+
+	// Limit the number of points of each series.
+	if cur, err = t.limit(cur); err != nil {
+		return nil, err
+	}
+
+	// Write the results into the target measurement if there is one.
+	if t.stmt.Target != nil {
+		return t.into(cur, t.stmt.Target)
+	}
 	return cur, nil
 }
 
+// limit limits the points of each series to the LIMIT and OFFSET of the statement.
+func (t *transpilerState) limit(in cursor) (cursor, error) {
+	if t.stmt.SLimit > 0 || t.stmt.SOffset > 0 {
+		return nil, errors.New("unimplemented: SLIMIT and SOFFSET")
+	} else if t.stmt.Limit == 0 {
+		if t.stmt.Offset > 0 {
+			return nil, errors.New("unimplemented: OFFSET without a LIMIT")
+		}
+		return in, nil
+	}
+
+	properties := []*ast.Property{{
+		Key:   &ast.Identifier{Name: "n"},
+		Value: &ast.IntegerLiteral{Value: int64(t.stmt.Limit)},
+	}}
+	if t.stmt.Offset > 0 {
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: "offset"},
+			Value: &ast.IntegerLiteral{Value: int64(t.stmt.Offset)},
+		})
+	}
+	return &pipeCursor{
+		expr: &ast.PipeExpression{
+			Argument: in.Expr(),
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{Name: "limit"},
+				Arguments: []ast.Expression{
+					&ast.ObjectExpression{
+						Properties: properties,
+					},
+				},
+			},
+		},
+		cursor: in,
+	}, nil
+}
+
+// into writes the fields of the results into the measurement of the target.
+// The tags of the results are the tags of the points written.
+func (t *transpilerState) into(in cursor, target *influxql.Target) (cursor, error) {
+	mapping, bucket, err := t.bucket(target.Measurement)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := in.Expr()
+	if name := target.Measurement.Name; name != "" {
+		expr = &ast.PipeExpression{
+			Argument: expr,
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{
+					Name: "set",
+				},
+				Arguments: []ast.Expression{
+					&ast.ObjectExpression{
+						Properties: []*ast.Property{
+							{
+								Key: &ast.Identifier{
+									Name: "key",
+								},
+								Value: &ast.StringLiteral{
+									Value: "_measurement",
+								},
+							},
+							{
+								Key: &ast.Identifier{
+									Name: "value",
+								},
+								Value: &ast.StringLiteral{
+									Value: name,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Each of the columns of the fields is a field of the points.
+	columns := t.stmt.ColumnNames()
+	fields := make([]*ast.Property, 0, len(columns))
+	for i, f := range t.stmt.Fields {
+		if ref, ok := f.Expr.(*influxql.VarRef); ok && ref.Val == "time" {
+			continue
+		}
+		fields = append(fields, &ast.Property{
+			Key: &ast.Identifier{Name: columns[i]},
+			Value: &ast.MemberExpression{
+				Object:   &ast.Identifier{Name: "r"},
+				Property: &ast.StringLiteral{Value: columns[i]},
+			},
+		})
+	}
+
+	properties := []*ast.Property{bucket}
+	if mapping != nil {
+		properties = append(properties, &ast.Property{
+			Key: &ast.Identifier{
+				Name: "orgID",
+			},
+			Value: &ast.StringLiteral{
+				Value: mapping.OrganizationID.String(),
+			},
+		})
+	}
+	properties = append(properties, &ast.Property{
+		Key: &ast.Identifier{
+			Name: "fieldFn",
+		},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: &ast.ObjectExpression{
+				Properties: fields,
+			},
+		},
+	})
+	return &pipeCursor{
+		expr: &ast.PipeExpression{
+			Argument: expr,
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{
+					Name: "to",
+				},
+				Arguments: []ast.Expression{
+					&ast.ObjectExpression{
+						Properties: properties,
+					},
+				},
+			},
+		},
+		cursor: in,
+	}, nil
+}
+
 func (t *transpilerState) mapType(ref *influxql.VarRef) influxql.DataType {
 	// TODO(jsternberg): Actually evaluate the type against the schema.
 	return influxql.Tag
 }
 
 func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error) {
+	_, bucket, err := t.bucket(m)
+	if err != nil {
+		return nil, err
+	}
+	return &ast.CallExpression{
+		Callee: &ast.Identifier{
+			Name: "from",
+		},
+		Arguments: []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: []*ast.Property{bucket},
+			},
+		},
+	}, nil
+}
+
+// bucket finds the bucket of the database and retention policy of the measurement.
+// It returns the mapping of the bucket, if there is one, and the property identifying
+// the bucket in the arguments of from() and to().
+func (t *transpilerState) bucket(m *influxql.Measurement) (*platform.DBRPMapping, *ast.Property, error) {
 	db, rp := m.Database, m.RetentionPolicy
 	if db == "" {
 		if t.config.DefaultDatabase == "" {
-			return nil, errors.New("database is required")
+			return nil, nil, errors.New("database is required")
 		}
 		db = t.config.DefaultDatabase
 	}
@@ -667,7 +848,9 @@ func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error)
 	}
 
 	var filter platform.DBRPMappingFilter
-	filter.Cluster = &t.config.Cluster
+	if t.config.Cluster != "" {
+		filter.Cluster = &t.config.Cluster
+	}
 	if db != "" {
 		filter.Database = &db
 	}
@@ -677,49 +860,32 @@ func (t *transpilerState) from(m *influxql.Measurement) (ast.Expression, error)
 	defaultRP := rp == ""
 	filter.Default = &defaultRP
 	mapping, err := t.dbrpMappingSvc.Find(context.TODO(), filter)
-	var args []ast.Expression
 	if err != nil {
 		if !t.config.FallbackToDBRP {
-			return nil, err
+			return nil, nil, err
 		}
 		// use `db/rp` naming convention
-		args = []ast.Expression{
-			&ast.ObjectExpression{
-				Properties: []*ast.Property{
-					{
-						Key: &ast.Identifier{
-							Name: "bucket",
-						},
-						Value: &ast.StringLiteral{
-							Value: fmt.Sprintf("%s/%s", db, rp),
-						},
-					},
-				},
-			},
+		if rp == "" {
+			rp = DefaultRetentionPolicy
 		}
-	} else {
-		// use mapping bucket id
-		args = []ast.Expression{
-			&ast.ObjectExpression{
-				Properties: []*ast.Property{
-					{
-						Key: &ast.Identifier{
-							Name: "bucketID",
-						},
-						Value: &ast.StringLiteral{
-							Value: mapping.BucketID.String(),
-						},
-					},
-				},
+		return nil, &ast.Property{
+			Key: &ast.Identifier{
+				Name: "bucket",
 			},
-		}
+			Value: &ast.StringLiteral{
+				Value: fmt.Sprintf("%s/%s", db, rp),
+			},
+		}, nil
 	}
 
-	return &ast.CallExpression{
-		Callee: &ast.Identifier{
-			Name: "from",
+	// use mapping bucket id
+	return mapping, &ast.Property{
+		Key: &ast.Identifier{
+			Name: "bucketID",
+		},
+		Value: &ast.StringLiteral{
+			Value: mapping.BucketID.String(),
 		},
-		Arguments: args,
 	}, nil
 }
 
@@ -0,0 +1,72 @@
+package promql
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb"
+)
+
+const CompilerType = "promql"
+
+// AddCompilerMappings adds the promql specific compiler mappings.
+func AddCompilerMappings(mappings flux.CompilerMappings) error {
+	return mappings.Add(CompilerType, func() flux.Compiler {
+		return new(Compiler)
+	})
+}
+
+// Compiler is the transpiler to convert PromQL to a Flux program.
+type Compiler struct {
+	BucketID      influxdb.ID   `json:"bucketID"`
+	Query         string        `json:"query"`
+	Start         time.Time     `json:"start"`
+	End           time.Time     `json:"end"`
+	Step          time.Duration `json:"step,omitempty"`
+	LookbackDelta time.Duration `json:"lookbackDelta,omitempty"`
+	Now           *time.Time    `json:"now,omitempty"`
+
+	logicalPlannerOptions []plan.LogicalOption
+}
+
+var _ flux.Compiler = &Compiler{}
+
+// Transpile transpiles the query into a Flux package.
+func (c *Compiler) Transpile() (*ast.Package, error) {
+	transpiler := NewTranspiler(Config{
+		BucketID:      c.BucketID,
+		Start:         c.Start,
+		End:           c.End,
+		Step:          c.Step,
+		LookbackDelta: c.LookbackDelta,
+	})
+	return transpiler.Transpile(c.Query)
+}
+
+// Compile transpiles the query into a Program.
+func (c *Compiler) Compile(ctx context.Context) (flux.Program, error) {
+	var now time.Time
+	if c.Now != nil {
+		now = *c.Now
+	} else {
+		now = time.Now()
+	}
+	astPkg, err := c.Transpile()
+	if err != nil {
+		return nil, err
+	}
+	compileOptions := lang.WithLogPlanOpts(c.logicalPlannerOptions...)
+	return lang.CompileAST(astPkg, now, compileOptions), nil
+}
+
+func (c *Compiler) CompilerType() flux.CompilerType {
+	return CompilerType
+}
+
+func (c *Compiler) WithLogicalPlannerOptions(opts ...plan.LogicalOption) {
+	c.logicalPlannerOptions = opts
+}
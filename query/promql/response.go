@@ -0,0 +1,61 @@
+package promql
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+const (
+	// MatrixResultType is the type of results holding the samples of series
+	// at several times.
+	MatrixResultType = "matrix"
+	// VectorResultType is the type of results holding a sample of each series
+	// at a single time.
+	VectorResultType = "vector"
+)
+
+// Response is the response of the query API of Prometheus.
+type Response struct {
+	Status    string `json:"status"`
+	Data      *Data  `json:"data,omitempty"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Data holds the results of a query.
+type Data struct {
+	ResultType string    `json:"resultType"`
+	Result     []*Series `json:"result"`
+}
+
+// Series is a series of a result. Series of matrices have values and series
+// of vectors have a single value.
+type Series struct {
+	Metric map[string]string `json:"metric"`
+	Values []Sample          `json:"values,omitempty"`
+	Value  *Sample           `json:"value,omitempty"`
+}
+
+// Sample is the value of a series at a time in milliseconds since the epoch.
+// It is encoded as the pair of its time in seconds and its value as a string.
+type Sample struct {
+	Time  int64
+	Value float64
+}
+
+func (s Sample) MarshalJSON() ([]byte, error) {
+	var v string
+	switch {
+	case math.IsInf(s.Value, 1):
+		v = "+Inf"
+	case math.IsInf(s.Value, -1):
+		v = "-Inf"
+	default:
+		v = strconv.FormatFloat(s.Value, 'f', -1, 64)
+	}
+	return json.Marshal([]interface{}{
+		json.Number(strconv.FormatFloat(float64(s.Time)/1e3, 'f', -1, 64)),
+		v,
+	})
+}
@@ -0,0 +1,155 @@
+package promql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/iocounter"
+)
+
+// MultiResultEncoder encodes results as the JSON of the query API of
+// Prometheus.
+type MultiResultEncoder struct {
+	// ResultType is the type of the results; defaults to a matrix.
+	ResultType string
+}
+
+// Encode writes the series of the results into w.
+// Expectations/Assumptions:
+//  1. The string columns of the group key of a table are the labels of its
+//     series, and the _measurement column is the name of its metric. The
+//     _field column is ignored.
+//  2. The _time and _value columns of a table are the samples of its series.
+//     Tables with the same labels are the same series.
+//  3. The series of vectors have a single sample, and only their latest
+//     sample is kept otherwise.
+//
+// Nothing is written if the results fail, so that the caller may respond
+// with the error.
+func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	resultType := e.ResultType
+	if resultType == "" {
+		resultType = MatrixResultType
+	}
+
+	series := make(map[string]*Series)
+	for results.More() {
+		res := results.Next()
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			metric := make(map[string]string)
+			for j, c := range tbl.Key().Cols() {
+				if c.Type != flux.TString {
+					continue
+				}
+				v := tbl.Key().ValueString(j)
+				switch c.Label {
+				case "_measurement":
+					metric[MetricNameLabel] = v
+				case "_field":
+				default:
+					metric[c.Label] = v
+				}
+			}
+
+			key := metricKey(metric)
+			s, ok := series[key]
+			if !ok {
+				s = &Series{Metric: metric}
+				series[key] = s
+			}
+
+			timeIdx := execute.ColIdx(execute.DefaultTimeColLabel, tbl.Cols())
+			valueIdx := execute.ColIdx(execute.DefaultValueColLabel, tbl.Cols())
+			if timeIdx < 0 || valueIdx < 0 {
+				return fmt.Errorf("table of series %s is missing the %s or %s column", key, execute.DefaultTimeColLabel, execute.DefaultValueColLabel)
+			}
+			return tbl.Do(func(cr flux.ColReader) error {
+				times := cr.Times(timeIdx)
+				for i := 0; i < cr.Len(); i++ {
+					if times.IsNull(i) {
+						continue
+					}
+					v, ok := sampleValue(cr, valueIdx, i)
+					if !ok {
+						continue
+					}
+					s.Values = append(s.Values, Sample{
+						Time:  times.Value(i) / int64(time.Millisecond),
+						Value: v,
+					})
+				}
+				return nil
+			})
+		}); err != nil {
+			results.Release()
+			return 0, err
+		}
+	}
+	results.Release()
+	if err := results.Err(); err != nil {
+		return 0, err
+	}
+
+	resp := Response{
+		Status: "success",
+		Data: &Data{
+			ResultType: resultType,
+			Result:     make([]*Series, 0, len(series)),
+		},
+	}
+	keys := make([]string, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := series[k]
+		if len(s.Values) == 0 {
+			continue
+		}
+		sort.SliceStable(s.Values, func(i, j int) bool { return s.Values[i].Time < s.Values[j].Time })
+		if resultType == VectorResultType {
+			s.Value = &s.Values[len(s.Values)-1]
+			s.Values = nil
+		}
+		resp.Data.Result = append(resp.Data.Result, s)
+	}
+
+	wc := &iocounter.Writer{Writer: w}
+	err := json.NewEncoder(wc).Encode(resp)
+	return wc.Count(), err
+}
+
+// sampleValue returns the value of the column j of the row i of cr as a
+// float, and false if the value is null or not a number.
+func sampleValue(cr flux.ColReader, j, i int) (float64, bool) {
+	switch cr.Cols()[j].Type {
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		return vs.Value(i), vs.IsValid(i)
+	case flux.TInt:
+		vs := cr.Ints(j)
+		return float64(vs.Value(i)), vs.IsValid(i)
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		return float64(vs.Value(i)), vs.IsValid(i)
+	default:
+		return 0, false
+	}
+}
+
+// metricKey returns a key identifying the labels of metric.
+func metricKey(metric map[string]string) string {
+	labels := make([]string, 0, len(metric))
+	for k, v := range metric {
+		labels = append(labels, fmt.Sprintf("%s=%q", k, v))
+	}
+	sort.Strings(labels)
+	return "{" + strings.Join(labels, ",") + "}"
+}
@@ -0,0 +1,453 @@
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+)
+
+const (
+	// DefaultLookbackDelta is the duration before an evaluation time in
+	// which the latest sample of a series is its value, as in Prometheus.
+	DefaultLookbackDelta = 5 * time.Minute
+
+	// MetricNameLabel is the label of the name of a metric.
+	MetricNameLabel = "__name__"
+
+	// ValueField is the field storing the samples of a metric.
+	ValueField = "value"
+)
+
+// Config is the configuration of the evaluation of a PromQL query.
+//
+// The samples of a metric are read from the value field of the measurement
+// named after the metric, and the labels of the metric are its tags.
+type Config struct {
+	// BucketID is the bucket the metrics are read from.
+	BucketID influxdb.ID
+
+	// Start and End are the first and last evaluation times of the query.
+	// Instant queries have the same start and end.
+	Start, End time.Time
+
+	// Step is the duration between the evaluation times of range queries.
+	Step time.Duration
+
+	// LookbackDelta defaults to DefaultLookbackDelta.
+	LookbackDelta time.Duration
+}
+
+// ResultType returns the type of the results of a query, which is a vector
+// for an instant query of an instant vector and a matrix otherwise.
+func (c Config) ResultType(expr interface{}) string {
+	if sel, ok := expr.(*Selector); c.Start.Equal(c.End) && (!ok || sel.Range == 0) {
+		return VectorResultType
+	}
+	return MatrixResultType
+}
+
+// Transpiler converts PromQL queries into Flux.
+type Transpiler struct {
+	Config Config
+}
+
+// NewTranspiler returns a transpiler evaluating queries with cfg.
+func NewTranspiler(cfg Config) *Transpiler {
+	return &Transpiler{Config: cfg}
+}
+
+// Transpile parses txt and returns the Flux package evaluating it.
+func (t *Transpiler) Transpile(txt string) (*ast.Package, error) {
+	parsed, err := ParsePromQL(txt)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := t.transpile(parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &ast.Package{
+		Package: "main",
+		Files: []*ast.File{{
+			Package: &ast.PackageClause{
+				Name: &ast.Identifier{Name: "main"},
+			},
+			Body: []ast.Statement{
+				&ast.ExpressionStatement{Expression: expr},
+			},
+		}},
+	}, nil
+}
+
+func (t *Transpiler) transpile(parsed interface{}) (ast.Expression, error) {
+	cfg := t.Config
+	if cfg.End.Before(cfg.Start) {
+		return nil, errors.New("end time must not be before start time")
+	}
+	if !cfg.Start.Equal(cfg.End) && cfg.Step <= 0 {
+		return nil, errors.New("range queries require a positive step")
+	}
+
+	switch expr := parsed.(type) {
+	case *Selector:
+		if expr.Range != 0 {
+			if !cfg.Start.Equal(cfg.End) {
+				return nil, fmt.Errorf("invalid expression type %q for range query, must be an instant vector", "range vector")
+			}
+			return t.rangeSelector(expr)
+		}
+		return t.instantSelector(expr)
+	case *AggregateExpr:
+		if expr.Selector.Range != 0 {
+			return nil, fmt.Errorf("expected type instant vector in aggregation expression, got range vector")
+		}
+		sel, err := t.instantSelector(expr.Selector)
+		if err != nil {
+			return nil, err
+		}
+		return t.aggregate(sel, expr)
+	case *Comment:
+		return nil, errors.New("query is a comment")
+	default:
+		return nil, fmt.Errorf("unsupported expression %T", parsed)
+	}
+}
+
+// instantSelector returns the latest sample of each series at each
+// evaluation time which is no older than the lookback delta.
+//
+// Every evaluation time t is the stop of a window holding the samples in
+// (t-lookback, t]; since Flux windows exclude their stop, the windows end a
+// nanosecond after t and the times of the points are shifted back.
+func (t *Transpiler) instantSelector(sel *Selector) (ast.Expression, error) {
+	cfg := t.Config
+	lookback := cfg.LookbackDelta
+	if lookback <= 0 {
+		lookback = DefaultLookbackDelta
+	}
+	step := cfg.Step
+	if cfg.Start.Equal(cfg.End) {
+		step = lookback
+	}
+
+	// The last evaluation time is the last step at or before end.
+	start := cfg.Start.Add(-sel.Offset)
+	end := start.Add(cfg.End.Sub(cfg.Start) / step * step)
+	stop := end.Add(time.Nanosecond)
+
+	// Flux clips the windows which stop after the range, so the range is
+	// extended by a step to drop the clipped windows with the others which
+	// stop after the last evaluation time.
+	expr, err := t.from(sel, start.Add(-lookback).Add(time.Nanosecond), stop.Add(step))
+	if err != nil {
+		return nil, err
+	}
+	offset := time.Duration(stop.UnixNano() % int64(step))
+	if offset < 0 {
+		offset += step
+	}
+	expr = pipe(expr, "window",
+		property("every", duration(step)),
+		property("period", duration(lookback)),
+		property("offset", duration(offset)))
+	expr = pipe(expr, "last")
+	expr = pipe(expr, "duplicate", property("column", str("_stop")), property("as", str("_time")))
+	expr = pipe(expr, "drop", property("columns", strs("_start", "_stop", "_field")))
+	expr = pipe(expr, "filter", property("fn", fn(&ast.LogicalExpression{
+		Operator: ast.AndOperator,
+		Left: &ast.BinaryExpression{
+			Operator: ast.GreaterThanOperator,
+			Left:     member("_time"),
+			Right:    &ast.DateTimeLiteral{Value: start.UTC()},
+		},
+		Right: &ast.BinaryExpression{
+			Operator: ast.LessThanEqualOperator,
+			Left:     member("_time"),
+			Right:    &ast.DateTimeLiteral{Value: stop.UTC()},
+		},
+	})))
+	expr = pipe(expr, "timeShift",
+		property("duration", duration(sel.Offset-time.Nanosecond)),
+		property("columns", strs("_time")))
+	return pipe(expr, "group", property("columns", strs("_time", "_value")), property("mode", str("except"))), nil
+}
+
+// rangeSelector returns the samples of each series in the range of the
+// selector before the evaluation time.
+func (t *Transpiler) rangeSelector(sel *Selector) (ast.Expression, error) {
+	stop := t.Config.End.Add(-sel.Offset).Add(time.Nanosecond)
+	expr, err := t.from(sel, stop.Add(-sel.Range), stop)
+	if err != nil {
+		return nil, err
+	}
+	expr = pipe(expr, "drop", property("columns", strs("_start", "_stop", "_field")))
+	if sel.Offset != 0 {
+		expr = pipe(expr, "timeShift",
+			property("duration", duration(sel.Offset)),
+			property("columns", strs("_time")))
+	}
+	return pipe(expr, "group", property("columns", strs("_time", "_value")), property("mode", str("except"))), nil
+}
+
+// from returns the samples of the series matching sel in [start, stop).
+func (t *Transpiler) from(sel *Selector, start, stop time.Time) (ast.Expression, error) {
+	var body ast.Expression = &ast.LogicalExpression{
+		Operator: ast.AndOperator,
+		Left: &ast.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left:     member("_measurement"),
+			Right:    str(sel.Name),
+		},
+		Right: &ast.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left:     member("_field"),
+			Right:    str(ValueField),
+		},
+	}
+	for _, m := range sel.LabelMatchers {
+		cond, err := labelMatcher(m)
+		if err != nil {
+			return nil, err
+		}
+		body = &ast.LogicalExpression{
+			Operator: ast.AndOperator,
+			Left:     body,
+			Right:    cond,
+		}
+	}
+
+	expr := ast.Expression(&ast.CallExpression{
+		Callee: &ast.Identifier{Name: "from"},
+		Arguments: []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: []*ast.Property{
+					property("bucketID", str(t.Config.BucketID.String())),
+				},
+			},
+		},
+	})
+	expr = pipe(expr, "range",
+		property("start", &ast.DateTimeLiteral{Value: start.UTC()}),
+		property("stop", &ast.DateTimeLiteral{Value: stop.UTC()}))
+	return pipe(expr, "filter", property("fn", fn(body))), nil
+}
+
+// labelMatcher returns the condition of m on the tags of a series. Label
+// matchers of empty values also match the series without the label, and the
+// regular expressions of Prometheus are anchored.
+func labelMatcher(m *LabelMatcher) (ast.Expression, error) {
+	var value string
+	switch v := m.Value.Value().(type) {
+	case string:
+		value = v
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("unsupported value %v for label %s", v, m.Name)
+	}
+	ref := member(labelColumn(m.Name))
+
+	switch m.Kind {
+	case Equal, NotEqual:
+		if value == "" {
+			var cond ast.Expression = &ast.UnaryExpression{Operator: ast.ExistsOperator, Argument: ref}
+			if m.Kind == Equal {
+				cond = &ast.UnaryExpression{Operator: ast.NotOperator, Argument: cond}
+			}
+			return cond, nil
+		}
+		op := ast.EqualOperator
+		if m.Kind == NotEqual {
+			op = ast.NotEqualOperator
+		}
+		return &ast.BinaryExpression{Operator: op, Left: ref, Right: str(value)}, nil
+	case RegexMatch, RegexNoMatch:
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression for label %s: %v", m.Name, err)
+		}
+		op := ast.RegexpMatchOperator
+		if m.Kind == RegexNoMatch {
+			op = ast.NotRegexpMatchOperator
+		}
+		cond := ast.Expression(&ast.BinaryExpression{Operator: op, Left: ref, Right: &ast.RegexpLiteral{Value: re}})
+		if re.MatchString("") {
+			// A missing label has the empty value.
+			missing := &ast.UnaryExpression{
+				Operator: ast.NotOperator,
+				Argument: &ast.UnaryExpression{Operator: ast.ExistsOperator, Argument: ref},
+			}
+			if m.Kind == RegexMatch {
+				return &ast.LogicalExpression{Operator: ast.OrOperator, Left: missing, Right: cond}, nil
+			}
+			return &ast.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left:     &ast.UnaryExpression{Operator: ast.ExistsOperator, Argument: ref},
+				Right:    cond,
+			}, nil
+		}
+		return cond, nil
+	default:
+		return nil, fmt.Errorf("unknown label match kind %d", m.Kind)
+	}
+}
+
+// aggregate returns the aggregate of expr of the series of each group at
+// each evaluation time.
+func (t *Transpiler) aggregate(expr ast.Expression, agg *AggregateExpr) (ast.Expression, error) {
+	// The points of a series are grouped by their labels and time, and the
+	// points of each group are aggregated.
+	var labels []string
+	without := agg.Aggregate != nil && agg.Aggregate.Without
+	if agg.Aggregate != nil {
+		for _, l := range agg.Aggregate.Labels {
+			labels = append(labels, labelColumn(l.Name))
+		}
+	}
+	if without {
+		expr = pipe(expr, "group",
+			property("columns", strs(append(labels, "_measurement", "_value")...)),
+			property("mode", str("except")))
+	} else {
+		expr = pipe(expr, "group", property("columns", strs(append(labels, "_time")...)))
+	}
+
+	var series bool
+	switch agg.Op.Kind {
+	case SumKind:
+		expr = pipe(expr, "sum")
+	case MinKind:
+		expr = pipe(expr, "min")
+	case MaxKind:
+		expr = pipe(expr, "max")
+	case AvgKind:
+		expr = pipe(expr, "mean")
+	case CountKind:
+		expr = pipe(expr, "count")
+	case StdevKind:
+		expr = pipe(expr, "stddev", property("mode", str("population")))
+	case StdVarKind:
+		expr = pipe(expr, "stddev", property("mode", str("population")))
+		expr = pipe(expr, "map", property("fn", fn(&ast.ObjectExpression{
+			With: &ast.Identifier{Name: "r"},
+			Properties: []*ast.Property{
+				property("_value", &ast.BinaryExpression{
+					Operator: ast.MultiplicationOperator,
+					Left:     member("_value"),
+					Right:    member("_value"),
+				}),
+			},
+		})))
+	case TopKind, BottomKind:
+		n, ok := agg.Op.Arg.Value().(float64)
+		if !ok || n < 1 {
+			return nil, fmt.Errorf("invalid parameter for %s", opName(agg.Op.Kind))
+		}
+		name := "top"
+		if agg.Op.Kind == BottomKind {
+			name = "bottom"
+		}
+		expr = pipe(expr, name, property("n", &ast.IntegerLiteral{Value: int64(n)}))
+		// Topk and bottomk select series, which keep all of their labels.
+		series = true
+	default:
+		return nil, fmt.Errorf("unsupported aggregation %s", opName(agg.Op.Kind))
+	}
+
+	switch {
+	case series:
+		return pipe(expr, "group", property("columns", strs("_time", "_value")), property("mode", str("except"))), nil
+	case without:
+		return pipe(expr, "group",
+			property("columns", strs(append(labels, "_measurement", "_time", "_value")...)),
+			property("mode", str("except"))), nil
+	default:
+		return pipe(expr, "group", property("columns", strs(labels...))), nil
+	}
+}
+
+// labelColumn returns the column of label.
+func labelColumn(label string) string {
+	if label == MetricNameLabel {
+		return "_measurement"
+	}
+	return label
+}
+
+func opName(kind OperatorKind) string {
+	switch kind {
+	case CountValuesKind:
+		return "count_values"
+	case TopKind:
+		return "topk"
+	case BottomKind:
+		return "bottomk"
+	case QuantileKind:
+		return "quantile"
+	case SumKind:
+		return "sum"
+	case MinKind:
+		return "min"
+	case MaxKind:
+		return "max"
+	case AvgKind:
+		return "avg"
+	case StdevKind:
+		return "stddev"
+	case StdVarKind:
+		return "stdvar"
+	case CountKind:
+		return "count"
+	default:
+		return strconv.Itoa(int(kind))
+	}
+}
+
+func pipe(arg ast.Expression, name string, properties ...*ast.Property) ast.Expression {
+	call := &ast.CallExpression{Callee: &ast.Identifier{Name: name}}
+	if len(properties) > 0 {
+		call.Arguments = []ast.Expression{&ast.ObjectExpression{Properties: properties}}
+	}
+	return &ast.PipeExpression{Argument: arg, Call: call}
+}
+
+func property(key string, value ast.Expression) *ast.Property {
+	return &ast.Property{Key: &ast.Identifier{Name: key}, Value: value}
+}
+
+func fn(body ast.Node) *ast.FunctionExpression {
+	return &ast.FunctionExpression{
+		Params: []*ast.Property{{Key: &ast.Identifier{Name: "r"}}},
+		Body:   body,
+	}
+}
+
+func member(column string) *ast.MemberExpression {
+	return &ast.MemberExpression{
+		Object:   &ast.Identifier{Name: "r"},
+		Property: &ast.StringLiteral{Value: column},
+	}
+}
+
+func str(s string) *ast.StringLiteral {
+	return &ast.StringLiteral{Value: s}
+}
+
+func strs(ss ...string) *ast.ArrayExpression {
+	arr := &ast.ArrayExpression{Elements: make([]ast.Expression, len(ss))}
+	for i, s := range ss {
+		arr.Elements[i] = str(s)
+	}
+	return arr
+}
+
+func duration(d time.Duration) *ast.DurationLiteral {
+	return &ast.DurationLiteral{
+		Values: []ast.Duration{{Magnitude: int64(d), Unit: ast.NanosecondUnit}},
+	}
+}
@@ -0,0 +1,101 @@
+package promql
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+)
+
+func TestTranspiler_Transpile(t *testing.T) {
+	ts := time.Unix(946684800, 0)
+	tests := []struct {
+		name    string
+		config  Config
+		promql  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "instant vector",
+			config: Config{BucketID: 0x1234, Start: ts, End: ts},
+			promql: `up{job="api",env=""}`,
+			want: `from(bucketID: "0000000000001234")
+	|> range(start: 1999-12-31T23:55:00.000000001Z, stop: 2000-01-01T00:05:00.000000001Z)
+	|> filter(fn: (r) =>
+		(r["_measurement"] == "up" and r["_field"] == "value" and r["job"] == "api" and not exists r["env"]))
+	|> window(every: 300000000000ns, period: 300000000000ns, offset: 1ns)
+	|> last()
+	|> duplicate(column: "_stop", as: "_time")
+	|> drop(columns: ["_start", "_stop", "_field"])
+	|> filter(fn: (r) =>
+		(r["_time"] > 2000-01-01T00:00:00Z and r["_time"] <= 2000-01-01T00:00:00.000000001Z))
+	|> timeShift(duration: -1ns, columns: ["_time"])
+	|> group(columns: ["_time", "_value"], mode: "except")`,
+		},
+		{
+			name:   "range query aggregate",
+			config: Config{BucketID: 0x1234, Start: ts, End: ts.Add(10 * time.Minute), Step: time.Minute},
+			promql: `sum(up{job=~"a.*"}) by (job)`,
+			want: `from(bucketID: "0000000000001234")
+	|> range(start: 1999-12-31T23:55:00.000000001Z, stop: 2000-01-01T00:11:00.000000001Z)
+	|> filter(fn: (r) =>
+		(r["_measurement"] == "up" and r["_field"] == "value" and r["job"] =~ /^(?:a.*)$/))
+	|> window(every: 60000000000ns, period: 300000000000ns, offset: 1ns)
+	|> last()
+	|> duplicate(column: "_stop", as: "_time")
+	|> drop(columns: ["_start", "_stop", "_field"])
+	|> filter(fn: (r) =>
+		(r["_time"] > 2000-01-01T00:00:00Z and r["_time"] <= 2000-01-01T00:10:00.000000001Z))
+	|> timeShift(duration: -1ns, columns: ["_time"])
+	|> group(columns: ["_time", "_value"], mode: "except")
+	|> group(columns: ["job", "_time"])
+	|> sum()
+	|> group(columns: ["job"])`,
+		},
+		{
+			name:   "range vector",
+			config: Config{BucketID: 0x1234, Start: ts, End: ts},
+			promql: `up[1m] offset 5m`,
+			want: `from(bucketID: "0000000000001234")
+	|> range(start: 1999-12-31T23:54:00.000000001Z, stop: 1999-12-31T23:55:00.000000001Z)
+	|> filter(fn: (r) =>
+		(r["_measurement"] == "up" and r["_field"] == "value"))
+	|> drop(columns: ["_start", "_stop", "_field"])
+	|> timeShift(duration: 300000000000ns, columns: ["_time"])
+	|> group(columns: ["_time", "_value"], mode: "except")`,
+		},
+		{
+			name:    "range vector in range query",
+			config:  Config{BucketID: 0x1234, Start: ts, End: ts.Add(time.Hour), Step: time.Minute},
+			promql:  `up[1m]`,
+			wantErr: `invalid expression type "range vector" for range query, must be an instant vector`,
+		},
+		{
+			name:    "unsupported aggregation",
+			config:  Config{BucketID: 0x1234, Start: ts, End: ts},
+			promql:  `quantile(0.9, up)`,
+			wantErr: `unsupported aggregation quantile`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, err := NewTranspiler(tt.config).Transpile(tt.promql)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("unexpected error: got %v want %s", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			got := strings.TrimSpace(strings.TrimPrefix(ast.Format(pkg.Files[0]), "package main"))
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("unexpected flux -got/+want\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
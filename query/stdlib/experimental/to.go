@@ -195,6 +195,9 @@ type ToTransformation struct {
 	spec     *ToOpSpec
 	deps     influxdb.ToDependencies
 	buf      *storage.BufferedPointsWriter
+	// series matches the series the authorizer of the context may write to
+	// the bucket, all the series if nil.
+	series *platform.SeriesMatcher
 }
 
 // RetractTable retracts the table for the transformation for the `to` flux function.
@@ -257,6 +260,10 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 			return nil, fmt.Errorf("failed to look up bucket with ID %q in org %q", bucketID, org)
 		}
 	}
+	series, err := influxdb.WriteSeriesMatcher(ctx, orgID, *bucketID)
+	if err != nil {
+		return nil, err
+	}
 	return &ToTransformation{
 		ctx:      ctx,
 		bucket:   bucket,
@@ -268,6 +275,7 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 		spec:     spec.Spec,
 		deps:     deps,
 		buf:      storage.NewBufferedPointsWriter(influxdb.DefaultBufferSize, deps.PointsWriter),
+		series:   series,
 	}, nil
 }
 
@@ -418,6 +426,21 @@ func (t *ToTransformation) writeTable(ctx context.Context, tbl flux.Table) error
 		return err
 	}
 
+	if t.series != nil {
+		// the series of the points of the table only differ by their field.
+		tags, err := models.NewTagsKeyValues(nil, tmd.Tags...)
+		if err != nil {
+			return err
+		}
+		measurement := tags.GetString(models.MeasurementTagKey)
+		if !t.series.Match(measurement, tags.GetString) {
+			return &flux.Error{
+				Code: codes.PermissionDenied,
+				Msg:  fmt.Sprintf("series of measurement %q is outside of the series scope of the authorization", measurement),
+			}
+		}
+	}
+
 	pointName := tsdb.EncodeNameString(t.orgID, t.bucketID)
 	return tbl.Do(func(cr flux.ColReader) error {
 		if cr.Len() == 0 {
@@ -0,0 +1,22 @@
+// Package downsample provides the Flux functions used by the tasks that
+// materialize the downsample policies of buckets.
+package downsample
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/parser"
+)
+
+// PackagePath is the import path of the downsample Flux package.
+const PackagePath = "influxdata/influxdb/downsample"
+
+const packageSource = `package downsample
+
+builtin fieldType
+`
+
+func init() {
+	pkg := parser.ParseSource(packageSource)
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+}
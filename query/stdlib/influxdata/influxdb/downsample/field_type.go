@@ -0,0 +1,157 @@
+package downsample
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+)
+
+const FieldTypeKind = "downsampleFieldType"
+
+// fieldTypes maps the names of the field types to the type of the value
+// column of the tables read from storage.
+var fieldTypes = map[string]flux.ColType{
+	"float":    flux.TFloat,
+	"integer":  flux.TInt,
+	"unsigned": flux.TUInt,
+	"boolean":  flux.TBool,
+	"string":   flux.TString,
+}
+
+// FieldTypeOpSpec keeps the tables whose value column holds fields of one type.
+type FieldTypeOpSpec struct {
+	Type   string `json:"type"`
+	Column string `json:"column"`
+}
+
+func init() {
+	fieldTypeSignature := flux.FunctionSignature(
+		map[string]semantic.PolyType{
+			"type":   semantic.String,
+			"column": semantic.String,
+		},
+		[]string{"type"},
+	)
+
+	flux.RegisterPackageValue(PackagePath, "fieldType", flux.FunctionValue(FieldTypeKind, createFieldTypeOpSpec, fieldTypeSignature))
+	flux.RegisterOpSpec(FieldTypeKind, newFieldTypeOp)
+	plan.RegisterProcedureSpec(FieldTypeKind, newFieldTypeProcedure, FieldTypeKind)
+	execute.RegisterTransformation(FieldTypeKind, createFieldTypeTransformation)
+}
+
+func createFieldTypeOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := new(FieldTypeOpSpec)
+	typ, err := args.GetRequiredString("type")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fieldTypes[typ]; !ok {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  fmt.Sprintf("unknown field type %q", typ),
+		}
+	}
+	spec.Type = typ
+
+	spec.Column = execute.DefaultValueColLabel
+	if col, ok, err := args.GetString("column"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Column = col
+	}
+	return spec, nil
+}
+
+func newFieldTypeOp() flux.OperationSpec {
+	return new(FieldTypeOpSpec)
+}
+
+func (s *FieldTypeOpSpec) Kind() flux.OperationKind {
+	return FieldTypeKind
+}
+
+type FieldTypeProcedureSpec struct {
+	plan.DefaultCost
+	Type   flux.ColType
+	Column string
+}
+
+func newFieldTypeProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FieldTypeOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	return &FieldTypeProcedureSpec{
+		Type:   fieldTypes[spec.Type],
+		Column: spec.Column,
+	}, nil
+}
+
+func (s *FieldTypeProcedureSpec) Kind() plan.ProcedureKind {
+	return FieldTypeKind
+}
+
+func (s *FieldTypeProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createFieldTypeTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*FieldTypeProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	t, d := NewFieldTypeTransformation(id, s)
+	return t, d, nil
+}
+
+// fieldTypeTransformation passes the tables with a value column of the
+// requested type through unchanged and discards all of the others. Dropping
+// the tables, instead of filtering their rows, allows the aggregates that
+// follow to only see the column type that they support.
+type fieldTypeTransformation struct {
+	d      *execute.PassthroughDataset
+	typ    flux.ColType
+	column string
+}
+
+func NewFieldTypeTransformation(id execute.DatasetID, spec *FieldTypeProcedureSpec) (execute.Transformation, execute.Dataset) {
+	t := &fieldTypeTransformation{
+		d:      execute.NewPassthroughDataset(id),
+		typ:    spec.Type,
+		column: spec.Column,
+	}
+	return t, t.d
+}
+
+func (t *fieldTypeTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *fieldTypeTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	if idx := execute.ColIdx(t.column, tbl.Cols()); idx < 0 || tbl.Cols()[idx].Type != t.typ {
+		tbl.Done()
+		return nil
+	}
+	return t.d.Process(tbl)
+}
+
+func (t *fieldTypeTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *fieldTypeTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *fieldTypeTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
@@ -0,0 +1,123 @@
+package downsample_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/querytest"
+	"github.com/influxdata/flux/stdlib/universe"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/downsample"
+)
+
+func TestFieldType_Query(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name: "from range fieldType",
+			Raw: `import "influxdata/influxdb/downsample"
+from(bucket:"mydb")
+  |> range(start: -1h)
+  |> downsample.fieldType(type: "boolean")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "influxDBFrom0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: "mydb",
+						},
+					},
+					{
+						ID: "range1",
+						Spec: &universe.RangeOpSpec{
+							Start:       flux.Time{IsRelative: true, Relative: -time.Hour},
+							Stop:        flux.Time{IsRelative: true},
+							TimeColumn:  "_time",
+							StartColumn: "_start",
+							StopColumn:  "_stop",
+						},
+					},
+					{
+						ID: "downsampleFieldType2",
+						Spec: &downsample.FieldTypeOpSpec{
+							Type:   "boolean",
+							Column: "_value",
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "influxDBFrom0", Child: "range1"},
+					{Parent: "range1", Child: "downsampleFieldType2"},
+				},
+			},
+		},
+		{
+			Name: "unknown type",
+			Raw: `import "influxdata/influxdb/downsample"
+from(bucket:"mydb") |> range(start: -1h) |> downsample.fieldType(type: "int")`,
+			WantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+func TestFieldType_Process(t *testing.T) {
+	data := []flux.Table{
+		&executetest.Table{
+			KeyCols: []string{"_field"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), "f", 1.0},
+				{execute.Time(2), "f", 2.0},
+			},
+		},
+		&executetest.Table{
+			KeyCols: []string{"_field"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), "s", "a"},
+			},
+		},
+	}
+	want := []*executetest.Table{
+		{
+			KeyCols: []string{"_field"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), "f", 1.0},
+				{execute.Time(2), "f", 2.0},
+			},
+		},
+	}
+
+	executetest.ProcessTestHelper2(t, data, want, nil,
+		func(id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+			return downsample.NewFieldTypeTransformation(id, &downsample.FieldTypeProcedureSpec{
+				Type:   flux.TFloat,
+				Column: execute.DefaultValueColLabel,
+			})
+		},
+	)
+}
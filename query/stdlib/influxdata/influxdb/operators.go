@@ -17,6 +17,8 @@ const (
 	ReadGroupPhysKind     = "ReadGroupPhysKind"
 	ReadTagKeysPhysKind   = "ReadTagKeysPhysKind"
 	ReadTagValuesPhysKind = "ReadTagValuesPhysKind"
+
+	ReadWindowAggregatePhysKind = "ReadWindowAggregatePhysKind"
 )
 
 type ReadGroupPhysSpec struct {
@@ -44,6 +46,33 @@ func (s *ReadGroupPhysSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// ReadWindowAggregatePhysSpec reads the points of a range aggregated
+// by storage in windows of WindowEvery nanoseconds aligned to the epoch.
+type ReadWindowAggregatePhysSpec struct {
+	plan.DefaultCost
+	ReadRangePhysSpec
+
+	WindowEvery int64
+	CreateEmpty bool
+
+	AggregateMethod string
+}
+
+func (s *ReadWindowAggregatePhysSpec) Kind() plan.ProcedureKind {
+	return ReadWindowAggregatePhysKind
+}
+
+func (s *ReadWindowAggregatePhysSpec) Copy() plan.ProcedureSpec {
+	ns := new(ReadWindowAggregatePhysSpec)
+	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
+
+	ns.WindowEvery = s.WindowEvery
+	ns.CreateEmpty = s.CreateEmpty
+
+	ns.AggregateMethod = s.AggregateMethod
+	return ns
+}
+
 type ReadRangePhysSpec struct {
 	plan.DefaultCost
 
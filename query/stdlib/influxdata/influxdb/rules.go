@@ -1,6 +1,8 @@
 package influxdb
 
 import (
+	"math"
+
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -16,6 +18,7 @@ func init() {
 		PushDownGroupRule{},
 		PushDownReadTagKeysRule{},
 		PushDownReadTagValuesRule{},
+		PushDownWindowAggregateRule{},
 		SortedPivotRule{},
 	)
 }
@@ -539,6 +542,91 @@ func isPushableFieldOperator(kind ast.OperatorKind) bool {
 	return false
 }
 
+// PushDownWindowAggregateRule matches 'ReadRange |> window() |> agg()'
+// where agg is one of mean, min, max, first, last, count or sum, as
+// written by aggregateWindow. Storage then aggregates the points of each
+// window instead of sending them all to the Flux engine.
+type PushDownWindowAggregateRule struct{}
+
+func (PushDownWindowAggregateRule) Name() string {
+	return "PushDownWindowAggregateRule"
+}
+
+// Pattern matches any node since the rule applies to several aggregates.
+// Rewrite checks the node and its predecessors.
+func (PushDownWindowAggregateRule) Pattern() plan.Pattern {
+	return plan.Any()
+}
+
+func (PushDownWindowAggregateRule) Rewrite(pn plan.Node) (plan.Node, bool, error) {
+	if !isPushableWindowAggregate(pn.ProcedureSpec()) {
+		return pn, false, nil
+	}
+
+	// The window and range must each feed only the next node.
+	if len(pn.Predecessors()) != 1 {
+		return pn, false, nil
+	}
+	windowNode := pn.Predecessors()[0]
+	if windowNode.Kind() != universe.WindowKind ||
+		len(windowNode.Successors()) != 1 || len(windowNode.Predecessors()) != 1 {
+		return pn, false, nil
+	}
+	fromNode := windowNode.Predecessors()[0]
+	if fromNode.Kind() != ReadRangePhysKind || len(fromNode.Successors()) != 1 {
+		return pn, false, nil
+	}
+	windowSpec := windowNode.ProcedureSpec().(*universe.WindowProcedureSpec)
+	fromSpec := fromNode.ProcedureSpec().(*ReadRangePhysSpec)
+
+	// Storage only computes consecutive windows of a fixed
+	// duration aligned to the epoch on the default columns.
+	window := windowSpec.Window
+	if !window.Every.Equal(window.Period) || !window.Offset.IsZero() ||
+		window.Every.Months() != 0 || !window.Every.IsPositive() ||
+		window.Every.Nanoseconds() == math.MaxInt64 {
+		return pn, false, nil
+	}
+	if windowSpec.TimeColumn != execute.DefaultTimeColLabel ||
+		windowSpec.StartColumn != execute.DefaultStartColLabel ||
+		windowSpec.StopColumn != execute.DefaultStopColLabel {
+		return pn, false, nil
+	}
+
+	return plan.CreatePhysicalNode("ReadWindowAggregate", &ReadWindowAggregatePhysSpec{
+		ReadRangePhysSpec: *fromSpec.Copy().(*ReadRangePhysSpec),
+		WindowEvery:       window.Every.Nanoseconds(),
+		CreateEmpty:       windowSpec.CreateEmpty,
+		AggregateMethod:   string(pn.Kind()),
+	}), true, nil
+}
+
+// isPushableWindowAggregate reports whether storage can compute the
+// aggregate or selector spec on the _value column.
+func isPushableWindowAggregate(spec plan.ProcedureSpec) bool {
+	switch spec := spec.(type) {
+	case *universe.MeanProcedureSpec:
+		return isValueColumns(spec.Columns)
+	case *universe.CountProcedureSpec:
+		return isValueColumns(spec.Columns)
+	case *universe.SumProcedureSpec:
+		return isValueColumns(spec.Columns)
+	case *universe.MinProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.MaxProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.FirstProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.LastProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	}
+	return false
+}
+
+func isValueColumns(cols []string) bool {
+	return len(cols) == 1 && cols[0] == execute.DefaultValueColLabel
+}
+
 // SortedPivotRule is a rule that optimizes a pivot when it is directly
 // after an influxdb from.
 type SortedPivotRule struct{}
@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -853,6 +854,140 @@ func TestPushDownGroupRule(t *testing.T) {
 	}
 }
 
+func TestPushDownWindowAggregateRule(t *testing.T) {
+	readRange := influxdb.ReadRangePhysSpec{
+		Bucket: "my-bucket",
+		Bounds: flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		},
+	}
+
+	window := func(every, period, offset time.Duration) *universe.WindowProcedureSpec {
+		return &universe.WindowProcedureSpec{
+			Window: plan.WindowSpec{
+				Every:  flux.ConvertDuration(every),
+				Period: flux.ConvertDuration(period),
+				Offset: flux.ConvertDuration(offset),
+			},
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+			CreateEmpty: true,
+		}
+	}
+	meanSpec := &universe.MeanProcedureSpec{
+		AggregateConfig: execute.AggregateConfig{Columns: []string{execute.DefaultValueColLabel}},
+	}
+
+	simple := func(name string, agg plan.PhysicalProcedureSpec) plantest.RuleTestCase {
+		return plantest.RuleTestCase{
+			Name:  name,
+			Rules: []plan.Rule{influxdb.PushDownWindowAggregateRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window(time.Minute, time.Minute, 0)),
+					plan.CreatePhysicalNode(plan.NodeID(name), agg),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", &influxdb.ReadWindowAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						WindowEvery:       int64(time.Minute),
+						CreateEmpty:       true,
+						AggregateMethod:   name,
+					}),
+				},
+			},
+		}
+	}
+	// The plan is compared against a new one rather than with NoChange
+	// since copying the window spec drops its columns.
+	noChange := func(name string, window *universe.WindowProcedureSpec, agg plan.PhysicalProcedureSpec) plantest.RuleTestCase {
+		spec := func() *plantest.PlanSpec {
+			return &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window),
+					plan.CreatePhysicalNode("agg", agg),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			}
+		}
+		return plantest.RuleTestCase{
+			Name:   name,
+			Rules:  []plan.Rule{influxdb.PushDownWindowAggregateRule{}},
+			Before: spec(),
+			After:  spec(),
+		}
+	}
+
+	valueSelector := execute.SelectorConfig{Column: execute.DefaultValueColLabel}
+	valueAggregate := execute.AggregateConfig{Columns: []string{execute.DefaultValueColLabel}}
+	otherTime := window(time.Minute, time.Minute, 0)
+	otherTime.TimeColumn = "_stop"
+
+	tests := []plantest.RuleTestCase{
+		simple("mean", meanSpec),
+		simple("count", &universe.CountProcedureSpec{AggregateConfig: valueAggregate}),
+		simple("sum", &universe.SumProcedureSpec{AggregateConfig: valueAggregate}),
+		simple("min", &universe.MinProcedureSpec{SelectorConfig: valueSelector}),
+		simple("max", &universe.MaxProcedureSpec{SelectorConfig: valueSelector}),
+		simple("first", &universe.FirstProcedureSpec{SelectorConfig: valueSelector}),
+		simple("last", &universe.LastProcedureSpec{SelectorConfig: valueSelector}),
+		noChange("sliding window", window(time.Minute, 2*time.Minute, 0), meanSpec),
+		noChange("offset window", window(time.Minute, time.Minute, time.Second), meanSpec),
+		noChange("infinite window", window(math.MaxInt64, math.MaxInt64, 0), meanSpec),
+		noChange("other time column", otherTime, meanSpec),
+		noChange("other column", window(time.Minute, time.Minute, 0), &universe.MeanProcedureSpec{
+			AggregateConfig: execute.AggregateConfig{Columns: []string{"_other"}},
+		}),
+		noChange("not an aggregate", window(time.Minute, time.Minute, 0), &universe.DistinctProcedureSpec{
+			Column: execute.DefaultValueColLabel,
+		}),
+		{
+			Name: "with multiple successors",
+			//
+			// mean    count       mean    count
+			//     \    /       =>      \    /
+			//     window              window
+			//        |                   |
+			//    ReadRange           ReadRange
+			//
+			Rules: []plan.Rule{influxdb.PushDownWindowAggregateRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window(time.Minute, time.Minute, 0)),
+					plan.CreatePhysicalNode("mean", meanSpec),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{AggregateConfig: valueAggregate}),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {1, 3}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window(time.Minute, time.Minute, 0)),
+					plan.CreatePhysicalNode("mean", meanSpec),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{AggregateConfig: valueAggregate}),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {1, 3}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
 func TestReadTagKeysRule(t *testing.T) {
 	fromSpec := influxdb.FromProcedureSpec{
 		Bucket: "my-bucket",
@@ -0,0 +1,254 @@
+// Package smtp provides the Flux functions used by the notification rules
+// that send emails through an SMTP server.
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// PackagePath is the import path of the smtp Flux package.
+const PackagePath = "influxdata/influxdb/smtp"
+
+// sendTimeout is the maximum duration of the exchange with the SMTP server to send an email.
+const sendTimeout = 30 * time.Second
+
+const packageSource = `package smtp
+
+// sendMail sends an email with subject and body from the address from to the addresses to,
+// through the SMTP server at host and port. It returns true once the server accepted the email.
+builtin sendMail
+
+// endpoint creates the endpoint sending the emails of a notification rule through the SMTP server at host and port.
+// The returned factory function accepts a mapFn parameter, that must return a record with the
+// to, subject and body arguments of sendMail.
+endpoint = (host, port=25, username="", password="", from) =>
+    (mapFn) =>
+        (tables=<-) => tables
+            |> map(fn: (r) => {
+                obj = mapFn(r: r)
+                return {r with _sent: string(v: sendMail(
+                    host: host,
+                    port: port,
+                    username: username,
+                    password: password,
+                    from: from,
+                    to: obj.to,
+                    subject: obj.subject,
+                    body: obj.body,
+                ))}
+            })
+`
+
+func init() {
+	pkg := parser.ParseSource(packageSource)
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+
+	flux.RegisterPackageValue(PackagePath, "sendMail", values.NewFunction(
+		"sendMail",
+		semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+			Parameters: map[string]semantic.PolyType{
+				"host":     semantic.String,
+				"port":     semantic.Int,
+				"username": semantic.String,
+				"password": semantic.String,
+				"from":     semantic.String,
+				"to":       semantic.NewArrayPolyType(semantic.String),
+				"subject":  semantic.String,
+				"body":     semantic.String,
+			},
+			Required: semantic.LabelSet{"host", "port", "from", "to", "subject", "body"},
+			Return:   semantic.Bool,
+		}),
+		sendMail,
+		true, // sendMail has side-effects
+	))
+}
+
+// Message is an email sent through an SMTP server.
+type Message struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	Subject  string
+	Body     string
+}
+
+func sendMail(ctx context.Context, args values.Object) (values.Value, error) {
+	a := interpreter.NewArguments(args)
+
+	var (
+		m   Message
+		err error
+	)
+	if m.Host, err = a.GetRequiredString("host"); err != nil {
+		return nil, err
+	}
+	port, err := a.GetRequiredInt("port")
+	if err != nil {
+		return nil, err
+	}
+	m.Port = int(port)
+	if m.Username, _, err = a.GetString("username"); err != nil {
+		return nil, err
+	}
+	if m.Password, _, err = a.GetString("password"); err != nil {
+		return nil, err
+	}
+	if m.From, err = a.GetRequiredString("from"); err != nil {
+		return nil, err
+	}
+	to, err := a.GetRequiredArray("to", semantic.String)
+	if err != nil {
+		return nil, err
+	}
+	to.Range(func(i int, v values.Value) {
+		m.To = append(m.To, v.Str())
+	})
+	if m.Subject, err = a.GetRequiredString("subject"); err != nil {
+		return nil, err
+	}
+	if m.Body, err = a.GetRequiredString("body"); err != nil {
+		return nil, err
+	}
+
+	// the SMTP server is subject to the restrictions of the URLs that Flux connects to.
+	validator, err := flux.GetDependencies(ctx).URLValidator()
+	if err != nil {
+		return nil, err
+	}
+	if err := validator.Validate(&url.URL{Scheme: "smtp", Host: m.addr()}); err != nil {
+		return nil, err
+	}
+
+	if err := Send(ctx, m); err != nil {
+		return nil, &flux.Error{Code: codes.Unavailable, Msg: "failed to send email", Err: err}
+	}
+	return values.NewBool(true), nil
+}
+
+func (m Message) addr() string {
+	return net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+}
+
+// Send sends the email m. The exchange with the SMTP server is upgraded to TLS if
+// the server supports it, and the server is authenticated with the username and
+// password of m, if any.
+func Send(ctx context.Context, m Message) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address %q: %v", m.From, err)
+	}
+	if len(m.To) == 0 {
+		return fmt.Errorf("no recipient")
+	}
+	to := make([]*mail.Address, 0, len(m.To))
+	for _, addr := range m.To {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("invalid to address %q: %v", addr, err)
+		}
+		to = append(to, a)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.addr())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	c, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.Host}); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("server does not support authentication")
+		}
+		if err := c.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, a := range to {
+		if err := c.Rcpt(a.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.message(from, to)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message returns the headers and body of the email.
+func (m Message) message(from *mail.Address, to []*mail.Address) []byte {
+	addrs := make([]string, 0, len(to))
+	for _, a := range to {
+		addrs = append(addrs, a.String())
+	}
+
+	// line breaks would add headers to the email.
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(m.Subject)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(addrs, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.NewReplacer("\r\n", "\r\n", "\n", "\r\n").Replace(m.Body))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
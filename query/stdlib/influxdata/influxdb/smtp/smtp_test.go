@@ -0,0 +1,144 @@
+package smtp_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/smtp"
+)
+
+// server is a stand-in SMTP server recording the commands and the data of the emails it receives.
+type server struct {
+	ln       net.Listener
+	commands chan []string
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{ln: ln, commands: make(chan []string, 1)}
+	go s.serve()
+	return s
+}
+
+func (s *server) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		var commands []string
+		r := bufio.NewReader(conn)
+		reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+		reply("220 localhost ready")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			commands = append(commands, line)
+
+			switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "DATA":
+				reply("354 go ahead")
+				var data []string
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data = append(data, strings.TrimRight(l, "\r\n"))
+				}
+				commands = append(commands, strings.Join(data, "\n"))
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+			default:
+				reply("250 ok")
+			}
+			if strings.HasPrefix(strings.ToUpper(line), "QUIT") {
+				break
+			}
+		}
+		conn.Close()
+		s.commands <- commands
+	}
+}
+
+func TestSendMail(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+
+	script := fmt.Sprintf(`import "influxdata/influxdb/smtp"
+
+smtp.sendMail(host: "127.0.0.1", port: %d, from: "InfluxDB <alerts@example.com>", to: ["oncall@example.com", "ops@example.com"], subject: "cpu is crit
+Bcc: everyone@example.com", body: "cpu usage is 99%%")`, s.port())
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	if _, _, err := flux.Eval(ctx, script); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := <-s.commands
+	for _, want := range []string{
+		"MAIL FROM:<alerts@example.com>",
+		"RCPT TO:<oncall@example.com>",
+		"RCPT TO:<ops@example.com>",
+	} {
+		if !contains(commands, want) {
+			t.Errorf("expected command %q, got %q", want, commands)
+		}
+	}
+
+	data := commands[len(commands)-2]
+	for _, want := range []string{
+		"To: <oncall@example.com>, <ops@example.com>",
+		"Subject: cpu is crit Bcc: everyone@example.com",
+		"\n\ncpu usage is 99%",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("expected %q in the email, got %q", want, data)
+		}
+	}
+}
+
+func TestSend_InvalidAddress(t *testing.T) {
+	err := smtp.Send(context.Background(), smtp.Message{
+		Host:    "127.0.0.1",
+		Port:    25,
+		From:    "alerts@example.com",
+		To:      []string{"not an address"},
+		Subject: "cpu",
+		Body:    "cpu",
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid to address") {
+		t.Fatalf("expected an invalid address error, got %v", err)
+	}
+}
+
+func contains(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
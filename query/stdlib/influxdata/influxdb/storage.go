@@ -119,6 +119,18 @@ type ReadGroupSpec struct {
 	AggregateMethod string
 }
 
+// ReadWindowAggregateSpec reads the points aggregated by AggregateMethod
+// in windows of WindowEvery nanoseconds. Each window of each series is a table.
+type ReadWindowAggregateSpec struct {
+	ReadFilterSpec
+
+	WindowEvery int64
+	// CreateEmpty requests tables for the windows without points.
+	CreateEmpty bool
+
+	AggregateMethod string
+}
+
 type ReadTagKeysSpec struct {
 	ReadFilterSpec
 }
@@ -131,6 +143,7 @@ type ReadTagValuesSpec struct {
 type Reader interface {
 	ReadFilter(ctx context.Context, spec ReadFilterSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadGroup(ctx context.Context, spec ReadGroupSpec, alloc *memory.Allocator) (TableIterator, error)
+	ReadWindowAggregate(ctx context.Context, spec ReadWindowAggregateSpec, alloc *memory.Allocator) (TableIterator, error)
 
 	ReadTagKeys(ctx context.Context, spec ReadTagKeysSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadTagValues(ctx context.Context, spec ReadTagValuesSpec, alloc *memory.Allocator) (TableIterator, error)
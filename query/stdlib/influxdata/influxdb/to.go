@@ -18,6 +18,7 @@ import (
 	"github.com/influxdata/flux/stdlib/kafka"
 	"github.com/influxdata/flux/values"
 	platform "github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/query"
@@ -278,6 +279,9 @@ type ToTransformation struct {
 	implicitTagColumns bool
 	deps               ToDependencies
 	buf                *storage.BufferedPointsWriter
+	// series matches the series the authorizer of the context may write to
+	// the bucket, all the series if nil.
+	series *platform.SeriesMatcher
 }
 
 // RetractTable retracts the table for the transformation for the `to` flux function.
@@ -342,6 +346,10 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 			Msg:  "You must specify org and bucket",
 		}
 	}
+	series, err := WriteSeriesMatcher(ctx, *orgID, *bucketID)
+	if err != nil {
+		return nil, err
+	}
 	return &ToTransformation{
 		Ctx:                ctx,
 		OrgID:              *orgID,
@@ -353,9 +361,30 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 		implicitTagColumns: spec.TagColumns == nil,
 		deps:               deps,
 		buf:                storage.NewBufferedPointsWriter(DefaultBufferSize, deps.PointsWriter),
+		series:             series,
 	}, nil
 }
 
+// WriteSeriesMatcher returns the matcher of the series of the bucket the
+// authorizer of the context may write, or nil if it may write all of them.
+func WriteSeriesMatcher(ctx context.Context, orgID, bucketID platform.ID) (*platform.SeriesMatcher, error) {
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		// the writes without an authorizer are not made on behalf of a user.
+		return nil, nil
+	}
+
+	p, err := platform.NewPermissionAtID(bucketID, platform.WriteAction, platform.BucketsResourceType, orgID)
+	if err != nil {
+		return nil, err
+	}
+	scopes := platform.SeriesScopes(a, *p)
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	return platform.NewSeriesMatcher(scopes)
+}
+
 // Process does the actual work for the ToTransformation.
 func (t *ToTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
 	if t.implicitTagColumns {
@@ -601,6 +630,13 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 				}
 			}
 
+			if t.series != nil && !t.series.Match(measurementName, tagValue(kv[2:])) {
+				return &flux.Error{
+					Code: codes.PermissionDenied,
+					Msg:  fmt.Sprintf("series of measurement %q is outside of the series scope of the authorization", measurementName),
+				}
+			}
+
 			if spec.FieldFn.Fn == nil {
 				if fieldValues, err = defaultFieldMapping(er, i); err != nil {
 					return err
@@ -674,6 +710,19 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 	})
 }
 
+// tagValue returns the lookup of the values of the tags in the list of
+// alternating keys and values kv.
+func tagValue(kv [][]byte) func(key string) string {
+	return func(key string) string {
+		for i := 0; i+1 < len(kv); i += 2 {
+			if string(kv[i]) == key {
+				return string(kv[i+1])
+			}
+		}
+		return ""
+	}
+}
+
 func defaultFieldMapping(er flux.ColReader, row int) (values.Object, error) {
 	fieldColumnIdx := execute.ColIdx(defaultFieldColLabel, er.Cols())
 	valueColumnIdx := execute.ColIdx(execute.DefaultValueColLabel, er.Cols())
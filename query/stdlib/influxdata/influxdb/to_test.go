@@ -16,6 +16,7 @@ import (
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values/valuestest"
 	platform "github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/models"
 	_ "github.com/influxdata/influxdb/query/builtin"
@@ -827,6 +828,109 @@ m,tag1=c,tag2=ee _value=4 41`),
 	}
 }
 
+func TestTo_ProcessSeriesScope(t *testing.T) {
+	oid, _ := mock.OrganizationLookup{}.Lookup(context.Background(), "my-org")
+	bid, _ := mock.BucketLookup{}.Lookup(context.Background(), oid, "my-bucket")
+	auth := &platform.Authorization{
+		Status: platform.Active,
+		Permissions: []platform.Permission{{
+			Action:   platform.WriteAction,
+			Resource: platform.Resource{Type: platform.BucketsResourceType, ID: &bid, OrgID: &oid},
+			Series: &platform.SeriesScope{
+				Measurements: []string{"a"},
+				Tags:         []platform.TagRule{{Tag: platform.Tag{Key: "host", Value: "web1"}, Operator: platform.Equal}},
+			},
+		}},
+	}
+	spec := &influxdb.ToProcedureSpec{
+		Spec: &influxdb.ToOpSpec{
+			Org:               "my-org",
+			Bucket:            "my-bucket",
+			TimeColumn:        "_time",
+			MeasurementColumn: "_measurement",
+			TagColumns:        []string{"host"},
+		},
+	}
+	table := func(measurement, host string) *executetest.Table {
+		return &executetest.Table{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "host", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(11), measurement, "_value", 2.0, host},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		measurement string
+		host        string
+		points      string
+		wantErr     error
+	}{
+		{
+			name:        "in scope",
+			measurement: "a",
+			host:        "web1",
+			points:      "a,host=web1 _value=2 11",
+		},
+		{
+			name:        "measurement outside of scope",
+			measurement: "b",
+			host:        "web1",
+			wantErr:     fmt.Errorf(`series of measurement "b" is outside of the series scope of the authorization`),
+		},
+		{
+			name:        "tag outside of scope",
+			measurement: "a",
+			host:        "db1",
+			wantErr:     fmt.Errorf(`series of measurement "a" is outside of the series scope of the authorization`),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			deps := influxdb.Dependencies{
+				FluxDeps: dependenciestest.Default(),
+				StorageDeps: influxdb.StorageDependencies{
+					ToDeps: mockDependencies(),
+				},
+			}
+			var want []*executetest.Table
+			if tc.wantErr == nil {
+				want = []*executetest.Table{table(tc.measurement, tc.host)}
+			}
+			executetest.ProcessTestHelper(
+				t,
+				[]flux.Table{executetest.MustCopyTable(table(tc.measurement, tc.host))},
+				want,
+				tc.wantErr,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					ctx := icontext.SetAuthorizer(deps.Inject(context.Background()), auth)
+					newT, err := influxdb.NewToTransformation(ctx, d, c, spec, deps.StorageDeps.ToDeps)
+					if err != nil {
+						t.Error(err)
+					}
+					return newT
+				},
+			)
+
+			pw := deps.StorageDeps.ToDeps.PointsWriter.(*mock.PointsWriter)
+			gotStr := pointsToStr(pw.Points)
+			wantStr := pointsToStr(mockPoints(oid, bid, tc.points))
+			if !cmp.Equal(gotStr, wantStr) {
+				t.Errorf("got other than expected %s", cmp.Diff(gotStr, wantStr))
+			}
+		})
+	}
+}
+
 func mockDependencies() influxdb.ToDependencies {
 	return influxdb.ToDependencies{
 		BucketLookup:       mock.BucketLookup{},
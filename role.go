@@ -0,0 +1,202 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ErrRoleNotFound is the error msg for a missing role.
+const ErrRoleNotFound = "role not found"
+
+// ops for role errors.
+const (
+	OpFindRoleByID         = "FindRoleByID"
+	OpFindRoles            = "FindRoles"
+	OpCreateRole           = "CreateRole"
+	OpUpdateRole           = "UpdateRole"
+	OpDeleteRole           = "DeleteRole"
+	OpFindRoleAssignments  = "FindRoleAssignments"
+	OpCreateRoleAssignment = "CreateRoleAssignment"
+	OpDeleteRoleAssignment = "DeleteRoleAssignment"
+)
+
+// Role is a named set of permissions on the resources of an organization,
+// e.g. "dashboard-editor". The users a role is assigned to, who must be
+// members of its organization, are granted its permissions in their sessions
+// and in their authorizations on its organization.
+type Role struct {
+	ID          ID           `json:"id,omitempty"`
+	OrgID       ID           `json:"orgID"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+	CRUDLog
+}
+
+// Valid returns an error if the role is invalid, or if any of its
+// permissions is not on the resources of its organization.
+func (r *Role) Valid() error {
+	if !r.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role orgID is invalid",
+		}
+	}
+	if r.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role name is empty",
+		}
+	}
+	if len(r.Permissions) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role must have at least one permission",
+		}
+	}
+	for _, p := range r.Permissions {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+		if !r.inOrg(p) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("role permission %s is not on the resources of organization %s", p, r.OrgID),
+			}
+		}
+	}
+	return nil
+}
+
+// inOrg returns whether the permission is on the organization of the role or
+// on resources of it.
+func (r *Role) inOrg(p Permission) bool {
+	if p.Resource.Type == OrgsResourceType {
+		return p.Resource.ID != nil && *p.Resource.ID == r.OrgID
+	}
+	return p.Resource.OrgID != nil && *p.Resource.OrgID == r.OrgID
+}
+
+// RoleFilter represents a set of filters that restrict the returned roles.
+type RoleFilter struct {
+	ID    *ID
+	OrgID *ID
+	Name  *string
+	// UserID restricts the roles to the ones assigned to the user.
+	UserID *ID
+}
+
+// QueryParams converts RoleFilter fields to url query params.
+func (f RoleFilter) QueryParams() map[string][]string {
+	qp := url.Values{}
+	if f.ID != nil {
+		qp.Add("id", f.ID.String())
+	}
+
+	if f.OrgID != nil {
+		qp.Add("orgID", f.OrgID.String())
+	}
+
+	if f.Name != nil {
+		qp.Add("name", *f.Name)
+	}
+
+	if f.UserID != nil {
+		qp.Add("userID", f.UserID.String())
+	}
+
+	return qp
+}
+
+// RoleUpdate is the set of fields of a role that can be updated. The
+// permissions replace the ones of the role if not nil.
+type RoleUpdate struct {
+	Name        *string      `json:"name,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty"`
+}
+
+// Valid returns an error if the update is empty.
+func (u RoleUpdate) Valid() error {
+	if u.Name == nil && u.Description == nil && u.Permissions == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role update must update at least one field",
+		}
+	}
+	return nil
+}
+
+// Apply applies the update to the role.
+func (u RoleUpdate) Apply(r *Role) {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Permissions != nil {
+		r.Permissions = u.Permissions
+	}
+}
+
+// RoleAssignment assigns a role to a user.
+type RoleAssignment struct {
+	RoleID ID `json:"roleID"`
+	UserID ID `json:"userID"`
+}
+
+// Valid returns an error if the assignment is missing its role or its user.
+func (a *RoleAssignment) Valid() error {
+	if !a.RoleID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role assignment roleID is invalid",
+		}
+	}
+	if !a.UserID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role assignment userID is invalid",
+		}
+	}
+	return nil
+}
+
+// RoleAssignmentFilter represents a set of filters that restrict the
+// returned role assignments.
+type RoleAssignmentFilter struct {
+	RoleID *ID
+	UserID *ID
+}
+
+// RoleService manages the roles of the organizations and their assignments
+// to users.
+type RoleService interface {
+	// FindRoleByID returns a single role by ID.
+	FindRoleByID(ctx context.Context, id ID) (*Role, error)
+
+	// FindRoles returns a list of roles that match filter and the total count of matching roles.
+	// Additional options provide pagination & sorting.
+	FindRoles(ctx context.Context, filter RoleFilter, opt ...FindOptions) ([]*Role, int, error)
+
+	// CreateRole creates a new role and sets r.ID with the new identifier.
+	CreateRole(ctx context.Context, r *Role) error
+
+	// UpdateRole updates a single role with changeset.
+	// Returns the new role state after update.
+	UpdateRole(ctx context.Context, id ID, upd RoleUpdate) (*Role, error)
+
+	// DeleteRole removes a role by ID, along with its assignments.
+	DeleteRole(ctx context.Context, id ID) error
+
+	// FindRoleAssignments returns a list of role assignments that match filter and the total count of matching assignments.
+	FindRoleAssignments(ctx context.Context, filter RoleAssignmentFilter) ([]*RoleAssignment, int, error)
+
+	// CreateRoleAssignment assigns a role to a user.
+	CreateRoleAssignment(ctx context.Context, a *RoleAssignment) error
+
+	// DeleteRoleAssignment unassigns a role from a user.
+	DeleteRoleAssignment(ctx context.Context, roleID, userID ID) error
+}
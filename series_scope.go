@@ -0,0 +1,241 @@
+package influxdb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SeriesScope restricts a permission on buckets to the series of its
+// measurements whose tags match all its tag rules. The series of every
+// measurement are in scope if Measurements is empty.
+type SeriesScope struct {
+	Measurements []string  `json:"measurements,omitempty"`
+	Tags         []TagRule `json:"tags,omitempty"`
+}
+
+// Valid returns an error if the series scope restricts nothing or has an
+// invalid tag rule.
+func (s *SeriesScope) Valid() error {
+	if len(s.Measurements) == 0 && len(s.Tags) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "series scope must restrict the measurements or the tags",
+		}
+	}
+	for _, m := range s.Measurements {
+		if m == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "series scope measurement must not be empty",
+			}
+		}
+	}
+	for _, tr := range s.Tags {
+		if err := tr.Operator.Valid(); err != nil {
+			return err
+		}
+		if tr.Key == "" || strings.HasPrefix(tr.Key, "_") {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("series scope tag key %q is invalid", tr.Key),
+			}
+		}
+		if tr.Operator == RegexEqual || tr.Operator == NotRegexEqual {
+			if _, err := regexp.Compile(tr.Value); err != nil {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("series scope tag %q has an invalid regular expression", tr.Key),
+					Err:  err,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// covers returns true if all the series in the scope o are in the scope s.
+func (s *SeriesScope) covers(o *SeriesScope) bool {
+	if len(s.Measurements) > 0 {
+		if len(o.Measurements) == 0 {
+			return false
+		}
+		for _, m := range o.Measurements {
+			if !containsString(s.Measurements, m) {
+				return false
+			}
+		}
+	}
+	for _, tr := range s.Tags {
+		found := false
+		for _, otr := range o.Tags {
+			if tr == otr {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SeriesScope) String() string {
+	var parts []string
+	if len(s.Measurements) > 0 {
+		parts = append(parts, "measurements="+strings.Join(s.Measurements, ","))
+	}
+	for _, tr := range s.Tags {
+		parts = append(parts, TagRuleString(tr))
+	}
+	return strings.Join(parts, " ")
+}
+
+// TagRuleString returns the tag rule in the form key=value, key!=value,
+// key=~/regex/ or key!~/regex/.
+func TagRuleString(tr TagRule) string {
+	switch tr.Operator {
+	case NotEqual:
+		return tr.Key + "!=" + tr.Value
+	case RegexEqual:
+		return tr.Key + "=~/" + tr.Value + "/"
+	case NotRegexEqual:
+		return tr.Key + "!~/" + tr.Value + "/"
+	default:
+		return tr.Key + "=" + tr.Value
+	}
+}
+
+// ParseTagRule parses a tag rule of the form key=value, key!=value,
+// key=~/regex/ or key!~/regex/.
+func ParseTagRule(s string) (TagRule, error) {
+	for _, op := range []struct {
+		sep   string
+		op    Operator
+		regex bool
+	}{
+		{sep: "!~", op: NotRegexEqual, regex: true},
+		{sep: "=~", op: RegexEqual, regex: true},
+		{sep: "!=", op: NotEqual},
+		{sep: "=", op: Equal},
+	} {
+		i := strings.Index(s, op.sep)
+		if i < 0 {
+			continue
+		}
+		tr := TagRule{
+			Tag:      Tag{Key: s[:i], Value: s[i+len(op.sep):]},
+			Operator: op.op,
+		}
+		if op.regex {
+			if len(tr.Value) < 2 || !strings.HasPrefix(tr.Value, "/") || !strings.HasSuffix(tr.Value, "/") {
+				break
+			}
+			tr.Value = tr.Value[1 : len(tr.Value)-1]
+		}
+		if tr.Key == "" {
+			break
+		}
+		return tr, nil
+	}
+	return TagRule{}, &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("tag rule %q must be of the form key=value, key!=value, key=~/regex/ or key!~/regex/", s),
+	}
+}
+
+// SeriesScopes returns the scopes restricting the permission p of the
+// authorizer a to some series, or nil if p is allowed on all the series.
+// It is meaningful only if a is allowed p.
+func SeriesScopes(a Authorizer, p Permission) []SeriesScope {
+	ps, ok := a.(interface {
+		PermissionSet() []Permission
+	})
+	if !ok {
+		return nil
+	}
+
+	p.Series = nil
+	var scopes []SeriesScope
+	for _, q := range ps.PermissionSet() {
+		if !q.Matches(p) {
+			continue
+		}
+		if q.Series == nil {
+			return nil
+		}
+		scopes = append(scopes, *q.Series)
+	}
+	return scopes
+}
+
+// SeriesMatcher matches the series in any of a list of series scopes.
+type SeriesMatcher struct {
+	scopes []seriesScopeMatcher
+}
+
+type seriesScopeMatcher struct {
+	measurements []string
+	tags         []tagRuleMatcher
+}
+
+type tagRuleMatcher struct {
+	key   string
+	value string
+	re    *regexp.Regexp
+	not   bool
+}
+
+// NewSeriesMatcher returns a matcher of the series in any of the scopes.
+func NewSeriesMatcher(scopes []SeriesScope) (*SeriesMatcher, error) {
+	m := &SeriesMatcher{}
+	for _, s := range scopes {
+		if err := s.Valid(); err != nil {
+			return nil, err
+		}
+		sm := seriesScopeMatcher{measurements: s.Measurements}
+		for _, tr := range s.Tags {
+			tm := tagRuleMatcher{
+				key:   tr.Key,
+				value: tr.Value,
+				not:   tr.Operator == NotEqual || tr.Operator == NotRegexEqual,
+			}
+			if tr.Operator == RegexEqual || tr.Operator == NotRegexEqual {
+				tm.re = regexp.MustCompile(tr.Value)
+			}
+			sm.tags = append(sm.tags, tm)
+		}
+		m.scopes = append(m.scopes, sm)
+	}
+	return m, nil
+}
+
+// Match returns true if the series of the measurement is in any scope of the
+// matcher. The tag function returns the value of the tag key of the series,
+// empty if the series has no such tag.
+func (m *SeriesMatcher) Match(measurement string, tag func(key string) string) bool {
+	for _, s := range m.scopes {
+		if s.match(measurement, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s seriesScopeMatcher) match(measurement string, tag func(key string) string) bool {
+	if len(s.measurements) > 0 && !containsString(s.measurements, measurement) {
+		return false
+	}
+	for _, tm := range s.tags {
+		v := tag(tm.key)
+		matched := v == tm.value
+		if tm.re != nil {
+			matched = tm.re.MatchString(v)
+		}
+		if matched == tm.not {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package influxdb_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	influxTesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestParseTagRule(t *testing.T) {
+	cases := []struct {
+		src     string
+		want    influxdb.TagRule
+		wantErr bool
+	}{
+		{
+			src:  "host=web1",
+			want: influxdb.TagRule{Tag: influxdb.Tag{Key: "host", Value: "web1"}, Operator: influxdb.Equal},
+		},
+		{
+			src:  "host!=web1",
+			want: influxdb.TagRule{Tag: influxdb.Tag{Key: "host", Value: "web1"}, Operator: influxdb.NotEqual},
+		},
+		{
+			src:  "host=~/^web/",
+			want: influxdb.TagRule{Tag: influxdb.Tag{Key: "host", Value: "^web"}, Operator: influxdb.RegexEqual},
+		},
+		{
+			src:  "host!~/^web/",
+			want: influxdb.TagRule{Tag: influxdb.Tag{Key: "host", Value: "^web"}, Operator: influxdb.NotRegexEqual},
+		},
+		{
+			src:  "url=http://a?b=c",
+			want: influxdb.TagRule{Tag: influxdb.Tag{Key: "url", Value: "http://a?b=c"}, Operator: influxdb.Equal},
+		},
+		{
+			src:     "host=~^web",
+			wantErr: true,
+		},
+		{
+			src:     "=web1",
+			wantErr: true,
+		},
+		{
+			src:     "host",
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.src, func(t *testing.T) {
+			got, err := influxdb.ParseTagRule(c.src)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("ParseTagRule() error = %v, wantErr %v", err, c.wantErr)
+			}
+			if got != c.want {
+				t.Errorf("ParseTagRule() = %v, want %v", got, c.want)
+			}
+			if err == nil {
+				if s := influxdb.TagRuleString(got); s != c.src {
+					t.Errorf("TagRuleString() = %s, want %s", s, c.src)
+				}
+			}
+		})
+	}
+}
+
+func TestSeriesMatcher_Match(t *testing.T) {
+	m, err := influxdb.NewSeriesMatcher([]influxdb.SeriesScope{
+		{
+			Measurements: []string{"cpu", "mem"},
+			Tags: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "^web"}, Operator: influxdb.RegexEqual},
+				{Tag: influxdb.Tag{Key: "env", Value: "dev"}, Operator: influxdb.NotEqual},
+			},
+		},
+		{
+			Measurements: []string{"disk"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name        string
+		measurement string
+		tags        map[string]string
+		want        bool
+	}{
+		{
+			name:        "matching measurement and tags",
+			measurement: "cpu",
+			tags:        map[string]string{"host": "web1", "env": "prod"},
+			want:        true,
+		},
+		{
+			name:        "missing tag is empty",
+			measurement: "mem",
+			tags:        map[string]string{"host": "web1"},
+			want:        true,
+		},
+		{
+			name:        "not matching regular expression",
+			measurement: "cpu",
+			tags:        map[string]string{"host": "db1", "env": "prod"},
+			want:        false,
+		},
+		{
+			name:        "excluded tag value",
+			measurement: "cpu",
+			tags:        map[string]string{"host": "web1", "env": "dev"},
+			want:        false,
+		},
+		{
+			name:        "measurement of another scope",
+			measurement: "disk",
+			tags:        map[string]string{"host": "db1"},
+			want:        true,
+		},
+		{
+			name:        "measurement out of scope",
+			measurement: "net",
+			tags:        map[string]string{"host": "web1"},
+			want:        false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := m.Match(c.measurement, func(key string) string {
+				return c.tags[key]
+			})
+			if got != c.want {
+				t.Errorf("Match() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSeriesScopes(t *testing.T) {
+	bucket := influxdb.Permission{
+		Action: influxdb.WriteAction,
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			OrgID: influxTesting.IDPtr(1),
+			ID:    influxTesting.IDPtr(2),
+		},
+	}
+	scoped := func(p influxdb.Permission, measurements ...string) influxdb.Permission {
+		p.Series = &influxdb.SeriesScope{Measurements: measurements}
+		return p
+	}
+	otherBucket := bucket
+	otherBucket.Resource.ID = influxTesting.IDPtr(3)
+
+	cases := []struct {
+		name        string
+		permissions []influxdb.Permission
+		want        []influxdb.SeriesScope
+	}{
+		{
+			name:        "unscoped permission",
+			permissions: []influxdb.Permission{bucket},
+		},
+		{
+			name:        "scoped permission",
+			permissions: []influxdb.Permission{scoped(bucket, "cpu"), scoped(otherBucket, "mem")},
+			want:        []influxdb.SeriesScope{{Measurements: []string{"cpu"}}},
+		},
+		{
+			name:        "scoped and unscoped permissions",
+			permissions: []influxdb.Permission{scoped(bucket, "cpu"), bucket},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := &influxdb.Authorization{Status: influxdb.Active, Permissions: c.permissions}
+			if got := influxdb.SeriesScopes(a, bucket); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("SeriesScopes() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -64,6 +64,11 @@ func (s *Session) Allowed(p Permission) bool {
 	return PermissionAllowed(p, s.Permissions)
 }
 
+// PermissionSet returns the permissions of the session.
+func (s *Session) PermissionSet() []Permission {
+	return s.Permissions
+}
+
 // Kind returns session and is used for auditing.
 func (s *Session) Kind() string { return SessionAuthorizionKind }
 
@@ -0,0 +1,176 @@
+package influxdb
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// ErrSilenceNotFound is the error msg for a missing silence.
+const ErrSilenceNotFound = "silence not found"
+
+// ops for silence errors.
+const (
+	OpFindSilenceByID = "FindSilenceByID"
+	OpFindSilences    = "FindSilences"
+	OpCreateSilence   = "CreateSilence"
+	OpUpdateSilence   = "UpdateSilence"
+	OpDeleteSilence   = "DeleteSilence"
+)
+
+// Silence mutes the notifications of the statuses it matches between its
+// start and end times. The statuses are matched by the check that wrote them,
+// the notification rule they are sent by and their tags; a silence matches
+// the statuses that satisfy every matcher it is configured with.
+// The notification rules still log the silenced statuses, as not sent.
+type Silence struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// CheckID matches the statuses of a check, any check if not set.
+	CheckID ID `json:"checkID,omitempty"`
+	// RuleID matches the statuses notified by a notification rule, any rule if not set.
+	RuleID ID `json:"ruleID,omitempty"`
+	// TagRules match the statuses by their tags.
+	TagRules  []TagRule `json:"tagRules,omitempty"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	CRUDLog
+}
+
+// Valid returns an error if the silence is invalid.
+func (s *Silence) Valid() error {
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence orgID is invalid",
+		}
+	}
+	if s.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence name is empty",
+		}
+	}
+	if !s.CheckID.Valid() && !s.RuleID.Valid() && len(s.TagRules) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence must match a check, a notification rule or tags",
+		}
+	}
+	for _, tr := range s.TagRules {
+		if err := tr.Valid(); err != nil {
+			return err
+		}
+	}
+	if s.StartTime.IsZero() || s.EndTime.IsZero() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence start and end times are required",
+		}
+	}
+	if !s.EndTime.After(s.StartTime) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence end time must be after its start time",
+		}
+	}
+	return nil
+}
+
+// Expired returns whether the silence has ended at time t.
+func (s *Silence) Expired(t time.Time) bool {
+	return !s.EndTime.After(t)
+}
+
+// AppliesTo returns whether the statuses notified by the rule can be muted by the silence.
+func (s *Silence) AppliesTo(ruleID ID) bool {
+	return !s.RuleID.Valid() || s.RuleID == ruleID
+}
+
+// SilenceFilter represents a set of filters that restrict the returned silences.
+type SilenceFilter struct {
+	ID      *ID
+	OrgID   *ID
+	CheckID *ID
+	RuleID  *ID
+}
+
+// QueryParams converts SilenceFilter fields to url query params.
+func (f SilenceFilter) QueryParams() map[string][]string {
+	qp := url.Values{}
+	if f.ID != nil {
+		qp.Add("id", f.ID.String())
+	}
+
+	if f.OrgID != nil {
+		qp.Add("orgID", f.OrgID.String())
+	}
+
+	if f.CheckID != nil {
+		qp.Add("checkID", f.CheckID.String())
+	}
+
+	if f.RuleID != nil {
+		qp.Add("ruleID", f.RuleID.String())
+	}
+
+	return qp
+}
+
+// SilenceUpdate is the set of fields of a silence that can be updated.
+type SilenceUpdate struct {
+	Name        *string    `json:"name,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	StartTime   *time.Time `json:"startTime,omitempty"`
+	EndTime     *time.Time `json:"endTime,omitempty"`
+}
+
+// Valid returns an error if the update is empty.
+func (u SilenceUpdate) Valid() error {
+	if u.Name == nil && u.Description == nil && u.StartTime == nil && u.EndTime == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence update must update at least one field",
+		}
+	}
+	return nil
+}
+
+// Apply applies the update to the silence.
+func (u SilenceUpdate) Apply(s *Silence) {
+	if u.Name != nil {
+		s.Name = *u.Name
+	}
+	if u.Description != nil {
+		s.Description = *u.Description
+	}
+	if u.StartTime != nil {
+		s.StartTime = *u.StartTime
+	}
+	if u.EndTime != nil {
+		s.EndTime = *u.EndTime
+	}
+}
+
+// SilenceService manages the silences of the notification rules.
+type SilenceService interface {
+	// FindSilenceByID returns a single silence by ID.
+	FindSilenceByID(ctx context.Context, id ID) (*Silence, error)
+
+	// FindSilences returns a list of silences that match filter and the total count of matching silences.
+	// Additional options provide pagination & sorting.
+	FindSilences(ctx context.Context, filter SilenceFilter, opt ...FindOptions) ([]*Silence, int, error)
+
+	// CreateSilence creates a new silence and sets s.ID with the new identifier.
+	// The notification rules it applies to are updated to mute the statuses it matches.
+	CreateSilence(ctx context.Context, s *Silence) error
+
+	// UpdateSilence updates a single silence with changeset.
+	// Returns the new silence state after update.
+	UpdateSilence(ctx context.Context, id ID, upd SilenceUpdate) (*Silence, error)
+
+	// DeleteSilence removes a silence by ID.
+	DeleteSilence(ctx context.Context, id ID) error
+}
@@ -17,6 +17,7 @@ const (
 	DefaultIndexDirectoryName      = "index"
 	DefaultWALDirectoryName        = "wal"
 	DefaultEngineDirectoryName     = "data"
+	DefaultBackupDirectoryName     = "backup"
 )
 
 // Config holds the configuration for an Engine.
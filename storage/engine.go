@@ -105,7 +105,9 @@ func WithNodeID(id int) Option {
 // metrics are labelled correctly.
 func WithRetentionEnforcer(finder BucketFinder) Option {
 	return func(e *Engine) {
-		e.retentionEnforcer = newRetentionEnforcer(e, e.engine, finder)
+		r := newRetentionEnforcer(e, e.engine, finder)
+		r.ColdTier = e
+		e.retentionEnforcer = r
 	}
 }
 
@@ -119,6 +121,14 @@ func WithRetentionEnforcerLimiter(f runnable) Option {
 	}
 }
 
+// WithColdStore sets the store the retention enforcer moves the TSM files of
+// the buckets with a cold tier period to.
+func WithColdStore(store tsm1.ColdStore) Option {
+	return func(e *Engine) {
+		e.engine.WithColdStore(store)
+	}
+}
+
 // WithFileStoreObserver makes the engine have the provided file store observer.
 func WithFileStoreObserver(obs tsm1.FileStoreObserver) Option {
 	return func(e *Engine) {
@@ -621,6 +631,21 @@ func (e *Engine) DeleteBucketRange(ctx context.Context, orgID, bucketID platform
 	return e.deleteBucketRangeLocked(ctx, orgID, bucketID, min, max, nil)
 }
 
+// MoveToColdTier moves the fully compacted TSM files for which fn returns true
+// to the cold tier, and returns the number of files moved.
+func (e *Engine) MoveToColdTier(ctx context.Context, fn func(tsm1.FileStat) bool) (int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return 0, ErrEngineClosed
+	}
+
+	return e.engine.MoveToColdTier(ctx, fn)
+}
+
 // DeleteBucketRangePredicate deletes data within a bucket from the storage engine. Any data
 // deleted must be in [min, max], and the key must match the predicate if provided.
 func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred platform.Predicate) error {
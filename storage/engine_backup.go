@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.BackupService = (*Engine)(nil)
+
+// CreateBackup creates a point-in-time snapshot of the TSM data, index and
+// series file in the engine's backup directory. The snapshot remains on disk
+// until it is removed with DeleteBackup.
+//
+// An incremental backup does not flush the cache; the closed WAL segments are
+// linked into the snapshot instead.
+func (e *Engine) CreateBackup(ctx context.Context, opts influxdb.BackupOptions) (*influxdb.BackupManifest, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	closed := e.closing == nil
+	e.mu.RUnlock()
+	if closed {
+		return nil, ErrEngineClosed
+	}
+
+	id, dir, err := e.createBackupDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.createBackup(ctx, dir, opts); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	files, err := backupFiles(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	e.logger.Info("Created backup",
+		zap.Int("backup_id", id),
+		zap.Bool("incremental", opts.Incremental),
+		zap.Int("files", len(files)))
+
+	return &influxdb.BackupManifest{
+		ID:        id,
+		CreatedAt: time.Now().UTC(),
+		Files:     files,
+	}, nil
+}
+
+// createBackup fills the backup directory dir.
+func (e *Engine) createBackup(ctx context.Context, dir string, opts influxdb.BackupOptions) error {
+	var tsmPath string
+	if opts.Incremental && e.config.WAL.Enabled {
+		// Holding the write lock prevents the cache from being snapshotted
+		// concurrently, so every write is either in one of the linked TSM
+		// files or in one of the linked WAL segments.
+		if err := func() (err error) {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			if e.closing == nil {
+				return ErrEngineClosed
+			}
+
+			if err := e.snapshotWAL(filepath.Join(dir, DefaultWALDirectoryName)); err != nil {
+				return err
+			}
+			tsmPath, err = e.engine.FileStore.CreateSnapshot(ctx)
+			return err
+		}(); err != nil {
+			return err
+		}
+	} else {
+		// Snapshotting the TSM files flushes the cache, which needs to acquire
+		// the engine's write lock. It must happen before the read lock is taken.
+		var err error
+		if tsmPath, err = e.engine.CreateSnapshot(ctx); err != nil {
+			return err
+		}
+	}
+	defer os.RemoveAll(tsmPath)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	// The index is snapshotted after the TSM files, and the series file after
+	// the index, so that every series with data in the backup is present in
+	// both of them.
+	if err := os.Rename(tsmPath, filepath.Join(dir, DefaultEngineDirectoryName)); err != nil {
+		return err
+	}
+	if err := e.index.SnapshotTo(filepath.Join(dir, DefaultIndexDirectoryName)); err != nil {
+		return err
+	}
+	return e.sfile.SnapshotTo(filepath.Join(dir, DefaultSeriesFileDirectoryName))
+}
+
+// snapshotWAL closes the active WAL segment and hard links all of the closed
+// segments into dir. It must be called with the write lock held.
+func (e *Engine) snapshotWAL(dir string) error {
+	if err := e.wal.CloseSegment(); err != nil {
+		return err
+	}
+
+	segments, err := e.wal.ClosedSegments()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	for _, seg := range segments {
+		if err := os.Link(seg, filepath.Join(dir, filepath.Base(seg))); err != nil {
+			return fmt.Errorf("error creating wal segment hard link: %q", err)
+		}
+	}
+	return nil
+}
+
+// FetchBackupFile writes the contents of a file from a backup to w.
+func (e *Engine) FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	path, err := e.backupFilePath(backupID, backupFile)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  fmt.Sprintf("backup file %q not found", backupFile),
+		}
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if fi, err := f.Stat(); err != nil {
+		return err
+	} else if fi.IsDir() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("backup file %q is a directory", backupFile),
+		}
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// DeleteBackup removes a backup from the engine's backup directory.
+func (e *Engine) DeleteBackup(ctx context.Context, backupID int) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	dir := e.backupPath(backupID)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  fmt.Sprintf("backup %d not found", backupID),
+		}
+	} else if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// backupPath returns the directory holding the backup with the provided id.
+func (e *Engine) backupPath(backupID int) string {
+	return filepath.Join(e.path, DefaultBackupDirectoryName, strconv.Itoa(backupID))
+}
+
+// backupFilePath returns the location on disk of a file within a backup,
+// ensuring that it cannot refer to anything outside of the backup.
+func (e *Engine) backupFilePath(backupID int, backupFile string) (string, error) {
+	dir := e.backupPath(backupID)
+	path := filepath.Join(dir, filepath.FromSlash(backupFile))
+	if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid backup file %q", backupFile),
+		}
+	}
+	return path, nil
+}
+
+// createBackupDir creates a new directory for a backup, using the next unused
+// backup id.
+func (e *Engine) createBackupDir() (int, string, error) {
+	root := filepath.Join(e.path, DefaultBackupDirectoryName)
+	if err := os.MkdirAll(root, 0777); err != nil {
+		return 0, "", err
+	}
+
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var id int
+	for _, fi := range fis {
+		if n, err := strconv.Atoi(fi.Name()); err == nil && n > id {
+			id = n
+		}
+	}
+
+	for {
+		id++
+		dir := e.backupPath(id)
+		if err := os.Mkdir(dir, 0777); os.IsExist(err) {
+			continue // Lost a race with a concurrent backup.
+		} else if err != nil {
+			return 0, "", err
+		}
+		return id, dir, nil
+	}
+}
+
+// backupFiles lists all of the files within the backup directory dir.
+func backupFiles(dir string) ([]influxdb.BackupFile, error) {
+	var files []influxdb.BackupFile
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if fi.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		var typ influxdb.BackupFileType
+		switch strings.SplitN(rel, "/", 2)[0] {
+		case DefaultEngineDirectoryName:
+			typ = influxdb.BackupFileTypeTSM
+		case DefaultIndexDirectoryName:
+			typ = influxdb.BackupFileTypeIndex
+		case DefaultSeriesFileDirectoryName:
+			typ = influxdb.BackupFileTypeSeries
+		case DefaultWALDirectoryName:
+			typ = influxdb.BackupFileTypeWAL
+		default:
+			return fmt.Errorf("unexpected file in backup: %q", rel)
+		}
+
+		files = append(files, influxdb.BackupFile{
+			Type: typ,
+			Path: rel,
+			Size: fi.Size(),
+		})
+		return nil
+	})
+	return files, err
+}
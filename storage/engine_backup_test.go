@@ -0,0 +1,177 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestEngine_Backup(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pt := models.MustNewPoint(
+		tsdb.EncodeNameString(engine.org, engine.bucket),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	if err := engine.Engine.WritePoints(context.Background(), []models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := engine.CreateBackup(context.Background(), influxdb.BackupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, typ := range []influxdb.BackupFileType{influxdb.BackupFileTypeTSM, influxdb.BackupFileTypeIndex, influxdb.BackupFileTypeSeries} {
+		if len(manifest.FilesOfType(typ)) == 0 {
+			t.Fatalf("backup contains no files of type %q", typ)
+		}
+	}
+
+	// Points written after the backup must not be part of it.
+	pt2 := models.MustNewPoint(
+		tsdb.EncodeNameString(engine.org, engine.bucket),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server2"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	if err := engine.Engine.WritePoints(context.Background(), []models.Point{pt2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restore the backup into a new engine by fetching each of its files.
+	path, err := ioutil.TempDir("", "storage_engine_backup_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	for _, file := range manifest.Files {
+		var buf bytes.Buffer
+		if err := engine.FetchBackupFile(context.Background(), manifest.ID, file.Path, &buf); err != nil {
+			t.Fatal(err)
+		} else if got, exp := int64(buf.Len()), file.Size; got != exp {
+			t.Fatalf("got %d bytes for %q, expected %d", got, file.Path, exp)
+		}
+
+		dst := filepath.Join(path, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(dst, buf.Bytes(), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restored := storage.NewEngine(path, storage.NewConfig(), storage.WithEngineID(rand.Int()), storage.WithNodeID(rand.Int()))
+	if err := restored.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+
+	if got, exp := restored.SeriesCardinality(), int64(1); got != exp {
+		t.Fatalf("got %d series, exp %d series in restored index", got, exp)
+	}
+
+	if err := engine.DeleteBackup(context.Background(), manifest.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.DeleteBackup(context.Background(), manifest.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("got error %v, expected not found", err)
+	}
+}
+
+func TestEngine_FetchBackupFile_Invalid(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	manifest, err := engine.CreateBackup(context.Background(), influxdb.BackupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{"", "../../data", "data/../../1/data", "data/missing.tsm"} {
+		err := engine.FetchBackupFile(context.Background(), manifest.ID, file, ioutil.Discard)
+		if code := influxdb.ErrorCode(err); code != influxdb.EInvalid && code != influxdb.ENotFound {
+			t.Errorf("got error %v for %q, expected invalid or not found", err, file)
+		}
+	}
+}
+
+func TestEngine_Backup_Incremental(t *testing.T) {
+	config := storage.NewConfig()
+	config.WAL.Enabled = true
+	engine := NewEngine(config, rand.Int(), rand.Int())
+	defer engine.Close()
+	engine.MustOpen()
+
+	pt := models.MustNewPoint(
+		tsdb.EncodeNameString(engine.org, engine.bucket),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	if err := engine.Engine.WritePoints(context.Background(), []models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := engine.CreateBackup(context.Background(), influxdb.BackupOptions{Incremental: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cache is not flushed, so the point is only in the WAL.
+	if got := manifest.FilesOfType(influxdb.BackupFileTypeTSM); len(got) != 0 {
+		t.Fatalf("got TSM files %v, expected none", got)
+	}
+	if len(manifest.FilesOfType(influxdb.BackupFileTypeWAL)) == 0 {
+		t.Fatal("backup contains no WAL segments")
+	}
+
+	path, err := ioutil.TempDir("", "storage_engine_backup_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	for _, file := range manifest.Files {
+		var buf bytes.Buffer
+		if err := engine.FetchBackupFile(context.Background(), manifest.ID, file.Path, &buf); err != nil {
+			t.Fatal(err)
+		}
+
+		dst := filepath.Join(path, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(dst, buf.Bytes(), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The WAL is replayed when the restored engine is opened.
+	restored := storage.NewEngine(path, config, storage.WithEngineID(rand.Int()), storage.WithNodeID(rand.Int()))
+	if err := restored.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+
+	if got, exp := restored.SeriesCardinality(), int64(1); got != exp {
+		t.Fatalf("got %d series, exp %d series in restored index", got, exp)
+	}
+}
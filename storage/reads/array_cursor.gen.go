@@ -9,6 +9,7 @@ package reads
 import (
 	"errors"
 
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
 )
 
@@ -132,6 +133,29 @@ func (c *floatMultiShardArrayCursor) Next() *cursors.FloatArray {
 	}
 }
 
+// PeekBlockStats returns the statistics of the next block of the cursor of
+// the current shard. Blocks of filtered or limited points are not skipped.
+func (c *floatMultiShardArrayCursor) PeekBlockStats() (minTime, maxTime int64, stats cursors.BlockStats, ok bool) {
+	blocks, ok := c.FloatArrayCursor.(cursors.BlockStatsCursor)
+	if !ok {
+		return 0, 0, stats, false
+	}
+
+	minTime, maxTime, stats, ok = blocks.PeekBlockStats()
+	if !ok || c.count+int64(stats.Count) > c.limit {
+		return 0, 0, stats, false
+	}
+	return minTime, maxTime, stats, true
+}
+
+// SkipBlock skips the block returned by PeekBlockStats.
+func (c *floatMultiShardArrayCursor) SkipBlock() {
+	blocks := c.FloatArrayCursor.(cursors.BlockStatsCursor)
+	_, _, stats, _ := blocks.PeekBlockStats()
+	c.count += int64(stats.Count)
+	blocks.SkipBlock()
+}
+
 func (c *floatMultiShardArrayCursor) nextArrayCursor() bool {
 	if len(c.itrs) == 0 {
 		return false
@@ -237,6 +261,323 @@ func (c *integerFloatCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// floatWindowArrayCursor aggregates the points of each window into a
+// point of the same type. Selectors keep the time of the selected point, and
+// the sum the time of the first point of the window.
+type floatWindowArrayCursor struct {
+	cursors.FloatArrayCursor
+	agg   datatypes.Aggregate_AggregateType
+	every int64
+	res   *cursors.FloatArray
+	tmp   *cursors.FloatArray
+
+	// blocks is not nil if the sum, min and max of the points of whole
+	// blocks can be read from their statistics.
+	blocks cursors.BlockStatsCursor
+}
+
+func newFloatWindowArrayCursor(cur cursors.FloatArrayCursor, agg datatypes.Aggregate_AggregateType, every int64) *floatWindowArrayCursor {
+	c := &floatWindowArrayCursor{
+		FloatArrayCursor: cur,
+		agg:              agg,
+		every:            every,
+		res:              cursors.NewFloatArrayLen(MaxPointsPerBlock),
+		tmp:              &cursors.FloatArray{},
+	}
+	switch agg {
+	case datatypes.AggregateTypeSum, datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+		c.blocks, _ = cur.(cursors.BlockStatsCursor)
+	}
+	return c
+}
+
+func (c *floatWindowArrayCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
+
+func (c *floatWindowArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int   // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+		v    float64
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if c.blocks != nil {
+				// Aggregate the blocks within a window from their statistics.
+				if minT, maxT, bs, ok := c.blocks.PeekBlockStats(); ok {
+					if n > 0 && minT >= stop {
+						c.res.Timestamps = append(c.res.Timestamps, ts)
+						c.res.Values = append(c.res.Values, v)
+						n = 0
+						if c.res.Len() >= MaxPointsPerBlock {
+							return c.res
+						}
+					}
+					if n == 0 {
+						stop = windowStop(minT, c.every)
+					}
+					if maxT < stop {
+						switch c.agg {
+						case datatypes.AggregateTypeSum:
+							if n == 0 {
+								ts, v = minT, 0
+							}
+							v += floatBlockValue(bs.Sum)
+						case datatypes.AggregateTypeMin:
+							if x := floatBlockValue(bs.Min); n == 0 || x < v {
+								ts, v = bs.TimeOfMin, x
+							}
+						case datatypes.AggregateTypeMax:
+							if x := floatBlockValue(bs.Max); n == 0 || x > v {
+								ts, v = bs.TimeOfMax, x
+							}
+						}
+						n += int(bs.Count)
+						c.blocks.SkipBlock()
+						continue
+					}
+				}
+			}
+			if a = c.FloatArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			stop = windowStop(a.Timestamps[0], c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		if j > 0 {
+			switch c.agg {
+			case datatypes.AggregateTypeFirst:
+				if n == 0 {
+					ts, v = a.Timestamps[0], a.Values[0]
+				}
+			case datatypes.AggregateTypeLast:
+				ts, v = a.Timestamps[j-1], a.Values[j-1]
+			case datatypes.AggregateTypeSum:
+				if n == 0 {
+					ts, v = a.Timestamps[0], 0
+				}
+				for _, x := range a.Values[:j] {
+					v += x
+				}
+			case datatypes.AggregateTypeMin:
+				for i, x := range a.Values[:j] {
+					if (n == 0 && i == 0) || x < v {
+						ts, v = a.Timestamps[i], x
+					}
+				}
+			case datatypes.AggregateTypeMax:
+				for i, x := range a.Values[:j] {
+					if (n == 0 && i == 0) || x > v {
+						ts, v = a.Timestamps[i], x
+					}
+				}
+			}
+			n += j
+		}
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+	}
+	return c.res
+}
+
+// floatWindowCountArrayCursor counts the points of each window. The
+// counts have the time of the first point of their window.
+type floatWindowCountArrayCursor struct {
+	cursors.FloatArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.FloatArray
+
+	// blocks is not nil if the points of whole blocks can be counted from
+	// their statistics.
+	blocks cursors.BlockStatsCursor
+}
+
+func newFloatWindowCountArrayCursor(cur cursors.FloatArrayCursor, every int64) *floatWindowCountArrayCursor {
+	c := &floatWindowCountArrayCursor{
+		FloatArrayCursor: cur,
+		every:            every,
+		res:              cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+		tmp:              &cursors.FloatArray{},
+	}
+	c.blocks, _ = cur.(cursors.BlockStatsCursor)
+	return c
+}
+
+func (c *floatWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int64 // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if c.blocks != nil {
+				// Count the points of the blocks within a window from their
+				// statistics.
+				if minT, maxT, bs, ok := c.blocks.PeekBlockStats(); ok {
+					if n > 0 && minT >= stop {
+						c.res.Timestamps = append(c.res.Timestamps, ts)
+						c.res.Values = append(c.res.Values, n)
+						n = 0
+						if c.res.Len() >= MaxPointsPerBlock {
+							return c.res
+						}
+					}
+					if n == 0 {
+						ts = minT
+						stop = windowStop(ts, c.every)
+					}
+					if maxT < stop {
+						n += int64(bs.Count)
+						c.blocks.SkipBlock()
+						continue
+					}
+				}
+			}
+			if a = c.FloatArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			ts = a.Timestamps[0]
+			stop = windowStop(ts, c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		n += int64(j)
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+	}
+	return c.res
+}
+
+// floatWindowMeanArrayCursor averages the points of each window. The
+// means have the time of the first point of their window.
+type floatWindowMeanArrayCursor struct {
+	cursors.FloatArrayCursor
+	every int64
+	res   *cursors.FloatArray
+	tmp   *cursors.FloatArray
+}
+
+func newFloatWindowMeanArrayCursor(cur cursors.FloatArrayCursor, every int64) *floatWindowMeanArrayCursor {
+	return &floatWindowMeanArrayCursor{
+		FloatArrayCursor: cur,
+		every:            every,
+		res:              cursors.NewFloatArrayLen(MaxPointsPerBlock),
+		tmp:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	a := c.tmp
+	if a.Len() == 0 {
+		a = c.FloatArrayCursor.Next()
+	}
+
+	var (
+		n    int   // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+		sum  float64
+	)
+	for a.Len() > 0 {
+		if n == 0 {
+			ts, sum = a.Timestamps[0], 0
+			stop = windowStop(ts, c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		for _, x := range a.Values[:j] {
+			sum += float64(x)
+		}
+		n += j
+
+		if j == a.Len() {
+			a = c.FloatArrayCursor.Next()
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, sum/float64(n))
+		n = 0
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, sum/float64(n))
+	}
+	c.tmp.Timestamps, c.tmp.Values = nil, nil
+	return c.res
+}
+
 type floatEmptyArrayCursor struct {
 	res cursors.FloatArray
 }
@@ -361,6 +702,29 @@ func (c *integerMultiShardArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// PeekBlockStats returns the statistics of the next block of the cursor of
+// the current shard. Blocks of filtered or limited points are not skipped.
+func (c *integerMultiShardArrayCursor) PeekBlockStats() (minTime, maxTime int64, stats cursors.BlockStats, ok bool) {
+	blocks, ok := c.IntegerArrayCursor.(cursors.BlockStatsCursor)
+	if !ok {
+		return 0, 0, stats, false
+	}
+
+	minTime, maxTime, stats, ok = blocks.PeekBlockStats()
+	if !ok || c.count+int64(stats.Count) > c.limit {
+		return 0, 0, stats, false
+	}
+	return minTime, maxTime, stats, true
+}
+
+// SkipBlock skips the block returned by PeekBlockStats.
+func (c *integerMultiShardArrayCursor) SkipBlock() {
+	blocks := c.IntegerArrayCursor.(cursors.BlockStatsCursor)
+	_, _, stats, _ := blocks.PeekBlockStats()
+	c.count += int64(stats.Count)
+	blocks.SkipBlock()
+}
+
 func (c *integerMultiShardArrayCursor) nextArrayCursor() bool {
 	if len(c.itrs) == 0 {
 		return false
@@ -466,6 +830,323 @@ func (c *integerIntegerCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// integerWindowArrayCursor aggregates the points of each window into a
+// point of the same type. Selectors keep the time of the selected point, and
+// the sum the time of the first point of the window.
+type integerWindowArrayCursor struct {
+	cursors.IntegerArrayCursor
+	agg   datatypes.Aggregate_AggregateType
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.IntegerArray
+
+	// blocks is not nil if the sum, min and max of the points of whole
+	// blocks can be read from their statistics.
+	blocks cursors.BlockStatsCursor
+}
+
+func newIntegerWindowArrayCursor(cur cursors.IntegerArrayCursor, agg datatypes.Aggregate_AggregateType, every int64) *integerWindowArrayCursor {
+	c := &integerWindowArrayCursor{
+		IntegerArrayCursor: cur,
+		agg:                agg,
+		every:              every,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+		tmp:                &cursors.IntegerArray{},
+	}
+	switch agg {
+	case datatypes.AggregateTypeSum, datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+		c.blocks, _ = cur.(cursors.BlockStatsCursor)
+	}
+	return c
+}
+
+func (c *integerWindowArrayCursor) Stats() cursors.CursorStats { return c.IntegerArrayCursor.Stats() }
+
+func (c *integerWindowArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int   // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+		v    int64
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if c.blocks != nil {
+				// Aggregate the blocks within a window from their statistics.
+				if minT, maxT, bs, ok := c.blocks.PeekBlockStats(); ok {
+					if n > 0 && minT >= stop {
+						c.res.Timestamps = append(c.res.Timestamps, ts)
+						c.res.Values = append(c.res.Values, v)
+						n = 0
+						if c.res.Len() >= MaxPointsPerBlock {
+							return c.res
+						}
+					}
+					if n == 0 {
+						stop = windowStop(minT, c.every)
+					}
+					if maxT < stop {
+						switch c.agg {
+						case datatypes.AggregateTypeSum:
+							if n == 0 {
+								ts, v = minT, 0
+							}
+							v += integerBlockValue(bs.Sum)
+						case datatypes.AggregateTypeMin:
+							if x := integerBlockValue(bs.Min); n == 0 || x < v {
+								ts, v = bs.TimeOfMin, x
+							}
+						case datatypes.AggregateTypeMax:
+							if x := integerBlockValue(bs.Max); n == 0 || x > v {
+								ts, v = bs.TimeOfMax, x
+							}
+						}
+						n += int(bs.Count)
+						c.blocks.SkipBlock()
+						continue
+					}
+				}
+			}
+			if a = c.IntegerArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			stop = windowStop(a.Timestamps[0], c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		if j > 0 {
+			switch c.agg {
+			case datatypes.AggregateTypeFirst:
+				if n == 0 {
+					ts, v = a.Timestamps[0], a.Values[0]
+				}
+			case datatypes.AggregateTypeLast:
+				ts, v = a.Timestamps[j-1], a.Values[j-1]
+			case datatypes.AggregateTypeSum:
+				if n == 0 {
+					ts, v = a.Timestamps[0], 0
+				}
+				for _, x := range a.Values[:j] {
+					v += x
+				}
+			case datatypes.AggregateTypeMin:
+				for i, x := range a.Values[:j] {
+					if (n == 0 && i == 0) || x < v {
+						ts, v = a.Timestamps[i], x
+					}
+				}
+			case datatypes.AggregateTypeMax:
+				for i, x := range a.Values[:j] {
+					if (n == 0 && i == 0) || x > v {
+						ts, v = a.Timestamps[i], x
+					}
+				}
+			}
+			n += j
+		}
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+	}
+	return c.res
+}
+
+// integerWindowCountArrayCursor counts the points of each window. The
+// counts have the time of the first point of their window.
+type integerWindowCountArrayCursor struct {
+	cursors.IntegerArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.IntegerArray
+
+	// blocks is not nil if the points of whole blocks can be counted from
+	// their statistics.
+	blocks cursors.BlockStatsCursor
+}
+
+func newIntegerWindowCountArrayCursor(cur cursors.IntegerArrayCursor, every int64) *integerWindowCountArrayCursor {
+	c := &integerWindowCountArrayCursor{
+		IntegerArrayCursor: cur,
+		every:              every,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+		tmp:                &cursors.IntegerArray{},
+	}
+	c.blocks, _ = cur.(cursors.BlockStatsCursor)
+	return c
+}
+
+func (c *integerWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int64 // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if c.blocks != nil {
+				// Count the points of the blocks within a window from their
+				// statistics.
+				if minT, maxT, bs, ok := c.blocks.PeekBlockStats(); ok {
+					if n > 0 && minT >= stop {
+						c.res.Timestamps = append(c.res.Timestamps, ts)
+						c.res.Values = append(c.res.Values, n)
+						n = 0
+						if c.res.Len() >= MaxPointsPerBlock {
+							return c.res
+						}
+					}
+					if n == 0 {
+						ts = minT
+						stop = windowStop(ts, c.every)
+					}
+					if maxT < stop {
+						n += int64(bs.Count)
+						c.blocks.SkipBlock()
+						continue
+					}
+				}
+			}
+			if a = c.IntegerArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			ts = a.Timestamps[0]
+			stop = windowStop(ts, c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		n += int64(j)
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+	}
+	return c.res
+}
+
+// integerWindowMeanArrayCursor averages the points of each window. The
+// means have the time of the first point of their window.
+type integerWindowMeanArrayCursor struct {
+	cursors.IntegerArrayCursor
+	every int64
+	res   *cursors.FloatArray
+	tmp   *cursors.IntegerArray
+}
+
+func newIntegerWindowMeanArrayCursor(cur cursors.IntegerArrayCursor, every int64) *integerWindowMeanArrayCursor {
+	return &integerWindowMeanArrayCursor{
+		IntegerArrayCursor: cur,
+		every:              every,
+		res:                cursors.NewFloatArrayLen(MaxPointsPerBlock),
+		tmp:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	a := c.tmp
+	if a.Len() == 0 {
+		a = c.IntegerArrayCursor.Next()
+	}
+
+	var (
+		n    int   // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+		sum  float64
+	)
+	for a.Len() > 0 {
+		if n == 0 {
+			ts, sum = a.Timestamps[0], 0
+			stop = windowStop(ts, c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		for _, x := range a.Values[:j] {
+			sum += float64(x)
+		}
+		n += j
+
+		if j == a.Len() {
+			a = c.IntegerArrayCursor.Next()
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, sum/float64(n))
+		n = 0
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, sum/float64(n))
+	}
+	c.tmp.Timestamps, c.tmp.Values = nil, nil
+	return c.res
+}
+
 type integerEmptyArrayCursor struct {
 	res cursors.IntegerArray
 }
@@ -590,6 +1271,29 @@ func (c *unsignedMultiShardArrayCursor) Next() *cursors.UnsignedArray {
 	}
 }
 
+// PeekBlockStats returns the statistics of the next block of the cursor of
+// the current shard. Blocks of filtered or limited points are not skipped.
+func (c *unsignedMultiShardArrayCursor) PeekBlockStats() (minTime, maxTime int64, stats cursors.BlockStats, ok bool) {
+	blocks, ok := c.UnsignedArrayCursor.(cursors.BlockStatsCursor)
+	if !ok {
+		return 0, 0, stats, false
+	}
+
+	minTime, maxTime, stats, ok = blocks.PeekBlockStats()
+	if !ok || c.count+int64(stats.Count) > c.limit {
+		return 0, 0, stats, false
+	}
+	return minTime, maxTime, stats, true
+}
+
+// SkipBlock skips the block returned by PeekBlockStats.
+func (c *unsignedMultiShardArrayCursor) SkipBlock() {
+	blocks := c.UnsignedArrayCursor.(cursors.BlockStatsCursor)
+	_, _, stats, _ := blocks.PeekBlockStats()
+	c.count += int64(stats.Count)
+	blocks.SkipBlock()
+}
+
 func (c *unsignedMultiShardArrayCursor) nextArrayCursor() bool {
 	if len(c.itrs) == 0 {
 		return false
@@ -695,6 +1399,323 @@ func (c *integerUnsignedCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// unsignedWindowArrayCursor aggregates the points of each window into a
+// point of the same type. Selectors keep the time of the selected point, and
+// the sum the time of the first point of the window.
+type unsignedWindowArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	agg   datatypes.Aggregate_AggregateType
+	every int64
+	res   *cursors.UnsignedArray
+	tmp   *cursors.UnsignedArray
+
+	// blocks is not nil if the sum, min and max of the points of whole
+	// blocks can be read from their statistics.
+	blocks cursors.BlockStatsCursor
+}
+
+func newUnsignedWindowArrayCursor(cur cursors.UnsignedArrayCursor, agg datatypes.Aggregate_AggregateType, every int64) *unsignedWindowArrayCursor {
+	c := &unsignedWindowArrayCursor{
+		UnsignedArrayCursor: cur,
+		agg:                 agg,
+		every:               every,
+		res:                 cursors.NewUnsignedArrayLen(MaxPointsPerBlock),
+		tmp:                 &cursors.UnsignedArray{},
+	}
+	switch agg {
+	case datatypes.AggregateTypeSum, datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+		c.blocks, _ = cur.(cursors.BlockStatsCursor)
+	}
+	return c
+}
+
+func (c *unsignedWindowArrayCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
+
+func (c *unsignedWindowArrayCursor) Next() *cursors.UnsignedArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int   // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+		v    uint64
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if c.blocks != nil {
+				// Aggregate the blocks within a window from their statistics.
+				if minT, maxT, bs, ok := c.blocks.PeekBlockStats(); ok {
+					if n > 0 && minT >= stop {
+						c.res.Timestamps = append(c.res.Timestamps, ts)
+						c.res.Values = append(c.res.Values, v)
+						n = 0
+						if c.res.Len() >= MaxPointsPerBlock {
+							return c.res
+						}
+					}
+					if n == 0 {
+						stop = windowStop(minT, c.every)
+					}
+					if maxT < stop {
+						switch c.agg {
+						case datatypes.AggregateTypeSum:
+							if n == 0 {
+								ts, v = minT, 0
+							}
+							v += unsignedBlockValue(bs.Sum)
+						case datatypes.AggregateTypeMin:
+							if x := unsignedBlockValue(bs.Min); n == 0 || x < v {
+								ts, v = bs.TimeOfMin, x
+							}
+						case datatypes.AggregateTypeMax:
+							if x := unsignedBlockValue(bs.Max); n == 0 || x > v {
+								ts, v = bs.TimeOfMax, x
+							}
+						}
+						n += int(bs.Count)
+						c.blocks.SkipBlock()
+						continue
+					}
+				}
+			}
+			if a = c.UnsignedArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			stop = windowStop(a.Timestamps[0], c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		if j > 0 {
+			switch c.agg {
+			case datatypes.AggregateTypeFirst:
+				if n == 0 {
+					ts, v = a.Timestamps[0], a.Values[0]
+				}
+			case datatypes.AggregateTypeLast:
+				ts, v = a.Timestamps[j-1], a.Values[j-1]
+			case datatypes.AggregateTypeSum:
+				if n == 0 {
+					ts, v = a.Timestamps[0], 0
+				}
+				for _, x := range a.Values[:j] {
+					v += x
+				}
+			case datatypes.AggregateTypeMin:
+				for i, x := range a.Values[:j] {
+					if (n == 0 && i == 0) || x < v {
+						ts, v = a.Timestamps[i], x
+					}
+				}
+			case datatypes.AggregateTypeMax:
+				for i, x := range a.Values[:j] {
+					if (n == 0 && i == 0) || x > v {
+						ts, v = a.Timestamps[i], x
+					}
+				}
+			}
+			n += j
+		}
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+	}
+	return c.res
+}
+
+// unsignedWindowCountArrayCursor counts the points of each window. The
+// counts have the time of the first point of their window.
+type unsignedWindowCountArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.UnsignedArray
+
+	// blocks is not nil if the points of whole blocks can be counted from
+	// their statistics.
+	blocks cursors.BlockStatsCursor
+}
+
+func newUnsignedWindowCountArrayCursor(cur cursors.UnsignedArrayCursor, every int64) *unsignedWindowCountArrayCursor {
+	c := &unsignedWindowCountArrayCursor{
+		UnsignedArrayCursor: cur,
+		every:               every,
+		res:                 cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+		tmp:                 &cursors.UnsignedArray{},
+	}
+	c.blocks, _ = cur.(cursors.BlockStatsCursor)
+	return c
+}
+
+func (c *unsignedWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int64 // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if c.blocks != nil {
+				// Count the points of the blocks within a window from their
+				// statistics.
+				if minT, maxT, bs, ok := c.blocks.PeekBlockStats(); ok {
+					if n > 0 && minT >= stop {
+						c.res.Timestamps = append(c.res.Timestamps, ts)
+						c.res.Values = append(c.res.Values, n)
+						n = 0
+						if c.res.Len() >= MaxPointsPerBlock {
+							return c.res
+						}
+					}
+					if n == 0 {
+						ts = minT
+						stop = windowStop(ts, c.every)
+					}
+					if maxT < stop {
+						n += int64(bs.Count)
+						c.blocks.SkipBlock()
+						continue
+					}
+				}
+			}
+			if a = c.UnsignedArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			ts = a.Timestamps[0]
+			stop = windowStop(ts, c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		n += int64(j)
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+	}
+	return c.res
+}
+
+// unsignedWindowMeanArrayCursor averages the points of each window. The
+// means have the time of the first point of their window.
+type unsignedWindowMeanArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	every int64
+	res   *cursors.FloatArray
+	tmp   *cursors.UnsignedArray
+}
+
+func newUnsignedWindowMeanArrayCursor(cur cursors.UnsignedArrayCursor, every int64) *unsignedWindowMeanArrayCursor {
+	return &unsignedWindowMeanArrayCursor{
+		UnsignedArrayCursor: cur,
+		every:               every,
+		res:                 cursors.NewFloatArrayLen(MaxPointsPerBlock),
+		tmp:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c *unsignedWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	a := c.tmp
+	if a.Len() == 0 {
+		a = c.UnsignedArrayCursor.Next()
+	}
+
+	var (
+		n    int   // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+		sum  float64
+	)
+	for a.Len() > 0 {
+		if n == 0 {
+			ts, sum = a.Timestamps[0], 0
+			stop = windowStop(ts, c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		for _, x := range a.Values[:j] {
+			sum += float64(x)
+		}
+		n += j
+
+		if j == a.Len() {
+			a = c.UnsignedArrayCursor.Next()
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, sum/float64(n))
+		n = 0
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, sum/float64(n))
+	}
+	c.tmp.Timestamps, c.tmp.Values = nil, nil
+	return c.res
+}
+
 type unsignedEmptyArrayCursor struct {
 	res cursors.UnsignedArray
 }
@@ -884,6 +1905,159 @@ func (c *integerStringCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// stringWindowArrayCursor aggregates the points of each window into a
+// point of the same type. Selectors keep the time of the selected point, and
+// the sum the time of the first point of the window.
+type stringWindowArrayCursor struct {
+	cursors.StringArrayCursor
+	agg   datatypes.Aggregate_AggregateType
+	every int64
+	res   *cursors.StringArray
+	tmp   *cursors.StringArray
+}
+
+func newStringWindowArrayCursor(cur cursors.StringArrayCursor, agg datatypes.Aggregate_AggregateType, every int64) *stringWindowArrayCursor {
+	c := &stringWindowArrayCursor{
+		StringArrayCursor: cur,
+		agg:               agg,
+		every:             every,
+		res:               cursors.NewStringArrayLen(MaxPointsPerBlock),
+		tmp:               &cursors.StringArray{},
+	}
+	return c
+}
+
+func (c *stringWindowArrayCursor) Stats() cursors.CursorStats { return c.StringArrayCursor.Stats() }
+
+func (c *stringWindowArrayCursor) Next() *cursors.StringArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int   // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+		v    string
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if a = c.StringArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			stop = windowStop(a.Timestamps[0], c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		if j > 0 {
+			switch c.agg {
+			case datatypes.AggregateTypeFirst:
+				if n == 0 {
+					ts, v = a.Timestamps[0], a.Values[0]
+				}
+			case datatypes.AggregateTypeLast:
+				ts, v = a.Timestamps[j-1], a.Values[j-1]
+			}
+			n += j
+		}
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+	}
+	return c.res
+}
+
+// stringWindowCountArrayCursor counts the points of each window. The
+// counts have the time of the first point of their window.
+type stringWindowCountArrayCursor struct {
+	cursors.StringArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.StringArray
+}
+
+func newStringWindowCountArrayCursor(cur cursors.StringArrayCursor, every int64) *stringWindowCountArrayCursor {
+	c := &stringWindowCountArrayCursor{
+		StringArrayCursor: cur,
+		every:             every,
+		res:               cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+		tmp:               &cursors.StringArray{},
+	}
+	return c
+}
+
+func (c *stringWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *stringWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int64 // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if a = c.StringArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			ts = a.Timestamps[0]
+			stop = windowStop(ts, c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		n += int64(j)
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+	}
+	return c.res
+}
+
 type stringEmptyArrayCursor struct {
 	res cursors.StringArray
 }
@@ -1073,6 +2247,159 @@ func (c *integerBooleanCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// booleanWindowArrayCursor aggregates the points of each window into a
+// point of the same type. Selectors keep the time of the selected point, and
+// the sum the time of the first point of the window.
+type booleanWindowArrayCursor struct {
+	cursors.BooleanArrayCursor
+	agg   datatypes.Aggregate_AggregateType
+	every int64
+	res   *cursors.BooleanArray
+	tmp   *cursors.BooleanArray
+}
+
+func newBooleanWindowArrayCursor(cur cursors.BooleanArrayCursor, agg datatypes.Aggregate_AggregateType, every int64) *booleanWindowArrayCursor {
+	c := &booleanWindowArrayCursor{
+		BooleanArrayCursor: cur,
+		agg:                agg,
+		every:              every,
+		res:                cursors.NewBooleanArrayLen(MaxPointsPerBlock),
+		tmp:                &cursors.BooleanArray{},
+	}
+	return c
+}
+
+func (c *booleanWindowArrayCursor) Stats() cursors.CursorStats { return c.BooleanArrayCursor.Stats() }
+
+func (c *booleanWindowArrayCursor) Next() *cursors.BooleanArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int   // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+		v    bool
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if a = c.BooleanArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			stop = windowStop(a.Timestamps[0], c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		if j > 0 {
+			switch c.agg {
+			case datatypes.AggregateTypeFirst:
+				if n == 0 {
+					ts, v = a.Timestamps[0], a.Values[0]
+				}
+			case datatypes.AggregateTypeLast:
+				ts, v = a.Timestamps[j-1], a.Values[j-1]
+			}
+			n += j
+		}
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, v)
+	}
+	return c.res
+}
+
+// booleanWindowCountArrayCursor counts the points of each window. The
+// counts have the time of the first point of their window.
+type booleanWindowCountArrayCursor struct {
+	cursors.BooleanArrayCursor
+	every int64
+	res   *cursors.IntegerArray
+	tmp   *cursors.BooleanArray
+}
+
+func newBooleanWindowCountArrayCursor(cur cursors.BooleanArrayCursor, every int64) *booleanWindowCountArrayCursor {
+	c := &booleanWindowCountArrayCursor{
+		BooleanArrayCursor: cur,
+		every:              every,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+		tmp:                &cursors.BooleanArray{},
+	}
+	return c
+}
+
+func (c *booleanWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *booleanWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:0]
+	c.res.Values = c.res.Values[:0]
+
+	var (
+		n    int64 // Number of points of the current window.
+		stop int64 // Stop of the current window.
+		ts   int64
+	)
+	a := c.tmp
+	for {
+		if a.Len() == 0 {
+			if a = c.BooleanArrayCursor.Next(); a.Len() == 0 {
+				break
+			}
+		}
+
+		if n == 0 {
+			ts = a.Timestamps[0]
+			stop = windowStop(ts, c.every)
+		}
+
+		j := windowEnd(a.Timestamps, stop)
+		n += int64(j)
+
+		c.tmp.Timestamps, c.tmp.Values = a.Timestamps[j:], a.Values[j:]
+		a = c.tmp
+		if a.Len() == 0 {
+			// The window may continue in the next array.
+			continue
+		}
+
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+		n = 0
+
+		if c.res.Len() >= MaxPointsPerBlock {
+			return c.res
+		}
+	}
+
+	if n > 0 {
+		c.res.Timestamps = append(c.res.Timestamps, ts)
+		c.res.Values = append(c.res.Values, n)
+	}
+	return c.res
+}
+
 type booleanEmptyArrayCursor struct {
 	res cursors.BooleanArray
 }
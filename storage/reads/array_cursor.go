@@ -3,6 +3,8 @@ package reads
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
@@ -23,9 +25,20 @@ func newAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, curs
 
 	switch agg.Type {
 	case datatypes.AggregateTypeSum:
-		return newSumArrayCursor(cursor)
+		if agg.WindowEvery == 0 {
+			return newSumArrayCursor(cursor)
+		}
+		return newWindowArrayCursor(cursor, agg)
 	case datatypes.AggregateTypeCount:
-		return newCountArrayCursor(cursor)
+		if agg.WindowEvery == 0 {
+			return newCountArrayCursor(cursor)
+		}
+		return newWindowCountArrayCursor(cursor, agg.WindowEvery)
+	case datatypes.AggregateTypeMin, datatypes.AggregateTypeMax,
+		datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast:
+		return newWindowArrayCursor(cursor, agg)
+	case datatypes.AggregateTypeMean:
+		return newWindowMeanArrayCursor(cursor, agg.WindowEvery)
 	default:
 		// TODO(sgc): should be validated higher up
 		panic("invalid aggregate")
@@ -63,6 +76,96 @@ func newCountArrayCursor(cur cursors.Cursor) cursors.Cursor {
 	}
 }
 
+// windowStop returns the stop of the window of every nanoseconds holding the
+// time t. Windows are aligned to the epoch, as the windows of Flux. A zero
+// every is a single window holding all times.
+func windowStop(t, every int64) int64 {
+	if every <= 0 {
+		return math.MaxInt64
+	}
+	start := t - t%every
+	if t < 0 && start != t {
+		start -= every
+	}
+	if start > math.MaxInt64-every {
+		return math.MaxInt64
+	}
+	return start + every
+}
+
+// windowEnd returns the index of the first of the sorted timestamps ts at or
+// after stop.
+func windowEnd(ts []int64, stop int64) int {
+	if len(ts) == 0 || ts[len(ts)-1] < stop {
+		return len(ts)
+	}
+	return sort.Search(len(ts), func(i int) bool { return ts[i] >= stop })
+}
+
+// floatBlockValue, integerBlockValue and unsignedBlockValue return the value
+// of the bits of the statistics of a block.
+func floatBlockValue(bits uint64) float64   { return math.Float64frombits(bits) }
+func integerBlockValue(bits uint64) int64   { return int64(bits) }
+func unsignedBlockValue(bits uint64) uint64 { return bits }
+
+// newWindowArrayCursor returns a cursor aggregating the points of cur in
+// windows into points of the same type, or nil if the aggregate does not
+// apply to the type of cur.
+func newWindowArrayCursor(cur cursors.Cursor, agg *datatypes.Aggregate) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowArrayCursor(cur, agg.Type, agg.WindowEvery)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowArrayCursor(cur, agg.Type, agg.WindowEvery)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowArrayCursor(cur, agg.Type, agg.WindowEvery)
+	}
+
+	// Only first and last select points of any type.
+	if agg.Type != datatypes.AggregateTypeFirst && agg.Type != datatypes.AggregateTypeLast {
+		return nil
+	}
+	switch cur := cur.(type) {
+	case cursors.StringArrayCursor:
+		return newStringWindowArrayCursor(cur, agg.Type, agg.WindowEvery)
+	case cursors.BooleanArrayCursor:
+		return newBooleanWindowArrayCursor(cur, agg.Type, agg.WindowEvery)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newWindowCountArrayCursor(cur cursors.Cursor, every int64) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowCountArrayCursor(cur, every)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowCountArrayCursor(cur, every)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowCountArrayCursor(cur, every)
+	case cursors.StringArrayCursor:
+		return newStringWindowCountArrayCursor(cur, every)
+	case cursors.BooleanArrayCursor:
+		return newBooleanWindowCountArrayCursor(cur, every)
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newWindowMeanArrayCursor(cur cursors.Cursor, every int64) cursors.Cursor {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowMeanArrayCursor(cur, every)
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowMeanArrayCursor(cur, every)
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowMeanArrayCursor(cur, every)
+	default:
+		// TODO(sgc): propagate an error instead?
+		return nil
+	}
+}
+
 type cursorContext struct {
 	ctx   context.Context
 	req   *cursors.CursorRequest
@@ -0,0 +1,210 @@
+package reads
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// mockIntegerArrayCursor returns the points of arrays one array at a time.
+type mockIntegerArrayCursor struct {
+	arrays []*cursors.IntegerArray
+}
+
+func (c *mockIntegerArrayCursor) Close()                     {}
+func (c *mockIntegerArrayCursor) Err() error                 { return nil }
+func (c *mockIntegerArrayCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+func (c *mockIntegerArrayCursor) Next() *cursors.IntegerArray {
+	if len(c.arrays) == 0 {
+		return &cursors.IntegerArray{}
+	}
+	a := c.arrays[0]
+	c.arrays = c.arrays[1:]
+	return a
+}
+
+// mockIntegerBlockStatsCursor returns the points of arrays one array at a
+// time, or skips them as blocks summarized by their statistics.
+type mockIntegerBlockStatsCursor struct {
+	mockIntegerArrayCursor
+	skipped int
+}
+
+func (c *mockIntegerBlockStatsCursor) PeekBlockStats() (minTime, maxTime int64, stats cursors.BlockStats, ok bool) {
+	if len(c.arrays) == 0 {
+		return 0, 0, stats, false
+	}
+	a := c.arrays[0]
+	stats = cursors.BlockStats{
+		Count:     uint32(a.Len()),
+		Min:       uint64(a.Values[0]),
+		Max:       uint64(a.Values[0]),
+		TimeOfMin: a.Timestamps[0],
+		TimeOfMax: a.Timestamps[0],
+	}
+	var sum int64
+	for i, v := range a.Values {
+		sum += v
+		if v < int64(stats.Min) {
+			stats.Min, stats.TimeOfMin = uint64(v), a.Timestamps[i]
+		}
+		if v > int64(stats.Max) {
+			stats.Max, stats.TimeOfMax = uint64(v), a.Timestamps[i]
+		}
+	}
+	stats.Sum = uint64(sum)
+	return a.Timestamps[0], a.Timestamps[a.Len()-1], stats, true
+}
+
+func (c *mockIntegerBlockStatsCursor) SkipBlock() {
+	c.arrays = c.arrays[1:]
+	c.skipped++
+}
+
+// windowTestArrays returns the points at times 0, 3, 5, 7, 12, 13 and 25 with
+// values 4, 2, 6, 1, 3, 3 and 5, split across several arrays.
+func windowTestArrays() []*cursors.IntegerArray {
+	return []*cursors.IntegerArray{
+		{Timestamps: []int64{0, 3}, Values: []int64{4, 2}},
+		{Timestamps: []int64{5, 7, 12}, Values: []int64{6, 1, 3}},
+		{Timestamps: []int64{13, 25}, Values: []int64{3, 5}},
+	}
+}
+
+func readIntegerArrays(cur cursors.IntegerArrayCursor) *cursors.IntegerArray {
+	res := &cursors.IntegerArray{}
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		res.Timestamps = append(res.Timestamps, a.Timestamps...)
+		res.Values = append(res.Values, a.Values...)
+	}
+	return res
+}
+
+func TestIntegerWindowArrayCursor(t *testing.T) {
+	tests := []struct {
+		agg  datatypes.Aggregate_AggregateType
+		want *cursors.IntegerArray
+	}{
+		{
+			agg:  datatypes.AggregateTypeSum,
+			want: &cursors.IntegerArray{Timestamps: []int64{0, 12, 25}, Values: []int64{13, 6, 5}},
+		},
+		{
+			agg:  datatypes.AggregateTypeMin,
+			want: &cursors.IntegerArray{Timestamps: []int64{7, 12, 25}, Values: []int64{1, 3, 5}},
+		},
+		{
+			agg:  datatypes.AggregateTypeMax,
+			want: &cursors.IntegerArray{Timestamps: []int64{5, 12, 25}, Values: []int64{6, 3, 5}},
+		},
+		{
+			agg:  datatypes.AggregateTypeFirst,
+			want: &cursors.IntegerArray{Timestamps: []int64{0, 12, 25}, Values: []int64{4, 3, 5}},
+		},
+		{
+			agg:  datatypes.AggregateTypeLast,
+			want: &cursors.IntegerArray{Timestamps: []int64{7, 13, 25}, Values: []int64{1, 3, 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.agg.String(), func(t *testing.T) {
+			cur := newIntegerWindowArrayCursor(&mockIntegerArrayCursor{arrays: windowTestArrays()}, tt.agg, 10)
+			if got := readIntegerArrays(cur); !cmp.Equal(got, tt.want) {
+				t.Errorf("unexpected points -got/+want\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestIntegerWindowCountArrayCursor(t *testing.T) {
+	cur := newIntegerWindowCountArrayCursor(&mockIntegerArrayCursor{arrays: windowTestArrays()}, 10)
+	want := &cursors.IntegerArray{Timestamps: []int64{0, 12, 25}, Values: []int64{4, 2, 1}}
+	if got := readIntegerArrays(cur); !cmp.Equal(got, want) {
+		t.Errorf("unexpected points -got/+want\n%s", cmp.Diff(got, want))
+	}
+}
+
+func TestIntegerWindowMeanArrayCursor(t *testing.T) {
+	cur := newIntegerWindowMeanArrayCursor(&mockIntegerArrayCursor{arrays: windowTestArrays()}, 10)
+	got := &cursors.FloatArray{}
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		got.Timestamps = append(got.Timestamps, a.Timestamps...)
+		got.Values = append(got.Values, a.Values...)
+	}
+	want := &cursors.FloatArray{Timestamps: []int64{0, 12, 25}, Values: []float64{3.25, 3, 5}}
+	if !cmp.Equal(got, want) {
+		t.Errorf("unexpected points -got/+want\n%s", cmp.Diff(got, want))
+	}
+}
+
+func TestIntegerWindowArrayCursor_BlockStats(t *testing.T) {
+	for _, every := range []int64{10, 100} {
+		for _, agg := range []datatypes.Aggregate_AggregateType{
+			datatypes.AggregateTypeCount,
+			datatypes.AggregateTypeSum,
+			datatypes.AggregateTypeMin,
+			datatypes.AggregateTypeMax,
+		} {
+			t.Run(fmt.Sprintf("%s/%d", agg, every), func(t *testing.T) {
+				newCursor := func(cur cursors.IntegerArrayCursor) cursors.IntegerArrayCursor {
+					if agg == datatypes.AggregateTypeCount {
+						return newIntegerWindowCountArrayCursor(cur, every)
+					}
+					return newIntegerWindowArrayCursor(cur, agg, every)
+				}
+
+				blocks := &mockIntegerBlockStatsCursor{mockIntegerArrayCursor: mockIntegerArrayCursor{arrays: windowTestArrays()}}
+				got := readIntegerArrays(newCursor(blocks))
+				want := readIntegerArrays(newCursor(&mockIntegerArrayCursor{arrays: windowTestArrays()}))
+				if !cmp.Equal(got, want) {
+					t.Errorf("unexpected points -got/+want\n%s", cmp.Diff(got, want))
+				}
+				if blocks.skipped == 0 {
+					t.Error("expected skipped blocks")
+				}
+			})
+		}
+	}
+}
+
+func TestIntegerWindowArrayCursor_MaxPointsPerBlock(t *testing.T) {
+	a := &cursors.IntegerArray{}
+	for i := 0; i < 2*MaxPointsPerBlock+1; i++ {
+		a.Timestamps = append(a.Timestamps, int64(i))
+		a.Values = append(a.Values, int64(i))
+	}
+	cur := newIntegerWindowCountArrayCursor(&mockIntegerArrayCursor{arrays: []*cursors.IntegerArray{a}}, 2)
+
+	var n int
+	for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+		if a.Len() > MaxPointsPerBlock {
+			t.Fatalf("got %d points, expected at most %d", a.Len(), MaxPointsPerBlock)
+		}
+		n += a.Len()
+	}
+	if want := MaxPointsPerBlock + 1; n != want {
+		t.Fatalf("got %d windows, expected %d", n, want)
+	}
+}
+
+func TestWindowStop(t *testing.T) {
+	for _, tt := range []struct {
+		t, every, want int64
+	}{
+		{t: 0, every: 10, want: 10},
+		{t: 9, every: 10, want: 10},
+		{t: 10, every: 10, want: 20},
+		{t: -5, every: 10, want: 0},
+		{t: -10, every: 10, want: 0},
+		{t: 5, every: 0, want: 1<<63 - 1},
+		{t: 1<<63 - 5, every: 10, want: 1<<63 - 1},
+	} {
+		if got := windowStop(tt.t, tt.every); got != tt.want {
+			t.Errorf("windowStop(%d, %d) = %d, expected %d", tt.t, tt.every, got, tt.want)
+		}
+	}
+}
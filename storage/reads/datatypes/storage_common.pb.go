@@ -96,18 +96,33 @@ const (
 	AggregateTypeNone  Aggregate_AggregateType = 0
 	AggregateTypeSum   Aggregate_AggregateType = 1
 	AggregateTypeCount Aggregate_AggregateType = 2
+	AggregateTypeMin   Aggregate_AggregateType = 3
+	AggregateTypeMax   Aggregate_AggregateType = 4
+	AggregateTypeFirst Aggregate_AggregateType = 5
+	AggregateTypeLast  Aggregate_AggregateType = 6
+	AggregateTypeMean  Aggregate_AggregateType = 7
 )
 
 var Aggregate_AggregateType_name = map[int32]string{
 	0: "NONE",
 	1: "SUM",
 	2: "COUNT",
+	3: "MIN",
+	4: "MAX",
+	5: "FIRST",
+	6: "LAST",
+	7: "MEAN",
 }
 
 var Aggregate_AggregateType_value = map[string]int32{
 	"NONE":  0,
 	"SUM":   1,
 	"COUNT": 2,
+	"MIN":   3,
+	"MAX":   4,
+	"FIRST": 5,
+	"LAST":  6,
+	"MEAN":  7,
 }
 
 func (x Aggregate_AggregateType) String() string {
@@ -181,6 +196,9 @@ type ReadFilterRequest struct {
 	ReadSource *types.Any     `protobuf:"bytes,1,opt,name=read_source,json=readSource,proto3" json:"read_source,omitempty"`
 	Range      TimestampRange `protobuf:"bytes,2,opt,name=range,proto3" json:"range"`
 	Predicate  *Predicate     `protobuf:"bytes,3,opt,name=predicate,proto3" json:"predicate,omitempty"`
+	// Aggregate aggregates the points of each series, in windows when
+	// Aggregate.WindowEvery is set.
+	Aggregate *Aggregate `protobuf:"bytes,4,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
 }
 
 func (m *ReadFilterRequest) Reset()         { *m = ReadFilterRequest{} }
@@ -263,6 +281,10 @@ var xxx_messageInfo_ReadGroupRequest proto.InternalMessageInfo
 
 type Aggregate struct {
 	Type Aggregate_AggregateType `protobuf:"varint,1,opt,name=type,proto3,enum=influxdata.platform.storage.Aggregate_AggregateType" json:"type,omitempty"`
+	// WindowEvery is the duration, in nanoseconds, of the windows the points
+	// are aggregated in. The windows are aligned to the epoch. The points of
+	// the whole range are aggregated when it is zero.
+	WindowEvery int64 `protobuf:"varint,2,opt,name=window_every,json=windowEvery,proto3" json:"window_every,omitempty"`
 }
 
 func (m *Aggregate) Reset()         { *m = Aggregate{} }
@@ -1175,101 +1197,106 @@ func init() {
 func init() { proto.RegisterFile("storage_common.proto", fileDescriptor_715e4bf4cdf1f73d) }
 
 var fileDescriptor_715e4bf4cdf1f73d = []byte{
-	// 1498 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x57, 0xcb, 0x6f, 0xdb, 0xc8,
-	0x19, 0x17, 0xf5, 0x34, 0x3f, 0xc9, 0x32, 0x3d, 0x51, 0x5d, 0x87, 0x69, 0x24, 0x56, 0x28, 0x52,
-	0x17, 0x49, 0xe4, 0xd4, 0x49, 0xd1, 0x20, 0x6d, 0x0f, 0x96, 0x23, 0x5b, 0x6a, 0x6c, 0xc9, 0xa0,
-	0xe4, 0x00, 0xe9, 0x45, 0x18, 0xdb, 0x63, 0x86, 0x88, 0x44, 0xb2, 0x24, 0x15, 0x58, 0x40, 0x2f,
-	0xbd, 0x05, 0x3a, 0xb5, 0x97, 0x1e, 0x0a, 0x08, 0x58, 0x60, 0x8f, 0x7b, 0xdf, 0xbf, 0x21, 0x87,
-	0x05, 0x36, 0xc7, 0x3d, 0x09, 0xbb, 0x0a, 0xb0, 0xc0, 0xfe, 0x0b, 0x7b, 0x5a, 0xcc, 0x0c, 0x47,
-	0xa2, 0x6c, 0xc3, 0x96, 0xf6, 0xb4, 0xc8, 0x6d, 0xe6, 0x7b, 0xfc, 0xbe, 0x07, 0xbf, 0xc7, 0x10,
-	0x72, 0x9e, 0x6f, 0xbb, 0xd8, 0x20, 0xed, 0x13, 0xbb, 0xdb, 0xb5, 0xad, 0x92, 0xe3, 0xda, 0xbe,
-	0x8d, 0xee, 0x98, 0xd6, 0x59, 0xa7, 0x77, 0x7e, 0x8a, 0x7d, 0x5c, 0x72, 0x3a, 0xd8, 0x3f, 0xb3,
-	0xdd, 0x6e, 0x29, 0x90, 0x54, 0x73, 0x86, 0x6d, 0xd8, 0x4c, 0x6e, 0x93, 0x9e, 0xb8, 0x8a, 0x7a,
-	0xc7, 0xb0, 0x6d, 0xa3, 0x43, 0x36, 0xd9, 0xed, 0xb8, 0x77, 0xb6, 0x49, 0xba, 0x8e, 0xdf, 0x0f,
-	0x98, 0xb7, 0x2f, 0x32, 0xb1, 0x25, 0x58, 0x2b, 0x8e, 0x4b, 0x4e, 0xcd, 0x13, 0xec, 0x13, 0x4e,
-	0x28, 0xfe, 0x20, 0xc1, 0xaa, 0x4e, 0xf0, 0xe9, 0xae, 0xd9, 0xf1, 0x89, 0xab, 0x93, 0x7f, 0xf6,
-	0x88, 0xe7, 0xa3, 0x0a, 0xa4, 0x5d, 0x82, 0x4f, 0xdb, 0x9e, 0xdd, 0x73, 0x4f, 0xc8, 0xba, 0xa4,
-	0x49, 0x1b, 0xe9, 0xad, 0x5c, 0x89, 0xe3, 0x96, 0x04, 0x6e, 0x69, 0xdb, 0xea, 0x97, 0xb3, 0xe3,
-	0x51, 0x01, 0x28, 0x42, 0x93, 0xc9, 0xea, 0xe0, 0x4e, 0xce, 0x68, 0x0f, 0x12, 0x2e, 0xb6, 0x0c,
-	0xb2, 0x1e, 0x65, 0x00, 0xf7, 0x4b, 0xd7, 0x04, 0x5a, 0x6a, 0x99, 0x5d, 0xe2, 0xf9, 0xb8, 0xeb,
-	0xe8, 0x54, 0xa5, 0x1c, 0x7f, 0x3f, 0x2a, 0x44, 0x74, 0xae, 0x8f, 0x9e, 0x83, 0x3c, 0x71, 0x7c,
-	0x3d, 0xc6, 0xc0, 0xee, 0x5d, 0x0b, 0x76, 0x28, 0xa4, 0xf5, 0xa9, 0x62, 0xf1, 0xab, 0x04, 0x28,
-	0xd4, 0xd3, 0x3d, 0xd7, 0xee, 0x39, 0x9f, 0x74, 0xa8, 0xe8, 0x01, 0x80, 0x41, 0xa3, 0x6c, 0xbf,
-	0x21, 0x7d, 0x6f, 0x3d, 0xae, 0xc5, 0x36, 0xe4, 0xf2, 0xf2, 0x78, 0x54, 0x90, 0x59, 0xec, 0x2f,
-	0x48, 0xdf, 0xd3, 0x65, 0x43, 0x1c, 0x51, 0x0d, 0x12, 0xec, 0xb2, 0x9e, 0xd0, 0xa4, 0x8d, 0xec,
-	0xd6, 0xe3, 0x6b, 0xed, 0x5d, 0xcc, 0x60, 0x89, 0x5f, 0x38, 0x02, 0x75, 0x1f, 0x1b, 0x86, 0x4b,
-	0x0c, 0xea, 0x7e, 0x72, 0x0e, 0xf7, 0xb7, 0x85, 0xb4, 0x3e, 0x55, 0x44, 0x0f, 0x20, 0xf1, 0xda,
-	0xb4, 0x7c, 0x6f, 0x3d, 0xa5, 0x49, 0x1b, 0xa9, 0xf2, 0xda, 0x78, 0x54, 0x48, 0x54, 0x29, 0xe1,
-	0xc7, 0x51, 0x41, 0xa6, 0x87, 0xdd, 0x0e, 0x36, 0x3c, 0x9d, 0x0b, 0x15, 0xf7, 0x20, 0xc1, 0x7c,
-	0x40, 0x77, 0x01, 0xf6, 0xf4, 0xc6, 0xd1, 0x61, 0xbb, 0xde, 0xa8, 0x57, 0x94, 0x88, 0xba, 0x3c,
-	0x18, 0x6a, 0x3c, 0xe2, 0xba, 0x6d, 0x11, 0x74, 0x1b, 0x96, 0x38, 0xbb, 0xfc, 0x4a, 0x89, 0xaa,
-	0xe9, 0xc1, 0x50, 0x4b, 0x31, 0x66, 0xb9, 0xaf, 0xc6, 0xdf, 0x7d, 0x9e, 0x8f, 0x14, 0xbf, 0x90,
-	0x60, 0x8a, 0x8e, 0xee, 0x80, 0x5c, 0xad, 0xd5, 0x5b, 0x02, 0x2c, 0x33, 0x18, 0x6a, 0x4b, 0x94,
-	0xcb, 0xb0, 0x7e, 0x07, 0xd9, 0x80, 0xd9, 0x3e, 0x6c, 0xd4, 0xea, 0xad, 0xa6, 0x22, 0xa9, 0xca,
-	0x60, 0xa8, 0x65, 0xb8, 0xc4, 0xa1, 0x4d, 0x3d, 0x0b, 0x4b, 0x35, 0x2b, 0x7a, 0xad, 0xd2, 0x54,
-	0xa2, 0x61, 0xa9, 0x26, 0x71, 0x4d, 0xe2, 0xa1, 0x4d, 0xc8, 0x31, 0xa9, 0xe6, 0x4e, 0xb5, 0x72,
-	0xb0, 0xdd, 0xde, 0xde, 0xdf, 0x6f, 0xb7, 0x6a, 0x07, 0x15, 0x25, 0xae, 0xfe, 0x6a, 0x30, 0xd4,
-	0x56, 0xa9, 0x6c, 0xf3, 0xe4, 0x35, 0xe9, 0xe2, 0xed, 0x4e, 0x87, 0x96, 0x4e, 0xe0, 0xed, 0xd7,
-	0x12, 0xc8, 0x93, 0xec, 0xa1, 0x2a, 0xc4, 0xfd, 0xbe, 0xc3, 0x0b, 0x38, 0xbb, 0xf5, 0x64, 0xbe,
-	0x9c, 0x4f, 0x4f, 0xad, 0xbe, 0x43, 0x74, 0x86, 0x50, 0x3c, 0x87, 0xe5, 0x19, 0x32, 0x2a, 0x40,
-	0x3c, 0xc8, 0x01, 0xf3, 0x67, 0x86, 0xc9, 0x92, 0x71, 0x17, 0x62, 0xcd, 0xa3, 0x03, 0x45, 0x52,
-	0x73, 0x83, 0xa1, 0xa6, 0xcc, 0xf0, 0x9b, 0xbd, 0x2e, 0xfa, 0x2d, 0x24, 0x76, 0x1a, 0x47, 0xf5,
-	0x96, 0x12, 0x55, 0xd7, 0x06, 0x43, 0x0d, 0xcd, 0x08, 0xec, 0xd8, 0x3d, 0xcb, 0x0f, 0x22, 0x7a,
-	0x08, 0xb1, 0x16, 0x36, 0x90, 0x02, 0xb1, 0x37, 0xa4, 0xcf, 0x22, 0xc9, 0xe8, 0xf4, 0x88, 0x72,
-	0x90, 0x78, 0x8b, 0x3b, 0x3d, 0xde, 0x5d, 0x19, 0x9d, 0x5f, 0x8a, 0xff, 0xcd, 0x42, 0x86, 0x56,
-	0xa3, 0x4e, 0x3c, 0xc7, 0xb6, 0x3c, 0x82, 0x0e, 0x20, 0x79, 0xe6, 0xe2, 0x2e, 0xf1, 0xd6, 0x25,
-	0x2d, 0xb6, 0x91, 0xde, 0xda, 0xbc, 0xb1, 0x90, 0x85, 0x6a, 0x69, 0x97, 0xea, 0x05, 0x9d, 0x18,
-	0x80, 0xa8, 0xef, 0x92, 0x90, 0x60, 0x74, 0xb4, 0x2f, 0x1a, 0x24, 0xc5, 0x2a, 0xfa, 0xc9, 0xfc,
-	0xb8, 0xac, 0xc0, 0x18, 0x48, 0x35, 0x22, 0x7a, 0xa4, 0x01, 0x49, 0x8f, 0x7d, 0xf9, 0x60, 0xda,
-	0xfc, 0x69, 0x7e, 0x38, 0x5e, 0x31, 0x02, 0x2f, 0x80, 0x41, 0x0e, 0x64, 0xce, 0x3a, 0x36, 0xf6,
-	0xdb, 0x0e, 0x2b, 0xbb, 0x60, 0x06, 0x3d, 0x5b, 0x20, 0x7a, 0xaa, 0xcd, 0x6b, 0x96, 0x27, 0x62,
-	0x65, 0x3c, 0x2a, 0xa4, 0x43, 0xd4, 0x6a, 0x44, 0x4f, 0x9f, 0x4d, 0xaf, 0xe8, 0x1c, 0xb2, 0xa6,
-	0xe5, 0x13, 0x83, 0xb8, 0xc2, 0x26, 0x1f, 0x55, 0x7f, 0x9d, 0xdf, 0x66, 0x8d, 0xeb, 0x87, 0xad,
-	0xae, 0x8e, 0x47, 0x85, 0xe5, 0x19, 0x7a, 0x35, 0xa2, 0x2f, 0x9b, 0x61, 0x02, 0xfa, 0x17, 0xac,
-	0xf4, 0x2c, 0xcf, 0x34, 0x2c, 0x72, 0x2a, 0x4c, 0xc7, 0x99, 0xe9, 0xbf, 0xcd, 0x6f, 0xfa, 0x28,
-	0x00, 0x08, 0xdb, 0x46, 0xe3, 0x51, 0x21, 0x3b, 0xcb, 0xa8, 0x46, 0xf4, 0x6c, 0x6f, 0x86, 0x42,
-	0xe3, 0x3e, 0xb6, 0xed, 0x0e, 0xc1, 0x96, 0x30, 0x9e, 0x58, 0x34, 0xee, 0x32, 0xd7, 0xbf, 0x14,
-	0xf7, 0x0c, 0x9d, 0xc6, 0x7d, 0x1c, 0x26, 0x20, 0x1f, 0x96, 0x3d, 0xdf, 0x35, 0x2d, 0x43, 0x18,
-	0xe6, 0xc3, 0xf5, 0x2f, 0x0b, 0xd4, 0x0e, 0x53, 0x0f, 0xdb, 0x55, 0xc6, 0xa3, 0x42, 0x26, 0x4c,
-	0xae, 0x46, 0xf4, 0x8c, 0x17, 0xba, 0x97, 0x93, 0x10, 0xa7, 0xc8, 0xea, 0x39, 0xc0, 0xb4, 0x92,
-	0xd1, 0x3d, 0x58, 0xf2, 0xb1, 0xc1, 0x77, 0x0b, 0xed, 0xb4, 0x4c, 0x39, 0x3d, 0x1e, 0x15, 0x52,
-	0x2d, 0x6c, 0xb0, 0xcd, 0x92, 0xf2, 0xf9, 0x01, 0x95, 0x01, 0x39, 0xd8, 0xf5, 0x4d, 0xdf, 0xb4,
-	0x2d, 0x2a, 0xdd, 0x7e, 0x8b, 0x3b, 0xb4, 0x3a, 0xa9, 0x46, 0x6e, 0x3c, 0x2a, 0x28, 0x87, 0x82,
-	0xfb, 0x82, 0xf4, 0x5f, 0xe2, 0x8e, 0xa7, 0x2b, 0xce, 0x05, 0x8a, 0xfa, 0x7f, 0x09, 0xd2, 0xa1,
-	0xaa, 0x47, 0xcf, 0x20, 0xee, 0x63, 0x43, 0x74, 0xb8, 0x76, 0xfd, 0x9e, 0xc5, 0x46, 0xd0, 0xd2,
-	0x4c, 0x07, 0x35, 0x40, 0xa6, 0x82, 0x6d, 0x36, 0x28, 0xa3, 0x6c, 0x50, 0x6e, 0xcd, 0x9f, 0xbf,
-	0xe7, 0xd8, 0xc7, 0x6c, 0x4c, 0x2e, 0x9d, 0x06, 0x27, 0xf5, 0xef, 0xa0, 0x5c, 0x6c, 0x1d, 0x94,
-	0x07, 0xf0, 0xc5, 0x7e, 0xe7, 0x6e, 0x2a, 0x7a, 0x88, 0x82, 0xd6, 0x20, 0xc9, 0xc6, 0x17, 0x4f,
-	0x84, 0xa4, 0x07, 0x37, 0x75, 0x1f, 0xd0, 0xe5, 0x96, 0x58, 0x10, 0x2d, 0x36, 0x41, 0x3b, 0x80,
-	0x5b, 0x57, 0x54, 0xf9, 0x82, 0x70, 0xf1, 0xb0, 0x73, 0x97, 0xeb, 0x76, 0x41, 0xb4, 0xa5, 0x09,
-	0xda, 0x0b, 0x58, 0xbd, 0x54, 0x8c, 0x0b, 0x82, 0xc9, 0x02, 0xac, 0xd8, 0x04, 0x99, 0x01, 0x04,
-	0xab, 0x2a, 0x19, 0x2c, 0xda, 0x88, 0x7a, 0x6b, 0x30, 0xd4, 0x56, 0x26, 0xac, 0x60, 0xd7, 0x16,
-	0x20, 0x39, 0xd9, 0xd7, 0xb3, 0x02, 0xdc, 0x97, 0x60, 0x13, 0x7d, 0x29, 0xc1, 0x92, 0xf8, 0xde,
-	0xe8, 0x37, 0x90, 0xd8, 0xdd, 0x6f, 0x6c, 0xb7, 0x94, 0x88, 0xba, 0x3a, 0x18, 0x6a, 0xcb, 0x82,
-	0xc1, 0x3e, 0x3d, 0xd2, 0x20, 0x55, 0xab, 0xb7, 0x2a, 0x7b, 0x15, 0x5d, 0x40, 0x0a, 0x7e, 0xf0,
-	0x39, 0x51, 0x11, 0x96, 0x8e, 0xea, 0xcd, 0xda, 0x5e, 0xbd, 0xf2, 0x5c, 0x89, 0xf2, 0x1d, 0x29,
-	0x44, 0xc4, 0x37, 0xa2, 0x28, 0xe5, 0x46, 0x63, 0xbf, 0xb2, 0x5d, 0x57, 0x62, 0xb3, 0x28, 0x41,
-	0xde, 0x51, 0x1e, 0x92, 0xcd, 0x96, 0x5e, 0xab, 0xef, 0x29, 0x71, 0x15, 0x0d, 0x86, 0x5a, 0x56,
-	0x08, 0xf0, 0x54, 0x06, 0x8e, 0x7f, 0x26, 0x41, 0x6e, 0x07, 0x3b, 0xf8, 0xd8, 0xec, 0x98, 0xbe,
-	0x49, 0xbc, 0xc9, 0x6e, 0x6c, 0x40, 0xfc, 0x04, 0x3b, 0xa2, 0x6f, 0xae, 0x1f, 0x1b, 0x57, 0x01,
-	0x50, 0xa2, 0x57, 0xb1, 0x7c, 0xb7, 0xaf, 0x33, 0x20, 0xf5, 0xcf, 0x20, 0x4f, 0x48, 0xe1, 0x95,
-	0x2d, 0x5f, 0xb1, 0xb2, 0xe5, 0x60, 0x65, 0x3f, 0x8b, 0x3e, 0x95, 0x8a, 0x4f, 0x21, 0x3b, 0xfb,
-	0x00, 0xa6, 0xb2, 0x9e, 0x8f, 0x5d, 0x9f, 0xe9, 0xc7, 0x74, 0x7e, 0xa1, 0x98, 0xc4, 0x3a, 0x65,
-	0xfa, 0x31, 0x9d, 0x1e, 0x8b, 0xdf, 0x4b, 0x90, 0x15, 0x43, 0x66, 0xfa, 0x7c, 0xa7, 0xad, 0x3d,
-	0xf7, 0xf3, 0xbd, 0x85, 0x0d, 0x4f, 0x3c, 0xdf, 0xfd, 0xc9, 0xf9, 0x97, 0xf6, 0xa7, 0xf2, 0xef,
-	0x28, 0x28, 0x2d, 0x6c, 0xbc, 0x64, 0x15, 0xfe, 0x49, 0x87, 0x8a, 0x7e, 0x0d, 0xa9, 0x60, 0x97,
-	0xb0, 0x3d, 0x2e, 0xeb, 0x49, 0xbe, 0x3d, 0x8a, 0x25, 0xc8, 0xf1, 0xca, 0x16, 0x59, 0x08, 0x0a,
-	0x79, 0x3a, 0x07, 0xd8, 0xea, 0x11, 0x73, 0x60, 0xeb, 0x7f, 0x71, 0x48, 0x35, 0xb9, 0x25, 0x64,
-	0x02, 0x4c, 0x7f, 0x6a, 0x51, 0xe9, 0xc6, 0x19, 0x3f, 0xf3, 0xf7, 0xab, 0xfe, 0x61, 0xee, 0x9d,
-	0xf0, 0x48, 0x42, 0x06, 0xc8, 0x93, 0x3f, 0x22, 0xf4, 0x70, 0xa1, 0x3f, 0xa7, 0xc5, 0x0c, 0xbd,
-	0x01, 0xb1, 0x60, 0xd1, 0xfd, 0x9b, 0xb6, 0x5e, 0xa8, 0x43, 0xd4, 0x3f, 0x5e, 0x2b, 0x7c, 0x55,
-	0x8a, 0x1f, 0x49, 0xc8, 0x06, 0x79, 0x52, 0x7f, 0x37, 0x44, 0x75, 0xb1, 0x4e, 0x7f, 0x9e, 0xc1,
-	0x57, 0x90, 0x09, 0x4f, 0x1d, 0xb4, 0x76, 0xa9, 0xae, 0x2b, 0x5d, 0xc7, 0xef, 0xdf, 0x00, 0x7e,
-	0xd5, 0xe0, 0x2a, 0xff, 0xfe, 0xfd, 0x77, 0xf9, 0xc8, 0xfb, 0x71, 0x5e, 0xfa, 0x30, 0xce, 0x4b,
-	0xdf, 0x8e, 0xf3, 0xd2, 0x7f, 0x3e, 0xe6, 0x23, 0x1f, 0x3e, 0xe6, 0x23, 0xdf, 0x7c, 0xcc, 0x47,
-	0xfe, 0xc1, 0x5e, 0x04, 0xf4, 0x41, 0xe0, 0x1d, 0x27, 0x99, 0xad, 0xc7, 0x3f, 0x05, 0x00, 0x00,
-	0xff, 0xff, 0x7f, 0x0e, 0xbb, 0xbb, 0xa6, 0x11, 0x00, 0x00,
+	// 1583 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0xcd, 0x6f, 0xdb, 0xc8,
+	0x15, 0x17, 0xf5, 0x69, 0x3e, 0xc9, 0x32, 0x3d, 0x51, 0x5d, 0x87, 0x69, 0x24, 0x46, 0x28, 0x52,
+	0x17, 0x49, 0xe4, 0xd4, 0x49, 0xd1, 0x20, 0x6d, 0x0f, 0x92, 0x23, 0x5b, 0x6a, 0xf4, 0x61, 0x50,
+	0x72, 0xd0, 0xf4, 0x22, 0x8c, 0xad, 0x31, 0x43, 0x44, 0x22, 0x55, 0x92, 0x4a, 0x2c, 0xa0, 0x97,
+	0xde, 0x02, 0x9d, 0xda, 0x4b, 0x0e, 0x2d, 0x04, 0x14, 0xe8, 0xb1, 0x87, 0xde, 0xf6, 0x6f, 0xc8,
+	0x61, 0x0f, 0x39, 0xee, 0x49, 0xd8, 0x55, 0x80, 0xfd, 0x23, 0xf6, 0xb4, 0x98, 0x19, 0x52, 0xa2,
+	0x6c, 0xc1, 0x96, 0x72, 0x5a, 0xe4, 0x36, 0xf3, 0x3e, 0x7e, 0x6f, 0xde, 0x9b, 0xf7, 0x31, 0x24,
+	0xa4, 0x6c, 0xc7, 0xb4, 0xb0, 0x46, 0x5a, 0xa7, 0x66, 0xb7, 0x6b, 0x1a, 0xb9, 0x9e, 0x65, 0x3a,
+	0x26, 0xba, 0xa5, 0x1b, 0x67, 0x9d, 0xfe, 0x79, 0x1b, 0x3b, 0x38, 0xd7, 0xeb, 0x60, 0xe7, 0xcc,
+	0xb4, 0xba, 0x39, 0x57, 0x52, 0x4e, 0x69, 0xa6, 0x66, 0x32, 0xb9, 0x5d, 0xba, 0xe2, 0x2a, 0xf2,
+	0x2d, 0xcd, 0x34, 0xb5, 0x0e, 0xd9, 0x65, 0xbb, 0x93, 0xfe, 0xd9, 0x2e, 0xe9, 0xf6, 0x9c, 0x81,
+	0xcb, 0xbc, 0x79, 0x91, 0x89, 0x0d, 0x8f, 0xb5, 0xd1, 0xb3, 0x48, 0x5b, 0x3f, 0xc5, 0x0e, 0xe1,
+	0x84, 0xec, 0xff, 0x83, 0xb0, 0xa9, 0x12, 0xdc, 0x3e, 0xd0, 0x3b, 0x0e, 0xb1, 0x54, 0xf2, 0xd7,
+	0x3e, 0xb1, 0x1d, 0x54, 0x84, 0xb8, 0x45, 0x70, 0xbb, 0x65, 0x9b, 0x7d, 0xeb, 0x94, 0x6c, 0x0b,
+	0x8a, 0xb0, 0x13, 0xdf, 0x4b, 0xe5, 0x38, 0x6e, 0xce, 0xc3, 0xcd, 0xe5, 0x8d, 0x41, 0x21, 0x39,
+	0x19, 0x67, 0x80, 0x22, 0x34, 0x98, 0xac, 0x0a, 0xd6, 0x74, 0x8d, 0x0e, 0x21, 0x62, 0x61, 0x43,
+	0x23, 0xdb, 0x41, 0x06, 0x70, 0x2f, 0x77, 0x85, 0xa3, 0xb9, 0xa6, 0xde, 0x25, 0xb6, 0x83, 0xbb,
+	0x3d, 0x95, 0xaa, 0x14, 0xc2, 0x1f, 0xc6, 0x99, 0x80, 0xca, 0xf5, 0xd1, 0x33, 0x10, 0xa7, 0x07,
+	0xdf, 0x0e, 0x31, 0xb0, 0xbb, 0x57, 0x82, 0x1d, 0x79, 0xd2, 0xea, 0x4c, 0x91, 0xa2, 0x60, 0x4d,
+	0xb3, 0x88, 0x46, 0x51, 0xc2, 0x4b, 0xa0, 0xe4, 0x3d, 0x69, 0x75, 0xa6, 0x98, 0xfd, 0x3a, 0x02,
+	0x12, 0xf5, 0xf7, 0xd0, 0x32, 0xfb, 0xbd, 0x2f, 0x3b, 0x60, 0xf7, 0x01, 0x34, 0xea, 0x65, 0xeb,
+	0x35, 0x19, 0xd8, 0xdb, 0x61, 0x25, 0xb4, 0x23, 0x16, 0xd6, 0x27, 0xe3, 0x8c, 0xc8, 0x7c, 0x7f,
+	0x4e, 0x06, 0xb6, 0x2a, 0x6a, 0xde, 0x12, 0x95, 0x21, 0xc2, 0x36, 0xdb, 0x11, 0x45, 0xd8, 0x49,
+	0xee, 0x3d, 0xba, 0xd2, 0xde, 0xc5, 0x08, 0xe6, 0xf8, 0x86, 0x23, 0xcc, 0xdf, 0x54, 0xf4, 0x33,
+	0x6f, 0x0a, 0xdd, 0x87, 0xc8, 0x2b, 0xdd, 0x70, 0xec, 0xed, 0x98, 0x22, 0xec, 0xc4, 0x0a, 0x5b,
+	0x93, 0x71, 0x26, 0x52, 0xa2, 0x84, 0x1f, 0xc6, 0x19, 0x91, 0x2e, 0x0e, 0x3a, 0x58, 0xb3, 0x55,
+	0x2e, 0x94, 0x3d, 0x84, 0x08, 0x3b, 0x03, 0xba, 0x0d, 0x70, 0xa8, 0xd6, 0x8f, 0x8f, 0x5a, 0xb5,
+	0x7a, 0xad, 0x28, 0x05, 0xe4, 0xf5, 0xe1, 0x48, 0xe1, 0x1e, 0xd7, 0x4c, 0x83, 0xa0, 0x9b, 0xb0,
+	0xc6, 0xd9, 0x85, 0x97, 0x52, 0x50, 0x8e, 0x0f, 0x47, 0x4a, 0x8c, 0x31, 0x0b, 0x03, 0x39, 0xfc,
+	0xee, 0xbf, 0xe9, 0x40, 0xf6, 0x7f, 0x02, 0xcc, 0xd0, 0xd1, 0x2d, 0x10, 0x4b, 0xe5, 0x5a, 0xd3,
+	0x03, 0x4b, 0x0c, 0x47, 0xca, 0x1a, 0xe5, 0x32, 0xac, 0x5f, 0x42, 0xd2, 0x65, 0xb6, 0x8e, 0xea,
+	0xe5, 0x5a, 0xb3, 0x21, 0x09, 0xb2, 0x34, 0x1c, 0x29, 0x09, 0x2e, 0x71, 0x64, 0xd2, 0x93, 0xf9,
+	0xa5, 0x1a, 0x45, 0xb5, 0x5c, 0x6c, 0x48, 0x41, 0xbf, 0x54, 0x83, 0x58, 0x3a, 0xb1, 0xd1, 0x2e,
+	0xa4, 0x98, 0x54, 0x63, 0xbf, 0x54, 0xac, 0xe6, 0x5b, 0xf9, 0x4a, 0xa5, 0xd5, 0x2c, 0x57, 0x8b,
+	0x52, 0x58, 0xfe, 0xd9, 0x70, 0xa4, 0x6c, 0x52, 0xd9, 0xc6, 0xe9, 0x2b, 0xd2, 0xc5, 0xf9, 0x4e,
+	0x87, 0xa6, 0x8e, 0x7b, 0xda, 0xf7, 0x21, 0x10, 0xa7, 0xd1, 0x43, 0x25, 0x08, 0x3b, 0x83, 0x1e,
+	0x4f, 0xe0, 0xe4, 0xde, 0xe3, 0xe5, 0x62, 0x3e, 0x5b, 0x35, 0x07, 0x3d, 0xa2, 0x32, 0x04, 0x74,
+	0x07, 0x12, 0x6f, 0x75, 0xa3, 0x6d, 0xbe, 0x6d, 0x91, 0x37, 0xc4, 0x1a, 0xb0, 0x8c, 0x0e, 0xa9,
+	0x71, 0x4e, 0x2b, 0x52, 0x52, 0xf6, 0xdf, 0x41, 0x58, 0x9f, 0x53, 0x45, 0x19, 0x08, 0xbb, 0x71,
+	0x62, 0x67, 0x9e, 0x63, 0xb2, 0x80, 0xdd, 0x86, 0x50, 0xe3, 0xb8, 0x2a, 0x09, 0x72, 0x6a, 0x38,
+	0x52, 0xa4, 0x39, 0x7e, 0xa3, 0xdf, 0x45, 0x77, 0x20, 0xb2, 0x5f, 0x3f, 0xae, 0x35, 0xa5, 0xa0,
+	0xbc, 0x35, 0x1c, 0x29, 0x68, 0x4e, 0x60, 0xdf, 0xec, 0x1b, 0x0e, 0x45, 0xa8, 0x96, 0x6b, 0x52,
+	0x68, 0x01, 0x42, 0x55, 0x37, 0x18, 0x3b, 0xff, 0x67, 0x29, 0xbc, 0x88, 0x8d, 0xcf, 0xa9, 0x81,
+	0x83, 0xb2, 0xda, 0x68, 0x4a, 0x91, 0x05, 0x06, 0x0e, 0x74, 0xcb, 0x76, 0xa8, 0x0f, 0x95, 0x7c,
+	0xa3, 0x29, 0x45, 0x17, 0xf8, 0x50, 0xc1, 0x5c, 0xa0, 0x5a, 0xcc, 0xd7, 0xa4, 0xd8, 0x02, 0x81,
+	0x2a, 0xc1, 0x86, 0x7b, 0x31, 0x0f, 0x20, 0xd4, 0xc4, 0x1a, 0x92, 0x20, 0xf4, 0x9a, 0x0c, 0xd8,
+	0x85, 0x24, 0x54, 0xba, 0x44, 0x29, 0x88, 0xbc, 0xc1, 0x9d, 0x3e, 0x6f, 0x12, 0x09, 0x95, 0x6f,
+	0xb2, 0xff, 0x4c, 0x42, 0x82, 0x16, 0x95, 0x4a, 0xec, 0x9e, 0x69, 0xd8, 0x04, 0x55, 0x21, 0x7a,
+	0x66, 0xe1, 0x2e, 0xb1, 0xb7, 0x05, 0x25, 0xb4, 0x13, 0xdf, 0xdb, 0xbd, 0xb6, 0x1e, 0x3d, 0xd5,
+	0xdc, 0x01, 0xd5, 0x73, 0x1b, 0x8a, 0x0b, 0x22, 0xbf, 0x8b, 0x42, 0x84, 0xd1, 0x51, 0xc5, 0xab,
+	0xf3, 0x18, 0x2b, 0xcc, 0xc7, 0xcb, 0xe3, 0xb2, 0x3a, 0x61, 0x20, 0xa5, 0x80, 0x57, 0xea, 0x75,
+	0x88, 0xda, 0x2c, 0x81, 0xdd, 0xa6, 0xf9, 0xdb, 0xe5, 0xe1, 0x78, 0xe2, 0x7b, 0x78, 0x2e, 0x0c,
+	0xea, 0x41, 0xe2, 0xac, 0x63, 0x62, 0xa7, 0xd5, 0x63, 0xd5, 0xe3, 0xb6, 0xd2, 0xa7, 0x2b, 0x78,
+	0x4f, 0xb5, 0x79, 0xe9, 0xf1, 0x40, 0x6c, 0x4c, 0xc6, 0x99, 0xb8, 0x8f, 0x5a, 0x0a, 0xa8, 0xf1,
+	0xb3, 0xd9, 0x16, 0x9d, 0x43, 0x52, 0x37, 0x1c, 0xa2, 0x11, 0xcb, 0xb3, 0xc9, 0x3b, 0xee, 0x1f,
+	0x96, 0xb7, 0x59, 0xe6, 0xfa, 0x7e, 0xab, 0x9b, 0x93, 0x71, 0x66, 0x7d, 0x8e, 0x5e, 0x0a, 0xa8,
+	0xeb, 0xba, 0x9f, 0x80, 0xfe, 0x06, 0x1b, 0x7d, 0xc3, 0xd6, 0x35, 0x83, 0xb4, 0x3d, 0xd3, 0x7c,
+	0xae, 0xfd, 0x71, 0x79, 0xd3, 0xc7, 0x2e, 0x80, 0xdf, 0x36, 0x9a, 0x8c, 0x33, 0xc9, 0x79, 0x46,
+	0x29, 0xa0, 0x26, 0xfb, 0x73, 0x14, 0xea, 0xf7, 0x89, 0x69, 0x76, 0x08, 0x36, 0x3c, 0xe3, 0x91,
+	0x55, 0xfd, 0x2e, 0x70, 0xfd, 0x4b, 0x7e, 0xcf, 0xd1, 0xa9, 0xdf, 0x27, 0x7e, 0x02, 0x72, 0x60,
+	0xdd, 0x76, 0x2c, 0xdd, 0xd0, 0x3c, 0xc3, 0x7c, 0x46, 0xfc, 0x7e, 0x85, 0xdc, 0x61, 0xea, 0x7e,
+	0xbb, 0xd2, 0x64, 0x9c, 0x49, 0xf8, 0xc9, 0xa5, 0x80, 0x9a, 0xb0, 0x7d, 0xfb, 0x42, 0x14, 0xc2,
+	0x14, 0x59, 0x3e, 0x07, 0x98, 0x65, 0x32, 0xba, 0x0b, 0x6b, 0x0e, 0xd6, 0xf8, 0x88, 0xa4, 0x95,
+	0x96, 0x28, 0xc4, 0x27, 0xe3, 0x4c, 0xac, 0x89, 0x35, 0x36, 0x20, 0x63, 0x0e, 0x5f, 0xa0, 0x02,
+	0xa0, 0x1e, 0xb6, 0x1c, 0xdd, 0xd1, 0x4d, 0x83, 0x4a, 0xb7, 0xde, 0xe0, 0x0e, 0xcd, 0x4e, 0xaa,
+	0x91, 0x9a, 0x8c, 0x33, 0xd2, 0x91, 0xc7, 0x7d, 0x4e, 0x06, 0x2f, 0x70, 0xc7, 0x56, 0xa5, 0xde,
+	0x05, 0x8a, 0xfc, 0x2f, 0x01, 0xe2, 0xbe, 0xac, 0x47, 0x4f, 0x21, 0xec, 0x60, 0xcd, 0xab, 0x70,
+	0xe5, 0xea, 0xe7, 0x02, 0xd6, 0xdc, 0x92, 0x66, 0x3a, 0xa8, 0x0e, 0x22, 0x15, 0x6c, 0xb1, 0x7e,
+	0x1f, 0x64, 0xfd, 0x7e, 0x6f, 0xf9, 0xf8, 0x3d, 0xc3, 0x0e, 0x66, 0xdd, 0x7e, 0xad, 0xed, 0xae,
+	0xe4, 0x3f, 0x81, 0x74, 0xb1, 0x74, 0x50, 0x1a, 0xc0, 0xf1, 0x9e, 0x29, 0xfc, 0x98, 0x92, 0xea,
+	0xa3, 0xa0, 0x2d, 0x88, 0xb2, 0xf6, 0xc5, 0x03, 0x21, 0xa8, 0xee, 0x4e, 0xae, 0x00, 0xba, 0x5c,
+	0x12, 0x2b, 0xa2, 0x85, 0xa6, 0x68, 0x55, 0xb8, 0xb1, 0x20, 0xcb, 0x57, 0x84, 0x0b, 0xfb, 0x0f,
+	0x77, 0x39, 0x6f, 0x57, 0x44, 0x5b, 0x9b, 0xa2, 0x3d, 0x87, 0xcd, 0x4b, 0xc9, 0xb8, 0x22, 0x98,
+	0xe8, 0x81, 0x65, 0x1b, 0x20, 0x32, 0x00, 0x77, 0x9a, 0x46, 0xdd, 0xf7, 0x42, 0x40, 0xbe, 0x31,
+	0x1c, 0x29, 0x1b, 0x53, 0x96, 0xfb, 0x64, 0xc8, 0x40, 0x74, 0xfa, 0xec, 0x98, 0x17, 0xe0, 0x67,
+	0x71, 0x27, 0xd1, 0x57, 0x02, 0xac, 0x79, 0xf7, 0x8d, 0x7e, 0x01, 0x91, 0x83, 0x4a, 0x3d, 0xdf,
+	0x94, 0x02, 0xf2, 0xe6, 0x70, 0xa4, 0xac, 0x7b, 0x0c, 0x76, 0xf5, 0x48, 0x81, 0x58, 0xb9, 0xd6,
+	0x2c, 0x1e, 0x16, 0x55, 0x0f, 0xd2, 0xe3, 0xbb, 0xd7, 0x89, 0xb2, 0xb0, 0x76, 0x5c, 0x6b, 0x94,
+	0x0f, 0x6b, 0xc5, 0x67, 0x52, 0x90, 0x4f, 0x59, 0x4f, 0xc4, 0xbb, 0x23, 0x8a, 0x52, 0xa8, 0xd7,
+	0x2b, 0x74, 0x48, 0x86, 0xe6, 0x51, 0xdc, 0xb8, 0xa3, 0x34, 0x44, 0x1b, 0x4d, 0xb5, 0x5c, 0x3b,
+	0x94, 0xc2, 0x32, 0x1a, 0x8e, 0x94, 0xa4, 0x27, 0xc0, 0x43, 0xe9, 0x1e, 0xfc, 0x3f, 0x02, 0xa4,
+	0xf6, 0x71, 0x0f, 0x9f, 0xe8, 0x1d, 0xdd, 0xd1, 0x89, 0x3d, 0x9d, 0x8d, 0x75, 0x08, 0x9f, 0xe2,
+	0x9e, 0x57, 0x37, 0x57, 0xb7, 0x8d, 0x45, 0x00, 0x94, 0x68, 0x17, 0x0d, 0xc7, 0x1a, 0xa8, 0x0c,
+	0x48, 0xfe, 0x1d, 0x88, 0x53, 0x92, 0x7f, 0x64, 0x8b, 0x0b, 0x46, 0xb6, 0xe8, 0x8e, 0xec, 0xa7,
+	0xc1, 0x27, 0x42, 0xf6, 0x09, 0x24, 0xe7, 0xdf, 0xf1, 0x54, 0xd6, 0x76, 0xb0, 0xe5, 0x30, 0xfd,
+	0x90, 0xca, 0x37, 0x14, 0x93, 0x18, 0x6d, 0xf7, 0x15, 0x45, 0x97, 0xd9, 0xef, 0x05, 0x48, 0x7a,
+	0x4d, 0x66, 0xf6, 0x15, 0x42, 0x4b, 0x7b, 0xe9, 0xaf, 0x90, 0x26, 0xd6, 0x6c, 0xef, 0x2b, 0xc4,
+	0x99, 0xae, 0x7f, 0x62, 0x5f, 0x21, 0xd9, 0xbf, 0x07, 0x41, 0x6a, 0x62, 0xed, 0x05, 0xcb, 0xf0,
+	0x2f, 0xda, 0x55, 0xf4, 0x73, 0x88, 0xb9, 0xb3, 0x84, 0xcd, 0x71, 0x51, 0x8d, 0xf2, 0xe9, 0x91,
+	0xcd, 0x41, 0x8a, 0x67, 0xb6, 0x17, 0x05, 0x37, 0x91, 0x67, 0x7d, 0x80, 0x8d, 0x1e, 0xaf, 0x0f,
+	0xec, 0xbd, 0x0f, 0x43, 0xac, 0xc1, 0x2d, 0x21, 0x1d, 0x60, 0xf6, 0x85, 0x8f, 0x72, 0xd7, 0xf6,
+	0xf8, 0xb9, 0x5f, 0x01, 0xf2, 0xaf, 0x97, 0x9e, 0x09, 0x0f, 0x05, 0xa4, 0x81, 0x38, 0xfd, 0xb0,
+	0x43, 0x0f, 0x56, 0xfa, 0x00, 0x5c, 0xcd, 0xd0, 0x6b, 0xf0, 0x06, 0x2c, 0xba, 0x77, 0xdd, 0xd4,
+	0xf3, 0x55, 0x88, 0xfc, 0x9b, 0x2b, 0x85, 0x17, 0x85, 0xf8, 0xa1, 0x80, 0x4c, 0x10, 0xa7, 0xf9,
+	0x77, 0x8d, 0x57, 0x17, 0xf3, 0xf4, 0xf3, 0x0c, 0xbe, 0x84, 0x84, 0xbf, 0xeb, 0xa0, 0xad, 0x4b,
+	0x79, 0x5d, 0xa4, 0xbf, 0x7b, 0xae, 0x01, 0x5f, 0xd4, 0xb8, 0x0a, 0xbf, 0xfa, 0xf0, 0x5d, 0x3a,
+	0xf0, 0x61, 0x92, 0x16, 0x3e, 0x4e, 0xd2, 0xc2, 0xb7, 0x93, 0xb4, 0xf0, 0x8f, 0x4f, 0xe9, 0xc0,
+	0xc7, 0x4f, 0xe9, 0xc0, 0x37, 0x9f, 0xd2, 0x81, 0xbf, 0xb0, 0x17, 0x01, 0x7d, 0x10, 0xd8, 0x27,
+	0x51, 0x66, 0xeb, 0xd1, 0x8f, 0x03, 0x00, 0xbc, 0xef, 0xed, 0x22, 0xb3, 0x12, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1638,6 +1665,16 @@ func (m *ReadFilterRequest) MarshalTo(dAtA []byte) (int, error) {
 		}
 		i += n3
 	}
+	if m.Aggregate != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Aggregate.Size()))
+		n4, err := m.Aggregate.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
 	return i, nil
 }
 
@@ -1660,29 +1697,29 @@ func (m *ReadGroupRequest) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.ReadSource.Size()))
-		n4, err := m.ReadSource.MarshalTo(dAtA[i:])
+		n5, err := m.ReadSource.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n4
+		i += n5
 	}
 	dAtA[i] = 0x12
 	i++
 	i = encodeVarintStorageCommon(dAtA, i, uint64(m.Range.Size()))
-	n5, err := m.Range.MarshalTo(dAtA[i:])
+	n6, err := m.Range.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n5
+	i += n6
 	if m.Predicate != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Predicate.Size()))
-		n6, err := m.Predicate.MarshalTo(dAtA[i:])
+		n7, err := m.Predicate.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n6
+		i += n7
 	}
 	if len(m.GroupKeys) > 0 {
 		for _, s := range m.GroupKeys {
@@ -1708,11 +1745,11 @@ func (m *ReadGroupRequest) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x32
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Aggregate.Size()))
-		n7, err := m.Aggregate.MarshalTo(dAtA[i:])
+		n8, err := m.Aggregate.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n7
+		i += n8
 	}
 	if m.Hints != 0 {
 		dAtA[i] = 0x3d
@@ -1743,6 +1780,11 @@ func (m *Aggregate) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Type))
 	}
+	if m.WindowEvery != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.WindowEvery))
+	}
 	return i, nil
 }
 
@@ -1822,11 +1864,11 @@ func (m *ReadResponse_Frame) MarshalTo(dAtA []byte) (int, error) {
 	var l int
 	_ = l
 	if m.Data != nil {
-		nn8, err := m.Data.MarshalTo(dAtA[i:])
+		nn9, err := m.Data.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += nn8
+		i += nn9
 	}
 	return i, nil
 }
@@ -1837,11 +1879,11 @@ func (m *ReadResponse_Frame_Series) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Series.Size()))
-		n9, err := m.Series.MarshalTo(dAtA[i:])
+		n10, err := m.Series.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n9
+		i += n10
 	}
 	return i, nil
 }
@@ -1851,11 +1893,11 @@ func (m *ReadResponse_Frame_FloatPoints) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x12
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.FloatPoints.Size()))
-		n10, err := m.FloatPoints.MarshalTo(dAtA[i:])
+		n11, err := m.FloatPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n10
+		i += n11
 	}
 	return i, nil
 }
@@ -1865,11 +1907,11 @@ func (m *ReadResponse_Frame_IntegerPoints) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.IntegerPoints.Size()))
-		n11, err := m.IntegerPoints.MarshalTo(dAtA[i:])
+		n12, err := m.IntegerPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n11
+		i += n12
 	}
 	return i, nil
 }
@@ -1879,11 +1921,11 @@ func (m *ReadResponse_Frame_UnsignedPoints) MarshalTo(dAtA []byte) (int, error)
 		dAtA[i] = 0x22
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.UnsignedPoints.Size()))
-		n12, err := m.UnsignedPoints.MarshalTo(dAtA[i:])
+		n13, err := m.UnsignedPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n12
+		i += n13
 	}
 	return i, nil
 }
@@ -1893,11 +1935,11 @@ func (m *ReadResponse_Frame_BooleanPoints) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x2a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.BooleanPoints.Size()))
-		n13, err := m.BooleanPoints.MarshalTo(dAtA[i:])
+		n14, err := m.BooleanPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n13
+		i += n14
 	}
 	return i, nil
 }
@@ -1907,11 +1949,11 @@ func (m *ReadResponse_Frame_StringPoints) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x32
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.StringPoints.Size()))
-		n14, err := m.StringPoints.MarshalTo(dAtA[i:])
+		n15, err := m.StringPoints.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n14
+		i += n15
 	}
 	return i, nil
 }
@@ -1921,11 +1963,11 @@ func (m *ReadResponse_Frame_Group) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0x3a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Group.Size()))
-		n15, err := m.Group.MarshalTo(dAtA[i:])
+		n16, err := m.Group.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n15
+		i += n16
 	}
 	return i, nil
 }
@@ -2027,8 +2069,8 @@ func (m *ReadResponse_FloatPointsFrame) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(len(m.Values)*8))
 		for _, num := range m.Values {
-			f16 := math.Float64bits(float64(num))
-			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f16))
+			f17 := math.Float64bits(float64(num))
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f17))
 			i += 8
 		}
 	}
@@ -2060,22 +2102,22 @@ func (m *ReadResponse_IntegerPointsFrame) MarshalTo(dAtA []byte) (int, error) {
 		}
 	}
 	if len(m.Values) > 0 {
-		dAtA18 := make([]byte, len(m.Values)*10)
-		var j17 int
+		dAtA19 := make([]byte, len(m.Values)*10)
+		var j18 int
 		for _, num1 := range m.Values {
 			num := uint64(num1)
 			for num >= 1<<7 {
-				dAtA18[j17] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA19[j18] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j17++
+				j18++
 			}
-			dAtA18[j17] = uint8(num)
-			j17++
+			dAtA19[j18] = uint8(num)
+			j18++
 		}
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintStorageCommon(dAtA, i, uint64(j17))
-		i += copy(dAtA[i:], dAtA18[:j17])
+		i = encodeVarintStorageCommon(dAtA, i, uint64(j18))
+		i += copy(dAtA[i:], dAtA19[:j18])
 	}
 	return i, nil
 }
@@ -2105,21 +2147,21 @@ func (m *ReadResponse_UnsignedPointsFrame) MarshalTo(dAtA []byte) (int, error) {
 		}
 	}
 	if len(m.Values) > 0 {
-		dAtA20 := make([]byte, len(m.Values)*10)
-		var j19 int
+		dAtA21 := make([]byte, len(m.Values)*10)
+		var j20 int
 		for _, num := range m.Values {
 			for num >= 1<<7 {
-				dAtA20[j19] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA21[j20] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j19++
+				j20++
 			}
-			dAtA20[j19] = uint8(num)
-			j19++
+			dAtA21[j20] = uint8(num)
+			j20++
 		}
 		dAtA[i] = 0x12
 		i++
-		i = encodeVarintStorageCommon(dAtA, i, uint64(j19))
-		i += copy(dAtA[i:], dAtA20[:j19])
+		i = encodeVarintStorageCommon(dAtA, i, uint64(j20))
+		i += copy(dAtA[i:], dAtA21[:j20])
 	}
 	return i, nil
 }
@@ -2288,29 +2330,29 @@ func (m *TagKeysRequest) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.TagsSource.Size()))
-		n21, err := m.TagsSource.MarshalTo(dAtA[i:])
+		n22, err := m.TagsSource.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n21
+		i += n22
 	}
 	dAtA[i] = 0x12
 	i++
 	i = encodeVarintStorageCommon(dAtA, i, uint64(m.Range.Size()))
-	n22, err := m.Range.MarshalTo(dAtA[i:])
+	n23, err := m.Range.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n22
+	i += n23
 	if m.Predicate != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Predicate.Size()))
-		n23, err := m.Predicate.MarshalTo(dAtA[i:])
+		n24, err := m.Predicate.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n23
+		i += n24
 	}
 	return i, nil
 }
@@ -2334,29 +2376,29 @@ func (m *TagValuesRequest) MarshalTo(dAtA []byte) (int, error) {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.TagsSource.Size()))
-		n24, err := m.TagsSource.MarshalTo(dAtA[i:])
+		n25, err := m.TagsSource.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n24
+		i += n25
 	}
 	dAtA[i] = 0x12
 	i++
 	i = encodeVarintStorageCommon(dAtA, i, uint64(m.Range.Size()))
-	n25, err := m.Range.MarshalTo(dAtA[i:])
+	n26, err := m.Range.MarshalTo(dAtA[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n25
+	i += n26
 	if m.Predicate != nil {
 		dAtA[i] = 0x1a
 		i++
 		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Predicate.Size()))
-		n26, err := m.Predicate.MarshalTo(dAtA[i:])
+		n27, err := m.Predicate.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n26
+		i += n27
 	}
 	if len(m.TagKey) > 0 {
 		dAtA[i] = 0x22
@@ -2418,6 +2460,10 @@ func (m *ReadFilterRequest) Size() (n int) {
 		l = m.Predicate.Size()
 		n += 1 + l + sovStorageCommon(uint64(l))
 	}
+	if m.Aggregate != nil {
+		l = m.Aggregate.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
 	return n
 }
 
@@ -2465,6 +2511,9 @@ func (m *Aggregate) Size() (n int) {
 	if m.Type != 0 {
 		n += 1 + sovStorageCommon(uint64(m.Type))
 	}
+	if m.WindowEvery != 0 {
+		n += 1 + sovStorageCommon(uint64(m.WindowEvery))
+	}
 	return n
 }
 
@@ -2957,6 +3006,42 @@ func (m *ReadFilterRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Aggregate == nil {
+				m.Aggregate = &Aggregate{}
+			}
+			if err := m.Aggregate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorageCommon(dAtA[iNdEx:])
@@ -3284,6 +3369,25 @@ func (m *Aggregate) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowEvery", wireType)
+			}
+			m.WindowEvery = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WindowEvery |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorageCommon(dAtA[iNdEx:])
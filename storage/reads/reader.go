@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gogo/protobuf/types"
 	"github.com/influxdata/flux"
@@ -51,6 +52,15 @@ func (r *storeReader) ReadGroup(ctx context.Context, spec influxdb.ReadGroupSpec
 	}, nil
 }
 
+func (r *storeReader) ReadWindowAggregate(ctx context.Context, spec influxdb.ReadWindowAggregateSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	return &windowAggregateIterator{
+		ctx:   ctx,
+		s:     r.s,
+		spec:  spec,
+		alloc: alloc,
+	}, nil
+}
+
 func (r *storeReader) ReadTagKeys(ctx context.Context, spec influxdb.ReadTagKeysSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	var predicate *datatypes.Predicate
 	if spec.Predicate != nil {
@@ -363,6 +373,238 @@ READ:
 	return rs.Err()
 }
 
+type windowAggregateIterator struct {
+	ctx   context.Context
+	s     Store
+	spec  influxdb.ReadWindowAggregateSpec
+	stats cursors.CursorStats
+	alloc *memory.Allocator
+}
+
+func (wai *windowAggregateIterator) Statistics() cursors.CursorStats { return wai.stats }
+
+func (wai *windowAggregateIterator) Do(f func(flux.Table) error) error {
+	src := wai.s.GetSource(
+		uint64(wai.spec.OrganizationID),
+		uint64(wai.spec.BucketID),
+	)
+
+	// Setup read request
+	any, err := types.MarshalAny(src)
+	if err != nil {
+		return err
+	}
+
+	var predicate *datatypes.Predicate
+	if wai.spec.Predicate != nil {
+		p, err := toStoragePredicate(wai.spec.Predicate)
+		if err != nil {
+			return err
+		}
+		predicate = p
+	}
+
+	agg, err := determineAggregateMethod(wai.spec.AggregateMethod)
+	if err != nil {
+		return err
+	} else if agg == datatypes.AggregateTypeNone {
+		return fmt.Errorf("missing aggregate method")
+	}
+	window, err := execute.NewWindow(
+		values.ConvertDuration(time.Duration(wai.spec.WindowEvery)),
+		values.ConvertDuration(time.Duration(wai.spec.WindowEvery)),
+		values.ConvertDuration(0),
+	)
+	if err != nil {
+		return err
+	}
+
+	var req datatypes.ReadFilterRequest
+	req.ReadSource = any
+	req.Predicate = predicate
+	req.Range.Start = int64(wai.spec.Bounds.Start)
+	req.Range.End = int64(wai.spec.Bounds.Stop)
+	req.Aggregate = &datatypes.Aggregate{
+		Type:        agg,
+		WindowEvery: wai.spec.WindowEvery,
+	}
+
+	rs, err := wai.s.ReadFilter(wai.ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	if rs == nil {
+		return nil
+	}
+	return wai.handleRead(f, rs, window, agg)
+}
+
+func (wai *windowAggregateIterator) handleRead(f func(flux.Table) error, rs ResultSet, window execute.Window, agg datatypes.Aggregate_AggregateType) error {
+	defer rs.Close()
+
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			// no data for series key + field combination
+			continue
+		}
+
+		err := wai.handleSeries(f, rs.Tags(), cur, window, agg)
+		stats := cur.Stats()
+		wai.stats.ScannedValues += stats.ScannedValues
+		wai.stats.ScannedBytes += stats.ScannedBytes
+		cur.Close()
+		if err != nil {
+			return err
+		}
+
+		if wai.ctx.Err() != nil {
+			break
+		}
+	}
+	return rs.Err()
+}
+
+// handleSeries emits a table for each window of the series holding an
+// aggregated point, and for the empty windows when requested. The aggregates
+// produce a row without time in all windows, the selectors produce the row
+// of the selected point as the Flux functions do.
+func (wai *windowAggregateIterator) handleSeries(f func(flux.Table) error, tags models.Tags, cur cursors.Cursor, window execute.Window, agg datatypes.Aggregate_AggregateType) error {
+	typ, next := windowArrayReader(cur)
+
+	var (
+		selector bool
+		cols     []flux.ColMeta
+		tagsIdx  int
+	)
+	switch agg {
+	case datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast,
+		datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+		selector = true
+		cols, _ = determineTableColsForSeries(tags, typ)
+		tagsIdx = valueColIdx + 1
+	default:
+		cols = determineTableColsForWindowAggregate(tags, typ)
+		tagsIdx = stopColIdx + 1
+	}
+
+	bounds := wai.spec.Bounds
+	emit := func(bnds execute.Bounds, t execute.Time, v values.Value) error {
+		bnds = bounds.Intersect(bnds)
+		builder := execute.NewColListTableBuilder(defaultGroupKeyForSeries(tags, bnds), wai.alloc)
+		for _, c := range cols {
+			if _, err := builder.AddCol(c); err != nil {
+				return err
+			}
+		}
+		defer builder.ClearData()
+
+		if v != nil || !selector {
+			for j, c := range cols {
+				var err error
+				switch c.Label {
+				case execute.DefaultStartColLabel:
+					err = builder.AppendTime(j, bnds.Start)
+				case execute.DefaultStopColLabel:
+					err = builder.AppendTime(j, bnds.Stop)
+				case execute.DefaultTimeColLabel:
+					err = builder.AppendTime(j, t)
+				case execute.DefaultValueColLabel:
+					if v == nil {
+						err = builder.AppendNil(j)
+					} else {
+						err = builder.AppendValue(j, v)
+					}
+				default:
+					err = builder.AppendString(j, string(tags[j-tagsIdx].Value))
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		tbl, err := builder.Table()
+		if err != nil {
+			return err
+		}
+		return f(tbl)
+	}
+
+	// The empty windows have no point, and no value but a zero count.
+	var empty values.Value
+	if agg == datatypes.AggregateTypeCount {
+		empty = values.NewInt(0)
+	}
+
+	// bi is the first window not emitted yet.
+	bi := window.GetEarliestBounds(bounds.Start)
+	for {
+		ts, value := next()
+		if len(ts) == 0 {
+			break
+		}
+		for i := range ts {
+			t := execute.Time(ts[i])
+			bnds := window.GetEarliestBounds(t)
+			if wai.spec.CreateEmpty {
+				for ; bi.Start < bnds.Start; bi.Start, bi.Stop = bi.Start.Add(window.Every), bi.Stop.Add(window.Every) {
+					if err := emit(bi, 0, empty); err != nil {
+						return err
+					}
+				}
+				bi.Start, bi.Stop = bnds.Start.Add(window.Every), bnds.Stop.Add(window.Every)
+			}
+			if err := emit(bnds, t, value(i)); err != nil {
+				return err
+			}
+		}
+	}
+	if wai.spec.CreateEmpty {
+		for ; bi.Start < bounds.Stop; bi.Start, bi.Stop = bi.Start.Add(window.Every), bi.Stop.Add(window.Every) {
+			if err := emit(bi, 0, empty); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// windowArrayReader returns the type of the points of cur and a function
+// reading the next array of points.
+func windowArrayReader(cur cursors.Cursor) (flux.ColType, func() ([]int64, func(int) values.Value)) {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return flux.TFloat, func() ([]int64, func(int) values.Value) {
+			a := cur.Next()
+			return a.Timestamps, func(i int) values.Value { return values.NewFloat(a.Values[i]) }
+		}
+	case cursors.IntegerArrayCursor:
+		return flux.TInt, func() ([]int64, func(int) values.Value) {
+			a := cur.Next()
+			return a.Timestamps, func(i int) values.Value { return values.NewInt(a.Values[i]) }
+		}
+	case cursors.UnsignedArrayCursor:
+		return flux.TUInt, func() ([]int64, func(int) values.Value) {
+			a := cur.Next()
+			return a.Timestamps, func(i int) values.Value { return values.NewUInt(a.Values[i]) }
+		}
+	case cursors.BooleanArrayCursor:
+		return flux.TBool, func() ([]int64, func(int) values.Value) {
+			a := cur.Next()
+			return a.Timestamps, func(i int) values.Value { return values.NewBool(a.Values[i]) }
+		}
+	case cursors.StringArrayCursor:
+		return flux.TString, func() ([]int64, func(int) values.Value) {
+			a := cur.Next()
+			return a.Timestamps, func(i int) values.Value { return values.NewString(a.Values[i]) }
+		}
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
 func determineAggregateMethod(agg string) (datatypes.Aggregate_AggregateType, error) {
 	if agg == "" {
 		return datatypes.AggregateTypeNone, nil
@@ -420,6 +662,32 @@ func determineTableColsForSeries(tags models.Tags, typ flux.ColType) ([]flux.Col
 	return cols, defs
 }
 
+// determineTableColsForWindowAggregate returns the columns of the tables
+// of an aggregate, the group key columns followed by the value.
+func determineTableColsForWindowAggregate(tags models.Tags, typ flux.ColType) []flux.ColMeta {
+	cols := make([]flux.ColMeta, 0, 3+len(tags))
+	cols = append(cols,
+		flux.ColMeta{
+			Label: execute.DefaultStartColLabel,
+			Type:  flux.TTime,
+		},
+		flux.ColMeta{
+			Label: execute.DefaultStopColLabel,
+			Type:  flux.TTime,
+		},
+	)
+	for _, tag := range tags {
+		cols = append(cols, flux.ColMeta{
+			Label: string(tag.Key),
+			Type:  flux.TString,
+		})
+	}
+	return append(cols, flux.ColMeta{
+		Label: execute.DefaultValueColLabel,
+		Type:  typ,
+	})
+}
+
 func defaultGroupKeyForSeries(tags models.Tags, bnds execute.Bounds) flux.GroupKey {
 	cols := make([]flux.ColMeta, 2, len(tags)+2)
 	vs := make([]values.Value, 2, len(tags)+2)
@@ -25,6 +25,7 @@ type resultSet struct {
 func NewFilteredResultSet(ctx context.Context, req *datatypes.ReadFilterRequest, cur SeriesCursor) ResultSet {
 	return &resultSet{
 		ctx: ctx,
+		agg: req.Aggregate,
 		cur: cur,
 		mb:  newMultiShardArrayCursors(ctx, req.Range.Start, req.Range.End, true, math.MaxInt64),
 	}
@@ -0,0 +1,92 @@
+package reads
+
+import (
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+)
+
+// SeriesScopePredicate returns the predicate restricting p to the series in
+// any of the scopes; p is returned unchanged if there are no scopes.
+func SeriesScopePredicate(p *datatypes.Predicate, scopes []influxdb.SeriesScope) *datatypes.Predicate {
+	if len(scopes) == 0 {
+		return p
+	}
+
+	var any []*datatypes.Node
+	for _, s := range scopes {
+		any = append(any, seriesScopeNode(s))
+	}
+	root := logicalNode(datatypes.LogicalOr, any)
+	if p.GetRoot() != nil {
+		root = logicalNode(datatypes.LogicalAnd, []*datatypes.Node{parenNode(p.Root), parenNode(root)})
+	}
+	return &datatypes.Predicate{Root: root}
+}
+
+func seriesScopeNode(s influxdb.SeriesScope) *datatypes.Node {
+	var all []*datatypes.Node
+	if len(s.Measurements) > 0 {
+		var ms []*datatypes.Node
+		for _, m := range s.Measurements {
+			ms = append(ms, tagComparisonNode(models.MeasurementTagKey, datatypes.ComparisonEqual, m))
+		}
+		all = append(all, parenNode(logicalNode(datatypes.LogicalOr, ms)))
+	}
+	for _, tr := range s.Tags {
+		var cmp datatypes.Node_Comparison
+		switch tr.Operator {
+		case influxdb.NotEqual:
+			cmp = datatypes.ComparisonNotEqual
+		case influxdb.RegexEqual:
+			cmp = datatypes.ComparisonRegex
+		case influxdb.NotRegexEqual:
+			cmp = datatypes.ComparisonNotRegex
+		default:
+			cmp = datatypes.ComparisonEqual
+		}
+		all = append(all, tagComparisonNode(tr.Key, cmp, tr.Value))
+	}
+	return parenNode(logicalNode(datatypes.LogicalAnd, all))
+}
+
+// logicalNode returns the logical expression of the nodes, or the node if
+// there is only one.
+func logicalNode(op datatypes.Node_Logical, nodes []*datatypes.Node) *datatypes.Node {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeLogicalExpression,
+		Value:    &datatypes.Node_Logical_{Logical: op},
+		Children: nodes,
+	}
+}
+
+func parenNode(n *datatypes.Node) *datatypes.Node {
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeParenExpression,
+		Children: []*datatypes.Node{n},
+	}
+}
+
+func tagComparisonNode(key string, cmp datatypes.Node_Comparison, value string) *datatypes.Node {
+	literal := &datatypes.Node{
+		NodeType: datatypes.NodeTypeLiteral,
+		Value:    &datatypes.Node_StringValue{StringValue: value},
+	}
+	if cmp == datatypes.ComparisonRegex || cmp == datatypes.ComparisonNotRegex {
+		literal.Value = &datatypes.Node_RegexValue{RegexValue: value}
+	}
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: cmp},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			literal,
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package reads_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+)
+
+func TestSeriesScopePredicate(t *testing.T) {
+	hostPredicate := &datatypes.Predicate{
+		Root: &datatypes.Node{
+			NodeType: datatypes.NodeTypeComparisonExpression,
+			Value:    &datatypes.Node_Comparison_{Comparison: datatypes.ComparisonEqual},
+			Children: []*datatypes.Node{
+				{NodeType: datatypes.NodeTypeTagRef, Value: &datatypes.Node_TagRefValue{TagRefValue: "host"}},
+				{NodeType: datatypes.NodeTypeLiteral, Value: &datatypes.Node_StringValue{StringValue: "web1"}},
+			},
+		},
+	}
+	webScope := influxdb.SeriesScope{
+		Measurements: []string{"cpu", "mem"},
+		Tags: []influxdb.TagRule{
+			{Tag: influxdb.Tag{Key: "host", Value: "^web"}, Operator: influxdb.RegexEqual},
+			{Tag: influxdb.Tag{Key: "env", Value: "dev"}, Operator: influxdb.NotEqual},
+		},
+	}
+
+	cases := []struct {
+		n      string
+		p      *datatypes.Predicate
+		scopes []influxdb.SeriesScope
+		e      string
+	}{
+		{
+			n: "no scopes",
+			p: hostPredicate,
+			e: `'host' = "web1"`,
+		},
+		{
+			n:      "no predicate",
+			scopes: []influxdb.SeriesScope{webScope},
+			e:      `( ( '\x00' = "cpu" OR '\x00' = "mem" ) AND 'host' =~ /^web/ AND 'env' != "dev" )`,
+		},
+		{
+			n: "predicate and scopes",
+			p: hostPredicate,
+			scopes: []influxdb.SeriesScope{
+				webScope,
+				{Measurements: []string{"disk"}},
+			},
+			e: `( 'host' = "web1" ) AND ( ( ( '\x00' = "cpu" OR '\x00' = "mem" ) AND 'host' =~ /^web/ AND 'env' != "dev" ) OR ( ( '\x00' = "disk" ) ) )`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			// the measurement is compared to its tag key, which is not printable.
+			got := strings.Replace(reads.PredicateToExprString(reads.SeriesScopePredicate(tc.p, tc.scopes)), models.MeasurementTagKey, `\x00`, -1)
+			if got != tc.e {
+				t.Fatal("got:", got, "wanted:", tc.e)
+			}
+		})
+	}
+}
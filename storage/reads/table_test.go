@@ -2,6 +2,7 @@ package reads_test
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"math"
 	"math/rand"
@@ -10,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
@@ -22,6 +24,7 @@ import (
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/influxdata/influxdb/storage/readservice"
+	"github.com/influxdata/influxdb/tsdb"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -134,6 +137,131 @@ func BenchmarkReadFilter(b *testing.B) {
 	})
 }
 
+func TestReadWindowAggregate(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "storage-reads-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(rootDir) }()
+
+	engine := storage.NewEngine(filepath.Join(rootDir, "engine"), storage.NewConfig())
+	engine.WithLogger(zaptest.NewLogger(t))
+	if err := engine.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	idgen := mock.NewMockIDGenerator()
+	orgID, bucketID := idgen.ID(), idgen.ID()
+	name := tsdb.EncodeNameString(orgID, bucketID)
+	tags := models.NewTags(map[string]string{
+		models.MeasurementTagKey: "m0",
+		models.FieldKeyTagKey:    "f0",
+		"t0":                     "a",
+	})
+	var points []models.Point
+	for i, ts := range []int64{0, 3, 5, 7, 12, 13, 25} {
+		v := []float64{4, 2, 6, 1, 3, 3, 5}[i]
+		points = append(points, models.MustNewPoint(name, tags, models.Fields{"f0": v}, time.Unix(0, ts)))
+	}
+	if err := engine.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := reads.NewReader(readservice.NewStore(engine))
+	for _, tt := range []struct {
+		name        string
+		aggregate   string
+		stop        int64
+		createEmpty bool
+		want        []string
+	}{
+		{
+			name:      "mean",
+			aggregate: "mean",
+			stop:      40,
+			want:      []string{"[0, 10) 3.25", "[10, 20) 3", "[20, 30) 5"},
+		},
+		{
+			name:        "count create empty",
+			aggregate:   "count",
+			stop:        40,
+			createEmpty: true,
+			want:        []string{"[0, 10) 4", "[10, 20) 2", "[20, 30) 1", "[30, 40) 0"},
+		},
+		{
+			name:        "sum create empty",
+			aggregate:   "sum",
+			stop:        35,
+			createEmpty: true,
+			want:        []string{"[0, 10) 13", "[10, 20) 6", "[20, 30) 5", "[30, 35) <nil>"},
+		},
+		{
+			name:        "min create empty",
+			aggregate:   "min",
+			stop:        40,
+			createEmpty: true,
+			want:        []string{"[0, 10) 7 1", "[10, 20) 12 3", "[20, 30) 25 5", "[30, 40)"},
+		},
+		{
+			name:      "last",
+			aggregate: "last",
+			stop:      20,
+			want:      []string{"[0, 10) 7 1", "[10, 20) 13 3"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := reader.ReadWindowAggregate(context.Background(), influxdb.ReadWindowAggregateSpec{
+				ReadFilterSpec: influxdb.ReadFilterSpec{
+					OrganizationID: orgID,
+					BucketID:       bucketID,
+					Bounds:         execute.Bounds{Start: 0, Stop: execute.Time(tt.stop)},
+				},
+				WindowEvery:     10,
+				CreateEmpty:     tt.createEmpty,
+				AggregateMethod: tt.aggregate,
+			}, &memory.Allocator{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			err = tables.Do(func(table flux.Table) error {
+				key := table.Key()
+				prefix := fmt.Sprintf("[%d, %d)", key.ValueTime(0), key.ValueTime(1))
+				if table.Empty() {
+					got = append(got, prefix)
+				}
+				return table.Do(func(cr flux.ColReader) error {
+					for i := 0; i < cr.Len(); i++ {
+						row := prefix
+						for j, c := range cr.Cols() {
+							switch c.Label {
+							case execute.DefaultTimeColLabel:
+								row += fmt.Sprintf(" %d", execute.ValueForRow(cr, i, j).Time())
+							case execute.DefaultValueColLabel:
+								if v := execute.ValueForRow(cr, i, j); v.IsNull() {
+									row += " <nil>"
+								} else {
+									row += fmt.Sprintf(" %v", v)
+								}
+							}
+						}
+						got = append(got, row)
+					}
+					return nil
+				})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("unexpected tables -got/+want\n%s", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
 func benchmarkRead(b *testing.B, sg gen.SeriesGenerator, f func(r influxdb.Reader) error) {
 	logger := zaptest.NewLogger(b)
 	rootDir, err := ioutil.TempDir("", "storage-reads-test")
@@ -7,6 +7,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
 	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
@@ -44,6 +45,12 @@ func (s *store) ReadFilter(ctx context.Context, req *datatypes.ReadFilterRequest
 		return nil, err
 	}
 
+	scoped := *req
+	if scoped.Predicate, err = seriesScopePredicate(ctx, source, req.Predicate); err != nil {
+		return nil, err
+	}
+	req = &scoped
+
 	var cur reads.SeriesCursor
 	if ic, err := newIndexSeriesCursor(ctx, &source, req.Predicate, s.viewer); err != nil {
 		return nil, err
@@ -66,6 +73,12 @@ func (s *store) ReadGroup(ctx context.Context, req *datatypes.ReadGroupRequest)
 		return nil, err
 	}
 
+	scoped := *req
+	if scoped.Predicate, err = seriesScopePredicate(ctx, source, req.Predicate); err != nil {
+		return nil, err
+	}
+	req = &scoped
+
 	newCursor := func() (reads.SeriesCursor, error) {
 		cur, err := newIndexSeriesCursor(ctx, &source, req.Predicate, s.viewer)
 		if cur == nil || err != nil {
@@ -92,9 +105,17 @@ func (s *store) TagKeys(ctx context.Context, req *datatypes.TagKeysRequest) (cur
 		req.Range.End = models.MaxNanoTime
 	}
 
+	readSource, err := getReadSource(*req.TagsSource)
+	if err != nil {
+		return nil, err
+	}
+	predicate, err := seriesScopePredicate(ctx, readSource, req.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
 	var expr influxql.Expr
-	var err error
-	if root := req.Predicate.GetRoot(); root != nil {
+	if root := predicate.GetRoot(); root != nil {
 		expr, err = reads.NodeToExpr(root, nil)
 		if err != nil {
 			return nil, err
@@ -109,10 +130,6 @@ func (s *store) TagKeys(ctx context.Context, req *datatypes.TagKeysRequest) (cur
 		}
 	}
 
-	readSource, err := getReadSource(*req.TagsSource)
-	if err != nil {
-		return nil, err
-	}
 	return s.viewer.TagKeys(ctx, influxdb.ID(readSource.OrganizationID), influxdb.ID(readSource.BucketID), req.Range.Start, req.Range.End, expr)
 }
 
@@ -135,9 +152,17 @@ func (s *store) TagValues(ctx context.Context, req *datatypes.TagValuesRequest)
 		return nil, errors.New("missing tag key")
 	}
 
+	readSource, err := getReadSource(*req.TagsSource)
+	if err != nil {
+		return nil, err
+	}
+	predicate, err := seriesScopePredicate(ctx, readSource, req.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
 	var expr influxql.Expr
-	var err error
-	if root := req.Predicate.GetRoot(); root != nil {
+	if root := predicate.GetRoot(); root != nil {
 		expr, err = reads.NodeToExpr(root, nil)
 		if err != nil {
 			return nil, err
@@ -152,11 +177,23 @@ func (s *store) TagValues(ctx context.Context, req *datatypes.TagValuesRequest)
 		}
 	}
 
-	readSource, err := getReadSource(*req.TagsSource)
+	return s.viewer.TagValues(ctx, influxdb.ID(readSource.OrganizationID), influxdb.ID(readSource.BucketID), req.TagKey, req.Range.Start, req.Range.End, expr)
+}
+
+// seriesScopePredicate returns the predicate restricting pred to the series
+// of the bucket of the source the authorizer of the context may read.
+func seriesScopePredicate(ctx context.Context, source readSource, pred *datatypes.Predicate) (*datatypes.Predicate, error) {
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		// the reads without an authorizer are not made on behalf of a user.
+		return pred, nil
+	}
+
+	p, err := influxdb.NewPermissionAtID(influxdb.ID(source.BucketID), influxdb.ReadAction, influxdb.BucketsResourceType, influxdb.ID(source.OrganizationID))
 	if err != nil {
 		return nil, err
 	}
-	return s.viewer.TagValues(ctx, influxdb.ID(readSource.OrganizationID), influxdb.ID(readSource.BucketID), req.TagKey, req.Range.Start, req.Range.End, expr)
+	return reads.SeriesScopePredicate(pred, influxdb.SeriesScopes(a, *p)), nil
 }
 
 // this is easier than fooling around with .proto files.
@@ -1,14 +1,17 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -29,6 +32,12 @@ type Snapshotter interface {
 	WriteSnapshot(ctx context.Context, status tsm1.CacheStatus) error
 }
 
+// A ColdTierMover implementation can move TSM files to the cold tier of a
+// storage engine.
+type ColdTierMover interface {
+	MoveToColdTier(ctx context.Context, fn func(tsm1.FileStat) bool) (int, error)
+}
+
 // A BucketFinder is responsible for providing access to buckets via a filter.
 type BucketFinder interface {
 	FindBuckets(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error)
@@ -45,6 +54,10 @@ type retentionEnforcer struct {
 
 	Snapshotter Snapshotter
 
+	// ColdTier moves the files of the buckets with a cold tier period. Files
+	// are not moved when it is nil.
+	ColdTier ColdTierMover
+
 	// BucketService provides an API for retrieving buckets associated with
 	// organisations.
 	BucketService BucketFinder
@@ -109,6 +122,9 @@ func (s *retentionEnforcer) run() {
 		log.Error("Unable to determine bucket information", zap.Error(err))
 	} else {
 		s.expireData(ctx, buckets, now)
+		if s.ColdTier != nil {
+			s.moveToColdTier(ctx, buckets, now)
+		}
 	}
 	s.tracker.CheckDuration(time.Since(now), err == nil)
 }
@@ -175,6 +191,72 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 	}
 }
 
+// moveToColdTier moves the TSM files holding only data older than the cold
+// tier period of their buckets to the cold tier.
+//
+// A file is only moved when every bucket it holds data of has a cold tier
+// period, and the file holds no data within any of those periods.
+func (s *retentionEnforcer) moveToColdTier(ctx context.Context, buckets []*influxdb.Bucket, now time.Time) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	logger, logEnd := logger.NewOperation(ctx, s.logger, "Cold tier move", "cold_tier_move")
+	defer logEnd()
+
+	// The thresholds are sorted by the name the data of their bucket is stored
+	// under. A zero threshold marks a bucket without a cold tier period.
+	type bucketThreshold struct {
+		name [16]byte
+		max  int64
+	}
+	thresholds := make([]bucketThreshold, 0, len(buckets))
+	var cold int
+	for _, b := range buckets {
+		if !b.OrgID.Valid() || !b.ID.Valid() {
+			continue
+		}
+
+		t := bucketThreshold{name: tsdb.EncodeName(b.OrgID, b.ID)}
+		if b.ColdTierPeriod > 0 {
+			t.max = now.Add(-b.ColdTierPeriod).UnixNano()
+			cold++
+		}
+		thresholds = append(thresholds, t)
+	}
+	if cold == 0 {
+		return
+	}
+	sort.Slice(thresholds, func(i, j int) bool {
+		return bytes.Compare(thresholds[i].name[:], thresholds[j].name[:]) < 0
+	})
+
+	n, err := s.ColdTier.MoveToColdTier(ctx, func(stat tsm1.FileStat) bool {
+		if len(stat.MinKey) < 16 || len(stat.MaxKey) < 16 {
+			return false
+		}
+		min, max := stat.MinKey[:16], stat.MaxKey[:16]
+
+		i := sort.Search(len(thresholds), func(i int) bool {
+			return bytes.Compare(thresholds[i].name[:], min) >= 0
+		})
+		var found bool
+		for ; i < len(thresholds) && bytes.Compare(thresholds[i].name[:], max) <= 0; i++ {
+			if thresholds[i].max == 0 || stat.MaxTime >= thresholds[i].max {
+				return false
+			}
+			found = true
+		}
+		return found
+	})
+	if n > 0 {
+		logger.Info("Moved files to cold tier", zap.Int("files_total", n))
+	}
+	if err != nil {
+		logger.Info("Unable to move files to cold tier", zap.Error(err))
+		tracing.LogError(span, err)
+	}
+}
+
 // getBucketInformation returns a slice of buckets to run retention on.
 func (s *retentionEnforcer) getBucketInformation(ctx context.Context) ([]*influxdb.Bucket, error) {
 	ctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
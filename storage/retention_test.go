@@ -240,6 +240,63 @@ func TestRetentionService(t *testing.T) {
 	})
 }
 
+func TestRetentionService_ColdTier(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2018, 4, 10, 23, 12, 33, 0, time.UTC)
+
+	cold := &influxdb.Bucket{OrgID: 1, ID: 1, ColdTierPeriod: 24 * time.Hour}
+	colder := &influxdb.Bucket{OrgID: 1, ID: 2, ColdTierPeriod: 48 * time.Hour}
+	hot := &influxdb.Bucket{OrgID: 1, ID: 3}
+	buckets := []*influxdb.Bucket{hot, colder, cold}
+
+	stat := func(maxTime time.Time, bs ...*influxdb.Bucket) tsm1.FileStat {
+		min := tsdb.EncodeNameSlice(bs[0].OrgID, bs[0].ID)
+		max := tsdb.EncodeNameSlice(bs[len(bs)-1].OrgID, bs[len(bs)-1].ID)
+		return tsm1.FileStat{
+			MinKey:  append(min, ",tag=a"...),
+			MaxKey:  append(max, ",tag=z"...),
+			MaxTime: maxTime.UnixNano(),
+		}
+	}
+	old, older := now.Add(-36*time.Hour), now.Add(-72*time.Hour)
+
+	for _, tt := range []struct {
+		name string
+		stat tsm1.FileStat
+		exp  bool
+	}{
+		{name: "old data of cold bucket", stat: stat(old, cold), exp: true},
+		{name: "recent data of cold bucket", stat: stat(now, cold), exp: false},
+		{name: "old data of cold buckets", stat: stat(old, cold, colder), exp: false},
+		{name: "older data of cold buckets", stat: stat(older, cold, colder), exp: true},
+		{name: "data of buckets without cold tier", stat: stat(older, cold, hot), exp: false},
+		{name: "data of unknown bucket", stat: stat(older, &influxdb.Bucket{OrgID: 2, ID: 1}), exp: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mover := &TestColdTierMover{}
+			service := newRetentionEnforcer(NewTestEngine(), &TestSnapshotter{}, NewTestBucketFinder())
+			service.ColdTier = mover
+			service.moveToColdTier(context.Background(), buckets, now)
+			if mover.fn == nil {
+				t.Fatal("no files moved to the cold tier")
+			}
+			if got := mover.fn(tt.stat); got != tt.exp {
+				t.Fatalf("got %v, expected %v", got, tt.exp)
+			}
+		})
+	}
+
+	t.Run("no cold buckets", func(t *testing.T) {
+		mover := &TestColdTierMover{}
+		service := newRetentionEnforcer(NewTestEngine(), &TestSnapshotter{}, NewTestBucketFinder())
+		service.ColdTier = mover
+		service.moveToColdTier(context.Background(), []*influxdb.Bucket{hot}, now)
+		if mover.fn != nil {
+			t.Fatal("got files moved to the cold tier without cold buckets")
+		}
+	})
+}
+
 func TestMetrics_Retention(t *testing.T) {
 	t.Parallel()
 	// metrics to be shared by multiple file stores.
@@ -327,6 +384,15 @@ func (e *TestEngine) DeleteBucketRange(ctx context.Context, orgID, bucketID infl
 	return e.DeleteBucketRangeFn(ctx, orgID, bucketID, min, max)
 }
 
+type TestColdTierMover struct {
+	fn func(tsm1.FileStat) bool
+}
+
+func (m *TestColdTierMover) MoveToColdTier(ctx context.Context, fn func(tsm1.FileStat) bool) (int, error) {
+	m.fn = fn
+	return 0, nil
+}
+
 type TestSnapshotter struct{}
 
 func (s *TestSnapshotter) WriteSnapshot(ctx context.Context, status tsm1.CacheStatus) error {
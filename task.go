@@ -57,6 +57,7 @@ type Task struct {
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
 	Offset          time.Duration          `json:"offset,omitempty"`
+	DependsOn       []ID                   `json:"dependsOn,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
@@ -91,6 +92,7 @@ type Run struct {
 	StartedAt    time.Time `json:"startedAt,omitempty"`   // StartedAt is the time the executor begins running the task
 	FinishedAt   time.Time `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
 	RequestedAt  time.Time `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
+	Attempt      int       `json:"attempt,omitempty"`     // Attempt is the attempt of a retried run for ScheduledFor, the first being 1
 	Log          []Log     `json:"log,omitempty"`
 }
 
@@ -142,6 +144,14 @@ type TaskService interface {
 	// ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 	// The value of scheduledFor may or may not align with the task's schedule.
 	ForceRun(ctx context.Context, taskID ID, scheduledFor int64) (*Run, error)
+
+	// BackfillTask creates a manual run of a task for every time of its schedule in the range of the request,
+	// to be executed as soon as possible, at most Concurrency of them at once. The runs are queued as the
+	// previous ones finish, the Runs of the returned backfill being the ones queued first.
+	BackfillTask(ctx context.Context, taskID ID, req BackfillRequest) (*Backfill, error)
+
+	// FindBackfills returns the backfills of a task, the oldest first.
+	FindBackfills(ctx context.Context, taskID ID) ([]*Backfill, error)
 }
 
 // TaskCreate is the set of values to create a task.
@@ -207,6 +217,9 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		// DependsOn are the IDs of the tasks that trigger the task.
+		DependsOn []string `json:"dependsOn,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &jo); err != nil {
@@ -222,6 +235,7 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	}
 	t.Options.Concurrency = jo.Concurrency
 	t.Options.Retry = jo.Retry
+	t.Options.DependsOn = jo.DependsOn
 	t.Flux = jo.Flux
 	t.Status = jo.Status
 	return nil
@@ -246,6 +260,10 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		// DependsOn are the IDs of the tasks that trigger the task.
+		// It is not omitted when empty as an empty DependsOn removes the dependencies.
+		DependsOn []string `json:"dependsOn"`
 	}{}
 	jo.Name = t.Options.Name
 	jo.Cron = t.Options.Cron
@@ -257,6 +275,7 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 	}
 	jo.Concurrency = t.Options.Concurrency
 	jo.Retry = t.Options.Retry
+	jo.DependsOn = t.Options.DependsOn
 	jo.Flux = t.Flux
 	jo.Status = t.Status
 	return json.Marshal(jo)
@@ -335,6 +354,17 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) (err error) {
 			toDelete["offset"] = struct{}{}
 		}
 	}
+	if t.Options.DependsOn != nil {
+		if len(t.Options.DependsOn) > 0 {
+			ids := make([]ast.Expression, 0, len(t.Options.DependsOn))
+			for _, id := range t.Options.DependsOn {
+				ids = append(ids, &ast.StringLiteral{Value: id})
+			}
+			op["dependsOn"] = &ast.ArrayExpression{Elements: ids}
+		} else {
+			toDelete["dependsOn"] = struct{}{}
+		}
+	}
 	if len(op) > 0 || len(toDelete) > 0 {
 		editFunc := func(opt *ast.OptionStatement) (ast.Expression, error) {
 			a, ok := opt.Assignment.(*ast.VariableAssignment)
@@ -362,6 +392,11 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) (err error) {
 						delete(op, "offset")
 						p.Value = offset.Copy().(*ast.DurationLiteral)
 					}
+				case "dependsOn":
+					if dependsOn, ok := op["dependsOn"]; ok {
+						delete(op, "dependsOn")
+						p.Value = dependsOn
+					}
 				case "every":
 					if every, ok := op["every"]; ok && !t.Options.Every.IsZero() {
 						p.Value = every.Copy().(*ast.DurationLiteral)
@@ -465,3 +500,69 @@ type LogFilter struct {
 	// The optional Run ID limits logs to a single run.
 	Run *ID
 }
+
+const (
+	BackfillStatusRunning = "running"
+	BackfillStatusSuccess = "success"
+	BackfillStatusFailed  = "failed"
+)
+
+// MaxBackfillRuns is the maximum number of runs a backfill can create.
+const MaxBackfillRuns = 10000
+
+// MaxBackfillConcurrency is the maximum number of runs of a backfill queued at once.
+const MaxBackfillConcurrency = 100
+
+// BackfillRequest is the set of values to backfill a task.
+type BackfillRequest struct {
+	// Start and Stop are the range of the backfill; a run is created for every time of
+	// the task's schedule after Start, up to and including Stop.
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
+	// Concurrency is the maximum number of runs of the backfill executed at once, 1 if unset.
+	Concurrency int `json:"concurrency,omitempty"`
+	// DryRun returns the backfill without creating any run.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+func (r BackfillRequest) Validate() error {
+	switch {
+	case r.Start.IsZero() || r.Stop.IsZero():
+		return errors.New("missing start or stop")
+	case !r.Start.Before(r.Stop):
+		return errors.New("start must be before stop")
+	case r.Concurrency < 0:
+		return errors.New("concurrency must not be negative")
+	case r.Concurrency > MaxBackfillConcurrency:
+		return fmt.Errorf("concurrency exceeded max of %d", MaxBackfillConcurrency)
+	}
+	return nil
+}
+
+// Backfill is the set of manual runs of a task created for the times of its schedule in a range.
+// The runs are queued in order as the previous ones finish, at most Concurrency of them at once.
+type Backfill struct {
+	ID           ID          `json:"id,omitempty"`
+	TaskID       ID          `json:"taskID"`
+	Status       string      `json:"status,omitempty"`
+	Start        time.Time   `json:"start"`
+	Stop         time.Time   `json:"stop"`
+	Concurrency  int         `json:"concurrency"`
+	ScheduledFor []time.Time `json:"scheduledFor"`         // ScheduledFor are the times of the runs, in order
+	Runs         []ID        `json:"runs,omitempty"`       // Runs are the IDs of the runs queued so far, in order
+	Succeeded    int         `json:"succeeded"`            // Succeeded is the number of runs that succeeded
+	Failed       int         `json:"failed"`               // Failed is the number of runs that failed or were canceled
+	Skipped      int         `json:"skipped"`              // Skipped is the number of times a run was already queued for
+	CreatedAt    time.Time   `json:"createdAt,omitempty"`  // CreatedAt is the time the backfill was requested
+	FinishedAt   time.Time   `json:"finishedAt,omitempty"` // FinishedAt is the time the last run of the backfill finished
+}
+
+// Finished returns the number of times of the backfill that finished or were skipped.
+func (b *Backfill) Finished() int {
+	return b.Succeeded + b.Failed + b.Skipped
+}
+
+// Running returns the number of queued runs of the backfill that did not finish yet.
+func (b *Backfill) Running() int {
+	return len(b.Runs) - b.Succeeded - b.Failed
+}
@@ -21,6 +21,7 @@ const (
 	startedAtField    = "startedAt"
 	finishedAtField   = "finishedAt"
 	requestedAtField  = "requestedAt"
+	attemptField      = "attempt"
 	logField          = "logs"
 
 	taskIDTag = "taskID"
@@ -390,6 +391,11 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 					continue
 				}
 				r.ScheduledFor = scheduled.UTC()
+			case attemptField:
+				// only the attempts of retried runs are recorded.
+				if col.Type == flux.TInt && cr.Ints(j).IsValid(i) {
+					r.Attempt = int(cr.Ints(j).Value(i))
+				}
 			case statusTag:
 				r.Status = cr.Strings(j).ValueString(i)
 			case finishedAtField:
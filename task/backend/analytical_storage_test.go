@@ -109,6 +109,46 @@ func TestDeduplicateRuns(t *testing.T) {
 	}
 }
 
+func TestRecordRunAttempt(t *testing.T) {
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(context.Background()); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+
+	ab := newAnalyticalBackend(t, svc, svc)
+	defer ab.Close(t)
+
+	mockTS := &mock.TaskService{
+		FindTaskByIDFn: func(context.Context, influxdb.ID) (*influxdb.Task, error) {
+			return &influxdb.Task{ID: 1, OrganizationID: 20}, nil
+		},
+		FindRunsFn: func(context.Context, influxdb.RunFilter) ([]*influxdb.Run, int, error) {
+			return nil, 0, nil
+		},
+	}
+	mockTCS := &mock.TaskControlService{
+		FinishRunFn: func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+			return &influxdb.Run{ID: runID, TaskID: 1, Status: "failed", Attempt: 2, ScheduledFor: time.Now(), StartedAt: time.Now().Add(1), FinishedAt: time.Now().Add(2)}, nil
+		},
+	}
+	mockBS := mock.NewBucketService()
+
+	svcStack := backend.NewAnalyticalStorage(zaptest.NewLogger(t), mockTS, mockBS, mockTCS, ab.PointsWriter(), ab.QueryService())
+
+	if _, err := svcStack.FinishRun(context.Background(), 1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, _, err := svcStack.FindRuns(context.Background(), influxdb.RunFilter{Task: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(runs) != 1 || runs[0].Attempt != 2 {
+		t.Fatalf("expected the attempt of the run to be recorded, got %v", runs)
+	}
+}
+
 type analyticalBackend struct {
 	queryController *control.Controller
 	rootDir         string
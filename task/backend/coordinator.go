@@ -40,10 +40,7 @@ func NotifyCoordinatorOfExisting(ctx context.Context, log *zap.Logger, ts TaskSe
 				continue
 			}
 
-			task, err := ts.UpdateTask(context.Background(), task.ID, influxdb.TaskUpdate{
-				LatestCompleted: &latestCompleted,
-				LatestScheduled: &latestCompleted,
-			})
+			task, err := resetLatestCompleted(ts, task, latestCompleted)
 			if err != nil {
 				log.Error("Failed to set latestCompleted", zap.Error(err))
 				continue
@@ -63,6 +60,21 @@ func NotifyCoordinatorOfExisting(ctx context.Context, log *zap.Logger, ts TaskSe
 	return nil
 }
 
+// resetLatestCompleted sets the latest completed and scheduled times of the task to
+// latestCompleted, so that the runs missed while the task was not scheduled are skipped.
+// Downsample tasks keep their times instead, because the runs they missed compute
+// windows of their downsampled bucket that would otherwise be left empty.
+func resetLatestCompleted(ts TaskService, task *influxdb.Task, latestCompleted time.Time) (*influxdb.Task, error) {
+	if task.Type == influxdb.TaskTypeDownsample && !task.LatestCompleted.IsZero() {
+		return task, nil
+	}
+
+	return ts.UpdateTask(context.Background(), task.ID, influxdb.TaskUpdate{
+		LatestCompleted: &latestCompleted,
+		LatestScheduled: &latestCompleted,
+	})
+}
+
 type TaskResumer func(ctx context.Context, id influxdb.ID, runID influxdb.ID) error
 
 // TaskNotifyCoordinatorOfExisting lists all tasks by the provided task service and for
@@ -82,10 +94,7 @@ func TaskNotifyCoordinatorOfExisting(ctx context.Context, ts TaskService, tcs Ta
 				continue
 			}
 
-			task, err := ts.UpdateTask(context.Background(), task.ID, influxdb.TaskUpdate{
-				LatestCompleted: &latestCompleted,
-				LatestScheduled: &latestCompleted,
-			})
+			task, err := resetLatestCompleted(ts, task, latestCompleted)
 			if err != nil {
 				log.Error("Failed to set latestCompleted", zap.Error(err))
 				continue
@@ -11,6 +11,13 @@ import (
 
 var _ middleware.Coordinator = (*Coordinator)(nil)
 
+// errDependenciesNotSupported is returned for the tasks that depend on other tasks,
+// as only the TreeScheduler triggers the runs of a task from the runs of the tasks it depends on.
+var errDependenciesNotSupported = &influxdb.Error{
+	Code: influxdb.EInvalid,
+	Msg:  "task dependencies require the new task scheduler",
+}
+
 type Coordinator struct {
 	log *zap.Logger
 	sch backend.Scheduler
@@ -41,10 +48,17 @@ func New(log *zap.Logger, scheduler backend.Scheduler, opts ...Option) *Coordina
 }
 
 func (c *Coordinator) TaskCreated(ctx context.Context, task *influxdb.Task) error {
+	if len(task.DependsOn) > 0 {
+		return errDependenciesNotSupported
+	}
 	return c.sch.ClaimTask(ctx, task)
 }
 
 func (c *Coordinator) TaskUpdated(ctx context.Context, from, to *influxdb.Task) error {
+	if len(to.DependsOn) > 0 {
+		return errDependenciesNotSupported
+	}
+
 	// if disabling the task release it before schedule update
 	if to.Status != from.Status && to.Status == string(backend.TaskInactive) {
 		if err := c.sch.ReleaseTask(to.ID); err != nil && err != influxdb.ErrTaskNotClaimed {
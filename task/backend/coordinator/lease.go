@@ -0,0 +1,504 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+)
+
+var (
+	taskLeaseBucket     = []byte("taskLeasesv1")
+	schedulerNodeBucket = []byte("taskSchedulerNodesv1")
+)
+
+// DefaultLeaseTTL is the duration for which a lease is held without being renewed.
+const DefaultLeaseTTL = 30 * time.Second
+
+// ErrLeaseNotHeld is returned when executing a run of a task of which the node does not hold the lease.
+var ErrLeaseNotHeld = &influxdb.Error{
+	Code: influxdb.EConflict,
+	Msg:  "task lease not held",
+}
+
+// lease is the lease of a task, or the heartbeat of a node, in the kv store.
+type lease struct {
+	Node      influxdb.ID `json:"node"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+}
+
+func (l lease) expired(now time.Time) bool {
+	return !l.ExpiresAt.After(now)
+}
+
+// Leases are the leases of tasks of a node in a kv.Store shared by several nodes.
+// A node holds the lease of a task until its TTL is past, unless it renews it, and
+// at most one node holds the lease of a task at a time.
+// Each node also records its heartbeat in the store, so that the nodes split the
+// tasks between the nodes that are alive.
+type Leases struct {
+	store kv.Store
+	node  influxdb.ID
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// LeaseOption configures Leases.
+type LeaseOption func(*Leases)
+
+// WithLeaseTTL sets the duration for which the leases of the node are held without being renewed.
+func WithLeaseTTL(ttl time.Duration) LeaseOption {
+	return func(l *Leases) {
+		l.ttl = ttl
+	}
+}
+
+// WithLeaseClock sets the function returning the current time of the node.
+func WithLeaseClock(now func() time.Time) LeaseOption {
+	return func(l *Leases) {
+		l.now = now
+	}
+}
+
+// NewLeases returns the Leases of the node in store.
+func NewLeases(store kv.Store, node influxdb.ID, opts ...LeaseOption) *Leases {
+	l := &Leases{
+		store: store,
+		node:  node,
+		ttl:   DefaultLeaseTTL,
+		now:   time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Node returns the ID of the node holding the leases.
+func (l *Leases) Node() influxdb.ID {
+	return l.node
+}
+
+// Held reports whether the node holds the lease of the task id.
+func (l *Leases) Held(ctx context.Context, id influxdb.ID) (bool, error) {
+	var held bool
+	err := l.store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(taskLeaseBucket)
+		if err != nil {
+			return err
+		}
+
+		ls, ok, err := getLease(b, id)
+		if err != nil {
+			return err
+		}
+		held = ok && ls.Node == l.node && !ls.expired(l.now())
+		return nil
+	})
+	if err != nil {
+		return false, &influxdb.Error{Err: err}
+	}
+
+	return held, nil
+}
+
+// acquire takes the lease of the task id if no other node holds it, and reports whether the node holds it.
+func (l *Leases) acquire(ctx context.Context, id influxdb.ID) (bool, error) {
+	var held bool
+	err := l.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(taskLeaseBucket)
+		if err != nil {
+			return err
+		}
+
+		now := l.now()
+		ls, ok, err := getLease(b, id)
+		if err != nil {
+			return err
+		}
+		if ok && ls.Node != l.node && !ls.expired(now) {
+			return nil
+		}
+
+		held = true
+		return putLease(b, id, lease{Node: l.node, ExpiresAt: now.Add(l.ttl)})
+	})
+	if err != nil {
+		return false, &influxdb.Error{Err: err}
+	}
+
+	return held, nil
+}
+
+// release gives up the lease of the task id if the node holds it.
+func (l *Leases) release(ctx context.Context, id influxdb.ID) error {
+	err := l.store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(taskLeaseBucket)
+		if err != nil {
+			return err
+		}
+
+		ls, ok, err := getLease(b, id)
+		if err != nil || !ok || ls.Node != l.node {
+			return err
+		}
+		return deleteLease(b, id)
+	})
+	if err != nil {
+		return &influxdb.Error{Err: err}
+	}
+
+	return nil
+}
+
+// releaseAll gives up all the leases of the node and its heartbeat,
+// so that the other nodes take over its tasks without waiting for the TTL to pass.
+func (l *Leases) releaseAll(ctx context.Context) error {
+	err := l.store.Update(ctx, func(tx kv.Tx) error {
+		nodes, err := tx.Bucket(schedulerNodeBucket)
+		if err != nil {
+			return err
+		}
+		if err := deleteLease(nodes, l.node); err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(taskLeaseBucket)
+		if err != nil {
+			return err
+		}
+		leases, err := allLeases(b)
+		if err != nil {
+			return err
+		}
+		for id, ls := range leases {
+			if ls.Node != l.node {
+				continue
+			}
+			if err := deleteLease(b, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{Err: err}
+	}
+
+	return nil
+}
+
+// balance records the heartbeat of the node and splits the groups of tasks between the live nodes.
+// The tasks of a group are leased to a single node, and each node leases at most its share of the groups.
+// The node renews the leases of the groups it keeps, takes the leases of the groups no live node holds
+// while it has less than its share of them, and gives up the groups past its share.
+// It returns the IDs of the tasks of which the node holds the lease.
+func (l *Leases) balance(ctx context.Context, groups [][]influxdb.ID) (map[influxdb.ID]bool, error) {
+	held := map[influxdb.ID]bool{}
+	err := l.store.Update(ctx, func(tx kv.Tx) error {
+		now := l.now()
+		renewed := lease{Node: l.node, ExpiresAt: now.Add(l.ttl)}
+
+		// record the heartbeat of the node, and forget the nodes that are gone.
+		nodes, err := tx.Bucket(schedulerNodeBucket)
+		if err != nil {
+			return err
+		}
+		if err := putLease(nodes, l.node, renewed); err != nil {
+			return err
+		}
+		beats, err := allLeases(nodes)
+		if err != nil {
+			return err
+		}
+		live := map[influxdb.ID]bool{}
+		for id, beat := range beats {
+			if beat.expired(now) {
+				if err := deleteLease(nodes, id); err != nil {
+					return err
+				}
+				continue
+			}
+			live[id] = true
+		}
+
+		b, err := tx.Bucket(taskLeaseBucket)
+		if err != nil {
+			return err
+		}
+		leases, err := allLeases(b)
+		if err != nil {
+			return err
+		}
+		holder := func(id influxdb.ID) (influxdb.ID, bool) {
+			ls, ok := leases[id]
+			if !ok || ls.expired(now) || !live[ls.Node] {
+				return 0, false
+			}
+			return ls.Node, true
+		}
+
+		// the groups of which the node holds a lease, and the ones no live node holds.
+		var kept, free [][]influxdb.ID
+		grouped := map[influxdb.ID]bool{}
+		for _, group := range groups {
+			// a group with leases held by several nodes, after tasks started to depend on each other,
+			// goes to the holder of the lowest held task.
+			var owner influxdb.ID
+			var owned bool
+			for _, id := range group {
+				grouped[id] = true
+				if !owned {
+					owner, owned = holder(id)
+				}
+			}
+
+			switch {
+			case !owned:
+				free = append(free, group)
+			case owner == l.node:
+				kept = append(kept, group)
+			default:
+				for _, id := range group {
+					if node, ok := holder(id); ok && node == l.node {
+						if err := deleteLease(b, id); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+
+		share := (len(groups) + len(live) - 1) / len(live)
+		for _, group := range free {
+			if len(kept) >= share {
+				break
+			}
+			kept = append(kept, group)
+		}
+		for len(kept) > share {
+			last := kept[len(kept)-1]
+			kept = kept[:len(kept)-1]
+			for _, id := range last {
+				if node, ok := holder(id); ok && node == l.node {
+					if err := deleteLease(b, id); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		for _, group := range kept {
+			for _, id := range group {
+				if node, ok := holder(id); ok && node != l.node {
+					// keep the tasks another node still holds until it gives them up.
+					continue
+				}
+				if err := putLease(b, id, renewed); err != nil {
+					return err
+				}
+				held[id] = true
+			}
+		}
+
+		// drop the leases of the tasks that are no longer scheduled.
+		for id, ls := range leases {
+			if grouped[id] || (ls.Node != l.node && !ls.expired(now)) {
+				continue
+			}
+			if err := deleteLease(b, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{Err: err}
+	}
+
+	return held, nil
+}
+
+// Executor returns an Executor that executes the runs of ex only for the tasks of which the node
+// holds the lease, so that a run is not executed by several nodes.
+func (l *Leases) Executor(ex scheduler.Executor) scheduler.Executor {
+	le := leasedExecutor{leases: l, ex: ex}
+	if fr, ok := ex.(scheduler.FailureRecorder); ok {
+		return leasedFailureRecorder{leasedExecutor: le, fr: fr}
+	}
+	return le
+}
+
+// SchedulableService returns a SchedulableService that records the runs scheduled by the node
+// only for the tasks of which it holds the lease, as the node that holds it schedules their runs.
+func (l *Leases) SchedulableService(ss scheduler.SchedulableService) scheduler.SchedulableService {
+	return leasedSchedulableService{leases: l, ss: ss}
+}
+
+func (l *Leases) check(ctx context.Context, id scheduler.ID) error {
+	held, err := l.Held(ctx, influxdb.ID(id))
+	if err != nil {
+		return err
+	}
+	if !held {
+		return ErrLeaseNotHeld
+	}
+	return nil
+}
+
+type leasedExecutor struct {
+	leases *Leases
+	ex     scheduler.Executor
+}
+
+func (e leasedExecutor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
+	if err := e.leases.check(ctx, id); err != nil {
+		return err
+	}
+	return e.ex.Execute(ctx, id, scheduledFor, runAt)
+}
+
+type leasedFailureRecorder struct {
+	leasedExecutor
+	fr scheduler.FailureRecorder
+}
+
+func (e leasedFailureRecorder) RecordFailure(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time, err error) error {
+	if err := e.leases.check(ctx, id); err != nil {
+		return err
+	}
+	return e.fr.RecordFailure(ctx, id, scheduledFor, runAt, err)
+}
+
+type leasedSchedulableService struct {
+	leases *Leases
+	ss     scheduler.SchedulableService
+}
+
+func (s leasedSchedulableService) UpdateLastScheduled(ctx context.Context, id scheduler.ID, t time.Time) error {
+	if err := s.leases.check(ctx, id); err != nil {
+		return err
+	}
+	return s.ss.UpdateLastScheduled(ctx, id, t)
+}
+
+func getLease(b kv.Bucket, id influxdb.ID) (lease, bool, error) {
+	key, err := id.Encode()
+	if err != nil {
+		return lease{}, false, err
+	}
+
+	v, err := b.Get(key)
+	if kv.IsNotFound(err) {
+		return lease{}, false, nil
+	}
+	if err != nil {
+		return lease{}, false, err
+	}
+
+	var ls lease
+	if err := json.Unmarshal(v, &ls); err != nil {
+		return lease{}, false, err
+	}
+	return ls, true, nil
+}
+
+func putLease(b kv.Bucket, id influxdb.ID, ls lease) error {
+	key, err := id.Encode()
+	if err != nil {
+		return err
+	}
+
+	v, err := json.Marshal(ls)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, v)
+}
+
+func deleteLease(b kv.Bucket, id influxdb.ID) error {
+	key, err := id.Encode()
+	if err != nil {
+		return err
+	}
+	return b.Delete(key)
+}
+
+func allLeases(b kv.Bucket) (map[influxdb.ID]lease, error) {
+	c, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	leases := map[influxdb.ID]lease{}
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		var id influxdb.ID
+		if err := id.Decode(k); err != nil {
+			return nil, err
+		}
+		var ls lease
+		if err := json.Unmarshal(v, &ls); err != nil {
+			return nil, err
+		}
+		leases[id] = ls
+	}
+	return leases, nil
+}
+
+// taskGroups splits tasks into the groups of tasks that depend on each other, directly or not,
+// as the runs of the tasks of a group are triggered on a single scheduler.
+// The IDs of each group, and the groups, are sorted by ID.
+func taskGroups(tasks []*influxdb.Task) [][]influxdb.ID {
+	parent := make(map[influxdb.ID]influxdb.ID, len(tasks))
+	var find func(id influxdb.ID) influxdb.ID
+	find = func(id influxdb.ID) influxdb.ID {
+		p, ok := parent[id]
+		if !ok || p == id {
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+	union := func(a, b influxdb.ID) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		if rb < ra {
+			ra, rb = rb, ra
+		}
+		parent[rb] = ra
+	}
+
+	for _, t := range tasks {
+		parent[t.ID] = t.ID
+	}
+	for _, t := range tasks {
+		for _, id := range t.DependsOn {
+			if _, ok := parent[id]; ok {
+				union(t.ID, id)
+			}
+		}
+	}
+
+	members := map[influxdb.ID][]influxdb.ID{}
+	for _, t := range tasks {
+		root := find(t.ID)
+		members[root] = append(members[root], t.ID)
+	}
+
+	groups := make([][]influxdb.ID, 0, len(members))
+	for _, group := range members {
+		sort.Slice(group, func(i, j int) bool { return group[i] < group[j] })
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups
+}
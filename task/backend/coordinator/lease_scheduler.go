@@ -0,0 +1,211 @@
+package coordinator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap"
+)
+
+var _ scheduler.Scheduler = (*LeaseScheduler)(nil)
+
+// TaskFinder is a type on which tasks can be listed.
+type TaskFinder interface {
+	FindTasks(ctx context.Context, filter influxdb.TaskFilter) ([]*influxdb.Task, int, error)
+}
+
+// LeaseScheduler is a Scheduler that schedules a task on its Scheduler only while its node holds
+// the lease of the task, so that several nodes sharing a kv.Store split the tasks without running them twice.
+// Its heartbeat renews the leases of the node, takes over the tasks of the nodes that are gone and
+// reschedules the tasks updated on the other nodes.
+type LeaseScheduler struct {
+	log    *zap.Logger
+	leases *Leases
+	sch    scheduler.Scheduler
+	ts     TaskFinder
+
+	mu        sync.Mutex
+	scheduled map[influxdb.ID]time.Time // the UpdatedAt of the tasks scheduled on sch
+}
+
+// NewLeaseScheduler returns a LeaseScheduler scheduling the tasks of ts of which the node of leases holds the lease on sch.
+// The Executor and SchedulableService of sch are expected to be wrapped by leases too.
+func NewLeaseScheduler(log *zap.Logger, leases *Leases, sch scheduler.Scheduler, ts TaskFinder) *LeaseScheduler {
+	return &LeaseScheduler{
+		log:       log,
+		leases:    leases,
+		sch:       sch,
+		ts:        ts,
+		scheduled: map[influxdb.ID]time.Time{},
+	}
+}
+
+// Schedule takes the lease of the task, and schedules it if the node holds it.
+// The task is otherwise scheduled by the node holding its lease on its next heartbeat.
+func (s *LeaseScheduler) Schedule(sch scheduler.Schedulable) error {
+	id := influxdb.ID(sch.ID())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	held, err := s.leases.acquire(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	if !held {
+		return s.release(id)
+	}
+
+	if err := s.sch.Schedule(sch); err != nil {
+		return err
+	}
+
+	var updatedAt time.Time
+	if t, ok := sch.(SchedulableTask); ok {
+		updatedAt = t.UpdatedAt
+	}
+	s.scheduled[id] = updatedAt
+	return nil
+}
+
+// Release releases the task and gives up its lease.
+func (s *LeaseScheduler) Release(taskID scheduler.ID) error {
+	id := influxdb.ID(taskID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.scheduled, id)
+	if err := s.sch.Release(taskID); err != nil {
+		return err
+	}
+
+	return s.leases.release(context.Background(), id)
+}
+
+// release releases the task from sch if it is scheduled on it.
+func (s *LeaseScheduler) release(id influxdb.ID) error {
+	if _, ok := s.scheduled[id]; !ok {
+		return nil
+	}
+
+	delete(s.scheduled, id)
+	return s.sch.Release(scheduler.ID(id))
+}
+
+// Heartbeat renews the leases of the node and splits the active tasks between the live nodes.
+// It schedules the tasks of which the node took the lease or that were updated since they were
+// scheduled, and releases the tasks of which it no longer holds the lease.
+func (s *LeaseScheduler) Heartbeat(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.findTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	held, err := s.leases.balance(ctx, taskGroups(tasks))
+	if err != nil {
+		return err
+	}
+
+	listed := make(map[influxdb.ID]bool, len(tasks))
+	for _, t := range tasks {
+		listed[t.ID] = true
+		if !held[t.ID] {
+			if err := s.release(t.ID); err != nil {
+				s.log.Error("Failed to release task", zap.String("taskID", t.ID.String()), zap.Error(err))
+			}
+			continue
+		}
+
+		if updatedAt, ok := s.scheduled[t.ID]; ok && !t.UpdatedAt.After(updatedAt) {
+			continue
+		}
+
+		st, err := NewSchedulableTask(t)
+		if err != nil {
+			s.log.Error("Failed to schedule leased task", zap.String("taskID", t.ID.String()), zap.Error(err))
+			continue
+		}
+		if err := s.sch.Schedule(st); err != nil {
+			s.log.Error("Failed to schedule leased task", zap.String("taskID", t.ID.String()), zap.Error(err))
+			continue
+		}
+		s.scheduled[t.ID] = t.UpdatedAt
+	}
+
+	// the tasks deleted or disabled on other nodes.
+	for id := range s.scheduled {
+		if listed[id] {
+			continue
+		}
+		if err := s.release(id); err != nil {
+			s.log.Error("Failed to release task", zap.String("taskID", id.String()), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// Run sends the heartbeat of the node every third of the TTL of its leases until ctx is done.
+// It then releases the tasks of the node and gives up their leases, for the other nodes to take them over.
+func (s *LeaseScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.leases.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Heartbeat(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error("Failed to renew task leases", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			s.stop()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *LeaseScheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.scheduled {
+		if err := s.release(id); err != nil {
+			s.log.Error("Failed to release task", zap.String("taskID", id.String()), zap.Error(err))
+		}
+	}
+
+	if err := s.leases.releaseAll(context.Background()); err != nil {
+		s.log.Error("Failed to give up task leases", zap.Error(err))
+	}
+}
+
+// findTasks lists the active tasks of ts.
+func (s *LeaseScheduler) findTasks(ctx context.Context) ([]*influxdb.Task, error) {
+	var active []*influxdb.Task
+	filter := influxdb.TaskFilter{}
+	for {
+		tasks, _, err := s.ts.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(tasks) == 0 {
+			return active, nil
+		}
+
+		for _, t := range tasks {
+			if t.Status == string(backend.TaskActive) {
+				active = append(active, t)
+			}
+		}
+		filter.After = &tasks[len(tasks)-1].ID
+	}
+}
@@ -0,0 +1,187 @@
+package coordinator
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap/zaptest"
+)
+
+type leaseSchedulerS struct {
+	scheduled map[scheduler.ID]bool
+}
+
+func (s *leaseSchedulerS) Schedule(task scheduler.Schedulable) error {
+	s.scheduled[task.ID()] = true
+	return nil
+}
+
+func (s *leaseSchedulerS) Release(taskID scheduler.ID) error {
+	delete(s.scheduled, taskID)
+	return nil
+}
+
+func (s *leaseSchedulerS) ids() []influxdb.ID {
+	ids := make([]influxdb.ID, 0, len(s.scheduled))
+	for id := range s.scheduled {
+		ids = append(ids, influxdb.ID(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+type countingExecutor struct {
+	runs map[scheduler.ID]int
+}
+
+func (e *countingExecutor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
+	e.runs[id]++
+	return nil
+}
+
+type leaseNode struct {
+	*LeaseScheduler
+	sch *leaseSchedulerS
+	ex  scheduler.Executor
+}
+
+func TestLeaseScheduler(t *testing.T) {
+	var (
+		store = inmem.NewKVStore()
+		now   = time.Unix(1000, 0)
+		clock = func() time.Time { return now }
+		tasks = []*influxdb.Task{
+			{ID: 1, Every: "1m", Status: "active"},
+			{ID: 2, Every: "1m", Status: "active"},
+			{ID: 3, Every: "1m", Status: "active"},
+			{ID: 4, Every: "1m", Status: "active"},
+			{ID: 5, Status: "active", DependsOn: []influxdb.ID{6}},
+			{ID: 6, Every: "1m", Status: "active"},
+			{ID: 7, Every: "1m", Status: "inactive"},
+		}
+		counter = &countingExecutor{runs: map[scheduler.ID]int{}}
+	)
+
+	ts := mock.NewTaskService()
+	ts.FindTasksFn = func(ctx context.Context, filter influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+		var found []*influxdb.Task
+		for _, task := range tasks {
+			if filter.After == nil || task.ID > *filter.After {
+				found = append(found, task)
+			}
+		}
+		return found, len(found), nil
+	}
+
+	newNode := func(id influxdb.ID) *leaseNode {
+		leases := NewLeases(store, id, WithLeaseClock(clock))
+		sch := &leaseSchedulerS{scheduled: map[scheduler.ID]bool{}}
+		return &leaseNode{
+			LeaseScheduler: NewLeaseScheduler(zaptest.NewLogger(t), leases, sch, ts),
+			sch:            sch,
+			ex:             leases.Executor(counter),
+		}
+	}
+	heartbeat := func(nodes ...*leaseNode) {
+		t.Helper()
+		for _, n := range nodes {
+			if err := n.Heartbeat(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// execute runs every task on every node, as if each node scheduled it.
+	execute := func(nodes ...*leaseNode) map[scheduler.ID]int {
+		counter.runs = map[scheduler.ID]int{}
+		for _, n := range nodes {
+			for _, task := range tasks {
+				n.ex.Execute(context.Background(), scheduler.ID(task.ID), now, now)
+			}
+		}
+		return counter.runs
+	}
+
+	a, b := newNode(100), newNode(200)
+
+	heartbeat(a)
+	if diff := cmp.Diff([]influxdb.ID{1, 2, 3, 4, 5, 6}, a.sch.ids()); diff != "" {
+		t.Fatalf("unexpected tasks scheduled on a single node: %s", diff)
+	}
+
+	// the second node gets its share of the tasks once the first one gave them up,
+	// and the tasks that depend on each other stay on the same node.
+	heartbeat(b, a, b)
+	if diff := cmp.Diff([]influxdb.ID{1, 2, 3}, a.sch.ids()); diff != "" {
+		t.Fatalf("unexpected tasks scheduled on the first node: %s", diff)
+	}
+	if diff := cmp.Diff([]influxdb.ID{4, 5, 6}, b.sch.ids()); diff != "" {
+		t.Fatalf("unexpected tasks scheduled on the second node: %s", diff)
+	}
+	if diff := cmp.Diff(map[scheduler.ID]int{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1}, execute(a, b)); diff != "" {
+		t.Fatalf("expected every active task to run once: %s", diff)
+	}
+
+	// the tasks of a node that is gone are taken over once its leases expired.
+	now = now.Add(DefaultLeaseTTL / 3)
+	heartbeat(a)
+	if diff := cmp.Diff([]influxdb.ID{1, 2, 3}, a.sch.ids()); diff != "" {
+		t.Fatalf("unexpected tasks taken over before the leases expired: %s", diff)
+	}
+	now = now.Add(DefaultLeaseTTL)
+	heartbeat(a)
+	if diff := cmp.Diff([]influxdb.ID{1, 2, 3, 4, 5, 6}, a.sch.ids()); diff != "" {
+		t.Fatalf("unexpected tasks after a node is gone: %s", diff)
+	}
+	if diff := cmp.Diff(map[scheduler.ID]int{1: 1, 2: 1, 3: 1, 4: 1, 5: 1, 6: 1}, execute(a, b)); diff != "" {
+		t.Fatalf("expected every active task to run once after a node is gone: %s", diff)
+	}
+
+	// a node coming back releases the tasks it lost.
+	heartbeat(b)
+	if diff := cmp.Diff([]influxdb.ID{}, b.sch.ids()); diff != "" {
+		t.Fatalf("unexpected tasks kept by a node that came back: %s", diff)
+	}
+
+	// a task is scheduled by the node it is created on if no other node holds it.
+	tasks = append(tasks, &influxdb.Task{ID: 8, Every: "1m", Status: "active"})
+	st, err := NewSchedulableTask(tasks[len(tasks)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Schedule(st); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Schedule(st); err != nil {
+		t.Fatal(err)
+	}
+	if !b.sch.scheduled[8] || a.sch.scheduled[8] {
+		t.Fatal("expected the created task to be scheduled by the node it was created on only")
+	}
+
+	// a released task is no longer leased.
+	if err := b.Release(8); err != nil {
+		t.Fatal(err)
+	}
+	if held, err := b.leases.Held(context.Background(), 8); err != nil || held {
+		t.Fatalf("expected the lease of a released task to be given up, got %v %v", held, err)
+	}
+
+	// a node that stops gives up its tasks to the other nodes right away.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a.Run(ctx)
+	if diff := cmp.Diff([]influxdb.ID{}, a.sch.ids()); diff != "" {
+		t.Fatalf("unexpected tasks scheduled on a stopped node: %s", diff)
+	}
+	heartbeat(b)
+	if diff := cmp.Diff([]influxdb.ID{1, 2, 3, 4, 5, 6, 8}, b.sch.ids()); diff != "" {
+		t.Fatalf("unexpected tasks after a node stopped: %s", diff)
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 var _ middleware.Coordinator = (*Coordinator)(nil)
 var _ Executor = (*executor.TaskExecutor)(nil)
+var _ scheduler.Dependent = SchedulableTask{}
 
 // DefaultLimit is the maximum number of tasks that a given taskd server can own
 const DefaultLimit = 1000
@@ -62,6 +63,15 @@ func (t SchedulableTask) LastScheduled() time.Time {
 	return t.lsc
 }
 
+// DependsOn returns the IDs of the tasks that trigger the Task
+func (t SchedulableTask) DependsOn() []scheduler.ID {
+	ids := make([]scheduler.ID, 0, len(t.Task.DependsOn))
+	for _, id := range t.Task.DependsOn {
+		ids = append(ids, scheduler.ID(id))
+	}
+	return ids
+}
+
 func WithLimitOpt(i int) CoordinatorOption {
 	return func(c *TaskCoordinator) {
 		c.limit = i
@@ -71,7 +81,7 @@ func WithLimitOpt(i int) CoordinatorOption {
 // NewSchedulableTask transforms an influxdb task to a schedulable task type
 func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 
-	if task.Cron == "" && task.Every == "" {
+	if task.Cron == "" && task.Every == "" && len(task.DependsOn) == 0 {
 		return SchedulableTask{}, errors.New("invalid cron or every")
 	}
 	effCron := task.EffectiveCron()
@@ -81,6 +91,10 @@ func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 	} else if !task.LatestCompleted.IsZero() {
 		ts = task.LatestCompleted
 	}
+	if effCron == "" {
+		// the task is only triggered by the tasks it depends on.
+		return SchedulableTask{Task: task, lsc: ts.UTC().Truncate(time.Second)}, nil
+	}
 	var sch scheduler.Schedule
 	var err error
 	sch, ts, err = scheduler.NewSchedule(effCron, ts)
@@ -252,3 +252,26 @@ func Test_Coordinator_Scheduler_Methods(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewSchedulableTask_DependsOn(t *testing.T) {
+	now := time.Now().UTC()
+	task := &influxdb.Task{ID: 3, CreatedAt: now, DependsOn: []influxdb.ID{1, 2}}
+
+	st, err := NewSchedulableTask(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.Schedule().IsZero() {
+		t.Error("expected task without cron or every to have a zero schedule")
+	}
+	if diff := cmp.Diff([]scheduler.ID{1, 2}, st.DependsOn()); diff != "" {
+		t.Errorf("unexpected dependencies %s", diff)
+	}
+	if st.LastScheduled() != now.Truncate(time.Second) {
+		t.Errorf("expected task LatestScheduled to be properly truncated but it was %s instead", st.LastScheduled())
+	}
+
+	if _, err := NewSchedulableTask(&influxdb.Task{ID: 4, CreatedAt: now}); err == nil {
+		t.Error("expected error for task without cron, every or dependencies")
+	}
+}
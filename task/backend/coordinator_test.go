@@ -15,6 +15,7 @@ var (
 	two   = influxdb.ID(2)
 	three = influxdb.ID(3)
 	four  = influxdb.ID(4)
+	five  = influxdb.ID(5)
 
 	aTime = time.Now().UTC()
 
@@ -22,12 +23,14 @@ var (
 	taskTwo   = &influxdb.Task{ID: two, Status: "active"}
 	taskThree = &influxdb.Task{ID: three, Status: "inactive"}
 	taskFour  = &influxdb.Task{ID: four}
+	taskFive  = &influxdb.Task{ID: five, Type: influxdb.TaskTypeDownsample, Status: "active", LatestCompleted: aTime.Add(-time.Hour)}
 
 	allTasks = map[influxdb.ID]*influxdb.Task{
 		one:   taskOne,
 		two:   taskTwo,
 		three: taskThree,
 		four:  taskFour,
+		five:  taskFive,
 	}
 )
 
@@ -40,6 +43,7 @@ func Test_NotifyCoordinatorOfCreated(t *testing.T) {
 			otherPages: map[influxdb.ID][]*influxdb.Task{
 				one:   []*influxdb.Task{taskTwo, taskThree},
 				three: []*influxdb.Task{taskFour},
+				four:  []*influxdb.Task{taskFive},
 			},
 		}
 	)
@@ -62,6 +66,7 @@ func Test_NotifyCoordinatorOfCreated(t *testing.T) {
 
 	if diff := cmp.Diff([]*influxdb.Task{
 		taskTwo,
+		taskFive,
 	}, coordinator.tasks); diff != "" {
 		t.Errorf("unexpected tasks sent to coordinator %v", diff)
 	}
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -14,10 +15,20 @@ import (
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
 	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"github.com/influxdata/influxdb/task/options"
 	"go.uber.org/zap"
 )
 
+const (
+	// defaultRetryDelay is the delay before the second attempt of a failed run of a task without a retryDelay option.
+	defaultRetryDelay = 10 * time.Second
+
+	// maxRetryDelay is the maximum delay before an attempt of a failed run, before jitter.
+	maxRetryDelay = time.Hour
+)
+
 var _ scheduler.Executor = (*TaskExecutor)(nil)
+var _ scheduler.FailureRecorder = (*TaskExecutor)(nil)
 
 type Promise interface {
 	ID() influxdb.ID
@@ -41,6 +52,13 @@ func MultiLimit(limits ...LimitFunc) LimitFunc {
 // LimitFunc is a function the executor will use to
 type LimitFunc func(*influxdb.Task, *influxdb.Run) error
 
+// CompletedFunc is a function the executor calls when a scheduled run of a task completes, with the error of the run.
+type CompletedFunc func(id scheduler.ID, scheduledFor time.Time, err error)
+
+// HeldFunc is a function the executor calls before starting an attempt of a retried run of the task id,
+// reporting whether the process holds the lease of the task.
+type HeldFunc func(ctx context.Context, id influxdb.ID) (bool, error)
+
 // NewExecutor creates a new task executor
 func NewExecutor(log *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, tcs backend.TaskControlService) (*TaskExecutor, *ExecutorMetrics) {
 	te := &TaskExecutor{
@@ -54,6 +72,8 @@ func NewExecutor(log *zap.Logger, qs query.QueryService, as influxdb.Authorizati
 		promiseQueue:    make(chan *promise, 1000),                                //TODO(lh): make this configurable
 		workerLimit:     make(chan struct{}, 100),                                 //TODO(lh): make this configurable
 		limitFunc:       func(*influxdb.Task, *influxdb.Run) error { return nil }, // noop
+		completedFunc:   func(scheduler.ID, time.Time, error) {},                  // noop
+		heldFunc:        func(context.Context, influxdb.ID) (bool, error) { return true, nil },
 	}
 
 	te.metrics = NewExecutorMetrics(te)
@@ -85,6 +105,10 @@ type TaskExecutor struct {
 
 	limitFunc LimitFunc
 
+	completedFunc CompletedFunc
+
+	heldFunc HeldFunc
+
 	// keep a pool of execution workers.
 	workerPool  sync.Pool
 	workerLimit chan struct{}
@@ -95,6 +119,19 @@ func (e *TaskExecutor) SetLimitFunc(l LimitFunc) {
 	e.limitFunc = l
 }
 
+// SetCompletedFunc sets the function called when a scheduled run completes.
+// It is used to trigger the runs of the tasks that depend on the task of the run.
+// Manual runs, such as the ones of backfills, do not trigger them.
+func (e *TaskExecutor) SetCompletedFunc(c CompletedFunc) {
+	e.completedFunc = c
+}
+
+// SetHeldFunc sets the function reporting whether the process holds the lease of a task.
+// The attempts of the retried runs of the tasks leased to other processes are started by them.
+func (e *TaskExecutor) SetHeldFunc(h HeldFunc) {
+	e.heldFunc = h
+}
+
 // Execute is a executor to satisfy the needs of tasks
 func (e *TaskExecutor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
 	_, err := e.PromisedExecute(ctx, id, scheduledFor, runAt)
@@ -108,6 +145,8 @@ func (e *TaskExecutor) Execute(ctx context.Context, id scheduler.ID, scheduledFo
 // We then start a worker to work the newly queued jobs.
 func (e *TaskExecutor) PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (Promise, error) {
 	iid := influxdb.ID(id)
+	e.startDueRetries(ctx, iid)
+
 	// create a run
 	p, err := e.createRun(ctx, iid, scheduledFor, runAt)
 	if err != nil {
@@ -153,6 +192,26 @@ func (e *TaskExecutor) ResumeCurrentRun(ctx context.Context, id influxdb.ID, run
 	return nil, influxdb.ErrRunNotFound
 }
 
+// RecordFailure creates a run of the task id for scheduledFor and fails it with err, without executing it.
+// It is used for the runs of a task of which a run of a task it depends on failed.
+func (e *TaskExecutor) RecordFailure(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time, err error) error {
+	iid := influxdb.ID(id)
+	r, rerr := e.tcs.CreateRun(ctx, iid, scheduledFor.UTC(), runAt.UTC())
+	if rerr != nil {
+		return rerr
+	}
+
+	e.tcs.AddRunLog(ctx, iid, r.ID, time.Now().UTC(), err.Error())
+	e.tcs.UpdateRunState(ctx, iid, r.ID, time.Now().UTC(), backend.RunFail)
+	e.tcs.AddRunLog(ctx, iid, r.ID, time.Now().UTC(), fmt.Sprintf("Completed(%s)", backend.RunFail.String()))
+	if _, ferr := e.tcs.FinishRun(ctx, iid, r.ID); ferr != nil {
+		e.log.Error("Failed to finish run", zap.String("taskID", iid.String()), zap.String("runID", r.ID.String()), zap.Error(ferr))
+	}
+
+	e.completedFunc(id, r.ScheduledFor, err)
+	return nil
+}
+
 func (e *TaskExecutor) createRun(ctx context.Context, id influxdb.ID, scheduledFor time.Time, runAt time.Time) (*promise, error) {
 	r, err := e.tcs.CreateRun(ctx, id, scheduledFor.UTC(), runAt.UTC())
 	if err != nil {
@@ -209,12 +268,19 @@ func (e *TaskExecutor) createPromise(ctx context.Context, run *influxdb.Run) (*p
 		return nil, err
 	}
 
+	// runs created before they were retried are first attempts.
+	attempt := run.Attempt
+	if attempt < 1 {
+		attempt = 1
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	// create promise
 	p := &promise{
 		run:        run,
 		task:       t,
 		auth:       t.Authorization,
+		attempt:    attempt,
 		createdAt:  time.Now().UTC(),
 		done:       make(chan struct{}),
 		ctx:        ctx,
@@ -282,6 +348,7 @@ func (w *worker) work() {
 				w.te.tcs.UpdateRunState(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), backend.RunCanceled)
 				prom.err = influxdb.ErrRunCanceled
 				close(prom.done)
+				w.te.completed(prom, prom.err)
 				return
 			case <-time.After(time.Second):
 			}
@@ -351,9 +418,169 @@ func (w *worker) finish(p *promise, rs backend.RunStatus, err error) {
 		w.te.log.Debug("Completed successfully", zap.String("taskID", p.task.ID.String()))
 	}
 
+	var (
+		retry *influxdb.Run
+		delay time.Duration
+	)
+	if err != nil {
+		retry, delay = w.te.queueRetry(p, err)
+	}
+
 	if _, err := w.te.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID); err != nil {
 		w.te.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
 	}
+
+	if retry != nil {
+		// the run completes with its last attempt.
+		w.te.awaitRetry(p, retry, delay)
+		return
+	}
+
+	w.te.completed(p, err)
+}
+
+// completed calls the completed func with err for the run of the promise, if it is a scheduled run
+// or an attempt of one. Manual runs are requested, unlike scheduled runs.
+func (e *TaskExecutor) completed(p *promise, err error) {
+	if !p.run.RequestedAt.IsZero() {
+		return
+	}
+	e.completedFunc(scheduler.ID(p.task.ID), p.run.ScheduledFor, err)
+}
+
+// nextAttempt returns the delay before the next attempt of the failed run of the promise,
+// the number of attempts of its task's runs and false if the run is not to be attempted again.
+// The delay is doubled for every attempt, and a random jitter is added to it.
+func (e *TaskExecutor) nextAttempt(p *promise, err error) (time.Duration, int64, bool) {
+	if p.ctx.Err() != nil || backend.IsUnrecoverable(err) {
+		return 0, 0, false
+	}
+
+	// a script of which the options cannot be parsed fails every attempt.
+	opts, oerr := options.FromScript(p.task.Flux)
+	if oerr != nil || opts.Retry == nil || int64(p.attempt) >= *opts.Retry {
+		return 0, 0, false
+	}
+
+	now := time.Now()
+	delay := defaultRetryDelay
+	if opts.RetryDelay != nil {
+		if d, err := opts.RetryDelay.DurationFrom(now); err == nil {
+			delay = d
+		}
+	}
+	for i := 1; i < p.attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	if opts.RetryJitter != nil {
+		if j, err := opts.RetryJitter.DurationFrom(now); err == nil && j > 0 {
+			delay += time.Duration(rand.Int63n(int64(j)))
+		}
+	}
+
+	return delay, *opts.Retry, true
+}
+
+// queueRetry queues the next attempt of the run of the promise that failed with err,
+// and returns it with the delay before it is started.
+// It returns a nil run if the run is not to be attempted again.
+func (e *TaskExecutor) queueRetry(p *promise, err error) (*influxdb.Run, time.Duration) {
+	delay, attempts, ok := e.nextAttempt(p, err)
+	if !ok {
+		if p.attempt > 1 {
+			e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Failed after %d attempts", p.attempt))
+		}
+		return nil, 0
+	}
+
+	// the attempt is queued with the manual runs of the task, so that it is not lost
+	// if the process stops before it is started.
+	r, qerr := e.tcs.QueueRetry(p.ctx, p.task.ID, p.run.ID, time.Now().Add(delay))
+	if qerr != nil {
+		e.log.Error("Failed to retry run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(qerr))
+		e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Failed to retry run: %s", qerr.Error()))
+		return nil, 0
+	}
+
+	e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Retrying in %s as run %s, attempt %d of %d", delay, r.ID, r.Attempt, attempts))
+	return r, delay
+}
+
+// awaitRetry starts the queued attempt run of the failed run of the promise after the delay.
+// An attempt that is not started then, because the process does not hold the lease of the task,
+// or stopped in the meantime, is started with the next scheduled run of the task.
+func (e *TaskExecutor) awaitRetry(p *promise, run *influxdb.Run, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		// the attempt outlives the context of the run, which can be the one of a request,
+		// but keeps its authorizer.
+		ctx := context.Background()
+		if a, err := icontext.GetAuthorizer(p.ctx); err == nil {
+			ctx = icontext.SetAuthorizer(ctx, a)
+		}
+
+		if err := e.startRetry(ctx, run.TaskID, run.ID); err != nil {
+			e.log.Error("Failed to retry run", zap.String("taskID", run.TaskID.String()), zap.String("runID", run.ID.String()), zap.Error(err))
+		}
+	})
+}
+
+// startDueRetries starts the queued attempts of the retried runs of the task id that are due.
+func (e *TaskExecutor) startDueRetries(ctx context.Context, id influxdb.ID) {
+	runs, err := e.tcs.ManualRuns(ctx, id)
+	if err != nil {
+		e.log.Error("Failed to find retried runs", zap.String("taskID", id.String()), zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	for _, r := range runs {
+		// manual runs which are not retries are started by the coordinator.
+		if r.Attempt < 2 || r.RunAt.After(now) {
+			continue
+		}
+		if err := e.startRetry(ctx, id, r.ID); err != nil {
+			e.log.Error("Failed to retry run", zap.String("taskID", id.String()), zap.String("runID", r.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+// startRetry starts the queued attempt runID of a retried run of the task id,
+// if the process holds the lease of the task and the task is active.
+// Otherwise the attempt stays queued.
+func (e *TaskExecutor) startRetry(ctx context.Context, id, runID influxdb.ID) error {
+	if held, err := e.heldFunc(ctx, id); err != nil || !held {
+		return err
+	}
+
+	t, err := e.ts.FindTaskByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.Status != string(backend.TaskActive) {
+		return nil
+	}
+
+	r, err := e.tcs.StartManualRun(ctx, id, runID)
+	if err != nil {
+		// the attempt was already started.
+		if err == influxdb.ErrRunNotFound {
+			return nil
+		}
+		return err
+	}
+	e.tcs.AddRunLog(ctx, id, r.ID, time.Now().UTC(), fmt.Sprintf("Attempt %d, retry of the run scheduled for %s", r.Attempt, r.ScheduledFor.Format(time.RFC3339)))
+
+	if _, err := e.createPromise(ctx, r); err != nil {
+		return err
+	}
+
+	e.startWorker()
+	e.metrics.retriedRunsCounter.WithLabelValues(id.String()).Inc()
+	return nil
 }
 
 func (w *worker) executeQuery(p *promise) {
@@ -443,6 +670,9 @@ type promise struct {
 	done chan struct{}
 	err  error
 
+	// attempt is the attempt of the run of the promise, the first being 1.
+	attempt int
+
 	createdAt time.Time
 	startedAt time.Time
 
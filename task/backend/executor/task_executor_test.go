@@ -70,12 +70,16 @@ func TestTaskExecutor(t *testing.T) {
 	t.Run("QuerySuccess", testQuerySuccess)
 	t.Run("QueryFailure", testQueryFailure)
 	t.Run("ManualRun", testManualRun)
+	t.Run("RetryManualRun", testRetryManualRun)
 	t.Run("ResumeRun", testResumingRun)
 	t.Run("WorkerLimit", testWorkerLimit)
 	t.Run("LimitFunc", testLimitFunc)
 	t.Run("Metrics", testMetrics)
 	t.Run("IteratorFailure", testIteratorFailure)
 	t.Run("ErrorHandling", testErrorHandling)
+	t.Run("RecordFailure", testRecordFailure)
+	t.Run("Retry", testRetry)
+	t.Run("RetryLease", testRetryLease)
 }
 
 func testQuerySuccess(t *testing.T) {
@@ -182,6 +186,240 @@ func testQueryFailure(t *testing.T) {
 	}
 }
 
+func testRecordFailure(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type completion struct {
+		id           scheduler.ID
+		scheduledFor time.Time
+		err          error
+	}
+	completed := make(chan completion, 2)
+	tes.ex.SetCompletedFunc(func(id scheduler.ID, scheduledFor time.Time, err error) {
+		completed <- completion{id: id, scheduledFor: scheduledFor, err: err}
+	})
+
+	upstreamErr := &scheduler.ErrUpstreamFailed{ID: 1, Err: errors.New("query failed")}
+	if err := tes.ex.RecordFailure(ctx, scheduler.ID(task.ID), time.Unix(63, 0), time.Unix(66, 0), upstreamErr); err != nil {
+		t.Fatal(err)
+	}
+
+	run := tes.tcs.run
+	if run == nil {
+		t.Fatal("expected the failed run to be finished")
+	}
+	if run.Status != backend.RunFail.String() {
+		t.Fatalf("expected run to be failed, got %s", run.Status)
+	}
+	if len(run.Log) == 0 || run.Log[0].Message != upstreamErr.Error() {
+		t.Fatalf("expected the upstream failure in the run log, got %v", run.Log)
+	}
+
+	task, err = tes.i.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.LastRunError != upstreamErr.Error() {
+		t.Fatalf("expected the upstream failure as the last run error, got %q", task.LastRunError)
+	}
+
+	select {
+	case c := <-completed:
+		if c.id != scheduler.ID(task.ID) || !c.scheduledFor.Equal(time.Unix(63, 0)) || c.err != upstreamErr {
+			t.Fatalf("unexpected completion %+v", c)
+		}
+	default:
+		t.Fatal("expected the failed run to be completed")
+	}
+
+	// runs that are executed are completed too.
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.SucceedQuery(script)
+	<-promise.Done()
+
+	select {
+	case c := <-completed:
+		if c.id != scheduler.ID(task.ID) || !c.scheduledFor.Equal(time.Unix(123, 0)) || c.err != nil {
+			t.Fatalf("unexpected completion %+v", c)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the run to be completed")
+	}
+}
+
+func testRetry(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(`
+option task = {
+			name: %q,
+			every: 1m,
+			retry: 2,
+			retryDelay: 1ms,
+}
+
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completed := make(chan error, 2)
+	tes.ex.SetCompletedFunc(func(id scheduler.ID, scheduledFor time.Time, err error) {
+		completed <- err
+	})
+
+	// a failed run is attempted again, and completes with its last attempt.
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.FailQuery(script, errors.New("blargyblargblarg"))
+	<-promise.Done()
+
+	if promise.Error() == nil {
+		t.Fatal("expected the first attempt to fail")
+	}
+	if run := tes.tcs.run; !strings.Contains(run.Log[len(run.Log)-1].Message, "Retrying in") {
+		t.Fatalf("expected the retry in the run log, got %v", run.Log)
+	}
+
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.SucceedQuery(script)
+
+	select {
+	case err := <-completed:
+		if err != nil {
+			t.Fatalf("expected the run to complete with its successful attempt, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the run to be completed")
+	}
+	if run := tes.tcs.run; run.Status != backend.RunSuccess.String() || run.Attempt != 2 || !strings.HasPrefix(run.Log[0].Message, "Attempt 2, retry of the run scheduled for 1970-01-01T00:02:03Z") {
+		t.Fatalf("expected the second attempt to succeed, got %s %d %v", run.Status, run.Attempt, run.Log)
+	}
+
+	// the run fails once it was attempted as many times as the retry option.
+	if _, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		tes.svc.WaitForQueryLive(t, script)
+		tes.svc.FailQuery(script, errors.New("blargyblargblarg"))
+	}
+
+	select {
+	case err := <-completed:
+		if err == nil {
+			t.Fatal("expected the run to complete with the error of its last attempt")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the run to be completed")
+	}
+	if run := tes.tcs.run; !strings.Contains(run.Log[len(run.Log)-1].Message, "Failed after 2 attempts") {
+		t.Fatalf("expected the failure in the run log, got %v", run.Log)
+	}
+
+	task, err = tes.i.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.LastRunStatus != backend.RunFail.String() || !strings.Contains(task.LastRunError, "blargyblargblarg") {
+		t.Fatalf("expected the failure as the last run of the task, got %s %q", task.LastRunStatus, task.LastRunError)
+	}
+}
+
+func testRetryLease(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(`
+option task = {
+			name: %q,
+			every: 1m,
+			retry: 2,
+			retryDelay: 1ms,
+}
+
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu   sync.Mutex
+		held bool
+	)
+	tes.ex.SetHeldFunc(func(ctx context.Context, id influxdb.ID) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return held, nil
+	})
+
+	// the attempt of a failed run of a task of which the lease is not held stays queued.
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.FailQuery(script, errors.New("blargyblargblarg"))
+	<-promise.Done()
+	time.Sleep(10 * time.Millisecond)
+
+	runs, err := tes.i.ManualRuns(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Attempt != 2 || !runs[0].ScheduledFor.Equal(time.Unix(123, 0)) {
+		t.Fatalf("expected the second attempt of the run to be queued, got %v", runs)
+	}
+
+	// it is started with the next scheduled run of the task once the lease is held.
+	mu.Lock()
+	held = true
+	mu.Unlock()
+	if _, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(183, 0), time.Unix(186, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err = tes.i.ManualRuns(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 0 {
+		t.Fatalf("expected the queued attempt to be started, got %v", runs)
+	}
+
+	running, err := tes.i.CurrentlyRunning(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var retried bool
+	for _, r := range running {
+		retried = retried || (r.Attempt == 2 && r.ScheduledFor.Equal(time.Unix(123, 0)))
+	}
+	if len(running) != 2 || !retried {
+		t.Fatalf("expected the attempt to run with the scheduled run, got %v", running)
+	}
+}
+
 func testManualRun(t *testing.T) {
 	t.Parallel()
 	tes := taskExecutorSystem(t)
@@ -193,6 +431,11 @@ func testManualRun(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	completed := make(chan time.Time, 1)
+	tes.ex.SetCompletedFunc(func(id scheduler.ID, scheduledFor time.Time, err error) {
+		completed <- scheduledFor
+	})
+
 	manualRun, err := tes.i.ForceRun(ctx, task.ID, 123)
 	if err != nil {
 		t.Fatal(err)
@@ -224,9 +467,75 @@ func testManualRun(t *testing.T) {
 	tes.svc.WaitForQueryLive(t, script)
 	tes.svc.SucceedQuery(script)
 
+	<-promise.Done()
 	if got := promise.Error(); got != nil {
 		t.Fatal(got)
 	}
+
+	// the runs of the tasks depending on the task are only triggered by its scheduled runs.
+	select {
+	case sf := <-completed:
+		t.Fatalf("expected the manual run not to be completed as a scheduled run, got %s", sf)
+	default:
+	}
+}
+
+func testRetryManualRun(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completed := make(chan time.Time, 2)
+	tes.ex.SetCompletedFunc(func(id scheduler.ID, scheduledFor time.Time, err error) {
+		completed <- scheduledFor
+	})
+
+	manualRun, err := tes.i.ForceRun(ctx, task.ID, 123)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.ManualRun(ctx, task.ID, manualRun.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, script)
+
+	retry, err := tes.i.RetryRun(ctx, task.ID, promise.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.RequestedAt.IsZero() {
+		t.Fatal("expected the retry of the run to be requested")
+	}
+
+	tes.svc.SucceedQuery(script)
+	<-promise.Done()
+
+	promise, err = tes.ex.ManualRun(ctx, task.ID, retry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.SucceedQuery(script)
+
+	<-promise.Done()
+	if got := promise.Error(); got != nil {
+		t.Fatal(got)
+	}
+
+	// neither the manual run nor its retry trigger the runs of the tasks depending on the task.
+	select {
+	case sf := <-completed:
+		t.Fatalf("expected the retried manual run not to be completed as a scheduled run, got %s", sf)
+	default:
+	}
 }
 
 func testResumingRun(t *testing.T) {
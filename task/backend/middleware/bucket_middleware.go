@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+)
+
+// CoordinatingBucketService acts as a BucketService decorator that handles coordinating the api request
+// with the required task control actions of the downsample policies of buckets.
+type CoordinatingBucketService struct {
+	influxdb.BucketService
+	coordinator Coordinator
+	taskService influxdb.TaskService
+	Now         func() time.Time
+}
+
+// NewBucketService constructs a new coordinating bucket service
+func NewBucketService(bs influxdb.BucketService, ts influxdb.TaskService, coordinator Coordinator) *CoordinatingBucketService {
+	c := &CoordinatingBucketService{
+		BucketService: bs,
+		taskService:   ts,
+		coordinator:   coordinator,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+
+	return c
+}
+
+// CreateBucket creates a bucket and publishes the task of its downsample policy so it can be scheduled.
+func (bs *CoordinatingBucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) error {
+	if err := bs.BucketService.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+
+	taskID := downsampleTaskID(b)
+	if !taskID.Valid() {
+		return nil
+	}
+
+	t, err := bs.taskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := bs.coordinator.TaskCreated(ctx, t); err != nil {
+		if derr := bs.BucketService.DeleteBucket(ctx, b.ID); derr != nil {
+			return fmt.Errorf("schedule task failed: %s\n\tcleanup also failed: %s", err, derr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// UpdateBucket updates a bucket and publishes the changes to the task of its downsample policy.
+func (bs *CoordinatingBucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	from, err := bs.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromTask *influxdb.Task
+	if taskID := downsampleTaskID(from); taskID.Valid() {
+		fromTask, err = bs.taskService.FindTaskByID(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	to, err := bs.BucketService.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return to, err
+	}
+
+	toTaskID := downsampleTaskID(to)
+	if !toTaskID.Valid() {
+		if fromTask != nil {
+			return to, bs.coordinator.TaskDeleted(ctx, fromTask.ID)
+		}
+		return to, nil
+	}
+
+	toTask, err := bs.taskService.FindTaskByID(ctx, toTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromTask == nil || fromTask.ID != toTask.ID {
+		if fromTask != nil {
+			if err := bs.coordinator.TaskDeleted(ctx, fromTask.ID); err != nil {
+				return to, err
+			}
+		}
+		return to, bs.coordinator.TaskCreated(ctx, toTask)
+	}
+
+	return to, bs.coordinator.TaskUpdated(ctx, fromTask, toTask)
+}
+
+// DeleteBucket deletes the bucket and publishes the removal of the task of its downsample policy.
+func (bs *CoordinatingBucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error {
+	b, err := bs.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := bs.BucketService.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+
+	if taskID := downsampleTaskID(b); taskID.Valid() {
+		return bs.coordinator.TaskDeleted(ctx, taskID)
+	}
+	return nil
+}
+
+func downsampleTaskID(b *influxdb.Bucket) influxdb.ID {
+	if b == nil || b.DownsamplePolicy == nil {
+		return 0
+	}
+	return b.DownsamplePolicy.TaskID
+}
+
+// maxDownsampleRecomputeRuns is the maximum number of runs forced for a
+// single downsampled bucket when data is deleted from its source.
+const maxDownsampleRecomputeRuns = 1000
+
+// DownsampleDeleteService acts as a DeleteService decorator that recomputes the windows
+// of the buckets downsampled from the bucket that data is deleted from.
+type DownsampleDeleteService struct {
+	influxdb.DeleteService
+	bucketService influxdb.BucketService
+	taskService   influxdb.TaskService
+}
+
+// NewDeleteService constructs a new downsample recomputing delete service
+func NewDeleteService(ds influxdb.DeleteService, bs influxdb.BucketService, ts influxdb.TaskService) *DownsampleDeleteService {
+	return &DownsampleDeleteService{
+		DeleteService: ds,
+		bucketService: bs,
+		taskService:   ts,
+	}
+}
+
+type downsampleRecompute struct {
+	bucket      *influxdb.Bucket
+	start, stop int64
+	runs        []int64
+}
+
+// DeleteBucketRangePredicate deletes the data from the bucket, then deletes the windows
+// overlapping the deleted range from the downsampled buckets and forces the runs of their
+// tasks computing those windows again.
+func (s *DownsampleDeleteService) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
+	recomputes, err := s.findRecomputes(ctx, orgID, bucketID, min, max)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DeleteService.DeleteBucketRangePredicate(ctx, orgID, bucketID, min, max, pred); err != nil {
+		return err
+	}
+
+	for _, r := range recomputes {
+		// The aggregated points are written at the stop of their window.
+		if err := s.DeleteService.DeleteBucketRangePredicate(ctx, orgID, r.bucket.ID, r.start+1, r.stop, pred); err != nil {
+			return err
+		}
+		for _, scheduledFor := range r.runs {
+			if _, err := s.taskService.ForceRun(ctx, r.bucket.DownsamplePolicy.TaskID, scheduledFor); err != nil && err != influxdb.ErrTaskRunAlreadyQueued {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *DownsampleDeleteService) findRecomputes(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64) ([]downsampleRecompute, error) {
+	buckets, _, err := s.bucketService.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	var recomputes []downsampleRecompute
+	for _, b := range buckets {
+		p := b.DownsamplePolicy
+		if p == nil || p.SourceBucketID != bucketID || !p.TaskID.Valid() {
+			continue
+		}
+
+		t, err := s.taskService.FindTaskByID(ctx, p.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if t.Status != string(backend.TaskActive) || t.LatestCompleted.IsZero() {
+			continue
+		}
+
+		start, stop, runs := downsampleRecomputeRuns(*p, t.CreatedAt, t.LatestCompleted, min, max)
+		if len(runs) == 0 {
+			continue
+		}
+		if len(runs) > maxDownsampleRecomputeRuns {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("deleting the range requires recomputing %d windows of downsampled bucket %q; delete a shorter range", len(runs), b.Name),
+			}
+		}
+		recomputes = append(recomputes, downsampleRecompute{bucket: b, start: start, stop: stop, runs: runs})
+	}
+	return recomputes, nil
+}
+
+// downsampleRecomputeRuns returns the range of the windows of the policy p that overlap the
+// deleted range [min, max] and were computed by a task created at createdAt that completed
+// its runs up to latestCompleted, along with the times, in unix seconds, of the runs computing
+// those windows again.
+func downsampleRecomputeRuns(p influxdb.DownsamplePolicy, createdAt, latestCompleted time.Time, min, max int64) (start, stop int64, runs []int64) {
+	every := int64(p.Every.Duration)
+	lookback := int64(p.EffectiveLookback())
+
+	if lo := createdAt.UnixNano() - lookback; min < lo {
+		min = lo
+	}
+	if hi := latestCompleted.UnixNano(); max > hi {
+		max = hi
+	}
+	if min > max {
+		return 0, 0, nil
+	}
+
+	start = floorTime(min, every)
+	stop = floorTime(max, every) + every
+	if last := floorTime(latestCompleted.UnixNano(), every); stop > last {
+		stop = last
+	}
+	if stop <= start {
+		return 0, 0, nil
+	}
+
+	for t := stop; t > start; t -= lookback {
+		runs = append(runs, t/int64(time.Second))
+	}
+	return start, stop, runs
+}
+
+// floorTime truncates t to a multiple of d, rounding towards negative infinity.
+func floorTime(t, d int64) int64 {
+	r := t % d
+	if r < 0 {
+		r += d
+	}
+	return t - r
+}
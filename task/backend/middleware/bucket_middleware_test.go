@@ -0,0 +1,273 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/middleware"
+)
+
+func newBucketSvcStack() (mockedSvc, *mock.BucketService, *middleware.CoordinatingBucketService) {
+	msvcs := newMockServices()
+	bs := mock.NewBucketService()
+	return msvcs, bs, middleware.NewBucketService(bs, msvcs.taskSvc, msvcs.pipingCoordinator)
+}
+
+func downsampledBucket(id, taskID influxdb.ID) *influxdb.Bucket {
+	return &influxdb.Bucket{
+		ID:    id,
+		OrgID: 1,
+		Name:  fmt.Sprintf("bucket-%s", id),
+		DownsamplePolicy: &influxdb.DownsamplePolicy{
+			SourceBucketID: 100,
+			Every:          influxdb.Duration{Duration: time.Hour},
+			Aggregates:     influxdb.DownsampleAggregates{Float: "mean"},
+			TaskID:         taskID,
+		},
+	}
+}
+
+func TestBucketCreate(t *testing.T) {
+	mocks, bs, bucketService := newBucketSvcStack()
+	ch := mocks.pipingCoordinator.taskCreatedChan()
+
+	bs.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+		b.DownsamplePolicy.TaskID = 4
+		return nil
+	}
+
+	b := downsampledBucket(2, 0)
+	if err := bucketService.CreateBucket(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case task := <-ch:
+		if task.ID != 4 {
+			t.Fatalf("task sent to coordinator doesn't match expected")
+		}
+	default:
+		t.Fatal("didn't receive task")
+	}
+
+	mocks.pipingCoordinator.err = fmt.Errorf("bad")
+	bs.DeleteBucketFn = func(context.Context, influxdb.ID) error { return fmt.Errorf("AARGH") }
+
+	err := bucketService.CreateBucket(context.Background(), downsampledBucket(2, 0))
+	if err.Error() != "schedule task failed: bad\n\tcleanup also failed: AARGH" {
+		t.Fatal(err)
+	}
+}
+
+func TestBucketCreateWithoutPolicy(t *testing.T) {
+	mocks, _, bucketService := newBucketSvcStack()
+	ch := mocks.pipingCoordinator.taskCreatedChan()
+
+	if err := bucketService.CreateBucket(context.Background(), &influxdb.Bucket{Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ch:
+		t.Fatal("received task for bucket without downsample policy")
+	default:
+	}
+}
+
+func TestBucketUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		from, to    *influxdb.Bucket
+		wantCreated influxdb.ID
+		wantUpdated influxdb.ID
+		wantDeleted influxdb.ID
+	}{
+		{
+			name:        "add policy",
+			from:        &influxdb.Bucket{ID: 2},
+			to:          downsampledBucket(2, 10),
+			wantCreated: 10,
+		},
+		{
+			name:        "update policy",
+			from:        downsampledBucket(2, 10),
+			to:          downsampledBucket(2, 10),
+			wantUpdated: 10,
+		},
+		{
+			name:        "remove policy",
+			from:        downsampledBucket(2, 10),
+			to:          &influxdb.Bucket{ID: 2},
+			wantDeleted: 10,
+		},
+		{
+			name: "no policy",
+			from: &influxdb.Bucket{ID: 2},
+			to:   &influxdb.Bucket{ID: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks, bs, bucketService := newBucketSvcStack()
+			created := mocks.pipingCoordinator.taskCreatedChan()
+			updated := mocks.pipingCoordinator.taskUpdatedChan()
+			deleted := mocks.pipingCoordinator.taskDeletedChan()
+
+			bs.FindBucketByIDFn = func(context.Context, influxdb.ID) (*influxdb.Bucket, error) { return tt.from, nil }
+			bs.UpdateBucketFn = func(context.Context, influxdb.ID, influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+				return tt.to, nil
+			}
+
+			if _, err := bucketService.UpdateBucket(context.Background(), 2, influxdb.BucketUpdate{}); err != nil {
+				t.Fatal(err)
+			}
+
+			var gotCreated, gotUpdated, gotDeleted influxdb.ID
+			select {
+			case task := <-created:
+				gotCreated = task.ID
+			default:
+			}
+			select {
+			case task := <-updated:
+				gotUpdated = task.ID
+			default:
+			}
+			select {
+			case id := <-deleted:
+				gotDeleted = id
+			default:
+			}
+
+			if gotCreated != tt.wantCreated || gotUpdated != tt.wantUpdated || gotDeleted != tt.wantDeleted {
+				t.Fatalf("unexpected coordination: created %s, updated %s, deleted %s", gotCreated, gotUpdated, gotDeleted)
+			}
+		})
+	}
+}
+
+func TestBucketDelete(t *testing.T) {
+	mocks, bs, bucketService := newBucketSvcStack()
+	ch := mocks.pipingCoordinator.taskDeletedChan()
+
+	bs.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return downsampledBucket(id, 12), nil
+	}
+
+	if err := bucketService.DeleteBucket(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case id := <-ch:
+		if id != 12 {
+			t.Fatalf("task sent to coordinator doesn't match expected")
+		}
+	default:
+		t.Fatal("didn't receive task")
+	}
+}
+
+func TestDownsampleDelete(t *testing.T) {
+	mocks := newMockServices()
+	bs := mock.NewBucketService()
+
+	bs.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		other := downsampledBucket(3, 13)
+		other.DownsamplePolicy.SourceBucketID = 101
+		return []*influxdb.Bucket{{ID: 100}, downsampledBucket(2, 12), other}, 3, nil
+	}
+
+	now := time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC)
+	mocks.taskSvc.FindTaskByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{
+			ID:              id,
+			Status:          string(backend.TaskActive),
+			CreatedAt:       now.Add(-24 * time.Hour),
+			LatestCompleted: now,
+		}, nil
+	}
+
+	var forced []int64
+	mocks.taskSvc.ForceRunFn = func(_ context.Context, id influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+		if id != 12 {
+			t.Fatalf("forced run of unexpected task %s", id)
+		}
+		forced = append(forced, scheduledFor)
+		if len(forced) == 2 {
+			return nil, influxdb.ErrTaskRunAlreadyQueued
+		}
+		return &influxdb.Run{}, nil
+	}
+
+	type deletion struct {
+		bucketID influxdb.ID
+		min, max int64
+	}
+	var deleted []deletion
+	ds := mock.NewDeleteService()
+	ds.DeleteBucketRangePredicateF = func(_ context.Context, _, bucketID influxdb.ID, min, max int64, _ influxdb.Predicate) error {
+		deleted = append(deleted, deletion{bucketID: bucketID, min: min, max: max})
+		return nil
+	}
+
+	deleteService := middleware.NewDeleteService(ds, bs, mocks.taskSvc)
+
+	min := now.Add(-150 * time.Minute).UnixNano()
+	max := now.Add(-90 * time.Minute).UnixNano()
+	if err := deleteService.DeleteBucketRangePredicate(context.Background(), 1, 100, min, max, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	start := now.Add(-3 * time.Hour).UnixNano()
+	stop := now.Add(-time.Hour).UnixNano()
+	wantDeleted := []deletion{
+		{bucketID: 100, min: min, max: max},
+		{bucketID: 2, min: start + 1, max: stop},
+	}
+	if !reflect.DeepEqual(deleted, wantDeleted) {
+		t.Fatalf("unexpected deletions: got %v, want %v", deleted, wantDeleted)
+	}
+
+	wantForced := []int64{now.Add(-time.Hour).Unix(), now.Add(-2 * time.Hour).Unix()}
+	if !reflect.DeepEqual(forced, wantForced) {
+		t.Fatalf("unexpected forced runs: got %v, want %v", forced, wantForced)
+	}
+}
+
+func TestDownsampleDeleteTooManyRuns(t *testing.T) {
+	mocks := newMockServices()
+	bs := mock.NewBucketService()
+	bs.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return []*influxdb.Bucket{downsampledBucket(2, 12)}, 1, nil
+	}
+
+	now := time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC)
+	mocks.taskSvc.FindTaskByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{
+			ID:              id,
+			Status:          string(backend.TaskActive),
+			CreatedAt:       now.Add(-365 * 24 * time.Hour),
+			LatestCompleted: now,
+		}, nil
+	}
+
+	ds := mock.NewDeleteService()
+	ds.DeleteBucketRangePredicateF = func(context.Context, influxdb.ID, influxdb.ID, int64, int64, influxdb.Predicate) error {
+		t.Fatal("data deleted although its downsampled windows cannot be recomputed")
+		return nil
+	}
+
+	deleteService := middleware.NewDeleteService(ds, bs, mocks.taskSvc)
+	err := deleteService.DeleteBucketRangePredicate(context.Background(), 1, 100, 0, now.UnixNano(), nil)
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/options"
 )
 
 // Coordinator is a type which is used to react to
@@ -26,6 +27,9 @@ type CoordinatingTaskService struct {
 	coordinator Coordinator
 
 	now func() time.Time
+
+	// backfillPollInterval is the interval the queued runs of the backfills are published at.
+	backfillPollInterval time.Duration
 }
 
 // New constructs a new coordinating task service
@@ -36,6 +40,7 @@ func New(service influxdb.TaskService, coordinator Coordinator, opts ...Option)
 		now: func() time.Time {
 			return time.Now().UTC()
 		},
+		backfillPollInterval: time.Second,
 	}
 
 	for _, opt := range opts {
@@ -60,7 +65,7 @@ func (s *CoordinatingTaskService) CreateTask(ctx context.Context, tc influxdb.Ta
 		return t, err
 	}
 
-	return t, nil
+	return t, s.backfillOption(ctx, t)
 }
 
 // UpdateTask Updates a task and publishes the change so the task owner can act on the update
@@ -75,7 +80,41 @@ func (s *CoordinatingTaskService) UpdateTask(ctx context.Context, id influxdb.ID
 		return to, err
 	}
 
-	return to, s.coordinator.TaskUpdated(ctx, from, to)
+	if err := s.coordinator.TaskUpdated(ctx, from, to); err != nil {
+		return to, err
+	}
+
+	if to.Flux == from.Flux {
+		return to, nil
+	}
+	return to, s.backfillOption(ctx, to)
+}
+
+// backfillOption backfills the task over the span of its backfill option before now, if any,
+// at the concurrency of the task.
+func (s *CoordinatingTaskService) backfillOption(ctx context.Context, t *influxdb.Task) error {
+	// the options of the task were validated by the task system.
+	opt, err := options.FromScript(t.Flux)
+	if err != nil || opt.Backfill == nil {
+		return nil
+	}
+
+	stop := s.now()
+	backfill, err := opt.Backfill.DurationFrom(stop)
+	if err != nil {
+		return err
+	}
+
+	req := influxdb.BackfillRequest{
+		Start:       stop.Add(-backfill),
+		Stop:        stop,
+		Concurrency: 1,
+	}
+	if opt.Concurrency != nil {
+		req.Concurrency = int(*opt.Concurrency)
+	}
+	_, err = s.BackfillTask(ctx, t.ID, req)
+	return err
 }
 
 // DeleteTask delete the task and publishes the change, to allow the task owner to find out about this change faster.
@@ -125,3 +164,69 @@ func (s *CoordinatingTaskService) ForceRun(ctx context.Context, taskID influxdb.
 
 	return r, s.coordinator.RunForced(ctx, t, r)
 }
+
+// BackfillTask creates the backfill in the task system and publishes its runs as they are queued,
+// without waiting for the runs to be executed.
+func (s *CoordinatingTaskService) BackfillTask(ctx context.Context, taskID influxdb.ID, req influxdb.BackfillRequest) (*influxdb.Backfill, error) {
+	t, err := s.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.TaskService.BackfillTask(ctx, taskID, req)
+	if err != nil || req.DryRun {
+		return b, err
+	}
+
+	go s.publishBackfill(t, b)
+	return b, nil
+}
+
+// publishBackfill publishes the runs of the backfill as the task system queues them, until the
+// backfill finishes. The task system only queues the next runs of the backfill once the previous
+// ones finished, which bounds the runs executed at once to the concurrency of the backfill
+// whether or not publishing a run waits for its execution.
+func (s *CoordinatingTaskService) publishBackfill(t *influxdb.Task, b *influxdb.Backfill) {
+	// the runs outlive the request that created them.
+	ctx := context.Background()
+
+	ticker := time.NewTicker(s.backfillPollInterval)
+	defer ticker.Stop()
+
+	published := make(map[influxdb.ID]bool, b.Concurrency)
+	for {
+		for _, id := range b.Runs {
+			if published[id] {
+				continue
+			}
+			published[id] = true
+			go func(id influxdb.ID) {
+				// the outcome of the run is recorded with the run, and counted by the backfill.
+				_ = s.coordinator.RunForced(ctx, t, &influxdb.Run{ID: id, TaskID: t.ID})
+			}(id)
+		}
+		if b.Status != influxdb.BackfillStatusRunning {
+			return
+		}
+
+		<-ticker.C
+		backfills, err := s.TaskService.FindBackfills(ctx, t.ID)
+		if err != nil {
+			// the task was deleted.
+			return
+		}
+		b = findBackfill(backfills, b.ID)
+		if b == nil {
+			return
+		}
+	}
+}
+
+func findBackfill(backfills []*influxdb.Backfill, id influxdb.ID) *influxdb.Backfill {
+	for _, b := range backfills {
+		if b.ID == id {
+			return b
+		}
+	}
+	return nil
+}
@@ -322,3 +322,157 @@ func TestCoordinatingTaskService_ForceRun(t *testing.T) {
 		t.Fatal("didn't receive task update in time")
 	}
 }
+
+// forcingCoordinator is a coordinator which records the forced runs and returns
+// immediately, as the legacy coordinator does.
+type forcingCoordinator struct {
+	pipingCoordinator
+	forced chan platform.ID
+}
+
+func (c *forcingCoordinator) RunForced(ctx context.Context, task *platform.Task, run *platform.Run) error {
+	c.forced <- run.ID
+	return nil
+}
+
+func TestCoordinatingTaskService_BackfillTask(t *testing.T) {
+	// backfill is the backfill of the task system, of which the runs are queued by the test.
+	var (
+		mu       sync.Mutex
+		backfill *platform.Backfill
+	)
+	queue := func(status string, runs ...platform.ID) {
+		mu.Lock()
+		defer mu.Unlock()
+		b := *backfill
+		b.Status = status
+		b.Runs = append(append([]platform.ID{}, b.Runs...), runs...)
+		backfill = &b
+	}
+
+	ts := inmemTaskService().(*pmock.TaskService)
+	ts.BackfillTaskFn = func(ctx context.Context, id platform.ID, req platform.BackfillRequest) (*platform.Backfill, error) {
+		b := &platform.Backfill{ID: 1, TaskID: id, Concurrency: req.Concurrency}
+		if !req.DryRun {
+			b.Status = platform.BackfillStatusRunning
+			b.Runs = []platform.ID{1, 2}
+			mu.Lock()
+			backfill = b
+			mu.Unlock()
+		}
+		return b, nil
+	}
+	ts.FindBackfillsFn = func(ctx context.Context, id platform.ID) ([]*platform.Backfill, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return []*platform.Backfill{backfill}, nil
+	}
+	coord := &forcingCoordinator{forced: make(chan platform.ID, 4)}
+	middleware := middleware.New(ts, coord, middleware.WithBackfillPollInterval(time.Millisecond))
+
+	task, err := middleware.CreateTask(context.Background(), platform.TaskCreate{OrganizationID: 1, Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := middleware.BackfillTask(context.Background(), task.ID, platform.BackfillRequest{Concurrency: 2, DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := middleware.BackfillTask(context.Background(), task.ID, platform.BackfillRequest{Concurrency: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	forced := func() platform.ID {
+		t.Helper()
+		select {
+		case id := <-coord.forced:
+			return id
+		case <-time.After(time.Second):
+			t.Fatal("didn't force run in time")
+		}
+		return 0
+	}
+	notForced := func() {
+		t.Helper()
+		select {
+		case id := <-coord.forced:
+			t.Fatalf("forced run %s before the task system queued it", id)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// only the queued runs of the backfill are forced, although forcing a run does not wait for it.
+	if a, b := forced(), forced(); a+b != 3 {
+		t.Fatalf("expected the first two runs to be forced, got %s and %s", a, b)
+	}
+	notForced()
+
+	queue(platform.BackfillStatusRunning, 3)
+	if id := forced(); id != 3 {
+		t.Fatalf("expected run 3 to be forced, got %s", id)
+	}
+	notForced()
+
+	queue(platform.BackfillStatusSuccess, 4)
+	if id := forced(); id != 4 {
+		t.Fatalf("expected run 4 to be forced, got %s", id)
+	}
+	notForced()
+}
+
+func TestCoordinatingTaskService_BackfillOption(t *testing.T) {
+	now := time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)
+	reqs := make(chan platform.BackfillRequest, 2)
+
+	ts := inmemTaskService().(*pmock.TaskService)
+	ts.BackfillTaskFn = func(ctx context.Context, id platform.ID, req platform.BackfillRequest) (*platform.Backfill, error) {
+		reqs <- req
+		return &platform.Backfill{ID: 1, TaskID: id, Status: platform.BackfillStatusSuccess}, nil
+	}
+	middleware := middleware.New(ts, &pipingCoordinator{}, middleware.WithNowFunc(func() time.Time { return now }))
+
+	backfilled := func(want platform.BackfillRequest) {
+		t.Helper()
+		select {
+		case req := <-reqs:
+			if !req.Start.Equal(want.Start) || !req.Stop.Equal(want.Stop) || req.Concurrency != want.Concurrency {
+				t.Fatalf("got backfill %+v, want %+v", req, want)
+			}
+		default:
+			t.Fatal("expected the task to be backfilled")
+		}
+	}
+	notBackfilled := func() {
+		t.Helper()
+		select {
+		case req := <-reqs:
+			t.Fatalf("unexpected backfill %+v", req)
+		default:
+		}
+	}
+
+	const backfillScript = `option task = {name: "a task", every: 1h, concurrency: 2, backfill: 24h} from(bucket:"test") |> range(start:-1h)`
+	task, err := middleware.CreateTask(context.Background(), platform.TaskCreate{OrganizationID: 1, Flux: backfillScript})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backfilled(platform.BackfillRequest{Start: now.Add(-24 * time.Hour), Stop: now, Concurrency: 2})
+
+	status := string(backend.TaskInactive)
+	if _, err := middleware.UpdateTask(context.Background(), task.ID, platform.TaskUpdate{Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+	notBackfilled()
+
+	now = now.Add(time.Hour)
+	flux := backfillScript + ` |> filter(fn: (r) => r._measurement == "cpu")`
+	if _, err := middleware.UpdateTask(context.Background(), task.ID, platform.TaskUpdate{Flux: &flux}); err != nil {
+		t.Fatal(err)
+	}
+	backfilled(platform.BackfillRequest{Start: now.Add(-24 * time.Hour), Stop: now, Concurrency: 2})
+
+	if _, err := middleware.CreateTask(context.Background(), platform.TaskCreate{OrganizationID: 1, Flux: script}); err != nil {
+		t.Fatal(err)
+	}
+	notBackfilled()
+}
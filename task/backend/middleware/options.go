@@ -11,3 +11,10 @@ func WithNowFunc(fn func() time.Time) Option {
 		c.now = fn
 	}
 }
+
+// WithBackfillPollInterval sets the interval the queued runs of the backfills are published at
+func WithBackfillPollInterval(d time.Duration) Option {
+	return func(c *CoordinatingTaskService) {
+		c.backfillPollInterval = d
+	}
+}
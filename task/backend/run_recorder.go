@@ -49,6 +49,9 @@ func (s *StoragePointsWriterRecorder) Record(ctx context.Context, orgID influxdb
 	fields[finishedAtField] = run.FinishedAt.Format(time.RFC3339Nano)
 	fields[scheduledForField] = run.ScheduledFor.Format(time.RFC3339)
 	fields[requestedAtField] = run.RequestedAt.Format(time.RFC3339)
+	if run.Attempt > 0 {
+		fields[attemptField] = int64(run.Attempt)
+	}
 
 	startedAt := run.StartedAt
 	if startedAt.IsZero() {
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -44,6 +45,26 @@ type Schedulable interface {
 	LastScheduled() time.Time
 }
 
+// Dependent is a Schedulable that is triggered by the runs of the Schedulables it depends on,
+// instead of by its Schedule.
+// A run of a Dependent is triggered for a scheduled time once the runs of all the Schedulables it
+// depends on succeeded for that time. It is failed without being executed as soon as one of
+// these runs fails. If the Schedule of the Dependent is not zero, only the first of these
+// times that is at or after the next time of its Schedule triggers a run.
+type Dependent interface {
+	Schedulable
+
+	// DependsOn returns the IDs of the Schedulables this Dependent depends on.
+	DependsOn() []ID
+}
+
+// FailureRecorder is implemented by Executors that record the runs of Dependents
+// that are failed because a run they depend on failed.
+type FailureRecorder interface {
+	// RecordFailure records the run of id for scheduledFor as failed with err, without executing it.
+	RecordFailure(ctx context.Context, id ID, scheduledFor time.Time, runAt time.Time, err error) error
+}
+
 // SchedulableService encapsulates the work necessary to schedule a job
 type SchedulableService interface {
 
@@ -95,6 +116,11 @@ func (s Schedule) Next(from time.Time) (time.Time, error) {
 	return cron.Parsed(s.cron).Next(from)
 }
 
+// IsZero reports whether s is the zero Schedule, which never triggers.
+func (s Schedule) IsZero() bool {
+	return s.cron == cron.Parsed{}
+}
+
 // ValidSchedule returns an error if the cron string is invalid.
 func ValidateSchedule(c string) error {
 	_, err := cron.ParseUTC(c)
@@ -126,3 +152,18 @@ func (e *ErrUnrecoverable) Error() string {
 func (e *ErrUnrecoverable) Unwrap() error {
 	return e.error
 }
+
+// ErrUpstreamFailed is the error of a run of a Dependent that is failed because the run of the
+// Schedulable ID that it depends on failed with Err.
+type ErrUpstreamFailed struct {
+	ID  ID
+	Err error
+}
+
+func (e *ErrUpstreamFailed) Error() string {
+	return fmt.Sprintf("upstream task %016x failed: %v", uint64(e.ID), e.Err)
+}
+
+func (e *ErrUpstreamFailed) Unwrap() error {
+	return e.Err
+}
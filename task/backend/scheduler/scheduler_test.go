@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"sync"
 	"testing"
@@ -666,3 +667,206 @@ func TestNewSchedule(t *testing.T) {
 		})
 	}
 }
+
+type mockDependent struct {
+	mockSchedulable
+	dependsOn []ID
+}
+
+func (d mockDependent) DependsOn() []ID {
+	return d.dependsOn
+}
+
+type mockFailureRecorder struct {
+	mockExecutor
+	failed chan error
+}
+
+func (e *mockFailureRecorder) RecordFailure(ctx context.Context, id ID, scheduledFor time.Time, runAt time.Time, err error) error {
+	e.failed <- err
+	return nil
+}
+
+func TestDependent_complete(t *testing.T) {
+	d := &dependent{dependsOn: []ID{1, 2}, results: map[int64]map[ID]error{}}
+	for sf := int64(1); sf <= maxPendingDependentRuns; sf++ {
+		if d.complete(1, 60*sf, nil) {
+			t.Fatalf("expected the dependent to wait on the run of 2 for %d", 60*sf)
+		}
+	}
+
+	// the oldest time other than the one of the completed run is evicted.
+	if d.complete(1, 0, nil) {
+		t.Fatal("expected the dependent to wait on the run of 2 for 0")
+	}
+	if len(d.results) != maxPendingDependentRuns {
+		t.Fatalf("expected %d pending times, got %d", maxPendingDependentRuns, len(d.results))
+	}
+	if _, ok := d.results[60]; ok {
+		t.Fatal("expected the oldest pending time to be evicted")
+	}
+	if !d.complete(2, 0, nil) {
+		t.Fatal("expected the dependent to no longer wait on any run for 0")
+	}
+}
+
+func TestTreeScheduler_Dependents(t *testing.T) {
+	type run struct {
+		id           ID
+		scheduledFor time.Time
+	}
+	now := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	newScheduler := func(t *testing.T, exe Executor, errs chan error) *TreeScheduler {
+		t.Helper()
+		mockTime := clock.NewMock()
+		mockTime.Set(now)
+		sch, _, err := NewScheduler(
+			exe,
+			&mockSchedulableService{fn: func(ctx context.Context, id ID, t time.Time) error {
+				return nil
+			}},
+			WithTime(mockTime),
+			WithOnErrorFn(func(_ context.Context, _ ID, _ time.Time, err error) {
+				errs <- err
+			}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sch
+	}
+	newExecutor := func(runs chan run) *mockExecutor {
+		return &mockExecutor{fn: func(l *sync.Mutex, ctx context.Context, id ID, scheduledFor time.Time) {
+			runs <- run{id: id, scheduledFor: scheduledFor}
+		}}
+	}
+	expectRun := func(t *testing.T, runs chan run, exp run) {
+		t.Helper()
+		select {
+		case got := <-runs:
+			if got.id != exp.id || !got.scheduledFor.Equal(exp.scheduledFor) {
+				t.Fatalf("unexpected run: got %d for %s, expected %d for %s", got.id, got.scheduledFor, exp.id, exp.scheduledFor)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected run of %d for %s", exp.id, exp.scheduledFor)
+		}
+	}
+	expectNoRun := func(t *testing.T, runs chan run) {
+		t.Helper()
+		select {
+		case got := <-runs:
+			t.Fatalf("unexpected run of %d for %s", got.id, got.scheduledFor)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	t.Run("runs once all upstream runs succeeded", func(t *testing.T) {
+		runs := make(chan run, 10)
+		sch := newScheduler(t, newExecutor(runs), make(chan error, 10))
+		defer sch.Stop()
+
+		if err := sch.Schedule(mockDependent{mockSchedulable: mockSchedulable{id: 3, lastScheduled: now}, dependsOn: []ID{1, 2}}); err != nil {
+			t.Fatal(err)
+		}
+
+		sf := now.Add(time.Minute)
+		sch.Completed(1, sf, nil)
+		expectNoRun(t, runs)
+		sch.Completed(2, now.Add(2*time.Minute), nil)
+		expectNoRun(t, runs)
+		sch.Completed(2, sf, nil)
+		expectRun(t, runs, run{id: 3, scheduledFor: sf})
+
+		// a retried upstream run triggers the run again.
+		sch.Completed(1, now.Add(2*time.Minute), nil)
+		expectRun(t, runs, run{id: 3, scheduledFor: now.Add(2 * time.Minute)})
+	})
+
+	t.Run("upstream failure is recorded", func(t *testing.T) {
+		runs := make(chan run, 10)
+		exe := &mockFailureRecorder{mockExecutor: *newExecutor(runs), failed: make(chan error, 10)}
+		sch := newScheduler(t, exe, make(chan error, 10))
+		defer sch.Stop()
+
+		if err := sch.Schedule(mockDependent{mockSchedulable: mockSchedulable{id: 3, lastScheduled: now}, dependsOn: []ID{1, 2}}); err != nil {
+			t.Fatal(err)
+		}
+
+		sch.Completed(1, now.Add(time.Minute), errors.New("query failed"))
+		select {
+		case err := <-exe.failed:
+			upstreamErr, ok := err.(*ErrUpstreamFailed)
+			if !ok || upstreamErr.ID != 1 {
+				t.Fatalf("unexpected error %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected failure to be recorded")
+		}
+		expectNoRun(t, runs)
+	})
+
+	t.Run("upstream failure is propagated without a failure recorder", func(t *testing.T) {
+		runs := make(chan run, 10)
+		errs := make(chan error, 10)
+		sch := newScheduler(t, newExecutor(runs), errs)
+		defer sch.Stop()
+
+		if err := sch.Schedule(mockDependent{mockSchedulable: mockSchedulable{id: 2, lastScheduled: now}, dependsOn: []ID{1}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Schedule(mockDependent{mockSchedulable: mockSchedulable{id: 3, lastScheduled: now}, dependsOn: []ID{2}}); err != nil {
+			t.Fatal(err)
+		}
+
+		sch.Completed(1, now.Add(time.Minute), errors.New("query failed"))
+		for _, upstream := range []ID{1, 2} {
+			select {
+			case err := <-errs:
+				upstreamErr, ok := err.(*ErrUpstreamFailed)
+				if !ok || upstreamErr.ID != upstream {
+					t.Fatalf("unexpected error %v", err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("expected failure of upstream %d to be propagated", upstream)
+			}
+		}
+		expectNoRun(t, runs)
+	})
+
+	t.Run("schedule of the dependent", func(t *testing.T) {
+		runs := make(chan run, 10)
+		sch := newScheduler(t, newExecutor(runs), make(chan error, 10))
+		defer sch.Stop()
+
+		schedule, ts, err := NewSchedule("@every 1h", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Schedule(mockDependent{mockSchedulable: mockSchedulable{id: 2, schedule: schedule, lastScheduled: ts}, dependsOn: []ID{1}}); err != nil {
+			t.Fatal(err)
+		}
+
+		sch.Completed(1, now.Add(30*time.Minute), nil)
+		expectNoRun(t, runs)
+		sch.Completed(1, now.Add(time.Hour), nil)
+		expectRun(t, runs, run{id: 2, scheduledFor: now.Add(time.Hour)})
+		sch.Completed(1, now.Add(time.Hour+time.Minute), nil)
+		expectNoRun(t, runs)
+	})
+
+	t.Run("released", func(t *testing.T) {
+		runs := make(chan run, 10)
+		sch := newScheduler(t, newExecutor(runs), make(chan error, 10))
+		defer sch.Stop()
+
+		if err := sch.Schedule(mockDependent{mockSchedulable: mockSchedulable{id: 2, lastScheduled: now}, dependsOn: []ID{1}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sch.Release(2); err != nil {
+			t.Fatal(err)
+		}
+
+		sch.Completed(1, now.Add(time.Minute), nil)
+		expectNoRun(t, runs)
+	})
+}
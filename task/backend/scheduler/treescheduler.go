@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/binary"
 	"errors"
+	"math"
 	"sync"
 	"time"
 
@@ -19,6 +20,10 @@ const (
 
 	// defaultMaxWorkers is a constant that sets the default number of maximum workers for a TreeScheduler
 	defaultMaxWorkers = 128
+
+	// maxPendingDependentRuns is the maximum number of scheduled times a Dependent waits on the runs it depends on for.
+	// The oldest times are dropped past it, as the runs of some of the Schedulables it depends on may never happen for them.
+	maxPendingDependentRuns = 100
 )
 
 // TreeScheduler is a Scheduler based on a btree.
@@ -60,6 +65,12 @@ const (
 // Removing a task from the scheduler acquires a write lock, deletes the task from the uniqueness index and from the
 // btree, then releases the lock.  We do not have to readjust the time on delete, because, if the minimum task isn't
 // ready yet, the main loop just resets the timer and keeps going.
+//
+// Dependents:
+//
+// Dependents are not put in the btree. They are kept in a map keyed by task ID, along with an index of the
+// Dependents of each task.  Completed records the result of a run for each of the Dependents of its task, and
+// executes the run of a Dependent for the scheduled time of the run once all the results for that time are in.
 type TreeScheduler struct {
 	mu           sync.RWMutex
 	scheduled    *btree.BTree
@@ -74,6 +85,9 @@ type TreeScheduler struct {
 	wg           sync.WaitGroup
 	checkpointer SchedulableService
 
+	dependents  map[ID]*dependent
+	downstreams map[ID][]ID // the IDs of the Dependents that depend on a task
+
 	sm *SchedulerMetrics
 }
 
@@ -114,6 +128,8 @@ func NewScheduler(executor Executor, checkpointer SchedulableService, opts ...tr
 		executor:     executor,
 		scheduled:    btree.New(degreeBtreeScheduled),
 		nextTime:     map[ID]ordering{},
+		dependents:   map[ID]*dependent{},
+		downstreams:  map[ID][]ID{},
 		onErr:        func(_ context.Context, _ ID, _ time.Time, _ error) {},
 		time:         clock.New(),
 		done:         make(chan struct{}, 1),
@@ -289,6 +305,28 @@ func (s *TreeScheduler) release(taskID ID) {
 	delete(s.nextTime, taskID)
 }
 
+func (s *TreeScheduler) releaseDependent(taskID ID) {
+	d, ok := s.dependents[taskID]
+	if !ok {
+		return
+	}
+
+	for _, upstream := range d.dependsOn {
+		downstreams := s.downstreams[upstream][:0]
+		for _, id := range s.downstreams[upstream] {
+			if id != taskID {
+				downstreams = append(downstreams, id)
+			}
+		}
+		if len(downstreams) == 0 {
+			delete(s.downstreams, upstream)
+		} else {
+			s.downstreams[upstream] = downstreams
+		}
+	}
+	delete(s.dependents, taskID)
+}
+
 // Release releases a task.
 // Release also cancels the running task.
 // Task deletion would be faster if the tree supported deleting ranges.
@@ -296,10 +334,58 @@ func (s *TreeScheduler) Release(taskID ID) error {
 	s.sm.release(taskID)
 	s.mu.Lock()
 	s.release(taskID)
+	s.releaseDependent(taskID)
 	s.mu.Unlock()
 	return nil
 }
 
+// Completed records that the run of taskID for scheduledFor completed with err.
+// It triggers the runs of the Dependents of taskID for scheduledFor that are no longer waiting on any run.
+// Executors call it at the end of every scheduled run, including the ones they record as failed,
+// but not at the end of manual runs, such as the ones of backfills.
+func (s *TreeScheduler) Completed(taskID ID, scheduledFor time.Time, err error) {
+	sf := scheduledFor.UTC().Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range s.downstreams[taskID] {
+		d := s.dependents[id]
+		if !d.complete(taskID, sf, err) {
+			continue
+		}
+		if !d.due(sf) {
+			continue
+		}
+
+		var upstreamErr error
+		if err != nil {
+			upstreamErr = &ErrUpstreamFailed{ID: taskID, Err: err}
+		}
+		s.trigger(Item{
+			ordering: ordering{when: s.time.Now().UTC().Unix()},
+			id:       d.id,
+			cron:     d.cron,
+			next:     sf,
+		}, upstreamErr)
+	}
+}
+
+// trigger executes the run of a Dependent outside of the main loop.
+// s.mu must be held when calling it.
+func (s *TreeScheduler) trigger(it Item, upstreamErr error) {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(context.Background(), it, upstreamErr)
+	}()
+}
+
 // work does work from the channel and checkpoints it.
 func (s *TreeScheduler) work(ctx context.Context, ch chan Item) {
 	var it Item
@@ -307,35 +393,61 @@ func (s *TreeScheduler) work(ctx context.Context, ch chan Item) {
 		s.wg.Done()
 	}()
 	for it = range ch {
-		t := time.Unix(it.next, 0)
-		err := func() (err error) {
-			defer func() {
-				if r := recover(); r != nil {
-					err = &ErrUnrecoverable{errors.New("executor panicked")}
-				}
-			}()
-			// report the difference between when the item was supposed to be scheduled and now
-			s.sm.reportScheduleDelay(time.Since(it.Next()))
-			preExec := time.Now()
-			// execute
-			err = s.executor.Execute(ctx, it.id, t, it.when())
-			// report how long execution took
-			s.sm.reportExecution(err, time.Since(preExec))
-			return err
+		s.execute(ctx, it, nil)
+	}
+}
+
+// execute executes the run of it and checkpoints it.
+// If upstreamErr is not nil, the run is failed with it instead of being executed.
+func (s *TreeScheduler) execute(ctx context.Context, it Item, upstreamErr error) {
+	t := time.Unix(it.next, 0)
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &ErrUnrecoverable{errors.New("executor panicked")}
+			}
 		}()
-		if err != nil {
-			s.onErr(ctx, it.id, it.Next(), err)
-		}
-		// TODO(docmerlin): we can increase performance by making the call to UpdateLastScheduled async
-		if err := s.checkpointer.UpdateLastScheduled(ctx, it.id, t); err != nil {
-			s.onErr(ctx, it.id, it.Next(), err)
+		// report the difference between when the item was supposed to be scheduled and now
+		s.sm.reportScheduleDelay(time.Since(it.Next()))
+		preExec := time.Now()
+		// execute
+		if upstreamErr != nil {
+			err = s.recordFailure(ctx, it, upstreamErr)
+		} else {
+			err = s.executor.Execute(ctx, it.id, t, it.when())
 		}
+		// report how long execution took
+		s.sm.reportExecution(err, time.Since(preExec))
+		return err
+	}()
+	if err != nil {
+		s.onErr(ctx, it.id, it.Next(), err)
+	}
+	// TODO(docmerlin): we can increase performance by making the call to UpdateLastScheduled async
+	if err := s.checkpointer.UpdateLastScheduled(ctx, it.id, t); err != nil {
+		s.onErr(ctx, it.id, it.Next(), err)
 	}
 }
 
+// recordFailure fails the run of it with upstreamErr.
+func (s *TreeScheduler) recordFailure(ctx context.Context, it Item, upstreamErr error) error {
+	fr, ok := s.executor.(FailureRecorder)
+	if !ok {
+		// nothing records the run, so the failure is propagated to the Dependents of it here.
+		s.Completed(it.id, it.Next(), upstreamErr)
+		return upstreamErr
+	}
+	return fr.RecordFailure(ctx, it.id, it.Next(), it.when(), upstreamErr)
+}
+
 // Schedule put puts a Schedulable on the TreeScheduler.
+// A Dependent that depends on other Schedulables is triggered by their runs instead of by its Schedule.
 func (s *TreeScheduler) Schedule(sch Schedulable) error {
 	s.sm.schedule(sch.ID())
+	if dep, ok := sch.(Dependent); ok && len(dep.DependsOn()) > 0 {
+		s.scheduleDependent(dep)
+		return nil
+	}
 	it := Item{
 		cron:   sch.Schedule(),
 		id:     sch.ID(),
@@ -378,9 +490,95 @@ func (s *TreeScheduler) Schedule(sch Schedulable) error {
 
 	// insert the new task run time
 	s.scheduled.ReplaceOrInsert(it)
+	s.releaseDependent(it.id)
 	return nil
 }
 
+func (s *TreeScheduler) scheduleDependent(dep Dependent) {
+	id := dep.ID()
+	d := &dependent{
+		id:        id,
+		cron:      dep.Schedule(),
+		last:      dep.LastScheduled().UTC().Unix(),
+		dependsOn: dep.DependsOn(),
+		results:   map[int64]map[ID]error{},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// keep the results of the runs the Dependent is waiting on.
+	if old, ok := s.dependents[id]; ok {
+		d.results = old.results
+	}
+	s.release(id)
+	s.releaseDependent(id)
+
+	s.dependents[id] = d
+	for _, upstream := range d.dependsOn {
+		s.downstreams[upstream] = append(s.downstreams[upstream], id)
+	}
+}
+
+// dependent is a Dependent on a TreeScheduler.
+type dependent struct {
+	id        ID
+	cron      Schedule
+	last      int64 // the last time a run of the dependent was triggered for
+	dependsOn []ID
+
+	// results are the errors of the runs the dependent depends on for the scheduled times it waits on.
+	results map[int64]map[ID]error
+}
+
+// complete records the result of the run of taskID for sf and reports whether
+// the dependent no longer waits on any run for sf.
+func (d *dependent) complete(taskID ID, sf int64, err error) bool {
+	results, ok := d.results[sf]
+	if !ok {
+		results = make(map[ID]error, len(d.dependsOn))
+		d.results[sf] = results
+		if len(d.results) > maxPendingDependentRuns {
+			// evict the oldest time other than sf, which is kept even when it is the oldest.
+			oldest := int64(math.MaxInt64)
+			for t := range d.results {
+				if t != sf && t < oldest {
+					oldest = t
+				}
+			}
+			delete(d.results, oldest)
+		}
+	}
+	results[taskID] = err
+
+	if err == nil && len(results) < len(d.dependsOn) {
+		return false
+	}
+	delete(d.results, sf)
+	return true
+}
+
+// due reports whether a run of the dependent is to be triggered for sf.
+// A dependent with a Schedule is due at the first time at or after the next time of its Schedule,
+// or for the last time it was triggered for, so that the retry of a run triggers it again.
+func (d *dependent) due(sf int64) bool {
+	if !d.cron.IsZero() {
+		if sf < d.last {
+			return false
+		}
+		if sf > d.last {
+			next, err := d.cron.Next(time.Unix(d.last, 0).UTC())
+			if err != nil || next.Unix() > sf {
+				return false
+			}
+		}
+	}
+	if sf > d.last {
+		d.last = sf
+	}
+	return true
+}
+
 type ordering struct {
 	when  int64
 	nonce int // for retries
@@ -33,6 +33,10 @@ type TaskControlService interface {
 	// StartManualRun pulls a manual run from the list and moves it to currently running.
 	StartManualRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error)
 
+	// QueueRetry adds the next attempt of the currently running run runID to the manual runs,
+	// to be started at runAt.
+	QueueRetry(ctx context.Context, taskID, runID influxdb.ID, runAt time.Time) (*influxdb.Run, error)
+
 	// FinishRun removes runID from the list of running tasks and if its `ScheduledFor` is later then last completed update it.
 	FinishRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error)
 
@@ -189,6 +189,30 @@ func (t *TaskControlService) StartManualRun(_ context.Context, taskID, runID inf
 	return run, nil
 }
 
+func (t *TaskControlService) QueueRetry(_ context.Context, taskID, runID influxdb.ID, runAt time.Time) (*influxdb.Run, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.runs[taskID][runID]
+	if !ok {
+		return nil, influxdb.ErrRunNotFound
+	}
+
+	attempt := r.Attempt
+	if attempt < 1 {
+		attempt = 1
+	}
+	run := &influxdb.Run{
+		ID:           idgen.ID(),
+		TaskID:       taskID,
+		ScheduledFor: r.ScheduledFor,
+		RunAt:        runAt,
+		Attempt:      attempt + 1,
+	}
+	t.manualRuns = append(t.manualRuns, run)
+	return run, nil
+}
+
 func (d *TaskControlService) FinishRun(_ context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -4,6 +4,7 @@ package options
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,7 +38,26 @@ type Options struct {
 
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
+	// Retry is the maximum number of attempts of a run, the first included.
 	Retry *int64 `json:"retry,omitempty"`
+
+	// RetryDelay is the delay before the second attempt of a failed run,
+	// doubled for every following attempt.
+	RetryDelay *Duration `json:"retryDelay,omitempty"`
+
+	// RetryJitter is the maximum random duration added to the delay before an attempt of a failed run.
+	RetryJitter *Duration `json:"retryJitter,omitempty"`
+
+	// Backfill is the span of time before the creation of the task, or before
+	// a change of its query, over which the task is backfilled.
+	Backfill *Duration `json:"backfill,omitempty"`
+
+	// DependsOn are the IDs of the tasks of which a scheduled run must succeed
+	// before a run of the task is triggered for the same scheduled time.
+	// Manual runs of those tasks, such as the ones of backfills, trigger none.
+	// A nil DependsOn leaves the dependencies of a task unchanged on update,
+	// an empty one removes them.
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // Duration is a time span that supports the same units as the flux parser's time duration, as well as negative length time spans.
@@ -134,6 +154,10 @@ func (o *Options) Clear() {
 	o.Offset = nil
 	o.Concurrency = nil
 	o.Retry = nil
+	o.RetryDelay = nil
+	o.RetryJitter = nil
+	o.Backfill = nil
+	o.DependsOn = nil
 }
 
 // IsZero tells us if the options has been zeroed out.
@@ -143,7 +167,11 @@ func (o *Options) IsZero() bool {
 		o.Every.IsZero() &&
 		(o.Offset == nil || o.Offset.IsZero()) &&
 		o.Concurrency == nil &&
-		o.Retry == nil
+		o.Retry == nil &&
+		o.RetryDelay == nil &&
+		o.RetryJitter == nil &&
+		o.Backfill == nil &&
+		o.DependsOn == nil
 }
 
 // All the task option names we accept.
@@ -154,6 +182,10 @@ const (
 	optOffset      = "offset"
 	optConcurrency = "concurrency"
 	optRetry       = "retry"
+	optRetryDelay  = "retryDelay"
+	optRetryJitter = "retryJitter"
+	optBackfill    = "backfill"
+	optDependsOn   = "dependsOn"
 )
 
 // contains is a helper function to see if an array of strings contains a string
@@ -167,7 +199,7 @@ func contains(s []string, e string) bool {
 }
 
 func grabTaskOptionAST(p *ast.Package, keys ...string) map[string]ast.Expression {
-	res := make(map[string]ast.Expression, 2) // we preallocate two keys for the map, as that is how many we will use at maximum (offset and every)
+	res := make(map[string]ast.Expression, len(keys))
 	for i := range p.Files {
 		for j := range p.Files[i].Body {
 			if p.Files[i].Body[j].Type() != "OptionStatement" {
@@ -217,7 +249,7 @@ func FromScript(script string) (Options, error) {
 	if err != nil {
 		return opt, err
 	}
-	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset)
+	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset, optRetryDelay, optRetryJitter, optBackfill)
 	// TODO(desa): should be dependencies.NewEmpty(), but for now we'll hack things together
 	ctx := newDeps().Inject(context.Background())
 	_, scope, err := flux.EvalAST(ctx, fluxAST)
@@ -254,8 +286,9 @@ func FromScript(script string) (Options, error) {
 		return opt, ErrDuplicateIntervalField
 	}
 
-	if !cronOK && !everyOK {
-		return opt, ErrMissingRequiredTaskOption("cron or every is required")
+	dependsOnVal, dependsOnOK := optObject.Get(optDependsOn)
+	if !cronOK && !everyOK && !dependsOnOK {
+		return opt, ErrMissingRequiredTaskOption("cron, every or dependsOn is required")
 	}
 
 	if cronOK {
@@ -326,6 +359,48 @@ func FromScript(script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
+	for _, o := range []struct {
+		name string
+		d    **Duration
+	}{{optRetryDelay, &opt.RetryDelay}, {optRetryJitter, &opt.RetryJitter}, {optBackfill, &opt.Backfill}} {
+		name, d := o.name, o.d
+		val, ok := optObject.Get(name)
+		if !ok {
+			continue
+		}
+		if err := checkNature(val.PolyType().Nature(), semantic.Duration); err != nil {
+			return opt, err
+		}
+		dur, ok := durTypes[name]
+		if !ok || dur == nil {
+			return opt, ErrParseTaskOptionField(name)
+		}
+		durNode, err := parseSignedDuration(dur.Location().Source)
+		if err != nil {
+			return opt, err
+		}
+		if _, err := time.ParseDuration(dur.Location().Source); err != nil { // TODO(docmerlin): remove this once tasks fully supports all flux duration units.
+			return opt, ErrParseTaskOptionField(name)
+		}
+		durNode.BaseNode = ast.BaseNode{}
+		*d = &Duration{Node: *durNode}
+	}
+
+	if dependsOnOK {
+		if err := checkNature(dependsOnVal.PolyType().Nature(), semantic.Array); err != nil {
+			return opt, err
+		}
+		arr := dependsOnVal.Array()
+		opt.DependsOn = make([]string, 0, arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			v := arr.Get(i)
+			if err := checkNature(v.PolyType().Nature(), semantic.String); err != nil {
+				return opt, err
+			}
+			opt.DependsOn = append(opt.DependsOn, v.Str())
+		}
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -343,9 +418,11 @@ func (o *Options) Validate() error {
 
 	cronPresent := o.Cron != ""
 	everyPresent := !o.Every.IsZero()
-	if cronPresent == everyPresent {
-		// They're both present or both missing.
+	if cronPresent && everyPresent {
 		errs = append(errs, "must specify exactly one of either cron or every")
+	} else if !cronPresent && !everyPresent && len(o.DependsOn) == 0 {
+		// A task without a schedule is only triggered by the tasks it depends on.
+		errs = append(errs, "must specify exactly one of either cron or every, or dependsOn")
 	} else if cronPresent {
 		_, err := cron.Parse(o.Cron)
 		if err != nil {
@@ -386,6 +463,42 @@ func (o *Options) Validate() error {
 			errs = append(errs, fmt.Sprintf("retry exceeded max of %d", maxRetry))
 		}
 	}
+	for _, opt := range []struct {
+		name string
+		d    *Duration
+	}{{optRetryDelay, o.RetryDelay}, {optRetryJitter, o.RetryJitter}} {
+		if opt.d == nil {
+			continue
+		}
+		dur, err := opt.d.DurationFrom(now)
+		if err != nil {
+			return err
+		}
+		if dur < 0 {
+			errs = append(errs, fmt.Sprintf("%s option must not be negative", opt.name))
+		}
+	}
+	if o.Backfill != nil {
+		backfill, err := o.Backfill.DurationFrom(now)
+		if err != nil {
+			return err
+		}
+		if backfill <= 0 {
+			errs = append(errs, "backfill option must be positive")
+		} else if !cronPresent && !everyPresent {
+			errs = append(errs, "backfill option requires cron or every")
+		}
+	}
+
+	seen := make(map[string]bool, len(o.DependsOn))
+	for _, id := range o.DependsOn {
+		if !validTaskID(id) {
+			errs = append(errs, fmt.Sprintf("dependsOn: %q is not a valid task ID", id))
+		} else if seen[id] {
+			errs = append(errs, fmt.Sprintf("dependsOn: duplicate task ID %q", id))
+		}
+		seen[id] = true
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -413,6 +526,16 @@ func (o *Options) EffectiveCronString() string {
 	return ""
 }
 
+// validTaskID reports whether id is the string form of a task ID,
+// 16 hexadecimal characters that are not all zeros.
+func validTaskID(id string) bool {
+	if len(id) != 16 {
+		return false
+	}
+	v, err := strconv.ParseUint(id, 16, 64)
+	return err == nil && v != 0
+}
+
 // checkNature returns a clean error of got and expected dont match.
 func checkNature(got, exp semantic.Nature) error {
 	if got != exp {
@@ -427,7 +550,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optRetryDelay, optRetryJitter, optBackfill, optDependsOn:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -436,7 +559,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry, optRetryDelay, optRetryJitter, optBackfill, optDependsOn}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
@@ -33,6 +33,22 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != nil && *opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, *opt.Retry)
 	}
+	if opt.RetryDelay != nil {
+		taskData = fmt.Sprintf("%s  retryDelay: %s,\n", taskData, opt.RetryDelay.String())
+	}
+	if opt.RetryJitter != nil {
+		taskData = fmt.Sprintf("%s  retryJitter: %s,\n", taskData, opt.RetryJitter.String())
+	}
+	if opt.Backfill != nil {
+		taskData = fmt.Sprintf("%s  backfill: %s,\n", taskData, opt.Backfill.String())
+	}
+	if opt.DependsOn != nil {
+		ids := make([]string, 0, len(opt.DependsOn))
+		for _, id := range opt.DependsOn {
+			ids = append(ids, fmt.Sprintf("%q", id))
+		}
+		taskData = fmt.Sprintf("%s  dependsOn: [%s],\n", taskData, strings.Join(ids, ", "))
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -96,6 +112,19 @@ func TestFromScript(t *testing.T) {
 			|> range(start: now(), stop: 8w)
 
 		`, shouldErr: true}, // TODO(docmerlin): remove this once tasks fully supports all flux duration units.
+		{script: scriptGenerator(options.Options{Name: "name10", DependsOn: []string{"0000000000000001", "0000000000000002"}}, ""),
+			exp: options.Options{Name: "name10", DependsOn: []string{"0000000000000001", "0000000000000002"}, Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name11", Every: *(options.MustParseDuration("1h")), DependsOn: []string{"0000000000000001"}}, ""),
+			exp: options.Options{Name: "name11", Every: *(options.MustParseDuration("1h")), DependsOn: []string{"0000000000000001"}, Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name12", DependsOn: []string{"not an id"}}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name13", DependsOn: []string{}}, ""), shouldErr: true},
+		{script: "option task = {\n  name: \"name14\",\n  dependsOn: [1],\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), Retry: pointer.Int64(3), RetryDelay: options.MustParseDuration("30s"), RetryJitter: options.MustParseDuration("5s")}, ""),
+			exp: options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(3), RetryDelay: options.MustParseDuration("30s"), RetryJitter: options.MustParseDuration("5s")}},
+		{script: "option task = {\n  name: \"name16\",\n  every: 1h,\n  retryDelay: \"30s\",\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name17", Every: *(options.MustParseDuration("1h")), Backfill: options.MustParseDuration("24h")}, ""),
+			exp: options.Options{Name: "name17", Every: *(options.MustParseDuration("1h")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), Backfill: options.MustParseDuration("24h")}},
+		{script: scriptGenerator(options.Options{Name: "name18", DependsOn: []string{"0000000000000001"}, Backfill: options.MustParseDuration("24h")}, ""), shouldErr: true},
 		{script: "option task = {name:\"test_task_smoke_name\", every:30s} from(bucket:\"test_tasks_smoke_bucket_source\") |> range(start: -1h) |> map(fn: (r) => ({r with _time: r._time, _value:r._value, t : \"quality_rocks\"}))|> to(bucket:\"test_tasks_smoke_bucket_dest\", orgID:\"3e73e749495d37d5\")",
 			exp: options.Options{Name: "test_task_smoke_name", Every: *(options.MustParseDuration("30s")), Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}, shouldErr: false}, // TODO(docmerlin): remove this once tasks fully supports all flux duration units.
 
@@ -143,7 +172,7 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 		t.Errorf("expected error to mention unrecognized options, but it said: %v", err)
 	}
 
-	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry"}
+	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry", "retryDelay", "retryJitter", "backfill", "dependsOn"}
 	for _, o := range validOpts {
 		if !strings.Contains(msg, o) {
 			t.Errorf("expected error to mention valid option %q but it said: %v", o, err)
@@ -176,6 +205,25 @@ func TestValidate(t *testing.T) {
 		t.Error("expected error for options with both cron and every")
 	}
 
+	dependent := good
+	dependent.Cron = ""
+	dependent.DependsOn = []string{"0000000000000001"}
+	if err := dependent.Validate(); err != nil {
+		t.Errorf("expected options with dependsOn but without cron or every to be valid: %v", err)
+	}
+
+	*bad = dependent
+	bad.DependsOn = []string{"0000000000000000"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for invalid task ID in dependsOn")
+	}
+
+	*bad = dependent
+	bad.DependsOn = []string{"0000000000000001", "0000000000000001"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for duplicate task ID in dependsOn")
+	}
+
 	*bad = good
 	bad.Cron = "not a cron string"
 	if err := bad.Validate(); err == nil {
@@ -218,6 +266,24 @@ func TestValidate(t *testing.T) {
 	if err := bad.Validate(); err == nil {
 		t.Error("expected error for retry too large")
 	}
+
+	*bad = good
+	bad.RetryDelay = options.MustParseDuration("-1s")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for negative retry delay")
+	}
+
+	*bad = good
+	bad.RetryJitter = options.MustParseDuration("-1s")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for negative retry jitter")
+	}
+
+	*bad = good
+	bad.Backfill = options.MustParseDuration("0s")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for empty backfill")
+	}
 }
 
 func TestEffectiveCronString(t *testing.T) {
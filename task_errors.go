@@ -79,6 +79,24 @@ var (
 		Msg:  "run limit is out of bounds, must be between 1 and 500",
 	}
 
+	// ErrTaskDependencyCycle is returned when a task would depend on itself, directly or not.
+	ErrTaskDependencyCycle = &Error{
+		Code: EInvalid,
+		Msg:  "task dependencies must not form a cycle",
+	}
+
+	// ErrTaskNotScheduled is returned when backfilling a task that has no schedule.
+	ErrTaskNotScheduled = &Error{
+		Code: EInvalid,
+		Msg:  "task has no cron or every schedule to backfill",
+	}
+
+	// ErrBackfillTooManyRuns is returned when a backfill would create more than MaxBackfillRuns runs.
+	ErrBackfillTooManyRuns = &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("backfill cannot create more than %d runs", MaxBackfillRuns),
+	}
+
 	// ErrInvalidOwnerID is called when trying to create a task with out a valid ownerID
 	ErrInvalidOwnerID = &Error{
 		Code: EInvalid,
@@ -171,6 +189,14 @@ func ErrJsonMarshalError(err error) *Error {
 	}
 }
 
+// ErrTaskDependencyNotFound is returned when a task depends on a task that is not in its organization.
+func ErrTaskDependencyNotFound(id ID) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("task %s in dependsOn not found", id),
+	}
+}
+
 func ErrRunExecutionError(err error) *Error {
 	return &Error{
 		Code: EInternal,
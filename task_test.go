@@ -32,6 +32,25 @@ func TestOptionsMarshal(t *testing.T) {
 	if tu.Flux == nil {
 		t.Fatalf("flux not properly unmarshaled, expected not nil but got nil")
 	}
+
+	tu = &platform.TaskUpdate{}
+	if err := json.Unmarshal([]byte(`{"dependsOn":[]}`), tu); err != nil {
+		t.Fatal(err)
+	}
+	if tu.Options.DependsOn == nil || len(tu.Options.DependsOn) != 0 {
+		t.Fatalf("option.dependsOn not properly unmarshaled, expected empty got %v", tu.Options.DependsOn)
+	}
+	b, err := json.Marshal(tu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tu = &platform.TaskUpdate{}
+	if err := json.Unmarshal(b, tu); err != nil {
+		t.Fatal(err)
+	}
+	if tu.Options.DependsOn == nil {
+		t.Fatalf("empty option.dependsOn not properly marshaled, got %s", b)
+	}
 }
 
 func TestOptionsEdit(t *testing.T) {
@@ -136,5 +155,33 @@ from(bucket: "x")
 			t.Fatalf(cmp.Diff(*tu.Flux, expscript))
 		}
 	})
+	t.Run("add dependencies", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.DependsOn = []string{"0000000000000001", "0000000000000002"}
+		if err := tu.UpdateFlux(`option task = {every: 20s, name: "foo"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(*tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(op.DependsOn, []string{"0000000000000001", "0000000000000002"}) {
+			t.Fatalf("unexpected dependsOn %v", op.DependsOn)
+		}
+	})
+	t.Run("delete dependencies", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.DependsOn = []string{}
+		expscript := `option task = {every: 20s, name: "foo"}
+
+from(bucket: "x")
+	|> range(start: -1h)`
+		if err := tu.UpdateFlux(`option task = {every: 20s, name: "foo", dependsOn: ["0000000000000001"]} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(*tu.Flux, expscript) {
+			t.Fatalf(cmp.Diff(*tu.Flux, expscript))
+		}
+	})
 
 }
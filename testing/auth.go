@@ -75,6 +75,10 @@ func AuthorizationService(
 			name: "DeleteAuthorization",
 			fn:   DeleteAuthorization,
 		},
+		{
+			name: "RotateAuthorization",
+			fn:   RotateAuthorization,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1386,6 +1390,283 @@ func DeleteAuthorization(
 	}
 }
 
+// RotateAuthorization testing
+func RotateAuthorization(
+	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
+	t *testing.T,
+) {
+	now := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	timePtr := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	fields := func(a *platform.Authorization) AuthorizationFields {
+		return AuthorizationFields{
+			IDGenerator:    mock.NewIDGenerator(authTwoID, t),
+			TimeGenerator:  &mock.TimeGenerator{FakeValue: now},
+			TokenGenerator: &mock.TokenGenerator{TokenFn: func() (string, error) { return "rand", nil }},
+			Users: []*platform.User{
+				{
+					Name: "cooluser",
+					ID:   MustIDBase16(userOneID),
+				},
+			},
+			Orgs: []*platform.Organization{
+				{
+					Name: "o1",
+					ID:   MustIDBase16(orgOneID),
+				},
+			},
+			Authorizations: []*platform.Authorization{a},
+		}
+	}
+
+	type args struct {
+		id       platform.ID
+		rotation platform.AuthorizationRotation
+	}
+	type wants struct {
+		err            error
+		authorizations []*platform.Authorization
+	}
+
+	tests := []struct {
+		name   string
+		fields AuthorizationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "rotated token is valid for the grace period",
+			fields: fields(&platform.Authorization{
+				ID:          MustIDBase16(authOneID),
+				UserID:      MustIDBase16(userOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Token:       "supersecret",
+				Status:      platform.Active,
+				Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+				Description: "telegraf",
+			}),
+			args: args{
+				id:       MustIDBase16(authOneID),
+				rotation: platform.AuthorizationRotation{GracePeriod: time.Hour},
+			},
+			wants: wants{
+				authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "supersecret",
+						Status:      platform.Active,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+						Description: "telegraf",
+						ExpiresAt:   timePtr(time.Hour),
+						CRUDLog: platform.CRUDLog{
+							UpdatedAt: now,
+						},
+					},
+					{
+						ID:          MustIDBase16(authTwoID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand",
+						Status:      platform.Active,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+						Description: "telegraf",
+						CRUDLog: platform.CRUDLog{
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "rotated authorization keeps its lifetime",
+			fields: fields(&platform.Authorization{
+				ID:          MustIDBase16(authOneID),
+				UserID:      MustIDBase16(userOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Token:       "supersecret",
+				Status:      platform.Inactive,
+				Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+				ExpiresAt:   timePtr(10 * time.Minute),
+				CRUDLog: platform.CRUDLog{
+					CreatedAt: now.Add(-time.Hour),
+					UpdatedAt: now.Add(-time.Hour),
+				},
+			}),
+			args: args{
+				id:       MustIDBase16(authOneID),
+				rotation: platform.AuthorizationRotation{GracePeriod: time.Hour},
+			},
+			wants: wants{
+				authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "supersecret",
+						Status:      platform.Inactive,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+						ExpiresAt:   timePtr(10 * time.Minute),
+						CRUDLog: platform.CRUDLog{
+							CreatedAt: now.Add(-time.Hour),
+							UpdatedAt: now,
+						},
+					},
+					{
+						ID:          MustIDBase16(authTwoID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand",
+						Status:      platform.Inactive,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+						ExpiresAt:   timePtr(70 * time.Minute),
+						CRUDLog: platform.CRUDLog{
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "rotated authorization with expiry",
+			fields: fields(&platform.Authorization{
+				ID:          MustIDBase16(authOneID),
+				UserID:      MustIDBase16(userOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Token:       "supersecret",
+				Status:      platform.Active,
+				Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+			}),
+			args: args{
+				id: MustIDBase16(authOneID),
+				rotation: platform.AuthorizationRotation{
+					ExpiresAt: timePtr(24 * time.Hour),
+				},
+			},
+			wants: wants{
+				authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "supersecret",
+						Status:      platform.Active,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+						ExpiresAt:   timePtr(0),
+						CRUDLog: platform.CRUDLog{
+							UpdatedAt: now,
+						},
+					},
+					{
+						ID:          MustIDBase16(authTwoID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand",
+						Status:      platform.Active,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+						ExpiresAt:   timePtr(24 * time.Hour),
+						CRUDLog: platform.CRUDLog{
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "expired authorization cannot be rotated",
+			fields: fields(&platform.Authorization{
+				ID:          MustIDBase16(authOneID),
+				UserID:      MustIDBase16(userOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Token:       "supersecret",
+				Status:      platform.Active,
+				Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+				ExpiresAt:   timePtr(-time.Minute),
+			}),
+			args: args{
+				id:       MustIDBase16(authOneID),
+				rotation: platform.AuthorizationRotation{GracePeriod: time.Hour},
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EForbidden,
+					Msg:  platform.ErrAuthorizationExpired,
+				},
+				authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "supersecret",
+						Status:      platform.Active,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+						ExpiresAt:   timePtr(-time.Minute),
+					},
+				},
+			},
+		},
+		{
+			name: "rotate authorization with id not found",
+			fields: fields(&platform.Authorization{
+				ID:          MustIDBase16(authOneID),
+				UserID:      MustIDBase16(userOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Token:       "supersecret",
+				Status:      platform.Active,
+				Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+			}),
+			args: args{
+				id:       MustIDBase16(authThreeID),
+				rotation: platform.AuthorizationRotation{GracePeriod: time.Hour},
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Msg:  "authorization not found",
+				},
+				authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "supersecret",
+						Status:      platform.Active,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			a, err := s.RotateAuthorization(ctx, tt.args.id, tt.args.rotation)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+			if a != nil {
+				defer s.DeleteAuthorization(ctx, a.ID)
+			}
+
+			authorizations, _, err := s.FindAuthorizations(ctx, platform.AuthorizationFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve authorizations: %v", err)
+			}
+			if diff := cmp.Diff(authorizations, tt.wants.authorizations, authorizationCmpOptions...); diff != "" {
+				t.Errorf("authorizations are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
 func allUsersPermission(orgID platform.ID) []platform.Permission {
 	return []platform.Permission{
 		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.UsersResourceType, OrgID: &orgID}},
@@ -39,6 +39,30 @@ type BooleanArrayCursor interface {
 	Next() *BooleanArray
 }
 
+// BlockStats summarizes the values of a block of float, integer or unsigned
+// points of a TSM file. Sum, Min and Max hold the bits of values of the type
+// of the block, those of math.Float64bits for floats.
+type BlockStats struct {
+	Count         uint32
+	Sum, Min, Max uint64
+
+	// TimeOfMin and TimeOfMax are the times of the first points holding the
+	// min and max values.
+	TimeOfMin, TimeOfMax int64
+}
+
+// BlockStatsCursor is implemented by the array cursors able to skip the
+// points of whole blocks summarized by the index of a TSM file.
+type BlockStatsCursor interface {
+	// PeekBlockStats returns the time range and the statistics of the next
+	// points of the cursor when they are exactly the points of one block
+	// with statistics. The block is not decoded.
+	PeekBlockStats() (minTime, maxTime int64, stats BlockStats, ok bool)
+
+	// SkipBlock skips the points of the block returned by PeekBlockStats.
+	SkipBlock()
+}
+
 type CursorRequest struct {
 	Name      []byte
 	Tags      models.Tags
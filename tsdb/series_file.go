@@ -212,6 +212,23 @@ func (f *SeriesFile) DisableCompactions() {
 	}
 }
 
+// SnapshotTo writes a point-in-time copy of the series file to path, which
+// must not already contain a series file.
+func (f *SeriesFile) SnapshotTo(path string) error {
+	ref, err := f.Acquire()
+	if err != nil {
+		return err
+	}
+	defer ref.Release()
+
+	for _, p := range f.partitions {
+		if err := p.SnapshotTo(filepath.Join(path, filepath.Base(p.Path()))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateSeriesListIfNotExists creates a list of series in bulk if they don't exist. It overwrites
 // the collection's Keys and SeriesIDs fields. The collection's SeriesIDs slice will have IDs for
 // every name+tags, creating new series IDs as needed. If any SeriesID is zero, then a type
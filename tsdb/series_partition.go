@@ -483,6 +483,46 @@ func (p *SeriesPartition) compactionsEnabled() bool {
 	return p.compactionsDisabled == 0
 }
 
+// SnapshotTo writes a point-in-time copy of the partition to path. Full
+// segments and the on-disk index are never modified in place, so they are hard
+// linked. The active segment is still being appended to and is copied.
+func (p *SeriesPartition) SnapshotTo(path string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrSeriesPartitionClosed
+	}
+
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return err
+	}
+
+	active := p.activeSegment()
+	if err := active.Flush(); err != nil {
+		return err
+	}
+
+	for _, segment := range p.segments {
+		dst := filepath.Join(path, filepath.Base(segment.path))
+		if segment == active {
+			if err := fs.CopyFile(segment.path, dst); err != nil {
+				return fmt.Errorf("error copying series segment: %q", err)
+			}
+			continue
+		}
+		if err := os.Link(segment.path, dst); err != nil {
+			return fmt.Errorf("error creating series segment hard link: %q", err)
+		}
+	}
+
+	// The index file only exists once the partition has been compacted.
+	if err := os.Link(p.IndexPath(), filepath.Join(path, filepath.Base(p.IndexPath()))); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error creating series index hard link: %q", err)
+	}
+	return nil
+}
+
 // AppendSeriesIDs returns a list of all series ids.
 func (p *SeriesPartition) AppendSeriesIDs(a []SeriesID) []SeriesID {
 	for _, segment := range p.segments {
@@ -345,6 +345,23 @@ func (i *Index) Wait() {
 	}
 }
 
+// SnapshotTo writes a point-in-time copy of every partition of the index to
+// path.
+func (i *Index) SnapshotTo(path string) error {
+	ref, err := i.Acquire()
+	if err != nil {
+		return err
+	}
+	defer ref.Release()
+
+	for _, p := range i.partitions {
+		if err := p.SnapshotTo(filepath.Join(path, filepath.Base(p.Path()))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close closes the index.
 func (i *Index) Close() error {
 	// Lock index and close partitions.
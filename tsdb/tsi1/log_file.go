@@ -16,6 +16,7 @@ import (
 
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/bloom"
+	"github.com/influxdata/influxdb/pkg/fs"
 	"github.com/influxdata/influxdb/pkg/lifecycle"
 	"github.com/influxdata/influxdb/pkg/mmap"
 	"github.com/influxdata/influxdb/tsdb"
@@ -244,6 +245,42 @@ func (f *LogFile) FlushAndSync() error {
 	return f.file.Sync()
 }
 
+// CopyTo writes the entries currently in the log file to a new file at path.
+// Writes are blocked while the copy is made so it always ends on an entry
+// boundary.
+func (f *LogFile) CopyTo(path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.w != nil {
+		if err := f.w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	src, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := fs.CreateFile(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(dst, src, f.size); err != nil {
+		dst.Close()
+		return err
+	}
+
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
 // ID returns the file sequence identifier.
 func (f *LogFile) ID() int { return f.id }
 
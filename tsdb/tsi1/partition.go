@@ -452,6 +452,36 @@ func (p *Partition) manifest(fs *FileSet) *Manifest {
 	return m
 }
 
+// SnapshotTo writes a point-in-time copy of the partition to path. Index files
+// are immutable and are hard linked, log files are copied.
+func (p *Partition) SnapshotTo(path string) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return err
+	}
+
+	for _, f := range p.fileSet.files {
+		dst := filepath.Join(path, filepath.Base(f.Path()))
+		switch f := f.(type) {
+		case *LogFile:
+			if err := f.CopyTo(dst); err != nil {
+				return fmt.Errorf("error copying index log file: %q", err)
+			}
+		default:
+			if err := os.Link(f.Path(), dst); err != nil {
+				return fmt.Errorf("error creating index file hard link: %q", err)
+			}
+		}
+	}
+
+	m := p.manifest(p.fileSet)
+	m.path = filepath.Join(path, ManifestFileName)
+	_, err := m.Write()
+	return err
+}
+
 // WithLogger sets the logger for the index.
 func (p *Partition) WithLogger(logger *zap.Logger) {
 	p.logger = logger.With(zap.String("index", "tsi"))
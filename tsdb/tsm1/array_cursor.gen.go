@@ -26,6 +26,11 @@ type floatArrayAscendingCursor struct {
 		values    *tsdb.FloatArray
 		pos       int
 		keyCursor *KeyCursor
+
+		// pending is true if the next block of keyCursor is yet to be read
+		// from seek, which lets PeekBlockStats skip it without decoding it.
+		pending bool
+		seek    int64
 	}
 
 	end   int64
@@ -49,10 +54,10 @@ func (c *floatArrayAscendingCursor) reset(seek, end int64, cacheValues Values, t
 	})
 
 	c.tsm.keyCursor = tsmKeyCursor
-	c.tsm.values = c.readArrayBlock()
-	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
-		return c.tsm.values.Timestamps[i] >= seek
-	})
+	c.tsm.values = nil
+	c.tsm.pos = 0
+	c.tsm.pending = true
+	c.tsm.seek = seek
 }
 
 func (c *floatArrayAscendingCursor) Err() error { return nil }
@@ -71,6 +76,10 @@ func (c *floatArrayAscendingCursor) Stats() cursors.CursorStats { return c.stats
 
 // Next returns the next key/value for the cursor.
 func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
+	if c.tsm.pending {
+		c.readPendingTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -109,7 +118,7 @@ func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.nextPendingTSM()
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
@@ -117,7 +126,7 @@ func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
 				pos += n
 				c.tsm.pos += n
 				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
+					c.nextPendingTSM()
 				}
 			}
 		}
@@ -158,11 +167,53 @@ func (c *floatArrayAscendingCursor) nextTSM() *tsdb.FloatArray {
 	return c.tsm.values
 }
 
+// nextPendingTSM moves to the next block without reading it.
+func (c *floatArrayAscendingCursor) nextPendingTSM() {
+	c.tsm.keyCursor.Next()
+	c.tsm.pending = true
+}
+
+// readPendingTSM reads the pending block from the seek time.
+func (c *floatArrayAscendingCursor) readPendingTSM() {
+	c.tsm.pending = false
+	c.tsm.values = c.readArrayBlock()
+	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
+		return c.tsm.values.Timestamps[i] >= c.tsm.seek
+	})
+}
+
 func (c *floatArrayAscendingCursor) readArrayBlock() *tsdb.FloatArray {
 	values, _ := c.tsm.keyCursor.ReadFloatArrayBlock(c.tsm.buf)
 	return values
 }
 
+// PeekBlockStats returns the statistics of the pending block if its points
+// are the next points of the cursor.
+func (c *floatArrayAscendingCursor) PeekBlockStats() (minTime, maxTime int64, stats cursors.BlockStats, ok bool) {
+	if !c.tsm.pending || c.tsm.keyCursor == nil {
+		return 0, 0, stats, false
+	}
+
+	entry, ok := c.tsm.keyCursor.peekBlockStats()
+	if !ok || entry.MinTime < c.tsm.seek || entry.MaxTime >= c.end {
+		return 0, 0, stats, false
+	}
+
+	// Points of the cache before the end of the block are read first or
+	// replace points of the block.
+	if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() <= entry.MaxTime {
+		return 0, 0, stats, false
+	}
+	return entry.MinTime, entry.MaxTime, entry.Stats, true
+}
+
+// SkipBlock skips the block returned by PeekBlockStats.
+func (c *floatArrayAscendingCursor) SkipBlock() {
+	n := int(c.tsm.keyCursor.skipBlock())
+	c.stats.ScannedValues += n
+	c.stats.ScannedBytes += n * 8
+}
+
 type floatArrayDescendingCursor struct {
 	cache struct {
 		values Values
@@ -334,6 +385,11 @@ type integerArrayAscendingCursor struct {
 		values    *tsdb.IntegerArray
 		pos       int
 		keyCursor *KeyCursor
+
+		// pending is true if the next block of keyCursor is yet to be read
+		// from seek, which lets PeekBlockStats skip it without decoding it.
+		pending bool
+		seek    int64
 	}
 
 	end   int64
@@ -357,10 +413,10 @@ func (c *integerArrayAscendingCursor) reset(seek, end int64, cacheValues Values,
 	})
 
 	c.tsm.keyCursor = tsmKeyCursor
-	c.tsm.values = c.readArrayBlock()
-	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
-		return c.tsm.values.Timestamps[i] >= seek
-	})
+	c.tsm.values = nil
+	c.tsm.pos = 0
+	c.tsm.pending = true
+	c.tsm.seek = seek
 }
 
 func (c *integerArrayAscendingCursor) Err() error { return nil }
@@ -379,6 +435,10 @@ func (c *integerArrayAscendingCursor) Stats() cursors.CursorStats { return c.sta
 
 // Next returns the next key/value for the cursor.
 func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
+	if c.tsm.pending {
+		c.readPendingTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -417,7 +477,7 @@ func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.nextPendingTSM()
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
@@ -425,7 +485,7 @@ func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
 				pos += n
 				c.tsm.pos += n
 				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
+					c.nextPendingTSM()
 				}
 			}
 		}
@@ -466,11 +526,53 @@ func (c *integerArrayAscendingCursor) nextTSM() *tsdb.IntegerArray {
 	return c.tsm.values
 }
 
+// nextPendingTSM moves to the next block without reading it.
+func (c *integerArrayAscendingCursor) nextPendingTSM() {
+	c.tsm.keyCursor.Next()
+	c.tsm.pending = true
+}
+
+// readPendingTSM reads the pending block from the seek time.
+func (c *integerArrayAscendingCursor) readPendingTSM() {
+	c.tsm.pending = false
+	c.tsm.values = c.readArrayBlock()
+	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
+		return c.tsm.values.Timestamps[i] >= c.tsm.seek
+	})
+}
+
 func (c *integerArrayAscendingCursor) readArrayBlock() *tsdb.IntegerArray {
 	values, _ := c.tsm.keyCursor.ReadIntegerArrayBlock(c.tsm.buf)
 	return values
 }
 
+// PeekBlockStats returns the statistics of the pending block if its points
+// are the next points of the cursor.
+func (c *integerArrayAscendingCursor) PeekBlockStats() (minTime, maxTime int64, stats cursors.BlockStats, ok bool) {
+	if !c.tsm.pending || c.tsm.keyCursor == nil {
+		return 0, 0, stats, false
+	}
+
+	entry, ok := c.tsm.keyCursor.peekBlockStats()
+	if !ok || entry.MinTime < c.tsm.seek || entry.MaxTime >= c.end {
+		return 0, 0, stats, false
+	}
+
+	// Points of the cache before the end of the block are read first or
+	// replace points of the block.
+	if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() <= entry.MaxTime {
+		return 0, 0, stats, false
+	}
+	return entry.MinTime, entry.MaxTime, entry.Stats, true
+}
+
+// SkipBlock skips the block returned by PeekBlockStats.
+func (c *integerArrayAscendingCursor) SkipBlock() {
+	n := int(c.tsm.keyCursor.skipBlock())
+	c.stats.ScannedValues += n
+	c.stats.ScannedBytes += n * 8
+}
+
 type integerArrayDescendingCursor struct {
 	cache struct {
 		values Values
@@ -642,6 +744,11 @@ type unsignedArrayAscendingCursor struct {
 		values    *tsdb.UnsignedArray
 		pos       int
 		keyCursor *KeyCursor
+
+		// pending is true if the next block of keyCursor is yet to be read
+		// from seek, which lets PeekBlockStats skip it without decoding it.
+		pending bool
+		seek    int64
 	}
 
 	end   int64
@@ -665,10 +772,10 @@ func (c *unsignedArrayAscendingCursor) reset(seek, end int64, cacheValues Values
 	})
 
 	c.tsm.keyCursor = tsmKeyCursor
-	c.tsm.values = c.readArrayBlock()
-	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
-		return c.tsm.values.Timestamps[i] >= seek
-	})
+	c.tsm.values = nil
+	c.tsm.pos = 0
+	c.tsm.pending = true
+	c.tsm.seek = seek
 }
 
 func (c *unsignedArrayAscendingCursor) Err() error { return nil }
@@ -687,6 +794,10 @@ func (c *unsignedArrayAscendingCursor) Stats() cursors.CursorStats { return c.st
 
 // Next returns the next key/value for the cursor.
 func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
+	if c.tsm.pending {
+		c.readPendingTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -725,7 +836,7 @@ func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.nextPendingTSM()
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
@@ -733,7 +844,7 @@ func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
 				pos += n
 				c.tsm.pos += n
 				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
+					c.nextPendingTSM()
 				}
 			}
 		}
@@ -774,11 +885,53 @@ func (c *unsignedArrayAscendingCursor) nextTSM() *tsdb.UnsignedArray {
 	return c.tsm.values
 }
 
+// nextPendingTSM moves to the next block without reading it.
+func (c *unsignedArrayAscendingCursor) nextPendingTSM() {
+	c.tsm.keyCursor.Next()
+	c.tsm.pending = true
+}
+
+// readPendingTSM reads the pending block from the seek time.
+func (c *unsignedArrayAscendingCursor) readPendingTSM() {
+	c.tsm.pending = false
+	c.tsm.values = c.readArrayBlock()
+	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
+		return c.tsm.values.Timestamps[i] >= c.tsm.seek
+	})
+}
+
 func (c *unsignedArrayAscendingCursor) readArrayBlock() *tsdb.UnsignedArray {
 	values, _ := c.tsm.keyCursor.ReadUnsignedArrayBlock(c.tsm.buf)
 	return values
 }
 
+// PeekBlockStats returns the statistics of the pending block if its points
+// are the next points of the cursor.
+func (c *unsignedArrayAscendingCursor) PeekBlockStats() (minTime, maxTime int64, stats cursors.BlockStats, ok bool) {
+	if !c.tsm.pending || c.tsm.keyCursor == nil {
+		return 0, 0, stats, false
+	}
+
+	entry, ok := c.tsm.keyCursor.peekBlockStats()
+	if !ok || entry.MinTime < c.tsm.seek || entry.MaxTime >= c.end {
+		return 0, 0, stats, false
+	}
+
+	// Points of the cache before the end of the block are read first or
+	// replace points of the block.
+	if c.cache.pos < len(c.cache.values) && c.cache.values[c.cache.pos].UnixNano() <= entry.MaxTime {
+		return 0, 0, stats, false
+	}
+	return entry.MinTime, entry.MaxTime, entry.Stats, true
+}
+
+// SkipBlock skips the block returned by PeekBlockStats.
+func (c *unsignedArrayAscendingCursor) SkipBlock() {
+	n := int(c.tsm.keyCursor.skipBlock())
+	c.stats.ScannedValues += n
+	c.stats.ScannedBytes += n * 8
+}
+
 type unsignedArrayDescendingCursor struct {
 	cache struct {
 		values Values
@@ -950,6 +1103,11 @@ type stringArrayAscendingCursor struct {
 		values    *tsdb.StringArray
 		pos       int
 		keyCursor *KeyCursor
+
+		// pending is true if the next block of keyCursor is yet to be read
+		// from seek, which lets PeekBlockStats skip it without decoding it.
+		pending bool
+		seek    int64
 	}
 
 	end   int64
@@ -973,10 +1131,10 @@ func (c *stringArrayAscendingCursor) reset(seek, end int64, cacheValues Values,
 	})
 
 	c.tsm.keyCursor = tsmKeyCursor
-	c.tsm.values = c.readArrayBlock()
-	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
-		return c.tsm.values.Timestamps[i] >= seek
-	})
+	c.tsm.values = nil
+	c.tsm.pos = 0
+	c.tsm.pending = true
+	c.tsm.seek = seek
 }
 
 func (c *stringArrayAscendingCursor) Err() error { return nil }
@@ -995,6 +1153,10 @@ func (c *stringArrayAscendingCursor) Stats() cursors.CursorStats { return c.stat
 
 // Next returns the next key/value for the cursor.
 func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
+	if c.tsm.pending {
+		c.readPendingTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -1033,7 +1195,7 @@ func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.nextPendingTSM()
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
@@ -1041,7 +1203,7 @@ func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
 				pos += n
 				c.tsm.pos += n
 				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
+					c.nextPendingTSM()
 				}
 			}
 		}
@@ -1084,6 +1246,21 @@ func (c *stringArrayAscendingCursor) nextTSM() *tsdb.StringArray {
 	return c.tsm.values
 }
 
+// nextPendingTSM moves to the next block without reading it.
+func (c *stringArrayAscendingCursor) nextPendingTSM() {
+	c.tsm.keyCursor.Next()
+	c.tsm.pending = true
+}
+
+// readPendingTSM reads the pending block from the seek time.
+func (c *stringArrayAscendingCursor) readPendingTSM() {
+	c.tsm.pending = false
+	c.tsm.values = c.readArrayBlock()
+	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
+		return c.tsm.values.Timestamps[i] >= c.tsm.seek
+	})
+}
+
 func (c *stringArrayAscendingCursor) readArrayBlock() *tsdb.StringArray {
 	values, _ := c.tsm.keyCursor.ReadStringArrayBlock(c.tsm.buf)
 	return values
@@ -1262,6 +1439,11 @@ type booleanArrayAscendingCursor struct {
 		values    *tsdb.BooleanArray
 		pos       int
 		keyCursor *KeyCursor
+
+		// pending is true if the next block of keyCursor is yet to be read
+		// from seek, which lets PeekBlockStats skip it without decoding it.
+		pending bool
+		seek    int64
 	}
 
 	end   int64
@@ -1285,10 +1467,10 @@ func (c *booleanArrayAscendingCursor) reset(seek, end int64, cacheValues Values,
 	})
 
 	c.tsm.keyCursor = tsmKeyCursor
-	c.tsm.values = c.readArrayBlock()
-	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
-		return c.tsm.values.Timestamps[i] >= seek
-	})
+	c.tsm.values = nil
+	c.tsm.pos = 0
+	c.tsm.pending = true
+	c.tsm.seek = seek
 }
 
 func (c *booleanArrayAscendingCursor) Err() error { return nil }
@@ -1307,6 +1489,10 @@ func (c *booleanArrayAscendingCursor) Stats() cursors.CursorStats { return c.sta
 
 // Next returns the next key/value for the cursor.
 func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
+	if c.tsm.pending {
+		c.readPendingTSM()
+	}
+
 	pos := 0
 	cvals := c.cache.values
 	tvals := c.tsm.values
@@ -1345,7 +1531,7 @@ func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
 				// optimization: all points served from TSM data
 				copy(c.res.Timestamps, tvals.Timestamps)
 				pos += copy(c.res.Values, tvals.Values)
-				c.nextTSM()
+				c.nextPendingTSM()
 			} else {
 				// copy as much as we can
 				n := copy(c.res.Timestamps[pos:], tvals.Timestamps[c.tsm.pos:])
@@ -1353,7 +1539,7 @@ func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
 				pos += n
 				c.tsm.pos += n
 				if c.tsm.pos >= len(tvals.Timestamps) {
-					c.nextTSM()
+					c.nextPendingTSM()
 				}
 			}
 		}
@@ -1394,6 +1580,21 @@ func (c *booleanArrayAscendingCursor) nextTSM() *tsdb.BooleanArray {
 	return c.tsm.values
 }
 
+// nextPendingTSM moves to the next block without reading it.
+func (c *booleanArrayAscendingCursor) nextPendingTSM() {
+	c.tsm.keyCursor.Next()
+	c.tsm.pending = true
+}
+
+// readPendingTSM reads the pending block from the seek time.
+func (c *booleanArrayAscendingCursor) readPendingTSM() {
+	c.tsm.pending = false
+	c.tsm.values = c.readArrayBlock()
+	c.tsm.pos = sort.Search(c.tsm.values.Len(), func(i int) bool {
+		return c.tsm.values.Timestamps[i] >= c.tsm.seek
+	})
+}
+
 func (c *booleanArrayAscendingCursor) readArrayBlock() *tsdb.BooleanArray {
 	values, _ := c.tsm.keyCursor.ReadBooleanArrayBlock(c.tsm.buf)
 	return values
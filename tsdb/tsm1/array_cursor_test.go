@@ -133,3 +133,129 @@ func TestFileStore_DuplicatePoints(t *testing.T) {
 		}
 	})
 }
+
+func TestFloatArrayAscendingCursor_BlockStats(t *testing.T) {
+	key := []byte("m,_field=v#!~#v")
+	makeVals := func(ts ...int64) []Value {
+		vals := make([]Value, len(ts))
+		for i, t := range ts {
+			vals[i] = NewFloatValue(t, float64(t))
+		}
+		return vals
+	}
+
+	tests := []struct {
+		name       string
+		seek, end  int64
+		cache      []Value
+		tombstones []TimeRange
+		skipped    []int64 // min times of the skipped blocks
+		exp        []int64
+	}{
+		{
+			name:    "all blocks",
+			seek:    0,
+			end:     100,
+			skipped: []int64{0, 10},
+		},
+		{
+			name:    "cache overlapping block",
+			seek:    0,
+			end:     100,
+			cache:   makeVals(12),
+			skipped: []int64{0},
+			exp:     []int64{10, 11, 12, 13},
+		},
+		{
+			name:    "seek within block",
+			seek:    2,
+			end:     100,
+			skipped: []int64{10},
+			exp:     []int64{2, 3},
+		},
+		{
+			name:    "end within block",
+			seek:    0,
+			end:     12,
+			skipped: []int64{0},
+			exp:     []int64{10, 11},
+		},
+		{
+			name:       "tombstone",
+			seek:       0,
+			end:        100,
+			tombstones: []TimeRange{{Min: 1, Max: 1}},
+			skipped:    []int64{10},
+			exp:        []int64{0, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := MustTempDir()
+			defer os.RemoveAll(dir)
+			store := NewFileStore(dir)
+			defer store.Close()
+
+			f := MustTempFile(dir)
+			w, err := NewTSMWriter(f, WithBlockStats(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, values := range [][]Value{makeVals(0, 1, 2, 3), makeVals(10, 11, 12, 13)} {
+				if err := w.Write(key, values); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.WriteIndex(); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			name := filepath.Join(dir, DefaultFormatFileName(1, 1)+".tsm")
+			if err := fs.RenameFile(f.Name(), name); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Replace(nil, []string{name}); err != nil {
+				t.Fatal(err)
+			}
+			for _, tr := range tt.tombstones {
+				if err := store.DeleteRange([][]byte{key}, tr.Min, tr.Max); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			kc := store.KeyCursor(context.Background(), key, tt.seek, true)
+			cur := newFloatArrayAscendingCursor()
+			cur.reset(tt.seek, tt.end, tt.cache, kc)
+			defer cur.Close()
+
+			var skipped, got []int64
+			var count int
+			for {
+				if minT, _, stats, ok := cur.PeekBlockStats(); ok {
+					skipped = append(skipped, minT)
+					count += int(stats.Count)
+					cur.SkipBlock()
+					continue
+				}
+				a := cur.Next()
+				if a.Len() == 0 {
+					break
+				}
+				got = append(got, a.Timestamps...)
+				count += a.Len()
+			}
+
+			if !cmp.Equal(skipped, tt.skipped) {
+				t.Errorf("unexpected skipped blocks; -got/+exp\n%s", cmp.Diff(skipped, tt.skipped))
+			}
+			if !cmp.Equal(got, tt.exp) {
+				t.Errorf("unexpected values; -got/+exp\n%s", cmp.Diff(got, tt.exp))
+			}
+			if got, exp := cur.Stats().ScannedValues, count; got != exp {
+				t.Errorf("unexpected scanned values: got %d, exp %d", got, exp)
+			}
+		})
+	}
+}
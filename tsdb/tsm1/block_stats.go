@@ -0,0 +1,153 @@
+package tsm1
+
+import (
+	"math"
+
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// hasBlockStats returns true if the values of the blocks of type typ can be
+// summarized in the index of a TSM file.
+func hasBlockStats(typ byte) bool {
+	switch typ {
+	case BlockFloat64, BlockInteger, BlockUnsigned:
+		return true
+	}
+	return false
+}
+
+// valuesBlockStats returns the statistics of the values of a block. It
+// returns zero statistics if the values cannot be summarized.
+func valuesBlockStats(values Values) cursors.BlockStats {
+	var s cursors.BlockStats
+	if len(values) == 0 {
+		return s
+	}
+
+	s.TimeOfMin, s.TimeOfMax = values[0].UnixNano(), values[0].UnixNano()
+	switch v0 := values[0].(type) {
+	case FloatValue:
+		sum, min, max := v0.RawValue(), v0.RawValue(), v0.RawValue()
+		for _, v := range values[1:] {
+			fv := v.(FloatValue).RawValue()
+			sum += fv
+			if fv < min {
+				min, s.TimeOfMin = fv, v.UnixNano()
+			}
+			if fv > max {
+				max, s.TimeOfMax = fv, v.UnixNano()
+			}
+		}
+		s.Sum, s.Min, s.Max = math.Float64bits(sum), math.Float64bits(min), math.Float64bits(max)
+	case IntegerValue:
+		sum, min, max := v0.RawValue(), v0.RawValue(), v0.RawValue()
+		for _, v := range values[1:] {
+			iv := v.(IntegerValue).RawValue()
+			sum += iv
+			if iv < min {
+				min, s.TimeOfMin = iv, v.UnixNano()
+			}
+			if iv > max {
+				max, s.TimeOfMax = iv, v.UnixNano()
+			}
+		}
+		s.Sum, s.Min, s.Max = uint64(sum), uint64(min), uint64(max)
+	case UnsignedValue:
+		sum, min, max := v0.RawValue(), v0.RawValue(), v0.RawValue()
+		for _, v := range values[1:] {
+			uv := v.(UnsignedValue).RawValue()
+			sum += uv
+			if uv < min {
+				min, s.TimeOfMin = uv, v.UnixNano()
+			}
+			if uv > max {
+				max, s.TimeOfMax = uv, v.UnixNano()
+			}
+		}
+		s.Sum, s.Min, s.Max = sum, min, max
+	default:
+		return cursors.BlockStats{}
+	}
+
+	s.Count = uint32(len(values))
+	return s
+}
+
+// blockStats decodes block and returns the statistics of its values. It
+// returns zero statistics if the values cannot be summarized.
+func blockStats(block []byte) (cursors.BlockStats, error) {
+	var s cursors.BlockStats
+
+	typ, err := BlockType(block)
+	if err != nil {
+		return s, err
+	}
+
+	switch typ {
+	case BlockFloat64:
+		var a tsdb.FloatArray
+		if err := DecodeFloatArrayBlock(block, &a); err != nil {
+			return s, err
+		}
+		if a.Len() == 0 {
+			return s, nil
+		}
+		sum, min, max := a.Values[0], a.Values[0], a.Values[0]
+		s.TimeOfMin, s.TimeOfMax = a.Timestamps[0], a.Timestamps[0]
+		for i, v := range a.Values[1:] {
+			sum += v
+			if v < min {
+				min, s.TimeOfMin = v, a.Timestamps[i+1]
+			}
+			if v > max {
+				max, s.TimeOfMax = v, a.Timestamps[i+1]
+			}
+		}
+		s.Count = uint32(a.Len())
+		s.Sum, s.Min, s.Max = math.Float64bits(sum), math.Float64bits(min), math.Float64bits(max)
+	case BlockInteger:
+		var a tsdb.IntegerArray
+		if err := DecodeIntegerArrayBlock(block, &a); err != nil {
+			return s, err
+		}
+		if a.Len() == 0 {
+			return s, nil
+		}
+		sum, min, max := a.Values[0], a.Values[0], a.Values[0]
+		s.TimeOfMin, s.TimeOfMax = a.Timestamps[0], a.Timestamps[0]
+		for i, v := range a.Values[1:] {
+			sum += v
+			if v < min {
+				min, s.TimeOfMin = v, a.Timestamps[i+1]
+			}
+			if v > max {
+				max, s.TimeOfMax = v, a.Timestamps[i+1]
+			}
+		}
+		s.Count = uint32(a.Len())
+		s.Sum, s.Min, s.Max = uint64(sum), uint64(min), uint64(max)
+	case BlockUnsigned:
+		var a tsdb.UnsignedArray
+		if err := DecodeUnsignedArrayBlock(block, &a); err != nil {
+			return s, err
+		}
+		if a.Len() == 0 {
+			return s, nil
+		}
+		sum, min, max := a.Values[0], a.Values[0], a.Values[0]
+		s.TimeOfMin, s.TimeOfMax = a.Timestamps[0], a.Timestamps[0]
+		for i, v := range a.Values[1:] {
+			sum += v
+			if v < min {
+				min, s.TimeOfMin = v, a.Timestamps[i+1]
+			}
+			if v > max {
+				max, s.TimeOfMax = v, a.Timestamps[i+1]
+			}
+		}
+		s.Count = uint32(a.Len())
+		s.Sum, s.Min, s.Max = sum, min, max
+	}
+	return s, nil
+}
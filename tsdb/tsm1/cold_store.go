@@ -0,0 +1,318 @@
+package tsm1
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/pkg/fs"
+)
+
+// ErrColdObjectNotFound is returned when an object does not exist in a ColdStore.
+var ErrColdObjectNotFound = errors.New("cold tier object not found")
+
+// ColdStore is a store of the TSM files moved to the cold tier of the engine.
+type ColdStore interface {
+	// Put stores the size bytes read from r as the object name.
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+
+	// Get returns the contents of the object name.
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+
+	// Delete removes the object name. Removing an object that does not exist
+	// is not an error.
+	Delete(ctx context.Context, name string) error
+}
+
+// NewColdStore returns the ColdStore configured by c, or nil if c does not
+// configure a cold tier.
+func NewColdStore(c ColdTierConfig) (ColdStore, error) {
+	if c.Path == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(c.Path, "s3://") {
+		return NewDirColdStore(c.Path), nil
+	}
+
+	u, err := url.Parse(c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cold tier path %q: %v", c.Path, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid cold tier path %q: missing bucket", c.Path)
+	}
+	if c.S3.Endpoint == "" {
+		return nil, errors.New("an S3 endpoint is required to store the cold tier in a bucket")
+	}
+	endpoint, err := url.Parse(c.S3.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint %q: %v", c.S3.Endpoint, err)
+	}
+
+	s := NewS3ColdStore(endpoint, u.Host, strings.Trim(u.Path, "/"))
+	s.Region = c.S3.Region
+	s.AccessKeyID = c.S3.AccessKeyID
+	s.SecretAccessKey = c.S3.SecretAccessKey
+	return s, nil
+}
+
+// DirColdStore is a ColdStore keeping its objects as files of a directory,
+// typically on a cheaper disk or a network mount.
+type DirColdStore struct {
+	dir string
+}
+
+// NewDirColdStore returns a DirColdStore keeping its objects in dir.
+func NewDirColdStore(dir string) *DirColdStore {
+	return &DirColdStore{dir: dir}
+}
+
+// Put stores the size bytes read from r as the object name.
+func (s *DirColdStore) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := os.MkdirAll(s.dir, 0777); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, name+".*."+TmpTSMFileExtension)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.CopyN(tmp, r, size); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := fs.RenameFileWithReplacement(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+	return fs.SyncDir(s.dir)
+}
+
+// Get returns the contents of the object name.
+func (s *DirColdStore) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return nil, ErrColdObjectNotFound
+	}
+	return f, err
+}
+
+// Delete removes the object name.
+func (s *DirColdStore) Delete(ctx context.Context, name string) error {
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// S3ColdStore is a ColdStore keeping its objects in a bucket of an S3
+// compatible object store, such as MinIO. Requests use path-style addressing
+// and are signed with AWS Signature Version 4.
+type S3ColdStore struct {
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+
+	// Region is the region of the bucket. It defaults to us-east-1.
+	Region string
+
+	// AccessKeyID and SecretAccessKey are the credentials signing the
+	// requests. Requests are anonymous when AccessKeyID is empty.
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// Client performs the requests. It defaults to http.DefaultClient.
+	Client *http.Client
+
+	now func() time.Time
+}
+
+// NewS3ColdStore returns an S3ColdStore keeping its objects in bucket of the
+// object store served at endpoint. The names of the objects are prefixed with
+// prefix.
+func NewS3ColdStore(endpoint *url.URL, bucket, prefix string) *S3ColdStore {
+	return &S3ColdStore{
+		endpoint: endpoint,
+		bucket:   bucket,
+		prefix:   prefix,
+		now:      time.Now,
+	}
+}
+
+// Put stores the size bytes read from r as the object name.
+func (s *S3ColdStore) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	req, err := s.newRequest(ctx, http.MethodPut, name, ioutil.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get returns the contents of the object name.
+func (s *S3ColdStore) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes the object name.
+func (s *S3ColdStore) Delete(ctx context.Context, name string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, name, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err == ErrColdObjectNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3ColdStore) newRequest(ctx context.Context, method, name string, body io.ReadCloser) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = "/" + path.Join(s.bucket, s.prefix, name)
+	u.RawPath = s3EscapePath(u.Path)
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = body
+	return req.WithContext(ctx), nil
+}
+
+func (s *S3ColdStore) do(req *http.Request) (*http.Response, error) {
+	s.sign(req)
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrColdObjectNotFound
+	}
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("cold tier request %s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, msg)
+}
+
+// sign adds the AWS Signature Version 4 authorization to req. The payload of
+// the request is not signed, so that it can be streamed.
+func (s *S3ColdStore) sign(req *http.Request) {
+	const payloadHash = "UNSIGNED-PAYLOAD"
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.AccessKeyID == "" {
+		return
+	}
+
+	region := s.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	scope := strings.Join([]string{now.Format("20060102"), region, "s3", "aws4_request"}, "/")
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	h := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(h[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + s.SecretAccessKey)
+	for _, part := range strings.Split(scope, "/") {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3EscapePath escapes p as expected by the canonical requests of signatures,
+// which leave only the unreserved characters and the path separators as is.
+func s3EscapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package tsm1_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// fakeS3 is an in-memory object store serving path-style S3 requests.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") ||
+		r.Header.Get("X-Amz-Date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		b, _ := ioutil.ReadAll(r.Body)
+		s.objects[r.URL.Path] = b
+	case http.MethodGet:
+		b, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(b)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3ColdStore(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	store, err := tsm1.NewColdStore(tsm1.ColdTierConfig{
+		Path: "s3://bucket/prefix",
+		S3: tsm1.S3Config{
+			Endpoint:        srv.URL,
+			AccessKeyID:     "key",
+			SecretAccessKey: "secret",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	data := []byte("tsm file")
+	if err := store.Put(ctx, "000000001-000000004.tsm", bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fake.objects["/bucket/prefix/000000001-000000004.tsm"]; !ok {
+		t.Fatalf("object not stored at the expected path: %v", fake.objects)
+	}
+
+	r, err := store.Get(ctx, "000000001-000000004.tsm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(got, data) {
+		t.Fatalf("got object %q, expected %q", got, data)
+	}
+
+	if err := store.Delete(ctx, "000000001-000000004.tsm"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "000000001-000000004.tsm"); err != tsm1.ErrColdObjectNotFound {
+		t.Fatalf("got error %v getting deleted object, expected %v", err, tsm1.ErrColdObjectNotFound)
+	}
+
+	if _, err := tsm1.NewColdStore(tsm1.ColdTierConfig{Path: "s3://bucket"}); err == nil {
+		t.Fatal("expected error creating an S3 store without endpoint")
+	}
+	if _, err := tsm1.NewColdStore(tsm1.ColdTierConfig{Path: "s3://bucket", S3: tsm1.S3Config{Endpoint: ":"}}); err == nil {
+		t.Fatal("expected error creating an S3 store with an invalid endpoint")
+	}
+}
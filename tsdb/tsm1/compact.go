@@ -627,6 +627,11 @@ func (c *DefaultPlanner) findGenerations(skipInUse bool) tsmGenerations {
 			continue
 		}
 
+		// Files moved to the cold tier are not compacted any further.
+		if f.Cold {
+			continue
+		}
+
 		group := generations[gen]
 		if group == nil {
 			group = newTsmGeneration(gen, c.ParseFileName)
@@ -698,6 +703,10 @@ type Compactor struct {
 	// RateLimit is the limit for disk writes for all concurrent compactions.
 	RateLimit limiter.Rate
 
+	// BlockStats enables the statistics of blocks in the index of the
+	// written TSM files.
+	BlockStats bool
+
 	formatFileName FormatFileNameFunc
 	parseFileName  ParseFileNameFunc
 
@@ -1111,12 +1120,12 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 	// Use a disk based TSM buffer if it looks like we might create a big index
 	// in memory.
 	if iter.EstimatedIndexSize() > 64*1024*1024 {
-		w, err = NewTSMWriterWithDiskBuffer(limitWriter)
+		w, err = NewTSMWriterWithDiskBuffer(limitWriter, WithBlockStats(c.BlockStats))
 		if err != nil {
 			return err
 		}
 	} else {
-		w, err = NewTSMWriter(limitWriter)
+		w, err = NewTSMWriter(limitWriter, WithBlockStats(c.BlockStats))
 		if err != nil {
 			return err
 		}
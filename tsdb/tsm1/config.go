@@ -33,8 +33,15 @@ type Config struct {
 	// preallocation to improve throughput. Currently used in the series file.
 	LargeSeriesWriteThreshold int `toml:"large-series-write-threshold"`
 
+	// BlockStats controls whether the TSM files written by snapshots and
+	// compactions store the count, sum, min and max of the values of their
+	// float, integer and unsigned blocks in their index. Window aggregates
+	// read the statistics instead of decoding the blocks they fully cover.
+	BlockStats bool `toml:"block-stats"`
+
 	Compaction CompactionConfig `toml:"compaction"`
 	Cache      CacheConfig      `toml:"cache"`
+	ColdTier   ColdTierConfig   `toml:"cold-tier"`
 }
 
 // NewConfig constructs a Config with the default values.
@@ -84,6 +91,32 @@ type CompactionConfig struct {
 	MaxConcurrent int `toml:"max-concurrent"`
 }
 
+// ColdTierConfig holds the configuration of the store of the TSM files moved
+// to the cold tier of the engine.
+type ColdTierConfig struct {
+	// Path is the directory the cold TSM files are moved to, or a URL of the
+	// form s3://bucket/prefix to move them to a bucket of an S3 compatible
+	// object store. An empty path disables the cold tier. Engines may share
+	// a path, their objects are named after the ID kept in their directory.
+	Path string `toml:"path"`
+
+	// S3 configures the object store when Path is a s3:// URL.
+	S3 S3Config `toml:"s3"`
+}
+
+// S3Config holds the configuration of an S3 compatible object store.
+type S3Config struct {
+	// Endpoint is the URL of the object store, such as http://localhost:9000.
+	Endpoint string `toml:"endpoint"`
+
+	// Region is the region of the bucket. It defaults to us-east-1.
+	Region string `toml:"region"`
+
+	// AccessKeyID and SecretAccessKey are the credentials used to access the bucket.
+	AccessKeyID     string `toml:"access-key-id"`
+	SecretAccessKey string `toml:"secret-access-key"`
+}
+
 // Default Cache configuration values.
 const (
 	DefaultCacheMaxMemorySize             = toml.Size(1024 << 20)           // 1GB
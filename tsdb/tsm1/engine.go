@@ -181,6 +181,7 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 	c := NewCompactor()
 	c.Dir = path
 	c.FileStore = fs
+	c.BlockStats = config.BlockStats
 	c.RateLimit = limiter.NewRate(
 		int(config.Compaction.Throughput),
 		int(config.Compaction.ThroughputBurst))
@@ -267,6 +268,11 @@ func (e *Engine) WithFileStoreObserver(obs FileStoreObserver) {
 	e.FileStore.WithObserver(obs)
 }
 
+// WithColdStore sets the store of the TSM files moved to the cold tier.
+func (e *Engine) WithColdStore(store ColdStore) {
+	e.FileStore.WithColdStore(store)
+}
+
 func (e *Engine) WithCompactionPlanner(planner CompactionPlanner) {
 	planner.SetFileStore(e.FileStore)
 	e.CompactionPlan = planner
@@ -463,6 +469,28 @@ func (e *Engine) ScheduleFullCompaction(ctx context.Context) error {
 	return nil
 }
 
+// CreateSnapshot writes any data in the cache to a TSM file and then hard links
+// all TSM and tombstone files into a new directory within the engine's path.
+// TSM files on the cold tier are copied from it instead.
+// The path of the directory is returned.
+func (e *Engine) CreateSnapshot(ctx context.Context) (string, error) {
+	if err := e.WriteSnapshot(ctx, CacheStatusBackup); err != nil {
+		return "", err
+	}
+	return e.FileStore.CreateSnapshot(ctx)
+}
+
+// MoveToColdTier moves the fully compacted TSM files for which fn returns true
+// to the cold tier, then frees the copies of the cold files that were not read
+// since the previous call. It returns the number of files moved.
+func (e *Engine) MoveToColdTier(ctx context.Context, fn func(FileStat) bool) (int, error) {
+	n, err := e.FileStore.MoveToColdTier(ctx, fn)
+	if err != nil {
+		return n, err
+	}
+	return n, e.FileStore.FreeColdFiles()
+}
+
 // Path returns the path the engine was opened with.
 func (e *Engine) Path() string { return e.path }
 
@@ -932,6 +960,7 @@ const (
 	CacheStatusColdNoWrites                      // The cache has not been written to for long enough that it should be snapshotted.
 	CacheStatusRetention                         // The cache was snapshotted before running retention.
 	CacheStatusFullCompaction                    // The cache was snapshotted as part of a full compaction.
+	CacheStatusBackup                            // The cache was snapshotted before creating a backup.
 )
 
 // ShouldCompactCache returns a status indicating if the Cache should be
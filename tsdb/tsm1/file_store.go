@@ -217,6 +217,9 @@ type FileStore struct {
 	parseFileName ParseFileNameFunc
 
 	obs FileStoreObserver
+
+	coldStore ColdStore // Store of the files moved to the cold tier, if any.
+	coldID    string    // Prefix of the names of the objects of the store in the cold store.
 }
 
 // FileStat holds information about a TSM file on disk.
@@ -227,6 +230,7 @@ type FileStat struct {
 	LastModified     int64
 	MinTime, MaxTime int64
 	MinKey, MaxKey   []byte
+	Cold             bool // Whether the file was moved to the cold tier.
 }
 
 // OverlapsTimeRange returns true if the time range of the file intersect min and max.
@@ -272,6 +276,12 @@ func (f *FileStore) WithObserver(obs FileStoreObserver) {
 	f.obs = obs
 }
 
+// WithColdStore sets the store of the files moved to the cold tier. It must be
+// called before the file store is opened.
+func (f *FileStore) WithColdStore(store ColdStore) {
+	f.coldStore = store
+}
+
 func (f *FileStore) WithParseFileNameFunc(parseFileNameFunc ParseFileNameFunc) {
 	f.parseFileName = parseFileNameFunc
 }
@@ -623,6 +633,16 @@ func (f *FileStore) Open(ctx context.Context) error {
 		}
 	}
 
+	// Copies of cold files fetched before a restart are not reused.
+	if err := os.RemoveAll(f.coldCacheDir()); err != nil {
+		return err
+	}
+	if f.coldStore != nil {
+		if f.coldID, err = f.loadColdID(); err != nil {
+			return err
+		}
+	}
+
 	files, err := filepath.Glob(filepath.Join(f.dir, fmt.Sprintf("*.%s", TSMFileExtension)))
 	if err != nil {
 		return err
@@ -659,9 +679,7 @@ func (f *FileStore) Open(ctx context.Context) error {
 			defer f.openLimiter.Release()
 
 			start := time.Now()
-			df, err := NewTSMReader(file,
-				WithMadviseWillNeed(f.tsmMMAPWillNeed),
-				WithTSMReaderLogger(f.logger))
+			df, err := NewTSMReader(file, f.readerOptions()...)
 			f.logger.Info("Opened file",
 				zap.String("path", file.Name()),
 				zap.Int("id", idx),
@@ -900,9 +918,7 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 			}
 		}
 
-		tsm, err := NewTSMReader(fd,
-			WithMadviseWillNeed(f.tsmMMAPWillNeed),
-			WithTSMReaderLogger(f.logger))
+		tsm, err := NewTSMReader(fd, f.readerOptions()...)
 		if err != nil {
 			return err
 		}
@@ -1207,7 +1223,7 @@ func (f *FileStore) locations(key []byte, t int64, ascending bool) []*location {
 }
 
 // CreateSnapshot creates hardlinks for all tsm and tombstone files
-// in the path provided.
+// in the path provided. The tsm files on the cold tier are copied from it.
 func (f *FileStore) CreateSnapshot(ctx context.Context) (string, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
@@ -1240,7 +1256,13 @@ func (f *FileStore) CreateSnapshot(ctx context.Context) (string, error) {
 	}
 	for _, tsmf := range files {
 		newpath := filepath.Join(tmpPath, filepath.Base(tsmf.Path()))
-		if err := os.Link(tsmf.Path(), newpath); err != nil {
+		if r, ok := tsmf.(*TSMReader); ok && r.IsCold() {
+			// The local copy of a cold file has no blocks, so the snapshot
+			// gets the file from the cold tier.
+			if err := f.copyColdFile(ctx, r, newpath); err != nil {
+				return "", fmt.Errorf("error copying cold tsm file: %q", err)
+			}
+		} else if err := os.Link(tsmf.Path(), newpath); err != nil {
 			return "", fmt.Errorf("error creating tsm hard link: %q", err)
 		}
 		for _, tf := range tsmf.TombstoneFiles() {
@@ -1481,6 +1503,44 @@ func (c *KeyCursor) Next() {
 	}
 }
 
+// peekBlockStats returns the index entry of the next block of an ascending
+// cursor if its statistics summarize the next points of the cursor: none of
+// the points of the block were read and no tombstone or other unread block
+// overlaps it.
+func (c *KeyCursor) peekBlockStats() (IndexEntry, bool) {
+	if !c.ascending || len(c.current) == 0 {
+		return IndexEntry{}, false
+	}
+
+	first := c.current[0]
+	if first.entry.Stats.Count == 0 || first.readMax >= first.entry.MinTime {
+		return IndexEntry{}, false
+	}
+
+	for _, cur := range c.current[1:] {
+		if !cur.read() && cur.entry.OverlapsTimeRange(first.entry.MinTime, first.entry.MaxTime) {
+			return IndexEntry{}, false
+		}
+	}
+
+	c.trbuf = first.r.TombstoneRange(c.key, c.trbuf[:0])
+	for _, tr := range c.trbuf {
+		if tr.Overlaps(first.entry.MinTime, first.entry.MaxTime) {
+			return IndexEntry{}, false
+		}
+	}
+	return first.entry, true
+}
+
+// skipBlock marks the points of the block returned by peekBlockStats as read
+// and moves to the next block. It returns the number of skipped points.
+func (c *KeyCursor) skipBlock() uint32 {
+	first := c.current[0]
+	first.markRead(first.entry.MinTime, first.entry.MaxTime)
+	c.Next()
+	return first.entry.Stats.Count
+}
+
 func (c *KeyCursor) nextAscending() {
 	for {
 		c.pos++
@@ -0,0 +1,254 @@
+package tsm1
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/influxdb/pkg/fs"
+	"go.uber.org/zap"
+)
+
+// ColdCacheDirectoryName is the name of the directory of the file store
+// holding the copies of the cold files fetched to be read.
+const ColdCacheDirectoryName = "_cold"
+
+// coldIDFileName is the name of the file of the file store holding the ID
+// prefixing the names of its objects in the cold store.
+const coldIDFileName = "_cold_id"
+
+// errColdMoveSkipped is returned when a file can't be moved to the cold tier
+// because it was removed or is in use.
+var errColdMoveSkipped = errors.New("file skipped")
+
+func (f *FileStore) coldCacheDir() string {
+	return filepath.Join(f.dir, ColdCacheDirectoryName)
+}
+
+// loadColdID returns the ID prefixing the names of the objects of the file
+// store in the cold store, generating it the first time. The ID keeps the
+// engines sharing a cold store from overwriting and removing each other's
+// objects, which are named after their files.
+func (f *FileStore) loadColdID() (string, error) {
+	path := filepath.Join(f.dir, coldIDFileName)
+	b, err := ioutil.ReadFile(path)
+	if err == nil {
+		if id := strings.TrimSpace(string(b)); id != "" {
+			return id, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(buf[:])
+
+	tmpPath := path + "." + TmpTSMFileExtension
+	fd, err := os.Create(tmpPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fd.WriteString(id); err != nil {
+		fd.Close()
+		return "", err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return "", err
+	}
+	if err := fd.Close(); err != nil {
+		return "", err
+	}
+	if err := fs.RenameFileWithReplacement(tmpPath, path); err != nil {
+		return "", err
+	}
+	return id, fs.SyncDir(f.dir)
+}
+
+// readerOptions returns the options of the readers of the files of the store.
+func (f *FileStore) readerOptions() []tsmReaderOption {
+	return []tsmReaderOption{
+		WithMadviseWillNeed(f.tsmMMAPWillNeed),
+		WithTSMReaderLogger(f.logger),
+		WithColdStore(f.coldStore, f.coldCacheDir()),
+	}
+}
+
+// MoveToColdTier moves the fully compacted files for which fn returns true to
+// the cold tier, and returns the number of files moved. A cold file is kept on
+// disk without its blocks, which are fetched from the cold store when read.
+// Files in use are skipped, to be moved by a later call.
+func (f *FileStore) MoveToColdTier(ctx context.Context, fn func(FileStat) bool) (int, error) {
+	if f.coldStore == nil {
+		return 0, nil
+	}
+
+	var paths []string
+	for _, stat := range f.Stats() {
+		if stat.Cold {
+			continue
+		}
+		if _, seq, err := f.parseFileName(stat.Path); err != nil || seq < 4 {
+			continue
+		}
+		if fn(stat) {
+			paths = append(paths, stat.Path)
+		}
+	}
+
+	var moved int
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return moved, err
+		}
+
+		err := f.moveToColdTier(ctx, path)
+		if err == errColdMoveSkipped {
+			continue
+		} else if err != nil {
+			return moved, fmt.Errorf("moving %s to the cold tier: %v", path, err)
+		}
+		f.logger.Info("Moved file to cold tier", zap.String("path", path))
+		moved++
+	}
+	return moved, nil
+}
+
+func (f *FileStore) moveToColdTier(ctx context.Context, path string) (err error) {
+	fd, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return errColdMoveSkipped
+	} else if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	stat, err := fd.Stat()
+	if err != nil {
+		return err
+	}
+
+	marker := coldMarker{Object: f.coldID + "-" + filepath.Base(path), Size: stat.Size()}
+	if err := f.coldStore.Put(ctx, marker.Object, io.NewSectionReader(fd, 0, marker.Size), marker.Size); err != nil {
+		return err
+	}
+
+	// Until the file is swapped with its stub, a failure leaves it on disk.
+	var swapped bool
+	stubPath := fmt.Sprintf("%s.stub.%s", path, TmpTSMFileExtension)
+	defer func() {
+		if err != nil && !swapped {
+			os.Remove(stubPath)
+			os.Remove(coldMarkerPath(path))
+			if derr := f.coldStore.Delete(context.Background(), marker.Object); derr != nil {
+				f.logger.Info("Unable to delete cold tier object", zap.String("object", marker.Object), zap.Error(derr))
+			}
+		}
+	}()
+
+	if err := writeColdStub(fd, stubPath, marker.Size); err != nil {
+		return err
+	}
+	if err := writeColdMarker(path, marker); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// The file may have been compacted or deleted while it was copied, and it
+	// can only be swapped with its stub when not in use, as in replace.
+	i := -1
+	for j, file := range f.files {
+		if file.Path() == path {
+			i = j
+			break
+		}
+	}
+	if i < 0 || f.files[i].InUse() {
+		return errColdMoveSkipped
+	}
+
+	if err := fs.RenameFileWithReplacement(stubPath, path); err != nil {
+		return err
+	}
+	swapped = true
+
+	// The new reader shares the tombstones of the replaced one, so the replaced
+	// one is only closed, not removed. If the new reader can't be opened, the
+	// replaced one keeps serving the blocks it has mapped until a restart.
+	stub, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r, err := NewTSMReader(stub, f.readerOptions()...)
+	if err != nil {
+		stub.Close()
+		return err
+	}
+	r.WithObserver(f.obs)
+
+	if err := f.files[i].Close(); err != nil {
+		r.Close()
+		return err
+	}
+	f.files[i] = r
+
+	f.lastFileStats = nil
+	f.lastModified = f.lastModified.Add(1)
+	return fs.SyncDir(f.dir)
+}
+
+// FreeColdFiles removes the copies of the cold files that were fetched but
+// not read since the previous call.
+func (f *FileStore) FreeColdFiles() error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, file := range f.files {
+		if r, ok := file.(*TSMReader); ok && r.IsCold() {
+			if err := r.Free(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyColdFile copies the cold file of r to path.
+func (f *FileStore) copyColdFile(ctx context.Context, r *TSMReader, path string) error {
+	c := r.accessor.(*coldAccessor)
+	if c.store == nil {
+		return ErrColdTierDisabled
+	}
+
+	src, err := c.store.Get(ctx, c.marker.Object)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,194 @@
+package tsm1_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/fs"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// newFullyCompactedFile writes a TSM file of the given generation at the level
+// of the files written by full compactions.
+func newFullyCompactedFile(dir string, generation int, kv keyValues) (string, error) {
+	files, err := newFiles(dir, kv)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, tsm1.DefaultFormatFileName(generation, 4)+".tsm")
+	return path, fs.RenameFile(files[0], path)
+}
+
+func TestFileStore_MoveToColdTier(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	coldDir := filepath.Join(dir, "cold")
+	engineDir := filepath.Join(dir, "engine")
+	if err := os.Mkdir(engineDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	values := []tsm1.Value{tsm1.NewValue(0, 1.0), tsm1.NewValue(1, 2.0), tsm1.NewValue(2, 3.0)}
+	cold, err := newFullyCompactedFile(engineDir, 1, keyValues{"cpu", values})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hot, err := newFiles(engineDir, keyValues{"mem", []tsm1.Value{tsm1.NewValue(0, 1.0)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := tsm1.NewDirColdStore(coldDir)
+	open := func(store tsm1.ColdStore) *tsm1.FileStore {
+		fs := tsm1.NewFileStore(engineDir)
+		fs.WithColdStore(store)
+		if err := fs.Open(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return fs
+	}
+	readCPU := func(fs *tsm1.FileStore) ([]tsm1.Value, error) {
+		return fs.Read([]byte("cpu"), 1)
+	}
+
+	fs := open(store)
+	if err := fs.DeleteRange([][]byte{[]byte("cpu")}, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := fs.MoveToColdTier(context.Background(), func(tsm1.FileStat) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("moved %d files to the cold tier, expected 1", n)
+	}
+	for _, stat := range fs.Stats() {
+		if stat.Cold != (stat.Path == cold) {
+			t.Fatalf("file %s has cold %v", stat.Path, stat.Cold)
+		}
+	}
+	objects, err := filepath.Glob(filepath.Join(coldDir, "*-"+filepath.Base(cold)))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(objects) != 1 {
+		t.Fatalf("file not moved to the cold store, got objects %v", objects)
+	}
+
+	// The blocks are fetched when read, and the tombstones are kept.
+	check := func(fs *tsm1.FileStore) {
+		t.Helper()
+		got, err := readCPU(fs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(values) || got[2].Value() != 3.0 {
+			t.Fatalf("read unexpected values %v from cold file", got)
+		}
+
+		r := fs.TSMReader(cold)
+		defer r.Unref()
+		if ts := r.TombstoneRange([]byte("cpu"), nil); len(ts) != 1 || ts[0].Min != 0 || ts[0].Max != 0 {
+			t.Fatalf("got tombstones %v for cold file", ts)
+		}
+	}
+	check(fs)
+
+	// Fetched copies are removed once not read for a whole period.
+	cacheDir := filepath.Join(engineDir, tsm1.ColdCacheDirectoryName)
+	for i := 0; i < 2; i++ {
+		if err := fs.FreeColdFiles(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fis, err := ioutil.ReadDir(cacheDir); err != nil || len(fis) != 0 {
+		t.Fatalf("got %d fetched copies, error %v, expected none", len(fis), err)
+	}
+	check(fs)
+
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Files stay on the cold tier when reopened.
+	fs = open(store)
+	check(fs)
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fs = open(nil)
+	if _, err := readCPU(fs); err != tsm1.ErrColdTierDisabled {
+		t.Fatalf("got error %v reading cold file without a cold tier, expected %v", err, tsm1.ErrColdTierDisabled)
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing a cold file removes it from the cold tier.
+	fs = open(store)
+	defer fs.Close()
+	if err := fs.Replace([]string{cold}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(objects[0]); !os.IsNotExist(err) {
+		t.Fatalf("got error %v checking removed cold file, expected not exists", err)
+	}
+	if _, err := os.Stat(cold + "." + tsm1.ColdTSMFileExtension); !os.IsNotExist(err) {
+		t.Fatalf("got error %v checking marker of removed cold file, expected not exists", err)
+	}
+	if stats := fs.Stats(); len(stats) != 1 || stats[0].Path != hot[0] {
+		t.Fatalf("unexpected files %v after removing cold file", stats)
+	}
+}
+
+func TestFileStore_MoveToColdTier_SharedStore(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	store := tsm1.NewDirColdStore(filepath.Join(dir, "cold"))
+
+	// The engines have files of the same names, which must not overwrite
+	// each other on the cold tier.
+	var stores []*tsm1.FileStore
+	var paths []string
+	for i := 0; i < 2; i++ {
+		engineDir := filepath.Join(dir, fmt.Sprintf("engine%d", i))
+		if err := os.Mkdir(engineDir, 0777); err != nil {
+			t.Fatal(err)
+		}
+		path, err := newFullyCompactedFile(engineDir, 1, keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, float64(i))}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fs := tsm1.NewFileStore(engineDir)
+		fs.WithColdStore(store)
+		if err := fs.Open(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		defer fs.Close()
+		if n, err := fs.MoveToColdTier(context.Background(), func(tsm1.FileStat) bool { return true }); err != nil {
+			t.Fatal(err)
+		} else if n != 1 {
+			t.Fatalf("moved %d files to the cold tier, expected 1", n)
+		}
+		stores = append(stores, fs)
+		paths = append(paths, path)
+	}
+
+	// Removing the cold file of an engine keeps the one of the other engine.
+	if err := stores[0].Replace([]string{paths[0]}, nil); err != nil {
+		t.Fatal(err)
+	}
+	values, err := stores[1].Read([]byte("cpu"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 1 || values[0].Value() != 1.0 {
+		t.Fatalf("read unexpected values %v from the cold file of the second engine", values)
+	}
+}
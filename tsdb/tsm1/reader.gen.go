@@ -283,3 +283,93 @@ func (m *mmapAccessor) readBooleanArrayBlock(entry *IndexEntry, values *tsdb.Boo
 
 	return err
 }
+
+func (c *coldAccessor) readFloatBlock(entry *IndexEntry, values *[]FloatValue) ([]FloatValue, error) {
+	m, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return m.readFloatBlock(entry, values)
+}
+
+func (c *coldAccessor) readFloatArrayBlock(entry *IndexEntry, values *tsdb.FloatArray) error {
+	m, err := c.acquire()
+	if err != nil {
+		return err
+	}
+	defer c.release()
+	return m.readFloatArrayBlock(entry, values)
+}
+
+func (c *coldAccessor) readIntegerBlock(entry *IndexEntry, values *[]IntegerValue) ([]IntegerValue, error) {
+	m, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return m.readIntegerBlock(entry, values)
+}
+
+func (c *coldAccessor) readIntegerArrayBlock(entry *IndexEntry, values *tsdb.IntegerArray) error {
+	m, err := c.acquire()
+	if err != nil {
+		return err
+	}
+	defer c.release()
+	return m.readIntegerArrayBlock(entry, values)
+}
+
+func (c *coldAccessor) readUnsignedBlock(entry *IndexEntry, values *[]UnsignedValue) ([]UnsignedValue, error) {
+	m, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return m.readUnsignedBlock(entry, values)
+}
+
+func (c *coldAccessor) readUnsignedArrayBlock(entry *IndexEntry, values *tsdb.UnsignedArray) error {
+	m, err := c.acquire()
+	if err != nil {
+		return err
+	}
+	defer c.release()
+	return m.readUnsignedArrayBlock(entry, values)
+}
+
+func (c *coldAccessor) readStringBlock(entry *IndexEntry, values *[]StringValue) ([]StringValue, error) {
+	m, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return m.readStringBlock(entry, values)
+}
+
+func (c *coldAccessor) readStringArrayBlock(entry *IndexEntry, values *tsdb.StringArray) error {
+	m, err := c.acquire()
+	if err != nil {
+		return err
+	}
+	defer c.release()
+	return m.readStringArrayBlock(entry, values)
+}
+
+func (c *coldAccessor) readBooleanBlock(entry *IndexEntry, values *[]BooleanValue) ([]BooleanValue, error) {
+	m, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return m.readBooleanBlock(entry, values)
+}
+
+func (c *coldAccessor) readBooleanArrayBlock(entry *IndexEntry, values *tsdb.BooleanArray) error {
+	m, err := c.acquire()
+	if err != nil {
+		return err
+	}
+	defer c.release()
+	return m.readBooleanArrayBlock(entry, values)
+}
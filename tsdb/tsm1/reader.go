@@ -40,6 +40,11 @@ type TSMReader struct {
 
 	// deleteMu limits concurrent deletes
 	deleteMu sync.Mutex
+
+	// coldStore and coldCacheDir are used to read the blocks of the file if
+	// it was moved to the cold tier.
+	coldStore    ColdStore
+	coldCacheDir string
 }
 
 type tsmReaderOption func(*TSMReader)
@@ -72,11 +77,24 @@ func NewTSMReader(f *os.File, options ...tsmReaderOption) (*TSMReader, error) {
 	}
 	t.size = stat.Size()
 	t.lastModified = stat.ModTime().UnixNano()
-	t.accessor = &mmapAccessor{
+	m := &mmapAccessor{
 		logger:       t.logger,
 		f:            f,
 		mmapWillNeed: t.madviseWillNeed,
 	}
+	t.accessor = m
+
+	marker, err := readColdMarker(f.Name())
+	if err != nil {
+		return nil, err
+	} else if marker != nil {
+		t.accessor = &coldAccessor{
+			mmapAccessor: m,
+			store:        t.coldStore,
+			marker:       *marker,
+			cacheDir:     t.coldCacheDir,
+		}
+	}
 
 	index, err := t.accessor.init()
 	if err != nil {
@@ -281,6 +299,12 @@ func (t *TSMReader) remove() error {
 		} else if err := os.RemoveAll(StatsFilename(path)); err != nil && !os.IsNotExist(err) {
 			return err
 		}
+
+		if c, ok := t.accessor.(*coldAccessor); ok {
+			if err := c.remove(path); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := t.tombstoner.Delete(); err != nil {
@@ -463,9 +487,16 @@ func (t *TSMReader) Stats() FileStat {
 		MinKey:       minKey,
 		MaxKey:       maxKey,
 		HasTombstone: t.tombstoner.HasTombstones(),
+		Cold:         t.IsCold(),
 	}
 }
 
+// IsCold returns true if the file was moved to the cold tier.
+func (t *TSMReader) IsCold() bool {
+	_, ok := t.accessor.(*coldAccessor)
+	return ok
+}
+
 // BlockIterator returns a BlockIterator for the underlying TSM file.
 func (t *TSMReader) BlockIterator() *BlockIterator {
 	t.mu.RLock()
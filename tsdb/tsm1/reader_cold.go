@@ -0,0 +1,345 @@
+package tsm1
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/influxdata/influxdb/pkg/fs"
+)
+
+// ColdTSMFileExtension is the extension of the files marking the TSM files
+// moved to the cold tier.
+const ColdTSMFileExtension = "cold"
+
+// tsmHeaderSize is the size of the header of a TSM file, which holds its magic
+// number and version.
+const tsmHeaderSize = 5
+
+// ErrColdTierDisabled is returned when reading the blocks of a TSM file moved
+// to the cold tier of an engine that has no cold tier configured.
+var ErrColdTierDisabled = errors.New("tsm file is on the cold tier, but no cold tier is configured")
+
+// coldMarker records that a TSM file was moved to the cold tier. It is stored
+// next to the local copy of the file, which only keeps the header and the index.
+type coldMarker struct {
+	Object string `json:"object"`
+	Size   int64  `json:"size"`
+}
+
+// coldMarkerPath returns the path of the marker of the TSM file at path.
+func coldMarkerPath(path string) string {
+	return path + "." + ColdTSMFileExtension
+}
+
+// readColdMarker returns the marker of the TSM file at path, or nil if the file
+// is not on the cold tier.
+func readColdMarker(path string) (*coldMarker, error) {
+	b, err := ioutil.ReadFile(coldMarkerPath(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var m coldMarker
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("invalid cold tier marker of %s: %v", path, err)
+	}
+	return &m, nil
+}
+
+// writeColdMarker durably writes the marker of the TSM file at path.
+func writeColdMarker(path string, m coldMarker) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := coldMarkerPath(path) + "." + TmpTSMFileExtension
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return fs.RenameFileWithReplacement(tmpPath, coldMarkerPath(path))
+}
+
+// writeColdStub writes to path a sparse copy of the TSM file src of the given
+// size. The copy keeps the header, the index and the footer of src at their
+// offsets, so that it can be opened as a TSM file, but none of its blocks.
+func writeColdStub(src *os.File, path string, size int64) error {
+	if size < tsmHeaderSize+8 {
+		return fmt.Errorf("tsm file %s is too small to be moved to the cold tier", src.Name())
+	}
+
+	var footer [8]byte
+	if _, err := src.ReadAt(footer[:], size-8); err != nil {
+		return err
+	}
+	indexStart := int64(binary.BigEndian.Uint64(footer[:]))
+	if indexStart < tsmHeaderSize || indexStart >= size-8 {
+		return fmt.Errorf("tsm file %s has an invalid index offset", src.Name())
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := func() error {
+		if _, err := io.Copy(f, io.NewSectionReader(src, 0, tsmHeaderSize)); err != nil {
+			return err
+		}
+		if _, err := f.Seek(indexStart, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, io.NewSectionReader(src, indexStart, size-indexStart)); err != nil {
+			return err
+		}
+		if err := f.Truncate(size); err != nil {
+			return err
+		}
+		return f.Sync()
+	}(); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// WithColdStore is an option for specifying the store of the TSM files moved to
+// the cold tier, and the directory their blocks are fetched to when read.
+var WithColdStore = func(store ColdStore, cacheDir string) tsmReaderOption {
+	return func(r *TSMReader) {
+		r.coldStore = store
+		r.coldCacheDir = cacheDir
+	}
+}
+
+// coldAccessor is the blockAccessor of a TSM file moved to the cold tier. The
+// index is read from the local stub of the file, and the blocks from a copy of
+// the file fetched from the cold store when they are first read.
+type coldAccessor struct {
+	*mmapAccessor
+
+	store    ColdStore
+	marker   coldMarker
+	cacheDir string
+
+	dataMu   sync.RWMutex
+	data     *mmapAccessor // The fetched copy of the file, or nil.
+	lastFree uint64        // Access count of data when it was last freed.
+}
+
+// acquire returns the accessor of the fetched copy of the file, fetching it if
+// needed. Unless an error is returned, the caller must call release once done
+// with the accessor.
+func (c *coldAccessor) acquire() (*mmapAccessor, error) {
+	for {
+		c.dataMu.RLock()
+		if c.data != nil {
+			return c.data, nil
+		}
+		c.dataMu.RUnlock()
+
+		c.dataMu.Lock()
+		err := c.fetch()
+		c.dataMu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *coldAccessor) release() {
+	c.dataMu.RUnlock()
+}
+
+// fetch copies the file from the cold store to the cache directory and maps it.
+// It must be called with the write lock held.
+func (c *coldAccessor) fetch() error {
+	if c.data != nil {
+		return nil
+	}
+	if c.store == nil {
+		return ErrColdTierDisabled
+	}
+
+	if err := os.MkdirAll(c.cacheDir, 0777); err != nil {
+		return err
+	}
+	path := filepath.Join(c.cacheDir, filepath.Base(c.mmapAccessor.path()))
+
+	r, err := c.store.Get(context.Background(), c.marker.Object)
+	if err != nil {
+		return fmt.Errorf("fetching %s from the cold tier: %v", c.marker.Object, err)
+	}
+	defer r.Close()
+
+	tmpPath := path + "." + TmpTSMFileExtension
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n != c.marker.Size {
+		err = fmt.Errorf("fetched %d bytes of %s from the cold tier, expected %d", n, c.marker.Object, c.marker.Size)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := fs.RenameFileWithReplacement(tmpPath, path); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	b, err := mmap(f, 0, int(n))
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+
+	c.data = &mmapAccessor{
+		logger: c.logger,
+		b:      b,
+		f:      f,
+		_path:  path,
+		index:  c.index,
+	}
+	c.lastFree = 0
+	return nil
+}
+
+// evict unmaps and removes the fetched copy of the file. It must be called
+// with the write lock held.
+func (c *coldAccessor) evict() error {
+	if c.data == nil {
+		return nil
+	}
+
+	path := c.data.path()
+	err := c.data.close()
+	c.data = nil
+	if rerr := os.Remove(path); err == nil && rerr != nil && !os.IsNotExist(rerr) {
+		err = rerr
+	}
+	return err
+}
+
+func (c *coldAccessor) read(key []byte, timestamp int64) ([]Value, error) {
+	entry := c.index.Entry(key, timestamp)
+	if entry == nil {
+		return nil, nil
+	}
+
+	return c.readBlock(entry, nil)
+}
+
+func (c *coldAccessor) readAll(key []byte) ([]Value, error) {
+	m, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return m.readAll(key)
+}
+
+func (c *coldAccessor) readBlock(entry *IndexEntry, values []Value) ([]Value, error) {
+	m, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return m.readBlock(entry, values)
+}
+
+// readBytes returns the bytes of a block of the fetched copy of the file. They
+// remain valid until the accessor is freed or closed.
+func (c *coldAccessor) readBytes(entry *IndexEntry, b []byte) (uint32, []byte, error) {
+	m, err := c.acquire()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer c.release()
+	return m.readBytes(entry, b)
+}
+
+// rename renames the stub of the file along with its marker.
+func (c *coldAccessor) rename(path string) error {
+	from := c.mmapAccessor.path()
+	if err := c.mmapAccessor.rename(path); err != nil {
+		return err
+	}
+	return fs.RenameFileWithReplacement(coldMarkerPath(from), coldMarkerPath(path))
+}
+
+// free releases the pages of the stub, and removes the fetched copy of the
+// file if it was not read since the last time the accessor was freed.
+func (c *coldAccessor) free() error {
+	if err := c.mmapAccessor.free(); err != nil {
+		return err
+	}
+
+	c.dataMu.Lock()
+	defer c.dataMu.Unlock()
+	if c.data == nil {
+		return nil
+	}
+
+	if n := atomic.LoadUint64(&c.data.accessCount); n != c.lastFree {
+		c.lastFree = n
+		return nil
+	}
+	return c.evict()
+}
+
+func (c *coldAccessor) close() error {
+	c.dataMu.Lock()
+	err := c.evict()
+	c.dataMu.Unlock()
+
+	if cerr := c.mmapAccessor.close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// remove removes the marker of the file and its object from the cold store.
+func (c *coldAccessor) remove(path string) error {
+	if c.store != nil {
+		if err := c.store.Delete(context.Background(), c.marker.Object); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(coldMarkerPath(path)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/influxdata/influxdb/tsdb/cursors"
 	"go.uber.org/zap"
 )
 
@@ -698,7 +699,7 @@ func (d *indirectIndex) Type(key []byte) (byte, error) {
 		return 0, errors.New("key does not exist")
 	}
 
-	return d.b.access(iter.EntryOffset(&d.b), 1)[0], nil
+	return d.b.access(iter.EntryOffset(&d.b), 1)[0] &^ blockStatsFlag, nil
 }
 
 // OverlapsTimeRange returns true if the time range of the file intersect min and max.
@@ -790,6 +791,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			return fmt.Errorf("indirectIndex: not enough data for key and type")
 		}
 		ro.AddKey(offset, b[i:i+keyLength])
+		entrySize := uint32(indexEntrySizeOf(b[i+keyLength]))
 		i += keyLength + indexTypeSize
 
 		// count of index entries
@@ -811,7 +813,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			minTime = minT
 		}
 
-		i += (count - 1) * indexEntrySize
+		i += (count - 1) * entrySize
 
 		// Find the max time for the block
 		if i+16 >= iMax {
@@ -822,7 +824,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			maxTime = maxT
 		}
 
-		i += indexEntrySize
+		i += entrySize
 	}
 
 	ro.Done()
@@ -870,13 +872,21 @@ func readEntries(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
 	} else {
 		entries = entries[:count]
 	}
+	size := indexEntrySizeOf(b[0])
 	b = b[indexTypeSize+indexCountSize:]
 
 	for i := range entries {
 		if err := entries[i].UnmarshalBinary(b); err != nil {
 			return entries[:0], err
 		}
-		b = b[indexEntrySize:]
+		if size > indexEntrySize {
+			if err := entries[i].unmarshalStats(b[indexEntrySize:]); err != nil {
+				return entries[:0], err
+			}
+		} else {
+			entries[i].Stats = cursors.BlockStats{}
+		}
+		b = b[size:]
 	}
 
 	return entries, nil
@@ -895,15 +905,16 @@ func readEntriesTimes(b []byte, entries []IndexEntry) ([]IndexEntry, error) {
 	} else {
 		entries = entries[:count]
 	}
+	size := indexEntrySizeOf(b[0])
 	b = b[indexTypeSize+indexCountSize:]
 
 	for i := range entries {
-		if len(b) < indexEntrySize {
+		if len(b) < size {
 			return entries[:0], errors.New("readEntries: stream too short for entry")
 		}
 		entries[i].MinTime = int64(binary.BigEndian.Uint64(b[0:8]))
 		entries[i].MaxTime = int64(binary.BigEndian.Uint64(b[8:16]))
-		b = b[indexEntrySize:]
+		b = b[size:]
 	}
 
 	return entries, nil
@@ -116,7 +116,7 @@ func (t *TSMIndexIterator) Key() []byte {
 	if t.key == nil {
 		buf := t.b.access(t.offset, 0)
 		t.key = readKey(buf)
-		t.typ = buf[2+len(t.key)]
+		t.typ = buf[2+len(t.key)] &^ blockStatsFlag
 	}
 	return t.key
 }
@@ -126,7 +126,7 @@ func (t *TSMIndexIterator) Type() byte {
 	if t.key == nil {
 		buf := t.b.access(t.offset, 0)
 		t.key = readKey(buf)
-		t.typ = buf[2+len(t.key)]
+		t.typ = buf[2+len(t.key)] &^ blockStatsFlag
 	}
 	return t.typ
 }
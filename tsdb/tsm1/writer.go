@@ -52,6 +52,19 @@ file, we can use the size to determine how much to read in a given IO.
 │ 2 bytes │ N bytes │1 byte│2 bytes│ 8 bytes │ 8 bytes │8 bytes │4 bytes │   │
 └─────────┴─────────┴──────┴───────┴─────────┴─────────┴────────┴────────┴───┘
 
+Files of version 2 may also summarize the values of the float, integer and
+unsigned blocks of a key in the index.  The highest bit of the type of the key
+is then set and each block entry is followed by the count of values of the
+block, the sum, min and max of the values in the encoding of the block type
+and the times of the first min and max values.
+
+┌───────────────────────────────────────────────────────────────┐
+│                          Block Stats                          │
+├─────────┬─────────┬─────────┬─────────┬───────────┬───────────┤
+│  Count  │   Sum   │   Min   │   Max   │Time of Min│Time of Max│
+│ 4 bytes │ 8 bytes │ 8 bytes │ 8 bytes │  8 bytes  │  8 bytes  │
+└─────────┴─────────┴─────────┴─────────┴───────────┴───────────┘
+
 The last section is the footer that stores the offset of the start of the index.
 
 ┌─────────┐
@@ -76,6 +89,7 @@ import (
 
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/fs"
+	"github.com/influxdata/influxdb/tsdb/cursors"
 )
 
 const (
@@ -86,9 +100,20 @@ const (
 	// Version indicates the version of the TSM file format.
 	Version byte = 1
 
+	// VersionBlockStats indicates the version of the TSM files whose index
+	// may hold the statistics of blocks.
+	VersionBlockStats byte = 2
+
 	// Size in bytes of an index entry
 	indexEntrySize = 28
 
+	// Size in bytes of the statistics following an index entry
+	indexEntryStatsSize = 44
+
+	// Flag set on the block type of a key whose index entries are followed
+	// by block statistics
+	blockStatsFlag = 0x80
+
 	// Size in bytes used to store the count of index entries for a key
 	indexCountSize = 2
 
@@ -161,6 +186,10 @@ type IndexWriter interface {
 	// Add records a new block entry for a key in the index.
 	Add(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32)
 
+	// AddWithStats records a new block entry summarized by stats for a key in
+	// the index. The blocks of a key must all have statistics or none.
+	AddWithStats(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32, stats cursors.BlockStats)
+
 	// Entries returns all index entries for a key.
 	Entries(key []byte) []IndexEntry
 
@@ -191,6 +220,19 @@ type IndexEntry struct {
 
 	// The size in bytes of the block in the file.
 	Size uint32
+
+	// Stats summarizes the values of the block. Count is 0 if the index
+	// does not hold the statistics of the block.
+	Stats cursors.BlockStats
+}
+
+// indexEntrySizeOf returns the size in bytes of the index entries of the
+// keys of type typ.
+func indexEntrySizeOf(typ byte) int {
+	if typ&blockStatsFlag != 0 {
+		return indexEntrySize + indexEntryStatsSize
+	}
+	return indexEntrySize
 }
 
 // UnmarshalBinary decodes an IndexEntry from a byte slice.
@@ -224,6 +266,32 @@ func (e *IndexEntry) AppendTo(b []byte) []byte {
 	return b
 }
 
+// unmarshalStats decodes the statistics of the block of the entry from b.
+func (e *IndexEntry) unmarshalStats(b []byte) error {
+	if len(b) < indexEntryStatsSize {
+		return fmt.Errorf("unmarshalStats: short buf: %v < %v", len(b), indexEntryStatsSize)
+	}
+	e.Stats.Count = binary.BigEndian.Uint32(b[:4])
+	e.Stats.Sum = binary.BigEndian.Uint64(b[4:12])
+	e.Stats.Min = binary.BigEndian.Uint64(b[12:20])
+	e.Stats.Max = binary.BigEndian.Uint64(b[20:28])
+	e.Stats.TimeOfMin = int64(binary.BigEndian.Uint64(b[28:36]))
+	e.Stats.TimeOfMax = int64(binary.BigEndian.Uint64(b[36:44]))
+	return nil
+}
+
+// appendStatsTo writes the binary-encoded statistics of the block of the
+// entry to b, which must be at least indexEntryStatsSize bytes long.
+func (e *IndexEntry) appendStatsTo(b []byte) []byte {
+	binary.BigEndian.PutUint32(b[:4], e.Stats.Count)
+	binary.BigEndian.PutUint64(b[4:12], e.Stats.Sum)
+	binary.BigEndian.PutUint64(b[12:20], e.Stats.Min)
+	binary.BigEndian.PutUint64(b[20:28], e.Stats.Max)
+	binary.BigEndian.PutUint64(b[28:36], uint64(e.Stats.TimeOfMin))
+	binary.BigEndian.PutUint64(b[36:44], uint64(e.Stats.TimeOfMax))
+	return b
+}
+
 // Contains returns true if this IndexEntry may contain values for the given time.
 // The min and max times are inclusive.
 func (e *IndexEntry) Contains(t int64) bool {
@@ -288,22 +356,30 @@ func (a *indexEntries) Less(i, j int) bool {
 }
 
 func (a *indexEntries) MarshalBinary() ([]byte, error) {
-	buf := make([]byte, len(a.entries)*indexEntrySize)
+	size := indexEntrySizeOf(a.Type)
+	buf := make([]byte, len(a.entries)*size)
 
 	for i, entry := range a.entries {
-		entry.AppendTo(buf[indexEntrySize*i:])
+		entry.AppendTo(buf[size*i:])
+		if size > indexEntrySize {
+			entry.appendStatsTo(buf[size*i+indexEntrySize:])
+		}
 	}
 
 	return buf, nil
 }
 
 func (a *indexEntries) WriteTo(w io.Writer) (total int64, err error) {
-	var buf [indexEntrySize]byte
+	var buf [indexEntrySize + indexEntryStatsSize]byte
 	var n int
 
+	size := indexEntrySizeOf(a.Type)
 	for _, entry := range a.entries {
 		entry.AppendTo(buf[:])
-		n, err = w.Write(buf[:])
+		if size > indexEntrySize {
+			entry.appendStatsTo(buf[indexEntrySize:])
+		}
+		n, err = w.Write(buf[:size])
 		total += int64(n)
 		if err != nil {
 			return total, err
@@ -314,6 +390,25 @@ func (a *indexEntries) WriteTo(w io.Writer) (total int64, err error) {
 }
 
 func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32) {
+	d.add(key, blockType, IndexEntry{
+		MinTime: minTime,
+		MaxTime: maxTime,
+		Offset:  offset,
+		Size:    size,
+	})
+}
+
+func (d *directIndex) AddWithStats(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32, stats cursors.BlockStats) {
+	d.add(key, blockType|blockStatsFlag, IndexEntry{
+		MinTime: minTime,
+		MaxTime: maxTime,
+		Offset:  offset,
+		Size:    size,
+		Stats:   stats,
+	})
+}
+
+func (d *directIndex) add(key []byte, blockType byte, entry IndexEntry) {
 	// Is this the first block being added?
 	if len(d.key) == 0 {
 		// size of the key stored in the index
@@ -326,15 +421,10 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 			d.indexEntries = &indexEntries{}
 		}
 		d.indexEntries.Type = blockType
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
+		d.indexEntries.entries = append(d.indexEntries.entries, entry)
 
 		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.size += uint32(indexEntrySizeOf(blockType))
 		d.keyCount++
 		return
 	}
@@ -343,15 +433,10 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 	cmp := bytes.Compare(d.key, key)
 	if cmp == 0 {
 		// The last block is still this key
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
+		d.indexEntries.entries = append(d.indexEntries.entries, entry)
 
 		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.size += uint32(indexEntrySizeOf(d.indexEntries.Type))
 
 	} else if cmp < 0 {
 		d.flush(d.w)
@@ -365,15 +450,10 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 
 		d.key = key
 		d.indexEntries.Type = blockType
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
+		d.indexEntries.entries = append(d.indexEntries.entries, entry)
 
 		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.size += uint32(indexEntrySizeOf(blockType))
 		d.keyCount++
 	} else {
 		// Keys can't be added out of order.
@@ -593,22 +673,40 @@ type tsmWriter struct {
 	lastSync int64
 
 	stats MeasurementStats
+
+	// blockStats is true if the writer summarizes the values of the float,
+	// integer and unsigned blocks in the index.
+	blockStats bool
+}
+
+type tsmWriterOption func(*tsmWriter)
+
+// WithBlockStats is an option for specifying whether to store the statistics
+// of the float, integer and unsigned blocks in the index of the file.
+var WithBlockStats = func(enabled bool) tsmWriterOption {
+	return func(t *tsmWriter) {
+		t.blockStats = enabled
+	}
 }
 
 // NewTSMWriter returns a new TSMWriter writing to w.
-func NewTSMWriter(w io.Writer) (TSMWriter, error) {
+func NewTSMWriter(w io.Writer, options ...tsmWriterOption) (TSMWriter, error) {
 	index := NewIndexWriter()
-	return &tsmWriter{
+	t := &tsmWriter{
 		wrapped: w,
 		w:       bufio.NewWriterSize(w, 1024*1024),
 		index:   index,
 		stats:   NewMeasurementStats(),
-	}, nil
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t, nil
 }
 
 // NewTSMWriterWithDiskBuffer returns a new TSMWriter writing to w and will use a disk
 // based buffer for the TSM index if possible.
-func NewTSMWriterWithDiskBuffer(w io.Writer) (TSMWriter, error) {
+func NewTSMWriterWithDiskBuffer(w io.Writer, options ...tsmWriterOption) (TSMWriter, error) {
 	var index IndexWriter
 	// Make sure is a File so we can write the temp index alongside it.
 	if fw, ok := w.(syncer); ok {
@@ -622,12 +720,16 @@ func NewTSMWriterWithDiskBuffer(w io.Writer) (TSMWriter, error) {
 		index = NewIndexWriter()
 	}
 
-	return &tsmWriter{
+	t := &tsmWriter{
 		wrapped: w,
 		w:       bufio.NewWriterSize(w, 1024*1024),
 		index:   index,
 		stats:   NewMeasurementStats(),
-	}, nil
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t, nil
 }
 
 // MeasurementStats returns the measurement statistics generated by the writer.
@@ -637,6 +739,9 @@ func (t *tsmWriter) writeHeader() error {
 	var buf [5]byte
 	binary.BigEndian.PutUint32(buf[0:4], MagicNumber)
 	buf[4] = Version
+	if t.blockStats {
+		buf[4] = VersionBlockStats
+	}
 
 	n, err := t.w.Write(buf[:])
 	if err != nil {
@@ -689,7 +794,11 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 	n += len(checksum)
 
 	// Record this block in index
-	t.index.Add(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n))
+	if t.blockStats && hasBlockStats(blockType) {
+		t.index.AddWithStats(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n), valuesBlockStats(values))
+	} else {
+		t.index.Add(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n))
+	}
 
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
@@ -723,6 +832,13 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 		return err
 	}
 
+	var stats cursors.BlockStats
+	if t.blockStats && hasBlockStats(blockType) {
+		if stats, err = blockStats(block); err != nil {
+			return err
+		}
+	}
+
 	// Write header only after we have some data to write.
 	if t.n == 0 {
 		if err := t.writeHeader(); err != nil {
@@ -745,7 +861,11 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 	n += len(checksum)
 
 	// Record this block in index
-	t.index.Add(key, blockType, minTime, maxTime, t.n, uint32(n))
+	if t.blockStats && hasBlockStats(blockType) {
+		t.index.AddWithStats(key, blockType, minTime, maxTime, t.n, uint32(n), stats)
+	} else {
+		t.index.Add(key, blockType, minTime, maxTime, t.n, uint32(n))
+	}
 
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
@@ -893,7 +1013,7 @@ func (t *tsmWriter) Size() uint32 {
 }
 
 // verifyVersion verifies that the reader's bytes are a TSM byte
-// stream of the correct version (1)
+// stream of a supported version (1 or 2)
 func verifyVersion(r io.ReadSeeker) error {
 	_, err := r.Seek(0, 0)
 	if err != nil {
@@ -911,8 +1031,8 @@ func verifyVersion(r io.ReadSeeker) error {
 	if err != nil {
 		return fmt.Errorf("init: error reading version: %v", err)
 	}
-	if b[0] != Version {
-		return fmt.Errorf("init: file is version %b. expected %b", b[0], Version)
+	if b[0] != Version && b[0] != VersionBlockStats {
+		return fmt.Errorf("init: file is version %b. expected %b or %b", b[0], Version, VersionBlockStats)
 	}
 
 	return nil
@@ -6,10 +6,12 @@ import (
 	"encoding/binary"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/tsdb/cursors"
 	"github.com/influxdata/influxdb/tsdb/tsm1"
 )
 
@@ -615,6 +617,110 @@ func (f *fakeSyncer) Sync() error {
 	return nil
 }
 
+func TestTSMWriter_BlockStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	f := MustTempFile(dir)
+
+	w, err := tsm1.NewTSMWriter(f, tsm1.WithBlockStats(true))
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+
+	// Blocks of cpu are written from values, the block of mem is copied.
+	cpu := [][]tsm1.Value{
+		{tsm1.NewValue(0, int64(4)), tsm1.NewValue(1, int64(2)), tsm1.NewValue(2, int64(6)), tsm1.NewValue(3, int64(2))},
+		{tsm1.NewValue(10, int64(-1))},
+	}
+	for _, values := range cpu {
+		if err := w.Write([]byte("cpu"), values); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	block, err := tsm1.Values{tsm1.NewValue(5, 1.5), tsm1.NewValue(6, 0.5)}.Encode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+	if err := w.WriteBlock([]byte("mem"), 5, 6, block); err != nil {
+		t.Fatalf("unexpected error writing block: %v", err)
+	}
+	if err := w.Write([]byte("str"), []tsm1.Value{tsm1.NewValue(0, "a")}); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if got, exp := b[4], tsm1.VersionBlockStats; got != exp {
+		t.Fatalf("version mismatch: got %v, exp %v", got, exp)
+	}
+
+	fd, err := os.Open(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error open file: %v", err)
+	}
+	r, err := tsm1.NewTSMReader(fd)
+	if err != nil {
+		t.Fatalf("unexpected error created reader: %v", err)
+	}
+	defer r.Close()
+
+	if typ, err := r.Type([]byte("cpu")); err != nil || typ != tsm1.BlockInteger {
+		t.Fatalf("unexpected type: got %v, %v", typ, err)
+	}
+
+	for _, tt := range []struct {
+		key string
+		exp []cursors.BlockStats
+	}{
+		{
+			key: "cpu",
+			exp: []cursors.BlockStats{
+				{Count: 4, Sum: 14, Min: 2, Max: 6, TimeOfMin: 1, TimeOfMax: 2},
+				{Count: 1, Sum: uint64(1<<64 - 1), Min: uint64(1<<64 - 1), Max: uint64(1<<64 - 1), TimeOfMin: 10, TimeOfMax: 10},
+			},
+		},
+		{
+			key: "mem",
+			exp: []cursors.BlockStats{
+				{Count: 2, Sum: math.Float64bits(2), Min: math.Float64bits(0.5), Max: math.Float64bits(1.5), TimeOfMin: 6, TimeOfMax: 5},
+			},
+		},
+		{
+			key: "str",
+			exp: []cursors.BlockStats{{}},
+		},
+	} {
+		entries, err := r.ReadEntries([]byte(tt.key), nil)
+		if err != nil {
+			t.Fatalf("unexpected error reading entries: %v", err)
+		}
+		var got []cursors.BlockStats
+		for _, e := range entries {
+			got = append(got, e.Stats)
+		}
+		if !cmp.Equal(got, tt.exp) {
+			t.Errorf("unexpected stats of %s; -got/+exp\n%s", tt.key, cmp.Diff(got, tt.exp))
+		}
+	}
+
+	values, err := r.ReadAll([]byte("cpu"))
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if got, exp := len(values), 5; got != exp {
+		t.Fatalf("read values length mismatch: got %v, exp %v", got, exp)
+	}
+}
+
 func TestTSMWriter_Sync(t *testing.T) {
 	f := &struct {
 		io.Writer